@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier"
+	"github.com/rs/zerolog"
+)
+
+// isConfigCommand reports whether the process was started as `monsterinc config ...`
+func isConfigCommand(args []string) bool {
+	return len(args) > 1 && args[1] == "config"
+}
+
+// runConfigCommand handles `monsterinc config <subcommand>` and returns the process exit code
+func runConfigCommand(args []string) int {
+	if len(args) < 1 {
+		printConfigUsage()
+		return 1
+	}
+
+	switch args[0] {
+	case "upgrade":
+		return runConfigUpgrade(args[1:])
+	case "schema":
+		return runConfigSchema(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "[FATAL] Unknown config subcommand '%s'\n", args[0])
+		printConfigUsage()
+		return 1
+	}
+}
+
+// runConfigUpgrade fills missing fields of a config file with documented defaults
+func runConfigUpgrade(args []string) int {
+	if len(args) != 1 {
+		printConfigUsage()
+		return 1
+	}
+
+	basicLogger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	result, err := config.NewConfigUpgrader(basicLogger).UpgradeFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Config upgrade failed for '%s': %v\n", args[0], err)
+		return 1
+	}
+
+	if len(result.AddedFields) == 0 {
+		fmt.Printf("[INFO] Config '%s' is up to date.\n", result.FilePath)
+		return 0
+	}
+
+	fmt.Printf("[INFO] Config '%s' upgraded with %d default field(s):\n", result.FilePath, len(result.AddedFields))
+	for _, field := range result.AddedFields {
+		fmt.Printf("  + %s\n", field)
+	}
+	return 0
+}
+
+// runConfigSchema prints the JSON Schema of the config file format, or writes it to the given file
+func runConfigSchema(args []string) int {
+	if len(args) > 1 {
+		printConfigUsage()
+		return 1
+	}
+
+	schema, err := json.MarshalIndent(config.GenerateJSONSchema(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Config schema could not be generated: %v\n", err)
+		return 1
+	}
+	schema = append(schema, '\n')
+
+	if len(args) == 0 {
+		_, _ = os.Stdout.Write(schema)
+		return 0
+	}
+
+	if err := os.WriteFile(args[0], schema, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Config schema could not be written to '%s': %v\n", args[0], err)
+		return 1
+	}
+	fmt.Printf("[INFO] Config schema written to '%s'.\n", args[0])
+	return 0
+}
+
+// runConfigCheck loads and validates the configuration without initializing services or using the network,
+// printing every problem found. It returns 0 when the configuration is valid.
+func runConfigCheck(flags AppFlags) int {
+	if flags.GlobalConfigFile != "" {
+		if _, err := os.Stat(flags.GlobalConfigFile); err != nil {
+			fmt.Fprintf(os.Stderr, "[FAIL] Config file '%s' cannot be read: %v\n", flags.GlobalConfigFile, err)
+			return 1
+		}
+	}
+
+	source := config.GetConfigPath(flags.GlobalConfigFile)
+	if source == "" {
+		source = "built-in defaults"
+	}
+
+	gCfg, err := config.LoadGlobalConfig(flags.GlobalConfigFile, zerolog.Nop())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[FAIL] Config '%s' could not be loaded: %v\n", source, err)
+		return 1
+	}
+	if flags.Mode != "" {
+		gCfg.Mode = flags.Mode
+	}
+
+	problems := config.NewConfigValidator(zerolog.Nop()).Problems(gCfg)
+	if _, err := notifier.LoadMessageTemplate(gCfg.NotificationConfig.TemplatePath); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "[FAIL] Config '%s' has %d problem(s):\n", source, len(problems))
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		return 1
+	}
+
+	fmt.Printf("[OK] Config '%s' is valid (mode: %s).\n", source, gCfg.Mode)
+	return 0
+}
+
+func printConfigUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: monsterinc config upgrade <file>")
+	fmt.Fprintln(os.Stderr, "       monsterinc config schema [output-file]")
+}
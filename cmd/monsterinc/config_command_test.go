@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStderr returns what fn writes to os.Stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+
+	original := os.Stderr
+	os.Stderr = writer
+	defer func() { os.Stderr = original }()
+
+	fn()
+	require.NoError(t, writer.Close())
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(output)
+}
+
+func TestRunConfigCheck_ReportsFieldRules(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`mode: onetime
+notification_config:
+  scan_service_discord_webhook_url: "not a url"
+  max_embed_fields: 500
+crawler_config:
+  retry_config:
+    max_jitter_secs: 99999
+  redirects:
+    max_per_chain: -5
+`), 0644))
+
+	var exitCode int
+	output := captureStderr(t, func() {
+		exitCode = runConfigCheck(AppFlags{GlobalConfigFile: configPath})
+	})
+
+	assert.Equal(t, 1, exitCode)
+	assert.Contains(t, output, "has 4 problem(s)")
+	assert.Contains(t, output, "'notification_config.scan_service_discord_webhook_url[0]': rule 'url'")
+	assert.Contains(t, output, "'notification_config.max_embed_fields': rule 'max'")
+	assert.Contains(t, output, "'crawler_config.retry_config.max_jitter_secs': rule 'max'")
+	assert.Contains(t, output, "'crawler_config.redirects.max_per_chain': rule 'min'")
+}
+
+func TestRunConfigCheck_AcceptsValidConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("mode: onetime\n"), 0644))
+
+	assert.Equal(t, 0, runConfigCheck(AppFlags{GlobalConfigFile: configPath}))
+}
@@ -4,17 +4,50 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
 )
 
 type AppFlags struct {
-	ScanTargetsFile  string
+	ScanTargetsFile  string // One or more target files, comma-separated (globs allowed)
+	TargetsStdin     bool
 	GlobalConfigFile string
 	Mode             string
+	RecordFixtures   string
+	ReplayFixtures   string
+	CrawlStateFile   string
+	MaxDuration      time.Duration
+	Baseline         bool
+	ConfigCheck      bool
+	OnlyTags         []string
+	DebugHAR         bool
+	ExpandWildcards  bool
+	SkipWebhookCheck bool
+	Sample           int
+	SamplePercent    float64
+	SampleSeed       int64
+	ProgressBar      bool
+}
+
+// stringListFlag collects the values of a flag that may be given more than once
+type stringListFlag []string
+
+func (s *stringListFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringListFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
 }
 
 func ParseFlags() AppFlags {
-	scanTargetsFile := flag.String("file", "", "Path to a text file containing seed URLs for the main scan. Used if --diff-target-file is not set. This flag is for backward compatibility.")
-	scanTargetsFileAlias := flag.String("f", "", "Alias for -file")
+	var scanTargetsFiles stringListFlag
+	flag.Var(&scanTargetsFiles, "file", "Path to a text file containing seed URLs for the main scan. Repeat the flag, or pass a comma-separated list or glob (e.g. 'recon/*.txt'), to scan the deduplicated union of several files.")
+	flag.Var(&scanTargetsFiles, "f", "Alias for -file")
+	targetsStdin := flag.Bool("targets-stdin", false, "Read seed URLs (one per line) from stdin instead of a file, e.g. 'subfinder -d example.com | monsterinc -m onetime --targets-stdin'")
 
 	globalConfigFile := flag.String("config", "", "Path to the global YAML/JSON configuration file. If not set, searches default locations.")
 	globalConfigFileAlias := flag.String("c", "", "Alias for -config")
@@ -22,15 +55,40 @@ func ParseFlags() AppFlags {
 	modeFlag := flag.String("mode", "", "Mode to run the tool: onetime or automated (overrides config file if set)")
 	modeFlagAlias := flag.String("m", "", "Alias for -mode")
 
+	recordFixtures := flag.String("record-fixtures", "", "Record every crawler HTTP exchange to this JSON Lines fixture file, replacing its contents (httpx probes are not recorded)")
+	replayFixtures := flag.String("replay-fixtures", "", "Replay crawler HTTP responses from this JSON Lines fixture file instead of the network (httpx probes still use the network)")
+
+	debugHAR := flag.Bool("debug-har", false, "Write crawler and prober requests/responses (headers, status, timings) as HAR files to har_export.output_dir for debugging")
+
+	crawlStateFile := flag.String("crawl-state", "", "Snapshot the crawl frontier to this file and resume from it if it exists")
+
+	maxDuration := flag.Duration("max-duration", 0, "Wall-clock cap for a onetime scan (e.g. 30m, 2h); results gathered so far are reported when it is reached")
+
+	baseline := flag.Bool("baseline", false, "Record a baseline: crawl, probe and store results for later diffs without sending change notifications (onetime mode only)")
+
+	expandWildcards := flag.Bool("expand-wildcards", false, "Expand '*.example.com' targets into known subdomains from target_expansion.wildcards sources (passive file and/or certificate transparency)")
+
+	onlyTags := flag.String("only-tags", "", "Scan only targets tagged with one of these comma-separated tags (e.g. 'payments' for lines ending in '|tags=prod,payments'); overrides only_tags in the config")
+
+	sample := flag.Int("sample", 0, "Scan a seeded sample of at most N targets instead of the whole list (e.g. to try a config change); overrides target_sampling in the config")
+	samplePercent := flag.Float64("sample-percent", 0, "Scan a seeded sample of P percent of the targets (e.g. 1 or 0.5); overrides target_sampling in the config")
+	sampleSeed := flag.Int64("sample-seed", 0, "Seed of the target sample; the same seed and targets give the same sample. 0 picks a new seed, printed at startup")
+
+	progressBar := flag.Bool("progress-bar", false, "Draw a scan progress bar (batches, crawled/probed URLs, ETA) on stderr when it is a terminal; overrides progress.bar in the config")
+
+	skipWebhookCheck := flag.Bool("skip-webhook-check", false, "Do not look up the configured Discord webhooks at startup (offline or air-gapped setups); overrides notification_config.preflight_webhooks")
+
+	configCheck := flag.Bool("config-check", false, "Load and validate the configuration, print any problems and exit (non-zero if invalid) without starting services")
+
 	flag.Parse()
 
 	flags := AppFlags{}
 
-	if *scanTargetsFile != "" {
-		flags.ScanTargetsFile = *scanTargetsFile
-	} else if *scanTargetsFileAlias != "" {
-		flags.ScanTargetsFile = *scanTargetsFileAlias
+	if *targetsStdin {
+		// Stdin is merged with any target files like one more file
+		scanTargetsFiles = append(scanTargetsFiles, urlhandler.StdinTargetsPath)
 	}
+	flags.ScanTargetsFile = scanTargetsFiles.String()
 
 	if *globalConfigFile != "" {
 		flags.GlobalConfigFile = *globalConfigFile
@@ -44,10 +102,50 @@ func ParseFlags() AppFlags {
 		flags.Mode = *modeFlagAlias
 	}
 
+	flags.TargetsStdin = *targetsStdin
+	flags.RecordFixtures = *recordFixtures
+	flags.ReplayFixtures = *replayFixtures
+	flags.CrawlStateFile = *crawlStateFile
+	flags.MaxDuration = *maxDuration
+	flags.Baseline = *baseline
+	flags.ConfigCheck = *configCheck
+	flags.OnlyTags = urlhandler.ParseTags(*onlyTags)
+	flags.DebugHAR = *debugHAR
+	flags.ExpandWildcards = *expandWildcards
+	flags.SkipWebhookCheck = *skipWebhookCheck
+	flags.Sample = *sample
+	flags.SamplePercent = *samplePercent
+	flags.SampleSeed = *sampleSeed
+	flags.ProgressBar = *progressBar
+
+	// Validation needs no targets or mode; the mode from the config file is checked instead
+	if flags.ConfigCheck {
+		return flags
+	}
+
+	if flags.RecordFixtures != "" && flags.ReplayFixtures != "" {
+		fmt.Fprintln(os.Stderr, "[FATAL] --record-fixtures and --replay-fixtures cannot be used together")
+		os.Exit(1)
+	}
+
+	if flags.Sample > 0 && flags.SamplePercent > 0 {
+		fmt.Fprintln(os.Stderr, "[FATAL] --sample and --sample-percent cannot be used together")
+		os.Exit(1)
+	}
+	if flags.Sample < 0 || flags.SamplePercent < 0 || flags.SamplePercent > 100 {
+		fmt.Fprintln(os.Stderr, "[FATAL] --sample must be positive and --sample-percent between 0 and 100")
+		os.Exit(1)
+	}
+
 	if flags.Mode == "" {
 		fmt.Fprintln(os.Stderr, "[FATAL] --mode argument is required (onetime or automated)")
 		os.Exit(1)
 	}
 
+	if flags.Baseline && flags.Mode != "onetime" {
+		fmt.Fprintln(os.Stderr, "[FATAL] --baseline can only be used with --mode onetime")
+		os.Exit(1)
+	}
+
 	return flags
 }
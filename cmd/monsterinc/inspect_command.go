@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/datastore"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+)
+
+// inspectDefaultLimit keeps a dump of a large history file to one screen unless more is asked for
+const inspectDefaultLimit = 50
+
+// isInspectCommand reports whether the process was started as `monsterinc inspect ...`
+func isInspectCommand(args []string) bool {
+	return len(args) > 1 && args[1] == "inspect"
+}
+
+// inspectArgs are the parsed arguments of `monsterinc inspect`
+type inspectArgs struct {
+	file    string
+	options datastore.ParquetInspectOptions
+	asJSON  bool
+}
+
+// parseInspectArgs parses the inspect flags, which may come before or after the file name.
+// ok is false when the arguments are invalid and the usage should be printed.
+func parseInspectArgs(args []string) (parsed inspectArgs, ok bool) {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	fs.StringVar(&parsed.options.URLContains, "url", "", "Only show records whose input or final URL contains this substring (case-insensitive)")
+	fs.BoolVar(&parsed.options.DiffsOnly, "diffs", false, "Only show records whose diff status is new or old")
+	fs.IntVar(&parsed.options.Limit, "limit", inspectDefaultLimit, "Maximum number of records to show (0 for all)")
+	fs.IntVar(&parsed.options.Offset, "offset", 0, "Number of matching records to skip")
+	fs.BoolVar(&parsed.asJSON, "json", false, "Print the records as JSON instead of a table")
+	fs.BoolVar(&parsed.options.SchemaOnly, "schema", false, "Only print the schema and record count")
+	if err := fs.Parse(args); err != nil {
+		return inspectArgs{}, false
+	}
+	// Accept flags after the file name too
+	if fs.NArg() > 1 {
+		parsed.file = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil || fs.NArg() > 0 {
+			return inspectArgs{}, false
+		}
+	} else if fs.NArg() == 1 {
+		parsed.file = fs.Arg(0)
+	}
+	if parsed.file == "" || parsed.options.Limit < 0 || parsed.options.Offset < 0 {
+		return inspectArgs{}, false
+	}
+	return parsed, true
+}
+
+// runInspectCommand handles `monsterinc inspect <file.parquet>`, printing the schema, record count and a
+// filtered page of the stored probe results. It returns the process exit code.
+func runInspectCommand(args []string) int {
+	parsed, ok := parseInspectArgs(args)
+	if !ok {
+		printInspectUsage()
+		return 1
+	}
+	offset, schemaOnly := parsed.options.Offset, parsed.options.SchemaOnly
+
+	basicLogger := zerolog.New(os.Stderr).With().Timestamp().Logger().Level(zerolog.WarnLevel)
+	inspection, err := datastore.InspectParquetFile(parsed.file, parsed.options, basicLogger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Could not inspect '%s': %v\n", parsed.file, err)
+		return 1
+	}
+	page := inspection.Results
+
+	if parsed.asJSON {
+		return printInspectionJSON(inspection, offset, schemaOnly)
+	}
+
+	fmt.Printf("File:       %s (%d bytes)\n", inspection.FilePath, inspection.Size)
+	fmt.Printf("Records:    %d in %d row group(s)\n", inspection.NumRows, inspection.RowGroups)
+	fmt.Printf("Schema:\n%s\n", inspection.Schema)
+	if schemaOnly {
+		return 0
+	}
+
+	fmt.Printf("\nShowing %d of %d matching record(s) (offset %d)\n\n", len(page), inspection.Matched, offset)
+	if len(page) == 0 {
+		return 0
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "URL\tMETHOD\tSTATUS\tDIFF\tLAST SEEN\tFIRST SEEN\tTITLE\tERROR")
+	for _, result := range page {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			result.InputURL,
+			result.Method,
+			result.StatusCode,
+			result.URLStatus,
+			formatInspectTime(result.Timestamp),
+			formatInspectTime(result.OldestScanTimestamp),
+			truncateCell(result.Title, 40),
+			truncateCell(result.Error, 60),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Failed to write records: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// printInspectionJSON prints the inspection as one JSON document. Response bodies are left out to keep
+// the dump readable; they are stored compressed in the response_body_gzip column.
+func printInspectionJSON(inspection *datastore.ParquetFileInspection, offset int, schemaOnly bool) int {
+	output := struct {
+		File      string                    `json:"file"`
+		Size      int64                     `json:"size"`
+		Records   int64                     `json:"records"`
+		RowGroups int                       `json:"row_groups"`
+		Schema    string                    `json:"schema"`
+		Matched   int                       `json:"matched,omitempty"`
+		Offset    int                       `json:"offset,omitempty"`
+		Results   []httpxrunner.ProbeResult `json:"results,omitempty"`
+	}{
+		File:      inspection.FilePath,
+		Size:      inspection.Size,
+		Records:   inspection.NumRows,
+		RowGroups: inspection.RowGroups,
+		Schema:    inspection.Schema,
+	}
+	if !schemaOnly {
+		output.Matched = inspection.Matched
+		output.Offset = offset
+		output.Results = make([]httpxrunner.ProbeResult, len(inspection.Results))
+		for i, result := range inspection.Results {
+			result.Body = ""
+			output.Results[i] = result
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Failed to write records: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// formatInspectTime renders a stored timestamp in UTC, or "-" when it was not recorded
+func formatInspectTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// truncateCell shortens a table cell to maxLen runes and flattens line breaks
+func truncateCell(s string, maxLen int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > maxLen {
+		return string(runes[:maxLen-3]) + "..."
+	}
+	return s
+}
+
+func printInspectUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: monsterinc inspect [-url <substring>] [-diffs] [-limit <n>] [-offset <n>] [-json] [-schema] <file.parquet>")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/datastore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInspectArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		want   inspectArgs
+		wantOK bool
+	}{
+		{
+			name:   "file only",
+			args:   []string{"example.com.parquet"},
+			want:   inspectArgs{file: "example.com.parquet", options: datastore.ParquetInspectOptions{Limit: inspectDefaultLimit}},
+			wantOK: true,
+		},
+		{
+			name: "flags before the file",
+			args: []string{"-url", "/api/", "-diffs", "-limit", "20", "example.com.parquet"},
+			want: inspectArgs{file: "example.com.parquet", options: datastore.ParquetInspectOptions{
+				URLContains: "/api/", DiffsOnly: true, Limit: 20,
+			}},
+			wantOK: true,
+		},
+		{
+			name: "flags after the file",
+			args: []string{"example.com.parquet", "-offset", "10", "-json", "-schema"},
+			want: inspectArgs{file: "example.com.parquet", asJSON: true, options: datastore.ParquetInspectOptions{
+				Offset: 10, Limit: inspectDefaultLimit, SchemaOnly: true,
+			}},
+			wantOK: true,
+		},
+		{
+			name: "flags on both sides",
+			args: []string{"-diffs", "example.com.parquet", "-limit", "0"},
+			want: inspectArgs{file: "example.com.parquet", options: datastore.ParquetInspectOptions{
+				DiffsOnly: true,
+			}},
+			wantOK: true,
+		},
+		{name: "no file", args: []string{"-diffs"}},
+		{name: "two files", args: []string{"a.parquet", "b.parquet"}},
+		{name: "argument after trailing flags", args: []string{"a.parquet", "-diffs", "b.parquet"}},
+		{name: "negative limit", args: []string{"a.parquet", "-limit", "-1"}},
+		{name: "negative offset", args: []string{"-offset", "-2", "a.parquet"}},
+		{name: "unknown flag after the file", args: []string{"a.parquet", "-verbose"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got inspectArgs
+			var ok bool
+			captureStderr(t, func() { got, ok = parseInspectArgs(tt.args) })
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
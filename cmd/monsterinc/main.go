@@ -4,21 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/contextutils"
 	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/common/interrupthooks"
 	"github.com/aleister1102/monsterinc/internal/common/summary"
 	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/datastore"
+	"github.com/aleister1102/monsterinc/internal/events"
 	"github.com/aleister1102/monsterinc/internal/logger"
 	"github.com/aleister1102/monsterinc/internal/notifier"
 	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/aleister1102/monsterinc/internal/reporter"
 	"github.com/aleister1102/monsterinc/internal/scanner"
 	"github.com/aleister1102/monsterinc/internal/scheduler"
 	"github.com/rs/zerolog"
@@ -72,14 +77,30 @@ func getAndSetInterruptNotificationSent() bool {
 }
 
 func main() {
+	if isConfigCommand(os.Args) {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+	if isNotifyCommand(os.Args) {
+		os.Exit(runNotifyCommand(os.Args[2:]))
+	}
+	if isInspectCommand(os.Args) {
+		os.Exit(runInspectCommand(os.Args[2:]))
+	}
+	if isReportsCommand(os.Args) {
+		os.Exit(runReportsCommand(os.Args[2:]))
+	}
+
+	flags := ParseFlags()
+	if flags.ConfigCheck {
+		os.Exit(runConfigCheck(flags))
+	}
+
 	fmt.Println("MonsterInc Crawler starting...")
 
 	// Set function pointer for scheduler to track active scans
 	scheduler.SetActiveScanSessionID = setActiveScanSessionID
 	scheduler.GetAndSetInterruptNotificationSent = getAndSetInterruptNotificationSent
 
-	flags := ParseFlags()
-
 	gCfg, err := loadConfiguration(flags)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[FATAL] Main: %v\n", err)
@@ -95,9 +116,9 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	discordHttpClient, err := httpclient.NewHTTPClientFactory(zLogger).CreateDiscordClient(
-		20 * time.Second,
-	)
+	discordHttpClient, err := httpclient.NewHTTPClientFactory(zLogger).
+		WithProxyConfig(gCfg.ProxyConfig).
+		CreateDiscordClient(20 * time.Second)
 	if err != nil {
 		zLogger.Fatal().Err(err).Msg("Failed to create Discord HTTP client.")
 	}
@@ -105,13 +126,28 @@ func main() {
 	if err != nil {
 		zLogger.Fatal().Err(err).Msg("Failed to initialize DiscordNotifier infra.")
 	}
-	notificationHelper := notifier.NewNotificationHelper(discordNotifier, gCfg.NotificationConfig, zLogger)
+	messageTemplate, err := notifier.LoadMessageTemplate(gCfg.NotificationConfig.TemplatePath)
+	if err != nil {
+		zLogger.Fatal().Err(err).Msg("Failed to load notification template.")
+	}
+	notificationHelper := notifier.NewNotificationHelper(discordNotifier, gCfg.NotificationConfig, zLogger).
+		WithMessageTemplate(messageTemplate).
+		WithReportPartSize(gCfg.ReporterConfig.MaxProbeResultsPerReportFile).
+		WithKeptReportFiles(gCfg.ReportBrowser.Enabled).
+		WithOnetimeMode(gCfg.Mode == "onetime")
+	if gCfg.NotificationConfig.PreflightWebhooks && !flags.SkipWebhookCheck {
+		preflightWebhooks(ctx, gCfg.NotificationConfig, discordNotifier, zLogger)
+	}
 
 	scanner, err := initializeScanner(gCfg, zLogger)
 	if err != nil {
 		zLogger.Fatal().Err(err).Msg("Failed to initialize scanner.")
 	}
+	scanner.SetEventSink(events.NewEventSinkFromConfig(gCfg.EventSinkConfig, gCfg.ProxyConfig, zLogger))
+	runStorageRetention(ctx, gCfg, zLogger)
+	startReportBrowser(ctx, gCfg, zLogger)
 
+	registerConfiguredInterruptHooks(gCfg.InterruptHooks)
 	setupSignalHandling(cancel, zLogger, notificationHelper, gCfg)
 
 	var schedulerPtr *scheduler.Scheduler
@@ -140,7 +176,44 @@ func loadConfiguration(flags AppFlags) (*config.GlobalConfig, error) {
 		fmt.Printf("[INFO] Main: Mode set to '%s' from command line flag.\n", gCfg.Mode)
 	}
 
-	if gCfg.ReporterConfig.OutputDir != "" {
+	if flags.RecordFixtures != "" {
+		gCfg.CrawlerConfig.Fixtures.RecordPath = flags.RecordFixtures
+		fmt.Printf("[INFO] Main: Recording crawler HTTP fixtures to '%s'.\n", flags.RecordFixtures)
+	}
+	if flags.ReplayFixtures != "" {
+		gCfg.CrawlerConfig.Fixtures.ReplayPath = flags.ReplayFixtures
+		fmt.Printf("[INFO] Main: Replaying crawler HTTP fixtures from '%s'.\n", flags.ReplayFixtures)
+	}
+
+	if flags.DebugHAR {
+		gCfg.HARExport.Enabled = true
+		fmt.Printf("[INFO] Main: Exporting crawler and prober traffic as HAR files to '%s'.\n", gCfg.HARExport.OutputDir)
+	}
+
+	if flags.CrawlStateFile != "" {
+		gCfg.CrawlerConfig.CrawlState.FilePath = flags.CrawlStateFile
+		fmt.Printf("[INFO] Main: Crawl frontier state will be saved to and resumed from '%s'.\n", flags.CrawlStateFile)
+	}
+
+	if flags.ExpandWildcards {
+		gCfg.TargetExpansion.Wildcards.Enabled = true
+		fmt.Println("[INFO] Main: Wildcard targets will be expanded into known subdomains.")
+	}
+
+	if len(flags.OnlyTags) > 0 {
+		gCfg.OnlyTags = flags.OnlyTags
+		fmt.Printf("[INFO] Main: Scanning only targets tagged %s.\n", strings.Join(flags.OnlyTags, ", "))
+	}
+
+	applySamplingFlags(gCfg, flags)
+
+	if flags.ProgressBar {
+		gCfg.Progress.Bar = true
+		fmt.Println("[INFO] Main: Drawing a scan progress bar on stderr.")
+	}
+
+	// A templated output directory is created per scan when reports are written
+	if gCfg.ReporterConfig.OutputDir != "" && !config.HasPathTokens(gCfg.ReporterConfig.OutputDir) {
 		if err := os.MkdirAll(gCfg.ReporterConfig.OutputDir, 0755); err != nil {
 			return gCfg, fmt.Errorf("could not create default report output directory '%s': %w", gCfg.ReporterConfig.OutputDir, err)
 		}
@@ -151,9 +224,46 @@ func loadConfiguration(flags AppFlags) (*config.GlobalConfig, error) {
 	}
 
 	fmt.Printf("[INFO] Main: Configuration validated successfully.\n")
+
+	// Scan history must outlive a single scan, so the storage path is expanded once per process
+	if config.HasPathTokens(gCfg.StorageConfig.ParquetBasePath) {
+		gCfg.StorageConfig.ParquetBasePath = config.ExpandPathTemplate(gCfg.StorageConfig.ParquetBasePath, "", time.Now())
+		fmt.Printf("[INFO] Main: Storing scan history under '%s'.\n", gCfg.StorageConfig.ParquetBasePath)
+	}
+
+	if gCfg.StorageConfig.ResponseBodies.Enabled && !gCfg.HttpxRunnerConfig.ExtractBody {
+		fmt.Println("[WARN] Main: storage_config.response_bodies is enabled but httpx_runner_config.extract_body is false; no bodies will be stored.")
+	}
 	return gCfg, nil
 }
 
+// applySamplingFlags lets --sample, --sample-percent and --sample-seed override target_sampling, and fixes the
+// seed of a sampled run so every component that loads the targets selects the same sample
+func applySamplingFlags(gCfg *config.GlobalConfig, flags AppFlags) {
+	switch {
+	case flags.Sample > 0:
+		gCfg.TargetSampling.Count, gCfg.TargetSampling.Percent = flags.Sample, 0
+	case flags.SamplePercent > 0:
+		gCfg.TargetSampling.Count, gCfg.TargetSampling.Percent = 0, flags.SamplePercent
+	}
+	if flags.SampleSeed != 0 {
+		gCfg.TargetSampling.Seed = flags.SampleSeed
+	}
+
+	sampling := &gCfg.TargetSampling
+	if !sampling.Enabled() {
+		return
+	}
+	if sampling.Seed == 0 {
+		sampling.Seed = rand.Int64N(1_000_000_000) + 1
+	}
+	size := fmt.Sprintf("%d targets", sampling.Count)
+	if sampling.Count == 0 {
+		size = fmt.Sprintf("%g%% of targets", sampling.Percent)
+	}
+	fmt.Printf("[INFO] Main: Sampled run: scanning %s with seed %d (repeat with --sample-seed %d).\n", size, sampling.Seed, sampling.Seed)
+}
+
 // initializeLogger initializes the logger based on the global configuration.
 // Refactored ✅
 func initializeLogger(gCfg *config.GlobalConfig) (zerolog.Logger, error) {
@@ -166,12 +276,46 @@ func initializeLogger(gCfg *config.GlobalConfig) (zerolog.Logger, error) {
 	return zLogger, nil
 }
 
+// runStorageRetention deletes archived scan files once at startup when storage retention is enabled.
+// Automated mode repeats the cleanup from the scheduler.
+func runStorageRetention(ctx context.Context, gCfg *config.GlobalConfig, appLogger zerolog.Logger) {
+	if !gCfg.StorageConfig.Retention.Enabled {
+		return
+	}
+
+	cleaner, err := datastore.NewRetentionCleaner(&gCfg.StorageConfig, appLogger)
+	if err != nil {
+		appLogger.Error().Err(err).Msg("Failed to create storage retention cleaner.")
+		return
+	}
+	if _, err := cleaner.Cleanup(ctx); err != nil {
+		appLogger.Error().Err(err).Msg("Storage retention cleanup failed.")
+	}
+}
+
+// startReportBrowser serves the report browser in the background for as long as the process runs
+func startReportBrowser(ctx context.Context, gCfg *config.GlobalConfig, appLogger zerolog.Logger) {
+	if !gCfg.ReportBrowser.Enabled {
+		return
+	}
+
+	browser := reporter.NewReportBrowser(gCfg.ReportBrowser.IndexFile, appLogger)
+	go func() {
+		if err := browser.Serve(ctx, gCfg.ReportBrowser.ListenAddr); err != nil {
+			appLogger.Error().Err(err).Msg("Report browser failed.")
+		}
+	}()
+}
+
 // initializeScanner initializes the scanner with the provided global configuration and logger.
 // Refactored ✅
 func initializeScanner(gCfg *config.GlobalConfig, appLogger zerolog.Logger) (*scanner.Scanner, error) {
 	pReader := datastore.NewParquetReader(&gCfg.StorageConfig, appLogger)
 
-	pWriter, err := datastore.NewParquetWriter(&gCfg.StorageConfig, appLogger)
+	pWriter, err := datastore.NewParquetWriterBuilder(appLogger).
+		WithStorageConfig(&gCfg.StorageConfig).
+		WithExtractionConfig(&gCfg.HttpxRunnerConfig).
+		Build()
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize ParquetWriter: %w", err)
 	}
@@ -182,6 +326,16 @@ func initializeScanner(gCfg *config.GlobalConfig, appLogger zerolog.Logger) (*sc
 	return scannerInstance, nil
 }
 
+// registerConfiguredInterruptHooks registers shell commands from config as interrupt hooks
+func registerConfiguredInterruptHooks(cfg config.InterruptHooksConfig) {
+	for i, command := range cfg.Commands {
+		if command == "" {
+			continue
+		}
+		interrupthooks.Register(fmt.Sprintf("config_command_%d", i+1), interrupthooks.CommandHook(command))
+	}
+}
+
 func setupSignalHandling(
 	cancel context.CancelFunc,
 	zLogger zerolog.Logger,
@@ -246,6 +400,13 @@ func setupSignalHandling(
 			zLogger.Debug().Msg("Specific interrupt notification already sent, skipping general notification")
 		}
 
+		// Run registered cleanup hooks (e.g. releasing external locks) before components are cancelled
+		hookTimeout := time.Duration(gCfg.InterruptHooks.TimeoutSecs) * time.Second
+		if hookTimeout <= 0 {
+			hookTimeout = time.Duration(config.DefaultInterruptHooksTimeoutSecs) * time.Second
+		}
+		interrupthooks.Default().Run(hookTimeout, zLogger.With().Str("component", "InterruptHooks").Logger())
+
 		// Wait a moment for notifications to be sent before cancelling context
 		time.Sleep(1 * time.Second)
 
@@ -277,7 +438,7 @@ func runApplicationLogic(
 	scanTargetsFile := ""
 	if flags.ScanTargetsFile != "" {
 		scanTargetsFile = flags.ScanTargetsFile
-		zLogger.Info().Str("file", scanTargetsFile).Msg("Using -st for main scan targets.")
+		zLogger.Info().Str("file", scanTargetsFile).Bool("stdin", flags.TargetsStdin).Msg("Using -st for main scan targets.")
 	}
 
 	// Set notification helper for scanner
@@ -288,6 +449,8 @@ func runApplicationLogic(
 			ctx,
 			gCfg,
 			scanTargetsFile,
+			resolveMaxDuration(gCfg, flags),
+			flags.Baseline,
 			zLogger,
 			notificationHelper,
 			scanner,
@@ -305,20 +468,34 @@ func runApplicationLogic(
 	}
 }
 
+// resolveMaxDuration returns the onetime scan wall-clock cap; the --max-duration flag overrides the config
+func resolveMaxDuration(gCfg *config.GlobalConfig, flags AppFlags) time.Duration {
+	if flags.MaxDuration > 0 {
+		return flags.MaxDuration
+	}
+	return time.Duration(gCfg.MaxDurationMins) * time.Minute
+}
+
 func runOnetimeScan(
 	ctx context.Context,
 	gCfg *config.GlobalConfig,
 	scanTargetsFile string,
+	maxDuration time.Duration,
+	baseline bool,
 	baseLogger zerolog.Logger,
 	notificationHelper *notifier.NotificationHelper,
 	scannerInstance *scanner.Scanner,
 ) {
-	// Create a new context for this scan that we can cancel.
+	// Create a new context for this scan that we can cancel, bounded by the max duration if set.
 	scanCtx, scanCancel := context.WithCancel(ctx)
+	if maxDuration > 0 {
+		scanCtx, scanCancel = context.WithTimeout(ctx, maxDuration)
+		baseLogger.Info().Dur("max_duration", maxDuration).Msg("Onetime scan wall-clock cap enabled")
+	}
 	defer scanCancel() // Ensure it's cancelled on return
 
 	// Load seed URLs using TargetManager
-	targetManager := urlhandler.NewTargetManager(baseLogger)
+	targetManager := urlhandler.NewTargetManager(baseLogger).WithExpansionConfig(gCfg.TargetExpansion.ToTargetExpansionConfig()).WithTagFilter(gCfg.OnlyTags).WithSampling(gCfg.TargetSampling.ToTargetSamplingConfig())
 	scanTargets, targetSource, err := targetManager.LoadAndSelectTargets(scanTargetsFile)
 
 	if err != nil {
@@ -354,6 +531,9 @@ func runOnetimeScan(
 	scanMode := "onetime"
 	scanUrls := targetManager.GetTargetStrings(scanTargets) // Convert to string slice for notification
 	baseLogger.Info().Int("count", len(scanUrls)).Str("source", targetSource).Msg("Starting onetime scan with seed URLs.")
+	if baseline {
+		baseLogger.Info().Msg("Baseline run: results are stored for later diffs, change notifications are suppressed.")
+	}
 
 	// Create single session ID for the entire scan
 	scanSessionID := time.Now().Format("20060102-150405")
@@ -375,52 +555,69 @@ func runOnetimeScan(
 	startSummary.ScanMode = scanMode
 	startSummary.Targets = scanUrls
 	startSummary.TotalTargets = len(scanUrls)
+	startSummary.MaxDuration = maxDuration
+	startSummary.Baseline = baseline
 	// Send scan start notification
 	notificationHelper.SendScanStartNotification(ctx, startSummary)
 
-	// Create batch workflow orchestrator
-	batchOrchestrator := scanner.NewBatchWorkflowOrchestrator(gCfg, scannerInstance, scanLogger)
-
-	// Execute batch scan
-	batchResult, workflowErr := batchOrchestrator.ExecuteBatchScan(
-		scanCtx,
-		gCfg,
-		scanTargetsFile,
-		scanSessionID,
-		targetSource,
-		scanMode,
-	)
+	// Run the scan through the in-process API, reusing the shared scanner so signal handling can shut it down
+	batchResult, workflowErr := scanner.NewOnetimeRunner(gCfg, scanLogger).
+		WithScanner(scannerInstance).
+		WithScanSessionID(scanSessionID).
+		WithTargetSource(targetSource).
+		WithBaseline(baseline).
+		WithTargetCredentials(urlhandler.NewTargetCredentials(scanTargets)).
+		WithTargetTags(urlhandler.NewTargetTags(scanTargets)).
+		WithTargetTimeouts(urlhandler.NewTargetTimeouts(scanTargets)).
+		Run(scanCtx, scanUrls)
 
 	// Clear active scan session when done
 	setActiveScanSessionID("")
 
-	var summaryData summary.ScanSummaryData
-	var reportFilePaths []string
+	if batchResult == nil {
+		// Targets were already validated above, so this only happens on a programming error
+		batchResult = &scanner.BatchScanResult{SummaryData: summary.GetDefaultScanSummaryData()}
+		batchResult.SummaryData.Status = string(summary.ScanStatusFailed)
+		if workflowErr != nil {
+			batchResult.SummaryData.ErrorMessages = []string{workflowErr.Error()}
+		}
+	}
 
-	if batchResult != nil {
-		summaryData = batchResult.SummaryData
-		reportFilePaths = batchResult.ReportFilePaths
+	summaryData := batchResult.SummaryData
+	reportFilePaths := batchResult.ReportFilePaths
 
-		// Log batch processing information
-		if batchResult.UsedBatching {
-			baseLogger.Info().
-				Int("total_batches", batchResult.TotalBatches).
-				Int("processed_batches", batchResult.ProcessedBatches).
-				Bool("interrupted", batchResult.InterruptedAt > 0).
-				Msg("Batch scan workflow completed")
-		}
-	} else {
-		// Fallback to default summary if batch result is nil
-		summaryData = summary.GetDefaultScanSummaryData()
+	// Log batch processing information
+	if batchResult.UsedBatching {
+		baseLogger.Info().
+			Int("total_batches", batchResult.TotalBatches).
+			Int("processed_batches", batchResult.ProcessedBatches).
+			Bool("interrupted", batchResult.InterruptedAt > 0).
+			Msg("Batch scan workflow completed")
+	}
+
+	summaryData.MaxDuration = maxDuration
+	summaryData.Baseline = baseline
+
+	// Max duration reached: finalize with whatever results and reports were gathered
+	if ctx.Err() == nil && errors.Is(scanCtx.Err(), context.DeadlineExceeded) {
+		baseLogger.Warn().
+			Str("scanSessionID", scanSessionID).
+			Dur("max_duration", maxDuration).
+			Msg("Onetime scan reached its max duration, finalizing with partial results.")
+
+		summaryData.Status = string(summary.ScanStatusPartialComplete)
 		summaryData.ScanSessionID = scanSessionID
 		summaryData.TargetSource = targetSource
 		summaryData.ScanMode = scanMode
 		summaryData.Targets = scanUrls
 		summaryData.TotalTargets = len(scanTargets)
-		summaryData.Status = string(summary.ScanStatusFailed)
-		if workflowErr != nil {
-			summaryData.ErrorMessages = []string{workflowErr.Error()}
+		if summaryData.ScanDuration == 0 {
+			summaryData.ScanDuration = maxDuration
 		}
+		summaryData.ErrorMessages = append(summaryData.ErrorMessages, fmt.Sprintf("Scan stopped after reaching max duration of %s.", maxDuration))
+
+		notificationHelper.SendScanCompletionNotification(context.Background(), summaryData, reportFilePaths)
+		return
 	}
 
 	// Handle workflow error or context cancellation
@@ -460,10 +657,6 @@ func runOnetimeScan(
 	baseLogger.Info().Msg("Scanner cleanup was handled within batch workflow - scan completed safely")
 
 	baseLogger.Info().Msg("MonsterInc Crawler finished (onetime mode).")
-
-	// Force exit for onetime mode to prevent hanging - this might not be needed after the fix.
-	// We'll leave it for now to be safe.
-	os.Exit(0)
 }
 
 func runAutomatedScan(
@@ -501,6 +694,7 @@ func runAutomatedScan(
 		return
 	}
 	*schedulerPtr = scheduler
+	watchPauseSignals(ctx, scheduler, zLogger)
 
 	// Start the scheduler. This is a blocking call.
 	if err := (*schedulerPtr).Start(ctx); err != nil {
@@ -558,7 +752,8 @@ func shutdownServices(
 		zLogger.Info().Msg("Shutdown sequence completed.")
 	}()
 
-	// Wait for either shutdown completion or timeout
+	// Wait for either shutdown completion or timeout. Returning from main ends the process even if a
+	// component is still stuck, so the timeout is the safety net for a shutdown that does not finish.
 	select {
 	case <-done:
 		if ctx.Err() == context.Canceled {
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/rs/zerolog"
+)
+
+// webhookTestTimeout bounds each test message so an unreachable webhook cannot hang a deploy
+const webhookTestTimeout = 20 * time.Second
+
+// isNotifyCommand reports whether the process was started as `monsterinc notify ...`
+func isNotifyCommand(args []string) bool {
+	return len(args) > 1 && args[1] == "notify"
+}
+
+// runNotifyCommand handles `monsterinc notify <subcommand>` and returns the process exit code
+func runNotifyCommand(args []string) int {
+	if len(args) < 1 {
+		printNotifyUsage()
+		return 1
+	}
+
+	switch args[0] {
+	case "test":
+		return runNotifyTest(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "[FATAL] Unknown notify subcommand '%s'\n", args[0])
+		printNotifyUsage()
+		return 1
+	}
+}
+
+// runNotifyTest sends a test message to every configured webhook through DiscordNotifier.SendNotification
+func runNotifyTest(args []string) int {
+	fs := flag.NewFlagSet("notify test", flag.ContinueOnError)
+	configFile := fs.String("config", "", "Path to the global YAML/JSON configuration file. If not set, searches default locations.")
+	configFileAlias := fs.String("c", "", "Alias for -config")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		printNotifyUsage()
+		return 1
+	}
+	if *configFile == "" {
+		*configFile = *configFileAlias
+	}
+
+	basicLogger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	gCfg, err := config.LoadGlobalConfig(*configFile, basicLogger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Could not load global config using path '%s': %v\n", *configFile, err)
+		return 1
+	}
+
+	webhooks := notifier.ConfiguredWebhooks(gCfg.NotificationConfig)
+
+	// Keep the notifier quiet; results are reported per webhook below
+	quietLogger := basicLogger.Level(zerolog.Disabled)
+	httpClient, err := httpclient.NewHTTPClientFactory(quietLogger).
+		WithProxyConfig(gCfg.ProxyConfig).
+		CreateDiscordClient(webhookTestTimeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Failed to create Discord HTTP client: %v\n", err)
+		return 1
+	}
+	discordNotifier, err := discord.NewDiscordNotifier(&gCfg.NotificationConfig, quietLogger, httpClient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Failed to initialize DiscordNotifier: %v\n", err)
+		return 1
+	}
+
+	tested, failed := 0, 0
+	for _, webhook := range webhooks {
+		if webhook.URL == "" {
+			fmt.Printf("[SKIP] %s webhook: not configured\n", webhook.Channel)
+			continue
+		}
+		tested++
+
+		ctx, cancel := context.WithTimeout(context.Background(), webhookTestTimeout)
+		err := discordNotifier.SendNotification(ctx, webhook.URL, notifier.FormatWebhookTestMessage(webhook.Channel), "")
+		cancel()
+
+		if err != nil {
+			failed++
+			fmt.Printf("[FAIL] %s webhook: %s\n", webhook.Channel, describeWebhookError(err))
+			continue
+		}
+		fmt.Printf("[OK]   %s webhook: test message delivered\n", webhook.Channel)
+	}
+
+	if tested == 0 {
+		fmt.Fprintln(os.Stderr, "[FATAL] No Discord webhooks are configured in notification_config.")
+		return 1
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// describeWebhookError leads with the HTTP status when Discord answered, so auth and routing problems stand out
+func describeWebhookError(err error) string {
+	var httpErr *errorwrapper.HTTPError
+	if errors.As(err, &httpErr) {
+		return fmt.Sprintf("HTTP %d: %s", httpErr.StatusCode, httpErr.Message)
+	}
+	return err.Error()
+}
+
+func printNotifyUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: monsterinc notify test [-config <file>]")
+}
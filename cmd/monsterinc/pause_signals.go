@@ -0,0 +1,37 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aleister1102/monsterinc/internal/scheduler"
+	"github.com/rs/zerolog"
+)
+
+// watchPauseSignals pauses the scheduler on SIGUSR1 and resumes it on SIGUSR2 until ctx is done
+func watchPauseSignals(ctx context.Context, s *scheduler.Scheduler, zLogger zerolog.Logger) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGUSR1, syscall.SIGUSR2)
+	zLogger.Info().Int("pid", os.Getpid()).Msg("Send SIGUSR1 to pause scheduled scans and SIGUSR2 to resume them")
+
+	go func() {
+		defer signal.Stop(sigChan)
+		for {
+			select {
+			case sig := <-sigChan:
+				zLogger.Info().Str("signal", sig.String()).Msg("Scheduler control signal received")
+				if sig == syscall.SIGUSR1 {
+					s.Pause()
+				} else {
+					s.Resume()
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
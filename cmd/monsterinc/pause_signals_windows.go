@@ -0,0 +1,13 @@
+package main
+
+import (
+	"context"
+
+	"github.com/aleister1102/monsterinc/internal/scheduler"
+	"github.com/rs/zerolog"
+)
+
+// watchPauseSignals is a no-op on Windows, which has no SIGUSR1/SIGUSR2
+func watchPauseSignals(_ context.Context, _ *scheduler.Scheduler, zLogger zerolog.Logger) {
+	zLogger.Debug().Msg("Pausing the scheduler with signals is not supported on Windows")
+}
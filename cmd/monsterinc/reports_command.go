@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/reporter"
+	"github.com/rs/zerolog"
+)
+
+// isReportsCommand reports whether the process was started as `monsterinc reports ...`
+func isReportsCommand(args []string) bool {
+	return len(args) > 1 && args[1] == "reports"
+}
+
+// runReportsCommand handles `monsterinc reports <subcommand>` and returns the process exit code
+func runReportsCommand(args []string) int {
+	if len(args) < 1 {
+		printReportsUsage()
+		return 1
+	}
+
+	switch args[0] {
+	case "serve":
+		return runReportsServe(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "[FATAL] Unknown reports subcommand '%s'\n", args[0])
+		printReportsUsage()
+		return 1
+	}
+}
+
+// runReportsServe serves the report browser on its own, without scanning, until interrupted
+func runReportsServe(args []string) int {
+	fs := flag.NewFlagSet("reports serve", flag.ContinueOnError)
+	configFile := fs.String("config", "", "Path to the global YAML/JSON configuration file. If not set, searches default locations.")
+	configFileAlias := fs.String("c", "", "Alias for -config")
+	listenAddr := fs.String("listen", "", "Address to listen on, overriding report_browser.listen_addr")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		printReportsUsage()
+		return 1
+	}
+	if *configFile == "" {
+		*configFile = *configFileAlias
+	}
+
+	basicLogger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	gCfg, err := config.LoadGlobalConfig(*configFile, basicLogger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] Could not load global config using path '%s': %v\n", *configFile, err)
+		return 1
+	}
+	if *listenAddr == "" {
+		*listenAddr = gCfg.ReportBrowser.ListenAddr
+	}
+	if *listenAddr == "" || gCfg.ReportBrowser.IndexFile == "" {
+		fmt.Fprintln(os.Stderr, "[FATAL] report_browser.listen_addr and report_browser.index_file must be set")
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	browser := reporter.NewReportBrowser(gCfg.ReportBrowser.IndexFile, basicLogger)
+	if err := browser.Serve(ctx, *listenAddr); err != nil {
+		fmt.Fprintf(os.Stderr, "[FATAL] %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printReportsUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: monsterinc reports serve [-config <file>] [-listen <addr>]")
+}
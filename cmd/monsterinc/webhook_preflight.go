@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/rs/zerolog"
+)
+
+// webhookPreflightTimeout bounds each webhook lookup so an unreachable Discord cannot stall startup for long
+const webhookPreflightTimeout = 10 * time.Second
+
+// preflightWebhooks looks up every configured webhook without posting to it and warns about each one that is
+// not a reachable Discord webhook. Startup continues either way; notifications to a failing webhook are lost
+// until it is fixed.
+func preflightWebhooks(ctx context.Context, cfg config.NotificationConfig, discordNotifier *discord.DiscordNotifier, appLogger zerolog.Logger) {
+	checked, failed := 0, 0
+	for _, webhook := range notifier.ConfiguredWebhooks(cfg) {
+		if webhook.URL == "" {
+			continue
+		}
+		checked++
+
+		checkCtx, cancel := context.WithTimeout(ctx, webhookPreflightTimeout)
+		info, err := discordNotifier.CheckWebhook(checkCtx, webhook.URL)
+		cancel()
+
+		if err != nil {
+			failed++
+			// Printed as well as logged: the log may only go to a file, and this is what explains missing notifications
+			fmt.Fprintf(os.Stderr, "[WARN] Main: %s webhook failed the startup check, its notifications will not arrive: %s\n", webhook.Channel, describeWebhookError(err))
+			appLogger.Warn().Err(err).Str("channel", webhook.Channel).Msg("Discord webhook failed the startup check")
+			continue
+		}
+		appLogger.Debug().Str("channel", webhook.Channel).Str("webhook_name", info.Name).Str("channel_id", info.ChannelID).Msg("Discord webhook checked")
+	}
+
+	if checked > 0 {
+		appLogger.Info().Int("checked", checked).Int("failed", failed).Msg("Discord webhook startup check completed")
+	}
+}
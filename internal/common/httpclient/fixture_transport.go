@@ -0,0 +1,287 @@
+package httpclient
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/rs/zerolog"
+)
+
+// FixtureMode selects how a FixtureTransport treats requests
+type FixtureMode string
+
+const (
+	// FixtureModeRecord forwards requests to the base transport and appends every exchange to the fixture file
+	FixtureModeRecord FixtureMode = "record"
+	// FixtureModeReplay serves responses from the fixture file without touching the network
+	FixtureModeReplay FixtureMode = "replay"
+)
+
+// startedFixtureRecordings holds the fixture paths this process has started recording to
+var (
+	startedFixtureRecordings   = make(map[string]bool)
+	startedFixtureRecordingsMu sync.Mutex
+)
+
+// FixtureEntry is one recorded HTTP exchange.
+//
+// Fixture files are JSON Lines: one FixtureEntry object per line, appended in the order
+// the exchanges were recorded. Entries are matched by Key, which is derived from the
+// request method, the full URL and the SHA-256 of the request body. When the same key is
+// recorded more than once, replay serves the recorded responses in order and repeats the
+// last one once they are exhausted. Body is base64-encoded by encoding/json.
+type FixtureEntry struct {
+	Key         string              `json:"key"`
+	Method      string              `json:"method"`
+	URL         string              `json:"url"`
+	BodySHA256  string              `json:"body_sha256"`
+	StatusCode  int                 `json:"status_code"`
+	Headers     map[string][]string `json:"headers,omitempty"`
+	Body        []byte              `json:"body,omitempty"`
+	RecordedErr string              `json:"error,omitempty"`
+}
+
+// FixtureTransport wraps an http.RoundTripper to record or replay HTTP exchanges
+type FixtureTransport struct {
+	base    http.RoundTripper
+	mode    FixtureMode
+	path    string
+	logger  zerolog.Logger
+	mu      sync.Mutex
+	entries map[string][]FixtureEntry
+	served  map[string]int
+}
+
+// NewFixtureTransport creates a FixtureTransport. In replay mode the fixture file is loaded eagerly
+// and base may be nil. In record mode the first transport of the process recording to path truncates
+// the file, so a recording never mixes with the exchanges of an earlier run; later ones append to it.
+func NewFixtureTransport(base http.RoundTripper, mode FixtureMode, path string, logger zerolog.Logger) (*FixtureTransport, error) {
+	if path == "" {
+		return nil, errorwrapper.NewValidationError("path", path, "fixture path cannot be empty")
+	}
+
+	ft := &FixtureTransport{
+		base:    base,
+		mode:    mode,
+		path:    path,
+		logger:  logger.With().Str("component", "FixtureTransport").Str("mode", string(mode)).Logger(),
+		entries: make(map[string][]FixtureEntry),
+		served:  make(map[string]int),
+	}
+
+	switch mode {
+	case FixtureModeReplay:
+		if err := ft.load(); err != nil {
+			return nil, err
+		}
+	case FixtureModeRecord:
+		if base == nil {
+			return nil, errorwrapper.NewValidationError("base", base, "base transport is required in record mode")
+		}
+		if dir := filepath.Dir(path); dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return nil, errorwrapper.WrapError(err, "failed to create fixture directory")
+			}
+		}
+		if err := startFixtureRecording(path); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errorwrapper.NewValidationError("mode", mode, "fixture mode must be 'record' or 'replay'")
+	}
+
+	return ft, nil
+}
+
+// FixtureKey builds the match key for a request from its method, URL and body
+func FixtureKey(method, rawURL string, body []byte) string {
+	return method + " " + rawURL + " " + hashFixtureBody(body)
+}
+
+// RoundTrip implements http.RoundTripper
+func (ft *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readAndRestoreBody(req)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to read request body for fixture matching")
+	}
+
+	key := FixtureKey(req.Method, req.URL.String(), body)
+
+	if ft.mode == FixtureModeReplay {
+		return ft.replay(req, key)
+	}
+	return ft.record(req, key, body)
+}
+
+// replay serves a recorded response for the request key
+func (ft *FixtureTransport) replay(req *http.Request, key string) (*http.Response, error) {
+	ft.mu.Lock()
+	recorded := ft.entries[key]
+	idx := ft.served[key]
+	if idx < len(recorded)-1 {
+		ft.served[key] = idx + 1
+	}
+	ft.mu.Unlock()
+
+	if len(recorded) == 0 {
+		ft.logger.Debug().Str("method", req.Method).Str("url", req.URL.String()).Msg("No fixture recorded for request")
+		return nil, fmt.Errorf("no fixture recorded for %s %s", req.Method, req.URL.String())
+	}
+
+	entry := recorded[idx]
+	if entry.RecordedErr != "" {
+		return nil, fmt.Errorf("replayed error: %s", entry.RecordedErr)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header(entry.Headers).Clone(),
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
+
+// record forwards the request to the base transport and persists the exchange
+func (ft *FixtureTransport) record(req *http.Request, key string, body []byte) (*http.Response, error) {
+	entry := FixtureEntry{
+		Key:        key,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		BodySHA256: hashFixtureBody(body),
+	}
+
+	resp, err := ft.base.RoundTrip(req)
+	if err != nil {
+		entry.RecordedErr = err.Error()
+		ft.appendEntry(entry)
+		return nil, err
+	}
+
+	respBody, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if readErr != nil {
+		return nil, errorwrapper.WrapError(readErr, "failed to read response body for fixture recording")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	entry.StatusCode = resp.StatusCode
+	entry.Headers = resp.Header.Clone()
+	entry.Body = respBody
+	ft.appendEntry(entry)
+
+	return resp, nil
+}
+
+// appendEntry stores the entry in memory and appends it to the fixture file
+func (ft *FixtureTransport) appendEntry(entry FixtureEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		ft.logger.Error().Err(err).Str("url", entry.URL).Msg("Failed to encode fixture entry")
+		return
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	ft.entries[entry.Key] = append(ft.entries[entry.Key], entry)
+
+	file, err := os.OpenFile(ft.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		ft.logger.Error().Err(err).Str("path", ft.path).Msg("Failed to open fixture file")
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		ft.logger.Error().Err(err).Str("path", ft.path).Msg("Failed to write fixture entry")
+	}
+}
+
+// load reads all fixture entries from the fixture file
+func (ft *FixtureTransport) load() error {
+	file, err := os.Open(ft.path)
+	if err != nil {
+		return errorwrapper.WrapError(err, "failed to open fixture file")
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	count := 0
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry FixtureEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return errorwrapper.WrapError(err, fmt.Sprintf("failed to decode fixture entry %d", count+1))
+			}
+			ft.entries[entry.Key] = append(ft.entries[entry.Key], entry)
+			count++
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return errorwrapper.WrapError(readErr, "failed to read fixture file")
+		}
+	}
+
+	ft.logger.Info().Str("path", ft.path).Int("entries", count).Msg("Loaded HTTP fixtures for replay")
+	return nil
+}
+
+// startFixtureRecording truncates the fixture file at path unless this process already records to it
+func startFixtureRecording(path string) error {
+	key := path
+	if absolute, err := filepath.Abs(path); err == nil {
+		key = absolute
+	}
+
+	startedFixtureRecordingsMu.Lock()
+	defer startedFixtureRecordingsMu.Unlock()
+
+	if startedFixtureRecordings[key] {
+		return nil
+	}
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		return errorwrapper.WrapError(err, "failed to truncate fixture file")
+	}
+	startedFixtureRecordings[key] = true
+	return nil
+}
+
+// readAndRestoreBody reads the request body and resets it so the request can still be sent
+func readAndRestoreBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// hashFixtureBody returns the hex SHA-256 of a request body
+func hashFixtureBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,115 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixtureTransport_RecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Echo", r.Method)
+		_, _ = w.Write([]byte("path=" + r.URL.Path + " body=" + string(body)))
+	}))
+
+	fixturePath := filepath.Join(t.TempDir(), "fixtures.jsonl")
+	logger := zerolog.Nop()
+
+	recorder, err := NewFixtureTransport(http.DefaultTransport, FixtureModeRecord, fixturePath, logger)
+	require.NoError(t, err)
+	recordClient := &http.Client{Transport: recorder}
+
+	resp, err := recordClient.Get(server.URL + "/a")
+	require.NoError(t, err)
+	recordedBody, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	resp, err = recordClient.Post(server.URL+"/b", "text/plain", strings.NewReader("payload"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	// Replay must work without the server
+	server.Close()
+
+	replayer, err := NewFixtureTransport(nil, FixtureModeReplay, fixturePath, logger)
+	require.NoError(t, err)
+	replayClient := &http.Client{Transport: replayer}
+
+	resp, err = replayClient.Get(server.URL + "/a")
+	require.NoError(t, err)
+	replayedBody, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "GET", resp.Header.Get("X-Echo"))
+	assert.Equal(t, string(recordedBody), string(replayedBody))
+
+	resp, err = replayClient.Post(server.URL+"/b", "text/plain", strings.NewReader("payload"))
+	require.NoError(t, err)
+	replayedBody, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "path=/b body=payload", string(replayedBody))
+
+	// Same URL with a different body has no recording
+	_, err = replayClient.Post(server.URL+"/b", "text/plain", strings.NewReader("other"))
+	assert.Error(t, err)
+}
+
+func TestNewFixtureTransport_Validation(t *testing.T) {
+	logger := zerolog.Nop()
+
+	_, err := NewFixtureTransport(http.DefaultTransport, FixtureModeRecord, "", logger)
+	assert.Error(t, err)
+
+	_, err = NewFixtureTransport(nil, FixtureModeRecord, filepath.Join(t.TempDir(), "f.jsonl"), logger)
+	assert.Error(t, err)
+
+	_, err = NewFixtureTransport(nil, FixtureModeReplay, filepath.Join(t.TempDir(), "missing.jsonl"), logger)
+	assert.Error(t, err)
+}
+
+func TestFixtureTransport_RecordReplacesEarlierRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("fresh"))
+	}))
+	defer server.Close()
+
+	fixturePath := filepath.Join(t.TempDir(), "fixtures.jsonl")
+	stale := `{"key":"GET https://stale.example/ x","method":"GET","url":"https://stale.example/","status_code":200}` + "\n"
+	require.NoError(t, os.WriteFile(fixturePath, []byte(stale), 0644))
+
+	countEntries := func() int {
+		data, err := os.ReadFile(fixturePath)
+		require.NoError(t, err)
+		return strings.Count(string(data), "\n")
+	}
+	get := func(transport http.RoundTripper, path string) {
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	recorder, err := NewFixtureTransport(http.DefaultTransport, FixtureModeRecord, fixturePath, zerolog.Nop())
+	require.NoError(t, err)
+	assert.Equal(t, 0, countEntries(), "the first recorder of the process truncates the file")
+	get(recorder, "/a")
+
+	// A second crawler of the same process, e.g. a later scan in automated mode, keeps recording to the same file
+	second, err := NewFixtureTransport(http.DefaultTransport, FixtureModeRecord, fixturePath, zerolog.Nop())
+	require.NoError(t, err)
+	get(second, "/b")
+	assert.Equal(t, 2, countEntries())
+
+	replayer, err := NewFixtureTransport(nil, FixtureModeReplay, fixturePath, zerolog.Nop())
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: replayer}).Get("https://stale.example/")
+	assert.Error(t, err, "entries of the earlier run are gone")
+}
@@ -0,0 +1,215 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+)
+
+const (
+	// HARSourceCrawler marks entries recorded from crawler traffic
+	HARSourceCrawler = "crawler"
+	// HARSourceProber marks entries recorded from httpx probe results
+	HARSourceProber = "prober"
+
+	harVersion        = "1.2"
+	harCreatorName    = "monsterinc"
+	harCreatorVersion = "dev"
+)
+
+// HAR is the root object of an HTTP Archive 1.2 file
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog lists the recorded entries
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+	Comment string     `json:"comment,omitempty"`
+}
+
+// HARCreator names the application that wrote the archive
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is one request/response exchange. Source is a custom field ("crawler" or "prober").
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"` // Total milliseconds
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Source          string      `json:"_source,omitempty"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+// HARRequest describes the request of an entry
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARResponse describes the response of an entry; Status is 0 when the request failed
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARContent describes the response body; bodies themselves are not recorded
+type HARContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+// HARNameValue is a header, cookie or query parameter
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARTimings breaks Time down into phases in milliseconds; -1 marks a phase that did not apply
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// HARRecorder collects entries from any number of goroutines and writes them as HAR files
+type HARRecorder struct {
+	mu      sync.Mutex
+	entries []HAREntry
+}
+
+// NewHARRecorder creates an empty HARRecorder
+func NewHARRecorder() *HARRecorder {
+	return &HARRecorder{}
+}
+
+// Add records an entry
+func (hr *HARRecorder) Add(entry HAREntry) {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	hr.entries = append(hr.entries, entry)
+}
+
+// Len returns the number of recorded entries
+func (hr *HARRecorder) Len() int {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	return len(hr.entries)
+}
+
+// Reset drops all recorded entries
+func (hr *HARRecorder) Reset() {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	hr.entries = nil
+}
+
+// Entries returns the recorded entries ordered by start time
+func (hr *HARRecorder) Entries() []HAREntry {
+	hr.mu.Lock()
+	entries := make([]HAREntry, len(hr.entries))
+	copy(entries, hr.entries)
+	hr.mu.Unlock()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+	return entries
+}
+
+// WriteFile writes every recorded entry to one HAR file, replacing it if it exists
+func (hr *HARRecorder) WriteFile(path, comment string) error {
+	return writeHARFile(path, comment, hr.Entries())
+}
+
+// WriteFilesPerHost writes one "<host>.har" file per request host into dir and returns the paths written
+func (hr *HARRecorder) WriteFilesPerHost(dir, comment string) ([]string, error) {
+	byHost := make(map[string][]HAREntry)
+	for _, entry := range hr.Entries() {
+		host := "unknown"
+		if u, err := url.Parse(entry.Request.URL); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		byHost[host] = append(byHost[host], entry)
+	}
+
+	hosts := make([]string, 0, len(byHost))
+	for host := range byHost {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	paths := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		path := filepath.Join(dir, harFileName(host))
+		if err := writeHARFile(path, comment, byHost[host]); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// harFileName turns a host (possibly with a port) into a safe file name
+func harFileName(host string) string {
+	replacer := strings.NewReplacer(":", "_", "/", "_", "\\", "_")
+	return replacer.Replace(host) + ".har"
+}
+
+// writeHARFile encodes entries as a HAR document at path
+func writeHARFile(path, comment string, entries []HAREntry) error {
+	if entries == nil {
+		entries = []HAREntry{}
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errorwrapper.WrapError(err, "failed to create HAR directory: "+dir)
+		}
+	}
+
+	data, err := json.MarshalIndent(HAR{Log: HARLog{
+		Version: harVersion,
+		Creator: HARCreator{Name: harCreatorName, Version: harCreatorVersion},
+		Entries: entries,
+		Comment: comment,
+	}}, "", "  ")
+	if err != nil {
+		return errorwrapper.WrapError(err, "failed to encode HAR file")
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errorwrapper.WrapError(err, "failed to write HAR file: "+path)
+	}
+	return nil
+}
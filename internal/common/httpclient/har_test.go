@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHARTransport_RecordsExchangeWithRedactedCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	recorder := NewHARRecorder()
+	client := &http.Client{Transport: NewHARTransport(http.DefaultTransport, recorder, HARSourceCrawler)}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/path?q=a%20b&x=1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("X-Trace", "abc")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 1)
+	entry := entries[0]
+
+	assert.Equal(t, HARSourceCrawler, entry.Source)
+	assert.Equal(t, http.MethodGet, entry.Request.Method)
+	assert.Equal(t, server.URL+"/path?q=a%20b&x=1", entry.Request.URL)
+	assert.Equal(t, []HARNameValue{{Name: "q", Value: "a b"}, {Name: "x", Value: "1"}}, entry.Request.QueryString)
+	assert.Contains(t, entry.Request.Headers, HARNameValue{Name: "Authorization", Value: harRedactedValue})
+	assert.Contains(t, entry.Request.Headers, HARNameValue{Name: "X-Trace", Value: "abc"})
+
+	assert.Equal(t, http.StatusTeapot, entry.Response.Status)
+	assert.Equal(t, "text/html; charset=utf-8", entry.Response.Content.MimeType)
+	assert.Equal(t, int64(13), entry.Response.Content.Size)
+	assert.Contains(t, entry.Response.Headers, HARNameValue{Name: "Set-Cookie", Value: harRedactedValue})
+	assert.Equal(t, "127.0.0.1", entry.ServerIPAddress)
+	assert.GreaterOrEqual(t, entry.Time, 0.0)
+	assert.GreaterOrEqual(t, entry.Timings.Connect, 0.0)
+	assert.Equal(t, -1.0, entry.Timings.SSL)
+}
+
+func TestHARTransport_RecordsFailedRequest(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	serverURL := server.URL
+	server.Close()
+
+	recorder := NewHARRecorder()
+	client := &http.Client{Transport: NewHARTransport(http.DefaultTransport, recorder, HARSourceCrawler)}
+
+	_, err := client.Get(serverURL)
+	require.Error(t, err)
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, 0, entries[0].Response.Status)
+	assert.NotEmpty(t, entries[0].Error)
+}
+
+func TestHARRecorder_WriteFilesPerHost(t *testing.T) {
+	recorder := NewHARRecorder()
+	recorder.Add(HAREntry{Request: HARRequest{Method: "GET", URL: "https://a.example.com/1"}})
+	recorder.Add(HAREntry{Request: HARRequest{Method: "GET", URL: "https://b.example.com:8443/2"}})
+	recorder.Add(HAREntry{Request: HARRequest{Method: "GET", URL: "https://a.example.com/3"}})
+
+	dir := t.TempDir()
+	paths, err := recorder.WriteFilesPerHost(dir, "session-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a.example.com.har"),
+		filepath.Join(dir, "b.example.com_8443.har"),
+	}, paths)
+
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	var har HAR
+	require.NoError(t, json.Unmarshal(data, &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, "session-1", har.Log.Comment)
+	assert.Len(t, har.Log.Entries, 2)
+
+	singlePath := filepath.Join(dir, "all", "session.har")
+	require.NoError(t, recorder.WriteFile(singlePath, ""))
+	data, err = os.ReadFile(singlePath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &har))
+	assert.Len(t, har.Log.Entries, 3)
+
+	recorder.Reset()
+	assert.Equal(t, 0, recorder.Len())
+}
@@ -0,0 +1,225 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const harRedactedValue = "[REDACTED]"
+
+// harRedactedHeaders carry credentials or session state; their values never reach a HAR file
+var harRedactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// HARTransport wraps an http.RoundTripper and records every exchange into a HARRecorder.
+// Headers, status and timings are recorded; bodies are not read, so the wrapped response is untouched.
+type HARTransport struct {
+	base     http.RoundTripper
+	recorder *HARRecorder
+	source   string
+}
+
+// NewHARTransport creates a HARTransport tagging its entries with source (e.g. HARSourceCrawler)
+func NewHARTransport(base http.RoundTripper, recorder *HARRecorder, source string) *HARTransport {
+	return &HARTransport{base: base, recorder: recorder, source: source}
+}
+
+// RoundTrip implements http.RoundTripper
+func (ht *HARTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &harTrace{}
+	tracedReq := req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+	started := time.Now()
+	resp, err := ht.base.RoundTrip(tracedReq)
+	finished := time.Now()
+
+	entry := HAREntry{
+		StartedDateTime: started,
+		Time:            harMillis(finished.Sub(started)),
+		Request:         harRequest(req),
+		Response:        HARResponse{Cookies: []HARNameValue{}, Headers: []HARNameValue{}},
+		Timings:         trace.timings(),
+		ServerIPAddress: trace.serverIP(),
+		Source:          ht.source,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Response = harResponse(resp)
+	}
+	ht.recorder.Add(entry)
+
+	return resp, err
+}
+
+// harRequest describes req without reading its body
+func harRequest(req *http.Request) HARRequest {
+	return HARRequest{
+		Method:      req.Method,
+		URL:         req.URL.Redacted(),
+		HTTPVersion: harProto(req.Proto),
+		Cookies:     []HARNameValue{},
+		Headers:     HARHeaders(req.Header),
+		QueryString: HARQueryString(req.URL.RawQuery),
+		HeadersSize: -1,
+		BodySize:    req.ContentLength,
+	}
+}
+
+// harResponse describes resp without reading its body; sizes come from Content-Length (-1 when unknown)
+func harResponse(resp *http.Response) HARResponse {
+	return HARResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: harProto(resp.Proto),
+		Cookies:     []HARNameValue{},
+		Headers:     HARHeaders(resp.Header),
+		Content:     HARContent{Size: max(resp.ContentLength, 0), MimeType: resp.Header.Get("Content-Type")},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    resp.ContentLength,
+	}
+}
+
+// HARHeaders converts headers to sorted HAR name/value pairs with credential headers redacted
+func HARHeaders(header http.Header) []HARNameValue {
+	pairs := make([]HARNameValue, 0, len(header))
+	for name, values := range header {
+		redacted := harRedactedHeaders[http.CanonicalHeaderKey(name)]
+		for _, value := range values {
+			if redacted {
+				value = harRedactedValue
+			}
+			pairs = append(pairs, HARNameValue{Name: name, Value: value})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+	return pairs
+}
+
+// HARQueryString converts a raw query to HAR name/value pairs in their original order
+func HARQueryString(rawQuery string) []HARNameValue {
+	pairs := []HARNameValue{}
+	for rawQuery != "" {
+		var pair string
+		pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		pairs = append(pairs, HARNameValue{Name: name, Value: value})
+	}
+	return pairs
+}
+
+// harProto defaults an empty protocol to HTTP/1.1
+func harProto(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+// harMillis converts a duration to fractional milliseconds
+func harMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// harTrace collects connection timings of one request. Callbacks may fire from several goroutines
+// (e.g. dialing IPv4 and IPv6 in parallel), so every field is guarded.
+type harTrace struct {
+	mu                    sync.Mutex
+	dnsStart, dnsDone     time.Time
+	connectStart          time.Time
+	connectDone           time.Time
+	tlsStart, tlsDone     time.Time
+	gotConn, wroteRequest time.Time
+	firstByte             time.Time
+	remoteAddr            net.Addr
+}
+
+func (t *harTrace) set(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if field.IsZero() {
+		*field = time.Now()
+	}
+}
+
+func (t *harTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { t.set(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { t.set(&t.dnsDone) },
+		ConnectStart:      func(string, string) { t.set(&t.connectStart) },
+		ConnectDone:       func(string, string, error) { t.set(&t.connectDone) },
+		TLSHandshakeStart: func() { t.set(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.set(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.set(&t.gotConn)
+			t.mu.Lock()
+			if info.Conn != nil {
+				t.remoteAddr = info.Conn.RemoteAddr()
+			}
+			t.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.set(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.set(&t.firstByte) },
+	}
+}
+
+// timings maps the collected timestamps to HAR phases; phases that did not happen
+// (a reused connection has no DNS, connect or TLS phase) are -1
+func (t *harTrace) timings() HARTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return HARTimings{
+		Blocked: -1,
+		DNS:     harPhase(t.dnsStart, t.dnsDone),
+		Connect: harPhase(t.connectStart, t.connectDone),
+		SSL:     harPhase(t.tlsStart, t.tlsDone),
+		Send:    max(harPhase(t.gotConn, t.wroteRequest), 0),
+		Wait:    max(harPhase(t.wroteRequest, t.firstByte), 0),
+		Receive: 0,
+	}
+}
+
+// serverIP returns the IP address of the connection the request was sent on
+func (t *harTrace) serverIP() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.remoteAddr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(t.remoteAddr.String())
+	if err != nil {
+		return t.remoteAddr.String()
+	}
+	return host
+}
+
+// harPhase returns the milliseconds between start and end, or -1 if either was not observed
+func harPhase(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return -1
+	}
+	return harMillis(end.Sub(start))
+}
@@ -64,7 +64,13 @@ func NewHTTPClient(config HTTPClientConfig, logger zerolog.Logger) (*HTTPClient,
 			return nil, errorwrapper.WrapError(err, "failed to parse proxy URL")
 		}
 		transport.Proxy = http.ProxyURL(proxyURL)
-		logger.Info().Str("proxy", config.Proxy).Msg("HTTP client configured with proxy")
+		logger.Info().Str("proxy", proxyURL.Redacted()).Msg("HTTP client configured with proxy")
+	} else {
+		proxyFunc, err := config.ProxyConfig.ProxyFunc()
+		if err != nil {
+			return nil, errorwrapper.WrapError(err, "failed to configure proxy")
+		}
+		transport.Proxy = proxyFunc
 	}
 
 	client := &http.Client{
@@ -213,7 +219,7 @@ func (c *HTTPClient) sendDiscordJSON(ctx context.Context, webhookURL string, pay
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Discord webhook returned status %d: %s", resp.StatusCode, string(resp.Body))
+		return &errorwrapper.HTTPError{StatusCode: resp.StatusCode, Message: "Discord webhook rejected the request: " + string(resp.Body)}
 	}
 
 	c.logger.Debug().Int("status_code", resp.StatusCode).Msg("Discord notification sent successfully")
@@ -412,7 +418,7 @@ func (c *HTTPClient) sendDiscordMultipartInternal(ctx context.Context, webhookUR
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Discord webhook returned status %d: %s", resp.StatusCode, string(resp.Body))
+		return &errorwrapper.HTTPError{StatusCode: resp.StatusCode, Message: "Discord webhook rejected the request: " + string(resp.Body)}
 	}
 
 	fileInfo, _ := os.Stat(filePath)
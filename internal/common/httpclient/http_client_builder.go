@@ -26,6 +26,12 @@ func (b *HTTPClientBuilder) WithTimeout(timeout time.Duration) *HTTPClientBuilde
 	return b
 }
 
+// WithProxyConfig sets the shared proxy settings
+func (b *HTTPClientBuilder) WithProxyConfig(proxyConfig ProxyConfig) *HTTPClientBuilder {
+	b.config.ProxyConfig = proxyConfig
+	return b
+}
+
 // WithInsecureSkipVerify sets whether to skip TLS verification
 func (b *HTTPClientBuilder) WithInsecureSkipVerify(skip bool) *HTTPClientBuilder {
 	b.config.InsecureSkipVerify = skip
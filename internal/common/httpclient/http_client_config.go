@@ -10,7 +10,8 @@ type HTTPClientConfig struct {
 	InsecureSkipVerify    bool              // Skip TLS verification
 	FollowRedirects       bool              // Whether to follow redirects
 	MaxRedirects          int               // Maximum number of redirects to follow
-	Proxy                 string            // Proxy URL (HTTP/SOCKS)
+	Proxy                 string            // Proxy URL (HTTP/SOCKS); takes precedence over ProxyConfig
+	ProxyConfig           ProxyConfig       // Shared proxy settings with per-scheme URLs, no-proxy list and env fallback
 	CustomHeaders         map[string]string // Custom headers to add to all requests
 	MaxIdleConns          int               // Maximum idle connections
 	MaxIdleConnsPerHost   int               // Maximum idle connections per host
@@ -8,7 +8,8 @@ import (
 
 // HTTPClientFactory provides methods to create common HTTP client configurations
 type HTTPClientFactory struct {
-	logger zerolog.Logger
+	logger      zerolog.Logger
+	proxyConfig ProxyConfig
 }
 
 // NewHTTPClientFactory creates a new HTTP client factory
@@ -16,9 +17,16 @@ func NewHTTPClientFactory(logger zerolog.Logger) *HTTPClientFactory {
 	return &HTTPClientFactory{logger: logger}
 }
 
+// WithProxyConfig sets the proxy settings applied to every client created by the factory
+func (f *HTTPClientFactory) WithProxyConfig(proxyConfig ProxyConfig) *HTTPClientFactory {
+	f.proxyConfig = proxyConfig
+	return f
+}
+
 // CreateDiscordClient creates an HTTP client optimized for Discord webhook calls
 func (f *HTTPClientFactory) CreateDiscordClient(timeout time.Duration) (*HTTPClient, error) {
 	return NewHTTPClientBuilder(f.logger).
+		WithProxyConfig(f.proxyConfig).
 		WithTimeout(timeout).
 		WithFollowRedirects(true).
 		WithMaxRedirects(3).
@@ -29,6 +37,7 @@ func (f *HTTPClientFactory) CreateDiscordClient(timeout time.Duration) (*HTTPCli
 // CreateMonitorClient creates an HTTP client optimized for file monitoring
 func (f *HTTPClientFactory) CreateMonitorClient(timeout time.Duration, insecureSkipVerify bool) (*HTTPClient, error) {
 	return NewHTTPClientBuilder(f.logger).
+		WithProxyConfig(f.proxyConfig).
 		WithTimeout(timeout).
 		WithInsecureSkipVerify(insecureSkipVerify).
 		WithFollowRedirects(true).
@@ -41,6 +50,7 @@ func (f *HTTPClientFactory) CreateMonitorClient(timeout time.Duration, insecureS
 // CreateBasicClient creates a basic HTTP client with minimal configuration
 func (f *HTTPClientFactory) CreateBasicClient(timeout time.Duration) (*HTTPClient, error) {
 	return NewHTTPClientBuilder(f.logger).
+		WithProxyConfig(f.proxyConfig).
 		WithTimeout(timeout).
 		WithHTTP2(true).
 		Build()
@@ -0,0 +1,113 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// InFlightLimiter bounds the number of outbound requests in flight across every client that shares it.
+// Per-client concurrency settings still apply underneath, as sub-caps of the shared limit.
+// It is safe for concurrent use.
+type InFlightLimiter struct {
+	slots chan struct{}
+}
+
+// NewInFlightLimiter creates a limiter allowing maxInFlight requests at once, or returns nil when
+// maxInFlight is 0 or less (no limit)
+func NewInFlightLimiter(maxInFlight int) *InFlightLimiter {
+	if maxInFlight <= 0 {
+		return nil
+	}
+	return &InFlightLimiter{slots: make(chan struct{}, maxInFlight)}
+}
+
+// Max returns the number of requests allowed in flight at once
+func (l *InFlightLimiter) Max() int {
+	return cap(l.slots)
+}
+
+// InFlight returns the number of slots currently held
+func (l *InFlightLimiter) InFlight() int {
+	return len(l.slots)
+}
+
+// Acquire waits for a free slot, or returns the context's error if it is done first
+func (l *InFlightLimiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AcquireUpTo waits for one slot, then takes as many more free slots as are available, up to n in total.
+// It is meant for clients that run a pool of workers sized once per run, such as httpx: the run gets
+// one worker per slot instead of waiting for n slots to be free at once. The caller releases the
+// returned number of slots.
+func (l *InFlightLimiter) AcquireUpTo(ctx context.Context, n int) (int, error) {
+	if err := l.Acquire(ctx); err != nil {
+		return 0, err
+	}
+
+	acquired := 1
+	for acquired < n {
+		select {
+		case l.slots <- struct{}{}:
+			acquired++
+		default:
+			return acquired, nil
+		}
+	}
+	return acquired, nil
+}
+
+// Release frees n slots
+func (l *InFlightLimiter) Release(n int) {
+	for range n {
+		<-l.slots
+	}
+}
+
+// InFlightTransport holds a slot of an InFlightLimiter for each request, from before it is sent until
+// its response body is closed
+type InFlightTransport struct {
+	base    http.RoundTripper
+	limiter *InFlightLimiter
+}
+
+// NewInFlightTransport wraps base so its requests count against limiter
+func NewInFlightTransport(base http.RoundTripper, limiter *InFlightLimiter) *InFlightTransport {
+	return &InFlightTransport{base: base, limiter: limiter}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *InFlightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Acquire(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		t.limiter.Release(1)
+		return resp, err
+	}
+	resp.Body = &inFlightBody{ReadCloser: resp.Body, release: func() { t.limiter.Release(1) }}
+	return resp, nil
+}
+
+// inFlightBody releases its request's slot once, when the body is closed
+type inFlightBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close closes the body and releases the slot
+func (b *inFlightBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,77 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInFlightLimiter_DisabledIsNil(t *testing.T) {
+	assert.Nil(t, NewInFlightLimiter(0))
+	assert.Nil(t, NewInFlightLimiter(-1))
+	assert.Equal(t, 3, NewInFlightLimiter(3).Max())
+}
+
+func TestInFlightLimiter_AcquireUpTo(t *testing.T) {
+	limiter := NewInFlightLimiter(4)
+	require.NoError(t, limiter.Acquire(context.Background()))
+
+	slots, err := limiter.AcquireUpTo(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, slots, "takes only the free slots")
+	assert.Equal(t, 4, limiter.InFlight())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.AcquireUpTo(ctx, 2)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "waits for at least one slot")
+
+	limiter.Release(slots + 1)
+	assert.Equal(t, 0, limiter.InFlight())
+}
+
+func TestInFlightTransport_CapsConcurrentRequests(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		inFlight.Add(-1)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	limiter := NewInFlightLimiter(2)
+	client := &http.Client{Transport: NewInFlightTransport(&http.Transport{}, limiter)}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+	assert.Equal(t, 0, limiter.InFlight(), "every slot is released when its body is closed")
+}
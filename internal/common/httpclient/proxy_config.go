@@ -0,0 +1,176 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// SupportedProxySchemes lists the proxy URL schemes accepted by ProxyConfig
+var SupportedProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// ProxyConfig describes the outbound proxy shared by every HTTP transport (crawler, httpx, notifications, event sinks)
+type ProxyConfig struct {
+	// URL is the default proxy for all requests, e.g. http://127.0.0.1:8080 or socks5://127.0.0.1:1080
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// HTTPURL overrides URL for plain http:// requests
+	HTTPURL string `json:"http_url,omitempty" yaml:"http_url,omitempty"`
+	// HTTPSURL overrides URL for https:// requests
+	HTTPSURL string `json:"https_url,omitempty" yaml:"https_url,omitempty"`
+	// Username and Password are applied to proxy URLs that carry no credentials of their own
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	// NoProxy lists hosts, domains (".example.com" for subdomains only), IPs or CIDRs that bypass the proxy
+	NoProxy []string `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty"`
+	// IgnoreEnvironment disables the HTTP_PROXY/HTTPS_PROXY/NO_PROXY fallback used when no proxy URL is configured
+	IgnoreEnvironment bool `json:"ignore_environment,omitempty" yaml:"ignore_environment,omitempty"`
+	// Pool rotates scan traffic over several proxies instead of URL/HTTPURL/HTTPSURL
+	Pool ProxyPoolConfig `json:"pool,omitempty" yaml:"pool,omitempty"`
+}
+
+// IsConfigured reports whether an explicit proxy URL is set
+func (pc ProxyConfig) IsConfigured() bool {
+	return pc.URL != "" || pc.HTTPURL != "" || pc.HTTPSURL != ""
+}
+
+// Validate checks that every configured proxy URL is well formed and uses a supported scheme
+func (pc ProxyConfig) Validate() error {
+	fields := []struct{ name, value string }{
+		{"url", pc.URL},
+		{"http_url", pc.HTTPURL},
+		{"https_url", pc.HTTPSURL},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if _, err := parseProxyURL(field.value); err != nil {
+			return errorwrapper.NewValidationError("proxy_config."+field.name, field.value, err.Error())
+		}
+	}
+
+	for _, rawURL := range pc.Pool.URLs {
+		if _, err := parseProxyURL(rawURL); err != nil {
+			return errorwrapper.NewValidationError("proxy_config.pool.urls", rawURL, err.Error())
+		}
+	}
+	switch pc.Pool.Rotation {
+	case "", ProxyRotationPerHost, ProxyRotationPerRequest:
+	default:
+		return errorwrapper.NewValidationError("proxy_config.pool.rotation", pc.Pool.Rotation,
+			"must be "+ProxyRotationPerHost+" or "+ProxyRotationPerRequest)
+	}
+	return nil
+}
+
+// ProxyFunc returns a function suitable for http.Transport.Proxy. Explicitly configured URLs take
+// precedence; otherwise the standard proxy environment variables are honoured unless IgnoreEnvironment is set.
+// A nil function means requests are sent directly.
+func (pc ProxyConfig) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	resolved, err := pc.resolve()
+	if err != nil {
+		return nil, err
+	}
+	if resolved == nil {
+		return nil, nil
+	}
+
+	proxyForURL := resolved.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
+	}, nil
+}
+
+// SingleURL returns one proxy URL for clients that cannot select a proxy per request, such as httpx.
+// The HTTPS proxy is preferred since most targets are served over TLS. An empty string means no proxy.
+func (pc ProxyConfig) SingleURL() (string, error) {
+	resolved, err := pc.resolve()
+	if err != nil || resolved == nil {
+		return "", err
+	}
+	if resolved.HTTPSProxy != "" {
+		return resolved.HTTPSProxy, nil
+	}
+	return resolved.HTTPProxy, nil
+}
+
+// resolve builds the effective httpproxy configuration, applying per-scheme overrides, credentials and the environment fallback
+func (pc ProxyConfig) resolve() (*httpproxy.Config, error) {
+	noProxy := strings.Join(pc.NoProxy, ",")
+
+	if !pc.IsConfigured() {
+		if pc.IgnoreEnvironment {
+			return nil, nil
+		}
+		env := httpproxy.FromEnvironment()
+		if env.HTTPProxy == "" && env.HTTPSProxy == "" {
+			return nil, nil
+		}
+		if noProxy != "" {
+			env.NoProxy = strings.Trim(env.NoProxy+","+noProxy, ",")
+		}
+		return env, nil
+	}
+
+	httpProxy, err := pc.withCredentials(firstNonEmpty(pc.HTTPURL, pc.URL))
+	if err != nil {
+		return nil, err
+	}
+	httpsProxy, err := pc.withCredentials(firstNonEmpty(pc.HTTPSURL, pc.URL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpproxy.Config{
+		HTTPProxy:  httpProxy,
+		HTTPSProxy: httpsProxy,
+		NoProxy:    noProxy,
+	}, nil
+}
+
+// withCredentials parses rawURL and injects the configured username and password when the URL has none
+func (pc ProxyConfig) withCredentials(rawURL string) (string, error) {
+	if rawURL == "" {
+		return "", nil
+	}
+
+	proxyURL, err := parseProxyURL(rawURL)
+	if err != nil {
+		return "", errorwrapper.WrapError(err, "invalid proxy URL")
+	}
+
+	if proxyURL.User == nil && pc.Username != "" {
+		proxyURL.User = url.UserPassword(pc.Username, pc.Password)
+	}
+	return proxyURL.String(), nil
+}
+
+// parseProxyURL parses a proxy URL and checks its scheme and host
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(SupportedProxySchemes, strings.ToLower(proxyURL.Scheme)) {
+		return nil, errorwrapper.NewError("unsupported proxy scheme %q (expected one of %s)", proxyURL.Scheme, strings.Join(SupportedProxySchemes, ", "))
+	}
+	if proxyURL.Host == "" {
+		return nil, errorwrapper.NewError("proxy URL %q has no host", rawURL)
+	}
+	return proxyURL, nil
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
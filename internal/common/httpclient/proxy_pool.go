@@ -0,0 +1,303 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Proxy rotation strategies of a ProxyPool
+const (
+	ProxyRotationPerHost    = "per_host"    // Each target host sticks to one proxy until that proxy is dropped
+	ProxyRotationPerRequest = "per_request" // Every request takes the next healthy proxy
+)
+
+// Defaults applied to unset ProxyPoolConfig fields
+const (
+	DefaultProxyPoolMaxFailures  = 3
+	DefaultProxyPoolCooldownSecs = 300
+)
+
+// ErrNoHealthyProxy is returned when every pooled proxy has been dropped and direct fallback is disabled
+var ErrNoHealthyProxy = errors.New("no healthy proxy left in the proxy pool")
+
+// ProxyPoolConfig describes a pool of proxies rotated across scan traffic (crawler and httpx).
+// Notification and event sink clients keep using the single proxy of ProxyConfig.
+type ProxyPoolConfig struct {
+	// URLs of the pooled proxies, e.g. socks5://10.0.0.1:1080; credentials default to ProxyConfig's
+	URLs []string `json:"urls,omitempty" yaml:"urls,omitempty"`
+	// Rotation is per_host (default) or per_request
+	Rotation string `json:"rotation,omitempty" yaml:"rotation,omitempty"`
+	// MaxFailures is the number of consecutive failed requests after which a proxy is dropped
+	MaxFailures int `json:"max_failures,omitempty" yaml:"max_failures,omitempty"`
+	// CooldownSecs is how long a dropped proxy stays out of rotation before it is tried again
+	CooldownSecs int `json:"cooldown_secs,omitempty" yaml:"cooldown_secs,omitempty"`
+	// FallbackDirect sends requests without a proxy while every proxy is dropped, instead of failing them
+	FallbackDirect bool `json:"fallback_direct,omitempty" yaml:"fallback_direct,omitempty"`
+}
+
+// pooledProxy is one proxy of the pool with its health
+type pooledProxy struct {
+	url       *url.URL
+	failures  int       // Consecutive failures; kept at the limit after a cooldown so one more failure drops it again
+	downUntil time.Time // Zero while healthy
+}
+
+// ProxyPool rotates requests over several proxies and drops proxies that keep failing for a cooldown period.
+// It is safe for concurrent use.
+type ProxyPool struct {
+	mu              sync.Mutex
+	proxies         []*pooledProxy
+	rotation        string
+	maxFailures     int
+	cooldown        time.Duration
+	fallbackDirect  bool
+	next            int
+	hostAssignments map[string]*pooledProxy
+	bypass          func(*url.URL) (*url.URL, error) // Returns nil for targets listed in no_proxy
+	now             func() time.Time
+	logger          zerolog.Logger
+}
+
+// NewProxyPool creates the pool described by pc.Pool, or returns nil when no pool URLs are configured
+func NewProxyPool(pc ProxyConfig, logger zerolog.Logger) (*ProxyPool, error) {
+	cfg := pc.Pool
+	if len(cfg.URLs) == 0 {
+		return nil, nil
+	}
+
+	pool := &ProxyPool{
+		rotation:        cfg.Rotation,
+		maxFailures:     cfg.MaxFailures,
+		cooldown:        time.Duration(cfg.CooldownSecs) * time.Second,
+		fallbackDirect:  cfg.FallbackDirect,
+		hostAssignments: make(map[string]*pooledProxy),
+		now:             time.Now,
+		logger:          logger.With().Str("component", "ProxyPool").Logger(),
+	}
+	if pool.rotation == "" {
+		pool.rotation = ProxyRotationPerHost
+	}
+	if pool.maxFailures <= 0 {
+		pool.maxFailures = DefaultProxyPoolMaxFailures
+	}
+	if pool.cooldown <= 0 {
+		pool.cooldown = DefaultProxyPoolCooldownSecs * time.Second
+	}
+
+	for _, rawURL := range cfg.URLs {
+		withCredentials, err := pc.withCredentials(rawURL)
+		if err != nil {
+			return nil, errorwrapper.WrapError(err, "invalid proxy pool URL")
+		}
+		proxyURL, err := url.Parse(withCredentials)
+		if err != nil {
+			return nil, errorwrapper.WrapError(err, "invalid proxy pool URL")
+		}
+		pool.proxies = append(pool.proxies, &pooledProxy{url: proxyURL})
+	}
+
+	if len(pc.NoProxy) > 0 {
+		// Any proxy URL works here: only whether the target bypasses it matters
+		pool.bypass = (&httpproxy.Config{
+			HTTPProxy:  pool.proxies[0].url.String(),
+			HTTPSProxy: pool.proxies[0].url.String(),
+			NoProxy:    strings.Join(pc.NoProxy, ","),
+		}).ProxyFunc()
+	}
+
+	return pool, nil
+}
+
+// Size returns the number of pooled proxies
+func (pp *ProxyPool) Size() int {
+	return len(pp.proxies)
+}
+
+// Healthy returns the number of proxies currently in rotation
+func (pp *ProxyPool) Healthy() int {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	now := pp.now()
+	healthy := 0
+	for _, proxy := range pp.proxies {
+		if proxy.isHealthy(now) {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// Select returns the proxy for a request to target according to the rotation strategy. A nil URL means
+// the request goes directly: the target is in no_proxy, or every proxy is dropped and fallback_direct is set.
+func (pp *ProxyPool) Select(target *url.URL) (*url.URL, error) {
+	if pp.bypass != nil {
+		if proxyURL, err := pp.bypass(target); err == nil && proxyURL == nil {
+			return nil, nil
+		}
+	}
+
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	now := pp.now()
+	host := strings.ToLower(target.Host)
+	if pp.rotation == ProxyRotationPerHost {
+		if proxy := pp.hostAssignments[host]; proxy != nil && proxy.isHealthy(now) {
+			return proxy.url, nil
+		}
+	}
+
+	proxy := pp.nextHealthy(now)
+	if proxy == nil {
+		if pp.fallbackDirect {
+			return nil, nil
+		}
+		return nil, ErrNoHealthyProxy
+	}
+	if pp.rotation == ProxyRotationPerHost {
+		pp.hostAssignments[host] = proxy
+	}
+	return proxy.url, nil
+}
+
+// Next returns the next healthy proxy in round-robin order, for clients that use one proxy per run
+// such as httpx. A nil URL means the run goes directly because every proxy is dropped and fallback_direct is set.
+func (pp *ProxyPool) Next() (*url.URL, error) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	if proxy := pp.nextHealthy(pp.now()); proxy != nil {
+		return proxy.url, nil
+	}
+	if pp.fallbackDirect {
+		return nil, nil
+	}
+	return nil, ErrNoHealthyProxy
+}
+
+// nextHealthy advances the round-robin cursor to the next healthy proxy; the caller holds pp.mu
+func (pp *ProxyPool) nextHealthy(now time.Time) *pooledProxy {
+	for range pp.proxies {
+		proxy := pp.proxies[pp.next]
+		pp.next = (pp.next + 1) % len(pp.proxies)
+		if proxy.isHealthy(now) {
+			return proxy
+		}
+	}
+	return nil
+}
+
+// RecordSuccess marks a proxy as working, clearing its failure count
+func (pp *ProxyPool) RecordSuccess(proxyURL *url.URL) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	if proxy := pp.find(proxyURL); proxy != nil {
+		proxy.failures = 0
+		proxy.downUntil = time.Time{}
+	}
+}
+
+// RecordFailure counts a failed request through a proxy and drops the proxy for the cooldown period
+// once it reaches max_failures consecutive failures
+func (pp *ProxyPool) RecordFailure(proxyURL *url.URL, cause error) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	proxy := pp.find(proxyURL)
+	if proxy == nil {
+		return
+	}
+
+	now := pp.now()
+	if !proxy.isHealthy(now) {
+		return // Requests already in flight when the proxy was dropped
+	}
+	proxy.failures = min(proxy.failures+1, pp.maxFailures)
+	if proxy.failures < pp.maxFailures {
+		return
+	}
+
+	proxy.downUntil = now.Add(pp.cooldown)
+	healthy := 0
+	for _, p := range pp.proxies {
+		if p.isHealthy(now) {
+			healthy++
+		}
+	}
+	pp.logger.Warn().
+		Err(cause).
+		Str("proxy", proxy.url.Redacted()).
+		Dur("cooldown", pp.cooldown).
+		Int("healthy_proxies", healthy).
+		Msg("Proxy dropped from the pool after repeated failures")
+}
+
+// find returns the pooled proxy with the given URL; the caller holds pp.mu
+func (pp *ProxyPool) find(proxyURL *url.URL) *pooledProxy {
+	for _, proxy := range pp.proxies {
+		if proxy.url == proxyURL || proxy.url.String() == proxyURL.String() {
+			return proxy
+		}
+	}
+	return nil
+}
+
+// isHealthy reports whether the proxy is in rotation at now
+func (p *pooledProxy) isHealthy(now time.Time) bool {
+	return !now.Before(p.downUntil)
+}
+
+// proxyContextKey carries the proxy chosen by ProxyPoolTransport to the wrapped transport's Proxy function
+type proxyContextKey struct{}
+
+// ProxyPoolTransport routes each request through a proxy of the pool and reports the outcome back to it
+type ProxyPoolTransport struct {
+	base *http.Transport
+	pool *ProxyPool
+}
+
+// NewProxyPoolTransport wraps base so requests use the proxies of pool. It replaces base.Proxy, so base
+// must not be shared with clients outside the pool.
+func NewProxyPoolTransport(base *http.Transport, pool *ProxyPool) *ProxyPoolTransport {
+	base.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxyURL, _ := req.Context().Value(proxyContextKey{}).(*url.URL)
+		return proxyURL, nil
+	}
+	return &ProxyPoolTransport{base: base, pool: pool}
+}
+
+// RoundTrip implements http.RoundTripper. Transport errors and 407 responses count as failures of the
+// proxy used; errors caused by the request's own cancellation do not.
+func (t *ProxyPoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxyURL, err := t.pool.Select(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	resp, err := t.base.RoundTrip(req.WithContext(context.WithValue(req.Context(), proxyContextKey{}, proxyURL)))
+	switch {
+	case err != nil:
+		if req.Context().Err() == nil {
+			t.pool.RecordFailure(proxyURL, err)
+		}
+	case resp.StatusCode == http.StatusProxyAuthRequired:
+		t.pool.RecordFailure(proxyURL, errorwrapper.NewError("proxy answered %d", resp.StatusCode))
+	default:
+		t.pool.RecordSuccess(proxyURL)
+	}
+	return resp, err
+}
@@ -0,0 +1,133 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseURL(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return u
+}
+
+func newTestProxyPool(t *testing.T, pool ProxyPoolConfig, noProxy ...string) *ProxyPool {
+	t.Helper()
+	pp, err := NewProxyPool(ProxyConfig{Pool: pool, NoProxy: noProxy, Username: "user", Password: "secret"}, zerolog.Nop())
+	require.NoError(t, err)
+	require.NotNil(t, pp)
+	return pp
+}
+
+func selectProxy(t *testing.T, pp *ProxyPool, target string) string {
+	t.Helper()
+	proxyURL, err := pp.Select(mustParseURL(t, target))
+	require.NoError(t, err)
+	if proxyURL == nil {
+		return ""
+	}
+	return proxyURL.Host
+}
+
+func TestNewProxyPool_EmptyIsNil(t *testing.T) {
+	pp, err := NewProxyPool(ProxyConfig{URL: "socks5://127.0.0.1:1080"}, zerolog.Nop())
+	require.NoError(t, err)
+	assert.Nil(t, pp)
+
+	_, err = NewProxyPool(ProxyConfig{Pool: ProxyPoolConfig{URLs: []string{"ftp://proxy:21"}}}, zerolog.Nop())
+	assert.Error(t, err)
+}
+
+func TestProxyPool_PerHostRotation(t *testing.T) {
+	pp := newTestProxyPool(t, ProxyPoolConfig{URLs: []string{"socks5://10.0.0.1:1080", "socks5://10.0.0.2:1080"}}, ".internal.example")
+
+	first := selectProxy(t, pp, "https://a.example.com/")
+	second := selectProxy(t, pp, "https://b.example.com/")
+	assert.NotEqual(t, first, second, "new hosts are spread over the pool")
+	assert.Equal(t, first, selectProxy(t, pp, "https://a.example.com/other"), "a host sticks to its proxy")
+	assert.Equal(t, "", selectProxy(t, pp, "https://app.internal.example/"), "no_proxy targets go directly")
+
+	proxyURL, err := pp.Select(mustParseURL(t, "https://a.example.com/"))
+	require.NoError(t, err)
+	assert.Equal(t, "user", proxyURL.User.Username(), "pool URLs get the shared credentials")
+}
+
+func TestProxyPool_PerRequestRotation(t *testing.T) {
+	pp := newTestProxyPool(t, ProxyPoolConfig{URLs: []string{"socks5://10.0.0.1:1080", "socks5://10.0.0.2:1080"}, Rotation: ProxyRotationPerRequest})
+
+	assert.Equal(t, "10.0.0.1:1080", selectProxy(t, pp, "https://a.example.com/"))
+	assert.Equal(t, "10.0.0.2:1080", selectProxy(t, pp, "https://a.example.com/"))
+	assert.Equal(t, "10.0.0.1:1080", selectProxy(t, pp, "https://a.example.com/"))
+}
+
+func TestProxyPool_DropsFailingProxyUntilCooldownEnds(t *testing.T) {
+	pp := newTestProxyPool(t, ProxyPoolConfig{URLs: []string{"socks5://10.0.0.1:1080", "socks5://10.0.0.2:1080"}, MaxFailures: 2, CooldownSecs: 60})
+	now := time.Now()
+	pp.now = func() time.Time { return now }
+
+	assert.Equal(t, "10.0.0.1:1080", selectProxy(t, pp, "https://a.example.com/"))
+	dead := pp.proxies[0].url
+	pp.RecordFailure(dead, errors.New("connection refused"))
+	assert.Equal(t, 2, pp.Healthy(), "one failure is not enough to drop a proxy")
+	pp.RecordFailure(dead, errors.New("connection refused"))
+	assert.Equal(t, 1, pp.Healthy())
+
+	assert.Equal(t, "10.0.0.2:1080", selectProxy(t, pp, "https://a.example.com/"), "hosts move off a dropped proxy")
+
+	now = now.Add(61 * time.Second)
+	assert.Equal(t, 2, pp.Healthy(), "the proxy is tried again after the cooldown")
+	pp.RecordFailure(dead, errors.New("connection refused"))
+	assert.Equal(t, 1, pp.Healthy(), "a proxy back from cooldown is dropped on its next failure")
+}
+
+func TestProxyPool_AllUnhealthy(t *testing.T) {
+	failFast := newTestProxyPool(t, ProxyPoolConfig{URLs: []string{"socks5://10.0.0.1:1080"}, MaxFailures: 1})
+	failFast.RecordFailure(failFast.proxies[0].url, errors.New("timeout"))
+
+	_, err := failFast.Select(mustParseURL(t, "https://a.example.com/"))
+	assert.ErrorIs(t, err, ErrNoHealthyProxy)
+	_, err = failFast.Next()
+	assert.ErrorIs(t, err, ErrNoHealthyProxy)
+
+	fallback := newTestProxyPool(t, ProxyPoolConfig{URLs: []string{"socks5://10.0.0.1:1080"}, MaxFailures: 1, FallbackDirect: true})
+	fallback.RecordFailure(fallback.proxies[0].url, errors.New("timeout"))
+
+	assert.Equal(t, "", selectProxy(t, fallback, "https://a.example.com/"))
+	proxyURL, err := fallback.Next()
+	require.NoError(t, err)
+	assert.Nil(t, proxyURL)
+}
+
+func TestProxyPoolTransport_RecordsProxyHealth(t *testing.T) {
+	// A plain HTTP forward proxy answering every request itself
+	liveProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "via proxy "+r.URL.Host)
+	}))
+	defer liveProxy.Close()
+	deadProxy := httptest.NewServer(http.NotFoundHandler())
+	deadProxy.Close()
+
+	pp := newTestProxyPool(t, ProxyPoolConfig{URLs: []string{deadProxy.URL, liveProxy.URL}, Rotation: ProxyRotationPerRequest, MaxFailures: 1})
+	client := &http.Client{Transport: NewProxyPoolTransport(&http.Transport{}, pp)}
+
+	_, err := client.Get("http://target.example/")
+	require.Error(t, err, "the first request goes through the dead proxy")
+	assert.Equal(t, 1, pp.Healthy())
+
+	resp, err := client.Get("http://target.example/")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "via proxy target.example", string(body))
+}
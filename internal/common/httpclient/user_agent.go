@@ -0,0 +1,75 @@
+package httpclient
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// User-Agent rotation strategies
+const (
+	UserAgentRotationRandom     = "random"
+	UserAgentRotationRoundRobin = "round-robin"
+)
+
+// UserAgentRotator picks a User-Agent per request from a configured pool
+type UserAgentRotator struct {
+	pool     []string
+	strategy string
+	next     int
+	mu       sync.Mutex
+}
+
+// NewUserAgentRotator creates a rotator. The pool takes precedence over the single userAgent;
+// blank entries are ignored and an empty pool falls back to userAgent.
+func NewUserAgentRotator(userAgent string, userAgents []string, strategy string) *UserAgentRotator {
+	var pool []string
+	for _, ua := range userAgents {
+		if ua = strings.TrimSpace(ua); ua != "" {
+			pool = append(pool, ua)
+		}
+	}
+	if len(pool) == 0 {
+		if ua := strings.TrimSpace(userAgent); ua != "" {
+			pool = []string{ua}
+		}
+	}
+
+	if strategy != UserAgentRotationRoundRobin {
+		strategy = UserAgentRotationRandom
+	}
+
+	return &UserAgentRotator{
+		pool:     pool,
+		strategy: strategy,
+	}
+}
+
+// Next returns the User-Agent for the next request, or an empty string when nothing is
+// configured so the client keeps its default User-Agent
+func (uar *UserAgentRotator) Next() string {
+	if uar == nil || len(uar.pool) == 0 {
+		return ""
+	}
+	if len(uar.pool) == 1 {
+		return uar.pool[0]
+	}
+
+	if uar.strategy == UserAgentRotationRandom {
+		return uar.pool[rand.Intn(len(uar.pool))]
+	}
+
+	uar.mu.Lock()
+	defer uar.mu.Unlock()
+	ua := uar.pool[uar.next]
+	uar.next = (uar.next + 1) % len(uar.pool)
+	return ua
+}
+
+// PoolSize returns the number of usable User-Agents
+func (uar *UserAgentRotator) PoolSize() int {
+	if uar == nil {
+		return 0
+	}
+	return len(uar.pool)
+}
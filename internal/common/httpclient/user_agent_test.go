@@ -0,0 +1,27 @@
+package httpclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAgentRotator_RoundRobin(t *testing.T) {
+	rotator := NewUserAgentRotator("static-ua", []string{"ua-a", " ", "ua-b"}, UserAgentRotationRoundRobin)
+
+	assert.Equal(t, 2, rotator.PoolSize())
+	assert.Equal(t, []string{"ua-a", "ua-b", "ua-a"}, []string{rotator.Next(), rotator.Next(), rotator.Next()})
+}
+
+func TestUserAgentRotator_Fallbacks(t *testing.T) {
+	// An empty pool falls back to the single User-Agent
+	assert.Equal(t, "static-ua", NewUserAgentRotator("static-ua", []string{"", "  "}, UserAgentRotationRandom).Next())
+
+	// Nothing configured leaves the client's default User-Agent in place
+	assert.Equal(t, "", NewUserAgentRotator("", nil, "").Next())
+
+	random := NewUserAgentRotator("", []string{"ua-a", "ua-b"}, "unknown")
+	for i := 0; i < 10; i++ {
+		assert.Contains(t, []string{"ua-a", "ua-b"}, random.Next())
+	}
+}
@@ -0,0 +1,124 @@
+package interrupthooks
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/rs/zerolog"
+)
+
+// Hook is a cleanup function invoked when the process is interrupted.
+// The context carries the shared deadline for all hooks.
+type Hook func(ctx context.Context) error
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Registry holds interrupt hooks and runs them concurrently with a shared deadline
+type Registry struct {
+	mu    sync.Mutex
+	hooks []namedHook
+}
+
+// NewRegistry creates an empty hook registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a named hook to the registry
+func (r *Registry) Register(name string, hook Hook) {
+	if hook == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, namedHook{name: name, hook: hook})
+}
+
+// Len returns the number of registered hooks
+func (r *Registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.hooks)
+}
+
+// Run invokes all hooks concurrently and waits until they finish or the timeout elapses.
+// Hook failures and panics are logged; hooks still running at the deadline are abandoned.
+func (r *Registry) Run(timeout time.Duration, logger zerolog.Logger) {
+	r.mu.Lock()
+	hooks := make([]namedHook, len(r.hooks))
+	copy(hooks, r.hooks)
+	r.mu.Unlock()
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	logger.Info().Int("hook_count", len(hooks)).Dur("timeout", timeout).Msg("Running interrupt hooks")
+
+	var wg sync.WaitGroup
+	for _, h := range hooks {
+		wg.Add(1)
+		go func(h namedHook) {
+			defer wg.Done()
+			defer func() {
+				if rec := recover(); rec != nil {
+					logger.Error().Str("hook", h.name).Interface("panic", rec).Msg("Interrupt hook panicked")
+				}
+			}()
+
+			start := time.Now()
+			if err := h.hook(ctx); err != nil {
+				logger.Error().Err(err).Str("hook", h.name).Dur("duration", time.Since(start)).Msg("Interrupt hook failed")
+				return
+			}
+			logger.Debug().Str("hook", h.name).Dur("duration", time.Since(start)).Msg("Interrupt hook completed")
+		}(h)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info().Msg("Interrupt hooks completed")
+	case <-ctx.Done():
+		logger.Warn().Dur("timeout", timeout).Msg("Interrupt hooks timed out, continuing shutdown")
+	}
+}
+
+// CommandHook returns a hook that runs a shell command, killed when the deadline passes
+func CommandHook(command string) Hook {
+	return func(ctx context.Context) error {
+		output, err := exec.CommandContext(ctx, "sh", "-c", command).CombinedOutput()
+		if err != nil {
+			return errorwrapper.WrapError(err, fmt.Sprintf("interrupt command failed: %s", string(output)))
+		}
+		return nil
+	}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds a named hook to the process-wide registry used by the signal handler
+func Register(name string, hook Hook) {
+	defaultRegistry.Register(name, hook)
+}
+
+// Default returns the process-wide registry used by the signal handler
+func Default() *Registry {
+	return defaultRegistry
+}
@@ -0,0 +1,55 @@
+package interrupthooks
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestRegistryRunsHooksConcurrently(t *testing.T) {
+	registry := NewRegistry()
+	var completed int32
+
+	for i := 0; i < 3; i++ {
+		registry.Register("sleep", func(ctx context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			atomic.AddInt32(&completed, 1)
+			return nil
+		})
+	}
+	registry.Register("failing", func(ctx context.Context) error {
+		return errors.New("lock already released")
+	})
+
+	start := time.Now()
+	registry.Run(time.Second, zerolog.Nop())
+
+	if got := atomic.LoadInt32(&completed); got != 3 {
+		t.Errorf("expected 3 hooks to complete, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 140*time.Millisecond {
+		t.Errorf("hooks did not run concurrently, took %v", elapsed)
+	}
+}
+
+func TestRegistryRunRespectsTimeout(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("stuck", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	registry.Register("panicking", func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	start := time.Now()
+	registry.Run(50*time.Millisecond, zerolog.Nop())
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run blocked past its timeout: %v", elapsed)
+	}
+}
@@ -0,0 +1,50 @@
+package summary
+
+import (
+	"sort"
+	"time"
+)
+
+// ChangeDigest aggregates the changes recorded by scheduled scans over a time window, for a periodic
+// digest notification
+type ChangeDigest struct {
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Scans       int // Completed scans in the window
+	FailedScans int
+	NewURLs     int
+	OldURLs     int
+	// Hosts with new or removed URLs; only Host, New, Old and NewByContentType are set
+	Hosts []HostStats
+	// New URLs per content type across all hosts
+	ContentTypes map[string]int
+	// Reports of the scans that found changes, newest first
+	ReportPaths []string
+}
+
+// HasChanges reports whether any scan in the window found new or removed URLs
+func (cd ChangeDigest) HasChanges() bool {
+	return cd.NewURLs > 0 || cd.OldURLs > 0
+}
+
+// ContentTypeCount is the number of new URLs of one content type
+type ContentTypeCount struct {
+	ContentType string
+	Count       int
+}
+
+// SortedContentTypes returns the content type counts, largest first, then by content type
+func (cd ChangeDigest) SortedContentTypes() []ContentTypeCount {
+	counts := make([]ContentTypeCount, 0, len(cd.ContentTypes))
+	for contentType, count := range cd.ContentTypes {
+		counts = append(counts, ContentTypeCount{ContentType: contentType, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].ContentType < counts[j].ContentType
+	})
+	return counts
+}
@@ -0,0 +1,183 @@
+package summary
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HostStats holds probe and diff counts for a single hostname, so a completion
+// notification can show which hosts produced the findings
+type HostStats struct {
+	Host        string
+	TotalProbed int
+	Failed      int // Probes that errored or returned a status outside 2xx/3xx
+	New         int
+	Old         int
+	Existing    int
+	StatusCodes map[int]int // Probe count per HTTP status code
+
+	// New URLs per content type (media type without parameters, "unknown" when missing)
+	NewByContentType map[string]int
+
+	// Soonest-expiring TLS certificate seen on this host; zero if none was captured
+	CertNotAfter time.Time
+	CertIssuer   string
+}
+
+// Merge adds the counts from other into hs
+func (hs *HostStats) Merge(other HostStats) {
+	hs.TotalProbed += other.TotalProbed
+	hs.Failed += other.Failed
+	hs.New += other.New
+	hs.Old += other.Old
+	hs.Existing += other.Existing
+
+	if len(other.StatusCodes) > 0 && hs.StatusCodes == nil {
+		hs.StatusCodes = make(map[int]int, len(other.StatusCodes))
+	}
+	for code, count := range other.StatusCodes {
+		hs.StatusCodes[code] += count
+	}
+	for contentType, count := range other.NewByContentType {
+		hs.addNewContentType(contentType, count)
+	}
+	hs.observeCertificate(other.CertNotAfter, other.CertIssuer)
+}
+
+// addNewContentType counts new URLs of a content type
+func (hs *HostStats) addNewContentType(contentType string, count int) {
+	if hs.NewByContentType == nil {
+		hs.NewByContentType = make(map[string]int)
+	}
+	hs.NewByContentType[MediaType(contentType)] += count
+}
+
+// MediaType reduces a Content-Type header to its lower-cased media type, "unknown" when empty
+func MediaType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "" {
+		return "unknown"
+	}
+	return mediaType
+}
+
+// observeCertificate keeps the certificate that expires first
+func (hs *HostStats) observeCertificate(notAfter time.Time, issuer string) {
+	if notAfter.IsZero() {
+		return
+	}
+	if hs.CertNotAfter.IsZero() || notAfter.Before(hs.CertNotAfter) {
+		hs.CertNotAfter = notAfter
+		hs.CertIssuer = issuer
+	}
+}
+
+// CertExpiresWithin reports whether the host's certificate expires before now+window (or already has)
+func (hs HostStats) CertExpiresWithin(window time.Duration, now time.Time) bool {
+	return !hs.CertNotAfter.IsZero() && hs.CertNotAfter.Before(now.Add(window))
+}
+
+// NotableStatusCodes returns the 4xx/5xx status codes seen on this host in ascending order
+func (hs HostStats) NotableStatusCodes() []int {
+	var codes []int
+	for code := range hs.StatusCodes {
+		if code >= 400 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// notableProbes counts probes that returned a 4xx/5xx status
+func (hs HostStats) notableProbes() int {
+	total := 0
+	for _, code := range hs.NotableStatusCodes() {
+		total += hs.StatusCodes[code]
+	}
+	return total
+}
+
+// MergeHostStats merges src into dst, allocating dst if needed, and returns it
+func MergeHostStats(dst, src map[string]HostStats) map[string]HostStats {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]HostStats, len(src))
+	}
+
+	for host, stats := range src {
+		merged := dst[host]
+		merged.Host = host
+		merged.Merge(stats)
+		dst[host] = merged
+	}
+	return dst
+}
+
+// SortedHostStats orders hosts for triage: most new URLs first, then most removed URLs,
+// then most 4xx/5xx responses, then by hostname
+func SortedHostStats(stats map[string]HostStats) []HostStats {
+	sorted := make([]HostStats, 0, len(stats))
+	for _, hs := range stats {
+		sorted = append(sorted, hs)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.New != b.New {
+			return a.New > b.New
+		}
+		if a.Old != b.Old {
+			return a.Old > b.Old
+		}
+		if na, nb := a.notableProbes(), b.notableProbes(); na != nb {
+			return na > nb
+		}
+		return a.Host < b.Host
+	})
+	return sorted
+}
+
+// HostNames returns the distinct hostnames of a scan, sorted
+func HostNames(stats map[string]HostStats) []string {
+	hosts := make([]string, 0, len(stats))
+	for host := range stats {
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// ExpiringCertificates returns the hosts whose certificate expires within window of now, soonest first
+func ExpiringCertificates(stats map[string]HostStats, window time.Duration, now time.Time) []HostStats {
+	var expiring []HostStats
+	for _, hs := range stats {
+		if hs.CertExpiresWithin(window, now) {
+			expiring = append(expiring, hs)
+		}
+	}
+
+	sort.Slice(expiring, func(i, j int) bool {
+		if !expiring[i].CertNotAfter.Equal(expiring[j].CertNotAfter) {
+			return expiring[i].CertNotAfter.Before(expiring[j].CertNotAfter)
+		}
+		return expiring[i].Host < expiring[j].Host
+	})
+	return expiring
+}
+
+// hostnameOf returns the hostname of rawURL, or "" if it cannot be parsed
+func hostnameOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
@@ -0,0 +1,109 @@
+package summary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/differ"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryBuilder_HostStats(t *testing.T) {
+	probes := []httpxrunner.ProbeResult{
+		{InputURL: "https://a.example.com/", StatusCode: 200, ContentType: "Text/HTML; charset=utf-8", URLStatus: "new"},
+		{InputURL: "https://a.example.com/admin", StatusCode: 403, URLStatus: "new"},
+		{InputURL: "https://b.example.com:8443/", StatusCode: 500, URLStatus: "existing"},
+		{InputURL: "https://b.example.com/down", Error: "timeout"},
+	}
+	gone := httpxrunner.ProbeResult{InputURL: "https://b.example.com/removed", URLStatus: "old"}
+
+	diffs := map[string]differ.URLDiffResult{
+		"https://a.example.com": {Results: []differ.DiffedURL{{ProbeResult: probes[0]}, {ProbeResult: probes[1]}}},
+		"https://b.example.com": {Results: []differ.DiffedURL{{ProbeResult: probes[2]}, {ProbeResult: gone}}},
+	}
+
+	summary := NewSummaryBuilder(zerolog.Nop()).BuildSummary(&SummaryInput{
+		ScanSessionID:  "20240101-120000",
+		ProbeResults:   probes,
+		URLDiffResults: diffs,
+	})
+
+	require.Len(t, summary.HostStats, 2)
+	a := summary.HostStats["a.example.com"]
+	assert.Equal(t, HostStats{
+		Host: "a.example.com", TotalProbed: 2, Failed: 1, New: 2,
+		StatusCodes:      map[int]int{200: 1, 403: 1},
+		NewByContentType: map[string]int{"text/html": 1, "unknown": 1},
+	}, a)
+	assert.Equal(t, []int{403}, a.NotableStatusCodes())
+
+	b := summary.HostStats["b.example.com"]
+	assert.Equal(t, 2, b.TotalProbed)
+	assert.Equal(t, 2, b.Failed)
+	assert.Equal(t, 1, b.Old)
+	assert.Equal(t, 1, b.Existing)
+	assert.Equal(t, []int{500}, b.NotableStatusCodes())
+}
+
+func TestMergeHostStats(t *testing.T) {
+	merged := MergeHostStats(nil, map[string]HostStats{
+		"a.example.com": {Host: "a.example.com", TotalProbed: 1, New: 1, StatusCodes: map[int]int{200: 1}},
+	})
+	merged = MergeHostStats(merged, map[string]HostStats{
+		"a.example.com": {Host: "a.example.com", TotalProbed: 2, Old: 1, StatusCodes: map[int]int{200: 1, 404: 1}},
+		"b.example.com": {Host: "b.example.com", TotalProbed: 1, StatusCodes: map[int]int{502: 3}},
+		"c.example.com": {Host: "c.example.com", TotalProbed: 1},
+	})
+
+	assert.Equal(t, HostStats{Host: "a.example.com", TotalProbed: 3, New: 1, Old: 1, StatusCodes: map[int]int{200: 2, 404: 1}}, merged["a.example.com"])
+
+	var order []string
+	for _, hs := range SortedHostStats(merged) {
+		order = append(order, hs.Host)
+	}
+	assert.Equal(t, []string{"a.example.com", "b.example.com", "c.example.com"}, order)
+}
+
+func TestHostStats_ExpiringCertificates(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	probes := []httpxrunner.ProbeResult{
+		{InputURL: "https://a.example.com/", StatusCode: 200, TLSCertNotAfter: now.Add(60 * 24 * time.Hour), TLSCertIssuer: "R3"},
+		{InputURL: "https://a.example.com:8443/", StatusCode: 200, TLSCertNotAfter: now.Add(5 * 24 * time.Hour), TLSCertIssuer: "Internal CA"},
+		{InputURL: "https://b.example.com/", StatusCode: 200, TLSCertNotAfter: now.Add(-time.Hour), TLSCertIssuer: "R3"},
+		{InputURL: "http://c.example.com/", StatusCode: 200},
+	}
+
+	stats := NewSummaryBuilder(zerolog.Nop()).BuildSummary(&SummaryInput{ScanSessionID: "s", ProbeResults: probes}).HostStats
+	assert.Equal(t, "Internal CA", stats["a.example.com"].CertIssuer, "the soonest-expiring certificate is kept")
+	assert.True(t, stats["c.example.com"].CertNotAfter.IsZero())
+
+	expiring := ExpiringCertificates(stats, 14*24*time.Hour, now)
+	require.Len(t, expiring, 2)
+	assert.Equal(t, "b.example.com", expiring[0].Host)
+	assert.Equal(t, "a.example.com", expiring[1].Host)
+
+	merged := MergeHostStats(nil, map[string]HostStats{"c.example.com": {Host: "c.example.com", CertNotAfter: now.Add(time.Hour)}})
+	merged = MergeHostStats(merged, map[string]HostStats{"c.example.com": {Host: "c.example.com", CertNotAfter: now.Add(48 * time.Hour)}})
+	assert.Equal(t, now.Add(time.Hour), merged["c.example.com"].CertNotAfter)
+}
+
+func TestChangeDigest_SortedContentTypes(t *testing.T) {
+	merged := MergeHostStats(nil, map[string]HostStats{
+		"a.example.com": {Host: "a.example.com", New: 3, NewByContentType: map[string]int{"text/html": 2, "application/json": 1}},
+	})
+	merged = MergeHostStats(merged, map[string]HostStats{
+		"a.example.com": {Host: "a.example.com", New: 2, NewByContentType: map[string]int{"application/json; charset=utf-8": 2}},
+	})
+	assert.Equal(t, map[string]int{"text/html": 2, "application/json": 3}, merged["a.example.com"].NewByContentType)
+
+	digest := ChangeDigest{ContentTypes: map[string]int{"text/html": 2, "application/json": 3, "image/png": 2}}
+	assert.Equal(t, []ContentTypeCount{
+		{ContentType: "application/json", Count: 3},
+		{ContentType: "image/png", Count: 2},
+		{ContentType: "text/html", Count: 2},
+	}, digest.SortedContentTypes())
+	assert.False(t, digest.HasChanges())
+}
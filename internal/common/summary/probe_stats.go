@@ -6,6 +6,7 @@ type ProbeStats struct {
 	SuccessfulProbes  int // Number of probes that returned a successful response (e.g., 2xx)
 	FailedProbes      int // Number of probes that failed or returned error codes
 	DiscoverableItems int // e.g. number of items from httpx
+	DuplicatesSkipped int // URLs not probed because an earlier batch of the scan already probed them
 }
 
 // ProbeStatsBuilder handles building probe stats
@@ -44,6 +45,12 @@ func (psb *ProbeStatsBuilder) WithDiscoverableItems(items int) *ProbeStatsBuilde
 	return psb
 }
 
+// WithDuplicatesSkipped sets the cross-batch duplicate count
+func (psb *ProbeStatsBuilder) WithDuplicatesSkipped(skipped int) *ProbeStatsBuilder {
+	psb.stats.DuplicatesSkipped = skipped
+	return psb
+}
+
 // Build returns the constructed probe stats
 func (psb *ProbeStatsBuilder) Build() ProbeStats {
 	return psb.stats
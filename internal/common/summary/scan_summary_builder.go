@@ -70,6 +70,18 @@ func (b *ScanSummaryDataBuilder) WithDiffStats(diffStats DiffStats) *ScanSummary
 	return b
 }
 
+// WithHostStats sets the per-hostname breakdown for the ScanSummaryData
+func (b *ScanSummaryDataBuilder) WithHostStats(hostStats map[string]HostStats) *ScanSummaryDataBuilder {
+	b.summary.HostStats = hostStats
+	return b
+}
+
+// WithNewHosts sets the hosts first probed by this scan
+func (b *ScanSummaryDataBuilder) WithNewHosts(newHosts []string) *ScanSummaryDataBuilder {
+	b.summary.NewHosts = newHosts
+	return b
+}
+
 // WithScanDuration sets the ScanDuration for the ScanSummaryData
 func (b *ScanSummaryDataBuilder) WithScanDuration(scanDuration time.Duration) *ScanSummaryDataBuilder {
 	b.summary.ScanDuration = scanDuration
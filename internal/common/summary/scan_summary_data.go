@@ -22,20 +22,26 @@ const (
 
 // ScanSummaryData holds all relevant information about a scan to be used in notifications.
 type ScanSummaryData struct {
-	ScanSessionID    string        // Unique identifier for the scan session (e.g., YYYYMMDD-HHMMSS timestamp)
-	TargetSource     string        // The source of the targets (e.g., file path, "config_input_urls")
-	ScanMode         string        // Mode of the scan (e.g., "onetime", "automated")
-	Targets          []string      // List of original target URLs/identifiers
-	TotalTargets     int           // Total number of targets processed or attempted
-	ProbeStats       ProbeStats    // Statistics from the probing phase
-	DiffStats        DiffStats     // Statistics from the diffing phase (New, Old, Existing)
-	ScanDuration     time.Duration // Total duration of the scan
-	ReportPath       string        // Filesystem path to the generated report (used by notifier to attach)
-	Status           string        // Overall status: "COMPLETED", "FAILED", "STARTED", "INTERRUPTED", "PARTIAL_COMPLETE"
-	ErrorMessages    []string      // Any critical errors encountered during the scan
-	Component        string        // Component where an error might have occurred (for critical errors)
-	RetriesAttempted int           // Number of retries, if applicable
-	CycleMinutes     int           // Cycle interval in minutes (only for automated mode)
+	ScanSessionID    string               // Unique identifier for the scan session (e.g., YYYYMMDD-HHMMSS timestamp)
+	TargetSource     string               // The source of the targets (e.g., file path, "config_input_urls")
+	ScanMode         string               // Mode of the scan (e.g., "onetime", "automated")
+	Targets          []string             // List of original target URLs/identifiers
+	TotalTargets     int                  // Total number of targets processed or attempted
+	ProbeStats       ProbeStats           // Statistics from the probing phase
+	DiffStats        DiffStats            // Statistics from the diffing phase (New, Old, Existing)
+	HostStats        map[string]HostStats // Per-hostname breakdown of probe and diff results
+	NewHosts         []string             // Hosts never probed by an earlier scan (only for automated mode)
+	NewURLsBySource  []SourceNewURLs      // New URLs grouped by the crawled page they were discovered on
+	ScanDuration     time.Duration        // Total duration of the scan
+	ReportPath       string               // Filesystem path to the generated report (used by notifier to attach)
+	Status           string               // Overall status: "COMPLETED", "FAILED", "STARTED", "INTERRUPTED", "PARTIAL_COMPLETE"
+	ErrorMessages    []string             // Any critical errors encountered during the scan
+	Component        string               // Component where an error might have occurred (for critical errors)
+	RetriesAttempted int                  // Number of retries, if applicable
+	CycleMinutes     int                  // Cycle interval in minutes (only for automated mode)
+	CronExpression   string               // Cron schedule overriding CycleMinutes (only for automated mode)
+	MaxDuration      time.Duration        // Wall-clock cap applied to the scan, 0 if none (only for onetime mode)
+	Baseline         bool                 // Scan only recorded a baseline to diff later scans against; change notifications are suppressed
 }
 
 // GetDefaultScanSummaryData initializes a ScanSummaryData with default/empty values.
@@ -152,6 +158,60 @@ func (sb *SummaryBuilder) calculateStats(summary *ScanSummaryData, probeResults
 		Old:      totalOld,
 		Existing: totalExisting,
 	}
+
+	summary.HostStats = sb.calculateHostStats(probeResults, urlDiffResults)
+	summary.NewURLsBySource = GroupNewURLsBySource(urlDiffResults)
+}
+
+// calculateHostStats groups probe status codes and diff statuses by hostname
+func (sb *SummaryBuilder) calculateHostStats(probeResults []httpxrunner.ProbeResult, urlDiffResults map[string]differ.URLDiffResult) map[string]HostStats {
+	hostStats := make(map[string]HostStats)
+	entryFor := func(rawURL string) (HostStats, string) {
+		host := hostnameOf(rawURL)
+		hs := hostStats[host]
+		hs.Host = host
+		return hs, host
+	}
+
+	for _, result := range probeResults {
+		hs, host := entryFor(result.InputURL)
+		if host == "" {
+			continue
+		}
+		hs.TotalProbed++
+		if result.Error != "" || result.StatusCode < 200 || result.StatusCode >= 400 {
+			hs.Failed++
+		}
+		if result.StatusCode > 0 {
+			if hs.StatusCodes == nil {
+				hs.StatusCodes = make(map[int]int)
+			}
+			hs.StatusCodes[result.StatusCode]++
+		}
+		hs.observeCertificate(result.TLSCertNotAfter, result.TLSCertIssuer)
+		hostStats[host] = hs
+	}
+
+	for _, diffResult := range urlDiffResults {
+		for _, diffed := range diffResult.Results {
+			hs, host := entryFor(diffed.ProbeResult.InputURL)
+			if host == "" {
+				continue
+			}
+			switch differ.URLStatus(diffed.ProbeResult.URLStatus) {
+			case differ.StatusNew:
+				hs.New++
+				hs.addNewContentType(diffed.ProbeResult.ContentType, 1)
+			case differ.StatusOld:
+				hs.Old++
+			case differ.StatusExisting:
+				hs.Existing++
+			}
+			hostStats[host] = hs
+		}
+	}
+
+	return hostStats
 }
 
 // determineStatus determines the final scan status based on workflow results
@@ -0,0 +1,63 @@
+package summary
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks how urgent a notification is, so routine changes can be kept off on-call webhooks
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"info", "low", "medium", "high", "critical"}
+
+// String returns the configuration name of the severity
+func (s Severity) String() string {
+	if s < SeverityInfo || int(s) >= len(severityNames) {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity parses a configured severity name; an empty name is SeverityInfo
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return SeverityInfo, nil
+	}
+	for i, known := range severityNames {
+		if name == known {
+			return Severity(i), nil
+		}
+	}
+	return SeverityInfo, fmt.Errorf("unknown severity '%s' (expected one of %s)", name, strings.Join(severityNames, ", "))
+}
+
+// ScanSeverity rates a scan completion for notification routing:
+//   - high: the scan did not complete, or it probed hosts never seen before
+//   - medium: new URLs serve JSON or XML, which usually means a new API surface
+//   - low: other new or gone URLs
+//   - info: nothing changed
+func ScanSeverity(data ScanSummaryData) Severity {
+	if data.Status != string(ScanStatusCompleted) || len(data.NewHosts) > 0 {
+		return SeverityHigh
+	}
+	for _, stats := range data.HostStats {
+		for contentType := range stats.NewByContentType {
+			if strings.Contains(contentType, "json") || strings.Contains(contentType, "xml") {
+				return SeverityMedium
+			}
+		}
+	}
+	if data.DiffStats.New > 0 || data.DiffStats.Old > 0 {
+		return SeverityLow
+	}
+	return SeverityInfo
+}
@@ -0,0 +1,51 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := ParseSeverity(" High ")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityHigh, severity)
+	assert.Equal(t, "high", severity.String())
+
+	severity, err = ParseSeverity("")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityInfo, severity)
+
+	_, err = ParseSeverity("urgent")
+	assert.Error(t, err)
+}
+
+func TestScanSeverity(t *testing.T) {
+	completed := string(ScanStatusCompleted)
+	tests := []struct {
+		name string
+		data ScanSummaryData
+		want Severity
+	}{
+		{"no changes", ScanSummaryData{Status: completed}, SeverityInfo},
+		{"gone urls", ScanSummaryData{Status: completed, DiffStats: DiffStats{Old: 2}}, SeverityLow},
+		{"new html", ScanSummaryData{
+			Status:    completed,
+			DiffStats: DiffStats{New: 1},
+			HostStats: map[string]HostStats{"a.example.com": {NewByContentType: map[string]int{"text/html": 1}}},
+		}, SeverityLow},
+		{"new json", ScanSummaryData{
+			Status:    completed,
+			DiffStats: DiffStats{New: 1},
+			HostStats: map[string]HostStats{"a.example.com": {NewByContentType: map[string]int{"application/json": 1}}},
+		}, SeverityMedium},
+		{"new host", ScanSummaryData{Status: completed, NewHosts: []string{"b.example.com"}}, SeverityHigh},
+		{"failed", ScanSummaryData{Status: string(ScanStatusFailed)}, SeverityHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ScanSeverity(tt.data))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package summary
+
+import (
+	"sort"
+
+	"github.com/aleister1102/monsterinc/internal/differ"
+)
+
+// SourceNewURLs lists the new URLs of a scan that were discovered on one crawled page. A page that
+// starts linking to endpoints no earlier scan saw is a recon signal of its own, whatever the hosts involved.
+type SourceNewURLs struct {
+	Source string   // Page the URLs were discovered on
+	URLs   []string // New URLs, sorted
+}
+
+// GroupNewURLsBySource groups the new URLs of a diff by the page the crawler found them on, most URLs
+// first. Seed URLs and URLs whose source page is unknown are left out.
+func GroupNewURLsBySource(urlDiffResults map[string]differ.URLDiffResult) []SourceNewURLs {
+	bySource := make(map[string][]string)
+	for _, diffResult := range urlDiffResults {
+		for _, diffed := range diffResult.Results {
+			probe := diffed.ProbeResult
+			if differ.URLStatus(probe.URLStatus) != differ.StatusNew || probe.DiscoveredFrom == "" {
+				continue
+			}
+			bySource[probe.DiscoveredFrom] = append(bySource[probe.DiscoveredFrom], probe.InputURL)
+		}
+	}
+	return sortedSourceNewURLs(bySource)
+}
+
+// MergeNewURLsBySource combines the groups of two summaries, e.g. of two batches of one scan
+func MergeNewURLsBySource(dst, src []SourceNewURLs) []SourceNewURLs {
+	if len(src) == 0 {
+		return dst
+	}
+
+	bySource := make(map[string][]string, len(dst)+len(src))
+	for _, group := range append(dst, src...) {
+		bySource[group.Source] = append(bySource[group.Source], group.URLs...)
+	}
+	return sortedSourceNewURLs(bySource)
+}
+
+// sortedSourceNewURLs deduplicates and sorts the URLs of each source, and orders sources by URL count,
+// then by source
+func sortedSourceNewURLs(bySource map[string][]string) []SourceNewURLs {
+	if len(bySource) == 0 {
+		return nil
+	}
+
+	groups := make([]SourceNewURLs, 0, len(bySource))
+	for source, urls := range bySource {
+		sort.Strings(urls)
+		unique := urls[:0]
+		for i, u := range urls {
+			if i == 0 || u != urls[i-1] {
+				unique = append(unique, u)
+			}
+		}
+		groups = append(groups, SourceNewURLs{Source: source, URLs: unique})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].URLs) != len(groups[j].URLs) {
+			return len(groups[i].URLs) > len(groups[j].URLs)
+		}
+		return groups[i].Source < groups[j].Source
+	})
+	return groups
+}
@@ -0,0 +1,52 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/differ"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupNewURLsBySource(t *testing.T) {
+	probe := func(url, status, from string) differ.DiffedURL {
+		return differ.DiffedURL{ProbeResult: httpxrunner.ProbeResult{InputURL: url, URLStatus: status, DiscoveredFrom: from}}
+	}
+	diffs := map[string]differ.URLDiffResult{
+		"https://a.example.com": {Results: []differ.DiffedURL{
+			probe("https://a.example.com/api/v2/users", "new", "https://a.example.com/app"),
+			probe("https://a.example.com/api/v2/admin", "new", "https://a.example.com/app"),
+			probe("https://a.example.com/api/v1/users", "existing", "https://a.example.com/app"),
+			probe("https://a.example.com/", "new", ""), // Seed
+		}},
+		"https://b.example.com": {Results: []differ.DiffedURL{
+			probe("https://b.example.com/login", "new", "https://b.example.com/"),
+			probe("https://b.example.com/old", "old", "https://b.example.com/"),
+		}},
+	}
+
+	assert.Equal(t, []SourceNewURLs{
+		{Source: "https://a.example.com/app", URLs: []string{"https://a.example.com/api/v2/admin", "https://a.example.com/api/v2/users"}},
+		{Source: "https://b.example.com/", URLs: []string{"https://b.example.com/login"}},
+	}, GroupNewURLsBySource(diffs))
+
+	assert.Nil(t, GroupNewURLsBySource(nil))
+}
+
+func TestMergeNewURLsBySource(t *testing.T) {
+	first := []SourceNewURLs{
+		{Source: "https://a.example.com/app", URLs: []string{"https://a.example.com/x"}},
+	}
+	second := []SourceNewURLs{
+		{Source: "https://b.example.com/", URLs: []string{"https://b.example.com/y"}},
+		{Source: "https://a.example.com/app", URLs: []string{"https://a.example.com/x", "https://a.example.com/w"}},
+	}
+
+	merged := MergeNewURLsBySource(first, second)
+	assert.Equal(t, []SourceNewURLs{
+		{Source: "https://a.example.com/app", URLs: []string{"https://a.example.com/w", "https://a.example.com/x"}},
+		{Source: "https://b.example.com/", URLs: []string{"https://b.example.com/y"}},
+	}, merged)
+
+	assert.Equal(t, first, MergeNewURLsBySource(first, nil))
+}
@@ -0,0 +1,55 @@
+package urlhandler
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+)
+
+const (
+	// StdinTargetsPath is passed in place of a targets file path to read targets from stdin
+	StdinTargetsPath = "-"
+	// StdinTargetSource is the target source reported for targets read from stdin
+	StdinTargetSource = "stdin"
+)
+
+// stdinContent caches stdin so targets can be loaded more than once per process
+// (e.g. by the onetime runner and the batch orchestrator, or on every scheduler cycle)
+var stdinContent struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// getTargetsFromStdin adds targets from the cached stdin content to set
+func (tm *TargetManager) getTargetsFromStdin(set *targetSet) error {
+	data, err := readStdinOnce()
+	if err != nil {
+		return err
+	}
+	return tm.readTargets(bytes.NewReader(data), set)
+}
+
+// readStdinOnce reads all of stdin the first time it is called and returns the same content afterwards
+func readStdinOnce() ([]byte, error) {
+	stdinContent.once.Do(func() {
+		info, err := os.Stdin.Stat()
+		if err != nil {
+			stdinContent.err = errorwrapper.WrapError(err, "failed to inspect stdin")
+			return
+		}
+		if info.Mode()&os.ModeCharDevice != 0 {
+			stdinContent.err = errorwrapper.NewError("no targets piped to stdin (it is a terminal)")
+			return
+		}
+
+		stdinContent.data, stdinContent.err = io.ReadAll(os.Stdin)
+		if stdinContent.err != nil {
+			stdinContent.err = errorwrapper.WrapError(stdinContent.err, "failed to read targets from stdin")
+		}
+	})
+	return stdinContent.data, stdinContent.err
+}
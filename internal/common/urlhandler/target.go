@@ -1,8 +1,13 @@
 package urlhandler
 
+import "time"
+
 // Target represents a URL to be scanned.
 // It includes the original input URL and its normalized form.
 // It can also store metadata about the target.
 type Target struct {
-	URL string // The URL as provided by the user
+	URL     string        // The URL as provided by the user
+	Auth    *TargetAuth   // Credentials from an inline annotation or companion file, nil if none
+	Tags    []string      // Lower-cased tags from an inline "|tags=" annotation
+	Timeout time.Duration // Request timeout from an inline "|timeout=" annotation, 0 to use the global timeouts
 }
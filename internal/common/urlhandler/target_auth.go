@@ -0,0 +1,195 @@
+package urlhandler
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+)
+
+// TargetAuthAnnotation separates a target URL from its inline credentials:
+// https://api.example.com/v1|auth=bearer:TOKEN or https://api.example.com|auth=basic:user:pass
+const TargetAuthAnnotation = "|auth="
+
+// TargetAuthCompanionSuffix is appended to a target file's path to find its companion credentials file
+const TargetAuthCompanionSuffix = ".auth"
+
+// Supported target auth schemes
+const (
+	TargetAuthBasic  = "basic"
+	TargetAuthBearer = "bearer"
+)
+
+// TargetAuth holds the credentials sent to a target. String never reveals them,
+// so a TargetAuth is safe to log.
+type TargetAuth struct {
+	Scheme   string
+	Username string
+	Password string
+	Token    string
+}
+
+// ParseTargetAuth parses "bearer:TOKEN" or "basic:USER:PASS"; the password may itself contain colons.
+// Errors name the scheme only, never the secret.
+func ParseTargetAuth(spec string) (TargetAuth, error) {
+	scheme, value, found := strings.Cut(strings.TrimSpace(spec), ":")
+	scheme = strings.ToLower(scheme)
+	if !found || value == "" {
+		return TargetAuth{}, errorwrapper.NewError("target auth must be bearer:TOKEN or basic:USER:PASS")
+	}
+
+	switch scheme {
+	case TargetAuthBearer:
+		return TargetAuth{Scheme: scheme, Token: value}, nil
+	case TargetAuthBasic:
+		username, password, found := strings.Cut(value, ":")
+		if !found || username == "" {
+			return TargetAuth{}, errorwrapper.NewError("basic target auth must be basic:USER:PASS")
+		}
+		return TargetAuth{Scheme: scheme, Username: username, Password: password}, nil
+	default:
+		return TargetAuth{}, errorwrapper.NewError("unsupported target auth scheme '%s' (use bearer or basic)", scheme)
+	}
+}
+
+// HeaderValue returns the Authorization header value for the credentials
+func (ta TargetAuth) HeaderValue() string {
+	if ta.Scheme == TargetAuthBasic {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(ta.Username+":"+ta.Password))
+	}
+	return "Bearer " + ta.Token
+}
+
+// String names the scheme with the secret redacted
+func (ta TargetAuth) String() string {
+	return ta.Scheme + ":[REDACTED]"
+}
+
+// splitTargetAuth strips an inline auth annotation from a target line. auth is nil when the line has none.
+func splitTargetAuth(line string) (string, *TargetAuth, error) {
+	index := strings.LastIndex(line, TargetAuthAnnotation)
+	if index < 0 {
+		return line, nil, nil
+	}
+
+	target := line[:index]
+	auth, err := ParseTargetAuth(line[index+len(TargetAuthAnnotation):])
+	if err != nil {
+		return target, nil, err
+	}
+	return target, &auth, nil
+}
+
+// TargetCredentials maps target URLs to the credentials sent to them. The zero value holds none.
+type TargetCredentials struct {
+	entries []credentialEntry
+}
+
+type credentialEntry struct {
+	scope targetScope
+	auth  TargetAuth
+}
+
+// NewTargetCredentials collects the credentials of the targets that have any
+func NewTargetCredentials(targets []Target) TargetCredentials {
+	var creds TargetCredentials
+	for _, target := range targets {
+		if target.Auth != nil {
+			creds.Add(target.URL, *target.Auth)
+		}
+	}
+	return creds
+}
+
+// Add registers auth for targetURL; unparsable URLs are ignored
+func (tc *TargetCredentials) Add(targetURL string, auth TargetAuth) {
+	scope, ok := parseTargetScope(targetURL)
+	if !ok {
+		return
+	}
+	tc.entries = append(tc.entries, credentialEntry{scope: scope, auth: auth})
+}
+
+// Merge adds all credentials of other
+func (tc *TargetCredentials) Merge(other TargetCredentials) {
+	tc.entries = append(tc.entries, other.entries...)
+}
+
+// Len returns the number of registered credentials
+func (tc TargetCredentials) Len() int {
+	return len(tc.entries)
+}
+
+// ForURL returns the credentials for u: the target on the same host with the longest path prefix of u wins,
+// otherwise the first target declared for that host. URLs on other hosts get none.
+func (tc TargetCredentials) ForURL(u *url.URL) (TargetAuth, bool) {
+	index := matchTargetScope(len(tc.entries), func(i int) targetScope { return tc.entries[i].scope }, u)
+	if index < 0 {
+		return TargetAuth{}, false
+	}
+	return tc.entries[index].auth, true
+}
+
+// ForURLString parses rawURL and returns its credentials
+func (tc TargetCredentials) ForURLString(rawURL string) (TargetAuth, bool) {
+	if len(tc.entries) == 0 {
+		return TargetAuth{}, false
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return TargetAuth{}, false
+	}
+	return tc.ForURL(parsed)
+}
+
+// targetScope is the host and path a target covers
+type targetScope struct {
+	host       string
+	pathPrefix string
+}
+
+// parseTargetScope returns the scope of targetURL; unparsable URLs have none
+func parseTargetScope(targetURL string) (targetScope, bool) {
+	parsed, err := url.Parse(targetURL)
+	if err != nil || parsed.Host == "" {
+		return targetScope{}, false
+	}
+	return targetScope{host: strings.ToLower(parsed.Host), pathPrefix: strings.TrimSuffix(parsed.Path, "/")}, true
+}
+
+// matchTargetScope returns the index of the scope that covers u: on the same host, the longest path prefix
+// of u wins, otherwise the first scope declared for that host. It returns -1 when no scope is on u's host.
+func matchTargetScope(count int, scopeAt func(int) targetScope, u *url.URL) int {
+	if u == nil {
+		return -1
+	}
+
+	host := strings.ToLower(u.Host)
+	hostMatch, prefixMatch := -1, -1
+	for i := 0; i < count; i++ {
+		scope := scopeAt(i)
+		if scope.host != host {
+			continue
+		}
+		if hostMatch < 0 {
+			hostMatch = i
+		}
+		if hasPathPrefix(u.Path, scope.pathPrefix) && (prefixMatch < 0 || len(scope.pathPrefix) > len(scopeAt(prefixMatch).pathPrefix)) {
+			prefixMatch = i
+		}
+	}
+
+	if prefixMatch >= 0 {
+		return prefixMatch
+	}
+	return hostMatch
+}
+
+// hasPathPrefix reports whether path equals prefix or continues it at a segment boundary
+func hasPathPrefix(path, prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
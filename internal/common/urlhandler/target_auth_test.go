@@ -0,0 +1,57 @@
+package urlhandler
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTargetAuth(t *testing.T) {
+	bearer, err := ParseTargetAuth("Bearer:abc.def")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer abc.def", bearer.HeaderValue())
+
+	basic, err := ParseTargetAuth("basic:alice:pa:ss")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", basic.Username)
+	assert.Equal(t, "pa:ss", basic.Password)
+	assert.Equal(t, "Basic YWxpY2U6cGE6c3M=", basic.HeaderValue())
+
+	for _, spec := range []string{"", "bearer", "bearer:", "basic:alice", "basic::pass", "digest:alice:secret"} {
+		_, err := ParseTargetAuth(spec)
+		require.Error(t, err, spec)
+		assert.NotContains(t, err.Error(), "secret")
+	}
+}
+
+func TestTargetAuth_StringRedactsSecret(t *testing.T) {
+	auth := TargetAuth{Scheme: TargetAuthBasic, Username: "alice", Password: "secret"}
+	assert.Equal(t, "basic:[REDACTED]", auth.String())
+}
+
+func TestTargetCredentials_ForURL(t *testing.T) {
+	var creds TargetCredentials
+	creds.Add("https://api.example.com/v1", TargetAuth{Scheme: TargetAuthBearer, Token: "v1"})
+	creds.Add("https://api.example.com/v1/admin", TargetAuth{Scheme: TargetAuthBearer, Token: "admin"})
+	creds.Add("https://other.example.com:8443", TargetAuth{Scheme: TargetAuthBearer, Token: "other"})
+
+	tokenFor := func(rawURL string) string {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		auth, ok := creds.ForURL(u)
+		if !ok {
+			return ""
+		}
+		return auth.Token
+	}
+
+	assert.Equal(t, "v1", tokenFor("https://api.example.com/v1/users?id=1"))
+	assert.Equal(t, "admin", tokenFor("https://api.example.com/v1/admin/keys"))
+	assert.Equal(t, "v1", tokenFor("https://API.example.com/v1admin"), "prefix must end at a path segment")
+	assert.Equal(t, "v1", tokenFor("https://api.example.com/static/app.js"), "falls back to the host's first target")
+	assert.Equal(t, "other", tokenFor("https://other.example.com:8443/anything"))
+	assert.Equal(t, "", tokenFor("https://other.example.com/anything"), "port is part of the host")
+	assert.Equal(t, "", tokenFor("https://unrelated.example.com/v1"))
+}
@@ -0,0 +1,111 @@
+package urlhandler
+
+import (
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+)
+
+// TargetExpansionConfig configures how CIDR ranges and wildcard domains in target files are expanded into
+// host URLs. It is built from config.TargetExpansionConfig.
+type TargetExpansionConfig struct {
+	// URL schemes generated for every host in a range
+	Schemes []string
+	// Ports generated for every host; empty uses the scheme's default port
+	Ports []int
+	// Maximum number of URLs a single range may expand to; larger ranges are rejected. 0 uses the default
+	MaxExpansion int
+	// Expansion of "*.example.com" targets into known subdomains
+	Wildcards WildcardExpansionConfig
+}
+
+// DefaultTargetExpansionConfig returns default configuration
+func DefaultTargetExpansionConfig() TargetExpansionConfig {
+	return TargetExpansionConfig{
+		Schemes:      []string{"http", "https"},
+		Ports:        []int{},
+		MaxExpansion: 65536,
+		Wildcards:    DefaultWildcardExpansionConfig(),
+	}
+}
+
+// parseCIDRTarget reports whether a target line is a CIDR range (IPv4 or IPv6)
+func parseCIDRTarget(line string) (netip.Prefix, bool) {
+	if !strings.Contains(line, "/") || strings.Contains(line, "://") {
+		return netip.Prefix{}, false
+	}
+	prefix, err := netip.ParsePrefix(line)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	return prefix.Masked(), true
+}
+
+// ExpandCIDR expands a CIDR range into one URL per host, scheme and port.
+// IPv4 network and broadcast addresses are skipped for ranges larger than /31.
+func ExpandCIDR(prefix netip.Prefix, cfg TargetExpansionConfig) ([]string, error) {
+	schemes := cfg.Schemes
+	if len(schemes) == 0 {
+		schemes = DefaultTargetExpansionConfig().Schemes
+	}
+	portCount := len(cfg.Ports)
+	if portCount == 0 {
+		portCount = 1
+	}
+
+	maxExpansion := cfg.MaxExpansion
+	if maxExpansion <= 0 {
+		maxExpansion = DefaultTargetExpansionConfig().MaxExpansion
+	}
+
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	combinations := uint64(len(schemes) * portCount)
+	// Anything this wide cannot fit under a sane limit, and shifting further would overflow
+	if hostBits >= 48 {
+		return nil, errorwrapper.NewError("CIDR range %s is too large to expand (max_expansion is %d URLs)", prefix, maxExpansion)
+	}
+
+	hostCount := uint64(1) << hostBits
+	skipEdges := prefix.Addr().Is4() && hostBits >= 2
+	if skipEdges {
+		hostCount -= 2
+	}
+
+	total := hostCount * combinations
+	if total > uint64(maxExpansion) {
+		return nil, errorwrapper.NewError("CIDR range %s expands to %d URLs, exceeding max_expansion of %d", prefix, total, maxExpansion)
+	}
+
+	urls := make([]string, 0, total)
+	first := prefix.Addr()
+	for addr := first; addr.IsValid() && prefix.Contains(addr); addr = addr.Next() {
+		if skipEdges && (addr == first || !prefix.Contains(addr.Next())) {
+			continue
+		}
+		urls = append(urls, buildHostURLs(addr, schemes, cfg.Ports)...)
+	}
+	return urls, nil
+}
+
+// buildHostURLs builds the URLs for one host address
+func buildHostURLs(addr netip.Addr, schemes []string, ports []int) []string {
+	host := addr.String()
+	var urls []string
+	for _, scheme := range schemes {
+		if len(ports) == 0 {
+			if addr.Is6() {
+				urls = append(urls, scheme+"://["+host+"]")
+			} else {
+				urls = append(urls, scheme+"://"+host)
+			}
+			continue
+		}
+		for _, port := range ports {
+			urls = append(urls, scheme+"://"+net.JoinHostPort(host, strconv.Itoa(port)))
+		}
+	}
+	return urls
+}
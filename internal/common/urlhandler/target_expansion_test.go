@@ -0,0 +1,65 @@
+package urlhandler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCIDRTarget(t *testing.T) {
+	prefix, ok := parseCIDRTarget("10.0.0.7/30")
+	require.True(t, ok)
+	assert.Equal(t, "10.0.0.4/30", prefix.String())
+
+	_, ok = parseCIDRTarget("2001:db8::/126")
+	assert.True(t, ok)
+
+	for _, line := range []string{"https://example.com/a/b", "example.com", "10.0.0.1", "example.com/24"} {
+		_, ok := parseCIDRTarget(line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestExpandCIDR(t *testing.T) {
+	prefix, _ := parseCIDRTarget("192.168.1.0/30")
+	urls, err := ExpandCIDR(prefix, TargetExpansionConfig{Schemes: []string{"https"}, MaxExpansion: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://192.168.1.1", "https://192.168.1.2"}, urls)
+
+	prefix, _ = parseCIDRTarget("2001:db8::/127")
+	urls, err = ExpandCIDR(prefix, TargetExpansionConfig{Schemes: []string{"http"}, Ports: []int{8080, 8443}, MaxExpansion: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"http://[2001:db8::]:8080", "http://[2001:db8::]:8443",
+		"http://[2001:db8::1]:8080", "http://[2001:db8::1]:8443",
+	}, urls)
+}
+
+func TestExpandCIDR_RejectsOversizedRanges(t *testing.T) {
+	prefix, _ := parseCIDRTarget("10.0.0.0/8")
+	_, err := ExpandCIDR(prefix, DefaultTargetExpansionConfig())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_expansion")
+
+	prefix, _ = parseCIDRTarget("2001:db8::/32")
+	_, err = ExpandCIDR(prefix, DefaultTargetExpansionConfig())
+	assert.Error(t, err)
+}
+
+func TestTargetManager_LoadAndSelectTargets_ExpandsCIDR(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "targets.txt")
+	require.NoError(t, os.WriteFile(file, []byte("https://example.com\n10.1.1.0/31\n"), 0644))
+
+	tm := NewTargetManager(zerolog.Nop()).WithExpansionConfig(TargetExpansionConfig{Schemes: []string{"https"}, MaxExpansion: 4})
+	targets, _, err := tm.LoadAndSelectTargets(file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com", "https://10.1.1.0", "https://10.1.1.1"}, tm.GetTargetStrings(targets))
+
+	require.NoError(t, os.WriteFile(file, []byte("10.1.0.0/24\n"), 0644))
+	_, _, err = tm.LoadAndSelectTargets(file)
+	assert.Error(t, err)
+}
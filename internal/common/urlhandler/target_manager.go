@@ -2,7 +2,12 @@ package urlhandler
 
 import (
 	"bufio"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
 	"github.com/rs/zerolog"
@@ -10,66 +15,334 @@ import (
 
 // TargetManager handles loading and managing targets from various sources
 type TargetManager struct {
-	logger zerolog.Logger
-	// We can add configuration here if needed later, e.g., for concurrent processing
+	logger          zerolog.Logger
+	expansionConfig TargetExpansionConfig
+	wildcards       *wildcardExpander
+	onlyTags        []string
+	sampling        TargetSamplingConfig
 }
 
 // NewTargetManager creates a new TargetManager instance
 func NewTargetManager(logger zerolog.Logger) *TargetManager {
-	return &TargetManager{
+	tm := &TargetManager{
 		logger: logger.With().Str("component", "TargetManager").Logger(),
 	}
+	return tm.WithExpansionConfig(DefaultTargetExpansionConfig())
 }
 
-// LoadAndSelectTargets loads targets from the command-line file option
+// WithExpansionConfig sets how CIDR ranges and wildcard domains in target files are expanded
+func (tm *TargetManager) WithExpansionConfig(cfg TargetExpansionConfig) *TargetManager {
+	tm.expansionConfig = cfg
+	tm.wildcards = newWildcardExpander(cfg.Wildcards, tm.logger)
+	return tm
+}
+
+// WithSubdomainSources replaces the configured wildcard subdomain sources
+func (tm *TargetManager) WithSubdomainSources(sources ...SubdomainSource) *TargetManager {
+	tm.wildcards.sources = sources
+	return tm
+}
+
+// WithTagFilter restricts LoadAndSelectTargets to targets carrying at least one of tags; no tags disables the filter
+func (tm *TargetManager) WithTagFilter(tags []string) *TargetManager {
+	tm.onlyTags = MergeTags(nil, tags)
+	return tm
+}
+
+// WithSampling reduces the targets returned by LoadAndSelectTargets to a seeded sample; a zero config disables it
+func (tm *TargetManager) WithSampling(cfg TargetSamplingConfig) *TargetManager {
+	tm.sampling = cfg
+	return tm
+}
+
+// LoadAndSelectTargets loads targets from the command-line file option. cliFile may
+// list several files separated by commas, and each entry may be a glob pattern;
+// targets are merged and deduplicated across all of them. A file's companion
+// credentials file (its path plus ".auth") supplies auth for targets without an inline annotation.
+// With a tag filter, only matching targets are returned and the source names the tags. Sampling
+// applies after the tag filter, and the source then notes the sample size and seed.
 func (tm *TargetManager) LoadAndSelectTargets(cliFile string) ([]Target, string, error) {
-	var targets []Target
 	var source string
-	var err error
 
 	// Only source: Command-line file option
 	if cliFile != "" {
-		// tm.logger.Info().Str("file", cliFile).Msg("Loading targets from command-line file option")
-		targets, err = tm.getTargetsFromFile(cliFile)
+		filePaths, err := ResolveTargetPaths(cliFile)
 		if err != nil {
-			return nil, source, errorwrapper.WrapError(err, "failed to load URLs from file '"+cliFile+"'")
+			return nil, source, err
+		}
+
+		set := newTargetSet()
+		for _, filePath := range filePaths {
+			if err := tm.getTargetsFromFile(filePath, set); err != nil {
+				return nil, source, errorwrapper.WrapError(err, "failed to load URLs from file '"+filePath+"'")
+			}
+		}
+		set.applyCompanionCredentials()
+
+		source = describeTargetSource(filePaths)
+		targets := set.targets
+		if len(tm.onlyTags) > 0 {
+			targets = FilterTargetsByTags(targets, tm.onlyTags)
+			source += " (tags: " + strings.Join(tm.onlyTags, ", ") + ")"
 		}
-		source = cliFile
-		tm.logger.Info().Int("count", len(targets)).Str("source", source).Msg("Loaded targets from command-line file")
+		selected := len(targets)
+		if tm.sampling.Enabled() {
+			targets = SampleTargets(targets, tm.sampling)
+			source += " (" + describeSample(len(targets), selected, tm.sampling.Seed) + ")"
+		}
+		tm.logger.Info().
+			Int("count", len(targets)).
+			Int("files", len(filePaths)).
+			Int("duplicates_removed", set.duplicates).
+			Int("filtered_by_tags", len(set.targets)-selected).
+			Int("excluded_by_sampling", selected-len(targets)).
+			Int("authenticated_targets", set.authenticated()).
+			Str("source", source).
+			Msg("Loaded targets from command-line file")
 		return targets, source, nil
 	}
 
 	// No input source available
 	tm.logger.Warn().Msg("No input source configured for targets")
 	source = "no_input"
+	return nil, source, errorwrapper.NewError("no valid URLs found in source: %s", source)
+}
+
+// ResolveTargetPaths splits a comma-separated list of target files and expands glob
+// patterns. Duplicate paths are dropped; a pattern that matches nothing is an error.
+func ResolveTargetPaths(spec string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		matches := []string{entry}
+		if entry != StdinTargetsPath && strings.ContainsAny(entry, "*?[") {
+			globMatches, err := filepath.Glob(entry)
+			if err != nil {
+				return nil, errorwrapper.WrapError(err, "invalid target file pattern '"+entry+"'")
+			}
+			if len(globMatches) == 0 {
+				return nil, errorwrapper.NewError("target file pattern '%s' matched no files", entry)
+			}
+			matches = globMatches
+		}
+
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				paths = append(paths, match)
+			}
+		}
+	}
 
-	// Validate that we have targets
-	if len(targets) == 0 {
-		return nil, source, errorwrapper.NewError("no valid URLs found in source: %s", source)
+	if len(paths) == 0 {
+		return nil, errorwrapper.NewError("no target files given in '%s'", spec)
+	}
+	return paths, nil
+}
+
+// describeTargetSource names the target source for summaries and notifications
+func describeTargetSource(filePaths []string) string {
+	names := make([]string, len(filePaths))
+	for i, filePath := range filePaths {
+		names[i] = filePath
+		if filePath == StdinTargetsPath {
+			names[i] = StdinTargetSource
+		}
+	}
+
+	if len(names) == 1 {
+		return names[0]
+	}
+	return fmt.Sprintf("%d sources: %s", len(names), strings.Join(names, ", "))
+}
+
+// targetSet collects targets in first-seen order, counting duplicates
+type targetSet struct {
+	targets    []Target
+	seen       map[string]int // URL -> index in targets
+	duplicates int
+	companion  TargetCredentials
+}
+
+func newTargetSet() *targetSet {
+	return &targetSet{seen: make(map[string]int)}
+}
+
+// add appends a target; a duplicate adds its tags and only contributes its credentials and timeout if the
+// first occurrence had none
+func (ts *targetSet) add(url string, auth *TargetAuth, tags []string, timeout time.Duration) {
+	if index, ok := ts.seen[url]; ok {
+		ts.duplicates++
+		if ts.targets[index].Auth == nil {
+			ts.targets[index].Auth = auth
+		}
+		if ts.targets[index].Timeout == 0 {
+			ts.targets[index].Timeout = timeout
+		}
+		ts.targets[index].Tags = MergeTags(ts.targets[index].Tags, tags)
+		return
+	}
+	ts.seen[url] = len(ts.targets)
+	ts.targets = append(ts.targets, Target{URL: url, Auth: auth, Tags: MergeTags(nil, tags), Timeout: timeout})
+}
+
+// applyCompanionCredentials gives targets without inline credentials the matching companion file entry
+func (ts *targetSet) applyCompanionCredentials() {
+	if ts.companion.Len() == 0 {
+		return
+	}
+	for i := range ts.targets {
+		if ts.targets[i].Auth != nil {
+			continue
+		}
+		if auth, ok := ts.companion.ForURLString(ts.targets[i].URL); ok {
+			ts.targets[i].Auth = &auth
+		}
 	}
+}
 
-	return targets, source, nil
+// authenticated counts the targets that carry credentials
+func (ts *targetSet) authenticated() int {
+	count := 0
+	for _, target := range ts.targets {
+		if target.Auth != nil {
+			count++
+		}
+	}
+	return count
 }
 
-func (tm *TargetManager) getTargetsFromFile(filePath string) ([]Target, error) {
+func (tm *TargetManager) getTargetsFromFile(filePath string, set *targetSet) error {
+	if filePath == StdinTargetsPath {
+		return tm.getTargetsFromStdin(set)
+	}
+
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	defer file.Close()
+
+	if err := tm.readTargets(file, set); err != nil {
+		return err
+	}
+	return tm.readCompanionCredentials(filePath+TargetAuthCompanionSuffix, set)
+}
+
+// readCompanionCredentials loads "URL|auth=..." lines from a target file's companion credentials file, if present.
+// Entries only supply credentials; they do not add targets.
+func (tm *TargetManager) readCompanionCredentials(path string, set *targetSet) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errorwrapper.WrapError(err, "failed to open target credentials file '"+path+"'")
 	}
 	defer file.Close()
 
-	var targets []Target
 	scanner := bufio.NewScanner(file)
+	loaded := 0
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		target, auth, err := splitTargetAuth(line)
+		if err == nil && auth == nil {
+			err = errorwrapper.NewError("missing %sSCHEME:... annotation", TargetAuthAnnotation)
+		}
+		var normalizedURL string
+		if err == nil {
+			normalizedURL, err = NormalizeURL(target)
+		}
+		if err != nil {
+			tm.logger.Warn().Str("file", path).Int("line", lineNumber).Err(err).Msg("Invalid target credentials entry, skipping")
+			continue
+		}
+
+		set.companion.Add(normalizedURL, *auth)
+		loaded++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	tm.logger.Info().Str("file", path).Int("entries", loaded).Msg("Loaded target credentials file")
+	return nil
+}
+
+// LoadTargetsFromReader reads one target per line, expanding CIDR ranges and wildcard domains,
+// normalizing URLs and dropping duplicates while keeping first-seen order.
+// Inline "|auth=", "|tags=" and "|timeout=" annotations are stripped from the URL and kept on Target.Auth,
+// Target.Tags and Target.Timeout.
+func (tm *TargetManager) LoadTargetsFromReader(reader io.Reader) ([]Target, error) {
+	set := newTargetSet()
+	if err := tm.readTargets(reader, set); err != nil {
+		return nil, err
+	}
+	return set.targets, nil
+}
+
+// readTargets adds the targets read from reader to set
+func (tm *TargetManager) readTargets(reader io.Reader, set *targetSet) error {
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
-		url := scanner.Text()
+		line, timeout, err := splitTargetTimeout(scanner.Text())
+		if err != nil {
+			tm.logger.Warn().Str("url", line).Err(err).Msg("Invalid target timeout annotation, skipping target")
+			continue
+		}
+		line, tags := splitTargetTags(line)
+		url, auth, err := splitTargetAuth(line)
+		if err != nil {
+			tm.logger.Warn().Str("url", url).Err(err).Msg("Invalid target auth annotation, skipping target")
+			continue
+		}
+
+		if scheme, apex, ok := parseWildcardTarget(strings.TrimSpace(url)); ok {
+			if !tm.expansionConfig.Wildcards.Enabled {
+				tm.logger.Warn().Str("target", strings.TrimSpace(url)).Msg("Wildcard target needs target_expansion.wildcards.enabled (or --expand-wildcards), skipping")
+				continue
+			}
+			expanded, err := tm.wildcards.expand(scheme, apex, tm.expansionConfig)
+			if err != nil {
+				return err
+			}
+			tm.logger.Info().Str("wildcard", "*."+apex).Int("count", len(expanded)).Msg("Expanded wildcard domain into targets")
+			for _, expandedURL := range expanded {
+				set.add(expandedURL, auth, tags, timeout)
+			}
+			continue
+		}
+
+		if prefix, ok := parseCIDRTarget(strings.TrimSpace(url)); ok {
+			expanded, err := ExpandCIDR(prefix, tm.expansionConfig)
+			if err != nil {
+				return err
+			}
+			tm.logger.Info().Str("cidr", prefix.String()).Int("count", len(expanded)).Msg("Expanded CIDR range into targets")
+			for _, expandedURL := range expanded {
+				set.add(expandedURL, auth, tags, timeout)
+			}
+			continue
+		}
+
 		normalizedURL, err := NormalizeURL(url)
 		if err != nil {
 			tm.logger.Warn().Str("url", url).Err(err).Msg("Failed to normalize URL, skipping")
 			continue
 		}
-		targets = append(targets, Target{URL: normalizedURL})
+		set.add(normalizedURL, auth, tags, timeout)
 	}
-	return targets, scanner.Err()
+	return scanner.Err()
 }
 
 // GetTargetStrings extracts URL strings from Target objects
@@ -0,0 +1,151 @@
+package urlhandler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetManager_LoadTargetsFromReader_NormalizesAndDedupes(t *testing.T) {
+	input := strings.Join([]string{
+		"https://example.com",
+		"example.com",
+		"https://example.com/",
+		"https://EXAMPLE.com",
+		"",
+		"https://other.example.com/path#frag",
+		"https://other.example.com/path",
+	}, "\n")
+
+	tm := NewTargetManager(zerolog.Nop())
+	targets, err := tm.LoadTargetsFromReader(strings.NewReader(input))
+	require.NoError(t, err)
+
+	urls := tm.GetTargetStrings(targets)
+	assert.Equal(t, len(urls), len(uniqueStrings(urls)), "targets should be deduplicated: %v", urls)
+	assert.Equal(t, "https://example.com", urls[0])
+	assert.Contains(t, urls, "https://other.example.com/path")
+}
+
+func TestTargetManager_LoadTargetsFromReader_Empty(t *testing.T) {
+	targets, err := NewTargetManager(zerolog.Nop()).LoadTargetsFromReader(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, targets)
+}
+
+func TestTargetManager_LoadAndSelectTargets_MergesMultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("https://a.example.com\nhttps://shared.example.com\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("https://shared.example.com\nhttps://b.example.com\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.list"), []byte("https://c.example.com\n"), 0644))
+
+	tm := NewTargetManager(zerolog.Nop())
+	spec := filepath.Join(dir, "*.txt") + "," + filepath.Join(dir, "c.list") + "," + filepath.Join(dir, "a.txt")
+	targets, source, err := tm.LoadAndSelectTargets(spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"https://a.example.com",
+		"https://shared.example.com",
+		"https://b.example.com",
+		"https://c.example.com",
+	}, tm.GetTargetStrings(targets))
+	assert.True(t, strings.HasPrefix(source, "3 sources: "), source)
+}
+
+func TestResolveTargetPaths(t *testing.T) {
+	paths, err := ResolveTargetPaths(" one.txt ,two.txt,one.txt,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one.txt", "two.txt"}, paths)
+
+	_, err = ResolveTargetPaths(filepath.Join(t.TempDir(), "*.txt"))
+	assert.Error(t, err)
+
+	_, err = ResolveTargetPaths(" , ")
+	assert.Error(t, err)
+}
+
+func uniqueStrings(values []string) map[string]bool {
+	unique := make(map[string]bool)
+	for _, v := range values {
+		unique[v] = true
+	}
+	return unique
+}
+
+func TestTargetManager_LoadTargetsFromReader_InlineAuth(t *testing.T) {
+	input := strings.Join([]string{
+		"https://api.example.com/v1|auth=bearer:token-a",
+		"https://api.example.com/v1",
+		"https://www.example.com",
+		"https://www.example.com|auth=basic:alice:secret",
+		"https://bad.example.com|auth=digest:secret",
+	}, "\n")
+
+	tm := NewTargetManager(zerolog.Nop())
+	targets, err := tm.LoadTargetsFromReader(strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://api.example.com/v1", "https://www.example.com"}, tm.GetTargetStrings(targets))
+	require.NotNil(t, targets[0].Auth)
+	assert.Equal(t, "token-a", targets[0].Auth.Token)
+	require.NotNil(t, targets[1].Auth, "a duplicate supplies credentials the first occurrence lacked")
+	assert.Equal(t, "alice", targets[1].Auth.Username)
+}
+
+func TestTargetManager_LoadAndSelectTargets_CompanionCredentials(t *testing.T) {
+	dir := t.TempDir()
+	targetsFile := filepath.Join(dir, "targets.txt")
+	require.NoError(t, os.WriteFile(targetsFile, []byte("https://api.example.com/v1\nhttps://www.example.com\nhttps://inline.example.com|auth=bearer:inline\n"), 0644))
+	require.NoError(t, os.WriteFile(targetsFile+TargetAuthCompanionSuffix, []byte(strings.Join([]string{
+		"# API credentials",
+		"https://api.example.com|auth=bearer:companion",
+		"https://inline.example.com|auth=bearer:ignored",
+		"https://extra.example.com|auth=bearer:not-a-target",
+		"https://missing-annotation.example.com",
+	}, "\n")), 0600))
+
+	tm := NewTargetManager(zerolog.Nop())
+	targets, _, err := tm.LoadAndSelectTargets(targetsFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://api.example.com/v1", "https://www.example.com", "https://inline.example.com"}, tm.GetTargetStrings(targets))
+	require.NotNil(t, targets[0].Auth)
+	assert.Equal(t, "companion", targets[0].Auth.Token)
+	assert.Nil(t, targets[1].Auth)
+	assert.Equal(t, "inline", targets[2].Auth.Token, "inline annotations take precedence")
+
+	creds := NewTargetCredentials(targets)
+	assert.Equal(t, 2, creds.Len())
+}
+
+func TestTargetManager_LoadAndSelectTargets_TagFilter(t *testing.T) {
+	targetsFile := filepath.Join(t.TempDir(), "targets.txt")
+	require.NoError(t, os.WriteFile(targetsFile, []byte(strings.Join([]string{
+		"https://pay.example.com|tags=prod,payments",
+		"https://api.example.com|auth=bearer:token|tags=Prod",
+		"https://staging.example.com|tags=staging",
+		"https://pay.example.com|tags=pci",
+		"https://untagged.example.com",
+	}, "\n")), 0644))
+
+	tm := NewTargetManager(zerolog.Nop())
+	targets, _, err := tm.LoadAndSelectTargets(targetsFile)
+	require.NoError(t, err)
+	require.Len(t, targets, 4)
+	assert.Equal(t, []string{"prod", "payments", "pci"}, targets[0].Tags, "duplicates add their tags")
+	assert.Equal(t, []string{"prod"}, targets[1].Tags)
+	require.NotNil(t, targets[1].Auth)
+	assert.Equal(t, "token", targets[1].Auth.Token)
+
+	tm = NewTargetManager(zerolog.Nop()).WithTagFilter([]string{"payments", "staging"})
+	targets, source, err := tm.LoadAndSelectTargets(targetsFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://pay.example.com", "https://staging.example.com"}, tm.GetTargetStrings(targets))
+	assert.Equal(t, targetsFile+" (tags: payments, staging)", source)
+}
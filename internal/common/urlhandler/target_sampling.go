@@ -0,0 +1,89 @@
+package urlhandler
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// TargetSamplingConfig reduces a large target set to a sample before scanning. It is built from
+// config.TargetSamplingConfig.
+type TargetSamplingConfig struct {
+	// Keep at most this many targets; 0 disables. Takes precedence over Percent
+	Count int
+	// Keep this percentage of the targets, rounded up to at least one; 0 disables
+	Percent float64
+	// Seed of the selection; the same seed and target list always yield the same sample
+	Seed int64
+}
+
+// DefaultTargetSamplingConfig returns default configuration (sampling disabled)
+func DefaultTargetSamplingConfig() TargetSamplingConfig {
+	return TargetSamplingConfig{
+		Count:   0,
+		Percent: 0,
+		Seed:    0,
+	}
+}
+
+// Enabled reports whether a sample size is configured
+func (c TargetSamplingConfig) Enabled() bool {
+	return c.Count > 0 || c.Percent > 0
+}
+
+// SampleSize returns how many of total targets the sample keeps
+func (c TargetSamplingConfig) SampleSize(total int) int {
+	size := total
+	switch {
+	case c.Count > 0:
+		size = c.Count
+	case c.Percent > 0:
+		size = int(math.Ceil(float64(total) * c.Percent / 100))
+	}
+	return max(min(size, total), 0)
+}
+
+// SampleTargets returns the sample of targets selected by cfg, in their original order. Each target is
+// ranked by a hash of the seed and its URL, so the sample does not depend on the order of the target
+// files, and adding or removing a few targets leaves the rest of the sample unchanged.
+func SampleTargets(targets []Target, cfg TargetSamplingConfig) []Target {
+	size := cfg.SampleSize(len(targets))
+	if !cfg.Enabled() || size >= len(targets) {
+		return targets
+	}
+
+	ranks := make([]uint64, len(targets))
+	order := make([]int, len(targets))
+	for i, target := range targets {
+		ranks[i] = sampleRank(cfg.Seed, target.URL)
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if ranks[order[i]] != ranks[order[j]] {
+			return ranks[order[i]] < ranks[order[j]]
+		}
+		return order[i] < order[j]
+	})
+
+	selected := order[:size]
+	sort.Ints(selected)
+	sample := make([]Target, 0, size)
+	for _, index := range selected {
+		sample = append(sample, targets[index])
+	}
+	return sample
+}
+
+// sampleRank hashes the seed and a target URL into the target's position in the sampling order
+func sampleRank(seed int64, url string) uint64 {
+	input := binary.LittleEndian.AppendUint64(nil, uint64(seed))
+	hash := sha256.Sum256(append(input, url...))
+	return binary.BigEndian.Uint64(hash[:8])
+}
+
+// describeSample names a sampled run for the target source, e.g. "sample 1000 of 100000, seed 42"
+func describeSample(kept, total int, seed int64) string {
+	return fmt.Sprintf("sample %d of %d, seed %d", kept, total, seed)
+}
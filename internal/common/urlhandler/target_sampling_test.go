@@ -0,0 +1,71 @@
+package urlhandler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func samplingTargets(n int) []Target {
+	targets := make([]Target, n)
+	for i := range targets {
+		targets[i] = Target{URL: fmt.Sprintf("https://host%d.example.com", i)}
+	}
+	return targets
+}
+
+func TestTargetSamplingConfig_SampleSize(t *testing.T) {
+	assert.Equal(t, 100, TargetSamplingConfig{}.SampleSize(100))
+	assert.Equal(t, 10, TargetSamplingConfig{Count: 10}.SampleSize(100))
+	assert.Equal(t, 5, TargetSamplingConfig{Count: 10}.SampleSize(5))
+	assert.Equal(t, 2, TargetSamplingConfig{Percent: 1.5}.SampleSize(100), "rounded up")
+	assert.Equal(t, 1, TargetSamplingConfig{Percent: 0.1}.SampleSize(10), "at least one target")
+}
+
+func TestSampleTargets_DeterministicAndOrdered(t *testing.T) {
+	targets := samplingTargets(200)
+	cfg := TargetSamplingConfig{Count: 20, Seed: 42}
+
+	sample := SampleTargets(targets, cfg)
+	require.Len(t, sample, 20)
+	assert.Equal(t, sample, SampleTargets(targets, cfg), "same seed, same sample")
+	assert.NotEqual(t, sample, SampleTargets(targets, TargetSamplingConfig{Count: 20, Seed: 7}))
+
+	index := make(map[string]int, len(targets))
+	for i, target := range targets {
+		index[target.URL] = i
+	}
+	for i := 1; i < len(sample); i++ {
+		assert.Less(t, index[sample[i-1].URL], index[sample[i].URL], "sample keeps the original order")
+	}
+
+	reversed := make([]Target, len(targets))
+	for i, target := range targets {
+		reversed[len(targets)-1-i] = target
+	}
+	assert.ElementsMatch(t, sample, SampleTargets(reversed, cfg), "the sample does not depend on file order")
+
+	assert.Len(t, SampleTargets(targets, TargetSamplingConfig{Percent: 10, Seed: 42}), 20)
+	assert.Len(t, SampleTargets(targets, TargetSamplingConfig{}), 200)
+}
+
+func TestTargetManager_LoadAndSelectTargets_Sampling(t *testing.T) {
+	lines := make([]string, 50)
+	for i, target := range samplingTargets(50) {
+		lines[i] = target.URL
+	}
+	file := filepath.Join(t.TempDir(), "targets.txt")
+	require.NoError(t, os.WriteFile(file, []byte(strings.Join(lines, "\n")), 0o600))
+
+	tm := NewTargetManager(zerolog.Nop()).WithSampling(TargetSamplingConfig{Count: 5, Seed: 3})
+	targets, source, err := tm.LoadAndSelectTargets(file)
+	require.NoError(t, err)
+	assert.Len(t, targets, 5)
+	assert.Equal(t, file+" (sample 5 of 50, seed 3)", source)
+}
@@ -0,0 +1,123 @@
+package urlhandler
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// TargetTagsAnnotation separates a target URL from its tags: https://pay.example.com|tags=prod,payments.
+// It may be combined with an auth annotation in either order.
+const TargetTagsAnnotation = "|tags="
+
+// ParseTags splits a comma-separated tag list into lower-cased, deduplicated tags, dropping empty entries
+func ParseTags(spec string) []string {
+	var tags []string
+	for _, tag := range strings.Split(spec, ",") {
+		tags = appendTag(tags, tag)
+	}
+	return tags
+}
+
+// MergeTags adds the tags of other missing from tags
+func MergeTags(tags, other []string) []string {
+	for _, tag := range other {
+		tags = appendTag(tags, tag)
+	}
+	return tags
+}
+
+// appendTag normalizes tag and appends it unless it is empty or already present
+func appendTag(tags []string, tag string) []string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || slices.Contains(tags, tag) {
+		return tags
+	}
+	return append(tags, tag)
+}
+
+// HasAnyTag reports whether the target carries at least one of tags
+func (t Target) HasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(t.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterTargetsByTags keeps the targets carrying at least one of tags; no tags keeps every target
+func FilterTargetsByTags(targets []Target, tags []string) []Target {
+	if len(tags) == 0 {
+		return targets
+	}
+
+	var filtered []Target
+	for _, target := range targets {
+		if target.HasAnyTag(tags) {
+			filtered = append(filtered, target)
+		}
+	}
+	return filtered
+}
+
+// splitTargetTags strips an inline tags annotation from a target line, wherever it appears after the URL
+func splitTargetTags(line string) (string, []string) {
+	index := strings.LastIndex(line, TargetTagsAnnotation)
+	if index < 0 {
+		return line, nil
+	}
+
+	rest := line[index+len(TargetTagsAnnotation):]
+	value, remainder := rest, ""
+	if end := strings.IndexByte(rest, '|'); end >= 0 {
+		value, remainder = rest[:end], rest[end:]
+	}
+	return line[:index] + remainder, ParseTags(value)
+}
+
+// TargetTags maps URLs to the tags of the target they belong to. The zero value holds none.
+type TargetTags struct {
+	entries []tagEntry
+}
+
+type tagEntry struct {
+	scope targetScope
+	tags  []string
+}
+
+// NewTargetTags collects the tags of the targets that have any
+func NewTargetTags(targets []Target) TargetTags {
+	var tt TargetTags
+	for _, target := range targets {
+		if len(target.Tags) == 0 {
+			continue
+		}
+		if scope, ok := parseTargetScope(target.URL); ok {
+			tt.entries = append(tt.entries, tagEntry{scope: scope, tags: target.Tags})
+		}
+	}
+	return tt
+}
+
+// Len returns the number of tagged targets
+func (tt TargetTags) Len() int {
+	return len(tt.entries)
+}
+
+// ForURLString returns the tags of the target covering rawURL, matched like TargetCredentials.ForURL
+func (tt TargetTags) ForURLString(rawURL string) []string {
+	if len(tt.entries) == 0 {
+		return nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+
+	index := matchTargetScope(len(tt.entries), func(i int) targetScope { return tt.entries[i].scope }, parsed)
+	if index < 0 {
+		return nil
+	}
+	return tt.entries[index].tags
+}
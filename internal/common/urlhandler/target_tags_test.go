@@ -0,0 +1,52 @@
+package urlhandler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	assert.Equal(t, []string{"prod", "payments"}, ParseTags(" Prod,payments,,PROD "))
+	assert.Nil(t, ParseTags(""))
+}
+
+func TestSplitTargetTags(t *testing.T) {
+	line, tags := splitTargetTags("https://pay.example.com|tags=prod,payments")
+	assert.Equal(t, "https://pay.example.com", line)
+	assert.Equal(t, []string{"prod", "payments"}, tags)
+
+	line, tags = splitTargetTags("https://api.example.com|tags=staging|auth=bearer:token")
+	assert.Equal(t, "https://api.example.com|auth=bearer:token", line)
+	assert.Equal(t, []string{"staging"}, tags)
+
+	line, tags = splitTargetTags("https://plain.example.com")
+	assert.Equal(t, "https://plain.example.com", line)
+	assert.Nil(t, tags)
+}
+
+func TestTargetTags_ForURLString(t *testing.T) {
+	tags := NewTargetTags([]Target{
+		{URL: "https://example.com", Tags: []string{"prod"}},
+		{URL: "https://example.com/payments", Tags: []string{"payments"}},
+		{URL: "https://untagged.example.com"},
+	})
+
+	assert.Equal(t, 2, tags.Len())
+	assert.Equal(t, []string{"payments"}, tags.ForURLString("https://example.com/payments/checkout"))
+	assert.Equal(t, []string{"prod"}, tags.ForURLString("https://example.com/about"))
+	assert.Nil(t, tags.ForURLString("https://untagged.example.com/"))
+}
+
+func TestFilterTargetsByTags(t *testing.T) {
+	targets := []Target{
+		{URL: "https://a.example.com", Tags: []string{"prod", "payments"}},
+		{URL: "https://b.example.com", Tags: []string{"staging"}},
+		{URL: "https://c.example.com"},
+	}
+
+	assert.Len(t, FilterTargetsByTags(targets, nil), 3)
+	filtered := FilterTargetsByTags(targets, []string{"payments", "staging"})
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, []string{filtered[0].URL, filtered[1].URL})
+	assert.Empty(t, FilterTargetsByTags(targets, []string{"dev"}))
+}
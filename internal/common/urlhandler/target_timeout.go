@@ -0,0 +1,105 @@
+package urlhandler
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+)
+
+// TargetTimeoutAnnotation separates a target URL from a request timeout that replaces the crawler and httpx
+// timeouts for the target's requests: https://slow.internal.example.com|timeout=60. The value is in seconds,
+// or a duration such as 1m30s. It may be combined with auth and tags annotations in any order.
+const TargetTimeoutAnnotation = "|timeout="
+
+// ParseTargetTimeout parses the value of a timeout annotation
+func ParseTargetTimeout(spec string) (time.Duration, error) {
+	spec = strings.TrimSpace(spec)
+	var timeout time.Duration
+	if secs, err := strconv.Atoi(spec); err == nil {
+		timeout = time.Duration(secs) * time.Second
+	} else if timeout, err = time.ParseDuration(spec); err != nil {
+		return 0, errorwrapper.NewError("invalid target timeout '%s' (use seconds or a duration such as 1m30s)", spec)
+	}
+	if timeout < time.Second {
+		return 0, errorwrapper.NewError("target timeout '%s' must be at least 1s", spec)
+	}
+	return timeout, nil
+}
+
+// splitTargetTimeout strips an inline timeout annotation from a target line, wherever it appears after the URL
+func splitTargetTimeout(line string) (string, time.Duration, error) {
+	index := strings.LastIndex(line, TargetTimeoutAnnotation)
+	if index < 0 {
+		return line, 0, nil
+	}
+
+	rest := line[index+len(TargetTimeoutAnnotation):]
+	value, remainder := rest, ""
+	if end := strings.IndexByte(rest, '|'); end >= 0 {
+		value, remainder = rest[:end], rest[end:]
+	}
+	timeout, err := ParseTargetTimeout(value)
+	return line[:index] + remainder, timeout, err
+}
+
+// TargetTimeouts maps URLs to the request timeout of the target they belong to. The zero value holds none.
+type TargetTimeouts struct {
+	entries []timeoutEntry
+}
+
+type timeoutEntry struct {
+	scope   targetScope
+	timeout time.Duration
+}
+
+// NewTargetTimeouts collects the timeouts of the targets that have one
+func NewTargetTimeouts(targets []Target) TargetTimeouts {
+	var tt TargetTimeouts
+	for _, target := range targets {
+		if target.Timeout <= 0 {
+			continue
+		}
+		if scope, ok := parseTargetScope(target.URL); ok {
+			tt.entries = append(tt.entries, timeoutEntry{scope: scope, timeout: target.Timeout})
+		}
+	}
+	return tt
+}
+
+// Len returns the number of targets with a timeout
+func (tt TargetTimeouts) Len() int {
+	return len(tt.entries)
+}
+
+// Max returns the longest timeout, or zero when there is none
+func (tt TargetTimeouts) Max() time.Duration {
+	var longest time.Duration
+	for _, entry := range tt.entries {
+		longest = max(longest, entry.timeout)
+	}
+	return longest
+}
+
+// ForURL returns the timeout of the target covering u, matched like TargetCredentials.ForURL
+func (tt TargetTimeouts) ForURL(u *url.URL) (time.Duration, bool) {
+	index := matchTargetScope(len(tt.entries), func(i int) targetScope { return tt.entries[i].scope }, u)
+	if index < 0 {
+		return 0, false
+	}
+	return tt.entries[index].timeout, true
+}
+
+// ForURLString parses rawURL and returns its timeout
+func (tt TargetTimeouts) ForURLString(rawURL string) (time.Duration, bool) {
+	if len(tt.entries) == 0 {
+		return 0, false
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, false
+	}
+	return tt.ForURL(parsed)
+}
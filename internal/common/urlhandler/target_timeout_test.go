@@ -0,0 +1,77 @@
+package urlhandler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTargetTimeout(t *testing.T) {
+	timeout, err := ParseTargetTimeout(" 60 ")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, timeout)
+
+	timeout, err = ParseTargetTimeout("1m30s")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, timeout)
+
+	for _, spec := range []string{"", "soon", "0", "-5", "500ms"} {
+		_, err := ParseTargetTimeout(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSplitTargetTimeout(t *testing.T) {
+	line, timeout, err := splitTargetTimeout("https://slow.example.com|timeout=60|tags=internal")
+	require.NoError(t, err)
+	assert.Equal(t, "https://slow.example.com|tags=internal", line)
+	assert.Equal(t, time.Minute, timeout)
+
+	line, timeout, err = splitTargetTimeout("https://plain.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "https://plain.example.com", line)
+	assert.Zero(t, timeout)
+
+	_, _, err = splitTargetTimeout("https://bad.example.com|timeout=later")
+	assert.Error(t, err)
+}
+
+func TestTargetTimeouts_ForURLString(t *testing.T) {
+	timeouts := NewTargetTimeouts([]Target{
+		{URL: "https://example.com", Timeout: 10 * time.Second},
+		{URL: "https://example.com/reports", Timeout: time.Minute},
+		{URL: "https://default.example.com"},
+	})
+
+	assert.Equal(t, 2, timeouts.Len())
+	assert.Equal(t, time.Minute, timeouts.Max())
+
+	timeout, ok := timeouts.ForURLString("https://example.com/reports/2024")
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, timeout)
+	timeout, ok = timeouts.ForURLString("https://example.com/about")
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, timeout)
+	_, ok = timeouts.ForURLString("https://default.example.com/")
+	assert.False(t, ok)
+}
+
+func TestLoadTargetsFromReader_TimeoutAnnotation(t *testing.T) {
+	input := strings.Join([]string{
+		"https://slow.internal.example.com|timeout=60|tags=internal",
+		"https://fast.example.com",
+		"https://broken.example.com|timeout=never",
+	}, "\n")
+
+	targets, err := NewTargetManager(zerolog.Nop()).LoadTargetsFromReader(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, targets, 2, "a target with an invalid timeout is skipped")
+	assert.Equal(t, "https://slow.internal.example.com", targets[0].URL)
+	assert.Equal(t, time.Minute, targets[0].Timeout)
+	assert.Equal(t, []string{"internal"}, targets[0].Tags)
+	assert.Zero(t, targets[1].Timeout)
+}
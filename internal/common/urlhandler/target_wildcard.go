@@ -0,0 +1,316 @@
+package urlhandler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/rs/zerolog"
+)
+
+// WildcardExpansionConfig configures how "*.example.com" targets are expanded into known subdomains.
+// It is built from config.WildcardExpansionConfig.
+type WildcardExpansionConfig struct {
+	// Expand wildcard targets; when disabled they are skipped with a warning
+	Enabled bool
+	// File of known hostnames, one per line (e.g. subfinder or amass output); URLs and "*." prefixes are accepted
+	SourceFile string
+	// Look up hostnames in certificate transparency logs through a crt.sh-compatible endpoint
+	CertTransparency bool
+	// Base URL of the crt.sh-compatible endpoint
+	CTEndpoint string
+	// Timeout of one certificate transparency lookup
+	CTTimeoutSecs int
+	// Maximum number of hosts a single wildcard may expand to, apex included; 0 uses the default
+	MaxSubdomains int
+	// Proxy for the certificate transparency lookup
+	Proxy httpclient.ProxyConfig
+	// Crawler scope exclusions; expanded hosts they rule out are dropped instead of becoming targets of their own
+	DisallowedHostnames  []string
+	DisallowedSubdomains []string
+}
+
+// DefaultWildcardExpansionConfig returns default configuration (expansion disabled)
+func DefaultWildcardExpansionConfig() WildcardExpansionConfig {
+	return WildcardExpansionConfig{
+		Enabled:          false,
+		SourceFile:       "",
+		CertTransparency: false,
+		CTEndpoint:       "https://crt.sh",
+		CTTimeoutSecs:    30,
+		MaxSubdomains:    1000,
+	}
+}
+
+// SubdomainSource returns hostnames known for a domain. Results may include unrelated or malformed
+// names; the expander keeps only valid hostnames under the apex.
+type SubdomainSource interface {
+	Name() string
+	Subdomains(apex string) ([]string, error)
+}
+
+// parseWildcardTarget reports whether a target line is a wildcard such as "*.example.com" or
+// "https://*.example.com", returning the explicit scheme (empty when none) and the apex domain
+func parseWildcardTarget(line string) (scheme string, apex string, ok bool) {
+	rest := line
+	if before, after, found := strings.Cut(line, "://"); found {
+		scheme, rest = strings.ToLower(before), after
+		if scheme != "http" && scheme != "https" {
+			return "", "", false
+		}
+	}
+
+	rest = strings.TrimSuffix(rest, "/")
+	if !strings.HasPrefix(rest, "*.") {
+		return "", "", false
+	}
+	apex = strings.ToLower(strings.TrimSuffix(rest[2:], "."))
+	if !isHostname(apex) || !strings.Contains(apex, ".") {
+		return "", "", false
+	}
+	return scheme, apex, true
+}
+
+// isHostname reports whether name is a plain DNS name without wildcards, ports or paths
+func isHostname(name string) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cleanHostname reduces a source entry ("*.a.example.com", "https://a.example.com:8443/x") to its hostname
+func cleanHostname(entry string) string {
+	entry = strings.ToLower(strings.TrimSpace(entry))
+	if strings.Contains(entry, "://") {
+		if u, err := url.Parse(entry); err == nil {
+			entry = u.Hostname()
+		}
+	} else if host, _, err := net.SplitHostPort(entry); err == nil {
+		entry = host
+	}
+	entry = strings.TrimPrefix(entry, "*.")
+	return strings.TrimSuffix(entry, ".")
+}
+
+// wildcardExpander resolves wildcard targets through the configured subdomain sources
+type wildcardExpander struct {
+	config  WildcardExpansionConfig
+	sources []SubdomainSource
+	logger  zerolog.Logger
+}
+
+// newWildcardExpander creates the sources enabled in cfg
+func newWildcardExpander(cfg WildcardExpansionConfig, logger zerolog.Logger) *wildcardExpander {
+	we := &wildcardExpander{config: cfg, logger: logger}
+	if cfg.SourceFile != "" {
+		we.sources = append(we.sources, fileSubdomainSource{path: cfg.SourceFile})
+	}
+	if cfg.CertTransparency {
+		source, err := newCTSubdomainSource(cfg, logger)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Certificate transparency source unavailable, skipping it")
+		} else {
+			we.sources = append(we.sources, source)
+		}
+	}
+	return we
+}
+
+// inScope reports whether the crawler scope would accept host: it is not a disallowed hostname or one
+// of its subdomains, and its subdomain part is not a disallowed subdomain. Seeds are always crawled, so
+// an expanded host the scope excludes has to be dropped here.
+func (we *wildcardExpander) inScope(host string) bool {
+	for _, disallowed := range we.config.DisallowedHostnames {
+		if host == disallowed || strings.HasSuffix(host, "."+disallowed) {
+			return false
+		}
+	}
+	base, err := GetBaseDomain(host)
+	if err != nil || base == host {
+		return true
+	}
+	return !slices.Contains(we.config.DisallowedSubdomains, strings.TrimSuffix(host, "."+base))
+}
+
+// expand returns the URLs of the apex and every known subdomain under it. A failing source is
+// logged and skipped, so one unreachable source does not lose the others' results.
+func (we *wildcardExpander) expand(scheme, apex string, expansion TargetExpansionConfig) ([]string, error) {
+	if len(we.sources) == 0 {
+		return nil, errorwrapper.NewError("wildcard *.%s needs a subdomain source (source_file or cert_transparency)", apex)
+	}
+
+	hosts := map[string]bool{apex: true}
+	for _, source := range we.sources {
+		names, err := source.Subdomains(apex)
+		if err != nil {
+			we.logger.Warn().Err(err).Str("source", source.Name()).Str("domain", apex).Msg("Subdomain source failed, continuing with the other sources")
+			continue
+		}
+
+		added, outOfScope := 0, 0
+		for _, name := range names {
+			host := cleanHostname(name)
+			if hosts[host] || !strings.HasSuffix(host, "."+apex) || !isHostname(host) {
+				continue
+			}
+			if !we.inScope(host) {
+				outOfScope++
+				continue
+			}
+			hosts[host] = true
+			added++
+		}
+		we.logger.Debug().Str("source", source.Name()).Str("domain", apex).Int("subdomains", added).Int("out_of_scope", outOfScope).Msg("Collected subdomains")
+	}
+
+	sorted := make([]string, 0, len(hosts))
+	for host := range hosts {
+		sorted = append(sorted, host)
+	}
+	sort.Strings(sorted)
+
+	maxHosts := we.config.MaxSubdomains
+	if maxHosts <= 0 {
+		maxHosts = DefaultWildcardExpansionConfig().MaxSubdomains
+	}
+	if len(sorted) > maxHosts {
+		we.logger.Warn().Str("domain", apex).Int("found", len(sorted)).Int("max_subdomains", maxHosts).Msg("Wildcard expands to too many hosts, keeping the first ones")
+		sorted = sorted[:maxHosts]
+	}
+
+	schemes := expansion.Schemes
+	if scheme != "" {
+		schemes = []string{scheme}
+	} else if len(schemes) == 0 {
+		schemes = DefaultTargetExpansionConfig().Schemes
+	}
+
+	var urls []string
+	for _, host := range sorted {
+		urls = append(urls, buildHostnameURLs(host, schemes, expansion.Ports)...)
+	}
+	return urls, nil
+}
+
+// buildHostnameURLs builds the URLs for one hostname
+func buildHostnameURLs(host string, schemes []string, ports []int) []string {
+	var urls []string
+	for _, scheme := range schemes {
+		if len(ports) == 0 {
+			urls = append(urls, scheme+"://"+host)
+			continue
+		}
+		for _, port := range ports {
+			urls = append(urls, scheme+"://"+net.JoinHostPort(host, strconv.Itoa(port)))
+		}
+	}
+	return urls
+}
+
+// fileSubdomainSource reads hostnames from a file; it is re-read on every lookup so
+// scheduled scans pick up an updated file
+type fileSubdomainSource struct {
+	path string
+}
+
+func (fs fileSubdomainSource) Name() string {
+	return "file:" + fs.path
+}
+
+func (fs fileSubdomainSource) Subdomains(string) ([]string, error) {
+	file, err := os.Open(fs.path)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to open subdomain source file '"+fs.path+"'")
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			names = append(names, line)
+		}
+	}
+	return names, scanner.Err()
+}
+
+// ctSubdomainSource queries a crt.sh-compatible endpoint for certificates issued under a domain
+type ctSubdomainSource struct {
+	endpoint string
+	client   *httpclient.HTTPClient
+}
+
+// newCTSubdomainSource creates the lookup client through the proxy-aware factory, so the lookup
+// leaves through proxy_config like every other outbound request
+func newCTSubdomainSource(cfg WildcardExpansionConfig, logger zerolog.Logger) (ctSubdomainSource, error) {
+	endpoint := cfg.CTEndpoint
+	if endpoint == "" {
+		endpoint = DefaultWildcardExpansionConfig().CTEndpoint
+	}
+	timeoutSecs := cfg.CTTimeoutSecs
+	if timeoutSecs <= 0 {
+		timeoutSecs = DefaultWildcardExpansionConfig().CTTimeoutSecs
+	}
+	client, err := httpclient.NewHTTPClientFactory(logger).
+		WithProxyConfig(cfg.Proxy).
+		CreateBasicClient(time.Duration(timeoutSecs) * time.Second)
+	if err != nil {
+		return ctSubdomainSource{}, errorwrapper.WrapError(err, "failed to create certificate transparency client")
+	}
+	return ctSubdomainSource{endpoint: strings.TrimSuffix(endpoint, "/"), client: client}, nil
+}
+
+func (cs ctSubdomainSource) Name() string {
+	return "cert_transparency"
+}
+
+// Subdomains returns the names of every certificate logged for apex or its subdomains.
+// A certificate's name_value lists its names separated by newlines.
+func (cs ctSubdomainSource) Subdomains(apex string) ([]string, error) {
+	query := url.Values{"q": {"%." + apex}, "output": {"json"}}
+	resp, err := cs.client.Do(&httpclient.HTTPRequest{URL: cs.endpoint + "/?" + query.Encode(), Method: http.MethodGet})
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "certificate transparency lookup failed")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorwrapper.NewError("certificate transparency lookup for %s returned status %d", apex, resp.StatusCode)
+	}
+
+	var certificates []struct {
+		CommonName string `json:"common_name"`
+		NameValue  string `json:"name_value"`
+	}
+	if err := json.Unmarshal(resp.Body, &certificates); err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to decode certificate transparency response")
+	}
+
+	var names []string
+	for _, certificate := range certificates {
+		names = append(names, certificate.CommonName)
+		names = append(names, strings.Split(certificate.NameValue, "\n")...)
+	}
+	return names, nil
+}
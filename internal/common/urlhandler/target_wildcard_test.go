@@ -0,0 +1,158 @@
+package urlhandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticSubdomainSource returns fixed names, or fails
+type staticSubdomainSource struct {
+	names []string
+	err   error
+}
+
+func (ss staticSubdomainSource) Name() string { return "static" }
+
+func (ss staticSubdomainSource) Subdomains(string) ([]string, error) { return ss.names, ss.err }
+
+func TestParseWildcardTarget(t *testing.T) {
+	scheme, apex, ok := parseWildcardTarget("*.Example.com")
+	require.True(t, ok)
+	assert.Equal(t, "", scheme)
+	assert.Equal(t, "example.com", apex)
+
+	scheme, apex, ok = parseWildcardTarget("https://*.example.com/")
+	require.True(t, ok)
+	assert.Equal(t, "https", scheme)
+	assert.Equal(t, "example.com", apex)
+
+	for _, line := range []string{"example.com", "https://example.com", "*.com", "ftp://*.example.com", "*.example.com/path", "a.*.example.com"} {
+		_, _, ok := parseWildcardTarget(line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestWildcardExpander_KeepsOnlyHostsUnderApex(t *testing.T) {
+	expander := newWildcardExpander(WildcardExpansionConfig{Enabled: true, MaxSubdomains: 10}, zerolog.Nop())
+	expander.sources = []SubdomainSource{
+		staticSubdomainSource{names: []string{"www.example.com", "*.api.example.com", "https://Shop.Example.com:8443/cart", "evil-example.com", "other.org", "bad host.example.com"}},
+		staticSubdomainSource{err: assert.AnError},
+	}
+
+	urls, err := expander.expand("", "example.com", TargetExpansionConfig{Schemes: []string{"https"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://api.example.com",
+		"https://example.com",
+		"https://shop.example.com",
+		"https://www.example.com",
+	}, urls)
+
+	urls, err = expander.expand("http", "example.com", TargetExpansionConfig{Schemes: []string{"https"}, Ports: []int{8080}})
+	require.NoError(t, err)
+	assert.Contains(t, urls, "http://www.example.com:8080")
+	assert.Len(t, urls, 4)
+
+	expander.config.MaxSubdomains = 2
+	urls, err = expander.expand("", "example.com", TargetExpansionConfig{Schemes: []string{"https"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://api.example.com", "https://example.com"}, urls)
+}
+
+func TestWildcardExpander_RequiresSource(t *testing.T) {
+	expander := newWildcardExpander(WildcardExpansionConfig{Enabled: true}, zerolog.Nop())
+	_, err := expander.expand("", "example.com", DefaultTargetExpansionConfig())
+	assert.Error(t, err)
+}
+
+func TestCTSubdomainSource(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`[{"common_name":"example.com","name_value":"example.com\nwww.example.com"},{"common_name":"*.dev.example.com","name_value":"*.dev.example.com"}]`))
+	}))
+	defer server.Close()
+
+	source, err := newCTSubdomainSource(WildcardExpansionConfig{CTEndpoint: server.URL + "/"}, zerolog.Nop())
+	require.NoError(t, err)
+	names, err := source.Subdomains("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "output=json&q=%25.example.com", query)
+	assert.Equal(t, []string{"example.com", "example.com", "www.example.com", "*.dev.example.com", "*.dev.example.com"}, names)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer failing.Close()
+	source, err = newCTSubdomainSource(WildcardExpansionConfig{CTEndpoint: failing.URL}, zerolog.Nop())
+	require.NoError(t, err)
+	_, err = source.Subdomains("example.com")
+	assert.Error(t, err)
+}
+
+func TestCTSubdomainSource_UsesConfiguredProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.Host
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	source, err := newCTSubdomainSource(WildcardExpansionConfig{
+		CTEndpoint: "http://ct.example.invalid",
+		Proxy:      httpclient.ProxyConfig{URL: proxy.URL, IgnoreEnvironment: true},
+	}, zerolog.Nop())
+	require.NoError(t, err)
+
+	_, err = source.Subdomains("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "ct.example.invalid", proxiedHost)
+}
+
+func TestWildcardExpander_DropsHostsOutOfCrawlerScope(t *testing.T) {
+	expander := newWildcardExpander(WildcardExpansionConfig{
+		Enabled:              true,
+		DisallowedHostnames:  []string{"internal.example.com"},
+		DisallowedSubdomains: []string{"dev"},
+	}, zerolog.Nop())
+	expander.sources = []SubdomainSource{
+		staticSubdomainSource{names: []string{"www.example.com", "internal.example.com", "vpn.internal.example.com", "dev.example.com", "api.dev.example.com"}},
+	}
+
+	urls, err := expander.expand("https", "example.com", TargetExpansionConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://api.dev.example.com", "https://example.com", "https://www.example.com"}, urls,
+		"disallowed subdomains match the whole subdomain part, as in the crawler scope")
+}
+
+func TestTargetManager_ExpandsWildcardTargets(t *testing.T) {
+	dir := t.TempDir()
+	sourceFile := filepath.Join(dir, "subdomains.txt")
+	require.NoError(t, os.WriteFile(sourceFile, []byte("# subfinder output\nwww.example.com\nmail.example.com\nexample.org\n"), 0644))
+
+	cfg := DefaultTargetExpansionConfig()
+	cfg.Schemes = []string{"https"}
+	cfg.Wildcards.Enabled = true
+	cfg.Wildcards.SourceFile = sourceFile
+	tm := NewTargetManager(zerolog.Nop()).WithExpansionConfig(cfg)
+
+	targets, err := tm.LoadTargetsFromReader(strings.NewReader("*.example.com|tags=prod\nhttps://www.example.com\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com", "https://mail.example.com", "https://www.example.com"}, tm.GetTargetStrings(targets))
+	assert.Equal(t, []string{"prod"}, targets[2].Tags)
+
+	// Disabled expansion skips the wildcard instead of scanning a literal "*" host
+	tm = NewTargetManager(zerolog.Nop())
+	targets, err = tm.LoadTargetsFromReader(strings.NewReader("*.example.com\nhttps://example.net\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.net"}, tm.GetTargetStrings(targets))
+}
@@ -0,0 +1,30 @@
+package config
+
+// AdaptiveConcurrencyConfig defines per-host AIMD concurrency control for crawl requests:
+// rate-limit responses cut a host's concurrency, sustained successes raise it back towards max_concurrent_requests
+type AdaptiveConcurrencyConfig struct {
+	// Whether per-host concurrency adapts to rate limiting
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Lowest concurrency a host is reduced to
+	MinConcurrency int `json:"min_concurrency,omitempty" yaml:"min_concurrency,omitempty" validate:"omitempty,min=1"`
+	// Factor the host's concurrency is multiplied by on a rate-limit response (e.g. 0.5 halves it)
+	DecreaseFactor float64 `json:"decrease_factor,omitempty" yaml:"decrease_factor,omitempty" validate:"omitempty,gt=0,lt=1"`
+	// Seconds after a cut during which further rate-limit responses from the host do not cut it again
+	DecreaseCooldownSecs int `json:"decrease_cooldown_secs,omitempty" yaml:"decrease_cooldown_secs,omitempty" validate:"omitempty,min=1"`
+	// Consecutive successful responses needed before the host's concurrency grows by one
+	IncreaseAfter int `json:"increase_after,omitempty" yaml:"increase_after,omitempty" validate:"omitempty,min=1"`
+	// HTTP status codes treated as rate limiting
+	StatusCodes []int `json:"status_codes,omitempty" yaml:"status_codes,omitempty"`
+}
+
+// NewDefaultAdaptiveConcurrencyConfig creates default adaptive concurrency configuration
+func NewDefaultAdaptiveConcurrencyConfig() AdaptiveConcurrencyConfig {
+	return AdaptiveConcurrencyConfig{
+		Enabled:              false,
+		MinConcurrency:       DefaultAdaptiveMinConcurrency,
+		DecreaseFactor:       DefaultAdaptiveDecreaseFactor,
+		DecreaseCooldownSecs: DefaultAdaptiveDecreaseCooldownSecs,
+		IncreaseAfter:        DefaultAdaptiveIncreaseAfter,
+		StatusCodes:          []int{429, 503},
+	}
+}
@@ -10,17 +10,68 @@ const (
 	DefaultCrawlerRequestTimeoutSecs    = 20
 	DefaultCrawlerMaxConcurrentRequests = 10
 	DefaultCrawlerMaxDepth              = 5
+	DefaultCrawlerMaxRedirectsPerChain  = 10  // Matches net/http's default redirect policy
+	DefaultCrawlerMaxRedirectsPerSeed   = 100 // Redirects followed for everything crawled from one seed
+	DefaultCrawlerSitemapMaxURLsPerHost = 10000
+
+	// Adaptive Concurrency Defaults
+	DefaultAdaptiveMinConcurrency       = 1
+	DefaultAdaptiveDecreaseFactor       = 0.5
+	DefaultAdaptiveDecreaseCooldownSecs = 5
+	DefaultAdaptiveIncreaseAfter        = 10
+
+	// User-Agent Defaults
+	DefaultUserAgentRotation = "random"
+
+	// HAR Export Defaults
+	DefaultHARExportOutputDir = "reports/har"
+
+	// Report Browser Defaults
+	DefaultReportBrowserListenAddr = "127.0.0.1:8470"
+	DefaultReportBrowserIndexFile  = "reports/report_index.jsonl"
+	DefaultReportBrowserMaxAgeDays = 30
+
+	// Progress Defaults
+	DefaultProgressIntervalSecs = 60
+	DefaultProgressPercentStep  = 10
+
+	// Crawl State Defaults
+	DefaultCrawlStateSnapshotIntervalSecs = 30
 
 	// Storage Defaults
-	DefaultStorageParquetBasePath  = "database"
-	DefaultStorageCompressionCodec = "zstd"
+	DefaultStorageParquetBasePath       = "database"
+	DefaultStorageCompressionCodec      = "zstd"
+	DefaultStorageRowGroupSize          = 50000      // Rows per row group; keeps per-host files scannable in chunks
+	DefaultStoragePageSize              = 256 * 1024 // Bytes; matches the parquet-go default
+	DefaultStorageResponseBodyMaxSizeKB = 512
+	DefaultContentArchiveDir            = "database/content_archive"
+	DefaultStorageBackend               = StorageBackendLocal
+	DefaultStorageS3Region              = "us-east-1"
+
+	// Target Expansion Defaults
+	DefaultTargetExpansionMaxExpansion = 65536
+	DefaultWildcardCTEndpoint          = "https://crt.sh"
+	DefaultWildcardCTTimeoutSecs       = 30
+	DefaultWildcardMaxSubdomains       = 1000
+
+	// Interrupt Hooks Defaults
+	DefaultInterruptHooksTimeoutSecs = 10
+
+	// URL Lifecycle Defaults
+	DefaultURLLifecycleMaxMissedScans = 0 // Keep unseen URLs forever
+
+	// Storage Retention Defaults
+	DefaultRetentionMaxAgeDays           = 30
+	DefaultRetentionMaxTotalSizeMB       = 0 // No size cap
+	DefaultRetentionCleanupIntervalHours = 24
 
 	// Log Defaults
-	DefaultLogLevel      = "info"
-	DefaultLogFormat     = "console"
-	DefaultLogFile       = ""
-	DefaultMaxLogSizeMB  = 100
-	DefaultMaxLogBackups = 3
+	DefaultLogLevel           = "info"
+	DefaultLogFormat          = "console"
+	DefaultLogFile            = ""
+	DefaultMaxLogSizeMB       = 100
+	DefaultMaxLogBackups      = 3
+	DefaultCompressLogBackups = true
 
 	// Monitor Defaults - using fast path file extensions
 	DefaultMonitorJSFileExtensions   = ".js,.jsx,.ts,.tsx"
@@ -29,8 +80,33 @@ const (
 	// Normalizer Defaults
 	DefaultNormalizerDefaultScheme = "http" // Example for future use
 
+	// Notification Defaults
+	DefaultNotificationReportCompressionThresholdMB = 5
+	DefaultNotificationMaxEmbedFields               = 25
+	DefaultNotificationMaxMessagesPerMinute         = 25 // Discord allows roughly 30 webhook messages per minute
+	DefaultNotificationMinSeverity                  = "info"
+	DefaultNotificationPreflightWebhooks            = true
+	DefaultNotificationMaxReportParts               = 10
+	DefaultNotificationReportPartsOverflow          = ReportPartsOverflowOmit
+	DefaultQuietHoursStart                          = "22:00"
+	DefaultQuietHoursEnd                            = "08:00"
+
+	// Event Sink Defaults
+	DefaultEventSinkHTTPTimeoutSecs = 10
+	DefaultEventSinkBufferSize      = 256
+
+	// Search Export Defaults
+	DefaultSearchExportIndex       = "monsterinc-probes"
+	DefaultSearchExportBatchSize   = 500
+	DefaultSearchExportMaxRetries  = 5
+	DefaultSearchExportTimeoutSecs = 30
+
 	// Scheduler Defaults
 	DefaultSchedulerScanIntervalMinutes = 10080 // 7 days
 	DefaultSchedulerRetryAttempts       = 2
 	DefaultSchedulerSQLiteDBPath        = "database/scheduler/scheduler_history.db"
+	DefaultSchedulerTLSExpiryWarnDays   = 14
+	DefaultSchedulerNotifyNewHosts      = true
+	DefaultSchedulerDigestCron          = "0 9 * * 1" // Mondays at 09:00
+	DefaultSchedulerDigestWindowDays    = 7
 )
@@ -0,0 +1,17 @@
+package config
+
+// CrawlStateConfig defines periodic snapshots of the crawl frontier so interrupted crawls can resume
+type CrawlStateConfig struct {
+	// Path of the JSON state file; resuming is enabled when set (also via --crawl-state)
+	FilePath string `json:"file_path,omitempty" yaml:"file_path,omitempty"`
+	// Seconds between frontier snapshots while crawling
+	SnapshotIntervalSecs int `json:"snapshot_interval_secs,omitempty" yaml:"snapshot_interval_secs,omitempty" validate:"omitempty,min=1"`
+}
+
+// NewDefaultCrawlStateConfig creates default crawl state configuration (resume disabled)
+func NewDefaultCrawlStateConfig() CrawlStateConfig {
+	return CrawlStateConfig{
+		FilePath:             "",
+		SnapshotIntervalSecs: DefaultCrawlStateSnapshotIntervalSecs,
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CrawlerLoginConfig defines an optional form login performed before crawling; the cookies it sets
+// are shared by every crawl request
+type CrawlerLoginConfig struct {
+	// Endpoint the credentials are POSTed to; empty disables the login step
+	URL string `json:"url,omitempty" yaml:"url,omitempty" validate:"omitempty,url"`
+	// Form fields sent as application/x-www-form-urlencoded (e.g. username, password)
+	FormFields map[string]string `json:"form_fields,omitempty" yaml:"form_fields,omitempty"`
+}
+
+// CrawlerAuthConfig defines credentials attached to crawl requests for targets that require login
+type CrawlerAuthConfig struct {
+	// Static Cookie header sent with every crawl request
+	Cookie string `json:"cookie,omitempty" yaml:"cookie,omitempty"`
+	// Extra headers sent with every crawl request (e.g. Authorization)
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Optional login step run before each batch until a session is established
+	Login CrawlerLoginConfig `json:"login,omitempty" yaml:"login,omitempty"`
+	// Path that a redirect lands on when the session has expired; defaults to the path of Login.URL
+	LoginPagePath string `json:"login_page_path,omitempty" yaml:"login_page_path,omitempty"`
+}
+
+// NewDefaultCrawlerAuthConfig creates default crawler auth configuration (unauthenticated)
+func NewDefaultCrawlerAuthConfig() CrawlerAuthConfig {
+	return CrawlerAuthConfig{
+		Cookie:  "",
+		Headers: map[string]string{},
+		Login: CrawlerLoginConfig{
+			URL:        "",
+			FormFields: map[string]string{},
+		},
+		LoginPagePath: "",
+	}
+}
+
+// HasLogin reports whether a login step is configured
+func (cac CrawlerAuthConfig) HasLogin() bool {
+	return strings.TrimSpace(cac.Login.URL) != ""
+}
+
+// ResolvedLoginPagePath returns the path used to detect an expired session, or "" if none is known
+func (cac CrawlerAuthConfig) ResolvedLoginPagePath() string {
+	if cac.LoginPagePath != "" {
+		return cac.LoginPagePath
+	}
+
+	if !cac.HasLogin() {
+		return ""
+	}
+
+	parsed, err := url.Parse(cac.Login.URL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Path
+}
@@ -1,6 +1,9 @@
 package config
 
-import "github.com/aleister1102/monsterinc/internal/common/urlhandler"
+import (
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+)
 
 // CrawlerScopeConfig defines scope restrictions for the crawler
 type CrawlerScopeConfig struct {
@@ -53,7 +56,11 @@ type CrawlerConfig struct {
 	MaxConcurrentRequests int                 `json:"max_concurrent_requests,omitempty" yaml:"max_concurrent_requests,omitempty" validate:"omitempty,min=1"`
 	MaxContentLengthMB    int                 `json:"max_content_length_mb,omitempty" yaml:"max_content_length_mb,omitempty"`
 	MaxDepth              int                 `json:"max_depth,omitempty" yaml:"max_depth,omitempty" validate:"omitempty,min=0"`
+	MaxTotalURLs          int                 `json:"max_total_urls,omitempty" yaml:"max_total_urls,omitempty" validate:"omitempty,min=0"`                   // Stop queueing discovered URLs after this many per crawl; 0 = unlimited
+	MaxCrawlDurationSecs  int                 `json:"max_crawl_duration_secs,omitempty" yaml:"max_crawl_duration_secs,omitempty" validate:"omitempty,min=0"` // Stop queueing URLs this long after the crawl started; 0 = unlimited
 	RequestTimeoutSecs    int                 `json:"request_timeout_secs,omitempty" yaml:"request_timeout_secs,omitempty" validate:"omitempty,min=1"`
+	RequestDelayMs        int                 `json:"request_delay_ms,omitempty" yaml:"request_delay_ms,omitempty" validate:"omitempty,min=0"`         // Minimum gap between requests to the same host; 0 = no delay
+	RequestDelayMaxMs     int                 `json:"request_delay_max_ms,omitempty" yaml:"request_delay_max_ms,omitempty" validate:"omitempty,min=0"` // When above request_delay_ms, each gap is random between the two
 	Scope                 CrawlerScopeConfig  `json:"scope,omitempty" yaml:"scope,omitempty"`
 	SeedURLs              []string            `json:"seed_urls,omitempty" yaml:"seed_urls,omitempty" validate:"omitempty,dive,url"`
 	AutoCalibrate         AutoCalibrateConfig `json:"auto_calibrate,omitempty" yaml:"auto_calibrate,omitempty"`
@@ -61,6 +68,44 @@ type CrawlerConfig struct {
 	URLNormalization urlhandler.URLNormalizationConfig `json:"url_normalization,omitempty" yaml:"url_normalization,omitempty"`
 	// Retry configuration for handling rate limits (429 errors)
 	RetryConfig RetryConfig `json:"retry_config,omitempty" yaml:"retry_config,omitempty"`
+	// Per-host concurrency that backs off on rate limiting instead of hammering the host
+	AdaptiveConcurrency AdaptiveConcurrencyConfig `json:"adaptive_concurrency,omitempty" yaml:"adaptive_concurrency,omitempty"`
+	// HTTP fixture record/replay configuration for hermetic test runs
+	Fixtures FixtureConfig `json:"fixtures,omitempty" yaml:"fixtures,omitempty"`
+	// Frontier snapshot configuration for resuming interrupted crawls
+	CrawlState CrawlStateConfig `json:"crawl_state,omitempty" yaml:"crawl_state,omitempty"`
+	// Response-header-based discovery of related hosts
+	HeaderScopeExpansion HeaderScopeExpansionConfig `json:"header_scope_expansion,omitempty" yaml:"header_scope_expansion,omitempty"`
+	// Static User-Agent for crawl requests (ignored when UserAgents is non-empty)
+	UserAgent string `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+	// Pool of User-Agents rotated per request
+	UserAgents []string `json:"user_agents,omitempty" yaml:"user_agents,omitempty"`
+	// Rotation strategy for UserAgents: "random" or "round-robin"
+	UserAgentRotation string `json:"user_agent_rotation,omitempty" yaml:"user_agent_rotation,omitempty" validate:"omitempty,oneof=random round-robin"`
+	// Probe only each seed host's root URL (plus RootProbePaths) instead of crawling
+	RootProbeOnly bool `json:"root_probe_only" yaml:"root_probe_only"`
+	// Common paths probed on each seed host in root-probe-only mode (e.g. /robots.txt)
+	RootProbePaths []string `json:"root_probe_paths,omitempty" yaml:"root_probe_paths,omitempty"`
+	// Seeding from the sitemap.xml of each seed host
+	Sitemap CrawlerSitemapConfig `json:"sitemap,omitempty" yaml:"sitemap,omitempty"`
+	// Redirect loop detection and per-chain/per-seed redirect caps
+	Redirects CrawlerRedirectConfig `json:"redirects,omitempty" yaml:"redirects,omitempty"`
+	// Cookie, header and login-based authentication for crawl requests
+	Auth CrawlerAuthConfig `json:"auth,omitempty" yaml:"auth,omitempty"`
+	// Request headers applied to every crawl request; populated from GlobalConfig.RequestHeaders at scan time
+	RequestHeaders RequestHeadersConfig `json:"-" yaml:"-"`
+	// Outbound proxy for crawl requests; populated from GlobalConfig.ProxyConfig at scan time
+	Proxy httpclient.ProxyConfig `json:"-" yaml:"-"`
+	// Proxy pool shared with httpx so proxy health carries across scans; set at scan time when proxy_config.pool is configured
+	ProxyPool *httpclient.ProxyPool `json:"-" yaml:"-"`
+	// Limit on requests in flight shared with httpx; set at scan time when max_in_flight_requests is configured
+	InFlightLimiter *httpclient.InFlightLimiter `json:"-" yaml:"-"`
+	// Per-target credentials from the target files; populated at scan time and never serialized
+	TargetCredentials urlhandler.TargetCredentials `json:"-" yaml:"-"`
+	// Per-target request timeouts from "|timeout=" target annotations; populated at scan time
+	TargetTimeouts urlhandler.TargetTimeouts `json:"-" yaml:"-"`
+	// Recorder for the debug HAR export; set at scan time when har_export is enabled
+	HARRecorder *httpclient.HARRecorder `json:"-" yaml:"-"`
 }
 
 // NewDefaultCrawlerConfig creates default crawler configuration
@@ -77,5 +122,16 @@ func NewDefaultCrawlerConfig() CrawlerConfig {
 		AutoCalibrate:         NewDefaultAutoCalibrateConfig(),
 		URLNormalization:      urlhandler.DefaultURLNormalizationConfig(),
 		RetryConfig:           NewDefaultRetryConfig(),
+		AdaptiveConcurrency:   NewDefaultAdaptiveConcurrencyConfig(),
+		Fixtures:              NewDefaultFixtureConfig(),
+		CrawlState:            NewDefaultCrawlStateConfig(),
+		HeaderScopeExpansion:  NewDefaultHeaderScopeExpansionConfig(),
+		UserAgents:            []string{},
+		UserAgentRotation:     DefaultUserAgentRotation,
+		RootProbeOnly:         false,
+		RootProbePaths:        []string{},
+		Sitemap:               NewDefaultCrawlerSitemapConfig(),
+		Redirects:             NewDefaultCrawlerRedirectConfig(),
+		Auth:                  NewDefaultCrawlerAuthConfig(),
 	}
 }
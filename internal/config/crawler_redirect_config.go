@@ -0,0 +1,17 @@
+package config
+
+// CrawlerRedirectConfig defines how far the crawler follows redirects before giving up on a chain
+type CrawlerRedirectConfig struct {
+	// Maximum redirects followed for a single request before the last response is used
+	MaxPerChain int `json:"max_per_chain,omitempty" yaml:"max_per_chain,omitempty" validate:"omitempty,min=1"`
+	// Maximum redirects followed across all requests descending from one seed URL (0 = unlimited)
+	MaxPerSeed int `json:"max_per_seed,omitempty" yaml:"max_per_seed,omitempty" validate:"omitempty,min=0"`
+}
+
+// NewDefaultCrawlerRedirectConfig creates default crawler redirect configuration
+func NewDefaultCrawlerRedirectConfig() CrawlerRedirectConfig {
+	return CrawlerRedirectConfig{
+		MaxPerChain: DefaultCrawlerMaxRedirectsPerChain,
+		MaxPerSeed:  DefaultCrawlerMaxRedirectsPerSeed,
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+// CrawlerSitemapConfig defines seeding the crawl from the sitemaps each seed host publishes
+type CrawlerSitemapConfig struct {
+	// Fetch the sitemaps of every seed host and visit their URLs right after the seeds
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Sitemap locations fetched on each seed host; gzipped sitemaps and sitemap indexes are followed
+	Paths []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	// Maximum URLs taken from the sitemaps of one host (0 = unlimited)
+	MaxURLsPerHost int `json:"max_urls_per_host,omitempty" yaml:"max_urls_per_host,omitempty" validate:"omitempty,min=0"`
+}
+
+// NewDefaultCrawlerSitemapConfig creates default crawler sitemap configuration (disabled)
+func NewDefaultCrawlerSitemapConfig() CrawlerSitemapConfig {
+	return CrawlerSitemapConfig{
+		Enabled:        false,
+		Paths:          []string{"/sitemap.xml"},
+		MaxURLsPerHost: DefaultCrawlerSitemapMaxURLsPerHost,
+	}
+}
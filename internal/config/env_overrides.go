@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvOverridePrefix starts the name of every environment variable that overrides a config value
+const EnvOverridePrefix = "MONSTERINC"
+
+// ApplyEnvOverrides overwrites config values with environment variables named after their YAML path:
+// the prefix, then each key upper-cased with a trailing "_config" dropped, joined by underscores.
+// notification_config.scan_service_discord_webhook_url becomes MONSTERINC_NOTIFICATION_SCAN_SERVICE_DISCORD_WEBHOOK_URL.
+//
+// Strings are taken verbatim. String lists accept comma-separated values; other values (numbers, booleans,
+// maps, lists of objects) are parsed as YAML, so JSON works too. It returns the names of the variables applied.
+func ApplyEnvOverrides(cfg *GlobalConfig, lookup func(string) (string, bool)) ([]string, error) {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	var applied []string
+	if err := applyEnvOverridesToStruct(reflect.ValueOf(cfg).Elem(), EnvOverridePrefix, lookup, &applied); err != nil {
+		return applied, err
+	}
+	return applied, nil
+}
+
+// applyEnvOverridesToStruct walks the YAML-visible fields of v, recursing into nested sections
+func applyEnvOverridesToStruct(v reflect.Value, prefix string, lookup func(string) (string, bool), applied *[]string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := yamlKey(field)
+		if key == "" || !field.IsExported() {
+			continue
+		}
+
+		envName := prefix + "_" + strings.ToUpper(strings.TrimSuffix(key, "_config"))
+		fieldValue := v.Field(i)
+
+		if fieldValue.Kind() == reflect.Struct {
+			if err := applyEnvOverridesToStruct(fieldValue, envName, lookup, applied); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := lookup(envName)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(fieldValue, raw); err != nil {
+			return errorwrapper.NewValidationError(envName, raw, "cannot parse environment override: "+err.Error())
+		}
+		*applied = append(*applied, envName)
+	}
+	return nil
+}
+
+// setFromEnv parses raw into a value of the field's type and stores it
+func setFromEnv(field reflect.Value, raw string) error {
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(raw)
+		return nil
+
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(raw), "["):
+		values := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = reflect.Append(values, reflect.ValueOf(item).Convert(field.Type().Elem()))
+			}
+		}
+		field.Set(values)
+		return nil
+
+	default:
+		parsed := reflect.New(field.Type())
+		if err := yaml.Unmarshal([]byte(raw), parsed.Interface()); err != nil {
+			return err
+		}
+		field.Set(parsed.Elem())
+		return nil
+	}
+}
+
+// yamlKey returns the YAML key of a struct field, or "" if the field is not serialized
+func yamlKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
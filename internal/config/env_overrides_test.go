@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	cfg := NewDefaultGlobalConfig()
+	cfg.NotificationConfig.ScanServiceDiscordWebhookURLs = WebhookURLs{"https://discord.com/api/webhooks/from-file"}
+
+	applied, err := ApplyEnvOverrides(cfg, envLookup(map[string]string{
+		"MONSTERINC_NOTIFICATION_SCAN_SERVICE_DISCORD_WEBHOOK_URL": "https://discord.com/api/webhooks/from-env",
+		"MONSTERINC_MODE":                                 "automated",
+		"MONSTERINC_HTTPX_RUNNER_THREADS":                 "50",
+		"MONSTERINC_HTTPX_RUNNER_EXTRACT_IPS":             "false",
+		"MONSTERINC_NOTIFICATION_MENTION_ROLE_IDS":        "111, 222",
+		"MONSTERINC_TARGET_EXPANSION_PORTS":               "[80, 8443]",
+		"MONSTERINC_STORAGE_RESPONSE_BODIES_ENABLED":      "true",
+		"MONSTERINC_HTTPX_RUNNER_CUSTOM_HEADERS":          `{"X-Api-Key": "secret"}`,
+		"MONSTERINC_NOTIFICATION_CONFIG_MENTION_ROLE_IDS": "ignored",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, WebhookURLs{"https://discord.com/api/webhooks/from-env"}, cfg.NotificationConfig.ScanServiceDiscordWebhookURLs)
+	assert.Equal(t, "automated", cfg.Mode)
+	assert.Equal(t, 50, cfg.HttpxRunnerConfig.Threads)
+	assert.False(t, cfg.HttpxRunnerConfig.ExtractIPs)
+	assert.Equal(t, []string{"111", "222"}, cfg.NotificationConfig.MentionRoleIDs)
+	assert.Equal(t, []int{80, 8443}, cfg.TargetExpansion.Ports)
+	assert.True(t, cfg.StorageConfig.ResponseBodies.Enabled)
+	assert.Equal(t, map[string]string{"X-Api-Key": "secret"}, cfg.HttpxRunnerConfig.CustomHeaders)
+	assert.Len(t, applied, 8)
+}
+
+func TestApplyEnvOverrides_InvalidValue(t *testing.T) {
+	_, err := ApplyEnvOverrides(NewDefaultGlobalConfig(), envLookup(map[string]string{
+		"MONSTERINC_HTTPX_RUNNER_THREADS": "many",
+	}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MONSTERINC_HTTPX_RUNNER_THREADS")
+}
@@ -0,0 +1,32 @@
+package config
+
+// EventSinkConfig defines delivery of machine-readable scan lifecycle events
+type EventSinkConfig struct {
+	// Enable structured event emission
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Append events as JSON Lines to this file (optional)
+	FilePath string `json:"file_path,omitempty" yaml:"file_path,omitempty"`
+	// POST each event as JSON to this URL (optional)
+	HTTPURL string `json:"http_url,omitempty" yaml:"http_url,omitempty" validate:"omitempty,url"`
+	// Extra headers sent with HTTP events (e.g. Authorization)
+	HTTPHeaders map[string]string `json:"http_headers,omitempty" yaml:"http_headers,omitempty"`
+	// Secret for signing HTTP event bodies with HMAC-SHA256 in the X-MonsterInc-Signature header (optional)
+	HTTPSigningSecret string `json:"http_signing_secret,omitempty" yaml:"http_signing_secret,omitempty"`
+	// Timeout for a single HTTP event delivery
+	HTTPTimeoutSecs int `json:"http_timeout_secs,omitempty" yaml:"http_timeout_secs,omitempty" validate:"omitempty,min=1"`
+	// Number of events buffered for HTTP delivery before new events are dropped
+	BufferSize int `json:"buffer_size,omitempty" yaml:"buffer_size,omitempty" validate:"omitempty,min=1"`
+}
+
+// NewDefaultEventSinkConfig creates default event sink configuration
+func NewDefaultEventSinkConfig() EventSinkConfig {
+	return EventSinkConfig{
+		Enabled:           false,
+		FilePath:          "",
+		HTTPURL:           "",
+		HTTPHeaders:       map[string]string{},
+		HTTPSigningSecret: "",
+		HTTPTimeoutSecs:   DefaultEventSinkHTTPTimeoutSecs,
+		BufferSize:        DefaultEventSinkBufferSize,
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+// FixtureConfig defines HTTP fixture recording and replay for deterministic crawler runs
+type FixtureConfig struct {
+	// Path of a JSON Lines fixture file to append every crawler HTTP exchange to
+	RecordPath string `json:"record_path,omitempty" yaml:"record_path,omitempty"`
+	// Path of a JSON Lines fixture file to serve crawler responses from instead of the network
+	ReplayPath string `json:"replay_path,omitempty" yaml:"replay_path,omitempty"`
+}
+
+// NewDefaultFixtureConfig creates default fixture configuration (recording and replay disabled)
+func NewDefaultFixtureConfig() FixtureConfig {
+	return FixtureConfig{
+		RecordPath: "",
+		ReplayPath: "",
+	}
+}
@@ -2,50 +2,85 @@ package config
 
 import (
 	"encoding/json"
+	"os"
 	"path/filepath"
 
 	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
 	"github.com/aleister1102/monsterinc/internal/common/filemanager"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
 	"github.com/rs/zerolog"
-	"gopkg.in/yaml.v3"
 )
 
+// Modes are the values accepted by mode
+var Modes = []string{"onetime", "automated"}
+
 // GlobalConfig contains all configuration sections for the application
 type GlobalConfig struct {
-	CrawlerConfig      CrawlerConfig      `json:"crawler_config,omitempty" yaml:"crawler_config,omitempty"`
-	HttpxRunnerConfig  HttpxRunnerConfig  `json:"httpx_runner_config,omitempty" yaml:"httpx_runner_config,omitempty"`
-	LogConfig          LogConfig          `json:"log_config,omitempty" yaml:"log_config,omitempty"`
-	Mode               string             `json:"mode,omitempty" yaml:"mode,omitempty" validate:"required,mode"`
-	NotificationConfig NotificationConfig `json:"notification_config,omitempty" yaml:"notification_config,omitempty"`
-	ReporterConfig     ReporterConfig     `json:"reporter_config,omitempty" yaml:"reporter_config,omitempty"`
-	SchedulerConfig    SchedulerConfig    `json:"scheduler_config,omitempty" yaml:"scheduler_config,omitempty"`
-	StorageConfig      StorageConfig      `json:"storage_config,omitempty" yaml:"storage_config,omitempty"`
-	ScanBatchConfig    ScanBatchConfig    `json:"scan_batch_config,omitempty" yaml:"scan_batch_config,omitempty"`
+	CrawlerConfig      CrawlerConfig          `json:"crawler_config,omitempty" yaml:"crawler_config,omitempty"`
+	EventSinkConfig    EventSinkConfig        `json:"event_sink_config,omitempty" yaml:"event_sink_config,omitempty"`
+	HARExport          HARExportConfig        `json:"har_export,omitempty" yaml:"har_export,omitempty"`
+	HttpxRunnerConfig  HttpxRunnerConfig      `json:"httpx_runner_config,omitempty" yaml:"httpx_runner_config,omitempty"`
+	InterruptHooks     InterruptHooksConfig   `json:"interrupt_hooks,omitempty" yaml:"interrupt_hooks,omitempty"`
+	LogConfig          LogConfig              `json:"log_config,omitempty" yaml:"log_config,omitempty"`
+	MaxDurationMins    int                    `json:"max_duration_mins,omitempty" yaml:"max_duration_mins,omitempty" validate:"omitempty,min=0"`           // Wall-clock cap for onetime scans; 0 disables
+	MaxInFlight        int                    `json:"max_in_flight_requests,omitempty" yaml:"max_in_flight_requests,omitempty" validate:"omitempty,min=0"` // Outbound scan requests in flight across crawler and httpx; 0 disables
+	Mode               string                 `json:"mode,omitempty" yaml:"mode,omitempty" validate:"required,mode"`
+	NotificationConfig NotificationConfig     `json:"notification_config,omitempty" yaml:"notification_config,omitempty"`
+	OnlyTags           []string               `json:"only_tags,omitempty" yaml:"only_tags,omitempty"` // Scan only targets carrying one of these "|tags=" tags; empty scans all
+	Progress           ProgressConfig         `json:"progress,omitempty" yaml:"progress,omitempty"`
+	ProxyConfig        httpclient.ProxyConfig `json:"proxy_config,omitempty" yaml:"proxy_config,omitempty"`
+	ReportBrowser      ReportBrowserConfig    `json:"report_browser,omitempty" yaml:"report_browser,omitempty"`
+	ReporterConfig     ReporterConfig         `json:"reporter_config,omitempty" yaml:"reporter_config,omitempty"`
+	RequestHeaders     RequestHeadersConfig   `json:"request_headers,omitempty" yaml:"request_headers,omitempty"`
+	SchedulerConfig    SchedulerConfig        `json:"scheduler_config,omitempty" yaml:"scheduler_config,omitempty"`
+	StorageConfig      StorageConfig          `json:"storage_config,omitempty" yaml:"storage_config,omitempty"`
+	ScanBatchConfig    ScanBatchConfig        `json:"scan_batch_config,omitempty" yaml:"scan_batch_config,omitempty"`
+	SearchExport       SearchExportConfig     `json:"search_export,omitempty" yaml:"search_export,omitempty"`
+	TargetExpansion    TargetExpansionConfig  `json:"target_expansion,omitempty" yaml:"target_expansion,omitempty"`
+	TargetSampling     TargetSamplingConfig   `json:"target_sampling,omitempty" yaml:"target_sampling,omitempty"`
 }
 
 // NewDefaultGlobalConfig creates a new GlobalConfig with default values
 func NewDefaultGlobalConfig() *GlobalConfig {
 	return &GlobalConfig{
 		CrawlerConfig:      NewDefaultCrawlerConfig(),
+		EventSinkConfig:    NewDefaultEventSinkConfig(),
+		HARExport:          NewDefaultHARExportConfig(),
 		HttpxRunnerConfig:  NewDefaultHTTPXRunnerConfig(),
+		InterruptHooks:     NewDefaultInterruptHooksConfig(),
 		LogConfig:          NewDefaultLogConfig(),
+		MaxDurationMins:    0,
+		MaxInFlight:        0,
 		Mode:               "onetime",
 		NotificationConfig: NewDefaultNotificationConfig(),
+		OnlyTags:           []string{},
+		Progress:           NewDefaultProgressConfig(),
+		ReportBrowser:      NewDefaultReportBrowserConfig(),
 		ReporterConfig:     NewDefaultReporterConfig(),
+		RequestHeaders:     NewDefaultRequestHeadersConfig(),
 		SchedulerConfig:    NewDefaultSchedulerConfig(),
 		StorageConfig:      NewDefaultStorageConfig(),
 		ScanBatchConfig:    NewDefaultScanBatchConfig(),
+		SearchExport:       NewDefaultSearchExportConfig(),
+		TargetExpansion:    NewDefaultTargetExpansionConfig(),
+		TargetSampling:     NewDefaultTargetSamplingConfig(),
 	}
 }
 
 // LoadGlobalConfig loads the configuration from a file or default locations.
 // It determines the config file path using GetConfigPath, supports both JSON and YAML formats.
 // YAML is preferred if the file extension is .yaml or .yml.
+// MONSTERINC_* environment variables take precedence over file values (see ApplyEnvOverrides).
 func LoadGlobalConfig(providedPath string, logger zerolog.Logger) (*GlobalConfig, error) {
 	cfg := NewDefaultGlobalConfig()
 
 	filePath := GetConfigPath(providedPath)
 	if filePath == "" {
+		if err := applyEnvOverrides(cfg, logger); err != nil {
+			return nil, err
+		}
+		shareProxyConfig(cfg)
+		shareCrawlerScope(cfg)
 		return cfg, nil
 	}
 
@@ -63,9 +98,40 @@ func LoadGlobalConfig(providedPath string, logger zerolog.Logger) (*GlobalConfig
 		return nil, errorwrapper.WrapError(err, "failed to parse config content")
 	}
 
+	if err := applyEnvOverrides(cfg, logger); err != nil {
+		return nil, err
+	}
+
+	shareProxyConfig(cfg)
+	shareCrawlerScope(cfg)
 	return cfg, nil
 }
 
+// shareProxyConfig hands proxy_config to the sections that open their own connections outside a scan
+func shareProxyConfig(cfg *GlobalConfig) {
+	cfg.StorageConfig.S3.Proxy = cfg.ProxyConfig
+	cfg.TargetExpansion.Wildcards.Proxy = cfg.ProxyConfig
+}
+
+// shareCrawlerScope hands the crawler's scope exclusions to wildcard expansion, which would otherwise
+// turn excluded subdomains into seeds
+func shareCrawlerScope(cfg *GlobalConfig) {
+	cfg.TargetExpansion.Wildcards.DisallowedHostnames = cfg.CrawlerConfig.Scope.DisallowedHostnames
+	cfg.TargetExpansion.Wildcards.DisallowedSubdomains = cfg.CrawlerConfig.Scope.DisallowedSubdomains
+}
+
+// applyEnvOverrides applies MONSTERINC_* environment variables on top of cfg, logging their names but never their values
+func applyEnvOverrides(cfg *GlobalConfig, logger zerolog.Logger) error {
+	applied, err := ApplyEnvOverrides(cfg, os.LookupEnv)
+	if err != nil {
+		return errorwrapper.WrapError(err, "failed to apply environment overrides")
+	}
+	if len(applied) > 0 {
+		logger.Info().Strs("variables", applied).Msg("Applied config overrides from environment")
+	}
+	return nil
+}
+
 // loadConfigFileContent reads the config file using FileManager
 func loadConfigFileContent(fileManager *filemanager.FileManager, filePath string) ([]byte, error) {
 	opts := filemanager.DefaultFileReadOptions()
@@ -88,9 +154,16 @@ func isYAMLFile(ext string) bool {
 	return ext == ".yaml" || ext == ".yml"
 }
 
-// parseYAMLConfig parses YAML configuration
+// parseYAMLConfig parses YAML configuration, expanding !include tags and the top-level includes list first
 func parseYAMLConfig(data []byte, filePath string, cfg *GlobalConfig) error {
-	if err := yaml.Unmarshal(data, cfg); err != nil {
+	doc, err := resolveYAMLIncludes(data, filePath)
+	if err != nil {
+		return err
+	}
+	if documentRoot(doc) == nil {
+		return nil // Empty file: defaults apply
+	}
+	if err := doc.Decode(cfg); err != nil {
 		return errorwrapper.NewError("failed to unmarshal YAML from '%s': %w", filePath, err)
 	}
 	return nil
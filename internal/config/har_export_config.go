@@ -0,0 +1,20 @@
+package config
+
+// HARExportConfig defines the debug export of crawler and prober traffic as HTTP Archive (HAR) files
+type HARExportConfig struct {
+	// Record request/response headers, status and timings of every crawler request and probe result
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Directory HAR files are written to, as <scan_session_id>.har (or <scan_session_id>/<host>.har when PerHost is set)
+	OutputDir string `json:"output_dir,omitempty" yaml:"output_dir,omitempty"`
+	// Write one HAR file per host instead of one per scan session
+	PerHost bool `json:"per_host" yaml:"per_host"`
+}
+
+// NewDefaultHARExportConfig creates default HAR export configuration (disabled)
+func NewDefaultHARExportConfig() HARExportConfig {
+	return HARExportConfig{
+		Enabled:   false,
+		OutputDir: DefaultHARExportOutputDir,
+		PerHost:   false,
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+// HeaderScopeExpansionConfig defines parsing of response headers for related hosts to feed back as crawl seeds
+type HeaderScopeExpansionConfig struct {
+	// Whether header-based discovery is enabled
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Response headers scanned for URLs and hostnames (e.g. Link, Access-Control-Allow-Origin)
+	Headers []string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// NewDefaultHeaderScopeExpansionConfig creates default header scope expansion configuration (disabled)
+func NewDefaultHeaderScopeExpansionConfig() HeaderScopeExpansionConfig {
+	return HeaderScopeExpansionConfig{
+		Enabled: false,
+		Headers: []string{"Link", "Access-Control-Allow-Origin"},
+	}
+}
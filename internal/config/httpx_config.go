@@ -21,6 +21,7 @@ const (
 	DefaultHTTPXExtractHeaders       = true
 	DefaultHTTPXRateLimit            = 0
 	DefaultHTTPXExtractASN           = true
+	DefaultHTTPXExtractTLS           = true
 )
 
 type HttpxRunnerConfig struct {
@@ -35,6 +36,7 @@ type HttpxRunnerConfig struct {
 	ExtractServerHeader  bool              `json:"extract_server_header" yaml:"extract_server_header"`
 	ExtractStatusCode    bool              `json:"extract_status_code" yaml:"extract_status_code"`
 	ExtractTitle         bool              `json:"extract_title" yaml:"extract_title"`
+	ExtractTLS           bool              `json:"extract_tls" yaml:"extract_tls"` // TLS version, cipher and leaf certificate (expiry, issuer, SANs)
 	FollowRedirects      bool              `json:"follow_redirects" yaml:"follow_redirects"`
 	MaxRedirects         int               `json:"max_redirects,omitempty" yaml:"max_redirects,omitempty" validate:"omitempty,min=0"`
 	Method               string            `json:"method,omitempty" yaml:"method,omitempty"`
@@ -44,6 +46,8 @@ type HttpxRunnerConfig struct {
 	TechDetect           bool              `json:"tech_detect" yaml:"tech_detect"`
 	Threads              int               `json:"threads,omitempty" yaml:"threads,omitempty" validate:"omitempty,min=1"`
 	TimeoutSecs          int               `json:"timeout_secs,omitempty" yaml:"timeout_secs,omitempty" validate:"omitempty,min=1"`
+	UserAgentRotation    string            `json:"user_agent_rotation,omitempty" yaml:"user_agent_rotation,omitempty" validate:"omitempty,oneof=random round-robin"`
+	UserAgents           []string          `json:"user_agents,omitempty" yaml:"user_agents,omitempty"` // Pool rotated per httpx run (httpx itself sends one UA per run)
 	Verbose              bool              `json:"verbose" yaml:"verbose"`
 }
 
@@ -60,6 +64,7 @@ func NewDefaultHTTPXRunnerConfig() HttpxRunnerConfig {
 		ExtractServerHeader:  DefaultHTTPXExtractServerHeader,
 		ExtractStatusCode:    DefaultHTTPXExtractStatusCode,
 		ExtractTitle:         DefaultHTTPXExtractTitle,
+		ExtractTLS:           DefaultHTTPXExtractTLS,
 		FollowRedirects:      DefaultHTTPXFollowRedirects,
 		MaxRedirects:         DefaultHTTPXMaxRedirects,
 		Method:               DefaultHTTPXMethod,
@@ -69,6 +74,8 @@ func NewDefaultHTTPXRunnerConfig() HttpxRunnerConfig {
 		TechDetect:           DefaultHTTPXTechDetect,
 		Threads:              DefaultHTTPXThreads,
 		TimeoutSecs:          DefaultHTTPXTimeoutSecs,
+		UserAgentRotation:    DefaultUserAgentRotation,
+		UserAgents:           []string{},
 		Verbose:              DefaultHTTPXVerbose,
 	}
 }
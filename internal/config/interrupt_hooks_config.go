@@ -0,0 +1,17 @@
+package config
+
+// InterruptHooksConfig defines cleanup run when the process receives SIGINT/SIGTERM
+type InterruptHooksConfig struct {
+	// Shell commands run concurrently on interrupt (e.g. releasing a distributed lock)
+	Commands []string `json:"commands,omitempty" yaml:"commands,omitempty"`
+	// Shared deadline for all hooks before shutdown continues without them
+	TimeoutSecs int `json:"timeout_secs,omitempty" yaml:"timeout_secs,omitempty" validate:"omitempty,min=1"`
+}
+
+// NewDefaultInterruptHooksConfig creates default interrupt hooks configuration
+func NewDefaultInterruptHooksConfig() InterruptHooksConfig {
+	return InterruptHooksConfig{
+		Commands:    []string{},
+		TimeoutSecs: DefaultInterruptHooksTimeoutSecs,
+	}
+}
@@ -1,21 +1,29 @@
 package config
 
+// LogLevels are the values accepted by log_level
+var LogLevels = []string{"debug", "info", "warn", "error", "fatal", "panic"}
+
+// LogFormats are the values accepted by log_format
+var LogFormats = []string{"console", "text", "json"}
+
 // LogConfig defines configuration for logging
 type LogConfig struct {
-	LogFile       string `json:"log_file,omitempty" yaml:"log_file,omitempty" validate:"omitempty,filepath"`
-	LogFormat     string `json:"log_format,omitempty" yaml:"log_format,omitempty" validate:"omitempty,logformat"`
-	LogLevel      string `json:"log_level,omitempty" yaml:"log_level,omitempty" validate:"omitempty,loglevel"`
-	MaxLogBackups int    `json:"max_log_backups,omitempty" yaml:"max_log_backups,omitempty"`
-	MaxLogSizeMB  int    `json:"max_log_size_mb,omitempty" yaml:"max_log_size_mb,omitempty"`
+	LogFile            string `json:"log_file,omitempty" yaml:"log_file,omitempty" validate:"omitempty,filepath"`
+	LogFormat          string `json:"log_format,omitempty" yaml:"log_format,omitempty" validate:"omitempty,logformat"`
+	LogLevel           string `json:"log_level,omitempty" yaml:"log_level,omitempty" validate:"omitempty,loglevel"`
+	MaxLogBackups      int    `json:"max_log_backups,omitempty" yaml:"max_log_backups,omitempty"` // Rotated files kept
+	MaxLogSizeMB       int    `json:"max_log_size_mb,omitempty" yaml:"max_log_size_mb,omitempty"` // Size at which the log file is rotated
+	CompressLogBackups bool   `json:"compress_log_backups" yaml:"compress_log_backups"`           // Gzip rotated files
 }
 
 // NewDefaultLogConfig creates default log configuration
 func NewDefaultLogConfig() LogConfig {
 	return LogConfig{
-		LogFile:       DefaultLogFile,
-		LogFormat:     DefaultLogFormat,
-		LogLevel:      DefaultLogLevel,
-		MaxLogBackups: DefaultMaxLogBackups,
-		MaxLogSizeMB:  DefaultMaxLogSizeMB,
+		LogFile:            DefaultLogFile,
+		LogFormat:          DefaultLogFormat,
+		LogLevel:           DefaultLogLevel,
+		MaxLogBackups:      DefaultMaxLogBackups,
+		MaxLogSizeMB:       DefaultMaxLogSizeMB,
+		CompressLogBackups: DefaultCompressLogBackups,
 	}
 }
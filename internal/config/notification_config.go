@@ -1,23 +1,145 @@
 package config
 
+import (
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
 // NotificationConfig defines configuration for notifications
 type NotificationConfig struct {
-	MentionRoleIDs                  []string `json:"mention_role_ids,omitempty" yaml:"mention_role_ids,omitempty"`
-	MonitorServiceDiscordWebhookURL string   `json:"monitor_service_discord_webhook_url,omitempty" yaml:"monitor_service_discord_webhook_url,omitempty" validate:"omitempty,url"`
-	NotifyOnFailure                 bool     `json:"notify_on_failure" yaml:"notify_on_failure"`
-	NotifyOnScanStart               bool     `json:"notify_on_scan_start" yaml:"notify_on_scan_start"`
-	NotifyOnSuccess                 bool     `json:"notify_on_success" yaml:"notify_on_success"`
-	ScanServiceDiscordWebhookURL    string   `json:"scan_service_discord_webhook_url,omitempty" yaml:"scan_service_discord_webhook_url,omitempty" validate:"omitempty,url"`
+	MaxEmbedFields                   int              `json:"max_embed_fields,omitempty" yaml:"max_embed_fields,omitempty" validate:"omitempty,min=2,max=25"` // Fields beyond this are spilled into an attached text file
+	MaxMessagesPerMinute             int              `json:"max_messages_per_minute" yaml:"max_messages_per_minute" validate:"omitempty,min=0"`              // Per-webhook send rate; excess messages queue instead of being rate-limited by Discord. 0 disables throttling
+	MaxReportParts                   int              `json:"max_report_parts" yaml:"max_report_parts" validate:"omitempty,min=0"`                            // Report files attached to a scan completion notification before report_parts_overflow applies; 0 = unlimited
+	MentionRoleIDs                   []string         `json:"mention_role_ids,omitempty" yaml:"mention_role_ids,omitempty"`
+	MinSeverity                      string           `json:"min_severity,omitempty" yaml:"min_severity,omitempty"` // Least severity sent to scan_service_discord_webhook_url (see NotificationSeverities)
+	MonitorServiceDiscordWebhookURLs WebhookURLs      `json:"monitor_service_discord_webhook_url,omitempty" yaml:"monitor_service_discord_webhook_url,omitempty" validate:"omitempty,dive,url"`
+	NotifyOnFailure                  bool             `json:"notify_on_failure" yaml:"notify_on_failure"`
+	NotifyOnScanStart                bool             `json:"notify_on_scan_start" yaml:"notify_on_scan_start"`
+	NotifyOnSuccess                  bool             `json:"notify_on_success" yaml:"notify_on_success"`
+	PreflightWebhooks                bool             `json:"preflight_webhooks" yaml:"preflight_webhooks"` // Look up every webhook at startup and warn about missing or revoked ones
+	QuietHours                       QuietHoursConfig `json:"quiet_hours,omitempty" yaml:"quiet_hours,omitempty"`
+	ReportCompressionThresholdMB     int              `json:"report_compression_threshold_mb" yaml:"report_compression_threshold_mb" validate:"omitempty,min=0"`                          // Gzip report attachments larger than this; 0 disables compression
+	ReportPartsOverflow              string           `json:"report_parts_overflow,omitempty" yaml:"report_parts_overflow,omitempty" validate:"omitempty,oneof=omit zip"`                 // What happens to report files beyond max_report_parts (see ReportPartsOverflowOmit, ReportPartsOverflowZip)
+	ScanServiceDiscordWebhookURLs    WebhookURLs      `json:"scan_service_discord_webhook_url,omitempty" yaml:"scan_service_discord_webhook_url,omitempty" validate:"omitempty,dive,url"` // Every notification of at least min_severity is sent to each webhook
+	SeverityWebhooks                 SeverityWebhooks `json:"severity_webhooks,omitempty" yaml:"severity_webhooks,omitempty"`                                                             // Additional webhooks, each receiving scan notifications from its own min_severity up
+	TemplatePath                     string           `json:"template_path,omitempty" yaml:"template_path,omitempty"`                                                                     // Go template file customizing scan message embeds; empty uses the built-in messages
 }
 
 // NewDefaultNotificationConfig creates default notification configuration
 func NewDefaultNotificationConfig() NotificationConfig {
 	return NotificationConfig{
-		MentionRoleIDs:                  []string{},
-		MonitorServiceDiscordWebhookURL: "",
-		NotifyOnFailure:                 true,
-		NotifyOnScanStart:               false,
-		NotifyOnSuccess:                 false,
-		ScanServiceDiscordWebhookURL:    "",
+		MaxEmbedFields:                   DefaultNotificationMaxEmbedFields,
+		MaxMessagesPerMinute:             DefaultNotificationMaxMessagesPerMinute,
+		MaxReportParts:                   DefaultNotificationMaxReportParts,
+		MentionRoleIDs:                   []string{},
+		MinSeverity:                      DefaultNotificationMinSeverity,
+		MonitorServiceDiscordWebhookURLs: WebhookURLs{},
+		NotifyOnFailure:                  true,
+		NotifyOnScanStart:                false,
+		NotifyOnSuccess:                  false,
+		PreflightWebhooks:                DefaultNotificationPreflightWebhooks,
+		QuietHours:                       NewDefaultQuietHoursConfig(),
+		ReportCompressionThresholdMB:     DefaultNotificationReportCompressionThresholdMB,
+		ReportPartsOverflow:              DefaultNotificationReportPartsOverflow,
+		ScanServiceDiscordWebhookURLs:    WebhookURLs{},
+		SeverityWebhooks:                 SeverityWebhooks{},
+		TemplatePath:                     "",
+	}
+}
+
+const (
+	// ReportPartsOverflowOmit attaches the first max_report_parts report files and notes how many were left out
+	ReportPartsOverflowOmit = "omit"
+	// ReportPartsOverflowZip attaches all report files as a single zip archive instead
+	ReportPartsOverflowZip = "zip"
+)
+
+// NotificationSeverities are the severities accepted by min_severity, least urgent first
+var NotificationSeverities = []string{"info", "low", "medium", "high", "critical"}
+
+// SeverityWebhookConfig routes scan notifications of at least MinSeverity to one more webhook, such as an
+// on-call channel that should only hear about failed scans and new hosts
+type SeverityWebhookConfig struct {
+	URL            string   `json:"url" yaml:"url"`
+	MinSeverity    string   `json:"min_severity" yaml:"min_severity"`                             // See NotificationSeverities
+	MentionRoleIDs []string `json:"mention_role_ids,omitempty" yaml:"mention_role_ids,omitempty"` // Roles mentioned only in messages sent to this webhook
+}
+
+// SeverityWebhooks lists the severity-routed webhooks
+type SeverityWebhooks []SeverityWebhookConfig
+
+// WebhookURLs lists the webhooks a notification is fanned out to. Besides a list, a single
+// string is accepted in YAML and JSON, as written by configs predating fan-out.
+type WebhookURLs []string
+
+// UnmarshalYAML accepts a string or a list of strings
+func (w *WebhookURLs) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var single string
+		if err := node.Decode(&single); err != nil {
+			return err
+		}
+		*w = newWebhookURLs(single)
+		return nil
+	}
+
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*w = newWebhookURLs(list...)
+	return nil
+}
+
+// UnmarshalJSON accepts a string or an array of strings
+func (w *WebhookURLs) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*w = newWebhookURLs(single)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*w = newWebhookURLs(list...)
+	return nil
+}
+
+// newWebhookURLs drops empty entries, so an empty string configures no webhook
+func newWebhookURLs(urls ...string) WebhookURLs {
+	webhooks := WebhookURLs{}
+	for _, url := range urls {
+		if url = strings.TrimSpace(url); url != "" {
+			webhooks = append(webhooks, url)
+		}
+	}
+	return webhooks
+}
+
+// QuietHoursConfig defines a recurring window during which non-critical notifications (scan start,
+// successful scan completion) are buffered and delivered as one digest when the window ends.
+// Failures, interrupts and critical errors are always sent immediately.
+type QuietHoursConfig struct {
+	Enabled   bool     `json:"enabled" yaml:"enabled"`
+	Start     string   `json:"start,omitempty" yaml:"start,omitempty" validate:"omitempty,datetime=15:04"`                       // Window start, 24h "HH:MM"
+	End       string   `json:"end,omitempty" yaml:"end,omitempty" validate:"omitempty,datetime=15:04"`                           // Window end; earlier than start means the window runs overnight
+	Timezone  string   `json:"timezone,omitempty" yaml:"timezone,omitempty" validate:"omitempty,timezone"`                       // IANA zone name; empty uses the local zone
+	Days      []string `json:"days,omitempty" yaml:"days,omitempty" validate:"omitempty,dive,oneof=mon tue wed thu fri sat sun"` // Days on which the window starts; empty means every day
+	StateFile string   `json:"state_file,omitempty" yaml:"state_file,omitempty"`                                                 // Optional file keeping buffered notifications across restarts
+}
+
+// NewDefaultQuietHoursConfig creates default quiet hours configuration (disabled, 22:00-08:00 UTC)
+func NewDefaultQuietHoursConfig() QuietHoursConfig {
+	return QuietHoursConfig{
+		Enabled:   false,
+		Start:     DefaultQuietHoursStart,
+		End:       DefaultQuietHoursEnd,
+		Timezone:  "UTC",
+		Days:      []string{},
+		StateFile: "",
 	}
 }
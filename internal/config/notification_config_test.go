@@ -0,0 +1,51 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWebhookURLs_AcceptsStringOrList(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		json string
+		want WebhookURLs
+	}{
+		{"single string", `"https://discord.com/api/webhooks/1"`, `"https://discord.com/api/webhooks/1"`, WebhookURLs{"https://discord.com/api/webhooks/1"}},
+		{"list", `["https://discord.com/api/webhooks/1", "https://discord.com/api/webhooks/2"]`, `["https://discord.com/api/webhooks/1", "https://discord.com/api/webhooks/2"]`, WebhookURLs{"https://discord.com/api/webhooks/1", "https://discord.com/api/webhooks/2"}},
+		{"empty string", `""`, `""`, WebhookURLs{}},
+		{"blank entries dropped", `["", " https://discord.com/api/webhooks/1 "]`, `["", " https://discord.com/api/webhooks/1 "]`, WebhookURLs{"https://discord.com/api/webhooks/1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromYAML NotificationConfig
+			require.NoError(t, yaml.Unmarshal([]byte("scan_service_discord_webhook_url: "+tt.yaml), &fromYAML))
+			assert.Equal(t, tt.want, fromYAML.ScanServiceDiscordWebhookURLs)
+
+			var fromJSON NotificationConfig
+			require.NoError(t, json.Unmarshal([]byte(`{"scan_service_discord_webhook_url": `+tt.json+`}`), &fromJSON))
+			assert.Equal(t, tt.want, fromJSON.ScanServiceDiscordWebhookURLs)
+		})
+	}
+}
+
+func TestWebhookURLs_RejectsOtherTypes(t *testing.T) {
+	var cfg NotificationConfig
+	assert.Error(t, yaml.Unmarshal([]byte("scan_service_discord_webhook_url: {url: x}"), &cfg))
+	assert.Error(t, json.Unmarshal([]byte(`{"scan_service_discord_webhook_url": 42}`), &cfg))
+}
+
+func TestApplyEnvOverrides_WebhookURLList(t *testing.T) {
+	cfg := NewDefaultGlobalConfig()
+	_, err := ApplyEnvOverrides(cfg, envLookup(map[string]string{
+		"MONSTERINC_NOTIFICATION_SCAN_SERVICE_DISCORD_WEBHOOK_URL": "https://discord.com/api/webhooks/1,https://discord.com/api/webhooks/2",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, WebhookURLs{"https://discord.com/api/webhooks/1", "https://discord.com/api/webhooks/2"}, cfg.NotificationConfig.ScanServiceDiscordWebhookURLs)
+}
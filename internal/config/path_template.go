@@ -0,0 +1,38 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// Tokens expanded in output paths such as reporter_config.output_dir ("reports/{date}/{session}")
+const (
+	PathTokenDate    = "{date}"    // Local date as 2006-01-02
+	PathTokenTime    = "{time}"    // Local time as 150405
+	PathTokenSession = "{session}" // Scan session ID
+)
+
+// HasPathTokens reports whether path contains any path template token
+func HasPathTokens(path string) bool {
+	return strings.Contains(path, PathTokenDate) ||
+		strings.Contains(path, PathTokenTime) ||
+		strings.Contains(path, PathTokenSession)
+}
+
+// ExpandPathTemplate replaces the path tokens in path. Path separators in scanSessionID are replaced
+// so a session ID can never escape its directory; an empty scanSessionID leaves {session} untouched.
+func ExpandPathTemplate(path, scanSessionID string, now time.Time) string {
+	if !HasPathTokens(path) {
+		return path
+	}
+
+	replacements := []string{
+		PathTokenDate, now.Format("2006-01-02"),
+		PathTokenTime, now.Format("150405"),
+	}
+	if scanSessionID != "" {
+		safeSessionID := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(scanSessionID)
+		replacements = append(replacements, PathTokenSession, safeSessionID)
+	}
+	return strings.NewReplacer(replacements...).Replace(path)
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPathTemplate(t *testing.T) {
+	now := time.Date(2024, 3, 9, 14, 5, 7, 0, time.Local)
+
+	assert.Equal(t, "reports/2024-03-09/20240309-140507", ExpandPathTemplate("reports/{date}/{session}", "20240309-140507", now))
+	assert.Equal(t, "reports/2024-03-09_140507", ExpandPathTemplate("reports/{date}_{time}", "", now))
+	assert.Equal(t, "reports/scan", ExpandPathTemplate("reports/scan", "abc", now))
+
+	// Without a session ID the token is left for a later expansion
+	assert.Equal(t, "reports/2024-03-09/{session}", ExpandPathTemplate("reports/{date}/{session}", "", now))
+
+	// A session ID cannot climb out of the output directory
+	assert.Equal(t, "reports/____etc", ExpandPathTemplate("reports/{session}", "../../etc", now))
+}
+
+func TestHasPathTokens(t *testing.T) {
+	assert.True(t, HasPathTokens("reports/{date}"))
+	assert.True(t, HasPathTokens("{session}"))
+	assert.False(t, HasPathTokens("reports/scan"))
+	assert.False(t, HasPathTokens("reports/{other}"))
+}
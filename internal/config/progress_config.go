@@ -0,0 +1,20 @@
+package config
+
+// ProgressConfig defines how the progress of a running scan is reported
+type ProgressConfig struct {
+	// Log a progress line (batches, crawled and probed URLs, ETA) this often; 0 disables the periodic line
+	IntervalSecs int `json:"interval_secs" yaml:"interval_secs" validate:"min=0"`
+	// Also log a progress line each time the scan crosses another multiple of this percentage; 0 disables
+	PercentStep int `json:"percent_step" yaml:"percent_step" validate:"min=0,max=100"`
+	// Redraw a progress bar on stderr every second when it is a terminal
+	Bar bool `json:"bar" yaml:"bar"`
+}
+
+// NewDefaultProgressConfig creates default progress reporting configuration (log lines, no bar)
+func NewDefaultProgressConfig() ProgressConfig {
+	return ProgressConfig{
+		IntervalSecs: DefaultProgressIntervalSecs,
+		PercentStep:  DefaultProgressPercentStep,
+		Bar:          false,
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// ReportBrowserConfig defines the built-in web UI listing the HTML reports of past scans
+type ReportBrowserConfig struct {
+	// Index every scan's reports, keep report files after they are sent and serve the UI while monsterinc runs
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Address the UI listens on; it has no authentication, so keep it on localhost or behind an authenticating proxy
+	ListenAddr string `json:"listen_addr,omitempty" yaml:"listen_addr,omitempty"`
+	// JSON Lines file with one entry per scan that produced reports
+	IndexFile string `json:"index_file,omitempty" yaml:"index_file,omitempty"`
+	// Kept reports older than this are deleted together with their index entries; 0 keeps them forever
+	MaxAgeDays int `json:"max_age_days,omitempty" yaml:"max_age_days,omitempty" validate:"omitempty,min=0"`
+}
+
+// NewDefaultReportBrowserConfig creates default report browser configuration (disabled)
+func NewDefaultReportBrowserConfig() ReportBrowserConfig {
+	return ReportBrowserConfig{
+		Enabled:    false,
+		ListenAddr: DefaultReportBrowserListenAddr,
+		IndexFile:  DefaultReportBrowserIndexFile,
+		MaxAgeDays: DefaultReportBrowserMaxAgeDays,
+	}
+}
+
+// MaxAge returns how long kept reports are listed, or 0 when they are kept forever
+func (rbc ReportBrowserConfig) MaxAge() time.Duration {
+	return time.Duration(rbc.MaxAgeDays) * 24 * time.Hour
+}
@@ -1,14 +1,22 @@
 package config
 
+// ReportColumns are the probe result columns selectable with ReporterConfig.Columns. The URL and
+// Details columns are always shown.
+var ReportColumns = []string{"diff", "status", "content_type", "type", "title", "tech", "tags", "length", "ip", "asn", "server"}
+
 // ReporterConfig defines configuration for generating reports
 type ReporterConfig struct {
-	DefaultItemsPerPage          int    `json:"default_items_per_page,omitempty" yaml:"default_items_per_page,omitempty"`
-	EmbedAssets                  bool   `json:"embed_assets" yaml:"embed_assets"`
-	EnableDataTables             bool   `json:"enable_data_tables" yaml:"enable_data_tables"`
-	ItemsPerPage                 int    `json:"items_per_page,omitempty" yaml:"items_per_page,omitempty" validate:"omitempty,min=1"`
-	MaxProbeResultsPerReportFile int    `mapstructure:"max_probe_results_per_report_file" json:"max_probe_results_per_report_file,omitempty" yaml:"max_probe_results_per_report_file,omitempty"`
-	OutputDir                    string `json:"output_dir,omitempty" yaml:"output_dir,omitempty" validate:"omitempty,dirpath"`
-	ReportTitle                  string `json:"report_title,omitempty" yaml:"report_title,omitempty"`
+	ChangedOnly                  bool     `json:"changed_only" yaml:"changed_only"`           // Omit URLs unchanged since the previous scan (status "existing") from the report
+	CSVOutput                    bool     `json:"csv_output" yaml:"csv_output"`               // Also write a flat CSV of probe results next to the HTML report
+	Columns                      []string `json:"columns,omitempty" yaml:"columns,omitempty"` // Probe result columns of the HTML results grid, in order (see ReportColumns); empty keeps the default layout
+	DefaultItemsPerPage          int      `json:"default_items_per_page,omitempty" yaml:"default_items_per_page,omitempty"`
+	EmbedAssets                  bool     `json:"embed_assets" yaml:"embed_assets"`
+	EnableDataTables             bool     `json:"enable_data_tables" yaml:"enable_data_tables"`
+	ItemsPerPage                 int      `json:"items_per_page,omitempty" yaml:"items_per_page,omitempty" validate:"omitempty,min=1"`
+	MaxProbeResultsPerReportFile int      `mapstructure:"max_probe_results_per_report_file" json:"max_probe_results_per_report_file,omitempty" yaml:"max_probe_results_per_report_file,omitempty"`
+	OutputDir                    string   `json:"output_dir,omitempty" yaml:"output_dir,omitempty" validate:"omitempty,dirpath"`
+	ReportTitle                  string   `json:"report_title,omitempty" yaml:"report_title,omitempty"`
+	ReportWorkers                int      `json:"report_workers,omitempty" yaml:"report_workers,omitempty"` // 0 = min(CPUs, 4)
 }
 
 // NewDefaultReporterConfig creates default reporter configuration
@@ -0,0 +1,66 @@
+package config
+
+import "strings"
+
+// RequestHeadersConfig defines default request headers (e.g. Accept-Language) sent by both the crawler
+// and the httpx prober, with optional per-host overrides to pin locale or other variants per target.
+type RequestHeadersConfig struct {
+	// Headers sent with every crawl and probe request
+	Default map[string]string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Headers merged over Default for a specific hostname (matched case-insensitively, exact host)
+	PerHost map[string]map[string]string `json:"per_host,omitempty" yaml:"per_host,omitempty"`
+}
+
+// NewDefaultRequestHeadersConfig creates default request headers configuration
+func NewDefaultRequestHeadersConfig() RequestHeadersConfig {
+	return RequestHeadersConfig{
+		Default: map[string]string{},
+		PerHost: map[string]map[string]string{},
+	}
+}
+
+// HasPerHostOverrides reports whether any host has its own header overrides
+func (rhc RequestHeadersConfig) HasPerHostOverrides() bool {
+	return len(rhc.PerHost) > 0
+}
+
+// OverridesForHost returns only the per-host overrides for hostname, or nil if it has none
+func (rhc RequestHeadersConfig) OverridesForHost(hostname string) map[string]string {
+	overrides, _ := rhc.lookupHost(hostname)
+	return overrides
+}
+
+// HeadersForHost returns Default merged with the overrides for hostname
+func (rhc RequestHeadersConfig) HeadersForHost(hostname string) map[string]string {
+	headers := make(map[string]string, len(rhc.Default))
+	for key, value := range rhc.Default {
+		headers[key] = value
+	}
+
+	if overrides, ok := rhc.lookupHost(hostname); ok {
+		for key, value := range overrides {
+			headers[key] = value
+		}
+	}
+
+	return headers
+}
+
+// lookupHost finds the per-host overrides for hostname, ignoring case
+func (rhc RequestHeadersConfig) lookupHost(hostname string) (map[string]string, bool) {
+	if hostname == "" || len(rhc.PerHost) == 0 {
+		return nil, false
+	}
+
+	if overrides, ok := rhc.PerHost[hostname]; ok {
+		return overrides, true
+	}
+
+	for host, overrides := range rhc.PerHost {
+		if strings.EqualFold(host, hostname) {
+			return overrides, true
+		}
+	}
+
+	return nil, false
+}
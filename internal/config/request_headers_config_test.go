@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestHeadersConfig_HeadersForHost(t *testing.T) {
+	rhc := RequestHeadersConfig{
+		Default: map[string]string{"Accept-Language": "en-US", "X-Scanner": "monsterinc"},
+		PerHost: map[string]map[string]string{
+			"Shop.Example.com": {"Accept-Language": "de-DE"},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"Accept-Language": "de-DE", "X-Scanner": "monsterinc"},
+		rhc.HeadersForHost("shop.example.com"), "per-host overrides win over the defaults, whatever the case")
+	assert.Equal(t, map[string]string{"Accept-Language": "en-US", "X-Scanner": "monsterinc"},
+		rhc.HeadersForHost("www.example.com"), "other hosts get the defaults")
+	assert.Equal(t, map[string]string{"Accept-Language": "en-US", "X-Scanner": "monsterinc"}, rhc.HeadersForHost(""))
+
+	headers := rhc.HeadersForHost("SHOP.EXAMPLE.COM")
+	headers["X-Scanner"] = "changed"
+	assert.Equal(t, "monsterinc", rhc.Default["X-Scanner"], "the returned map is a copy")
+	assert.Equal(t, "en-US", rhc.Default["Accept-Language"])
+}
+
+func TestRequestHeadersConfig_OverridesForHost(t *testing.T) {
+	rhc := RequestHeadersConfig{
+		Default: map[string]string{"Accept-Language": "en-US"},
+		PerHost: map[string]map[string]string{
+			"shop.example.com": {"Accept-Language": "de-DE"},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"Accept-Language": "de-DE"}, rhc.OverridesForHost("shop.example.com"))
+	assert.Equal(t, map[string]string{"Accept-Language": "de-DE"}, rhc.OverridesForHost("Shop.EXAMPLE.com"))
+	assert.Nil(t, rhc.OverridesForHost("example.com"), "hosts match exactly, not by parent domain")
+	assert.Nil(t, rhc.OverridesForHost(""))
+	assert.True(t, rhc.HasPerHostOverrides())
+	assert.False(t, NewDefaultRequestHeadersConfig().HasPerHostOverrides())
+}
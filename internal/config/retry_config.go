@@ -10,8 +10,35 @@ type RetryConfig struct {
 	MaxDelaySecs int `json:"max_delay_secs,omitempty" yaml:"max_delay_secs,omitempty" validate:"omitempty,min=1,max=3600"`
 	// Enable jitter to randomize delays slightly
 	EnableJitter bool `json:"enable_jitter" yaml:"enable_jitter"`
+	// Upper bound in seconds for the random jitter added to a delay (0 = no cap beyond 10% of the delay)
+	MaxJitterSecs int `json:"max_jitter_secs,omitempty" yaml:"max_jitter_secs,omitempty" validate:"omitempty,min=0,max=300"`
 	// HTTP status codes that should trigger retries (default: [429])
 	RetryStatusCodes []int `json:"retry_status_codes,omitempty" yaml:"retry_status_codes,omitempty"`
+	// Per-status-code backoff overrides; listed codes are retried even if missing from RetryStatusCodes
+	StatusBackoff map[int]StatusBackoffConfig `json:"status_backoff,omitempty" yaml:"status_backoff,omitempty" validate:"omitempty,dive,keys,min=100,max=599,endkeys,omitempty"`
+}
+
+// StatusBackoffConfig overrides the backoff delays for one HTTP status code.
+// Zero values fall back to the RetryConfig defaults.
+type StatusBackoffConfig struct {
+	// Base delay in seconds for exponential backoff
+	BaseDelaySecs int `json:"base_delay_secs,omitempty" yaml:"base_delay_secs,omitempty" validate:"omitempty,min=1,max=300"`
+	// Maximum delay in seconds for exponential backoff
+	MaxDelaySecs int `json:"max_delay_secs,omitempty" yaml:"max_delay_secs,omitempty" validate:"omitempty,min=1,max=3600"`
+}
+
+// BackoffFor returns the base and maximum delay in seconds to use for a status code
+func (rc RetryConfig) BackoffFor(statusCode int) (baseDelaySecs, maxDelaySecs int) {
+	baseDelaySecs, maxDelaySecs = rc.BaseDelaySecs, rc.MaxDelaySecs
+	if override, ok := rc.StatusBackoff[statusCode]; ok {
+		if override.BaseDelaySecs > 0 {
+			baseDelaySecs = override.BaseDelaySecs
+		}
+		if override.MaxDelaySecs > 0 {
+			maxDelaySecs = override.MaxDelaySecs
+		}
+	}
+	return baseDelaySecs, maxDelaySecs
 }
 
 // NewDefaultRetryConfig creates default retry configuration
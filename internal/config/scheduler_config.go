@@ -1,17 +1,91 @@
 package config
 
+import (
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/robfig/cron/v3"
+)
+
 // SchedulerConfig defines configuration for scheduler
 type SchedulerConfig struct {
 	CycleMinutes  int    `json:"cycle_minutes,omitempty" yaml:"cycle_minutes,omitempty" validate:"min=1"` // in minutes
 	RetryAttempts int    `json:"retry_attempts,omitempty" yaml:"retry_attempts,omitempty" validate:"min=0"`
 	SQLiteDBPath  string `json:"sqlite_db_path,omitempty" yaml:"sqlite_db_path,omitempty" validate:"required"`
+	// Standard 5-field cron spec (e.g. "0 2 * * *"); overrides CycleMinutes when set
+	CronExpression string `json:"cron_expression,omitempty" yaml:"cron_expression,omitempty" validate:"omitempty,cronexpr"`
+	// Warn when a scanned host's TLS certificate expires within this many days (0 = disabled)
+	TLSExpiryWarningDays int `json:"tls_expiry_warning_days" yaml:"tls_expiry_warning_days" validate:"min=0"`
+	// Notify when a scan probes a host that no earlier scan probed
+	NotifyNewHosts bool `json:"notify_new_hosts" yaml:"notify_new_hosts"`
+	// Periodic digest of the changes found by scheduled scans
+	Digest DigestConfig `json:"digest" yaml:"digest"`
+}
+
+// DigestConfig controls the periodic change digest sent in automated mode
+type DigestConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Standard 5-field cron spec for when the digest is sent (e.g. "0 9 * * 1", Mondays at 09:00)
+	CronExpression string `json:"cron_expression,omitempty" yaml:"cron_expression,omitempty" validate:"omitempty,cronexpr"`
+	// How many days of scan history each digest covers (0 uses the default of 7)
+	WindowDays int `json:"window_days,omitempty" yaml:"window_days,omitempty" validate:"min=0"`
+}
+
+// NewDefaultDigestConfig creates default change digest configuration
+func NewDefaultDigestConfig() DigestConfig {
+	return DigestConfig{
+		Enabled:        false,
+		CronExpression: DefaultSchedulerDigestCron,
+		WindowDays:     DefaultSchedulerDigestWindowDays,
+	}
+}
+
+// Window returns the period each digest covers
+func (dc DigestConfig) Window() time.Duration {
+	days := dc.WindowDays
+	if days <= 0 {
+		days = DefaultSchedulerDigestWindowDays
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
 
 // NewDefaultSchedulerConfig creates default scheduler configuration
 func NewDefaultSchedulerConfig() SchedulerConfig {
 	return SchedulerConfig{
-		CycleMinutes:  DefaultSchedulerScanIntervalMinutes,
-		RetryAttempts: DefaultSchedulerRetryAttempts,
-		SQLiteDBPath:  DefaultSchedulerSQLiteDBPath,
+		CycleMinutes:         DefaultSchedulerScanIntervalMinutes,
+		RetryAttempts:        DefaultSchedulerRetryAttempts,
+		SQLiteDBPath:         DefaultSchedulerSQLiteDBPath,
+		CronExpression:       "",
+		TLSExpiryWarningDays: DefaultSchedulerTLSExpiryWarnDays,
+		NotifyNewHosts:       DefaultSchedulerNotifyNewHosts,
+		Digest:               NewDefaultDigestConfig(),
+	}
+}
+
+// UsesCron reports whether scans fire on the cron expression instead of the fixed interval
+func (sc SchedulerConfig) UsesCron() bool {
+	return strings.TrimSpace(sc.CronExpression) != ""
+}
+
+// TLSExpiryWindow returns how far ahead certificate expiry is reported, or 0 when the check is disabled
+func (sc SchedulerConfig) TLSExpiryWindow() time.Duration {
+	if sc.TLSExpiryWarningDays <= 0 {
+		return 0
+	}
+	return time.Duration(sc.TLSExpiryWarningDays) * 24 * time.Hour
+}
+
+// NextCronTime returns the first fire time of a standard cron expression strictly after from
+func NextCronTime(expression string, from time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(strings.TrimSpace(expression))
+	if err != nil {
+		return time.Time{}, errorwrapper.WrapError(err, "invalid cron expression '"+expression+"'")
+	}
+
+	next := schedule.Next(from)
+	if next.IsZero() {
+		return time.Time{}, errorwrapper.NewValidationError("cron_expression", expression, "cron expression never fires")
 	}
+	return next, nil
 }
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of the generated schema, the one YAML editors support best
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+var (
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// GenerateJSONSchema describes the config file format as a JSON Schema, so editors can complete keys and flag
+// mistakes while a config is written. Properties are named after the yaml tags of GlobalConfig, carry the
+// values of NewDefaultGlobalConfig as defaults, and translate the validate tags that have a schema
+// equivalent (min/max/gte/lte/gt/lt, oneof, url, the log level, log format and mode checks). Unknown keys
+// are rejected. Checks needing the whole config, such as cross-field rules or file existence, are left to
+// ValidateConfig.
+func GenerateJSONSchema() map[string]any {
+	schema := schemaForStruct(reflect.TypeOf(GlobalConfig{}), reflect.ValueOf(*NewDefaultGlobalConfig()))
+	schema["$schema"] = JSONSchemaDraft
+	schema["title"] = "MonsterInc configuration"
+	schema["properties"].(map[string]any)[YAMLIncludesKey] = map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "string"},
+		"description": "Config files this file is laid over, relative to this file",
+	}
+	return schema
+}
+
+// schemaForStruct describes a struct as an object with one property per yaml-tagged field
+func schemaForStruct(t reflect.Type, defaults reflect.Value) map[string]any {
+	properties := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, inline, ok := schemaFieldName(field)
+		if !ok {
+			continue
+		}
+
+		var fieldDefault reflect.Value
+		if defaults.IsValid() {
+			fieldDefault = defaults.Field(i)
+		}
+		if inline {
+			inlined := schemaForType(field.Type, fieldDefault)
+			if inlinedProperties, ok := inlined["properties"].(map[string]any); ok {
+				for key, value := range inlinedProperties {
+					properties[key] = value
+				}
+			}
+			continue
+		}
+
+		property := schemaForType(field.Type, fieldDefault)
+		applyValidateTag(property, field.Tag.Get("validate"))
+		properties[name] = property
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// schemaFieldName returns the key of a field in YAML configs; ok is false for fields never read from a file
+func schemaFieldName(field reflect.StructField) (name string, inline bool, ok bool) {
+	if !field.IsExported() {
+		return "", false, false
+	}
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if strings.Contains(","+options+",", ",inline,") {
+		return "", true, true
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name) // yaml.v3's default key
+	}
+	return name, false, true
+}
+
+// schemaForType describes a Go type; defaultValue, when valid, becomes the default of scalars and lists
+func schemaForType(t reflect.Type, defaultValue reflect.Value) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		if defaultValue.IsValid() && !defaultValue.IsNil() {
+			defaultValue = defaultValue.Elem()
+		} else {
+			defaultValue = reflect.Value{}
+		}
+		t = t.Elem()
+	}
+
+	// Types decoding themselves may accept several shapes; the schema does not guess which
+	if schemaCustomDecoder(t) {
+		return map[string]any{}
+	}
+
+	schema := make(map[string]any)
+	switch t.Kind() {
+	case reflect.Struct:
+		return schemaForStruct(t, defaultValue)
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Slice, reflect.Array:
+		schema["type"] = "array"
+		schema["items"] = schemaForType(t.Elem(), reflect.Value{})
+	case reflect.Map:
+		schema["type"] = "object"
+		schema["additionalProperties"] = schemaForType(t.Elem(), reflect.Value{})
+		return schema // Map defaults are not shown
+	default:
+		return schema
+	}
+
+	if defaultValue.IsValid() && !(t.Kind() == reflect.Slice && defaultValue.IsNil()) {
+		schema["default"] = defaultValue.Interface()
+	}
+	return schema
+}
+
+// schemaCustomDecoder reports whether values of t are decoded by their own unmarshal method
+func schemaCustomDecoder(t reflect.Type) bool {
+	pointer := reflect.PointerTo(t)
+	return pointer.Implements(yamlUnmarshalerType) || pointer.Implements(jsonUnmarshalerType) || pointer.Implements(textUnmarshalerType)
+}
+
+// applyValidateTag translates the rules of a validate tag into schema keywords. Rules after "dive" apply to
+// the items of a list. Under omitempty the zero value, which stands for "use the default", stays valid.
+func applyValidateTag(schema map[string]any, tag string) {
+	if tag == "" || tag == "-" {
+		return
+	}
+
+	target := schema
+	constraints := make(map[string]any)
+	omitEmpty := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "omitempty":
+			omitEmpty = true
+		case "dive":
+			items, ok := target["items"].(map[string]any)
+			if !ok {
+				return
+			}
+			addSchemaConstraints(target, constraints, omitEmpty)
+			target, constraints, omitEmpty = items, make(map[string]any), false
+		case "min", "gte":
+			setSchemaBound(target, constraints, "minimum", "minLength", "minItems", param)
+		case "max", "lte":
+			setSchemaBound(target, constraints, "maximum", "maxLength", "maxItems", param)
+		case "gt":
+			setSchemaBound(target, constraints, "exclusiveMinimum", "", "", param)
+		case "lt":
+			setSchemaBound(target, constraints, "exclusiveMaximum", "", "", param)
+		case "oneof":
+			var values []any
+			for _, value := range strings.Fields(param) {
+				values = append(values, schemaEnumValue(target, value))
+			}
+			constraints["enum"] = values
+		case "url", "http_url", "proxyurl":
+			constraints["format"] = "uri"
+		case "loglevel":
+			constraints["enum"] = schemaEnum(LogLevels)
+		case "logformat":
+			constraints["enum"] = schemaEnum(LogFormats)
+		case "mode":
+			constraints["enum"] = schemaEnum(Modes)
+		}
+	}
+	addSchemaConstraints(target, constraints, omitEmpty)
+}
+
+// addSchemaConstraints adds the keywords of a validate tag to a schema. When the tag allows the zero value,
+// the keywords go under an anyOf next to it.
+func addSchemaConstraints(schema, constraints map[string]any, omitEmpty bool) {
+	if len(constraints) == 0 {
+		return
+	}
+	if zero, ok := schemaZeroValue(schema); ok && omitEmpty {
+		schema["anyOf"] = []any{constraints, map[string]any{"const": zero}}
+		return
+	}
+	for keyword, value := range constraints {
+		schema[keyword] = value
+	}
+}
+
+// schemaZeroValue returns the zero value of a schema's type as it appears in a config file
+func schemaZeroValue(schema map[string]any) (any, bool) {
+	switch schema["type"] {
+	case "integer", "number":
+		return 0, true
+	case "string":
+		return "", true
+	case "array":
+		return []any{}, true
+	default:
+		return nil, false
+	}
+}
+
+// setSchemaBound sets the keyword of a bound matching the schema's type: numbers, string lengths or list sizes
+func setSchemaBound(schema, constraints map[string]any, numberKeyword, stringKeyword, arrayKeyword, param string) {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	keyword := numberKeyword
+	switch schema["type"] {
+	case "string":
+		keyword = stringKeyword
+	case "array":
+		keyword = arrayKeyword
+	case "integer", "number":
+	default:
+		return
+	}
+	if keyword == "" {
+		return
+	}
+	if bound == float64(int64(bound)) {
+		constraints[keyword] = int64(bound)
+		return
+	}
+	constraints[keyword] = bound
+}
+
+// schemaEnumValue converts a oneof value to the schema's type
+func schemaEnumValue(schema map[string]any, value string) any {
+	if schema["type"] == "integer" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// schemaEnum lists values for an enum keyword
+func schemaEnum(values []string) []any {
+	enum := make([]any, 0, len(values))
+	for _, value := range values {
+		enum = append(enum, value)
+	}
+	return enum
+}
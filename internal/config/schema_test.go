@@ -0,0 +1,64 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func schemaProperty(t *testing.T, schema map[string]any, path ...string) map[string]any {
+	t.Helper()
+	current := schema
+	for _, key := range path {
+		properties, ok := current["properties"].(map[string]any)
+		require.True(t, ok, "no properties above %s", key)
+		current, ok = properties[key].(map[string]any)
+		require.True(t, ok, "no property %s", key)
+	}
+	return current
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	schema := GenerateJSONSchema()
+
+	assert.Equal(t, JSONSchemaDraft, schema["$schema"])
+	assert.Equal(t, false, schema["additionalProperties"], "unknown keys are rejected")
+	assert.Equal(t, "array", schemaProperty(t, schema, YAMLIncludesKey)["type"])
+
+	mode := schemaProperty(t, schema, "mode")
+	assert.Equal(t, "string", mode["type"])
+	assert.Equal(t, []any{"onetime", "automated"}, mode["enum"])
+
+	logLevel := schemaProperty(t, schema, "log_config", "log_level")
+	assert.Equal(t, DefaultLogLevel, logLevel["default"])
+	require.Len(t, logLevel["anyOf"], 2, "omitempty keeps the empty value valid")
+	assert.Equal(t, map[string]any{"const": ""}, logLevel["anyOf"].([]any)[1])
+
+	webhooks := schemaProperty(t, schema, "notification_config", "scan_service_discord_webhook_url")
+	assert.Empty(t, webhooks, "a value decoding itself may take several shapes")
+
+	_, err := json.Marshal(schema)
+	require.NoError(t, err)
+}
+
+func TestApplyValidateTag(t *testing.T) {
+	integer := map[string]any{"type": "integer"}
+	applyValidateTag(integer, "min=0,max=100")
+	assert.Equal(t, map[string]any{"type": "integer", "minimum": int64(0), "maximum": int64(100)}, integer)
+
+	optional := map[string]any{"type": "integer"}
+	applyValidateTag(optional, "omitempty,min=1")
+	assert.Equal(t, []any{map[string]any{"minimum": int64(1)}, map[string]any{"const": 0}}, optional["anyOf"])
+
+	list := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	applyValidateTag(list, "omitempty,dive,oneof=http https")
+	assert.Equal(t, []any{"http", "https"}, list["items"].(map[string]any)["enum"])
+	assert.NotContains(t, list, "anyOf")
+
+	fraction := map[string]any{"type": "number"}
+	applyValidateTag(fraction, "gt=0,lt=1")
+	assert.Equal(t, int64(0), fraction["exclusiveMinimum"])
+	assert.Equal(t, int64(1), fraction["exclusiveMaximum"])
+}
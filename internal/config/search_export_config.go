@@ -0,0 +1,34 @@
+package config
+
+// SearchExportConfig defines bulk indexing of probe results into Elasticsearch or OpenSearch after each scan
+type SearchExportConfig struct {
+	// Index every scan's probe results into the cluster
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Base URL of the cluster, e.g. https://search.example.com:9200
+	URL string `json:"url,omitempty" yaml:"url,omitempty" validate:"omitempty,url"`
+	// Index name; {date} and {session} are expanded per scan (e.g. "monsterinc-{date}")
+	Index string `json:"index,omitempty" yaml:"index,omitempty"`
+	// Basic authentication credentials (optional)
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	// API key sent as "Authorization: ApiKey <key>"; takes precedence over basic authentication
+	APIKey string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	// Documents sent per bulk request
+	BatchSize int `json:"batch_size,omitempty" yaml:"batch_size,omitempty" validate:"omitempty,min=1"`
+	// Retries of a bulk request, or of its rejected documents, when the cluster pushes back (429/503)
+	MaxRetries int `json:"max_retries,omitempty" yaml:"max_retries,omitempty" validate:"omitempty,min=0"`
+	// Timeout of one bulk request
+	TimeoutSecs int `json:"timeout_secs,omitempty" yaml:"timeout_secs,omitempty" validate:"omitempty,min=1"`
+}
+
+// NewDefaultSearchExportConfig creates default search export configuration (disabled)
+func NewDefaultSearchExportConfig() SearchExportConfig {
+	return SearchExportConfig{
+		Enabled:     false,
+		URL:         "",
+		Index:       DefaultSearchExportIndex,
+		BatchSize:   DefaultSearchExportBatchSize,
+		MaxRetries:  DefaultSearchExportMaxRetries,
+		TimeoutSecs: DefaultSearchExportTimeoutSecs,
+	}
+}
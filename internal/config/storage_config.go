@@ -1,9 +1,90 @@
 package config
 
+import (
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+)
+
+// Storage backends selectable with StorageConfig.Backend
+const (
+	StorageBackendLocal = "local"
+	StorageBackendS3    = "s3"
+)
+
 // StorageConfig defines configuration for data storage
 type StorageConfig struct {
-	CompressionCodec string `json:"compression_codec,omitempty" yaml:"compression_codec,omitempty"`
-	ParquetBasePath  string `json:"parquet_base_path,omitempty" yaml:"parquet_base_path,omitempty"`
+	CompressionCodec string             `json:"compression_codec,omitempty" yaml:"compression_codec,omitempty" validate:"omitempty,oneof=zstd gzip snappy none"`
+	CompressionLevel int                `json:"compression_level,omitempty" yaml:"compression_level,omitempty" validate:"omitempty,min=1,max=22"` // zstd 1-22 or gzip 1-9; 0 uses the codec default
+	ParquetBasePath  string             `json:"parquet_base_path,omitempty" yaml:"parquet_base_path,omitempty"`
+	URLLifecycle     URLLifecycleConfig `json:"url_lifecycle,omitempty" yaml:"url_lifecycle,omitempty"`
+	ResponseBodies   ResponseBodyConfig `json:"response_bodies,omitempty" yaml:"response_bodies,omitempty"`
+	Retention        RetentionConfig    `json:"retention,omitempty" yaml:"retention,omitempty"`
+	RowGroupSize     int                `json:"row_group_size,omitempty" yaml:"row_group_size,omitempty" validate:"omitempty,min=100"` // Max rows per Parquet row group; 0 uses the library default (unbounded)
+	PageSize         int                `json:"page_size,omitempty" yaml:"page_size,omitempty" validate:"omitempty,min=4096"`          // Page buffer size in bytes; 0 uses the library default (256 KiB)
+	Backend          string             `json:"backend,omitempty" yaml:"backend,omitempty" validate:"omitempty,oneof=local s3"`        // Where Parquet files live: "local" (default) or "s3"
+	S3               S3StorageConfig    `json:"s3,omitempty" yaml:"s3,omitempty"`
+}
+
+// S3StorageConfig configures the S3-compatible storage backend. Objects are stored under
+// ParquetBasePath inside the bucket, mirroring the local layout. Any store speaking the S3 API
+// works: AWS S3, MinIO, or Google Cloud Storage through its interoperability endpoint with HMAC keys.
+// Empty credentials fall back to AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+type S3StorageConfig struct {
+	Endpoint        string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"` // e.g. http://minio:9000 or https://storage.googleapis.com; empty uses AWS S3 in Region
+	Region          string `json:"region,omitempty" yaml:"region,omitempty"`
+	Bucket          string `json:"bucket,omitempty" yaml:"bucket,omitempty"`
+	AccessKeyID     string `json:"access_key_id,omitempty" yaml:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty" yaml:"secret_access_key,omitempty"`
+	SessionToken    string `json:"session_token,omitempty" yaml:"session_token,omitempty"`
+	PathStyle       bool   `json:"path_style,omitempty" yaml:"path_style,omitempty"` // Address the bucket as endpoint/bucket instead of bucket.endpoint (MinIO)
+
+	// Outbound proxy for S3 requests; populated from GlobalConfig.ProxyConfig when the config is loaded
+	Proxy httpclient.ProxyConfig `json:"-" yaml:"-"`
+}
+
+// URLLifecycleConfig controls first-seen/last-seen tracking of URLs across scans.
+// When enabled, URLs that disappear from a scan stay in Parquet with their last-seen
+// timestamp and a count of consecutive scans they were missing from.
+type URLLifecycleConfig struct {
+	Enabled        bool `json:"enabled" yaml:"enabled"`
+	MaxMissedScans int  `json:"max_missed_scans,omitempty" yaml:"max_missed_scans,omitempty" validate:"omitempty,min=0"` // 0 keeps unseen URLs forever
+}
+
+// ResponseBodyConfig controls persisting probe response bodies in the scan Parquet files.
+// Bodies are gzip-compressed and capped in size; they are only available when
+// httpx_runner_config.extract_body is enabled.
+type ResponseBodyConfig struct {
+	Enabled   bool                 `json:"enabled" yaml:"enabled"`
+	MaxSizeKB int                  `json:"max_size_kb,omitempty" yaml:"max_size_kb,omitempty" validate:"omitempty,min=1"` // Bodies are truncated to this size before compression
+	Archive   ContentArchiveConfig `json:"archive,omitempty" yaml:"archive,omitempty"`
+}
+
+// Content archive formats selectable with ContentArchiveConfig.Format
+const (
+	ContentArchiveFormatRaw  = "raw"
+	ContentArchiveFormatGzip = "gzip"
+)
+
+// ContentArchiveConfig controls additionally writing the full body of every URL whose content changed
+// since the previous scan to Dir, as <host>/<url hash>/<scan time>.<ext>, for manual inspection and
+// other tools. Changes are found by comparing against the stored bodies, so the archive needs
+// response_bodies.enabled. It always lives on local disk and is capped by storage_config.retention.
+type ContentArchiveConfig struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Dir     string `json:"dir,omitempty" yaml:"dir,omitempty"`
+	Format  string `json:"format,omitempty" yaml:"format,omitempty" validate:"omitempty,oneof=raw gzip"` // "raw" keeps the bytes as received, "gzip" compresses each file
+}
+
+// RetentionConfig controls cleanup of archived files under ParquetBasePath: quarantined .corrupt backups
+// and temporary files left by interrupted writes. A target's live Parquet file holds the latest record of
+// every URL and is never removed. The same limits apply per host to the content archive.
+type RetentionConfig struct {
+	Enabled              bool `json:"enabled" yaml:"enabled"`
+	MaxAgeDays           int  `json:"max_age_days,omitempty" yaml:"max_age_days,omitempty" validate:"omitempty,min=0"`                     // Archived files older than this are deleted; 0 keeps them regardless of age
+	MaxTotalSizeMB       int  `json:"max_total_size_mb,omitempty" yaml:"max_total_size_mb,omitempty" validate:"omitempty,min=0"`           // Per-target cap on live file plus archives; oldest archives go first, 0 = no cap
+	CleanupIntervalHours int  `json:"cleanup_interval_hours,omitempty" yaml:"cleanup_interval_hours,omitempty" validate:"omitempty,min=0"` // How often automated mode repeats the cleanup after the one at startup
 }
 
 // NewDefaultStorageConfig creates default storage configuration
@@ -11,5 +92,107 @@ func NewDefaultStorageConfig() StorageConfig {
 	return StorageConfig{
 		CompressionCodec: DefaultStorageCompressionCodec,
 		ParquetBasePath:  DefaultStorageParquetBasePath,
+		URLLifecycle:     NewDefaultURLLifecycleConfig(),
+		ResponseBodies:   NewDefaultResponseBodyConfig(),
+		Retention:        NewDefaultRetentionConfig(),
+		RowGroupSize:     DefaultStorageRowGroupSize,
+		PageSize:         DefaultStoragePageSize,
+		Backend:          DefaultStorageBackend,
+		S3:               NewDefaultS3StorageConfig(),
+	}
+}
+
+// NewDefaultS3StorageConfig creates default S3 backend configuration
+func NewDefaultS3StorageConfig() S3StorageConfig {
+	return S3StorageConfig{
+		Region: DefaultStorageS3Region,
+	}
+}
+
+// NewDefaultURLLifecycleConfig creates default URL lifecycle tracking configuration
+func NewDefaultURLLifecycleConfig() URLLifecycleConfig {
+	return URLLifecycleConfig{
+		Enabled:        false,
+		MaxMissedScans: DefaultURLLifecycleMaxMissedScans,
+	}
+}
+
+// NewDefaultResponseBodyConfig creates default response body storage configuration
+func NewDefaultResponseBodyConfig() ResponseBodyConfig {
+	return ResponseBodyConfig{
+		Enabled:   false,
+		MaxSizeKB: DefaultStorageResponseBodyMaxSizeKB,
+		Archive:   NewDefaultContentArchiveConfig(),
+	}
+}
+
+// NewDefaultContentArchiveConfig creates default content archive configuration
+func NewDefaultContentArchiveConfig() ContentArchiveConfig {
+	return ContentArchiveConfig{
+		Enabled: false,
+		Dir:     DefaultContentArchiveDir,
+		Format:  ContentArchiveFormatRaw,
+	}
+}
+
+// NewDefaultRetentionConfig creates default retention configuration
+func NewDefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		Enabled:              false,
+		MaxAgeDays:           DefaultRetentionMaxAgeDays,
+		MaxTotalSizeMB:       DefaultRetentionMaxTotalSizeMB,
+		CleanupIntervalHours: DefaultRetentionCleanupIntervalHours,
+	}
+}
+
+// CleanupInterval returns the time between periodic cleanups
+func (rc RetentionConfig) CleanupInterval() time.Duration {
+	if rc.CleanupIntervalHours <= 0 {
+		return DefaultRetentionCleanupIntervalHours * time.Hour
+	}
+	return time.Duration(rc.CleanupIntervalHours) * time.Hour
+}
+
+// MaxAge returns how long archived files are kept, or 0 when age does not matter
+func (rc RetentionConfig) MaxAge() time.Duration {
+	return time.Duration(rc.MaxAgeDays) * 24 * time.Hour
+}
+
+// MaxTotalSizeBytes returns the per-target size cap in bytes, or 0 when there is none
+func (rc RetentionConfig) MaxTotalSizeBytes() int64 {
+	return int64(rc.MaxTotalSizeMB) * 1024 * 1024
+}
+
+// MaxSizeBytes returns the body size cap in bytes
+func (rbc ResponseBodyConfig) MaxSizeBytes() int {
+	if rbc.MaxSizeKB <= 0 {
+		return DefaultStorageResponseBodyMaxSizeKB * 1024
+	}
+	return rbc.MaxSizeKB * 1024
+}
+
+// CompressionLevelRange returns the compression levels accepted by the configured codec.
+// supported is false for codecs without a level setting (snappy, none).
+func (sc StorageConfig) CompressionLevelRange() (minLevel, maxLevel int, supported bool) {
+	switch strings.ToLower(sc.CompressionCodec) {
+	case "", "zstd":
+		return 1, 22, true
+	case "gzip":
+		return 1, 9, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// UsesS3 reports whether Parquet files are kept in an S3-compatible bucket
+func (sc StorageConfig) UsesS3() bool {
+	return strings.EqualFold(sc.Backend, StorageBackendS3)
+}
+
+// ShouldRetain reports whether a URL missing from the given number of consecutive scans is still kept
+func (ulc URLLifecycleConfig) ShouldRetain(missedScans int) bool {
+	if !ulc.Enabled {
+		return false
 	}
+	return ulc.MaxMissedScans <= 0 || missedScans <= ulc.MaxMissedScans
 }
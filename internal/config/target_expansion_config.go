@@ -0,0 +1,88 @@
+package config
+
+import (
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+)
+
+// TargetExpansionConfig defines how CIDR ranges and wildcard domains in target files are expanded into host URLs
+type TargetExpansionConfig struct {
+	// URL schemes generated for every host in a range
+	Schemes []string `json:"schemes,omitempty" yaml:"schemes,omitempty" validate:"omitempty,dive,oneof=http https"`
+	// Ports generated for every host; empty uses the scheme's default port
+	Ports []int `json:"ports,omitempty" yaml:"ports,omitempty" validate:"omitempty,dive,min=1,max=65535"`
+	// Maximum number of URLs a single range may expand to; larger ranges are rejected. 0 uses the default
+	MaxExpansion int `json:"max_expansion,omitempty" yaml:"max_expansion,omitempty" validate:"omitempty,min=1"`
+	// Expansion of "*.example.com" targets into known subdomains
+	Wildcards WildcardExpansionConfig `json:"wildcards,omitempty" yaml:"wildcards,omitempty"`
+}
+
+// NewDefaultTargetExpansionConfig creates default target expansion configuration
+func NewDefaultTargetExpansionConfig() TargetExpansionConfig {
+	return TargetExpansionConfig{
+		Schemes:      []string{"http", "https"},
+		Ports:        []int{},
+		MaxExpansion: DefaultTargetExpansionMaxExpansion,
+		Wildcards:    NewDefaultWildcardExpansionConfig(),
+	}
+}
+
+// ToTargetExpansionConfig converts TargetExpansionConfig to urlhandler.TargetExpansionConfig
+func (tec TargetExpansionConfig) ToTargetExpansionConfig() urlhandler.TargetExpansionConfig {
+	return urlhandler.TargetExpansionConfig{
+		Schemes:      tec.Schemes,
+		Ports:        tec.Ports,
+		MaxExpansion: tec.MaxExpansion,
+		Wildcards:    tec.Wildcards.ToWildcardExpansionConfig(),
+	}
+}
+
+// WildcardExpansionConfig defines how "*.example.com" targets are expanded into known subdomains
+type WildcardExpansionConfig struct {
+	// Expand wildcard targets; when disabled they are skipped with a warning
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// File of known hostnames, one per line (e.g. subfinder or amass output); URLs and "*." prefixes are accepted
+	SourceFile string `json:"source_file,omitempty" yaml:"source_file,omitempty"`
+	// Look up hostnames in certificate transparency logs through a crt.sh-compatible endpoint
+	CertTransparency bool `json:"cert_transparency" yaml:"cert_transparency"`
+	// Base URL of the crt.sh-compatible endpoint
+	CTEndpoint string `json:"ct_endpoint,omitempty" yaml:"ct_endpoint,omitempty" validate:"omitempty,url"`
+	// Timeout of one certificate transparency lookup
+	CTTimeoutSecs int `json:"ct_timeout_secs,omitempty" yaml:"ct_timeout_secs,omitempty" validate:"omitempty,min=1"`
+	// Maximum number of hosts a single wildcard may expand to, apex included; 0 uses the default
+	MaxSubdomains int `json:"max_subdomains,omitempty" yaml:"max_subdomains,omitempty" validate:"omitempty,min=1"`
+
+	// Proxy for the certificate transparency lookup, set from proxy_config by LoadGlobalConfig
+	Proxy httpclient.ProxyConfig `json:"-" yaml:"-"`
+	// Crawler scope exclusions, set from crawler_config.scope by LoadGlobalConfig; expanded hosts they
+	// rule out are dropped instead of becoming targets of their own
+	DisallowedHostnames  []string `json:"-" yaml:"-"`
+	DisallowedSubdomains []string `json:"-" yaml:"-"`
+}
+
+// NewDefaultWildcardExpansionConfig creates default wildcard expansion configuration (disabled)
+func NewDefaultWildcardExpansionConfig() WildcardExpansionConfig {
+	return WildcardExpansionConfig{
+		Enabled:          false,
+		SourceFile:       "",
+		CertTransparency: false,
+		CTEndpoint:       DefaultWildcardCTEndpoint,
+		CTTimeoutSecs:    DefaultWildcardCTTimeoutSecs,
+		MaxSubdomains:    DefaultWildcardMaxSubdomains,
+	}
+}
+
+// ToWildcardExpansionConfig converts WildcardExpansionConfig to urlhandler.WildcardExpansionConfig
+func (wec WildcardExpansionConfig) ToWildcardExpansionConfig() urlhandler.WildcardExpansionConfig {
+	return urlhandler.WildcardExpansionConfig{
+		Enabled:              wec.Enabled,
+		SourceFile:           wec.SourceFile,
+		CertTransparency:     wec.CertTransparency,
+		CTEndpoint:           wec.CTEndpoint,
+		CTTimeoutSecs:        wec.CTTimeoutSecs,
+		MaxSubdomains:        wec.MaxSubdomains,
+		Proxy:                wec.Proxy,
+		DisallowedHostnames:  wec.DisallowedHostnames,
+		DisallowedSubdomains: wec.DisallowedSubdomains,
+	}
+}
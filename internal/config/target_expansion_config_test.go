@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetExpansionConfig_DefaultsMatchURLHandler(t *testing.T) {
+	assert.Equal(t, urlhandler.DefaultTargetExpansionConfig(), NewDefaultTargetExpansionConfig().ToTargetExpansionConfig())
+}
+
+func TestTargetExpansionConfig_ToTargetExpansionConfig(t *testing.T) {
+	cfg := NewDefaultGlobalConfig()
+	cfg.ProxyConfig = httpclient.ProxyConfig{URL: "http://proxy.internal:3128"}
+	cfg.CrawlerConfig.Scope.DisallowedHostnames = []string{"internal.example.com"}
+	cfg.CrawlerConfig.Scope.DisallowedSubdomains = []string{"dev"}
+	cfg.TargetExpansion.Ports = []int{8443}
+	cfg.TargetExpansion.Wildcards.Enabled = true
+	cfg.TargetExpansion.Wildcards.SourceFile = "subdomains.txt"
+	shareProxyConfig(cfg)
+	shareCrawlerScope(cfg)
+
+	converted := cfg.TargetExpansion.ToTargetExpansionConfig()
+	assert.Equal(t, []int{8443}, converted.Ports)
+	assert.Equal(t, []string{"http", "https"}, converted.Schemes)
+	assert.True(t, converted.Wildcards.Enabled)
+	assert.Equal(t, "subdomains.txt", converted.Wildcards.SourceFile)
+	assert.Equal(t, "http://proxy.internal:3128", converted.Wildcards.Proxy.URL)
+	assert.Equal(t, []string{"internal.example.com"}, converted.Wildcards.DisallowedHostnames)
+	assert.Equal(t, []string{"dev"}, converted.Wildcards.DisallowedSubdomains)
+}
@@ -0,0 +1,38 @@
+package config
+
+import "github.com/aleister1102/monsterinc/internal/common/urlhandler"
+
+// TargetSamplingConfig reduces a large target set to a sample before scanning, e.g. to try a config change
+// against a representative slice of a 100k-URL list
+type TargetSamplingConfig struct {
+	// Scan at most this many targets; 0 disables. Cannot be combined with Percent
+	Count int `json:"count,omitempty" yaml:"count,omitempty" validate:"omitempty,min=0"`
+	// Scan this percentage of the targets, rounded up to at least one; 0 disables
+	Percent float64 `json:"percent,omitempty" yaml:"percent,omitempty" validate:"omitempty,gte=0,lte=100"`
+	// Seed of the selection; the same seed and target list always yield the same sample. With 0 the CLI
+	// picks a new seed for each run and prints it
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+}
+
+// NewDefaultTargetSamplingConfig creates default target sampling configuration (sampling disabled)
+func NewDefaultTargetSamplingConfig() TargetSamplingConfig {
+	return TargetSamplingConfig{
+		Count:   0,
+		Percent: 0,
+		Seed:    0,
+	}
+}
+
+// Enabled reports whether a sample size is configured
+func (tsc TargetSamplingConfig) Enabled() bool {
+	return tsc.Count > 0 || tsc.Percent > 0
+}
+
+// ToTargetSamplingConfig converts TargetSamplingConfig to urlhandler.TargetSamplingConfig
+func (tsc TargetSamplingConfig) ToTargetSamplingConfig() urlhandler.TargetSamplingConfig {
+	return urlhandler.TargetSamplingConfig{
+		Count:   tsc.Count,
+		Percent: tsc.Percent,
+		Seed:    tsc.Seed,
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetSamplingConfig_ToTargetSamplingConfig(t *testing.T) {
+	assert.Equal(t, urlhandler.DefaultTargetSamplingConfig(), NewDefaultTargetSamplingConfig().ToTargetSamplingConfig())
+
+	cfg := TargetSamplingConfig{Percent: 2.5, Seed: 42}
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, urlhandler.TargetSamplingConfig{Percent: 2.5, Seed: 42}, cfg.ToTargetSamplingConfig())
+	assert.False(t, NewDefaultTargetSamplingConfig().Enabled())
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/filemanager"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigUpgradeResult describes the outcome of upgrading a config file
+type ConfigUpgradeResult struct {
+	FilePath    string
+	AddedFields []string // Dotted paths of fields filled in from defaults
+}
+
+// ConfigUpgrader fills missing fields of an existing config file with the values from NewDefaultGlobalConfig,
+// keeping user-set values and the original file format (YAML or JSON)
+type ConfigUpgrader struct {
+	fileManager *filemanager.FileManager
+	logger      zerolog.Logger
+}
+
+// NewConfigUpgrader creates a new ConfigUpgrader
+func NewConfigUpgrader(logger zerolog.Logger) *ConfigUpgrader {
+	return &ConfigUpgrader{
+		fileManager: filemanager.NewFileManager(logger),
+		logger:      logger.With().Str("component", "ConfigUpgrader").Logger(),
+	}
+}
+
+// UpgradeFile merges missing default fields into the config file at filePath and writes it back in place.
+// The file is left untouched when nothing is missing.
+func (cu *ConfigUpgrader) UpgradeFile(filePath string) (*ConfigUpgradeResult, error) {
+	if !cu.fileManager.FileExists(filePath) {
+		return nil, errorwrapper.NewValidationError("config_file", filePath, "config file does not exist")
+	}
+
+	data, err := loadConfigFileContent(cu.fileManager, filePath)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to load config file content")
+	}
+
+	// Reject files the loader itself would not accept before rewriting them
+	if err := parseConfigContent(data, filePath, NewDefaultGlobalConfig()); err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to parse config content")
+	}
+
+	var upgraded []byte
+	var added []string
+	if isYAMLFile(filepath.Ext(filePath)) {
+		// Defaults written into the including file would override the values of the included files
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err == nil && usesYAMLIncludes(&doc) {
+			return nil, errorwrapper.NewValidationError("config_file", filePath, "config files with includes cannot be upgraded in place")
+		}
+
+		upgraded, added, err = upgradeYAMLConfig(data)
+	} else {
+		upgraded, added, err = upgradeJSONConfig(data)
+	}
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to merge default config values")
+	}
+
+	result := &ConfigUpgradeResult{FilePath: filePath, AddedFields: added}
+	if len(added) == 0 {
+		cu.logger.Info().Str("path", filePath).Msg("Config file already contains all fields")
+		return result, nil
+	}
+
+	opts := filemanager.DefaultFileWriteOptions()
+	if info, statErr := os.Stat(filePath); statErr == nil {
+		opts.Permissions = info.Mode().Perm()
+	}
+	if err := cu.fileManager.WriteFile(filePath, upgraded, opts); err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to write upgraded config file")
+	}
+
+	cu.logger.Info().Str("path", filePath).Int("added_fields", len(added)).Msg("Config file upgraded")
+	return result, nil
+}
+
+// upgradeYAMLConfig merges defaults at the node level so comments and key order of the original file survive
+func upgradeYAMLConfig(data []byte) ([]byte, []string, error) {
+	defaultsData, err := yaml.Marshal(NewDefaultGlobalConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var defaultsDoc yaml.Node
+	if err := yaml.Unmarshal(defaultsData, &defaultsDoc); err != nil {
+		return nil, nil, err
+	}
+
+	var userDoc yaml.Node
+	if err := yaml.Unmarshal(data, &userDoc); err != nil {
+		return nil, nil, err
+	}
+
+	var added []string
+	if len(userDoc.Content) == 0 {
+		userDoc = defaultsDoc
+		added = collectYAMLKeys(defaultsDoc.Content[0], "")
+	} else {
+		mergeMissingYAMLKeys(userDoc.Content[0], defaultsDoc.Content[0], "", &added)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&userDoc); err != nil {
+		return nil, nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), added, nil
+}
+
+// mergeMissingYAMLKeys appends keys present in src but absent from dst, recursing into nested mappings
+func mergeMissingYAMLKeys(dst, src *yaml.Node, prefix string, added *[]string) {
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		srcKey, srcValue := src.Content[i], src.Content[i+1]
+		path := joinConfigPath(prefix, srcKey.Value)
+
+		dstValue := findYAMLMappingValue(dst, srcKey.Value)
+		if dstValue == nil {
+			dst.Content = append(dst.Content, srcKey, srcValue)
+			*added = append(*added, path)
+			continue
+		}
+
+		mergeMissingYAMLKeys(dstValue, srcValue, path, added)
+	}
+}
+
+// findYAMLMappingValue returns the value node for key in a mapping node, or nil
+func findYAMLMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// collectYAMLKeys lists the top-level keys of a mapping node as dotted paths
+func collectYAMLKeys(mapping *yaml.Node, prefix string) []string {
+	var keys []string
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keys = append(keys, joinConfigPath(prefix, mapping.Content[i].Value))
+	}
+	return keys
+}
+
+// upgradeJSONConfig merges defaults into the decoded JSON object and re-encodes it with two-space indentation
+func upgradeJSONConfig(data []byte) ([]byte, []string, error) {
+	defaultsData, err := json.Marshal(NewDefaultGlobalConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defaults, err := decodeJSONObject(defaultsData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userConfig, err := decodeJSONObject(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var added []string
+	mergeMissingJSONKeys(userConfig, defaults, "", &added)
+	sort.Strings(added)
+
+	upgraded, err := json.MarshalIndent(userConfig, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(upgraded, '\n'), added, nil
+}
+
+// decodeJSONObject decodes a JSON object keeping numbers verbatim
+func decodeJSONObject(data []byte) (map[string]interface{}, error) {
+	object := make(map[string]interface{})
+	if len(bytes.TrimSpace(data)) == 0 {
+		return object, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// mergeMissingJSONKeys copies keys present in src but absent from dst, recursing into nested objects
+func mergeMissingJSONKeys(dst, src map[string]interface{}, prefix string, added *[]string) {
+	for key, srcValue := range src {
+		path := joinConfigPath(prefix, key)
+
+		dstValue, exists := dst[key]
+		if !exists {
+			dst[key] = srcValue
+			*added = append(*added, path)
+			continue
+		}
+
+		dstObject, dstIsObject := dstValue.(map[string]interface{})
+		srcObject, srcIsObject := srcValue.(map[string]interface{})
+		if dstIsObject && srcIsObject {
+			mergeMissingJSONKeys(dstObject, srcObject, path, added)
+		}
+	}
+}
+
+// joinConfigPath builds a dotted field path
+func joinConfigPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfigUpgrader_UpgradeYAMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := "# keep me\nmode: automated\nscheduler_config:\n  cycle_minutes: 30 # custom\n"
+	require.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+	result, err := NewConfigUpgrader(zerolog.Nop()).UpgradeFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, result.AddedFields, "scan_batch_config")
+	assert.Contains(t, result.AddedFields, "scheduler_config.retry_attempts")
+	assert.NotContains(t, result.AddedFields, "mode")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# keep me\nmode: automated\n"))
+	assert.Contains(t, string(data), "cycle_minutes: 30 # custom")
+
+	cfg := NewDefaultGlobalConfig()
+	require.NoError(t, yaml.Unmarshal(data, cfg))
+	assert.Equal(t, 30, cfg.SchedulerConfig.CycleMinutes)
+	assert.Equal(t, DefaultSchedulerRetryAttempts, cfg.SchedulerConfig.RetryAttempts)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	second, err := NewConfigUpgrader(zerolog.Nop()).UpgradeFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, second.AddedFields)
+}
+
+func TestConfigUpgrader_UpgradeJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"mode":"onetime","crawler_config":{"max_depth":1}}`), 0644))
+
+	result, err := NewConfigUpgrader(zerolog.Nop()).UpgradeFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, result.AddedFields, "crawler_config.retry_config")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	cfg := NewDefaultGlobalConfig()
+	require.NoError(t, json.Unmarshal(data, cfg))
+	assert.Equal(t, 1, cfg.CrawlerConfig.MaxDepth)
+	assert.Equal(t, NewDefaultRetryConfig(), cfg.CrawlerConfig.RetryConfig)
+}
@@ -3,12 +3,16 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
 	"github.com/aleister1102/monsterinc/internal/common/filemanager"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
 	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog"
@@ -27,6 +31,15 @@ func NewConfigValidator(logger zerolog.Logger) *ConfigValidator {
 		logger:    logger,
 	}
 
+	// Report fields by their config keys, e.g. notification_config.max_embed_fields
+	cv.validator.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
 	cv.registerCustomValidations()
 	return cv
 }
@@ -40,12 +53,166 @@ func ValidateConfig(cfg *GlobalConfig) error {
 
 // Validate performs validation on the GlobalConfig structure
 func (cv *ConfigValidator) Validate(cfg *GlobalConfig) error {
-	validationView := cv.createValidationView(cfg)
+	problems := cv.Problems(cfg)
+	if len(problems) == 0 {
+		return nil
+	}
+	return errorwrapper.NewError("configuration validation failed:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// crossFieldCheckedKeys are config keys whose field rules crossFieldProblems reports with a clearer message
+var crossFieldCheckedKeys = map[string]bool{"mode": true}
+
+// Problems returns every validation problem in cfg as a readable message: field rules,
+// the compression level, and rules spanning several sections. An empty result means cfg is valid.
+func (cv *ConfigValidator) Problems(cfg *GlobalConfig) []string {
+	problems := cv.fieldProblems(cfg)
+	for _, problem := range cv.fieldProblems(cv.createValidationView(cfg)) {
+		if !slices.Contains(problems, problem) {
+			problems = append(problems, problem)
+		}
+	}
+
+	if err := cv.validateCompressionLevel(cfg.StorageConfig); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return append(problems, cv.crossFieldProblems(cfg)...)
+}
+
+// fieldProblems checks the validate tags of a config struct
+func (cv *ConfigValidator) fieldProblems(s interface{}) []string {
+	err := cv.validator.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+	root := reflect.Indirect(reflect.ValueOf(s)).Type().Name()
+
+	var messages []string
+	for _, fieldErr := range validationErrors {
+		key := cv.getFieldName(fieldErr, root)
+		if !crossFieldCheckedKeys[key] {
+			messages = append(messages, cv.formatSingleValidationError(fieldErr, key))
+		}
+	}
+	return messages
+}
+
+// crossFieldProblems checks rules that depend on more than one setting
+func (cv *ConfigValidator) crossFieldProblems(cfg *GlobalConfig) []string {
+	var problems []string
+
+	if !cv.validateMode(cfg.Mode) || cfg.Mode == "" {
+		problems = append(problems, fmt.Sprintf("mode must be 'onetime' or 'automated', got '%s'", cfg.Mode))
+	}
+
+	if strings.EqualFold(cfg.Mode, "automated") {
+		if strings.TrimSpace(cfg.SchedulerConfig.SQLiteDBPath) == "" {
+			problems = append(problems, "automated mode requires scheduler_config.sqlite_db_path")
+		}
+		if !cfg.SchedulerConfig.UsesCron() && cfg.SchedulerConfig.CycleMinutes < 1 {
+			problems = append(problems, "automated mode requires scheduler_config.cycle_minutes >= 1 or a cron_expression")
+		}
+	}
+	if cfg.SchedulerConfig.Digest.Enabled && strings.TrimSpace(cfg.SchedulerConfig.Digest.CronExpression) == "" {
+		problems = append(problems, "scheduler_config.digest.enabled requires scheduler_config.digest.cron_expression")
+	}
+
+	if strings.TrimSpace(cfg.StorageConfig.ParquetBasePath) == "" {
+		problems = append(problems, "storage_config.parquet_base_path must not be empty")
+	}
+	if strings.Contains(cfg.StorageConfig.ParquetBasePath, PathTokenSession) {
+		problems = append(problems, "storage_config.parquet_base_path cannot use {session}; scan history must be shared across scans")
+	}
+	if cfg.StorageConfig.UsesS3() && strings.TrimSpace(cfg.StorageConfig.S3.Bucket) == "" {
+		problems = append(problems, "storage_config.backend s3 requires storage_config.s3.bucket")
+	}
+
+	if archive := cfg.StorageConfig.ResponseBodies.Archive; archive.Enabled {
+		if !cfg.StorageConfig.ResponseBodies.Enabled {
+			problems = append(problems, "storage_config.response_bodies.archive.enabled requires storage_config.response_bodies.enabled")
+		}
+		if strings.TrimSpace(archive.Dir) == "" {
+			problems = append(problems, "storage_config.response_bodies.archive.enabled requires storage_config.response_bodies.archive.dir")
+		}
+	}
+
+	fixtures := cfg.CrawlerConfig.Fixtures
+	if fixtures.RecordPath != "" && fixtures.ReplayPath != "" {
+		problems = append(problems, "crawler_config.fixtures.record_path and replay_path cannot both be set")
+	}
+	if crawler := cfg.CrawlerConfig; crawler.RequestDelayMaxMs > 0 && crawler.RequestDelayMaxMs < crawler.RequestDelayMs {
+		problems = append(problems, "crawler_config.request_delay_max_ms must be 0 or at least request_delay_ms")
+	}
+	retry := cfg.CrawlerConfig.RetryConfig
+	for _, statusCode := range slices.Sorted(maps.Keys(retry.StatusBackoff)) {
+		if baseDelaySecs, maxDelaySecs := retry.BackoffFor(statusCode); baseDelaySecs > maxDelaySecs {
+			problems = append(problems, fmt.Sprintf("crawler_config.retry_config.status_backoff[%d] starts at %ds, past its max_delay_secs of %ds", statusCode, baseDelaySecs, maxDelaySecs))
+		}
+	}
+	wildcards := cfg.TargetExpansion.Wildcards
+	if wildcards.Enabled && strings.TrimSpace(wildcards.SourceFile) == "" && !wildcards.CertTransparency {
+		problems = append(problems, "target_expansion.wildcards.enabled requires source_file or cert_transparency")
+	}
+	if cfg.TargetSampling.Count > 0 && cfg.TargetSampling.Percent > 0 {
+		problems = append(problems, "target_sampling.count and target_sampling.percent cannot both be set")
+	}
+	if cfg.HARExport.Enabled && strings.TrimSpace(cfg.HARExport.OutputDir) == "" {
+		problems = append(problems, "har_export.enabled requires har_export.output_dir")
+	}
+	if cfg.SearchExport.Enabled && strings.TrimSpace(cfg.SearchExport.URL) == "" {
+		problems = append(problems, "search_export.enabled requires search_export.url")
+	}
+	if cfg.ReportBrowser.Enabled && (strings.TrimSpace(cfg.ReportBrowser.ListenAddr) == "" || strings.TrimSpace(cfg.ReportBrowser.IndexFile) == "") {
+		problems = append(problems, "report_browser.enabled requires report_browser.listen_addr and report_browser.index_file")
+	}
+	for _, column := range cfg.ReporterConfig.Columns {
+		if !slices.Contains(ReportColumns, column) {
+			problems = append(problems, fmt.Sprintf("reporter_config.columns has unknown column '%s' (expected one of %s)", column, strings.Join(ReportColumns, ", ")))
+		}
+	}
+	for i, webhook := range cfg.NotificationConfig.SeverityWebhooks {
+		if urlhandler.ValidateURLFormat(webhook.URL) != nil {
+			problems = append(problems, fmt.Sprintf("notification_config.severity_webhooks[%d].url must be a valid URL", i))
+		}
+		if !slices.Contains(NotificationSeverities, webhook.MinSeverity) {
+			problems = append(problems, fmt.Sprintf("notification_config.severity_webhooks[%d].min_severity must be one of %s", i, strings.Join(NotificationSeverities, ", ")))
+		}
+	}
+
+	return problems
+}
+
+// validateCompressionLevel checks the compression level against the configured codec.
+// A level on a codec without levels is only warned about, since it is simply ignored.
+func (cv *ConfigValidator) validateCompressionLevel(storageCfg StorageConfig) error {
+	level := storageCfg.CompressionLevel
+	if level == 0 {
+		return nil
+	}
 
-	if err := cv.validator.Struct(validationView); err != nil {
-		return cv.handleValidationError(err)
+	minLevel, maxLevel, supported := storageCfg.CompressionLevelRange()
+	if !supported {
+		cv.logger.Warn().
+			Str("compression_codec", storageCfg.CompressionCodec).
+			Int("compression_level", level).
+			Msg("Compression level is ignored for this codec")
+		return nil
 	}
 
+	if level < minLevel || level > maxLevel {
+		codec := storageCfg.CompressionCodec
+		if codec == "" {
+			codec = DefaultStorageCompressionCodec
+		}
+		return errorwrapper.NewValidationError("storage_config.compression_level", level,
+			fmt.Sprintf("%s supports levels %d-%d", codec, minLevel, maxLevel))
+	}
 	return nil
 }
 
@@ -56,6 +223,7 @@ func (cv *ConfigValidator) registerCustomValidations() {
 	cv.registerLogValidations()
 	cv.registerModeValidations()
 	cv.registerSchedulerValidations()
+	cv.registerProxyValidations()
 }
 
 // registerFileValidations registers file-related custom validations
@@ -144,6 +312,14 @@ func (cv *ConfigValidator) registerSchedulerValidations() {
 	if err != nil {
 		cv.logger.Error().Err(err).Msg("Failed to register sqlitepath validation")
 	}
+
+	err = cv.validator.RegisterValidation("cronexpr", func(fl validator.FieldLevel) bool {
+		_, err := NextCronTime(fl.Field().String(), time.Now())
+		return err == nil
+	})
+	if err != nil {
+		cv.logger.Error().Err(err).Msg("Failed to register cronexpr validation")
+	}
 }
 
 // validateFileExists checks if a file exists
@@ -156,7 +332,8 @@ func (cv *ConfigValidator) validateFileExists(filePath string) bool {
 	return fileManager.FileExists(filePath)
 }
 
-// validateDirectoryPath checks if a directory path exists
+// validateDirectoryPath checks that a path can be used as a directory. A missing directory is valid,
+// since output directories are created when first written to.
 func (cv *ConfigValidator) validateDirectoryPath(dirPath string) bool {
 	if dirPath == "" {
 		return true // Optional field
@@ -164,7 +341,7 @@ func (cv *ConfigValidator) validateDirectoryPath(dirPath string) bool {
 
 	info, err := os.Stat(dirPath)
 	if os.IsNotExist(err) {
-		return false
+		return true
 	}
 	return err == nil && info.IsDir()
 }
@@ -206,102 +383,144 @@ func (cv *ConfigValidator) validateURLSlice(field reflect.Value) bool {
 	return true
 }
 
-// validateLogLevel validates log level values
+// validateLogLevel validates log level values against LogLevels; empty uses the default
 func (cv *ConfigValidator) validateLogLevel(level string) bool {
-	normalizedLevel := strings.ToLower(level)
-	validLevels := []string{"", "debug", "info", "warn", "error", "fatal", "panic"}
-
-	for _, validLevel := range validLevels {
-		if normalizedLevel == validLevel {
-			return true
-		}
-	}
-	return false
+	return level == "" || slices.Contains(LogLevels, strings.ToLower(level))
 }
 
-// validateLogFormat validates log format values
+// validateLogFormat validates log format values against LogFormats; empty uses the default
 func (cv *ConfigValidator) validateLogFormat(format string) bool {
-	normalizedFormat := strings.ToLower(format)
-	validFormats := []string{"", "console", "text", "json"}
-
-	for _, validFormat := range validFormats {
-		if normalizedFormat == validFormat {
-			return true
-		}
-	}
-	return false
+	return format == "" || slices.Contains(LogFormats, strings.ToLower(format))
 }
 
-// validateMode validates mode values
+// validateMode validates mode values against Modes; empty is reported by the required rule
 func (cv *ConfigValidator) validateMode(mode string) bool {
-	normalizedMode := strings.ToLower(mode)
-	validModes := []string{"", "onetime", "automated"}
+	return mode == "" || slices.Contains(Modes, strings.ToLower(mode))
+}
 
-	for _, validMode := range validModes {
-		if normalizedMode == validMode {
-			return true
-		}
+// registerProxyValidations registers proxy-related custom validations
+func (cv *ConfigValidator) registerProxyValidations() {
+	err := cv.validator.RegisterValidation("proxyurl", func(fl validator.FieldLevel) bool {
+		return httpclient.ProxyConfig{URL: fl.Field().String()}.Validate() == nil
+	})
+	if err != nil {
+		cv.logger.Error().Err(err).Msg("Failed to register proxyurl validation")
 	}
-	return false
 }
 
 // createValidationView creates a validation view struct for the config
 func (cv *ConfigValidator) createValidationView(cfg *GlobalConfig) interface{} {
 	return struct {
-		CycleMinutes  int    `validate:"-"`
-		RetryAttempts int    `validate:"-"`
-		SQLiteDBPath  string `validate:"-"`
+		CycleMinutes   int      `yaml:"scheduler_config.cycle_minutes" validate:"-"`
+		RetryAttempts  int      `yaml:"scheduler_config.retry_attempts" validate:"-"`
+		SQLiteDBPath   string   `yaml:"scheduler_config.sqlite_db_path" validate:"-"`
+		CronExpression string   `yaml:"scheduler_config.cron_expression" validate:"omitempty,cronexpr"`
+		RowGroupSize   int      `yaml:"storage_config.row_group_size" validate:"omitempty,min=100"`
+		PageSize       int      `yaml:"storage_config.page_size" validate:"omitempty,min=4096"`
+		CompressCodec  string   `yaml:"storage_config.compression_codec" validate:"omitempty,oneof=zstd gzip snappy none"`
+		CompressLevel  int      `yaml:"storage_config.compression_level" validate:"omitempty,min=1,max=22"`
+		ExpandSchemes  []string `yaml:"target_expansion.schemes" validate:"omitempty,dive,oneof=http https"`
+		ExpandPorts    []int    `yaml:"target_expansion.ports" validate:"omitempty,dive,min=1,max=65535"`
+		MaxExpansion   int      `yaml:"target_expansion.max_expansion" validate:"omitempty,min=1"`
+		MaxBodySizeKB  int      `yaml:"storage_config.response_bodies.max_size_kb" validate:"omitempty,min=1"`
+		ProxyURL       string   `yaml:"proxy_config.url" validate:"omitempty,proxyurl"`
+		ProxyHTTPURL   string   `yaml:"proxy_config.http_url" validate:"omitempty,proxyurl"`
+		ProxyHTTPSURL  string   `yaml:"proxy_config.https_url" validate:"omitempty,proxyurl"`
+		ProxyPoolURLs  []string `yaml:"proxy_config.pool.urls" validate:"omitempty,dive,proxyurl"`
+		ProxyRotation  string   `yaml:"proxy_config.pool.rotation" validate:"omitempty,oneof=per_host per_request"`
+		ProxyPoolFails int      `yaml:"proxy_config.pool.max_failures" validate:"min=0"`
+		ProxyCooldown  int      `yaml:"proxy_config.pool.cooldown_secs" validate:"min=0"`
+		QuietStart     string   `yaml:"notification_config.quiet_hours.start" validate:"omitempty,datetime=15:04"`
+		QuietEnd       string   `yaml:"notification_config.quiet_hours.end" validate:"omitempty,datetime=15:04"`
+		QuietTimezone  string   `yaml:"notification_config.quiet_hours.timezone" validate:"omitempty,timezone"`
+		QuietDays      []string `yaml:"notification_config.quiet_hours.days" validate:"omitempty,dive,oneof=mon tue wed thu fri sat sun"`
+		ReportWorkers  int      `yaml:"reporter_config.report_workers" validate:"min=0"`
+		AdaptiveMin    int      `yaml:"crawler_config.adaptive_concurrency.min_concurrency" validate:"omitempty,min=1"`
+		AdaptiveFactor float64  `yaml:"crawler_config.adaptive_concurrency.decrease_factor" validate:"omitempty,gt=0,lt=1"`
+		TLSExpiryDays  int      `yaml:"scheduler_config.tls_expiry_warning_days" validate:"min=0"`
+		StorageBackend string   `yaml:"storage_config.backend" validate:"omitempty,oneof=local s3"`
+		MaxTotalURLs   int      `yaml:"crawler_config.max_total_urls" validate:"min=0"`
+		MaxCrawlSecs   int      `yaml:"crawler_config.max_crawl_duration_secs" validate:"min=0"`
+		RequestDelay   int      `yaml:"crawler_config.request_delay_ms" validate:"min=0"`
+		RequestDelayMx int      `yaml:"crawler_config.request_delay_max_ms" validate:"min=0"`
+		S3Endpoint     string   `yaml:"storage_config.s3.endpoint" validate:"omitempty,url"`
+		DigestCron     string   `yaml:"scheduler_config.digest.cron_expression" validate:"omitempty,cronexpr"`
+		DigestWindow   int      `yaml:"scheduler_config.digest.window_days" validate:"min=0"`
+		WildcardCTURL  string   `yaml:"target_expansion.wildcards.ct_endpoint" validate:"omitempty,url"`
+		WildcardCTSecs int      `yaml:"target_expansion.wildcards.ct_timeout_secs" validate:"min=0"`
+		WildcardMax    int      `yaml:"target_expansion.wildcards.max_subdomains" validate:"min=0"`
+		SearchURL      string   `yaml:"search_export.url" validate:"omitempty,url"`
+		SearchBatch    int      `yaml:"search_export.batch_size" validate:"min=0"`
+		RetentionAge   int      `yaml:"storage_config.retention.max_age_days" validate:"min=0"`
+		RetentionSize  int      `yaml:"storage_config.retention.max_total_size_mb" validate:"min=0"`
+		RetentionEvery int      `yaml:"storage_config.retention.cleanup_interval_hours" validate:"min=0"`
+		MaxInFlight    int      `yaml:"max_in_flight_requests" validate:"min=0"`
+		NotifySeverity string   `yaml:"notification_config.min_severity" validate:"omitempty,oneof=info low medium high critical"`
+		ArchiveFormat  string   `yaml:"storage_config.response_bodies.archive.format" validate:"omitempty,oneof=raw gzip"`
+		SampleCount    int      `yaml:"target_sampling.count" validate:"min=0"`
+		SamplePercent  float64  `yaml:"target_sampling.percent" validate:"gte=0,lte=100"`
+		ProgressSecs   int      `yaml:"progress.interval_secs" validate:"min=0"`
+		ProgressStep   int      `yaml:"progress.percent_step" validate:"min=0,max=100"`
 	}{
-		CycleMinutes:  cfg.SchedulerConfig.CycleMinutes,
-		RetryAttempts: cfg.SchedulerConfig.RetryAttempts,
-		SQLiteDBPath:  cfg.SchedulerConfig.SQLiteDBPath,
+		CycleMinutes:   cfg.SchedulerConfig.CycleMinutes,
+		RetryAttempts:  cfg.SchedulerConfig.RetryAttempts,
+		SQLiteDBPath:   cfg.SchedulerConfig.SQLiteDBPath,
+		CronExpression: cfg.SchedulerConfig.CronExpression,
+		RowGroupSize:   cfg.StorageConfig.RowGroupSize,
+		PageSize:       cfg.StorageConfig.PageSize,
+		CompressCodec:  cfg.StorageConfig.CompressionCodec,
+		CompressLevel:  cfg.StorageConfig.CompressionLevel,
+		ExpandSchemes:  cfg.TargetExpansion.Schemes,
+		ExpandPorts:    cfg.TargetExpansion.Ports,
+		MaxExpansion:   cfg.TargetExpansion.MaxExpansion,
+		MaxBodySizeKB:  cfg.StorageConfig.ResponseBodies.MaxSizeKB,
+		ProxyURL:       cfg.ProxyConfig.URL,
+		ProxyHTTPURL:   cfg.ProxyConfig.HTTPURL,
+		ProxyHTTPSURL:  cfg.ProxyConfig.HTTPSURL,
+		ProxyPoolURLs:  cfg.ProxyConfig.Pool.URLs,
+		ProxyRotation:  cfg.ProxyConfig.Pool.Rotation,
+		ProxyPoolFails: cfg.ProxyConfig.Pool.MaxFailures,
+		ProxyCooldown:  cfg.ProxyConfig.Pool.CooldownSecs,
+		QuietStart:     cfg.NotificationConfig.QuietHours.Start,
+		QuietEnd:       cfg.NotificationConfig.QuietHours.End,
+		QuietTimezone:  cfg.NotificationConfig.QuietHours.Timezone,
+		QuietDays:      cfg.NotificationConfig.QuietHours.Days,
+		ReportWorkers:  cfg.ReporterConfig.ReportWorkers,
+		AdaptiveMin:    cfg.CrawlerConfig.AdaptiveConcurrency.MinConcurrency,
+		AdaptiveFactor: cfg.CrawlerConfig.AdaptiveConcurrency.DecreaseFactor,
+		TLSExpiryDays:  cfg.SchedulerConfig.TLSExpiryWarningDays,
+		StorageBackend: cfg.StorageConfig.Backend,
+		MaxTotalURLs:   cfg.CrawlerConfig.MaxTotalURLs,
+		MaxCrawlSecs:   cfg.CrawlerConfig.MaxCrawlDurationSecs,
+		RequestDelay:   cfg.CrawlerConfig.RequestDelayMs,
+		RequestDelayMx: cfg.CrawlerConfig.RequestDelayMaxMs,
+		S3Endpoint:     cfg.StorageConfig.S3.Endpoint,
+		DigestCron:     cfg.SchedulerConfig.Digest.CronExpression,
+		DigestWindow:   cfg.SchedulerConfig.Digest.WindowDays,
+		WildcardCTURL:  cfg.TargetExpansion.Wildcards.CTEndpoint,
+		WildcardCTSecs: cfg.TargetExpansion.Wildcards.CTTimeoutSecs,
+		WildcardMax:    cfg.TargetExpansion.Wildcards.MaxSubdomains,
+		SearchURL:      cfg.SearchExport.URL,
+		SearchBatch:    cfg.SearchExport.BatchSize,
+		RetentionAge:   cfg.StorageConfig.Retention.MaxAgeDays,
+		RetentionSize:  cfg.StorageConfig.Retention.MaxTotalSizeMB,
+		RetentionEvery: cfg.StorageConfig.Retention.CleanupIntervalHours,
+		MaxInFlight:    cfg.MaxInFlight,
+		NotifySeverity: cfg.NotificationConfig.MinSeverity,
+		ArchiveFormat:  cfg.StorageConfig.ResponseBodies.Archive.Format,
+		SampleCount:    cfg.TargetSampling.Count,
+		SamplePercent:  cfg.TargetSampling.Percent,
+		ProgressSecs:   cfg.Progress.IntervalSecs,
+		ProgressStep:   cfg.Progress.PercentStep,
 	}
 }
 
-// handleValidationError processes validation errors and returns a meaningful error
-func (cv *ConfigValidator) handleValidationError(err error) error {
-	var validationErrors validator.ValidationErrors
-	if !errors.As(err, &validationErrors) {
-		return errorwrapper.WrapError(err, "configuration validation error")
+// getFieldName returns the config key of a failed field, without the name of its root struct
+func (cv *ConfigValidator) getFieldName(err validator.FieldError, root string) string {
+	if root == "" {
+		return err.Namespace()
 	}
-
-	errorMessages := cv.formatValidationErrors(validationErrors)
-	return errorwrapper.NewError("configuration validation failed:\n  %s", strings.Join(errorMessages, "\n  "))
-}
-
-// formatValidationErrors formats validation errors into readable messages
-func (cv *ConfigValidator) formatValidationErrors(errors validator.ValidationErrors) []string {
-	var messages []string
-
-	for _, err := range errors {
-		fieldName := cv.getFieldName(err)
-		message := cv.formatSingleValidationError(err, fieldName)
-		messages = append(messages, message)
-	}
-
-	return messages
-}
-
-// getFieldName extracts a readable field name from validation error
-func (cv *ConfigValidator) getFieldName(err validator.FieldError) string {
-	fieldName := err.StructNamespace()
-
-	if strings.Contains(fieldName, ".") {
-		parts := strings.Split(fieldName, ".")
-		// Find the field name that matches the error field
-		for i := len(parts) - 1; i >= 0; i-- {
-			if strings.EqualFold(parts[i], err.Field()) {
-				fieldName = strings.Join(parts[i:], ".")
-				break
-			}
-		}
-
-		if !strings.HasPrefix(fieldName, err.Field()) {
-			fieldName = err.Field()
-		}
-	}
-
-	return fieldName
+	return strings.TrimPrefix(err.Namespace(), root+".")
 }
 
 // formatSingleValidationError formats a single validation error
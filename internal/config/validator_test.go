@@ -0,0 +1,140 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidator_Problems(t *testing.T) {
+	cv := NewConfigValidator(zerolog.Nop())
+
+	cfg := NewDefaultGlobalConfig()
+	assert.Empty(t, cv.Problems(cfg))
+	assert.NoError(t, cv.Validate(cfg))
+
+	cfg.Mode = "automated"
+	cfg.SchedulerConfig.SQLiteDBPath = ""
+	cfg.SchedulerConfig.CycleMinutes = 0
+	cfg.CrawlerConfig.Fixtures = FixtureConfig{RecordPath: "a.jsonl", ReplayPath: "b.jsonl"}
+
+	problems := cv.Problems(cfg)
+	assert.Contains(t, problems, "automated mode requires scheduler_config.sqlite_db_path")
+	assert.Contains(t, problems, "automated mode requires scheduler_config.cycle_minutes >= 1 or a cron_expression")
+	assert.Contains(t, problems, "crawler_config.fixtures.record_path and replay_path cannot both be set")
+	assert.Error(t, cv.Validate(cfg))
+
+	// A cron schedule replaces the cycle interval
+	cfg.SchedulerConfig.CronExpression = "0 2 * * *"
+	assert.NotContains(t, cv.Problems(cfg), "automated mode requires scheduler_config.cycle_minutes >= 1 or a cron_expression")
+
+	cfg = NewDefaultGlobalConfig()
+	cfg.CrawlerConfig.RequestDelayMs = 500
+	cfg.CrawlerConfig.RequestDelayMaxMs = 200
+	assert.Equal(t, []string{"crawler_config.request_delay_max_ms must be 0 or at least request_delay_ms"}, cv.Problems(cfg))
+	cfg.CrawlerConfig.RequestDelayMaxMs = 1500
+	assert.Empty(t, cv.Problems(cfg))
+
+	cfg.SearchExport.Enabled = true
+	assert.Equal(t, []string{"search_export.enabled requires search_export.url"}, cv.Problems(cfg))
+	cfg.SearchExport.URL = "https://search.example.com:9200"
+	assert.Empty(t, cv.Problems(cfg))
+
+	cfg.ReporterConfig.Columns = []string{"status", "length", "cookies"}
+	assert.Equal(t, []string{"reporter_config.columns has unknown column 'cookies' (expected one of diff, status, content_type, type, title, tech, tags, length, ip, asn, server)"}, cv.Problems(cfg))
+	cfg.ReporterConfig.Columns = []string{"status", "length"}
+	assert.Empty(t, cv.Problems(cfg))
+
+	cfg.NotificationConfig.SeverityWebhooks = SeverityWebhooks{{URL: "", MinSeverity: "urgent"}}
+	assert.Equal(t, []string{
+		"notification_config.severity_webhooks[0].url must be a valid URL",
+		"notification_config.severity_webhooks[0].min_severity must be one of info, low, medium, high, critical",
+	}, cv.Problems(cfg))
+	cfg.NotificationConfig.SeverityWebhooks = SeverityWebhooks{{URL: "https://discord.com/api/webhooks/oncall", MinSeverity: "high"}}
+	assert.Empty(t, cv.Problems(cfg))
+
+	cfg.StorageConfig.ResponseBodies.Archive.Enabled = true
+	assert.Equal(t, []string{"storage_config.response_bodies.archive.enabled requires storage_config.response_bodies.enabled"}, cv.Problems(cfg))
+	cfg.StorageConfig.ResponseBodies.Enabled = true
+	assert.Empty(t, cv.Problems(cfg))
+
+	cfg.TargetSampling = TargetSamplingConfig{Count: 500, Percent: 5}
+	assert.Equal(t, []string{"target_sampling.count and target_sampling.percent cannot both be set"}, cv.Problems(cfg))
+	cfg.TargetSampling.Count = 0
+	assert.Empty(t, cv.Problems(cfg))
+
+	cfg = NewDefaultGlobalConfig()
+	cfg.Mode = "daily"
+	assert.Equal(t, []string{"mode must be 'onetime' or 'automated', got 'daily'"}, cv.Problems(cfg))
+}
+
+func TestConfigValidator_FieldRules(t *testing.T) {
+	cv := NewConfigValidator(zerolog.Nop())
+
+	cfg := NewDefaultGlobalConfig()
+	cfg.NotificationConfig.ScanServiceDiscordWebhookURLs = WebhookURLs{"not a url"}
+	cfg.NotificationConfig.MaxEmbedFields = 500
+	cfg.CrawlerConfig.RetryConfig.MaxJitterSecs = 99999
+	cfg.CrawlerConfig.Redirects.MaxPerChain = -5
+	cfg.ProxyConfig.Pool.Rotation = "random"
+
+	assert.ElementsMatch(t, []string{
+		"Validation failed for 'notification_config.scan_service_discord_webhook_url[0]': rule 'url', actual: 'not a url'",
+		"Validation failed for 'notification_config.max_embed_fields': rule 'max' (expected: 25), actual: '500'",
+		"Validation failed for 'crawler_config.retry_config.max_jitter_secs': rule 'max' (expected: 300), actual: '99999'",
+		"Validation failed for 'crawler_config.redirects.max_per_chain': rule 'min' (expected: 1), actual: '-5'",
+		"Validation failed for 'proxy_config.pool.rotation': rule 'oneof' (expected: per_host per_request), actual: 'random'",
+	}, cv.Problems(cfg))
+
+	// Status backoff overrides are checked per status code, and against the delays they fall back to
+	cfg = NewDefaultGlobalConfig()
+	cfg.CrawlerConfig.RetryConfig.StatusBackoff = map[int]StatusBackoffConfig{
+		42:  {BaseDelaySecs: 5},
+		429: {},
+		502: {BaseDelaySecs: 120},
+		503: {BaseDelaySecs: 30, MaxDelaySecs: 99999},
+	}
+	assert.ElementsMatch(t, []string{
+		"Validation failed for 'crawler_config.retry_config.status_backoff[42]': rule 'min' (expected: 100), actual: '42'",
+		"Validation failed for 'crawler_config.retry_config.status_backoff[503].max_delay_secs': rule 'max' (expected: 3600), actual: '99999'",
+		"crawler_config.retry_config.status_backoff[502] starts at 120s, past its max_delay_secs of 60s",
+	}, cv.Problems(cfg))
+
+	// A rule declared on both the config struct and the validation view is reported once
+	cfg = NewDefaultGlobalConfig()
+	cfg.Progress.PercentStep = 200
+	assert.Equal(t, []string{"Validation failed for 'progress.percent_step': rule 'max' (expected: 100), actual: '200'"}, cv.Problems(cfg))
+
+	// Output directories are created on first write
+	cfg = NewDefaultGlobalConfig()
+	cfg.ReporterConfig.OutputDir = filepath.Join(t.TempDir(), "not-yet-created")
+	assert.Empty(t, cv.Problems(cfg))
+}
+
+func TestConfigValidator_StorageBackend(t *testing.T) {
+	cv := NewConfigValidator(zerolog.Nop())
+
+	cfg := NewDefaultGlobalConfig()
+	cfg.StorageConfig.Backend = StorageBackendS3
+	assert.Contains(t, cv.Problems(cfg), "storage_config.backend s3 requires storage_config.s3.bucket")
+
+	cfg.StorageConfig.S3.Bucket = "scan-history"
+	cfg.StorageConfig.S3.Endpoint = "http://minio:9000"
+	assert.Empty(t, cv.Problems(cfg))
+
+	cfg.StorageConfig.Backend = "gcs"
+	assert.NotEmpty(t, cv.Problems(cfg))
+}
+
+func TestConfigValidator_StoragePathTokens(t *testing.T) {
+	cv := NewConfigValidator(zerolog.Nop())
+
+	cfg := NewDefaultGlobalConfig()
+	cfg.StorageConfig.ParquetBasePath = "database/{date}"
+	assert.Empty(t, cv.Problems(cfg))
+
+	cfg.StorageConfig.ParquetBasePath = "database/{session}"
+	assert.Contains(t, cv.Problems(cfg), "storage_config.parquet_base_path cannot use {session}; scan history must be shared across scans")
+}
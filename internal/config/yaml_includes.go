@@ -0,0 +1,199 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/filemanager"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// YAMLIncludeTag replaces the tagged value with the content of another YAML file:
+	// notification_config: !include shared/notification.yaml
+	YAMLIncludeTag = "!include"
+	// YAMLIncludesKey lists files at the top level of a YAML config that the rest of the file is laid over,
+	// in order, so later files and the including file override earlier ones
+	YAMLIncludesKey = "includes"
+)
+
+// yamlIncludeResolver expands the includes of a YAML config file. Include paths are relative to the file that
+// names them, and a file that includes itself, directly or through others, is rejected.
+type yamlIncludeResolver struct {
+	fileManager *filemanager.FileManager
+	stack       []string // Absolute paths of the files being resolved, outermost first
+}
+
+// resolveYAMLIncludes parses data, read from filePath, and returns its document with every include expanded
+func resolveYAMLIncludes(data []byte, filePath string) (*yaml.Node, error) {
+	resolver := &yamlIncludeResolver{fileManager: filemanager.NewFileManager(zerolog.Nop())}
+	return resolver.resolveDocument(data, filePath)
+}
+
+// usesYAMLIncludes reports whether a YAML config names other files, with the include tag or the includes key
+func usesYAMLIncludes(doc *yaml.Node) bool {
+	if root := documentRoot(doc); root != nil && root.Kind == yaml.MappingNode && findYAMLMappingValue(root, YAMLIncludesKey) != nil {
+		return true
+	}
+	var tagged func(node *yaml.Node) bool
+	tagged = func(node *yaml.Node) bool {
+		if node.Tag == YAMLIncludeTag {
+			return true
+		}
+		for _, child := range node.Content {
+			if tagged(child) {
+				return true
+			}
+		}
+		return false
+	}
+	return tagged(doc)
+}
+
+// resolveDocument parses one file and expands its includes
+func (r *yamlIncludeResolver) resolveDocument(data []byte, filePath string) (*yaml.Node, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to resolve config path '"+filePath+"'")
+	}
+	for _, open := range r.stack {
+		if open == absPath {
+			return nil, errorwrapper.NewError("config include cycle: %s", strings.Join(append(r.stack, absPath), " -> "))
+		}
+	}
+	r.stack = append(r.stack, absPath)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errorwrapper.NewError("failed to unmarshal YAML from '%s': %w", filePath, err)
+	}
+	root := documentRoot(&doc)
+	if root == nil {
+		return &doc, nil
+	}
+
+	if err := r.expandTags(root, filepath.Dir(absPath)); err != nil {
+		return nil, err
+	}
+	if err := r.applyIncludesList(root, filepath.Dir(absPath)); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// expandTags replaces every node tagged with the include tag by the root of the named file
+func (r *yamlIncludeResolver) expandTags(node *yaml.Node, baseDir string) error {
+	if node.Tag == YAMLIncludeTag {
+		if node.Kind != yaml.ScalarNode || strings.TrimSpace(node.Value) == "" {
+			return errorwrapper.NewError("%s at line %d must name a file", YAMLIncludeTag, node.Line)
+		}
+		included, err := r.includeFile(node.Value, baseDir)
+		if err != nil {
+			return err
+		}
+		*node = *included
+		return nil
+	}
+
+	for _, child := range node.Content {
+		if err := r.expandTags(child, baseDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyIncludesList removes the top-level includes key and lays the rest of the mapping over the named files
+func (r *yamlIncludeResolver) applyIncludesList(root *yaml.Node, baseDir string) error {
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+	includes := findYAMLMappingValue(root, YAMLIncludesKey)
+	if includes == nil {
+		return nil
+	}
+	if includes.Kind != yaml.SequenceNode {
+		return errorwrapper.NewError("%s at line %d must be a list of files", YAMLIncludesKey, includes.Line)
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, entry := range includes.Content {
+		if entry.Kind != yaml.ScalarNode || strings.TrimSpace(entry.Value) == "" {
+			return errorwrapper.NewError("%s entry at line %d must name a file", YAMLIncludesKey, entry.Line)
+		}
+		included, err := r.includeFile(entry.Value, baseDir)
+		if err != nil {
+			return err
+		}
+		if included.Kind != yaml.MappingNode {
+			return errorwrapper.NewError("included config '%s' must be a mapping", entry.Value)
+		}
+		mergeYAMLMappings(merged, included)
+	}
+
+	removeYAMLMappingKey(root, YAMLIncludesKey)
+	mergeYAMLMappings(merged, root)
+	*root = *merged
+	return nil
+}
+
+// includeFile reads and resolves an included file, returning the root node of its document
+func (r *yamlIncludeResolver) includeFile(path, baseDir string) (*yaml.Node, error) {
+	path = strings.TrimSpace(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	if !r.fileManager.FileExists(path) {
+		return nil, errorwrapper.NewValidationError("config_include", path, "included config file does not exist")
+	}
+
+	data, err := loadConfigFileContent(r.fileManager, path)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to load included config '"+path+"'")
+	}
+	doc, err := r.resolveDocument(data, path)
+	if err != nil {
+		return nil, err
+	}
+	if root := documentRoot(doc); root != nil {
+		return root, nil
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil // An empty file includes nothing
+}
+
+// mergeYAMLMappings lays src over dst: nested mappings are merged key by key, any other value replaces dst's
+func mergeYAMLMappings(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := findYAMLMappingValue(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeYAMLMappings(existing, value)
+		default:
+			*existing = *value
+		}
+	}
+}
+
+// removeYAMLMappingKey deletes key and its value from a mapping node
+func removeYAMLMappingKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// documentRoot returns the top-level node of a parsed document, or nil for an empty document
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	return doc.Content[0]
+}
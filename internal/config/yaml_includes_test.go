@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestLoadGlobalConfig_YAMLIncludes(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"shared/base.yaml": "mode: automated\nscheduler_config:\n  cycle_minutes: 30\n  retry_attempts: 5\n",
+		"shared/notification.yaml": "scan_service_discord_webhook_url: https://discord.com/api/webhooks/scan\n" +
+			"mention_role_ids: [\"123\"]\n",
+		"prod.yaml": "includes: [shared/base.yaml]\n" +
+			"scheduler_config:\n  cycle_minutes: 60\n" +
+			"notification_config: !include shared/notification.yaml\n",
+	})
+
+	cfg, err := LoadGlobalConfig(filepath.Join(dir, "prod.yaml"), zerolog.Nop())
+	require.NoError(t, err)
+	assert.Equal(t, "automated", cfg.Mode)
+	assert.Equal(t, 60, cfg.SchedulerConfig.CycleMinutes, "the including file overrides included values")
+	assert.Equal(t, 5, cfg.SchedulerConfig.RetryAttempts, "included values the file does not set are kept")
+	assert.Equal(t, WebhookURLs{"https://discord.com/api/webhooks/scan"}, cfg.NotificationConfig.ScanServiceDiscordWebhookURLs)
+	assert.Equal(t, []string{"123"}, cfg.NotificationConfig.MentionRoleIDs)
+}
+
+func TestLoadGlobalConfig_YAMLIncludeCycle(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"a.yaml": "includes: [b.yaml]\nmode: onetime\n",
+		"b.yaml": "scheduler_config: !include c.yaml\n",
+		"c.yaml": "includes: [a.yaml]\n",
+	})
+
+	_, err := LoadGlobalConfig(filepath.Join(dir, "a.yaml"), zerolog.Nop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config include cycle")
+
+	dir = writeConfigFiles(t, map[string]string{"self.yaml": "log_config: !include self.yaml\n"})
+	_, err = LoadGlobalConfig(filepath.Join(dir, "self.yaml"), zerolog.Nop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config include cycle")
+}
+
+func TestLoadGlobalConfig_YAMLIncludeMissingFile(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{"config.yaml": "includes: [missing.yaml]\n"})
+
+	_, err := LoadGlobalConfig(filepath.Join(dir, "config.yaml"), zerolog.Nop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "included config file does not exist")
+}
+
+func TestConfigUpgrader_RejectsIncludes(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"shared.yaml": "mode: automated\n",
+		"config.yaml": "includes: [shared.yaml]\n",
+	})
+
+	_, err := NewConfigUpgrader(zerolog.Nop()).UpgradeFile(filepath.Join(dir, "config.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be upgraded in place")
+}
@@ -0,0 +1,181 @@
+package crawler
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// AdaptiveConcurrencyTransport limits in-flight requests per host and adapts the limit AIMD-style:
+// a rate-limit response multiplies the host's limit by the decrease factor, at most once per cooldown
+// window so a burst of responses to requests already in flight counts as one signal, and every IncreaseAfter
+// consecutive successes raise it by one, up to maxConcurrency. Rate-limited hosts keep being crawled, only slower.
+type AdaptiveConcurrencyTransport struct {
+	base             http.RoundTripper
+	config           config.AdaptiveConcurrencyConfig
+	maxConcurrency   int
+	cooldown         time.Duration
+	rateLimitedCodes map[int]bool
+	hosts            map[string]*hostLimiter
+	mutex            sync.Mutex
+	logger           zerolog.Logger
+	now              func() time.Time
+}
+
+// hostLimiter is the concurrency state of one host
+type hostLimiter struct {
+	limit     float64
+	inFlight  int
+	successes int
+	cutAt     time.Time     // When the limit was last cut
+	changed   chan struct{} // Closed and replaced whenever a slot frees up or the limit changes
+}
+
+// NewAdaptiveConcurrencyTransport creates a transport that starts every host at maxConcurrency
+func NewAdaptiveConcurrencyTransport(base http.RoundTripper, cfg config.AdaptiveConcurrencyConfig, maxConcurrency int, logger zerolog.Logger) *AdaptiveConcurrencyTransport {
+	cfg.MinConcurrency = getIntValueOrDefault(cfg.MinConcurrency, config.DefaultAdaptiveMinConcurrency)
+	cfg.IncreaseAfter = getIntValueOrDefault(cfg.IncreaseAfter, config.DefaultAdaptiveIncreaseAfter)
+	cfg.DecreaseCooldownSecs = getIntValueOrDefault(cfg.DecreaseCooldownSecs, config.DefaultAdaptiveDecreaseCooldownSecs)
+	if cfg.DecreaseFactor <= 0 || cfg.DecreaseFactor >= 1 {
+		cfg.DecreaseFactor = config.DefaultAdaptiveDecreaseFactor
+	}
+	maxConcurrency = max(maxConcurrency, cfg.MinConcurrency)
+
+	codes := make(map[int]bool, len(cfg.StatusCodes))
+	for _, code := range cfg.StatusCodes {
+		codes[code] = true
+	}
+
+	return &AdaptiveConcurrencyTransport{
+		base:             base,
+		config:           cfg,
+		maxConcurrency:   maxConcurrency,
+		cooldown:         time.Duration(cfg.DecreaseCooldownSecs) * time.Second,
+		rateLimitedCodes: codes,
+		hosts:            make(map[string]*hostLimiter),
+		logger:           logger.With().Str("component", "AdaptiveConcurrency").Logger(),
+		now:              time.Now,
+	}
+}
+
+// RoundTrip waits for a free slot on the request's host, sends the request and adjusts the host's limit
+func (at *AdaptiveConcurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if err := at.acquire(req, host); err != nil {
+		return nil, err
+	}
+
+	resp, err := at.base.RoundTrip(req)
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	at.release(host, statusCode, err)
+
+	return resp, err
+}
+
+// acquire blocks until the host has fewer requests in flight than its current limit
+func (at *AdaptiveConcurrencyTransport) acquire(req *http.Request, host string) error {
+	for {
+		at.mutex.Lock()
+		hl := at.hostLimiter(host)
+		if hl.inFlight < int(hl.limit) {
+			hl.inFlight++
+			at.mutex.Unlock()
+			return nil
+		}
+		changed := hl.changed
+		at.mutex.Unlock()
+
+		select {
+		case <-req.Context().Done():
+			return req.Context().Err()
+		case <-changed:
+		}
+	}
+}
+
+// release frees the request's slot and applies the AIMD update for its outcome.
+// Network errors leave the limit unchanged; they are not a rate-limit signal.
+func (at *AdaptiveConcurrencyTransport) release(host string, statusCode int, err error) {
+	at.mutex.Lock()
+	defer at.mutex.Unlock()
+
+	hl := at.hostLimiter(host)
+	hl.inFlight--
+
+	switch {
+	case err != nil:
+	case at.rateLimitedCodes[statusCode]:
+		at.decrease(host, hl, statusCode)
+	default:
+		at.increase(host, hl)
+	}
+
+	close(hl.changed)
+	hl.changed = make(chan struct{})
+}
+
+// decrease cuts the host's limit multiplicatively, never below MinConcurrency.
+// Rate-limit responses within the cooldown of the last cut only reset the success streak.
+func (at *AdaptiveConcurrencyTransport) decrease(host string, hl *hostLimiter, statusCode int) {
+	hl.successes = 0
+	now := at.now()
+	if !hl.cutAt.IsZero() && now.Sub(hl.cutAt) < at.cooldown {
+		return
+	}
+	hl.cutAt = now
+
+	previous := int(hl.limit)
+	hl.limit = max(hl.limit*at.config.DecreaseFactor, float64(at.config.MinConcurrency))
+
+	if current := int(hl.limit); current < previous {
+		at.logger.Warn().
+			Str("host", host).
+			Int("status_code", statusCode).
+			Int("previous_concurrency", previous).
+			Int("concurrency", current).
+			Msg("Host is rate limiting, reducing concurrency")
+	}
+}
+
+// increase raises the host's limit by one after IncreaseAfter consecutive successes
+func (at *AdaptiveConcurrencyTransport) increase(host string, hl *hostLimiter) {
+	if int(hl.limit) >= at.maxConcurrency {
+		return
+	}
+
+	hl.successes++
+	if hl.successes < at.config.IncreaseAfter {
+		return
+	}
+
+	hl.successes = 0
+	hl.limit = min(float64(int(hl.limit)+1), float64(at.maxConcurrency))
+	at.logger.Debug().
+		Str("host", host).
+		Int("concurrency", int(hl.limit)).
+		Msg("Host recovered, raising concurrency")
+}
+
+// hostLimiter returns the limiter for host, creating it at full concurrency; callers hold the mutex
+func (at *AdaptiveConcurrencyTransport) hostLimiter(host string) *hostLimiter {
+	hl, ok := at.hosts[host]
+	if !ok {
+		hl = &hostLimiter{limit: float64(at.maxConcurrency), changed: make(chan struct{})}
+		at.hosts[host] = hl
+	}
+	return hl
+}
+
+// Concurrency returns the current concurrency limit for host
+func (at *AdaptiveConcurrencyTransport) Concurrency(host string) int {
+	at.mutex.Lock()
+	defer at.mutex.Unlock()
+	return int(at.hostLimiter(host).limit)
+}
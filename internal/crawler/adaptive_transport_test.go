@@ -0,0 +1,106 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statusRoundTripper struct {
+	status int
+}
+
+func (s *statusRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: s.status, Body: http.NoBody, Request: req}, nil
+}
+
+func TestAdaptiveConcurrencyTransport_AIMD(t *testing.T) {
+	base := &statusRoundTripper{status: http.StatusTooManyRequests}
+	cfg := config.AdaptiveConcurrencyConfig{Enabled: true, MinConcurrency: 1, DecreaseFactor: 0.5, IncreaseAfter: 2, StatusCodes: []int{429}}
+	at := NewAdaptiveConcurrencyTransport(base, cfg, 8, zerolog.Nop())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at.now = func() time.Time { return now }
+
+	send := func(url string) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		_, err = at.RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	send("https://slow.example.com/a")
+	assert.Equal(t, 4, at.Concurrency("slow.example.com"))
+	for _, path := range []string{"b", "c", "d"} {
+		now = now.Add(time.Minute)
+		send("https://slow.example.com/" + path)
+	}
+	assert.Equal(t, 1, at.Concurrency("slow.example.com"), "never below the minimum")
+	assert.Equal(t, 8, at.Concurrency("other.example.com"), "limits are per host")
+
+	base.status = http.StatusOK
+	send("https://slow.example.com/e")
+	assert.Equal(t, 1, at.Concurrency("slow.example.com"))
+	send("https://slow.example.com/f")
+	assert.Equal(t, 2, at.Concurrency("slow.example.com"), "additive increase after sustained success")
+}
+
+func TestAdaptiveConcurrencyTransport_DecreaseCooldown(t *testing.T) {
+	base := &statusRoundTripper{status: http.StatusTooManyRequests}
+	cfg := config.AdaptiveConcurrencyConfig{Enabled: true, MinConcurrency: 1, DecreaseFactor: 0.5, DecreaseCooldownSecs: 5, IncreaseAfter: 2, StatusCodes: []int{429}}
+	at := NewAdaptiveConcurrencyTransport(base, cfg, 16, zerolog.Nop())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at.now = func() time.Time { return now }
+
+	send := func() {
+		req, err := http.NewRequest(http.MethodGet, "https://slow.example.com/", nil)
+		require.NoError(t, err)
+		_, err = at.RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	// A burst of 429s answering requests sent before the first cut counts once
+	for i := 0; i < 6; i++ {
+		send()
+		now = now.Add(500 * time.Millisecond)
+	}
+	assert.Equal(t, 8, at.Concurrency("slow.example.com"))
+
+	// Rate limiting inside the window still resets the success streak
+	base.status = http.StatusOK
+	send()
+	base.status = http.StatusTooManyRequests
+	send()
+	base.status = http.StatusOK
+	send()
+	assert.Equal(t, 8, at.Concurrency("slow.example.com"))
+
+	// Once the window has passed the next rate-limit response cuts again
+	now = now.Add(5 * time.Second)
+	base.status = http.StatusTooManyRequests
+	send()
+	assert.Equal(t, 4, at.Concurrency("slow.example.com"))
+}
+
+func TestAdaptiveConcurrencyTransport_WaitsForSlot(t *testing.T) {
+	cfg := config.AdaptiveConcurrencyConfig{Enabled: true, StatusCodes: []int{429}}
+	at := NewAdaptiveConcurrencyTransport(&statusRoundTripper{status: http.StatusOK}, cfg, 1, zerolog.Nop())
+
+	first, err := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	require.NoError(t, err)
+	require.NoError(t, at.acquire(first, "example.com"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	second, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/", nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, at.acquire(second, "example.com"), context.DeadlineExceeded)
+
+	at.release("example.com", http.StatusOK, nil)
+	assert.NoError(t, at.acquire(second.WithContext(context.Background()), "example.com"))
+}
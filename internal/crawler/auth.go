@@ -0,0 +1,202 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/gocolly/colly/v2"
+)
+
+// authSession tracks the login state shared by all crawl workers
+type authSession struct {
+	mutex         sync.Mutex
+	authenticated bool
+	expiryWarned  bool
+	loginPagePath string
+}
+
+// initializeAuth prepares session tracking for authenticated crawling
+func (cr *Crawler) initializeAuth() {
+	auth := cr.config.Auth
+	cr.auth = &authSession{loginPagePath: auth.ResolvedLoginPagePath()}
+
+	if auth.Cookie != "" || len(auth.Headers) > 0 || auth.HasLogin() {
+		cr.logger.Info().
+			Bool("static_cookie", auth.Cookie != "").
+			Int("auth_headers", len(auth.Headers)).
+			Bool("login_step", auth.HasLogin()).
+			Str("login_page_path", cr.auth.loginPagePath).
+			Msg("Authenticated crawling enabled")
+	}
+
+	if credentials := cr.config.TargetCredentials.Len(); credentials > 0 {
+		cr.logger.Info().Int("target_credentials", credentials).Msg("Per-target credentials enabled")
+	}
+}
+
+// applyAuthHeaders sets the configured static Cookie and auth headers on a crawl request.
+// Cookies captured by the login step live in the collector's cookie jar and are added on top.
+func (cr *Crawler) applyAuthHeaders(headers *http.Header) {
+	auth := cr.config.Auth
+	for key, value := range auth.Headers {
+		headers.Set(key, value)
+	}
+	if auth.Cookie != "" {
+		headers.Set("Cookie", auth.Cookie)
+	}
+}
+
+// applyTargetCredentials sets the Authorization header from the credentials of the target u belongs to.
+// It runs after the global auth headers, so per-target credentials win.
+func (cr *Crawler) applyTargetCredentials(u *url.URL, headers *http.Header) {
+	if auth, ok := cr.config.TargetCredentials.ForURL(u); ok {
+		headers.Set("Authorization", auth.HeaderValue())
+	}
+}
+
+// ensureAuthenticated runs the login step if one is configured and no session is active
+func (cr *Crawler) ensureAuthenticated(ctx context.Context) {
+	if !cr.config.Auth.HasLogin() {
+		return
+	}
+
+	cr.auth.mutex.Lock()
+	defer cr.auth.mutex.Unlock()
+
+	if cr.auth.authenticated {
+		return
+	}
+
+	cookieCount, err := cr.performLogin(ctx)
+	if err != nil {
+		cr.logger.Error().Err(err).Str("login_url", cr.config.Auth.Login.URL).Msg("Crawler login failed, crawling without a session")
+		return
+	}
+
+	cr.auth.authenticated = true
+	cr.auth.expiryWarned = false
+	cr.logger.Info().
+		Str("login_url", cr.config.Auth.Login.URL).
+		Int("cookies", cookieCount).
+		Msg("Crawler login succeeded, session shared with all crawl requests")
+}
+
+// performLogin POSTs the configured form fields and copies the resulting cookies into the collector's jar
+func (cr *Crawler) performLogin(ctx context.Context) (int, error) {
+	login := cr.config.Auth.Login
+
+	loginURL, err := url.Parse(login.URL)
+	if err != nil {
+		return 0, errorwrapper.WrapError(err, "invalid login URL")
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return 0, errorwrapper.WrapError(err, "failed to create login cookie jar")
+	}
+
+	client := &http.Client{Transport: cr.transport, Jar: jar, Timeout: cr.requestTimeout}
+
+	form := url.Values{}
+	for key, value := range login.FormFields {
+		form.Set(key, value)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, errorwrapper.WrapError(err, "failed to build login request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if userAgent := cr.userAgents.Next(); userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	cr.applyAuthHeaders(&req.Header)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, errorwrapper.WrapError(err, "login request failed")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return 0, fmt.Errorf("login returned status %d", resp.StatusCode)
+	}
+
+	cookies := jar.Cookies(loginURL)
+	if len(cookies) == 0 {
+		return 0, fmt.Errorf("login response set no cookies")
+	}
+
+	for _, target := range cr.sessionCookieTargets(loginURL) {
+		cr.collector.SetCookies(target, cookies)
+	}
+
+	return len(cookies), nil
+}
+
+// sessionCookieTargets returns the site roots that receive the login cookies: the login host and every seed host
+func (cr *Crawler) sessionCookieTargets(loginURL *url.URL) []string {
+	seen := make(map[string]bool)
+	var targets []string
+
+	add := func(u *url.URL) {
+		root := u.Scheme + "://" + u.Host + "/"
+		if !seen[root] {
+			seen[root] = true
+			targets = append(targets, root)
+		}
+	}
+
+	add(loginURL)
+	for _, seed := range cr.seedURLs {
+		if parsed, err := url.Parse(seed); err == nil && parsed.Host != "" {
+			add(parsed)
+		}
+	}
+
+	return targets
+}
+
+// isLoginPage reports whether u points at the page unauthenticated requests are sent to
+func (cr *Crawler) isLoginPage(u *url.URL) bool {
+	if cr.auth == nil || cr.auth.loginPagePath == "" {
+		return false
+	}
+	return strings.TrimSuffix(u.Path, "/") == strings.TrimSuffix(cr.auth.loginPagePath, "/")
+}
+
+// handleSessionExpiry detects a crawl request that was redirected to the login page. The session is marked
+// expired so the next batch logs in again, and the login page itself is not crawled.
+func (cr *Crawler) handleSessionExpiry(r *colly.Response) bool {
+	if !cr.isLoginPage(r.Request.URL) {
+		return false
+	}
+
+	cr.auth.mutex.Lock()
+	alreadyWarned := cr.auth.expiryWarned
+	cr.auth.expiryWarned = true
+	cr.auth.authenticated = false
+	cr.auth.mutex.Unlock()
+
+	if !alreadyWarned {
+		cr.logger.Warn().
+			Str("url", r.Request.URL.String()).
+			Str("login_page_path", cr.auth.loginPagePath).
+			Msg("Crawl session appears expired (redirected to login page); skipping login page content until re-login")
+	} else {
+		cr.logger.Debug().Str("url", r.Request.URL.String()).Msg("Redirected to login page, skipping")
+	}
+
+	return true
+}
@@ -0,0 +1,122 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.FormValue("user") == "admin" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "valid", Path: "/"})
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body><a href="/forgot">forgot</a></body></html>`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "valid" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body><a href="/secret">secret</a></body></html>`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func runAuthTestCrawl(t *testing.T, server *httptest.Server, auth config.CrawlerAuthConfig) []string {
+	cfg := config.NewDefaultCrawlerConfig()
+	cfg.SeedURLs = []string{server.URL + "/"}
+	cfg.MaxDepth = 2
+	cfg.RetryConfig.MaxRetries = 0
+	cfg.AutoCalibrate.Enabled = false
+	cfg.Auth = auth
+
+	cr, err := NewCrawler(&cfg, zerolog.Nop())
+	require.NoError(t, err)
+	defer cr.Stop()
+
+	cr.RunBatch(context.Background(), cfg.SeedURLs)
+	return cr.GetDiscoveredURLs()
+}
+
+func TestCrawler_LoginSessionSharedWithCrawl(t *testing.T) {
+	server := newAuthTestServer()
+	defer server.Close()
+
+	auth := config.NewDefaultCrawlerAuthConfig()
+	auth.Login.URL = server.URL + "/login"
+	auth.Login.FormFields = map[string]string{"user": "admin"}
+
+	discovered := runAuthTestCrawl(t, server, auth)
+	assert.Contains(t, discovered, server.URL+"/secret")
+}
+
+func TestCrawler_ExpiredSessionSkipsLoginPage(t *testing.T) {
+	server := newAuthTestServer()
+	defer server.Close()
+
+	auth := config.NewDefaultCrawlerAuthConfig()
+	auth.LoginPagePath = "/login"
+
+	discovered := runAuthTestCrawl(t, server, auth)
+	assert.NotContains(t, discovered, server.URL+"/secret")
+	assert.NotContains(t, discovered, server.URL+"/forgot")
+}
+
+func TestCrawlerAuthConfig_ResolvedLoginPagePath(t *testing.T) {
+	auth := config.NewDefaultCrawlerAuthConfig()
+	assert.Equal(t, "", auth.ResolvedLoginPagePath())
+
+	auth.Login.URL = "https://example.com/account/login?next=/"
+	assert.Equal(t, "/account/login", auth.ResolvedLoginPagePath())
+
+	auth.LoginPagePath = "/signin"
+	assert.Equal(t, "/signin", auth.ResolvedLoginPagePath())
+}
+
+func TestCrawler_TargetCredentialsPerURL(t *testing.T) {
+	var mutex sync.Mutex
+	received := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		received[r.URL.Path] = r.Header.Get("Authorization")
+		mutex.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body>ok</body></html>`))
+	}))
+	defer server.Close()
+
+	var creds urlhandler.TargetCredentials
+	creds.Add(server.URL+"/api", urlhandler.TargetAuth{Scheme: urlhandler.TargetAuthBearer, Token: "api-token"})
+	creds.Add(server.URL+"/admin", urlhandler.TargetAuth{Scheme: urlhandler.TargetAuthBasic, Username: "root", Password: "pw"})
+
+	cfg := config.NewDefaultCrawlerConfig()
+	cfg.SeedURLs = []string{server.URL + "/admin/", server.URL + "/"}
+	cfg.MaxDepth = 1
+	cfg.RetryConfig.MaxRetries = 0
+	cfg.AutoCalibrate.Enabled = false
+	cfg.TargetCredentials = creds
+
+	cr, err := NewCrawler(&cfg, zerolog.Nop())
+	require.NoError(t, err)
+	defer cr.Stop()
+	cr.RunBatch(context.Background(), cfg.SeedURLs)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, "Basic cm9vdDpwdw==", received["/admin/"])
+	assert.Equal(t, "Bearer api-token", received["/"], "other paths on the host fall back to its first credentials")
+}
@@ -5,10 +5,14 @@ import (
 	"time"
 )
 
+// defaultStopTimeout bounds how long shutdown waits for requests already in flight
+const defaultStopTimeout = 5 * time.Second
+
 // RunBatch runs the crawler for a specific batch without full initialization/shutdown
 func (cr *Crawler) RunBatch(ctx context.Context, seedURLs []string) {
 	cr.ctx = ctx
 	cr.crawlStartTime = time.Now()
+	cr.budget.start(cr.crawlStartTime)
 
 	// Start URL batch processor if not already running
 	if cr.urlQueue == nil {
@@ -19,9 +23,49 @@ func (cr *Crawler) RunBatch(ctx context.Context, seedURLs []string) {
 		Int("seed_count", len(seedURLs)).
 		Msg("Starting crawler batch")
 
-	// Process seed URLs for this batch
-	cr.processSeedURLsBatch(seedURLs)
-	cr.waitForBatchCompletion()
+	cr.ensureAuthenticated(ctx)
+
+	if cr.crawlStatePath() == "" {
+		// Process seed URLs for this batch
+		cr.processSeedURLsBatch(seedURLs)
+		cr.waitForBatchCompletion()
+		cr.logBatchSummary(seedURLs)
+		return
+	}
+
+	cr.runResumableBatch(ctx, seedURLs)
+}
+
+// runResumableBatch crawls with periodic frontier snapshots, resuming from a saved state when one matches the seeds
+func (cr *Crawler) runResumableBatch(ctx context.Context, seedURLs []string) {
+	if state := cr.loadCrawlState(seedURLs); state != nil {
+		cr.restoreState(state)
+		cr.logger.Info().
+			Int("visited", len(state.Visited)).
+			Int("frontier", len(state.Frontier)).
+			Time("saved_at", state.SavedAt).
+			Str("state_file", cr.crawlStatePath()).
+			Msg("Resuming crawl from saved frontier")
+
+		stopSnapshots := cr.startStateSnapshots()
+		cr.processBatch(state.Frontier)
+		cr.waitForBatchCompletion()
+		stopSnapshots()
+	} else {
+		stopSnapshots := cr.startStateSnapshots()
+		cr.processSeedURLsBatch(seedURLs)
+		cr.waitForBatchCompletion()
+		stopSnapshots()
+	}
+
+	if ctx.Err() != nil {
+		// Interrupted: keep the frontier so the next run can pick up from here
+		cr.saveCrawlState()
+		cr.logger.Info().Str("state_file", cr.crawlStatePath()).Msg("Crawl interrupted, frontier saved for resume")
+	} else {
+		cr.clearCrawlState(seedURLs)
+	}
+
 	cr.logBatchSummary(seedURLs)
 }
 
@@ -101,6 +145,13 @@ func (cr *Crawler) processBatch(urls []string) {
 			return
 		}
 
+		if cr.budget.expired() {
+			cr.logger.Debug().
+				Int("skipped", len(urls)-i).
+				Msg("Crawl duration budget reached, skipping the rest of the batch")
+			return
+		}
+
 		cr.logger.Debug().
 			Str("url", url).
 			Int("index", i+1).
@@ -151,6 +202,8 @@ func (cr *Crawler) processSeedURLsBatch(seedURLs []string) {
 		}
 	}
 
+	cr.discoverFromSitemaps(seedURLs)
+
 	cr.logger.Info().
 		Int("total_processed", len(seedURLs)).
 		Int("total_discovered", len(cr.discoveredURLs)).
@@ -244,6 +297,7 @@ func (cr *Crawler) ResetForNewBatch(newSeedURLs []string) {
 	// Reset discovered URLs for new batch
 	cr.discoveredURLs = make(map[string]bool)
 	cr.urlParentMap = make(map[string]string)
+	cr.visitedURLs = make(map[string]bool)
 
 	// Update seed URLs for this batch
 	cr.seedURLs = make([]string, len(newSeedURLs))
@@ -258,6 +312,11 @@ func (cr *Crawler) ResetForNewBatch(newSeedURLs []string) {
 		cr.patternDetector.Reset()
 	}
 
+	// Redirect budgets are per seed, so they start over with the new seeds
+	if cr.redirects != nil {
+		cr.redirects.reset()
+	}
+
 	cr.logger.Debug().
 		Int("new_seed_count", len(newSeedURLs)).
 		Msg("Crawler reset for new batch")
@@ -267,33 +326,49 @@ func (cr *Crawler) ResetForNewBatch(newSeedURLs []string) {
 func (cr *Crawler) Stop() {
 	cr.logger.Info().Msg("Stopping crawler...")
 
+	// Requests issued from responses still in flight are aborted, so the collector drains
+	cr.stopping.Store(true)
+
 	// Stop URL batch processor first to prevent new URLs being queued
 	if cr.urlQueue != nil {
 		cr.stopBatchedURLsProcessing()
 		cr.logger.Debug().Msg("URL batch processor stopped")
 	}
 
-	// Wait for colly collector to finish all current operations with timeout
-	if cr.collector != nil {
-		// Create a channel to signal when colly wait completes
-		done := make(chan struct{})
-		go func() {
-			defer close(done)
-			cr.collector.Wait()
-		}()
-
-		// Wait for either completion or timeout
-		select {
-		case <-done:
-			cr.logger.Debug().Msg("Colly collector stopped normally")
-		case <-time.After(5 * time.Second): // 5 second timeout for graceful stop
-			cr.logger.Warn().Msg("Colly collector stop timeout reached, forcing shutdown")
-		}
+	if cr.waitForCollector() {
+		cr.logger.Debug().Msg("Colly collector stopped normally")
+	} else {
+		cr.logger.Warn().Msg("Colly collector stop timeout reached, forcing shutdown")
 	}
 
 	cr.logger.Info().Msg("Crawler stopped completely")
 }
 
+// waitForCollector waits up to stopTimeout for the collector's in-flight requests, reporting whether
+// they finished. Requests still running afterwards end on their own request timeout and are discarded.
+func (cr *Crawler) waitForCollector() bool {
+	if cr.collector == nil {
+		return true
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cr.collector.Wait()
+	}()
+
+	timeout := cr.stopTimeout
+	if timeout <= 0 {
+		timeout = defaultStopTimeout
+	}
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
 // stopBatchedURLsProcessing gracefully stops the URL batch processor
 func (cr *Crawler) stopBatchedURLsProcessing() {
 	cr.mutex.Lock()
@@ -320,10 +395,11 @@ func (cr *Crawler) EnsureFullShutdown() {
 	cr.batchWG.Wait()
 	cr.logger.Debug().Msg("URL batch processor goroutines completed")
 
-	// Double-check colly is done
-	if cr.collector != nil {
-		cr.collector.Wait()
+	// Double-check colly is done; an unbounded wait here used to hang shutdown on a stuck request
+	if cr.waitForCollector() {
 		cr.logger.Debug().Msg("Colly collector confirmed stopped")
+	} else {
+		cr.logger.Warn().Dur("timeout", cr.stopTimeout).Msg("Colly collector still has requests in flight, continuing shutdown without them")
 	}
 
 	// Log pattern detector statistics before shutdown
@@ -0,0 +1,82 @@
+package crawler
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// crawlBudget bounds the work of one crawl run by the number of discovered URLs queued and by wall-clock time.
+// Once either budget is spent no further URLs are queued; requests already in flight finish and the crawl ends
+// with what was collected. Reservations are atomic, so concurrent workers never overshoot the URL budget.
+type crawlBudget struct {
+	maxURLs     int64         // 0 = unlimited
+	maxDuration time.Duration // 0 = unlimited
+	queued      atomic.Int64
+	deadline    atomic.Int64 // Unix nanoseconds, 0 until the crawl starts
+	exhausted   atomic.Bool
+	logger      zerolog.Logger
+}
+
+// newCrawlBudget creates a crawlBudget from the crawler configuration
+func newCrawlBudget(cfg *config.CrawlerConfig, logger zerolog.Logger) *crawlBudget {
+	return &crawlBudget{
+		maxURLs:     int64(max(cfg.MaxTotalURLs, 0)),
+		maxDuration: time.Duration(max(cfg.MaxCrawlDurationSecs, 0)) * time.Second,
+		logger:      logger,
+	}
+}
+
+// start resets the budget and starts the duration clock
+func (cb *crawlBudget) start(now time.Time) {
+	cb.queued.Store(0)
+	cb.exhausted.Store(false)
+	if cb.maxDuration > 0 {
+		cb.deadline.Store(now.Add(cb.maxDuration).UnixNano())
+	} else {
+		cb.deadline.Store(0)
+	}
+}
+
+// reserve claims one URL from the budget, reporting false once either budget is spent
+func (cb *crawlBudget) reserve() bool {
+	if cb.expired() {
+		cb.markExhausted("max_crawl_duration_secs")
+		return false
+	}
+	if cb.maxURLs == 0 {
+		return true
+	}
+
+	for {
+		queued := cb.queued.Load()
+		if queued >= cb.maxURLs {
+			cb.markExhausted("max_total_urls")
+			return false
+		}
+		if cb.queued.CompareAndSwap(queued, queued+1) {
+			return true
+		}
+	}
+}
+
+// expired reports whether the crawl has run past its duration budget
+func (cb *crawlBudget) expired() bool {
+	deadline := cb.deadline.Load()
+	return deadline != 0 && time.Now().UnixNano() >= deadline
+}
+
+// markExhausted logs, once per crawl, which budget stopped the crawl from growing
+func (cb *crawlBudget) markExhausted(budget string) {
+	if !cb.exhausted.CompareAndSwap(false, true) {
+		return
+	}
+	cb.logger.Warn().
+		Str("budget", budget).
+		Int64("max_total_urls", cb.maxURLs).
+		Dur("max_crawl_duration", cb.maxDuration).
+		Int64("urls_queued", cb.queued.Load()).
+		Msg("Crawl budget reached, no more URLs will be queued; finishing with the URLs collected so far")
+}
@@ -0,0 +1,86 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrawlBudget_URLLimitIsAtomic(t *testing.T) {
+	cb := newCrawlBudget(&config.CrawlerConfig{MaxTotalURLs: 10}, zerolog.Nop())
+	cb.start(time.Now())
+
+	var granted atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if cb.reserve() {
+					granted.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(10), granted.Load())
+
+	cb.start(time.Now())
+	assert.True(t, cb.reserve(), "a new crawl starts with a fresh budget")
+}
+
+func TestCrawlBudget_Duration(t *testing.T) {
+	cb := newCrawlBudget(&config.CrawlerConfig{MaxCrawlDurationSecs: 60}, zerolog.Nop())
+
+	cb.start(time.Now())
+	assert.False(t, cb.expired())
+	assert.True(t, cb.reserve())
+
+	cb.start(time.Now().Add(-time.Hour))
+	assert.True(t, cb.expired())
+	assert.False(t, cb.reserve())
+
+	unlimited := newCrawlBudget(&config.CrawlerConfig{}, zerolog.Nop())
+	unlimited.start(time.Now().Add(-time.Hour))
+	assert.False(t, unlimited.expired())
+	assert.True(t, unlimited.reserve())
+}
+
+func TestCrawler_StopsAtURLBudget(t *testing.T) {
+	// A listing page linking to far more pages than the budget allows
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, "<html><body>")
+		for page := 1; page <= 50; page++ {
+			_, _ = fmt.Fprintf(w, `<a href="/list?page=%d">%d</a>`, page, page)
+		}
+		_, _ = fmt.Fprint(w, "</body></html>")
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultCrawlerConfig()
+	cfg.SeedURLs = []string{server.URL + "/list?page=0"}
+	cfg.MaxDepth = 2
+	cfg.MaxTotalURLs = 5
+	cfg.RetryConfig.MaxRetries = 0
+	cfg.AutoCalibrate.Enabled = false
+
+	cr, err := NewCrawler(&cfg, zerolog.Nop())
+	require.NoError(t, err)
+	defer cr.Stop()
+	cr.RunBatch(context.Background(), cfg.SeedURLs)
+
+	assert.Len(t, cr.GetDiscoveredURLs(), 5)
+}
@@ -34,8 +34,10 @@ func (cb *CrawlerBuilder) Build() (*Crawler, error) {
 	crawler := &Crawler{
 		discoveredURLs: make(map[string]bool),
 		urlParentMap:   make(map[string]string),
+		visitedURLs:    make(map[string]bool),
 		logger:         cb.logger,
 		config:         cb.config,
+		stopTimeout:    defaultStopTimeout,
 	}
 
 	if err := crawler.initialize(); err != nil {
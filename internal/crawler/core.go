@@ -2,9 +2,12 @@ package crawler
 
 import (
 	"context"
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
 	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/gocolly/colly/v2"
@@ -23,8 +26,11 @@ type Crawler struct {
 	collector      *colly.Collector
 	discoveredURLs map[string]bool
 	// Track parent URL for each discovered URL
-	urlParentMap   map[string]string // child URL -> parent URL
+	urlParentMap map[string]string // child URL -> parent URL
+	// URLs whose request finished (response, error or abort); discovered minus visited is the frontier
+	visitedURLs    map[string]bool
 	mutex          sync.RWMutex
+	stateMutex     sync.Mutex // Serializes crawl state file access
 	requestTimeout time.Duration
 	threads        int
 	maxDepth       int
@@ -48,6 +54,20 @@ type Crawler struct {
 	patternDetector *URLPatternDetector
 	// Stats callback for monitoring
 	statsCallback StatsCallback
+	// Per-request User-Agent selection
+	userAgents *httpclient.UserAgentRotator
+	// Transport shared by the collector and the login step
+	transport http.RoundTripper
+	// Login session shared by all crawl workers
+	auth *authSession
+	// Redirect loop detection and per-seed redirect budget
+	redirects *redirectGuard
+	// URL count and duration caps for one crawl run
+	budget *crawlBudget
+	// Set by Stop so responses still in flight cannot queue new requests
+	stopping atomic.Bool
+	// How long Stop and EnsureFullShutdown wait for in-flight requests
+	stopTimeout time.Duration
 }
 
 // NewCrawler initializes a new Crawler based on the provided configuration
@@ -75,6 +95,13 @@ func (cr *Crawler) TrackURLParent(childURL, parentURL string) {
 	cr.urlParentMap[childURL] = parentURL
 }
 
+// GetURLParent returns the page a URL was discovered on, or "" for seeds and URLs without a tracked parent
+func (cr *Crawler) GetURLParent(discoveredURL string) string {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+	return cr.urlParentMap[discoveredURL]
+}
+
 // GetRootTargetForDiscoveredURL returns the root target URL for a discovered URL
 // by tracing back through the parent chain to find the original seed URL
 func (cr *Crawler) GetRootTargetForDiscoveredURL(discoveredURL string) string {
@@ -0,0 +1,285 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/gocolly/colly/v2"
+)
+
+// crawlStateFileVersion is bumped when the on-disk layout changes incompatibly
+const crawlStateFileVersion = 1
+
+// crawlStateURLKey stores the URL a request was issued for, so redirects still mark the original visited
+const crawlStateURLKey = "crawl_state_url"
+
+// CrawlState is a resumable snapshot of one crawl batch
+type CrawlState struct {
+	SavedAt      time.Time               `json:"saved_at"`
+	SeedURLs     []string                `json:"seed_urls"`
+	Visited      []string                `json:"visited"`
+	Frontier     []string                `json:"frontier"`
+	URLParents   map[string]string       `json:"url_parents,omitempty"`
+	PatternState URLPatternDetectorState `json:"pattern_state"`
+	TotalVisited int                     `json:"total_visited"`
+	TotalErrors  int                     `json:"total_errors"`
+}
+
+// crawlStateFile holds states for every batch of a scan, keyed by a hash of the batch seeds
+type crawlStateFile struct {
+	Version int                   `json:"version"`
+	Batches map[string]CrawlState `json:"batches"`
+}
+
+// crawlStateKey identifies a batch by its seed URLs regardless of order
+func crawlStateKey(seedURLs []string) string {
+	sorted := slices.Clone(seedURLs)
+	slices.Sort(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// readCrawlStateFile loads the state file, returning an empty one when it does not exist
+func readCrawlStateFile(path string) (*crawlStateFile, error) {
+	stateFile := &crawlStateFile{Version: crawlStateFileVersion, Batches: map[string]CrawlState{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return stateFile, nil
+	}
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to read crawl state file: "+path)
+	}
+
+	if err := json.Unmarshal(data, stateFile); err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to parse crawl state file: "+path)
+	}
+	if stateFile.Version != crawlStateFileVersion {
+		return nil, errorwrapper.NewValidationError("version", stateFile.Version, "unsupported crawl state file version")
+	}
+	if stateFile.Batches == nil {
+		stateFile.Batches = map[string]CrawlState{}
+	}
+
+	return stateFile, nil
+}
+
+// writeCrawlStateFile atomically replaces the state file, removing it when no batches remain
+func writeCrawlStateFile(path string, stateFile *crawlStateFile) error {
+	if len(stateFile.Batches) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errorwrapper.WrapError(err, "failed to remove crawl state file: "+path)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(stateFile)
+	if err != nil {
+		return errorwrapper.WrapError(err, "failed to encode crawl state")
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errorwrapper.WrapError(err, "failed to create crawl state directory: "+dir)
+		}
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return errorwrapper.WrapError(err, "failed to write crawl state file: "+tmpPath)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return errorwrapper.WrapError(err, "failed to replace crawl state file: "+path)
+	}
+
+	return nil
+}
+
+// crawlStatePath returns the configured state file path, empty when resume is disabled
+func (cr *Crawler) crawlStatePath() string {
+	return cr.config.CrawlState.FilePath
+}
+
+// loadCrawlState returns the saved state for the given seeds, or nil if there is none
+func (cr *Crawler) loadCrawlState(seedURLs []string) *CrawlState {
+	cr.stateMutex.Lock()
+	defer cr.stateMutex.Unlock()
+
+	stateFile, err := readCrawlStateFile(cr.crawlStatePath())
+	if err != nil {
+		cr.logger.Warn().Err(err).Str("state_file", cr.crawlStatePath()).Msg("Ignoring unreadable crawl state, starting from seeds")
+		return nil
+	}
+
+	state, exists := stateFile.Batches[crawlStateKey(seedURLs)]
+	if !exists {
+		return nil
+	}
+	return &state
+}
+
+// saveCrawlState writes the current frontier and visited set for this batch
+func (cr *Crawler) saveCrawlState() {
+	state := cr.snapshotState()
+
+	cr.stateMutex.Lock()
+	defer cr.stateMutex.Unlock()
+
+	stateFile, err := readCrawlStateFile(cr.crawlStatePath())
+	if err != nil {
+		cr.logger.Warn().Err(err).Msg("Overwriting unreadable crawl state file")
+		stateFile = &crawlStateFile{Version: crawlStateFileVersion, Batches: map[string]CrawlState{}}
+	}
+
+	stateFile.Batches[crawlStateKey(state.SeedURLs)] = state
+	if err := writeCrawlStateFile(cr.crawlStatePath(), stateFile); err != nil {
+		cr.logger.Error().Err(err).Msg("Failed to save crawl state")
+		return
+	}
+
+	cr.logger.Debug().
+		Int("visited", len(state.Visited)).
+		Int("frontier", len(state.Frontier)).
+		Str("state_file", cr.crawlStatePath()).
+		Msg("Crawl state snapshot saved")
+}
+
+// clearCrawlState removes this batch from the state file after a clean finish
+func (cr *Crawler) clearCrawlState(seedURLs []string) {
+	cr.stateMutex.Lock()
+	defer cr.stateMutex.Unlock()
+
+	stateFile, err := readCrawlStateFile(cr.crawlStatePath())
+	if err != nil {
+		cr.logger.Warn().Err(err).Msg("Failed to read crawl state file for cleanup")
+		return
+	}
+
+	key := crawlStateKey(seedURLs)
+	if _, exists := stateFile.Batches[key]; !exists {
+		return
+	}
+
+	delete(stateFile.Batches, key)
+	if err := writeCrawlStateFile(cr.crawlStatePath(), stateFile); err != nil {
+		cr.logger.Error().Err(err).Msg("Failed to clean up crawl state")
+	}
+}
+
+// snapshotState captures the crawler's resumable state
+func (cr *Crawler) snapshotState() CrawlState {
+	cr.mutex.RLock()
+	state := CrawlState{
+		SavedAt:      time.Now(),
+		SeedURLs:     slices.Clone(cr.seedURLs),
+		Visited:      make([]string, 0, len(cr.visitedURLs)),
+		URLParents:   make(map[string]string, len(cr.urlParentMap)),
+		TotalVisited: cr.totalVisited,
+		TotalErrors:  cr.totalErrors,
+	}
+	for visitedURL := range cr.visitedURLs {
+		state.Visited = append(state.Visited, visitedURL)
+	}
+	for discoveredURL := range cr.discoveredURLs {
+		if !cr.visitedURLs[discoveredURL] {
+			state.Frontier = append(state.Frontier, discoveredURL)
+		}
+	}
+	for child, parent := range cr.urlParentMap {
+		state.URLParents[child] = parent
+	}
+	cr.mutex.RUnlock()
+
+	if cr.patternDetector != nil {
+		state.PatternState = cr.patternDetector.ExportState()
+	}
+
+	return state
+}
+
+// restoreState loads a saved snapshot; visited URLs count as discovered so they are never re-queued
+func (cr *Crawler) restoreState(state *CrawlState) {
+	cr.mutex.Lock()
+	for _, visitedURL := range state.Visited {
+		cr.visitedURLs[visitedURL] = true
+		cr.discoveredURLs[visitedURL] = true
+	}
+	for _, frontierURL := range state.Frontier {
+		cr.discoveredURLs[frontierURL] = true
+	}
+	for child, parent := range state.URLParents {
+		cr.urlParentMap[child] = parent
+	}
+	cr.totalVisited = state.TotalVisited
+	cr.totalErrors = state.TotalErrors
+	cr.mutex.Unlock()
+
+	if cr.patternDetector != nil {
+		cr.patternDetector.RestoreState(state.PatternState)
+	}
+}
+
+// startStateSnapshots periodically saves crawl state until the returned stop function is called
+func (cr *Crawler) startStateSnapshots() func() {
+	interval := time.Duration(getIntValueOrDefault(cr.config.CrawlState.SnapshotIntervalSecs, config.DefaultCrawlStateSnapshotIntervalSecs)) * time.Second
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				cr.saveCrawlState()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+	}
+}
+
+// markRequestStarted remembers the requested URL across redirects for visited tracking
+func (cr *Crawler) markRequestStarted(r *colly.Request) {
+	if r.Ctx.Get(crawlStateURLKey) == "" {
+		r.Ctx.Put(crawlStateURLKey, r.URL.String())
+	}
+}
+
+// markRequestFinished records a request as visited once it produced a response or error
+func (cr *Crawler) markRequestFinished(r *colly.Request) {
+	if r == nil {
+		return
+	}
+
+	requestedURL := r.Ctx.Get(crawlStateURLKey)
+
+	cr.mutex.Lock()
+	if requestedURL != "" {
+		cr.visitedURLs[requestedURL] = true
+	}
+	cr.visitedURLs[r.URL.String()] = true
+	cr.mutex.Unlock()
+}
+
+// markURLVisited records a URL as done without a response (aborted or refused by colly)
+func (cr *Crawler) markURLVisited(visitedURL string) {
+	cr.mutex.Lock()
+	cr.visitedURLs[visitedURL] = true
+	cr.mutex.Unlock()
+}
@@ -0,0 +1,73 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+func newStateTestCrawler(t *testing.T, statePath string, seeds []string) *Crawler {
+	t.Helper()
+
+	cfg := config.NewDefaultCrawlerConfig()
+	cfg.SeedURLs = seeds
+	cfg.CrawlState.FilePath = statePath
+
+	cr, err := NewCrawler(&cfg, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to create crawler: %v", err)
+	}
+	cr.ResetForNewBatch(seeds)
+	return cr
+}
+
+func TestCrawlStateSaveRestoreAndClear(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "crawl.json")
+	seeds := []string{"https://example.com/", "https://example.com/start"}
+
+	cr := newStateTestCrawler(t, statePath, seeds)
+	cr.discoveredURLs["https://example.com/"] = true
+	cr.discoveredURLs["https://example.com/a"] = true
+	cr.discoveredURLs["https://example.com/b"] = true
+	cr.markURLVisited("https://example.com/")
+	cr.TrackURLParent("https://example.com/a", "https://example.com/")
+	cr.patternDetector.ShouldSkipURL("https://example.com/a")
+	cr.saveCrawlState()
+
+	// Same seeds in a different order resolve to the same batch state
+	resumed := newStateTestCrawler(t, statePath, seeds)
+	state := resumed.loadCrawlState([]string{seeds[1], seeds[0]})
+	if state == nil {
+		t.Fatal("expected saved crawl state to be found")
+	}
+
+	frontier := slices.Clone(state.Frontier)
+	slices.Sort(frontier)
+	if !slices.Equal(frontier, []string{"https://example.com/a", "https://example.com/b"}) {
+		t.Errorf("unexpected frontier: %v", frontier)
+	}
+
+	resumed.restoreState(state)
+	if !resumed.isURLAlreadyDiscovered("https://example.com/") {
+		t.Error("visited URL should be treated as discovered after restore")
+	}
+	if got := resumed.GetRootTargetForDiscoveredURL("https://example.com/a"); got != "https://example.com/" {
+		t.Errorf("expected parent chain to be restored, got %q", got)
+	}
+	if !resumed.patternDetector.ShouldSkipURL("https://example.com/a") {
+		t.Error("pattern detector should remember URLs seen before the interruption")
+	}
+
+	if other := resumed.loadCrawlState([]string{"https://other.example/"}); other != nil {
+		t.Error("state must not be reused for a batch with different seeds")
+	}
+
+	resumed.clearCrawlState(seeds)
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("expected state file to be removed after clean finish, stat err: %v", err)
+	}
+}
@@ -123,6 +123,11 @@ func (cr *Crawler) queueURLForVisit(normalizedURL string) {
 		return
 	}
 
+	if !cr.budget.reserve() {
+		cr.mutex.Unlock()
+		return
+	}
+
 	cr.discoveredURLs[normalizedURL] = true
 	cr.mutex.Unlock()
 
@@ -10,6 +10,7 @@ import (
 // handleError processes colly error callbacks
 func (cr *Crawler) handleError(r *colly.Response, e error) {
 	cr.incrementErrorCount()
+	cr.markRequestFinished(r.Request)
 
 	if cr.isContextCancelled() {
 		cr.logger.Warn().Str("url", r.Request.URL.String()).Err(e).Msg("Request failed after context cancellation")
@@ -38,19 +39,48 @@ func (cr *Crawler) handleRequest(r *colly.Request) {
 		return
 	}
 
+	if cr.stopping.Load() {
+		cr.logger.Debug().Str("url", r.URL.String()).Msg("Crawler stopping, aborting request")
+		r.Abort()
+		return
+	}
+
 	if cr.shouldAbortRequest(r) {
 		cr.logger.Info().
 			Str("url", r.URL.String()).
 			Str("path", r.URL.Path).
 			Msg("Abort request (file extension not allowed)")
+		cr.markURLVisited(r.URL.String())
 		r.Abort()
 		return
 	}
 
+	// Never crawl the login page itself; re-posting or following it would reset the session
+	if cr.isLoginPage(r.URL) {
+		cr.logger.Debug().Str("url", r.URL.String()).Msg("Abort request (login page)")
+		cr.markURLVisited(r.URL.String())
+		r.Abort()
+		return
+	}
+
+	cr.markRequestStarted(r)
+
 	// Add cache control headers to disable caching
 	r.Headers.Set("Cache-Control", "no-cache, no-store, must-revalidate")
 	r.Headers.Set("Pragma", "no-cache")
 	r.Headers.Set("Expires", "0")
+
+	if userAgent := cr.userAgents.Next(); userAgent != "" {
+		r.Headers.Set("User-Agent", userAgent)
+	}
+
+	// Apply configured default and per-host headers (e.g. pinned Accept-Language)
+	for key, value := range cr.config.RequestHeaders.HeadersForHost(r.URL.Hostname()) {
+		r.Headers.Set(key, value)
+	}
+
+	cr.applyAuthHeaders(r.Headers)
+	cr.applyTargetCredentials(r.URL, r.Headers)
 }
 
 // handleResponse processes colly response callbacks
@@ -62,6 +92,13 @@ func (cr *Crawler) handleResponse(r *colly.Response) {
 		Msg("Received response")
 
 	cr.incrementVisitedCount()
+	cr.markRequestFinished(r.Request)
+
+	if cr.handleSessionExpiry(r) {
+		return
+	}
+
+	cr.discoverFromHeaders(r)
 
 	if cr.isHTMLContent(r) {
 		cr.extractAssetsFromResponse(r)
@@ -157,6 +194,7 @@ func (cr *Crawler) incrementVisitedCount() {
 // handleVisitError handles errors from colly Visit calls
 func (cr *Crawler) handleVisitError(normalizedURL string, err error) {
 	if strings.Contains(err.Error(), "already visited") || errors.Is(err, colly.ErrRobotsTxtBlocked) {
+		cr.markURLVisited(normalizedURL)
 		return
 	}
 
@@ -0,0 +1,110 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/gocolly/colly/v2"
+)
+
+// bareHostnameRegex matches header tokens that are a hostname with an optional port (e.g. api.example.com:8443)
+var bareHostnameRegex = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*\.[a-z]{2,}(:\d{1,5})?$`)
+
+// discoverFromHeaders feeds URLs found in the configured response headers back into discovery
+func (cr *Crawler) discoverFromHeaders(r *colly.Response) {
+	if !cr.config.HeaderScopeExpansion.Enabled || r.Headers == nil {
+		return
+	}
+
+	base := r.Request.URL
+	for _, headerName := range cr.config.HeaderScopeExpansion.Headers {
+		for _, headerValue := range r.Headers.Values(headerName) {
+			for _, candidate := range extractURLsFromHeader(headerName, headerValue) {
+				cr.discoverHeaderURL(candidate, headerName, base)
+			}
+		}
+	}
+}
+
+// discoverHeaderURL scope-checks a header candidate and queues it, logging newly found in-scope URLs
+func (cr *Crawler) discoverHeaderURL(candidate string, headerName string, base *url.URL) {
+	absURL, err := urlhandler.ResolveURL(candidate, base)
+	if err != nil {
+		cr.logger.Debug().Str("candidate", candidate).Str("header", headerName).Err(err).Msg("Could not resolve URL from response header")
+		return
+	}
+
+	if !cr.isURLInScope(absURL) {
+		cr.logger.Debug().
+			Str("url", absURL).
+			Str("header", headerName).
+			Str("source_url", base.String()).
+			Msg("URL from response header is out of scope")
+		return
+	}
+
+	if cr.isURLAlreadyDiscovered(absURL) {
+		return
+	}
+
+	host := absURL
+	if parsed, err := url.Parse(absURL); err == nil {
+		host = parsed.Host
+	}
+
+	cr.logger.Info().
+		Str("host", host).
+		Str("url", absURL).
+		Str("header", headerName).
+		Str("source_url", base.String()).
+		Msg("Discovered URL from response header")
+
+	cr.DiscoverURL(absURL, base)
+}
+
+// extractURLsFromHeader returns URLs and bare hostnames found in a header value.
+// Link headers contribute their <...> targets (possibly relative); other headers are split into tokens.
+func extractURLsFromHeader(headerName string, headerValue string) []string {
+	var candidates []string
+
+	if strings.EqualFold(headerName, "Link") {
+		for _, part := range strings.Split(headerValue, ",") {
+			start := strings.Index(part, "<")
+			end := strings.Index(part, ">")
+			if start == -1 || end <= start+1 {
+				continue
+			}
+			if target := strings.TrimSpace(part[start+1 : end]); target != "" {
+				candidates = append(candidates, target)
+			}
+		}
+		return candidates
+	}
+
+	tokens := strings.FieldsFunc(headerValue, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t'
+	})
+	for _, token := range tokens {
+		token = strings.Trim(token, `<>"'`)
+		if strings.Contains(token, "*") {
+			continue
+		}
+
+		switch {
+		case isHeaderURLCandidate(token):
+			candidates = append(candidates, token)
+		case bareHostnameRegex.MatchString(token):
+			candidates = append(candidates, "https://"+token+"/")
+		}
+	}
+
+	return candidates
+}
+
+// isHeaderURLCandidate reports whether a token is an absolute or scheme-relative HTTP(S) URL
+func isHeaderURLCandidate(token string) bool {
+	lower := strings.ToLower(token)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "//")
+}
@@ -0,0 +1,47 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractURLsFromHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "link header targets",
+			header:   "Link",
+			value:    `<https://cdn.example.com/app.css>; rel=preload, </api/v2>; rel="alternate"`,
+			expected: []string{"https://cdn.example.com/app.css", "/api/v2"},
+		},
+		{
+			name:     "allow origin",
+			header:   "Access-Control-Allow-Origin",
+			value:    "https://app.example.com",
+			expected: []string{"https://app.example.com"},
+		},
+		{
+			name:     "wildcard origin ignored",
+			header:   "Access-Control-Allow-Origin",
+			value:    "*",
+			expected: nil,
+		},
+		{
+			name:     "bare hostnames in policy header",
+			header:   "Content-Security-Policy",
+			value:    "default-src 'self' api.example.com *.example.net; img-src https://img.example.com",
+			expected: []string{"https://api.example.com/", "https://img.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractURLsFromHeader(tt.header, tt.value))
+		})
+	}
+}
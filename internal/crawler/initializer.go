@@ -9,6 +9,7 @@ import (
 	"slices"
 
 	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/gocolly/colly/v2"
 )
@@ -30,6 +31,8 @@ func (cr *Crawler) initialize() error {
 	cr.initializeURLBatcher()
 	cr.initializeExtensionMap()
 	cr.initializePatternDetector()
+	cr.initializeUserAgents()
+	cr.initializeAuth()
 	cr.logInitialization()
 	return nil
 }
@@ -122,6 +125,9 @@ func (cr *Crawler) setupCollector() error {
 	}
 
 	cr.collector = collector
+	cr.redirects = newRedirectGuard(cr.config.Redirects, cr.logger)
+	cr.budget = newCrawlBudget(cr.config, cr.logger)
+	cr.collector.SetRedirectHandler(cr.handleRedirect)
 	cr.setupCallbacks()
 	return nil
 }
@@ -135,10 +141,21 @@ func (cr *Crawler) createCollector() (*colly.Collector, error) {
 	}
 
 	collector := colly.NewCollector(collectorOptions...)
-	collector.SetRequestTimeout(cr.requestTimeout)
+	collector.SetRequestTimeout(max(cr.requestTimeout, cr.config.TargetTimeouts.Max()))
+
+	proxyFunc, err := cr.config.Proxy.ProxyFunc()
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to configure crawler proxy")
+	}
+	if cr.config.Proxy.IsConfigured() {
+		cr.logger.Info().
+			Strs("no_proxy", cr.config.Proxy.NoProxy).
+			Msg("Colly configured with outbound proxy")
+	}
 
 	// Create base HTTP transport
-	baseTransport := &http.Transport{
+	httpTransport := &http.Transport{
+		Proxy: proxyFunc,
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: true,
 		},
@@ -146,6 +163,60 @@ func (cr *Crawler) createCollector() (*colly.Collector, error) {
 		MaxIdleConnsPerHost: 2,
 		IdleConnTimeout:     90 * time.Second,
 	}
+	var baseTransport http.RoundTripper = httpTransport
+
+	// Rotate over the proxy pool instead of the single proxy; the pool tracks which proxies keep failing
+	if cr.config.ProxyPool != nil {
+		baseTransport = httpclient.NewProxyPoolTransport(httpTransport, cr.config.ProxyPool)
+		cr.logger.Info().
+			Int("proxies", cr.config.ProxyPool.Size()).
+			Int("healthy_proxies", cr.config.ProxyPool.Healthy()).
+			Msg("Colly configured with proxy pool")
+	}
+
+	// Count every request that reaches the network against the limit shared with httpx. It sits below the
+	// per-host concurrency limit and the retry transport, so waiting for a host slot or a backoff holds no shared slot
+	if cr.config.InFlightLimiter != nil {
+		baseTransport = httpclient.NewInFlightTransport(baseTransport, cr.config.InFlightLimiter)
+		cr.logger.Info().
+			Int("max_in_flight_requests", cr.config.InFlightLimiter.Max()).
+			Msg("Colly configured with shared in-flight request limit")
+	}
+
+	// Wrap with fixture transport when recording or replaying HTTP exchanges
+	baseTransport, err = cr.wrapWithFixtureTransport(baseTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record every attempt, including replayed fixtures and retries, for the debug HAR export
+	if cr.config.HARRecorder != nil {
+		baseTransport = httpclient.NewHARTransport(baseTransport, cr.config.HARRecorder, httpclient.HARSourceCrawler)
+		cr.logger.Info().Msg("Colly configured with HAR recording transport")
+	}
+
+	// Limit per-host concurrency below the retry transport so every attempt, including retries, takes a slot
+	if cr.config.AdaptiveConcurrency.Enabled {
+		baseTransport = NewAdaptiveConcurrencyTransport(baseTransport, cr.config.AdaptiveConcurrency, cr.threads, cr.logger)
+		cr.logger.Info().
+			Int("max_concurrency", cr.threads).
+			Int("min_concurrency", cr.config.AdaptiveConcurrency.MinConcurrency).
+			Float64("decrease_factor", cr.config.AdaptiveConcurrency.DecreaseFactor).
+			Int("increase_after", cr.config.AdaptiveConcurrency.IncreaseAfter).
+			Ints("status_codes", cr.config.AdaptiveConcurrency.StatusCodes).
+			Msg("Colly configured with adaptive per-host concurrency")
+	}
+
+	// Space out requests per host above the concurrency limit, so a waiting request does not hold a slot
+	if cr.config.RequestDelayMs > 0 || cr.config.RequestDelayMaxMs > 0 {
+		baseTransport = NewRequestDelayTransport(baseTransport,
+			time.Duration(cr.config.RequestDelayMs)*time.Millisecond,
+			time.Duration(cr.config.RequestDelayMaxMs)*time.Millisecond)
+		cr.logger.Info().
+			Int("request_delay_ms", cr.config.RequestDelayMs).
+			Int("request_delay_max_ms", cr.config.RequestDelayMaxMs).
+			Msg("Colly configured with per-host request delay")
+	}
 
 	// Wrap with retry transport if retries are enabled
 	var transport http.RoundTripper = baseTransport
@@ -156,13 +227,25 @@ func (cr *Crawler) createCollector() (*colly.Collector, error) {
 			Int("base_delay_secs", cr.config.RetryConfig.BaseDelaySecs).
 			Int("max_delay_secs", cr.config.RetryConfig.MaxDelaySecs).
 			Bool("enable_jitter", cr.config.RetryConfig.EnableJitter).
+			Int("max_jitter_secs", cr.config.RetryConfig.MaxJitterSecs).
 			Ints("retry_status_codes", cr.config.RetryConfig.RetryStatusCodes).
 			Msg("Colly configured with retry transport for rate limiting")
 	}
 
+	// Give targets with a "|timeout=" annotation their own timeout; the collector's timeout is the longest of them
+	if cr.config.TargetTimeouts.Len() > 0 {
+		transport = NewTargetTimeoutTransport(transport, cr.requestTimeout, cr.config.TargetTimeouts)
+		cr.logger.Info().
+			Int("targets", cr.config.TargetTimeouts.Len()).
+			Dur("default_timeout", cr.requestTimeout).
+			Dur("max_timeout", cr.config.TargetTimeouts.Max()).
+			Msg("Colly configured with per-target request timeouts")
+	}
+
 	collector.WithTransport(transport)
+	cr.transport = transport
 
-	err := collector.Limit(&colly.LimitRule{
+	err = collector.Limit(&colly.LimitRule{
 		DomainGlob:  "*",
 		Parallelism: cr.threads,
 	})
@@ -174,6 +257,39 @@ func (cr *Crawler) createCollector() (*colly.Collector, error) {
 	return collector, nil
 }
 
+// wrapWithFixtureTransport wraps the base transport for fixture recording or replay if configured
+func (cr *Crawler) wrapWithFixtureTransport(base http.RoundTripper) (http.RoundTripper, error) {
+	fixtures := cr.config.Fixtures
+
+	if fixtures.RecordPath != "" && fixtures.ReplayPath != "" {
+		return nil, errorwrapper.NewValidationError("fixtures", fixtures, "record and replay fixture paths cannot both be set")
+	}
+
+	mode, path := httpclient.FixtureModeRecord, fixtures.RecordPath
+	if fixtures.ReplayPath != "" {
+		mode, path = httpclient.FixtureModeReplay, fixtures.ReplayPath
+	}
+
+	if path == "" {
+		return base, nil
+	}
+
+	fixtureTransport, err := httpclient.NewFixtureTransport(base, mode, path, cr.logger)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to set up fixture transport")
+	}
+
+	cr.logger.Info().
+		Str("fixture_mode", string(mode)).
+		Str("fixture_path", path).
+		Msg("Colly configured with HTTP fixture transport")
+	cr.logger.Warn().
+		Str("fixture_mode", string(mode)).
+		Msg("HTTP fixtures cover the crawler only; httpx probes still go to the network")
+
+	return fixtureTransport, nil
+}
+
 // setupCallbacks configures colly event callbacks
 func (cr *Crawler) setupCallbacks() {
 	cr.collector.OnError(cr.handleError)
@@ -196,6 +312,17 @@ func (cr *Crawler) initializePatternDetector() {
 	cr.patternDetector = NewURLPatternDetector(cr.config.AutoCalibrate, cr.logger)
 }
 
+// initializeUserAgents sets up User-Agent rotation; with nothing configured colly's default is kept
+func (cr *Crawler) initializeUserAgents() {
+	cr.userAgents = httpclient.NewUserAgentRotator(cr.config.UserAgent, cr.config.UserAgents, cr.config.UserAgentRotation)
+	if cr.userAgents.PoolSize() > 1 {
+		cr.logger.Info().
+			Int("user_agent_pool_size", cr.userAgents.PoolSize()).
+			Str("rotation", cr.config.UserAgentRotation).
+			Msg("User-Agent rotation enabled")
+	}
+}
+
 // logInitialization logs the initialization summary
 func (cr *Crawler) logInitialization() {
 	logEvent := cr.logger.Info().
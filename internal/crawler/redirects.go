@@ -0,0 +1,137 @@
+package crawler
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// redirectGuard breaks redirect loops and caps how many redirects the crawler follows,
+// both within one request and across everything crawled from the same seed
+type redirectGuard struct {
+	maxPerChain int
+	maxPerSeed  int // 0 = unlimited
+	counts      map[string]int
+	exhausted   map[string]bool
+	mutex       sync.Mutex
+	logger      zerolog.Logger
+}
+
+// newRedirectGuard creates a redirectGuard from the crawler redirect configuration
+func newRedirectGuard(cfg config.CrawlerRedirectConfig, logger zerolog.Logger) *redirectGuard {
+	return &redirectGuard{
+		maxPerChain: getIntValueOrDefault(cfg.MaxPerChain, config.DefaultCrawlerMaxRedirectsPerChain),
+		maxPerSeed:  cfg.MaxPerSeed,
+		counts:      make(map[string]int),
+		exhausted:   make(map[string]bool),
+		logger:      logger,
+	}
+}
+
+// check decides whether the redirect to req should be followed. Returning http.ErrUseLastResponse
+// stops the chain and hands the last redirect response to the crawler instead of failing the request.
+func (rg *redirectGuard) check(seed string, req *http.Request, via []*http.Request) error {
+	if isRedirectLoop(req, via) {
+		rg.logger.Warn().
+			Str("seed", seed).
+			Str("chain", formatRedirectChain(req, via)).
+			Msg("Redirect loop detected, stopping chain")
+		return http.ErrUseLastResponse
+	}
+
+	if len(via) >= rg.maxPerChain {
+		rg.logger.Warn().
+			Str("seed", seed).
+			Int("max_per_chain", rg.maxPerChain).
+			Str("chain", formatRedirectChain(req, via)).
+			Msg("Redirect chain limit reached, stopping chain")
+		return http.ErrUseLastResponse
+	}
+
+	if !rg.allowForSeed(seed) {
+		return http.ErrUseLastResponse
+	}
+
+	return nil
+}
+
+// allowForSeed counts one more redirect for seed and reports whether it is within the per-seed cap
+func (rg *redirectGuard) allowForSeed(seed string) bool {
+	if rg.maxPerSeed <= 0 {
+		return true
+	}
+
+	rg.mutex.Lock()
+	defer rg.mutex.Unlock()
+
+	if rg.counts[seed] >= rg.maxPerSeed {
+		// Warn once per seed; every later redirect from the same lineage is refused quietly
+		if !rg.exhausted[seed] {
+			rg.exhausted[seed] = true
+			rg.logger.Warn().
+				Str("seed", seed).
+				Int("max_per_seed", rg.maxPerSeed).
+				Msg("Redirect budget for seed exhausted, no longer following redirects")
+		}
+		return false
+	}
+
+	rg.counts[seed]++
+	return true
+}
+
+// reset clears the per-seed redirect counts
+func (rg *redirectGuard) reset() {
+	rg.mutex.Lock()
+	defer rg.mutex.Unlock()
+
+	rg.counts = make(map[string]int)
+	rg.exhausted = make(map[string]bool)
+}
+
+// isRedirectLoop reports whether req revisits a URL already in the chain. A single hop back to the
+// first URL is allowed, since sites commonly redirect there once after setting a session cookie.
+func isRedirectLoop(req *http.Request, via []*http.Request) bool {
+	target := req.URL.String()
+
+	seen := 0
+	for _, prev := range via {
+		if prev.URL.String() == target {
+			seen++
+		}
+	}
+
+	if seen == 1 && len(via) > 0 && via[0].URL.String() == target {
+		return false
+	}
+	return seen > 0
+}
+
+// formatRedirectChain renders the chain as "A -> B -> C" for logging
+func formatRedirectChain(req *http.Request, via []*http.Request) string {
+	hops := make([]string, 0, len(via)+1)
+	for _, prev := range via {
+		hops = append(hops, prev.URL.String())
+	}
+	hops = append(hops, req.URL.String())
+	return strings.Join(hops, " -> ")
+}
+
+// handleRedirect is the collector's redirect policy; it runs after colly's own domain and revisit checks
+func (cr *Crawler) handleRedirect(req *http.Request, via []*http.Request) error {
+	seed := cr.GetRootTargetForDiscoveredURL(via[0].URL.String())
+	if err := cr.redirects.check(seed, req, via); err != nil {
+		return err
+	}
+
+	// Same as colly's default policy: don't leak credentials to another host,
+	// but send the new host its own target credentials if it has any
+	if req.URL.Host != via[len(via)-1].URL.Host {
+		req.Header.Del("Authorization")
+		cr.applyTargetCredentials(req.URL, &req.Header)
+	}
+	return nil
+}
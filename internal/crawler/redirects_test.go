@@ -0,0 +1,57 @@
+package crawler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func redirectChain(t *testing.T, urls ...string) []*http.Request {
+	t.Helper()
+
+	chain := make([]*http.Request, 0, len(urls))
+	for _, u := range urls {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		require.NoError(t, err)
+		chain = append(chain, req)
+	}
+	return chain
+}
+
+func TestRedirectGuard_Loops(t *testing.T) {
+	rg := newRedirectGuard(config.CrawlerRedirectConfig{}, zerolog.Nop())
+	const seed = "https://example.com/"
+
+	// One hop back to the first URL is the session cookie pattern and is followed
+	hops := redirectChain(t, "https://example.com/a", "https://example.com/b", "https://example.com/a")
+	assert.NoError(t, rg.check(seed, hops[2], hops[:2]))
+
+	// Coming back a second time is a loop
+	hops = redirectChain(t, "https://example.com/a", "https://example.com/a", "https://example.com/a")
+	assert.ErrorIs(t, rg.check(seed, hops[2], hops[:2]), http.ErrUseLastResponse)
+
+	hops = redirectChain(t, "https://example.com/a", "https://example.com/b", "https://example.com/c", "https://example.com/b")
+	assert.ErrorIs(t, rg.check(seed, hops[3], hops[:3]), http.ErrUseLastResponse)
+	assert.Equal(t, "https://example.com/a -> https://example.com/b -> https://example.com/c -> https://example.com/b",
+		formatRedirectChain(hops[3], hops[:3]))
+}
+
+func TestRedirectGuard_Limits(t *testing.T) {
+	rg := newRedirectGuard(config.CrawlerRedirectConfig{MaxPerChain: 2, MaxPerSeed: 3}, zerolog.Nop())
+
+	hops := redirectChain(t, "https://example.com/1", "https://example.com/2", "https://example.com/3")
+	assert.NoError(t, rg.check("seed-a", hops[1], hops[:1]))
+	assert.ErrorIs(t, rg.check("seed-a", hops[2], hops[:2]), http.ErrUseLastResponse, "chain limit")
+
+	assert.NoError(t, rg.check("seed-a", hops[1], hops[:1]))
+	assert.NoError(t, rg.check("seed-a", hops[1], hops[:1]))
+	assert.ErrorIs(t, rg.check("seed-a", hops[1], hops[:1]), http.ErrUseLastResponse, "seed budget")
+	assert.NoError(t, rg.check("seed-b", hops[1], hops[:1]), "budgets are per seed")
+
+	rg.reset()
+	assert.NoError(t, rg.check("seed-a", hops[1], hops[:1]))
+}
@@ -0,0 +1,67 @@
+package crawler
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RequestDelayTransport spaces out requests to the same host: each request starts at least the
+// configured delay after the previous one to that host. Hosts are tracked separately, so a slow or
+// throttled host never delays requests to the others.
+type RequestDelayTransport struct {
+	base     http.RoundTripper
+	minDelay time.Duration
+	maxDelay time.Duration
+	nextSlot map[string]time.Time // Earliest start of the next request per host
+	mutex    sync.Mutex
+}
+
+// NewRequestDelayTransport creates a transport waiting minDelay between requests to a host, or a
+// random duration in [minDelay, maxDelay] when maxDelay is larger
+func NewRequestDelayTransport(base http.RoundTripper, minDelay, maxDelay time.Duration) *RequestDelayTransport {
+	return &RequestDelayTransport{
+		base:     base,
+		minDelay: minDelay,
+		maxDelay: max(maxDelay, minDelay),
+		nextSlot: make(map[string]time.Time),
+	}
+}
+
+// RoundTrip waits for the request's slot on its host, then sends it
+func (dt *RequestDelayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	wait := dt.reserve(req.URL.Host, time.Now())
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	return dt.base.RoundTrip(req)
+}
+
+// reserve books the next free slot on host and returns how long to wait for it. Booking slots up front
+// keeps concurrent requests to one host spaced out instead of all waking after the same delay.
+func (dt *RequestDelayTransport) reserve(host string, now time.Time) time.Duration {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	slot := dt.nextSlot[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	dt.nextSlot[host] = slot.Add(dt.nextDelay())
+	return slot.Sub(now)
+}
+
+// nextDelay returns the gap to leave after a request; callers hold the mutex
+func (dt *RequestDelayTransport) nextDelay() time.Duration {
+	if dt.maxDelay <= dt.minDelay {
+		return dt.minDelay
+	}
+	return dt.minDelay + time.Duration(rand.Int63n(int64(dt.maxDelay-dt.minDelay)+1))
+}
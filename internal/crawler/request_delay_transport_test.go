@@ -0,0 +1,51 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestDelayTransport_ReservesSlotsPerHost(t *testing.T) {
+	dt := NewRequestDelayTransport(&statusRoundTripper{status: http.StatusOK}, 100*time.Millisecond, 0)
+	now := time.Now()
+
+	assert.Zero(t, dt.reserve("a.example.com", now), "first request to a host is not delayed")
+	assert.Equal(t, 100*time.Millisecond, dt.reserve("a.example.com", now))
+	assert.Equal(t, 200*time.Millisecond, dt.reserve("a.example.com", now), "concurrent requests queue one delay apart")
+	assert.Zero(t, dt.reserve("b.example.com", now), "hosts are delayed independently")
+	assert.Zero(t, dt.reserve("a.example.com", now.Add(time.Second)), "an idle host is not delayed")
+}
+
+func TestRequestDelayTransport_RandomizedRange(t *testing.T) {
+	dt := NewRequestDelayTransport(&statusRoundTripper{status: http.StatusOK}, 50*time.Millisecond, 80*time.Millisecond)
+	for i := 0; i < 100; i++ {
+		delay := dt.nextDelay()
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 80*time.Millisecond)
+	}
+}
+
+func TestRequestDelayTransport_WaitsAndHonoursCancellation(t *testing.T) {
+	dt := NewRequestDelayTransport(&statusRoundTripper{status: http.StatusOK}, 50*time.Millisecond, 0)
+
+	send := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/", nil)
+		require.NoError(t, err)
+		_, err = dt.RoundTrip(req)
+		return err
+	}
+
+	start := time.Now()
+	require.NoError(t, send(context.Background()))
+	require.NoError(t, send(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, send(ctx), context.Canceled)
+}
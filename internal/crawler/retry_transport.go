@@ -26,6 +26,9 @@ func NewRetryTransport(base http.RoundTripper, retryConfig config.RetryConfig, u
 	for _, code := range retryConfig.RetryStatusCodes {
 		statusCodeMap[code] = true
 	}
+	for code := range retryConfig.StatusBackoff {
+		statusCodeMap[code] = true
+	}
 
 	return &RetryTransport{
 		base:             base,
@@ -109,7 +112,7 @@ func (rt *RetryTransport) shouldRetry(statusCode int, attempt int) bool {
 
 // waitForRetry waits for the calculated delay before retrying
 func (rt *RetryTransport) waitForRetry(ctx context.Context, attempt int, statusCode int, url string) error {
-	delay := rt.calculateDelay(attempt)
+	delay := rt.calculateDelay(attempt, statusCode)
 
 	rt.logger.Warn().
 		Str("url", url).
@@ -127,10 +130,12 @@ func (rt *RetryTransport) waitForRetry(ctx context.Context, attempt int, statusC
 	}
 }
 
-// calculateDelay calculates the delay for the next retry attempt using exponential backoff
-func (rt *RetryTransport) calculateDelay(attempt int) time.Duration {
-	baseDelay := time.Duration(rt.retryConfig.BaseDelaySecs) * time.Second
-	maxDelay := time.Duration(rt.retryConfig.MaxDelaySecs) * time.Second
+// calculateDelay calculates the delay for the next retry attempt using exponential backoff,
+// with the base and max delay taken from the status code's override when configured
+func (rt *RetryTransport) calculateDelay(attempt int, statusCode int) time.Duration {
+	baseDelaySecs, maxDelaySecs := rt.retryConfig.BackoffFor(statusCode)
+	baseDelay := time.Duration(baseDelaySecs) * time.Second
+	maxDelay := time.Duration(maxDelaySecs) * time.Second
 
 	if attempt <= 0 {
 		return baseDelay
@@ -146,13 +151,24 @@ func (rt *RetryTransport) calculateDelay(attempt int) time.Duration {
 
 	// Add jitter to prevent thundering herd
 	if rt.retryConfig.EnableJitter {
-		jitter := time.Duration(rand.Intn(int(delay.Milliseconds()/10))) * time.Millisecond
-		delay += jitter
+		delay += rt.calculateJitter(delay)
 	}
 
 	return delay
 }
 
+// calculateJitter returns a random jitter of up to 10% of delay, bounded by MaxJitterSecs when set
+func (rt *RetryTransport) calculateJitter(delay time.Duration) time.Duration {
+	maxJitter := delay / 10
+	if rt.retryConfig.MaxJitterSecs > 0 {
+		maxJitter = min(maxJitter, time.Duration(rt.retryConfig.MaxJitterSecs)*time.Second)
+	}
+	if maxJitter < time.Millisecond {
+		return 0
+	}
+	return time.Duration(rand.Int63n(maxJitter.Milliseconds())) * time.Millisecond
+}
+
 // cloneRequest creates a shallow clone of the HTTP request
 func (rt *RetryTransport) cloneRequest(req *http.Request) *http.Request {
 	// Clone the request
@@ -0,0 +1,74 @@
+package crawler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+func newTestRetryTransport(retryConfig config.RetryConfig) *RetryTransport {
+	return NewRetryTransport(http.DefaultTransport, retryConfig, urlhandler.DefaultURLNormalizationConfig(), zerolog.Nop())
+}
+
+func TestRetryTransport_CalculateDelay_PerStatusBackoff(t *testing.T) {
+	rt := newTestRetryTransport(config.RetryConfig{
+		MaxRetries:       3,
+		BaseDelaySecs:    10,
+		MaxDelaySecs:     60,
+		RetryStatusCodes: []int{429},
+		StatusBackoff: map[int]config.StatusBackoffConfig{
+			429: {BaseDelaySecs: 30, MaxDelaySecs: 300},
+			503: {BaseDelaySecs: 2},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		statusCode int
+		attempt    int
+		expected   time.Duration
+	}{
+		{"default backoff", 500, 1, 20 * time.Second},
+		{"default backoff capped", 500, 3, 60 * time.Second},
+		{"429 override base", 429, 0, 30 * time.Second},
+		{"429 override max", 429, 3, 240 * time.Second},
+		{"503 override base with default max", 503, 1, 4 * time.Second},
+		{"503 falls back to default max", 503, 5, 60 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rt.calculateDelay(tt.attempt, tt.statusCode); got != tt.expected {
+				t.Errorf("calculateDelay(%d, %d) = %v, want %v", tt.attempt, tt.statusCode, got, tt.expected)
+			}
+		})
+	}
+
+	if !rt.shouldRetry(503, 0) {
+		t.Error("expected status code with a backoff override to be retried")
+	}
+	if rt.shouldRetry(500, 0) {
+		t.Error("expected status code without retry configuration not to be retried")
+	}
+}
+
+func TestRetryTransport_CalculateDelay_JitterCap(t *testing.T) {
+	rt := newTestRetryTransport(config.RetryConfig{
+		MaxRetries:    3,
+		BaseDelaySecs: 100,
+		MaxDelaySecs:  3600,
+		EnableJitter:  true,
+		MaxJitterSecs: 1,
+	})
+
+	for i := 0; i < 50; i++ {
+		delay := rt.calculateDelay(2, 429)
+		if delay < 400*time.Second || delay >= 401*time.Second {
+			t.Fatalf("expected delay in [400s, 401s), got %v", delay)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrawler_ShutdownDoesNotWaitForStuckRequests(t *testing.T) {
+	release := make(chan struct{})
+	var otherHits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/other" {
+			otherHits.Add(1)
+			return
+		}
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := config.NewDefaultCrawlerConfig()
+	cfg.SeedURLs = []string{server.URL + "/stuck"}
+	cfg.RequestTimeoutSecs = 60
+	cfg.RetryConfig.MaxRetries = 0
+	cfg.AutoCalibrate.Enabled = false
+
+	cr, err := NewCrawler(&cfg, zerolog.Nop())
+	require.NoError(t, err)
+	cr.stopTimeout = 100 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	cr.RunBatch(ctx, cfg.SeedURLs)
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		cr.Stop()
+		cr.EnsureFullShutdown()
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown blocked on a request that never completes")
+	}
+
+	// A stopped crawler issues no new requests, e.g. for links parsed from late responses
+	cr.ctx = context.Background()
+	_ = cr.collector.Visit(server.URL + "/other")
+	assert.Equal(t, int64(0), otherHits.Load())
+}
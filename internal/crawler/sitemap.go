@@ -0,0 +1,187 @@
+package crawler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+)
+
+const (
+	// maxSitemapBytes is the largest sitemap read, the uncompressed limit of the sitemap protocol
+	maxSitemapBytes = 50 * 1024 * 1024
+	// maxSitemapIndexDepth bounds how many levels of nested sitemap indexes are followed
+	maxSitemapIndexDepth = 3
+)
+
+// sitemapDocument holds the locations of a <urlset> or a <sitemapindex>
+type sitemapDocument struct {
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+// sitemapEntry is a <url> or <sitemap> element
+type sitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// discoverFromSitemaps fetches the configured sitemaps of every seed host and queues the URLs they list.
+// Seeds were visited just before, so these URLs are crawled ahead of the links found on the seed pages.
+func (cr *Crawler) discoverFromSitemaps(seedURLs []string) {
+	if !cr.config.Sitemap.Enabled {
+		return
+	}
+
+	seenHosts := make(map[string]bool)
+	for _, seed := range seedURLs {
+		if cr.isContextCancelled() {
+			return
+		}
+
+		seedURL, err := url.Parse(seed)
+		if err != nil || seedURL.Host == "" {
+			continue
+		}
+		root := seedURL.Scheme + "://" + seedURL.Host
+		if seenHosts[root] {
+			continue
+		}
+		seenHosts[root] = true
+
+		cr.discoverFromHostSitemaps(seed, seedURL)
+	}
+}
+
+// discoverFromHostSitemaps queues the URLs of one host's sitemaps, up to the per-host limit
+func (cr *Crawler) discoverFromHostSitemaps(seed string, seedURL *url.URL) {
+	limit := cr.config.Sitemap.MaxURLsPerHost
+	visitedSitemaps := make(map[string]bool)
+	queued := 0
+
+	var walk func(sitemapURL *url.URL, depth int)
+	walk = func(sitemapURL *url.URL, depth int) {
+		if visitedSitemaps[sitemapURL.String()] || cr.isContextCancelled() || (limit > 0 && queued >= limit) {
+			return
+		}
+		visitedSitemaps[sitemapURL.String()] = true
+
+		doc, err := cr.fetchSitemap(sitemapURL)
+		if err != nil {
+			cr.logger.Debug().Str("sitemap", sitemapURL.String()).Err(err).Msg("Could not read sitemap")
+			return
+		}
+
+		for _, entry := range doc.URLs {
+			if limit > 0 && queued >= limit {
+				cr.logger.Info().Str("host", seedURL.Host).Int("limit", limit).Msg("Sitemap URL limit reached for host")
+				return
+			}
+			pageURL, ok := resolveSitemapLoc(entry.Loc, sitemapURL)
+			if !ok {
+				continue
+			}
+			cr.TrackURLParent(pageURL, sitemapURL.String())
+			cr.DiscoverURL(pageURL, sitemapURL)
+			queued++
+		}
+
+		if depth >= maxSitemapIndexDepth {
+			return
+		}
+		for _, entry := range doc.Sitemaps {
+			nestedURL, ok := resolveSitemapLoc(entry.Loc, sitemapURL)
+			if !ok {
+				continue
+			}
+			nested, err := url.Parse(nestedURL)
+			if err != nil || !strings.EqualFold(nested.Host, seedURL.Host) {
+				continue // The sitemap protocol only lets a host list sitemaps of its own
+			}
+			cr.TrackURLParent(nestedURL, sitemapURL.String())
+			walk(nested, depth+1)
+		}
+	}
+
+	for _, path := range cr.config.Sitemap.Paths {
+		sitemapURL, err := seedURL.Parse(path)
+		if err != nil {
+			continue
+		}
+		cr.TrackURLParent(sitemapURL.String(), seed)
+		walk(sitemapURL, 0)
+	}
+
+	if queued > 0 {
+		cr.logger.Info().Str("host", seedURL.Host).Int("urls", queued).Msg("Seeded crawl from sitemaps")
+	}
+}
+
+// fetchSitemap downloads and parses a sitemap, decompressing it when gzipped
+func (cr *Crawler) fetchSitemap(sitemapURL *url.URL) (*sitemapDocument, error) {
+	ctx := cr.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL.String(), nil)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to build sitemap request")
+	}
+	if userAgent := cr.userAgents.Next(); userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	cr.applyAuthHeaders(&req.Header)
+	cr.applyTargetCredentials(sitemapURL, &req.Header)
+
+	client := &http.Client{Transport: cr.transport, Timeout: cr.requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "sitemap request failed")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sitemap returned status %d", resp.StatusCode)
+	}
+
+	return parseSitemap(resp.Body)
+}
+
+// parseSitemap decodes a <urlset> or <sitemapindex>, gunzipping the body first when it starts with the gzip magic
+func parseSitemap(body io.Reader) (*sitemapDocument, error) {
+	reader := bufio.NewReader(body)
+	var content io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errorwrapper.WrapError(err, "invalid gzipped sitemap")
+		}
+		defer func() { _ = gz.Close() }()
+		content = gz
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(io.LimitReader(content, maxSitemapBytes)).Decode(&doc); err != nil {
+		return nil, errorwrapper.WrapError(err, "invalid sitemap XML")
+	}
+	return &doc, nil
+}
+
+// resolveSitemapLoc returns the absolute HTTP(S) URL of a <loc> value
+func resolveSitemapLoc(loc string, base *url.URL) (string, bool) {
+	loc = strings.TrimSpace(loc)
+	if loc == "" {
+		return "", false
+	}
+	resolved, err := base.Parse(loc)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return "", false
+	}
+	return resolved.String(), true
+}
@@ -0,0 +1,104 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSitemapTestServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>` + server.URL + `/sitemaps/pages.xml.gz</loc></sitemap>
+  <sitemap><loc>https://elsewhere.example/sitemap.xml</loc></sitemap>
+</sitemapindex>`))
+	})
+	mux.HandleFunc("/sitemaps/pages.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, _ = gz.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>` + server.URL + `/articles/one</loc></url>
+  <url><loc>` + server.URL + `/articles/two</loc></url>
+  <url><loc>https://out-of-scope.example/page</loc></url>
+</urlset>`))
+		_ = gz.Close()
+		w.Header().Set("Content-Type", "application/gzip")
+		_, _ = w.Write(buf.Bytes())
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body>no links</body></html>`))
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func runSitemapTestCrawl(t *testing.T, server *httptest.Server, sitemap config.CrawlerSitemapConfig) *Crawler {
+	cfg := config.NewDefaultCrawlerConfig()
+	cfg.SeedURLs = []string{server.URL + "/"}
+	cfg.RetryConfig.MaxRetries = 0
+	cfg.AutoCalibrate.Enabled = false
+	cfg.Sitemap = sitemap
+
+	cr, err := NewCrawler(&cfg, zerolog.Nop())
+	require.NoError(t, err)
+	t.Cleanup(cr.Stop)
+
+	cr.RunBatch(context.Background(), cfg.SeedURLs)
+	return cr
+}
+
+func TestCrawler_SeedsFromSitemaps(t *testing.T) {
+	server := newSitemapTestServer(t)
+	sitemap := config.NewDefaultCrawlerSitemapConfig()
+	sitemap.Enabled = true
+
+	cr := runSitemapTestCrawl(t, server, sitemap)
+	discovered := cr.GetDiscoveredURLs()
+	assert.Contains(t, discovered, server.URL+"/articles/one")
+	assert.Contains(t, discovered, server.URL+"/articles/two")
+	assert.NotContains(t, discovered, "https://out-of-scope.example/page")
+	assert.Equal(t, server.URL+"/sitemaps/pages.xml.gz", cr.GetURLParent(server.URL+"/articles/one"))
+	assert.Equal(t, server.URL+"/", cr.GetRootTargetForDiscoveredURL(server.URL+"/articles/one"))
+}
+
+func TestCrawler_SitemapURLLimitPerHost(t *testing.T) {
+	server := newSitemapTestServer(t)
+	sitemap := config.NewDefaultCrawlerSitemapConfig()
+	sitemap.Enabled = true
+	sitemap.MaxURLsPerHost = 1
+
+	discovered := runSitemapTestCrawl(t, server, sitemap).GetDiscoveredURLs()
+	assert.Contains(t, discovered, server.URL+"/articles/one")
+	assert.NotContains(t, discovered, server.URL+"/articles/two")
+}
+
+func TestCrawler_SitemapsDisabledByDefault(t *testing.T) {
+	server := newSitemapTestServer(t)
+
+	discovered := runSitemapTestCrawl(t, server, config.NewDefaultCrawlerSitemapConfig()).GetDiscoveredURLs()
+	assert.NotContains(t, discovered, server.URL+"/articles/one")
+}
+
+func TestParseSitemap_PlainURLSet(t *testing.T) {
+	doc, err := parseSitemap(strings.NewReader(`<urlset><url><loc> https://example.com/a </loc></url></urlset>`))
+	require.NoError(t, err)
+	require.Len(t, doc.URLs, 1)
+	assert.Empty(t, doc.Sitemaps)
+
+	_, err = parseSitemap(strings.NewReader("not xml"))
+	assert.Error(t, err)
+}
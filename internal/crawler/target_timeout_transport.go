@@ -0,0 +1,62 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+)
+
+// TargetTimeoutTransport applies the request timeout of the target each request belongs to, or the
+// default timeout for requests outside any target with a "|timeout=" annotation. It sits above the retry
+// transport, so like the collector's client timeout it bounds a request together with its retries. The
+// collector's own timeout is raised to the longest target timeout so it never cuts an override short.
+type TargetTimeoutTransport struct {
+	base           http.RoundTripper
+	defaultTimeout time.Duration
+	timeouts       urlhandler.TargetTimeouts
+}
+
+// NewTargetTimeoutTransport creates a transport bounding requests by their target's timeout
+func NewTargetTimeoutTransport(base http.RoundTripper, defaultTimeout time.Duration, timeouts urlhandler.TargetTimeouts) *TargetTimeoutTransport {
+	return &TargetTimeoutTransport{
+		base:           base,
+		defaultTimeout: defaultTimeout,
+		timeouts:       timeouts,
+	}
+}
+
+// RoundTrip sends the request with its timeout; the deadline also covers reading the response body
+func (tt *TargetTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout, ok := tt.timeouts.ForURL(req.URL)
+	if !ok {
+		timeout = tt.defaultTimeout
+	}
+	if timeout <= 0 {
+		return tt.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := tt.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody releases the request's timeout once the response body is closed
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,48 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadlineRoundTripper records the deadline of each request it receives
+type deadlineRoundTripper struct {
+	deadlines map[string]time.Duration
+	ctx       context.Context
+}
+
+func (rt *deadlineRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if deadline, ok := req.Context().Deadline(); ok {
+		rt.deadlines[req.URL.Host] = time.Until(deadline).Round(time.Second)
+	}
+	rt.ctx = req.Context()
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Request: req}, nil
+}
+
+func TestTargetTimeoutTransport_AppliesTargetTimeout(t *testing.T) {
+	base := &deadlineRoundTripper{deadlines: make(map[string]time.Duration)}
+	timeouts := urlhandler.NewTargetTimeouts([]urlhandler.Target{{URL: "https://slow.example.com", Timeout: time.Minute}})
+	tt := NewTargetTimeoutTransport(base, 5*time.Second, timeouts)
+
+	for _, rawURL := range []string{"https://slow.example.com/report", "https://fast.example.com/"} {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		require.NoError(t, err)
+		resp, err := tt.RoundTrip(req)
+		require.NoError(t, err)
+
+		require.NoError(t, base.ctx.Err(), "the deadline stays active while the body is read")
+		require.NoError(t, resp.Body.Close())
+		assert.ErrorIs(t, base.ctx.Err(), context.Canceled, "closing the body releases the deadline")
+	}
+
+	assert.Equal(t, time.Minute, base.deadlines["slow.example.com"])
+	assert.Equal(t, 5*time.Second, base.deadlines["fast.example.com"])
+}
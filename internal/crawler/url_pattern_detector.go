@@ -587,3 +587,40 @@ func (upd *URLPatternDetector) Reset() {
 
 	upd.logger.Debug().Msg("URL pattern detector reset")
 }
+
+// URLPatternDetectorState is a serializable snapshot of the detector's skip decisions
+type URLPatternDetectorState struct {
+	PatternCounts map[string]int `json:"pattern_counts,omitempty"`
+	SeenURLs      []string       `json:"seen_urls,omitempty"`
+}
+
+// ExportState returns a copy of the current pattern tracking data
+func (upd *URLPatternDetector) ExportState() URLPatternDetectorState {
+	state := URLPatternDetectorState{PatternCounts: upd.GetPatternStats()}
+
+	upd.urlMutex.RLock()
+	state.SeenURLs = make([]string, 0, len(upd.seenURLs))
+	for seenURL := range upd.seenURLs {
+		state.SeenURLs = append(state.SeenURLs, seenURL)
+	}
+	upd.urlMutex.RUnlock()
+
+	return state
+}
+
+// RestoreState replaces pattern tracking data with a previously exported snapshot
+func (upd *URLPatternDetector) RestoreState(state URLPatternDetectorState) {
+	upd.patternMutex.Lock()
+	upd.patternCounts = make(map[string]int, len(state.PatternCounts))
+	for pattern, count := range state.PatternCounts {
+		upd.patternCounts[pattern] = count
+	}
+	upd.patternMutex.Unlock()
+
+	upd.urlMutex.Lock()
+	upd.seenURLs = make(map[string]bool, len(state.SeenURLs))
+	for _, seenURL := range state.SeenURLs {
+		upd.seenURLs[seenURL] = true
+	}
+	upd.urlMutex.Unlock()
+}
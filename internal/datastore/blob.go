@@ -0,0 +1,82 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// scanBlobDir holds one Parquet file per scanned target
+const scanBlobDir = "scan"
+
+// ErrBlobNotFound is returned by Blob operations on a key that does not exist
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Blob stores the Parquet files of ParquetReader and ParquetWriter. Keys are slash-separated paths
+// relative to StorageConfig.ParquetBasePath, such as "scan/example.com.parquet".
+type Blob interface {
+	// Open returns the object stored under key for random access, or ErrBlobNotFound
+	Open(ctx context.Context, key string) (BlobObject, error)
+	// Write stores what write produces under key. The previous object stays in place until write
+	// succeeds, so readers never see a partially written file.
+	Write(ctx context.Context, key string, write func(io.Writer) error) (BlobInfo, error)
+	// Stat returns the size and modification time of key, or ErrBlobNotFound
+	Stat(ctx context.Context, key string) (BlobInfo, error)
+	// List returns the objects directly under the prefix "dir/", sorted by key
+	List(ctx context.Context, dir string) ([]BlobInfo, error)
+	// Rename moves an object to a new key, replacing any object already there
+	Rename(ctx context.Context, from, to string) error
+	// Delete removes the object stored under key, or returns ErrBlobNotFound
+	Delete(ctx context.Context, key string) error
+	// Location describes where key is stored, for logs and results
+	Location(key string) string
+}
+
+// BlobObject is an opened object. Size lets parquet-go open it without seeking.
+type BlobObject interface {
+	io.ReaderAt
+	io.Closer
+	Size() int64
+}
+
+// BlobInfo describes a stored object
+type BlobInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// NewBlob creates the backend selected by cfg.Backend. {date} and {time} in ParquetBasePath are expanded
+// here for callers that pass the configured value as is; the CLI expands them once at startup instead.
+func NewBlob(cfg *config.StorageConfig, logger zerolog.Logger) (Blob, error) {
+	if cfg == nil {
+		return nil, errorwrapper.NewValidationError("config", cfg, "storage config cannot be nil")
+	}
+
+	basePath := config.ExpandPathTemplate(cfg.ParquetBasePath, "", time.Now())
+	switch strings.ToLower(cfg.Backend) {
+	case "", config.StorageBackendLocal:
+		return NewLocalBlob(basePath), nil
+	case config.StorageBackendS3:
+		return NewS3Blob(cfg.S3, basePath, logger)
+	default:
+		return nil, errorwrapper.NewValidationError("storage_config.backend", cfg.Backend, "unsupported storage backend (use local or s3)")
+	}
+}
+
+// blobKey joins key segments with slashes
+func blobKey(segments ...string) string {
+	return path.Join(segments...)
+}
+
+// blobBaseName returns the last segment of key
+func blobBaseName(key string) string {
+	return path.Base(key)
+}
@@ -0,0 +1,154 @@
+package datastore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+)
+
+// LocalBlob keeps objects as files under a base directory
+type LocalBlob struct {
+	baseDir string
+}
+
+// NewLocalBlob creates a backend rooted at baseDir; directories are created on first write
+func NewLocalBlob(baseDir string) *LocalBlob {
+	return &LocalBlob{baseDir: baseDir}
+}
+
+// localBlobObject adds Size to an open file
+type localBlobObject struct {
+	*os.File
+	size int64
+}
+
+func (o *localBlobObject) Size() int64 {
+	return o.size
+}
+
+// Open opens the file of key
+func (lb *LocalBlob) Open(_ context.Context, key string) (BlobObject, error) {
+	filePath := lb.Location(key)
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to open file: "+filePath)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, errorwrapper.WrapError(err, "failed to get file stats for: "+filePath)
+	}
+	return &localBlobObject{File: file, size: stat.Size()}, nil
+}
+
+// Write writes to a temporary file next to the target and renames it into place, so a crash mid-write
+// leaves the previous file intact. The temporary name does not keep the extension, so listings skip it.
+func (lb *LocalBlob) Write(_ context.Context, key string, write func(io.Writer) error) (BlobInfo, error) {
+	filePath := lb.Location(key)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return BlobInfo{}, errorwrapper.WrapError(err, "failed to create directory: "+filepath.Dir(filePath))
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return BlobInfo{}, errorwrapper.WrapError(err, "failed to create temporary file for: "+filePath)
+	}
+	tempPath := file.Name()
+	_ = file.Chmod(0644)
+
+	err = write(file)
+	if err == nil {
+		if syncErr := file.Sync(); syncErr != nil {
+			err = errorwrapper.WrapError(syncErr, "failed to sync file: "+tempPath)
+		}
+	}
+	if closeErr := file.Close(); closeErr != nil && err == nil {
+		err = errorwrapper.WrapError(closeErr, "failed to close file: "+tempPath)
+	}
+	if err == nil {
+		if renameErr := os.Rename(tempPath, filePath); renameErr != nil {
+			err = errorwrapper.WrapError(renameErr, "failed to move file into place: "+filePath)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return BlobInfo{}, err
+	}
+
+	return lb.Stat(context.Background(), key)
+}
+
+// Stat returns the size and modification time of key's file
+func (lb *LocalBlob) Stat(_ context.Context, key string) (BlobInfo, error) {
+	filePath := lb.Location(key)
+	stat, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return BlobInfo{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return BlobInfo{}, errorwrapper.WrapError(err, "failed to stat file: "+filePath)
+	}
+	return BlobInfo{Key: key, Size: stat.Size(), ModTime: stat.ModTime()}, nil
+}
+
+// List returns the regular files in dir; a missing directory holds none
+func (lb *LocalBlob) List(_ context.Context, dir string) ([]BlobInfo, error) {
+	dirPath := lb.Location(dir)
+	entries, err := os.ReadDir(dirPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to list directory: "+dirPath)
+	}
+
+	var infos []BlobInfo
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed while listing
+		}
+		infos = append(infos, BlobInfo{Key: blobKey(dir, entry.Name()), Size: info.Size(), ModTime: info.ModTime()})
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+	return infos, nil
+}
+
+// Rename renames key's file
+func (lb *LocalBlob) Rename(_ context.Context, from, to string) error {
+	if err := os.Rename(lb.Location(from), lb.Location(to)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrBlobNotFound
+		}
+		return errorwrapper.WrapError(err, "failed to rename file: "+lb.Location(from))
+	}
+	return nil
+}
+
+// Delete removes key's file
+func (lb *LocalBlob) Delete(_ context.Context, key string) error {
+	if err := os.Remove(lb.Location(key)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrBlobNotFound
+		}
+		return errorwrapper.WrapError(err, "failed to delete file: "+lb.Location(key))
+	}
+	return nil
+}
+
+// Location returns the file path of key
+func (lb *LocalBlob) Location(key string) string {
+	return filepath.Join(lb.baseDir, filepath.FromSlash(key))
+}
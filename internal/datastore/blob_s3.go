@@ -0,0 +1,321 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// s3RequestTimeout bounds a single S3 request, including the transfer of a whole Parquet file
+const s3RequestTimeout = 5 * time.Minute
+
+// S3Blob keeps objects in a bucket of an S3-compatible store, under the prefix given by ParquetBasePath.
+// Objects are transferred whole: parquet-go needs random access, so files are downloaded into memory
+// before decoding and encoded into memory before upload. A PUT replaces an object atomically.
+type S3Blob struct {
+	client    *httpclient.HTTPClient
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	prefix    string
+	pathStyle bool
+	creds     s3Credentials
+	logger    zerolog.Logger
+}
+
+// NewS3Blob creates a backend for cfg.Bucket, reached through cfg.Proxy. Objects are stored under basePath inside the bucket.
+func NewS3Blob(cfg config.S3StorageConfig, basePath string, logger zerolog.Logger) (*S3Blob, error) {
+	if strings.TrimSpace(cfg.Bucket) == "" {
+		return nil, errorwrapper.NewValidationError("storage_config.s3.bucket", cfg.Bucket, "bucket is required for the s3 backend")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = config.DefaultStorageS3Region
+	}
+
+	rawEndpoint := cfg.Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(rawEndpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, errorwrapper.NewValidationError("storage_config.s3.endpoint", cfg.Endpoint, "endpoint must be an absolute URL")
+	}
+
+	creds := s3Credentials{
+		accessKeyID:     firstNonEmpty(cfg.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID")),
+		secretAccessKey: firstNonEmpty(cfg.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY")),
+		sessionToken:    firstNonEmpty(cfg.SessionToken, os.Getenv("AWS_SESSION_TOKEN")),
+	}
+
+	blobLogger := logger.With().Str("component", "S3Blob").Str("bucket", cfg.Bucket).Logger()
+	if creds.accessKeyID == "" || creds.secretAccessKey == "" {
+		blobLogger.Warn().Msg("No S3 credentials configured, requests are sent unsigned")
+	}
+
+	client, err := httpclient.NewHTTPClientFactory(blobLogger).
+		WithProxyConfig(cfg.Proxy).
+		CreateBasicClient(s3RequestTimeout)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to create S3 HTTP client")
+	}
+
+	return &S3Blob{
+		client:    client,
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.Bucket,
+		prefix:    s3KeyPrefix(basePath),
+		pathStyle: cfg.PathStyle,
+		creds:     creds,
+		logger:    blobLogger,
+	}, nil
+}
+
+// s3KeyPrefix turns a local-style base path into an object key prefix ("./database" becomes "database")
+func s3KeyPrefix(basePath string) string {
+	prefix := strings.Trim(path.Clean(filepath.ToSlash(basePath)), "/")
+	if prefix == "." {
+		return ""
+	}
+	return prefix
+}
+
+// s3BlobObject is a downloaded object
+type s3BlobObject struct {
+	*bytes.Reader
+}
+
+func (o s3BlobObject) Close() error {
+	return nil
+}
+
+// Open downloads the object of key
+func (sb *S3Blob) Open(ctx context.Context, key string) (BlobObject, error) {
+	resp, err := sb.do(ctx, http.MethodGet, sb.objectURL(key, nil), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s3BlobObject{Reader: bytes.NewReader(resp.Body)}, nil
+}
+
+// Write encodes the object in memory and uploads it with a single PUT
+func (sb *S3Blob) Write(ctx context.Context, key string, write func(io.Writer) error) (BlobInfo, error) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		return BlobInfo{}, err
+	}
+
+	if _, err := sb.do(ctx, http.MethodPut, sb.objectURL(key, nil), buf.Bytes(), nil); err != nil {
+		return BlobInfo{}, err
+	}
+	return BlobInfo{Key: key, Size: int64(buf.Len()), ModTime: time.Now()}, nil
+}
+
+// Stat issues a HEAD request for key
+func (sb *S3Blob) Stat(ctx context.Context, key string) (BlobInfo, error) {
+	resp, err := sb.do(ctx, http.MethodHead, sb.objectURL(key, nil), nil, nil)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+
+	size, _ := strconv.ParseInt(resp.Headers["Content-Length"], 10, 64)
+	modTime, _ := http.ParseTime(resp.Headers["Last-Modified"])
+	return BlobInfo{Key: key, Size: size, ModTime: modTime}, nil
+}
+
+// s3ListResult is the part of a ListObjectsV2 response used by List
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List pages through ListObjectsV2 for the objects directly under dir
+func (sb *S3Blob) List(ctx context.Context, dir string) ([]BlobInfo, error) {
+	listPrefix := sb.objectKey(dir) + "/"
+	if listPrefix == "/" {
+		listPrefix = ""
+	}
+
+	var infos []BlobInfo
+	continuationToken := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {listPrefix}, "delimiter": {"/"}}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		resp, err := sb.do(ctx, http.MethodGet, sb.bucketURL(query), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		if err := xml.Unmarshal(resp.Body, &result); err != nil {
+			return nil, errorwrapper.WrapError(err, "failed to decode object listing of: "+sb.Location(dir))
+		}
+
+		for _, object := range result.Contents {
+			infos = append(infos, BlobInfo{
+				Key:     blobKey(dir, strings.TrimPrefix(object.Key, listPrefix)),
+				Size:    object.Size,
+				ModTime: object.LastModified,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return infos, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// Rename copies the object to its new key and deletes the original; S3 has no native rename
+func (sb *S3Blob) Rename(ctx context.Context, from, to string) error {
+	copySource := "/" + sb.bucket + "/" + s3Escape(sb.objectKey(from), false)
+	resp, err := sb.do(ctx, http.MethodPut, sb.objectURL(to, nil), nil, map[string]string{"X-Amz-Copy-Source": copySource})
+	if err != nil {
+		return err
+	}
+	// A copy can fail after the 200 status has been sent; the error is then in the body
+	if bytes.Contains(resp.Body, []byte("<Error>")) {
+		return sb.responseError(http.MethodPut, sb.Location(to), resp.StatusCode, resp.Body)
+	}
+
+	_, err = sb.do(ctx, http.MethodDelete, sb.objectURL(from, nil), nil, nil)
+	return err
+}
+
+// Delete deletes the object. S3 answers 204 whether or not the key existed; ErrBlobNotFound only comes from stores answering 404.
+func (sb *S3Blob) Delete(ctx context.Context, key string) error {
+	_, err := sb.do(ctx, http.MethodDelete, sb.objectURL(key, nil), nil, nil)
+	return err
+}
+
+// Location returns the s3:// URI of key
+func (sb *S3Blob) Location(key string) string {
+	return "s3://" + sb.bucket + "/" + sb.objectKey(key)
+}
+
+// objectKey prepends the base path prefix to key
+func (sb *S3Blob) objectKey(key string) string {
+	return strings.TrimPrefix(path.Join(sb.prefix, key), "/")
+}
+
+// bucketURL addresses the bucket itself, virtual-hosted or path-style
+func (sb *S3Blob) bucketURL(query url.Values) *url.URL {
+	u := *sb.endpoint
+	if sb.pathStyle {
+		u.Path = u.Path + "/" + sb.bucket + "/"
+	} else {
+		u.Host = sb.bucket + "." + u.Host
+		u.Path = u.Path + "/"
+	}
+	u.RawPath = ""
+	u.RawQuery = s3CanonicalQuery(query)
+	return &u
+}
+
+// objectURL addresses the object of key
+func (sb *S3Blob) objectURL(key string, query url.Values) *url.URL {
+	u := sb.bucketURL(query)
+	u.Path += sb.objectKey(key)
+	u.RawPath = s3EscapePath(u.Path)
+	return u
+}
+
+// do sends a signed request and reads the whole response. Missing objects map to ErrBlobNotFound,
+// other non-2xx responses to errors.
+func (sb *S3Blob) do(ctx context.Context, method string, u *url.URL, body []byte, headers map[string]string) (*httpclient.HTTPResponse, error) {
+	// The signature covers the headers of this request, which are then sent as they are
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to build S3 request")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if sb.creds.accessKeyID != "" && sb.creds.secretAccessKey != "" {
+		payloadHash := s3EmptyPayloadHash
+		if len(body) > 0 {
+			payloadHash = sha256Hex(body)
+		}
+		signS3Request(req, payloadHash, sb.creds, sb.region, time.Now())
+	}
+
+	signedHeaders := make(map[string]string, len(req.Header))
+	for name := range req.Header {
+		signedHeaders[name] = req.Header.Get(name)
+	}
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	resp, err := sb.client.Do(&httpclient.HTTPRequest{
+		URL:     u.String(),
+		Method:  method,
+		Headers: signedHeaders,
+		Body:    bodyReader,
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, fmt.Sprintf("S3 %s request failed", method))
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	if resp.StatusCode == http.StatusNotFound && !bytes.Contains(resp.Body, []byte("NoSuchBucket")) {
+		return nil, ErrBlobNotFound
+	}
+	return nil, sb.responseError(method, u.Redacted(), resp.StatusCode, resp.Body)
+}
+
+// responseError describes a failed S3 request by its status and the error code of the response body
+func (sb *S3Blob) responseError(method, target string, statusCode int, body []byte) error {
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	_ = xml.Unmarshal(body, &s3Err)
+
+	message := fmt.Sprintf("S3 %s %s returned status %d", method, target, statusCode)
+	if s3Err.Code != "" {
+		message += ": " + s3Err.Code
+		if s3Err.Message != "" {
+			message += " (" + s3Err.Message + ")"
+		}
+	}
+	return errorwrapper.NewError("%s", message)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
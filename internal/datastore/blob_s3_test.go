@@ -0,0 +1,280 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a path-style S3 endpoint for bucket "bucket" keeping objects in memory.
+// Listings return at most pageSize objects per page.
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	pageSize int
+	requests []string
+
+	failCopy   bool // answer copies with 200 and an <Error> body, as S3 does for late copy failures
+	failDelete bool
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *S3Blob) {
+	t.Helper()
+	fake := &fakeS3{objects: map[string][]byte{}, pageSize: 1000}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	blob, err := NewS3Blob(config.S3StorageConfig{
+		Endpoint:        server.URL,
+		Bucket:          "bucket",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	}, "./database", zerolog.Nop())
+	require.NoError(t, err)
+	return fake, blob
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/bucket/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "<Error><Code>NoSuchBucket</Code></Error>")
+		return
+	}
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		f.list(w, r)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		if f.failCopy {
+			_, _ = io.WriteString(w, "<Error><Code>InternalError</Code><Message>copy failed</Message></Error>")
+			return
+		}
+		source := strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/bucket/")
+		data, exists := f.objects[source]
+		if !exists {
+			f.notFound(w)
+			return
+		}
+		f.objects[key] = data
+		_, _ = io.WriteString(w, "<CopyObjectResult></CopyObjectResult>")
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, exists := f.objects[key]
+		if !exists {
+			f.notFound(w)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 12:00:00 GMT")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case r.Method == http.MethodDelete:
+		if f.failDelete {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, "<Error><Code>AccessDenied</Code><Message>no delete</Message></Error>")
+			return
+		}
+		if _, exists := f.objects[key]; !exists {
+			f.notFound(w)
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeS3) notFound(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+}
+
+// list answers ListObjectsV2 for the direct children of prefix, paging with the key to continue after
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+
+	var keys []string
+	for key := range f.objects {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if ok && !strings.Contains(rest, "/") && key > query.Get("continuation-token") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	truncated := len(keys) > f.pageSize
+	if truncated {
+		keys = keys[:f.pageSize]
+	}
+
+	var body strings.Builder
+	body.WriteString("<ListBucketResult>")
+	for _, key := range keys {
+		fmt.Fprintf(&body, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>2025-01-01T12:00:00.000Z</LastModified></Contents>", key, len(f.objects[key]))
+	}
+	if truncated {
+		fmt.Fprintf(&body, "<IsTruncated>true</IsTruncated><NextContinuationToken>%s</NextContinuationToken>", keys[len(keys)-1])
+	}
+	body.WriteString("</ListBucketResult>")
+	_, _ = io.WriteString(w, body.String())
+}
+
+func writeBlob(t *testing.T, blob Blob, key, content string) {
+	t.Helper()
+	_, err := blob.Write(context.Background(), key, func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	})
+	require.NoError(t, err)
+}
+
+func TestS3Blob_WriteOpenStat(t *testing.T) {
+	fake, blob := newFakeS3(t)
+	ctx := context.Background()
+
+	writeBlob(t, blob, "scan/example.com.parquet", "parquet bytes")
+	assert.Equal(t, []byte("parquet bytes"), fake.objects["database/scan/example.com.parquet"], "keys live under the base path")
+
+	object, err := blob.Open(ctx, "scan/example.com.parquet")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("parquet bytes")), object.Size())
+	require.NoError(t, object.Close())
+
+	info, err := blob.Stat(ctx, "scan/example.com.parquet")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("parquet bytes")), info.Size)
+	assert.Equal(t, 2025, info.ModTime.Year())
+}
+
+func TestS3Blob_MissingObjectIsErrBlobNotFound(t *testing.T) {
+	_, blob := newFakeS3(t)
+	ctx := context.Background()
+
+	_, err := blob.Open(ctx, "scan/missing.parquet")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+	_, err = blob.Stat(ctx, "scan/missing.parquet")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+	assert.ErrorIs(t, blob.Delete(ctx, "scan/missing.parquet"), ErrBlobNotFound)
+
+	// A missing bucket is a configuration error, not a missing object
+	otherBucket := *blob
+	otherBucket.bucket = "other"
+	_, err = otherBucket.Open(ctx, "scan/missing.parquet")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrBlobNotFound)
+	assert.Contains(t, err.Error(), "NoSuchBucket")
+}
+
+func TestS3Blob_ListFollowsContinuationTokens(t *testing.T) {
+	fake, blob := newFakeS3(t)
+	fake.pageSize = 2
+
+	for _, host := range []string{"a.com", "b.com", "c.com", "d.com", "e.com"} {
+		writeBlob(t, blob, "scan/"+host+".parquet", host)
+	}
+	writeBlob(t, blob, "scan/archive/old.parquet", "nested objects are not listed")
+
+	infos, err := blob.List(context.Background(), "scan")
+	require.NoError(t, err)
+
+	var keys []string
+	for _, info := range infos {
+		keys = append(keys, info.Key)
+	}
+	assert.Equal(t, []string{"scan/a.com.parquet", "scan/b.com.parquet", "scan/c.com.parquet", "scan/d.com.parquet", "scan/e.com.parquet"}, keys)
+	assert.Equal(t, int64(len("a.com")), infos[0].Size)
+
+	var listings int
+	for _, request := range fake.requests {
+		if strings.Contains(request, "list-type=2") {
+			listings++
+		}
+	}
+	assert.Equal(t, 3, listings, "five objects in pages of two")
+}
+
+func TestS3Blob_Rename(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("copies then deletes the original", func(t *testing.T) {
+		fake, blob := newFakeS3(t)
+		writeBlob(t, blob, "scan/example.com.parquet", "data")
+
+		require.NoError(t, blob.Rename(ctx, "scan/example.com.parquet", "scan/example.com.parquet.corrupt"))
+		assert.Equal(t, []byte("data"), fake.objects["database/scan/example.com.parquet.corrupt"])
+		assert.NotContains(t, fake.objects, "database/scan/example.com.parquet")
+	})
+
+	t.Run("a failed copy keeps the original", func(t *testing.T) {
+		fake, blob := newFakeS3(t)
+		writeBlob(t, blob, "scan/example.com.parquet", "data")
+		fake.failCopy = true
+
+		err := blob.Rename(ctx, "scan/example.com.parquet", "scan/example.com.parquet.corrupt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "InternalError (copy failed)")
+		assert.Contains(t, fake.objects, "database/scan/example.com.parquet")
+		for _, request := range fake.requests {
+			assert.NotContains(t, request, "DELETE", "nothing is deleted after a failed copy")
+		}
+	})
+
+	t.Run("a failed delete is reported", func(t *testing.T) {
+		fake, blob := newFakeS3(t)
+		writeBlob(t, blob, "scan/example.com.parquet", "data")
+		fake.failDelete = true
+
+		err := blob.Rename(ctx, "scan/example.com.parquet", "scan/example.com.parquet.corrupt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 403: AccessDenied")
+	})
+
+	t.Run("a missing source is ErrBlobNotFound", func(t *testing.T) {
+		_, blob := newFakeS3(t)
+		assert.ErrorIs(t, blob.Rename(ctx, "scan/missing.parquet", "scan/elsewhere.parquet"), ErrBlobNotFound)
+	})
+}
+
+func TestS3Blob_UsesConfiguredProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.Host
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	blob, err := NewS3Blob(config.S3StorageConfig{
+		Endpoint: "http://s3.storage.invalid",
+		Bucket:   "bucket",
+		Proxy:    httpclient.ProxyConfig{URL: proxy.URL, IgnoreEnvironment: true},
+	}, "", zerolog.Nop())
+	require.NoError(t, err)
+
+	require.NoError(t, blob.Delete(context.Background(), "scan/example.com.parquet"))
+	assert.Equal(t, "bucket.s3.storage.invalid", proxiedHost)
+}
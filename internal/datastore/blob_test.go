@@ -0,0 +1,25 @@
+package datastore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlob_ExpandsBasePathTemplate(t *testing.T) {
+	baseDir := t.TempDir()
+	storageConfig := config.NewDefaultStorageConfig()
+	storageConfig.ParquetBasePath = filepath.Join(baseDir, "history-{date}")
+
+	blob, err := NewBlob(&storageConfig, zerolog.Nop())
+	require.NoError(t, err)
+	writeBlob(t, blob, "scan/example.com.parquet", "data")
+
+	assert.FileExists(t, filepath.Join(baseDir, "history-"+time.Now().Format("2006-01-02"), "scan", "example.com.parquet"))
+	assert.NoDirExists(t, filepath.Join(baseDir, "history-{date}"))
+}
@@ -0,0 +1,221 @@
+package datastore
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+)
+
+// contentArchiveURLFile names the file in each URL directory holding the URL its snapshots belong to
+const contentArchiveURLFile = "url.txt"
+
+// ContentArchiver writes the full bodies of URLs whose content changed to storage_config.response_bodies.archive.dir,
+// as <host>/<url hash>/<scan time>.<ext>. The layout is plain files so analysts and other tools can read the
+// exact bytes without going through Parquet.
+type ContentArchiver struct {
+	blob   *LocalBlob
+	dir    string
+	format string
+	logger zerolog.Logger
+}
+
+// NewContentArchiver creates an archiver, or returns nil when archiving is disabled
+func NewContentArchiver(cfg config.ContentArchiveConfig, logger zerolog.Logger) *ContentArchiver {
+	if !cfg.Enabled || strings.TrimSpace(cfg.Dir) == "" {
+		return nil
+	}
+	return &ContentArchiver{
+		blob:   NewLocalBlob(cfg.Dir),
+		dir:    cfg.Dir,
+		format: cfg.Format,
+		logger: logger.With().Str("module", "ContentArchiver").Logger(),
+	}
+}
+
+// Archive writes a snapshot of every probe with BodyChanged set and returns how many were written.
+// A failing snapshot does not stop the others; their errors are joined.
+func (ca *ContentArchiver) Archive(ctx context.Context, hostname string, probes []httpxrunner.ProbeResult, scanTime time.Time) (int, error) {
+	hostDir := urlhandler.SanitizeFilename(hostname)
+	if hostDir == "" {
+		return 0, errorwrapper.NewValidationError("hostname", hostname, "sanitized hostname is empty, cannot archive content")
+	}
+
+	archived := 0
+	var errs []error
+	for _, probe := range probes {
+		if !probe.BodyChanged || probe.Body == "" {
+			continue
+		}
+		if err := ca.archiveProbe(ctx, hostDir, probe, scanTime); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		archived++
+	}
+
+	if archived > 0 {
+		ca.logger.Info().Str("hostname", hostname).Int("snapshots", archived).Str("dir", ca.dir).Msg("Archived changed content")
+	}
+	return archived, errors.Join(errs...)
+}
+
+// archiveProbe writes one snapshot, and the URL file on the URL's first snapshot
+func (ca *ContentArchiver) archiveProbe(ctx context.Context, hostDir string, probe httpxrunner.ProbeResult, scanTime time.Time) error {
+	targetURL := probe.GetEffectiveURL()
+	hash := sha256.Sum256([]byte(targetURL))
+	urlDir := blobKey(hostDir, hex.EncodeToString(hash[:8]))
+
+	urlKey := blobKey(urlDir, contentArchiveURLFile)
+	if _, err := ca.blob.Stat(ctx, urlKey); errors.Is(err, ErrBlobNotFound) {
+		if _, err := ca.blob.Write(ctx, urlKey, func(w io.Writer) error {
+			_, err := io.WriteString(w, targetURL+"\n")
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	name := scanTime.UTC().Format("20060102T150405Z") + contentArchiveExtension(probe.ContentType)
+	if ca.format == config.ContentArchiveFormatGzip {
+		name += ".gz"
+	}
+	_, err := ca.blob.Write(ctx, blobKey(urlDir, name), func(w io.Writer) error {
+		if ca.format != config.ContentArchiveFormatGzip {
+			_, err := io.WriteString(w, probe.Body)
+			return err
+		}
+		gz := gzip.NewWriter(w)
+		if _, err := io.WriteString(gz, probe.Body); err != nil {
+			return err
+		}
+		return gz.Close()
+	})
+	return err
+}
+
+// contentArchiveExtension picks a file extension from the response content type, so snapshots open in
+// the right tool
+func contentArchiveExtension(contentType string) string {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	switch mediaType = strings.TrimSpace(mediaType); {
+	case strings.Contains(mediaType, "html"):
+		return ".html"
+	case strings.Contains(mediaType, "javascript"):
+		return ".js"
+	case strings.Contains(mediaType, "json"):
+		return ".json"
+	case strings.Contains(mediaType, "xml"):
+		return ".xml"
+	case mediaType == "text/css":
+		return ".css"
+	case strings.HasPrefix(mediaType, "text/"):
+		return ".txt"
+	default:
+		return ".bin"
+	}
+}
+
+// archivedSnapshot is one snapshot file found by Cleanup
+type archivedSnapshot struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Cleanup applies the retention policy to the archive: snapshots older than maxAge are deleted first, then
+// each host's oldest snapshots while its snapshots exceed maxSize. Zero disables either limit. URL
+// directories left without snapshots are removed.
+func (ca *ContentArchiver) Cleanup(maxAge time.Duration, maxSize int64, now time.Time) (RetentionResult, error) {
+	snapshotsByHost := make(map[string][]archivedSnapshot)
+	err := filepath.WalkDir(ca.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil // Nothing archived yet
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() || entry.Name() == contentArchiveURLFile || strings.Contains(entry.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(ca.dir, path)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil // Removed while walking
+		}
+		host, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		snapshotsByHost[host] = append(snapshotsByHost[host], archivedSnapshot{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return RetentionResult{}, errorwrapper.WrapError(err, "failed to list content archive: "+ca.dir)
+	}
+
+	result := RetentionResult{Targets: len(snapshotsByHost)}
+	var errs []error
+	for _, snapshots := range snapshotsByHost {
+		sort.Slice(snapshots, func(i, j int) bool {
+			if !snapshots[i].modTime.Equal(snapshots[j].modTime) {
+				return snapshots[i].modTime.Before(snapshots[j].modTime)
+			}
+			return snapshots[i].path < snapshots[j].path // Names start with the scan time
+		})
+
+		var hostSize int64
+		for _, snapshot := range snapshots {
+			hostSize += snapshot.size
+		}
+		for _, snapshot := range snapshots {
+			expired := maxAge > 0 && now.Sub(snapshot.modTime) > maxAge
+			if !expired && (maxSize <= 0 || hostSize <= maxSize) {
+				continue
+			}
+			if err := ca.deleteSnapshot(snapshot); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			hostSize -= snapshot.size
+			result.DeletedFiles++
+			result.FreedBytes += snapshot.size
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// deleteSnapshot removes a snapshot, and its URL directory once no snapshot is left in it
+func (ca *ContentArchiver) deleteSnapshot(snapshot archivedSnapshot) error {
+	if err := os.Remove(snapshot.path); err != nil && !os.IsNotExist(err) {
+		return errorwrapper.WrapError(err, "failed to delete archived content "+snapshot.path)
+	}
+
+	urlDir := filepath.Dir(snapshot.path)
+	entries, err := os.ReadDir(urlDir)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		if entry.Name() != contentArchiveURLFile {
+			return nil
+		}
+	}
+	_ = os.RemoveAll(urlDir)
+	return nil
+}
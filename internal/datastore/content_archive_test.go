@@ -0,0 +1,152 @@
+package datastore
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var archiveScanTime = time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+
+func newTestContentArchiver(t *testing.T, format string) (*ContentArchiver, string) {
+	t.Helper()
+	dir := t.TempDir()
+	archiver := NewContentArchiver(config.ContentArchiveConfig{Enabled: true, Dir: dir, Format: format}, zerolog.Nop())
+	require.NotNil(t, archiver)
+	return archiver, dir
+}
+
+// archivedURLDir returns where the snapshots of targetURL on hostname are written
+func archivedURLDir(dir, hostname, targetURL string) string {
+	hash := sha256.Sum256([]byte(targetURL))
+	return filepath.Join(dir, urlhandler.SanitizeFilename(hostname), hex.EncodeToString(hash[:8]))
+}
+
+func TestNewContentArchiver_Disabled(t *testing.T) {
+	assert.Nil(t, NewContentArchiver(config.ContentArchiveConfig{Enabled: false, Dir: t.TempDir()}, zerolog.Nop()))
+	assert.Nil(t, NewContentArchiver(config.ContentArchiveConfig{Enabled: true, Dir: " "}, zerolog.Nop()))
+}
+
+func TestContentArchiver_ArchiveLayout(t *testing.T) {
+	archiver, dir := newTestContentArchiver(t, config.ContentArchiveFormatRaw)
+
+	probes := []httpxrunner.ProbeResult{
+		{InputURL: "https://example.com/app.js", BodyChanged: true, Body: "console.log(1)", ContentType: "application/javascript; charset=utf-8"},
+		{InputURL: "https://example.com/", FinalURL: "https://example.com/home", BodyChanged: true, Body: "<html></html>", ContentType: "text/html"},
+		{InputURL: "https://example.com/same", BodyChanged: false, Body: "unchanged"},
+		{InputURL: "https://example.com/empty", BodyChanged: true},
+	}
+	archived, err := archiver.Archive(context.Background(), "example.com", probes, archiveScanTime)
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived, "unchanged and empty bodies are skipped")
+
+	scriptDir := archivedURLDir(dir, "example.com", "https://example.com/app.js")
+	body, err := os.ReadFile(filepath.Join(scriptDir, "20250304T050607Z.js"))
+	require.NoError(t, err)
+	assert.Equal(t, "console.log(1)", string(body))
+	urlFile, err := os.ReadFile(filepath.Join(scriptDir, contentArchiveURLFile))
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/app.js\n", string(urlFile))
+
+	// Redirected URLs are archived under the URL they ended on
+	assert.FileExists(t, filepath.Join(archivedURLDir(dir, "example.com", "https://example.com/home"), "20250304T050607Z.html"))
+
+	// A later scan adds a snapshot next to the first one
+	probes[0].Body = "console.log(2)"
+	_, err = archiver.Archive(context.Background(), "example.com", probes[:1], archiveScanTime.Add(time.Hour))
+	require.NoError(t, err)
+	entries, err := os.ReadDir(scriptDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestContentArchiver_ArchiveGzip(t *testing.T) {
+	archiver, dir := newTestContentArchiver(t, config.ContentArchiveFormatGzip)
+
+	body := strings.Repeat("{\"key\": \"value\"}", 100)
+	probes := []httpxrunner.ProbeResult{{InputURL: "https://api.example.com/v1", BodyChanged: true, Body: body, ContentType: "application/json"}}
+	_, err := archiver.Archive(context.Background(), "api.example.com", probes, archiveScanTime)
+	require.NoError(t, err)
+
+	file, err := os.Open(filepath.Join(archivedURLDir(dir, "api.example.com", "https://api.example.com/v1"), "20250304T050607Z.json.gz"))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+// writeSnapshot places a snapshot of size bytes for targetURL, last modified at modTime
+func writeSnapshot(t *testing.T, dir, hostname, targetURL, name string, size int, modTime time.Time) string {
+	t.Helper()
+	urlDir := archivedURLDir(dir, hostname, targetURL)
+	require.NoError(t, os.MkdirAll(urlDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(urlDir, contentArchiveURLFile), []byte(targetURL+"\n"), 0644))
+
+	snapshotPath := filepath.Join(urlDir, name)
+	require.NoError(t, os.WriteFile(snapshotPath, []byte(strings.Repeat("x", size)), 0644))
+	require.NoError(t, os.Chtimes(snapshotPath, modTime, modTime))
+	return snapshotPath
+}
+
+func TestContentArchiver_CleanupPerHostSizeCap(t *testing.T) {
+	archiver, dir := newTestContentArchiver(t, config.ContentArchiveFormatRaw)
+	now := archiveScanTime
+
+	oldest := writeSnapshot(t, dir, "example.com", "https://example.com/a", "1.html", 400, now.Add(-3*time.Hour))
+	middle := writeSnapshot(t, dir, "example.com", "https://example.com/b", "2.html", 400, now.Add(-2*time.Hour))
+	newest := writeSnapshot(t, dir, "example.com", "https://example.com/a", "3.html", 400, now.Add(-time.Hour))
+	otherHost := writeSnapshot(t, dir, "other.com", "https://other.com/", "1.html", 900, now.Add(-4*time.Hour))
+
+	result, err := archiver.Cleanup(0, 1000, now)
+	require.NoError(t, err)
+
+	// example.com holds 1200 bytes and drops its oldest snapshot; other.com is within the cap on its own
+	assert.Equal(t, RetentionResult{Targets: 2, DeletedFiles: 1, FreedBytes: 400}, result)
+	assert.NoFileExists(t, oldest)
+	assert.FileExists(t, middle)
+	assert.FileExists(t, newest)
+	assert.FileExists(t, otherHost)
+}
+
+func TestContentArchiver_CleanupRemovesEmptyURLDirs(t *testing.T) {
+	archiver, dir := newTestContentArchiver(t, config.ContentArchiveFormatRaw)
+	now := archiveScanTime
+
+	expired := writeSnapshot(t, dir, "example.com", "https://example.com/gone", "1.html", 10, now.Add(-10*24*time.Hour))
+	partlyExpired := writeSnapshot(t, dir, "example.com", "https://example.com/kept", "1.html", 10, now.Add(-10*24*time.Hour))
+	recent := writeSnapshot(t, dir, "example.com", "https://example.com/kept", "2.html", 10, now.Add(-time.Hour))
+
+	result, err := archiver.Cleanup(7*24*time.Hour, 0, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.DeletedFiles)
+
+	assert.NoDirExists(t, filepath.Dir(expired), "a URL directory with only url.txt left is removed")
+	assert.NoFileExists(t, partlyExpired)
+	assert.FileExists(t, recent)
+	assert.FileExists(t, filepath.Join(filepath.Dir(recent), contentArchiveURLFile))
+}
+
+func TestContentArchiver_CleanupWithoutArchive(t *testing.T) {
+	archiver := NewContentArchiver(config.ContentArchiveConfig{Enabled: true, Dir: filepath.Join(t.TempDir(), "missing")}, zerolog.Nop())
+
+	result, err := archiver.Cleanup(time.Hour, 1, archiveScanTime)
+	require.NoError(t, err, "nothing archived yet is not an error")
+	assert.Equal(t, RetentionResult{}, result)
+}
@@ -0,0 +1,146 @@
+package datastore
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog"
+)
+
+// ParquetFileInspection is the contents of one probe result Parquet file, as read by InspectParquetFile
+type ParquetFileInspection struct {
+	FilePath  string
+	Size      int64
+	Schema    string // Parquet schema in its textual message form
+	NumRows   int64
+	RowGroups int
+	Matched   int                       // Records matching the options, including those outside the returned page
+	Results   []httpxrunner.ProbeResult // The page of matching records selected by Offset and Limit
+}
+
+// ParquetInspectOptions selects the records InspectParquetFile returns. The zero value returns every record.
+type ParquetInspectOptions struct {
+	// Only read the file metadata, no records
+	SchemaOnly bool
+	// Case-insensitive substring of the input or final URL
+	URLContains string
+	// Only records whose diff status marks a change (new or old)
+	DiffsOnly bool
+	// Matching records skipped before the first one returned
+	Offset int
+	// Maximum number of records returned; 0 returns every match
+	Limit int
+}
+
+// matches reports whether a stored record passes the URL and diff filters. It only looks at plain
+// columns, so records outside the page are counted without decompressing their response bodies.
+func (opts ParquetInspectOptions) matches(row *ParquetProbeResult, urlSubstring string) bool {
+	if urlSubstring != "" &&
+		!strings.Contains(strings.ToLower(row.OriginalURL), urlSubstring) &&
+		!strings.Contains(strings.ToLower(StringFromPtr(row.FinalURL)), urlSubstring) {
+		return false
+	}
+	if opts.DiffsOnly {
+		status := StringFromPtr(row.DiffStatus)
+		return status == "new" || status == "old"
+	}
+	return true
+}
+
+// InspectParquetFile reads a probe result Parquet file from any local path, outside the configured storage.
+// Unlike the scan history reader it never moves a corrupt file aside: the file is what is being debugged.
+// Row groups are streamed through the options, so only the requested page of records is held in memory.
+func InspectParquetFile(filePath string, opts ParquetInspectOptions, logger zerolog.Logger) (inspection *ParquetFileInspection, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to open Parquet file: "+filePath)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			logger.Error().Err(closeErr).Str("file", filePath).Msg("Failed to close Parquet file")
+		}
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to stat Parquet file: "+filePath)
+	}
+	if info.Size() < 12 {
+		return nil, errorwrapper.NewValidationError("file_size", info.Size(), "Parquet file is too small to be valid (minimum 12 bytes required)")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			inspection = nil
+			err = fmt.Errorf("panic reading parquet file: %v", r)
+		}
+	}()
+
+	parquetFile, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to open Parquet file: "+filePath)
+	}
+
+	inspection = &ParquetFileInspection{
+		FilePath:  filePath,
+		Size:      info.Size(),
+		Schema:    parquetFile.Schema().String(),
+		NumRows:   parquetFile.NumRows(),
+		RowGroups: len(parquetFile.RowGroups()),
+	}
+
+	if opts.SchemaOnly {
+		return inspection, nil
+	}
+
+	for _, rowGroup := range parquetFile.RowGroups() {
+		if err := inspectRowGroup(rowGroup, opts, inspection, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	return inspection, nil
+}
+
+// inspectRowGroup reads a row group in batches, counting matching records and converting those on the page
+func inspectRowGroup(rowGroup parquet.RowGroup, opts ParquetInspectOptions, inspection *ParquetFileInspection, logger zerolog.Logger) error {
+	missingLists := missingListColumns(rowGroup.Schema())
+	reader := parquet.NewGenericRowGroupReader[ParquetProbeResult](rowGroup)
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Error().Err(err).Msg("Failed to close Parquet row group reader")
+		}
+	}()
+
+	urlSubstring := strings.ToLower(opts.URLContains)
+	const batchSize = 1000
+	rows := make([]ParquetProbeResult, batchSize)
+
+	for {
+		n, err := reader.Read(rows)
+		if err != nil && err != io.EOF {
+			return errorwrapper.WrapError(err, "failed to read rows from Parquet row group")
+		}
+
+		for i := 0; i < n; i++ {
+			if !opts.matches(&rows[i], urlSubstring) {
+				continue
+			}
+			inspection.Matched++
+			if inspection.Matched <= opts.Offset || (opts.Limit > 0 && len(inspection.Results) >= opts.Limit) {
+				continue
+			}
+			rows[i].clearListColumns(missingLists)
+			inspection.Results = append(inspection.Results, rows[i].ToProbeResult(logger))
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeInspectFixture stores ten probe results in row groups of four and returns the file path.
+// Every third URL is new, /api/ URLs redirect to a final URL holding "Checkout", and every result has a body.
+func writeInspectFixture(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	storageConfig := config.NewDefaultStorageConfig()
+	storageConfig.ParquetBasePath = baseDir
+	storageConfig.RowGroupSize = 4
+	storageConfig.ResponseBodies = config.ResponseBodyConfig{Enabled: true}
+	writer, err := NewParquetWriter(&storageConfig, zerolog.Nop())
+	require.NoError(t, err)
+
+	var results []httpxrunner.ProbeResult
+	for i := 1; i <= 10; i++ {
+		result := httpxrunner.ProbeResult{
+			InputURL:      fmt.Sprintf("https://example.com/page%d", i),
+			StatusCode:    200,
+			Body:          fmt.Sprintf("<p>page %d</p>", i),
+			RootTargetURL: "example.com",
+			URLStatus:     "existing",
+		}
+		if i%3 == 0 {
+			result.URLStatus = "new"
+		}
+		if i%5 == 0 {
+			result.InputURL = fmt.Sprintf("https://example.com/api/%d", i)
+			result.FinalURL = "https://example.com/Checkout"
+		}
+		results = append(results, result)
+	}
+	require.NoError(t, writer.Write(context.Background(), results, "session", "example.com"))
+	return filepath.Join(baseDir, scanBlobDir, "example.com.parquet")
+}
+
+func inspectedURLs(inspection *ParquetFileInspection) []string {
+	var urls []string
+	for _, result := range inspection.Results {
+		urls = append(urls, result.InputURL)
+	}
+	return urls
+}
+
+func TestInspectParquetFile(t *testing.T) {
+	filePath := writeInspectFixture(t)
+
+	tests := []struct {
+		name        string
+		opts        ParquetInspectOptions
+		wantMatched int
+		wantURLs    []string
+	}{
+		{
+			name:        "everything",
+			wantMatched: 10,
+			wantURLs: []string{
+				"https://example.com/page1", "https://example.com/page2", "https://example.com/page3", "https://example.com/page4",
+				"https://example.com/api/5", "https://example.com/page6", "https://example.com/page7", "https://example.com/page8",
+				"https://example.com/page9", "https://example.com/api/10",
+			},
+		},
+		{
+			name:        "input URL substring ignores case",
+			opts:        ParquetInspectOptions{URLContains: "/API/"},
+			wantMatched: 2,
+			wantURLs:    []string{"https://example.com/api/5", "https://example.com/api/10"},
+		},
+		{
+			name:        "final URL substring",
+			opts:        ParquetInspectOptions{URLContains: "checkout"},
+			wantMatched: 2,
+			wantURLs:    []string{"https://example.com/api/5", "https://example.com/api/10"},
+		},
+		{
+			name:        "diffs only",
+			opts:        ParquetInspectOptions{DiffsOnly: true},
+			wantMatched: 3,
+			wantURLs:    []string{"https://example.com/page3", "https://example.com/page6", "https://example.com/page9"},
+		},
+		{
+			name:        "offset and limit page through the matches",
+			opts:        ParquetInspectOptions{Offset: 3, Limit: 2},
+			wantMatched: 10,
+			wantURLs:    []string{"https://example.com/page4", "https://example.com/api/5"},
+		},
+		{
+			name:        "offset past the matches",
+			opts:        ParquetInspectOptions{DiffsOnly: true, Offset: 5},
+			wantMatched: 3,
+		},
+		{
+			name: "schema only reads no records",
+			opts: ParquetInspectOptions{SchemaOnly: true, Limit: 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspection, err := InspectParquetFile(filePath, tt.opts, zerolog.Nop())
+			require.NoError(t, err)
+			assert.Equal(t, int64(10), inspection.NumRows)
+			assert.Equal(t, 3, inspection.RowGroups)
+			assert.NotEmpty(t, inspection.Schema)
+			assert.Equal(t, tt.wantMatched, inspection.Matched)
+			assert.Equal(t, tt.wantURLs, inspectedURLs(inspection))
+		})
+	}
+}
+
+func TestInspectParquetFile_PageKeepsBodies(t *testing.T) {
+	inspection, err := InspectParquetFile(writeInspectFixture(t), ParquetInspectOptions{Offset: 1, Limit: 1}, zerolog.Nop())
+	require.NoError(t, err)
+	require.Len(t, inspection.Results, 1)
+	assert.Equal(t, "<p>page 2</p>", inspection.Results[0].Body)
+}
+
+func TestParquetInspectOptions_Matches(t *testing.T) {
+	finalURL := "https://example.com/Login"
+	oldStatus := "old"
+	existing := "existing"
+
+	tests := []struct {
+		name string
+		opts ParquetInspectOptions
+		row  ParquetProbeResult
+		want bool
+	}{
+		{name: "no filters", row: ParquetProbeResult{OriginalURL: "https://example.com/"}, want: true},
+		{name: "input URL", opts: ParquetInspectOptions{URLContains: "Example.COM"}, row: ParquetProbeResult{OriginalURL: "https://example.com/"}, want: true},
+		{name: "final URL", opts: ParquetInspectOptions{URLContains: "login"}, row: ParquetProbeResult{OriginalURL: "https://example.com/", FinalURL: &finalURL}, want: true},
+		{name: "no final URL", opts: ParquetInspectOptions{URLContains: "login"}, row: ParquetProbeResult{OriginalURL: "https://example.com/"}, want: false},
+		{name: "old is a diff", opts: ParquetInspectOptions{DiffsOnly: true}, row: ParquetProbeResult{DiffStatus: &oldStatus}, want: true},
+		{name: "existing is not a diff", opts: ParquetInspectOptions{DiffsOnly: true}, row: ParquetProbeResult{DiffStatus: &existing}, want: false},
+		{name: "no diff status", opts: ParquetInspectOptions{DiffsOnly: true}, row: ParquetProbeResult{}, want: false},
+		{name: "both filters", opts: ParquetInspectOptions{URLContains: "login", DiffsOnly: true}, row: ParquetProbeResult{FinalURL: &finalURL, DiffStatus: &existing}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.opts.matches(&tt.row, strings.ToLower(tt.opts.URLContains)))
+		})
+	}
+}
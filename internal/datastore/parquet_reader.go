@@ -2,10 +2,10 @@ package datastore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"path"
 	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
@@ -24,6 +24,7 @@ type ParquetReader struct {
 	logger        zerolog.Logger
 	fileManager   *filemanager.FileManager
 	config        ParquetReaderConfig
+	blob          Blob
 }
 
 // NewParquetReader creates a new ParquetReader using builder pattern
@@ -75,12 +76,13 @@ func (pr *ParquetReader) searchProbeResults(query ProbeResultQuery) (*ProbeResul
 		return nil, err
 	}
 
-	filePath, err := pr.buildParquetFilePath(query.RootTargetURL)
+	key, err := pr.buildParquetKey(query.RootTargetURL)
 	if err != nil {
 		return nil, err
 	}
+	filePath := pr.blob.Location(key)
 
-	fileInfo, err := pr.validateFileExists(filePath)
+	fileInfo, err := pr.statParquetFile(key)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +96,7 @@ func (pr *ParquetReader) searchProbeResults(query ProbeResultQuery) (*ProbeResul
 		}, nil
 	}
 
-	results, err := pr.readProbeResultsFromFile(filePath, query.RootTargetURL)
+	results, err := pr.readProbeResultsFromFile(key, query.RootTargetURL)
 	if err != nil {
 		return nil, errorwrapper.WrapError(err, "failed to read probe results from file")
 	}
@@ -107,48 +109,78 @@ func (pr *ParquetReader) searchProbeResults(query ProbeResultQuery) (*ProbeResul
 	return &ProbeResultSearchResult{
 		Results:      results,
 		TotalCount:   len(results),
-		LastModified: fileInfo.ModTime(),
+		LastModified: fileInfo.ModTime,
 		FilePath:     filePath,
 	}, nil
 }
 
 // validateConfiguration checks if the reader is properly configured
 func (pr *ParquetReader) validateConfiguration() error {
-	if pr.storageConfig == nil || pr.storageConfig.ParquetBasePath == "" {
+	if pr.storageConfig == nil || pr.storageConfig.ParquetBasePath == "" || pr.blob == nil {
 		return errorwrapper.NewValidationError("parquet_base_path", pr.storageConfig, "ParquetBasePath is not configured")
 	}
 	return nil
 }
 
-// buildParquetFilePath constructs the file path for a given root target URL
-func (pr *ParquetReader) buildParquetFilePath(rootTargetURL string) (string, error) {
+// buildParquetKey constructs the storage key for a given root target URL
+func (pr *ParquetReader) buildParquetKey(rootTargetURL string) (string, error) {
 	sanitizedTargetName := urlhandler.SanitizeFilename(rootTargetURL)
 	if sanitizedTargetName == "" {
 		return "", errorwrapper.NewValidationError("root_target_url", rootTargetURL, "sanitized to empty string, cannot determine Parquet file path")
 	}
 
 	fileName := fmt.Sprintf("%s.parquet", sanitizedTargetName)
-	return filepath.Join(pr.storageConfig.ParquetBasePath, "scan", fileName), nil
+	return blobKey(scanBlobDir, fileName), nil
 }
 
-// validateFileExists checks if the target file exists and returns file info
-func (pr *ParquetReader) validateFileExists(filePath string) (os.FileInfo, error) {
-	fileInfo, err := os.Stat(filePath)
-	if os.IsNotExist(err) {
-		// pr.logger.Info().Str("file", filePath).Msg("Parquet file not found for target")
+// statParquetFile returns the info of a stored Parquet file, or nil if it does not exist yet
+func (pr *ParquetReader) statParquetFile(key string) (*BlobInfo, error) {
+	info, err := pr.blob.Stat(context.Background(), key)
+	if errors.Is(err, ErrBlobNotFound) {
 		return nil, nil // Not an error - file simply doesn't exist yet
 	}
 	if err != nil {
-		return nil, errorwrapper.WrapError(err, "failed to stat Parquet file: "+filePath)
+		return nil, errorwrapper.WrapError(err, "failed to stat Parquet file: "+pr.blob.Location(key))
 	}
-	return fileInfo, nil
+	return &info, nil
 }
 
-// readProbeResultsFromFile reads all probe results from a specific Parquet file
-func (pr *ParquetReader) readProbeResultsFromFile(filePath, contextualRootTargetURL string) ([]httpxrunner.ProbeResult, error) {
+// listParquetKeys returns the keys of every stored target's Parquet file
+func (pr *ParquetReader) listParquetKeys() ([]string, error) {
+	infos, err := pr.blob.List(context.Background(), scanBlobDir)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to list Parquet directory: "+pr.blob.Location(scanBlobDir))
+	}
+
+	var keys []string
+	for _, info := range infos {
+		if path.Ext(info.Key) == ".parquet" {
+			keys = append(keys, info.Key)
+		}
+	}
+	return keys, nil
+}
+
+// readProbeResultsFromFile reads all probe results from a specific Parquet file.
+// A corrupt file is moved aside and treated as empty, so the target's history starts fresh
+// instead of failing every later scan of it.
+func (pr *ParquetReader) readProbeResultsFromFile(key, contextualRootTargetURL string) ([]httpxrunner.ProbeResult, error) {
+	results, err := pr.decodeProbeResultsFile(key, contextualRootTargetURL)
+
+	var corruptErr *corruptFileError
+	if errors.As(err, &corruptErr) {
+		pr.quarantineCorruptFile(key, corruptErr)
+		return []httpxrunner.ProbeResult{}, nil
+	}
+	return results, err
+}
+
+// decodeProbeResultsFile opens and decodes a Parquet file, reporting unreadable contents as a corruptFileError
+func (pr *ParquetReader) decodeProbeResultsFile(key, contextualRootTargetURL string) ([]httpxrunner.ProbeResult, error) {
+	filePath := pr.blob.Location(key)
 	pr.logger.Debug().Str("file", filePath).Msg("Reading probe results from Parquet file")
 
-	file, err := pr.openParquetFile(filePath)
+	file, err := pr.openParquetFile(key)
 	if err != nil {
 		return nil, err
 	}
@@ -161,21 +193,12 @@ func (pr *ParquetReader) readProbeResultsFromFile(filePath, contextualRootTarget
 
 	// Check file size before attempting to create reader
 	if err := pr.validateParquetFile(file, filePath); err != nil {
-		// If file is too small, return empty results instead of error
-		pr.logger.Info().
-			Str("file", filePath).
-			Msg("Parquet file is too small or invalid, returning empty results")
-		return []httpxrunner.ProbeResult{}, nil
+		return nil, &corruptFileError{reason: "file is too small to be valid Parquet", err: err}
 	}
 
-	reader, err := pr.createParquetReader(file)
+	reader, missingLists, err := pr.createParquetReader(file, filePath)
 	if err != nil {
-		// If parquet reader creation fails, return empty results instead of error
-		pr.logger.Info().
-			Str("file", filePath).
-			Err(err).
-			Msg("Failed to create Parquet reader, returning empty results")
-		return []httpxrunner.ProbeResult{}, nil
+		return nil, &corruptFileError{reason: "failed to open Parquet reader", err: err}
 	}
 	defer func() {
 		err := reader.Close()
@@ -184,9 +207,9 @@ func (pr *ParquetReader) readProbeResultsFromFile(filePath, contextualRootTarget
 		}
 	}()
 
-	results, err := pr.readAllRecords(reader, contextualRootTargetURL)
+	results, err := pr.readAllRecords(reader, missingLists, contextualRootTargetURL)
 	if err != nil {
-		return nil, errorwrapper.WrapError(err, "failed to read records from Parquet file")
+		return nil, &corruptFileError{reason: "failed to read records", err: err}
 	}
 
 	pr.logger.Debug().
@@ -197,9 +220,47 @@ func (pr *ParquetReader) readProbeResultsFromFile(filePath, contextualRootTarget
 	return results, nil
 }
 
-// openParquetFile opens and validates a Parquet file
-func (pr *ParquetReader) openParquetFile(filePath string) (*os.File, error) {
-	file, err := os.Open(filePath)
+// corruptFileError marks a Parquet file whose contents cannot be decoded, as opposed to one that cannot be opened
+type corruptFileError struct {
+	reason string
+	err    error
+}
+
+func (e *corruptFileError) Error() string {
+	return e.reason + ": " + e.err.Error()
+}
+
+func (e *corruptFileError) Unwrap() error {
+	return e.err
+}
+
+// quarantineCorruptFile renames a corrupt Parquet file to a .corrupt backup so it can be inspected later
+// and is no longer picked up as history. Older backups of the same file are kept.
+func (pr *ParquetReader) quarantineCorruptFile(key string, cause error) {
+	ctx := context.Background()
+	backupKey := key + ".corrupt"
+	if _, err := pr.blob.Stat(ctx, backupKey); err == nil {
+		backupKey = fmt.Sprintf("%s.%s.corrupt", key, time.Now().Format("20060102-150405"))
+	}
+
+	logEvent := pr.logger.Error().
+		Err(cause).
+		Str("file", pr.blob.Location(key))
+
+	if err := pr.blob.Rename(ctx, key, backupKey); err != nil {
+		logEvent.AnErr("rename_error", err).
+			Msg("Corrupt Parquet file could not be moved aside; treating its history as empty")
+		return
+	}
+
+	logEvent.Str("backup", pr.blob.Location(backupKey)).
+		Msg("Corrupt Parquet file moved aside; treating its history as empty, the target starts fresh")
+}
+
+// openParquetFile opens a stored Parquet file
+func (pr *ParquetReader) openParquetFile(key string) (BlobObject, error) {
+	filePath := pr.blob.Location(key)
+	file, err := pr.blob.Open(context.Background(), key)
 	if err != nil {
 		pr.logger.Error().Err(err).Str("file", filePath).Msg("Failed to open Parquet file")
 		return nil, errorwrapper.WrapError(err, "failed to open Parquet file: "+filePath)
@@ -208,44 +269,41 @@ func (pr *ParquetReader) openParquetFile(filePath string) (*os.File, error) {
 }
 
 // validateParquetFile checks if the Parquet file is valid and not empty
-func (pr *ParquetReader) validateParquetFile(file *os.File, filePath string) error {
-	// Get file stats to check size
-	stat, err := file.Stat()
-	if err != nil {
-		return errorwrapper.WrapError(err, "failed to get file stats for: "+filePath)
-	}
-
+func (pr *ParquetReader) validateParquetFile(file BlobObject, filePath string) error {
 	// Check if file is empty or too small to contain a valid Parquet file
 	// Parquet files need at least 12 bytes for magic header and footer
-	if stat.Size() < 12 {
+	if file.Size() < 12 {
 		pr.logger.Warn().
-			Int64("file_size", stat.Size()).
+			Int64("file_size", file.Size()).
 			Str("file", filePath).
 			Msg("Parquet file is too small or empty, returning empty results")
-		return errorwrapper.NewValidationError("file_size", stat.Size(), "Parquet file is too small to be valid (minimum 12 bytes required)")
+		return errorwrapper.NewValidationError("file_size", file.Size(), "Parquet file is too small to be valid (minimum 12 bytes required)")
 	}
 
 	return nil
 }
 
-// createParquetReader creates a configured Parquet reader
-func (pr *ParquetReader) createParquetReader(file *os.File) (reader *parquet.GenericReader[ParquetProbeResult], err error) {
+// createParquetReader creates a configured Parquet reader, along with the list columns missing from the file
+func (pr *ParquetReader) createParquetReader(file BlobObject, filePath string) (reader *parquet.GenericReader[ParquetProbeResult], missingLists []string, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			pr.logger.Error().
 				Interface("panic", r).
-				Str("file", file.Name()).
+				Str("file", filePath).
 				Msg("Panic occurred while creating Parquet reader")
 			err = errorwrapper.WrapError(fmt.Errorf("panic creating parquet reader: %v", r), "failed to create parquet reader due to panic")
 			reader = nil
 		}
 	}()
 
-	readerOptions := pr.buildReaderOptions()
+	parquetFile, err := parquet.OpenFile(file, file.Size())
+	if err != nil {
+		return nil, nil, errorwrapper.WrapError(err, "failed to open Parquet file: "+filePath)
+	}
 
-	// Try to create reader with proper error handling
-	reader = parquet.NewGenericReader[ParquetProbeResult](file, readerOptions...)
-	return reader, nil
+	readerOptions := pr.buildReaderOptions()
+	reader = parquet.NewGenericReader[ParquetProbeResult](parquetFile, readerOptions...)
+	return reader, missingListColumns(parquetFile.Schema()), nil
 }
 
 // buildReaderOptions constructs reader options based on configuration
@@ -255,8 +313,8 @@ func (pr *ParquetReader) buildReaderOptions() []parquet.ReaderOption {
 	return options
 }
 
-// readAllRecords reads all records from the Parquet reader
-func (pr *ParquetReader) readAllRecords(reader *parquet.GenericReader[ParquetProbeResult], contextualRootTargetURL string) ([]httpxrunner.ProbeResult, error) {
+// readAllRecords reads all records from the Parquet reader, clearing the list columns the file lacks
+func (pr *ParquetReader) readAllRecords(reader *parquet.GenericReader[ParquetProbeResult], missingLists []string, contextualRootTargetURL string) ([]httpxrunner.ProbeResult, error) {
 	var results []httpxrunner.ProbeResult
 
 	// Read all rows using a buffer and loop
@@ -272,6 +330,7 @@ func (pr *ParquetReader) readAllRecords(reader *parquet.GenericReader[ParquetPro
 
 		// Process the read rows
 		for i := 0; i < n; i++ {
+			rows[i].clearListColumns(missingLists)
 			probeResult := pr.convertParquetRecord(rows[i], contextualRootTargetURL)
 			results = append(results, probeResult)
 		}
@@ -287,7 +346,7 @@ func (pr *ParquetReader) readAllRecords(reader *parquet.GenericReader[ParquetPro
 
 // convertParquetRecord converts a Parquet record to ProbeResult
 func (pr *ParquetReader) convertParquetRecord(row ParquetProbeResult, contextualRootTargetURL string) httpxrunner.ProbeResult {
-	probeResult := row.ToProbeResult()
+	probeResult := row.ToProbeResult(pr.logger)
 
 	// Ensure RootTargetURL is set using context if missing
 	if probeResult.RootTargetURL == "" && contextualRootTargetURL != "" {
@@ -11,6 +11,7 @@ type ParquetReaderBuilder struct {
 	storageConfig *config.StorageConfig
 	logger        zerolog.Logger
 	config        ParquetReaderConfig
+	blob          Blob
 }
 
 // NewParquetReaderBuilder creates a new ParquetReaderBuilder
@@ -27,6 +28,12 @@ func (b *ParquetReaderBuilder) WithStorageConfig(cfg *config.StorageConfig) *Par
 	return b
 }
 
+// WithBlob sets the storage backend; by default it is created from the storage configuration
+func (b *ParquetReaderBuilder) WithBlob(blob Blob) *ParquetReaderBuilder {
+	b.blob = blob
+	return b
+}
+
 // WithReaderConfig sets the reader configuration
 func (b *ParquetReaderBuilder) WithReaderConfig(cfg ParquetReaderConfig) *ParquetReaderBuilder {
 	b.config = cfg
@@ -39,6 +46,14 @@ func (b *ParquetReaderBuilder) Build() (*ParquetReader, error) {
 		b.logger.Warn().Msg("StorageConfig or ParquetBasePath is not properly configured")
 	}
 
+	blob := b.blob
+	if blob == nil && b.storageConfig != nil {
+		var err error
+		if blob, err = NewBlob(b.storageConfig, b.logger); err != nil {
+			return nil, err
+		}
+	}
+
 	fileManager := filemanager.NewFileManager(b.logger)
 
 	return &ParquetReader{
@@ -46,5 +61,6 @@ func (b *ParquetReaderBuilder) Build() (*ParquetReader, error) {
 		logger:        b.logger,
 		fileManager:   fileManager,
 		config:        b.config,
+		blob:          blob,
 	}, nil
 }
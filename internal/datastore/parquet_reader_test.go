@@ -0,0 +1,89 @@
+package datastore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestParquetStore returns a writer and reader sharing a temporary parquet_base_path
+func newTestParquetStore(t *testing.T) (*ParquetWriter, *ParquetReader, string) {
+	t.Helper()
+	baseDir := t.TempDir()
+	storageConfig := config.NewDefaultStorageConfig()
+	storageConfig.ParquetBasePath = baseDir
+
+	writer, err := NewParquetWriter(&storageConfig, zerolog.Nop())
+	require.NoError(t, err)
+	reader, err := NewParquetReaderBuilder(zerolog.Nop()).WithStorageConfig(&storageConfig).Build()
+	require.NoError(t, err)
+	return writer, reader, baseDir
+}
+
+func writeProbeResults(t *testing.T, writer *ParquetWriter, hostname string, urls ...string) {
+	t.Helper()
+	var results []httpxrunner.ProbeResult
+	for _, u := range urls {
+		results = append(results, httpxrunner.ProbeResult{InputURL: u, StatusCode: 200, RootTargetURL: hostname})
+	}
+	require.NoError(t, writer.Write(context.Background(), results, "session", hostname))
+}
+
+func TestParquetReader_QuarantinesCorruptFile(t *testing.T) {
+	writer, reader, baseDir := newTestParquetStore(t)
+	writeProbeResults(t, writer, "example.com", "https://example.com/", "https://example.com/login")
+
+	livePath := filepath.Join(baseDir, scanBlobDir, "example.com.parquet")
+	data, err := os.ReadFile(livePath)
+	require.NoError(t, err)
+
+	// A file cut short, as left behind by a process killed mid-write
+	require.NoError(t, os.WriteFile(livePath, data[:len(data)/2], 0644))
+
+	results, _, err := reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err, "a corrupt file is treated as empty history")
+	assert.Empty(t, results)
+	assert.NoFileExists(t, livePath)
+	backup, err := os.ReadFile(livePath + ".corrupt")
+	require.NoError(t, err)
+	assert.Equal(t, data[:len(data)/2], backup, "the corrupt file is kept for inspection")
+
+	// The target starts fresh, and a second corrupt file does not overwrite the first backup
+	writeProbeResults(t, writer, "example.com", "https://example.com/new")
+	results, _, err = reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/new", results[0].InputURL)
+
+	require.NoError(t, os.WriteFile(livePath, []byte("PAR1 not really parquet PAR1"), 0644))
+	_, _, err = reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(baseDir, scanBlobDir))
+	require.NoError(t, err)
+	var backups []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".corrupt") {
+			backups = append(backups, entry.Name())
+		}
+	}
+	assert.Len(t, backups, 2)
+	assert.NoFileExists(t, livePath)
+}
+
+func TestParquetReader_MissingFileIsNotCorrupt(t *testing.T) {
+	_, reader, baseDir := newTestParquetStore(t)
+
+	results, _, err := reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.NoFileExists(t, filepath.Join(baseDir, scanBlobDir, "example.com.parquet.corrupt"))
+}
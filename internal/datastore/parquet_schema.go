@@ -2,10 +2,14 @@ package datastore
 
 import (
 	"encoding/json"
+	"slices"
 	stdtime "time"
 
 	time "github.com/aleister1102/monsterinc/internal/common/timeutils"
+	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog"
 )
 
 // Required for TLSCertExpiry potentially if it becomes time.Time
@@ -37,6 +41,100 @@ type ParquetProbeResult struct {
 	ScanTimestamp      int64   `parquet:"scan_timestamp"`                // Timestamp of the current scan session for this record
 	FirstSeenTimestamp *int64  `parquet:"first_seen_timestamp,optional"` // Timestamp when this URL was first ever seen
 	LastSeenTimestamp  *int64  `parquet:"last_seen_timestamp,optional"`  // Timestamp when this URL was last seen (could be same as ScanTimestamp for new/existing)
+	MissedScans        *int32  `parquet:"missed_scans,optional"`         // Consecutive scans the URL was not seen in (only set for retained "old" URLs)
+
+	// Leaf TLS certificate (only populated when httpx extract_tls is enabled)
+	TLSCertNotAfter *int64   `parquet:"tls_cert_not_after,optional"` // Certificate expiry in Unix milliseconds
+	TLSCertIssuer   *string  `parquet:"tls_cert_issuer,optional"`
+	TLSCertSANs     []string `parquet:"tls_cert_sans,list"`
+
+	// Tags of the target the URL belongs to, from "|tags=" target annotations
+	Tags []string `parquet:"tags,list"`
+
+	// Response body storage (only populated when storage_config.response_bodies is enabled)
+	ResponseBodyGzip      []byte `parquet:"response_body_gzip"`               // Gzip-compressed, size-capped response body; empty when not stored (parquet-go does not round-trip optional []byte)
+	ResponseBodyTruncated *bool  `parquet:"response_body_truncated,optional"` // True if the body was cut at the size cap before compression
+}
+
+// OmittedColumns returns the optional Parquet columns whose httpx extraction is disabled in cfg.
+// Files written without these columns stay readable: missing columns decode as zero values.
+func OmittedColumns(cfg config.HttpxRunnerConfig) []string {
+	toggles := []struct {
+		enabled bool
+		column  string
+	}{
+		{cfg.ExtractContentLength, "content_length"},
+		{cfg.ExtractContentType, "content_type"},
+		{cfg.ExtractHeaders, "headers_json"},
+		{cfg.ExtractIPs, "ip_address"},
+		{cfg.ExtractServerHeader, "web_server"},
+		{cfg.ExtractTitle, "title"},
+		{cfg.TechDetect, "technologies"},
+		{cfg.ExtractTLS, "tls_cert_not_after"},
+		{cfg.ExtractTLS, "tls_cert_issuer"},
+		{cfg.ExtractTLS, "tls_cert_sans"},
+	}
+
+	var omitted []string
+	for _, toggle := range toggles {
+		if !toggle.enabled {
+			omitted = append(omitted, toggle.column)
+		}
+	}
+	return omitted
+}
+
+// probeResultSchemaWithout returns the ParquetProbeResult schema minus the given top-level columns,
+// along with the conversion from full rows to rows of that schema
+func probeResultSchemaWithout(omit []string) (*parquet.Schema, parquet.Conversion, error) {
+	full := parquet.SchemaOf(ParquetProbeResult{})
+
+	projected := parquet.Group{}
+	for _, field := range full.Fields() {
+		if !slices.Contains(omit, field.Name()) {
+			projected[field.Name()] = field
+		}
+	}
+
+	schema := parquet.NewSchema(full.Name(), projected)
+	conversion, err := parquet.Convert(schema, full)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schema, conversion, nil
+}
+
+// missingListColumns returns the list columns of ParquetProbeResult that fileSchema lacks. parquet-go
+// reads an absent list column as one empty element instead of an empty list, so callers clear them.
+func missingListColumns(fileSchema *parquet.Schema) []string {
+	present := make(map[string]bool)
+	for _, field := range fileSchema.Fields() {
+		present[field.Name()] = true
+	}
+
+	var missing []string
+	for _, column := range []string{"technologies", "ip_address", "tls_cert_sans", "tags"} {
+		if !present[column] {
+			missing = append(missing, column)
+		}
+	}
+	return missing
+}
+
+// clearListColumns resets the list fields named in columns
+func (ppr *ParquetProbeResult) clearListColumns(columns []string) {
+	for _, column := range columns {
+		switch column {
+		case "technologies":
+			ppr.Technologies = nil
+		case "ip_address":
+			ppr.IPAddress = nil
+		case "tls_cert_sans":
+			ppr.TLSCertSANs = nil
+		case "tags":
+			ppr.Tags = nil
+		}
+	}
 }
 
 // TimePtrToUnixMilliOptional converts time.Time to a pointer to int64 (Unix milliseconds).
@@ -50,7 +148,8 @@ func TimePtrToUnixMilliOptional(t stdtime.Time) *int64 {
 }
 
 // ToProbeResult converts a ParquetProbeResult back to a models.ProbeResult.
-func (ppr *ParquetProbeResult) ToProbeResult() httpxrunner.ProbeResult {
+// A stored response body that cannot be decompressed is logged and left empty.
+func (ppr *ParquetProbeResult) ToProbeResult(logger zerolog.Logger) httpxrunner.ProbeResult {
 	var headers map[string]string
 	if ppr.HeadersJSON != nil && *ppr.HeadersJSON != "" {
 		if err := json.Unmarshal([]byte(*ppr.HeadersJSON), &headers); err != nil {
@@ -60,6 +159,13 @@ func (ppr *ParquetProbeResult) ToProbeResult() httpxrunner.ProbeResult {
 		}
 	}
 
+	// A corrupt body should not cost the rest of the record
+	body, err := decompressResponseBody(ppr.ResponseBodyGzip)
+	if err != nil {
+		logger.Warn().Err(err).Str("url", ppr.OriginalURL).Msg("Failed to decompress stored response body")
+		body = ""
+	}
+
 	var technologies []httpxrunner.Technology
 	for _, name := range ppr.Technologies { // Assuming ppr.Technologies is []string
 		technologies = append(technologies, httpxrunner.Technology{Name: name})
@@ -76,12 +182,18 @@ func (ppr *ParquetProbeResult) ToProbeResult() httpxrunner.ProbeResult {
 		ContentLength:       Int64FromPtr(ppr.ContentLength),
 		ContentType:         StringFromPtr(ppr.ContentType),
 		Headers:             headers,
+		Body:                body,
 		Title:               StringFromPtr(ppr.Title),
 		WebServer:           StringFromPtr(ppr.WebServer),
 		IPs:                 ppr.IPAddress,
 		Technologies:        technologies,
 		URLStatus:           StringFromPtr(ppr.DiffStatus),
 		OldestScanTimestamp: time.UnixMilliToTimeOptional(ppr.FirstSeenTimestamp), // Corrected: Call directly from models package
+		MissedScans:         int(Int32FromPtr(ppr.MissedScans)),
+		TLSCertNotAfter:     time.UnixMilliToTimeOptional(ppr.TLSCertNotAfter),
+		TLSCertIssuer:       StringFromPtr(ppr.TLSCertIssuer),
+		TLSCertSANs:         ppr.TLSCertSANs,
+		Tags:                ppr.Tags,
 	}
 }
 
@@ -0,0 +1,145 @@
+package datastore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// narrowExtraction keeps title, content type/length and tech detection, dropping headers, IPs, server and TLS
+var narrowExtraction = config.HttpxRunnerConfig{
+	ExtractContentLength: true,
+	ExtractContentType:   true,
+	ExtractTitle:         true,
+	TechDetect:           true,
+}
+
+var omittedByNarrowExtraction = []string{"headers_json", "ip_address", "web_server", "tls_cert_not_after", "tls_cert_issuer", "tls_cert_sans"}
+
+func newSchemaTestWriter(t *testing.T, baseDir string, extraction *config.HttpxRunnerConfig) *ParquetWriter {
+	t.Helper()
+	storageConfig := config.NewDefaultStorageConfig()
+	storageConfig.ParquetBasePath = baseDir
+	writer, err := NewParquetWriterBuilder(zerolog.Nop()).WithStorageConfig(&storageConfig).WithExtractionConfig(extraction).Build()
+	require.NoError(t, err)
+	return writer
+}
+
+func fullProbeResult(u string) httpxrunner.ProbeResult {
+	return httpxrunner.ProbeResult{
+		InputURL:      u,
+		StatusCode:    200,
+		ContentType:   "text/html",
+		ContentLength: 512,
+		Title:         "Home",
+		WebServer:     "nginx",
+		IPs:           []string{"203.0.113.7"},
+		Headers:       map[string]string{"Server": "nginx"},
+		Technologies:  []httpxrunner.Technology{{Name: "Nginx"}},
+		TLSCertIssuer: "Example CA",
+		RootTargetURL: "example.com",
+	}
+}
+
+// storedColumns returns the top-level columns of a written Parquet file
+func storedColumns(t *testing.T, filePath string) []string {
+	t.Helper()
+	file, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	require.NoError(t, err)
+	parquetFile, err := parquet.OpenFile(file, info.Size())
+	require.NoError(t, err)
+
+	var columns []string
+	for _, field := range parquetFile.Schema().Fields() {
+		columns = append(columns, field.Name())
+	}
+	return columns
+}
+
+func TestOmittedColumns(t *testing.T) {
+	assert.ElementsMatch(t, omittedByNarrowExtraction, OmittedColumns(narrowExtraction))
+
+	all := narrowExtraction
+	all.ExtractHeaders, all.ExtractIPs, all.ExtractServerHeader, all.ExtractTLS = true, true, true, true
+	assert.Empty(t, OmittedColumns(all))
+}
+
+func TestParquetSchema_FullFileReadAfterNarrowingExtraction(t *testing.T) {
+	baseDir := t.TempDir()
+	livePath := filepath.Join(baseDir, scanBlobDir, "example.com.parquet")
+	_, reader, _ := newTestParquetStore(t)
+	reader.blob = NewLocalBlob(baseDir)
+
+	// A file from before the extractions were disabled still carries every column
+	require.NoError(t, newSchemaTestWriter(t, baseDir, nil).Write(context.Background(),
+		[]httpxrunner.ProbeResult{fullProbeResult("https://example.com/")}, "old", "example.com"))
+	assert.Subset(t, storedColumns(t, livePath), omittedByNarrowExtraction)
+
+	results, _, err := reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "nginx", results[0].WebServer)
+	assert.Equal(t, []string{"203.0.113.7"}, results[0].IPs)
+	assert.Equal(t, "Example CA", results[0].TLSCertIssuer)
+
+	// Rewritten with the narrow extraction, the file drops the columns and keeps the rest
+	require.NoError(t, newSchemaTestWriter(t, baseDir, &narrowExtraction).Write(context.Background(), results, "new", "example.com"))
+	columns := storedColumns(t, livePath)
+	for _, omitted := range omittedByNarrowExtraction {
+		assert.NotContains(t, columns, omitted)
+	}
+
+	results, _, err = reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Home", results[0].Title)
+	assert.Equal(t, "text/html", results[0].ContentType)
+	assert.Equal(t, int64(512), results[0].ContentLength)
+	assert.Equal(t, []httpxrunner.Technology{{Name: "Nginx"}}, results[0].Technologies)
+	assert.Empty(t, results[0].WebServer)
+	assert.Empty(t, results[0].IPs, "an absent list column reads back as an empty list")
+	assert.Empty(t, results[0].TLSCertSANs)
+	assert.Empty(t, results[0].Headers)
+}
+
+func TestParquetSchema_NarrowFileReadAfterWideningExtraction(t *testing.T) {
+	baseDir := t.TempDir()
+	_, reader, _ := newTestParquetStore(t)
+	reader.blob = NewLocalBlob(baseDir)
+
+	require.NoError(t, newSchemaTestWriter(t, baseDir, &narrowExtraction).Write(context.Background(),
+		[]httpxrunner.ProbeResult{fullProbeResult("https://example.com/")}, "old", "example.com"))
+
+	// Missing columns decode as zero values, and a full write afterwards restores them
+	results, _, err := reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Home", results[0].Title)
+	assert.Empty(t, results[0].WebServer)
+	assert.Empty(t, results[0].IPs)
+
+	current := fullProbeResult("https://example.com/login")
+	require.NoError(t, newSchemaTestWriter(t, baseDir, nil).Write(context.Background(), append(results, current), "new", "example.com"))
+
+	results, _, err = reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	byURL := map[string]httpxrunner.ProbeResult{}
+	for _, result := range results {
+		byURL[result.InputURL] = result
+	}
+	assert.Empty(t, byURL["https://example.com/"].WebServer)
+	assert.Equal(t, "nginx", byURL["https://example.com/login"].WebServer)
+	assert.Equal(t, []string{"203.0.113.7"}, byURL["https://example.com/login"].IPs)
+}
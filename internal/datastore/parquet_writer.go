@@ -3,8 +3,7 @@ package datastore
 import (
 	stdcontext "context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
 	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/contextutils"
@@ -15,6 +14,8 @@ import (
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 
 	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/gzip"
+	"github.com/parquet-go/parquet-go/compress/zstd"
 	"github.com/rs/zerolog"
 )
 
@@ -24,6 +25,11 @@ type ParquetWriter struct {
 	logger       zerolog.Logger
 	fileManager  *filemanager.FileManager
 	writerConfig ParquetWriterConfig
+	blob         Blob
+
+	// Set when writerConfig.OmitColumns is non-empty: records are converted to this narrower schema before writing
+	projectedSchema *parquet.Schema
+	projection      parquet.Conversion
 }
 
 // NewParquetWriter creates a new ParquetWriter using builder pattern
@@ -84,14 +90,7 @@ func (pw *ParquetWriter) writeProbeResults(ctx stdcontext.Context, request Write
 		return nil, err
 	}
 
-	filePath, err := pw.prepareOutputFile(request.RootTarget)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := pw.checkCancellation(ctx, "before file creation"); err != nil {
-		return nil, err
-	}
+	key := pw.buildScanKey(request.RootTarget)
 
 	transformedResults, err := pw.transformRecords(ctx, request)
 	if err != nil {
@@ -102,21 +101,15 @@ func (pw *ParquetWriter) writeProbeResults(ctx stdcontext.Context, request Write
 		return nil, err
 	}
 
-	recordsWritten, err := pw.writeToParquetFile(filePath, transformedResults)
+	recordsWritten, info, err := pw.writeToParquetFile(ctx, key, transformedResults)
 	if err != nil {
 		return nil, err
 	}
 
-	fileInfo, _ := os.Stat(filePath)
-	fileSize := int64(0)
-	if fileInfo != nil {
-		fileSize = fileInfo.Size()
-	}
-
 	return &WriteResult{
-		FilePath:       filePath,
+		FilePath:       pw.blob.Location(key),
 		RecordsWritten: recordsWritten,
-		FileSize:       fileSize,
+		FileSize:       info.Size,
 		WriteTime:      time.Since(startTime),
 	}, nil
 }
@@ -143,24 +136,14 @@ func (pw *ParquetWriter) checkCancellation(ctx stdcontext.Context, operation str
 	return nil
 }
 
-// prepareOutputFile prepares the output directory and file path
-func (pw *ParquetWriter) prepareOutputFile(hostname string) (string, error) {
-	sanitizedHostname := urlhandler.SanitizeFilename(hostname)
-
-	scanOutputDir := filepath.Join(pw.config.ParquetBasePath, "scan")
-	if err := os.MkdirAll(scanOutputDir, 0755); err != nil {
-		return "", errorwrapper.WrapError(err, "failed to create scan-specific Parquet directory: "+scanOutputDir)
-	}
-
-	fileName := fmt.Sprintf("%s.parquet", sanitizedHostname)
-	filePath := filepath.Join(scanOutputDir, fileName)
-
-	return filePath, nil
+// buildScanKey returns the storage key of a target's Parquet file
+func (pw *ParquetWriter) buildScanKey(hostname string) string {
+	return blobKey(scanBlobDir, fmt.Sprintf("%s.parquet", urlhandler.SanitizeFilename(hostname)))
 }
 
 // transformRecords transforms probe results to parquet format
 func (pw *ParquetWriter) transformRecords(ctx stdcontext.Context, request WriteRequest) ([]ParquetProbeResult, error) {
-	transformer := NewRecordTransformer(pw.logger)
+	transformer := NewRecordTransformer(pw.logger).WithResponseBodies(pw.config.ResponseBodies)
 	var parquetResults []ParquetProbeResult
 
 	for _, pr := range request.ProbeResults {
@@ -176,70 +159,137 @@ func (pw *ParquetWriter) transformRecords(ctx stdcontext.Context, request WriteR
 	return parquetResults, nil
 }
 
-// writeToParquetFile writes the transformed results to a Parquet file
-func (pw *ParquetWriter) writeToParquetFile(filePath string, parquetResults []ParquetProbeResult) (int, error) {
-	// pw.logger.Info().
-	// 	Str("file_path", filePath).
-	// 	Int("probe_count", len(parquetResults)).
-	// 	Msg("Writing probe results to Parquet file")
-
-	file, err := pw.createParquetFile(filePath)
+// writeToParquetFile encodes the results into the key's object. The storage backend only replaces the
+// previous object once encoding succeeded, so a crash mid-write leaves it intact instead of truncated.
+func (pw *ParquetWriter) writeToParquetFile(ctx stdcontext.Context, key string, parquetResults []ParquetProbeResult) (int, BlobInfo, error) {
+	recordsWritten := 0
+	info, err := pw.blob.Write(ctx, key, func(w io.Writer) error {
+		n, err := pw.encodeParquetFile(w, parquetResults)
+		recordsWritten = n
+		return err
+	})
 	if err != nil {
-		return 0, err
+		return 0, BlobInfo{}, errorwrapper.WrapError(err, "failed to store parquet file: "+pw.blob.Location(key))
 	}
-	defer func() {
-		err := file.Close()
+	return recordsWritten, info, nil
+}
+
+// encodeParquetFile writes all records to w and closes the Parquet writer, which flushes the footer
+func (pw *ParquetWriter) encodeParquetFile(w io.Writer, parquetResults []ParquetProbeResult) (int, error) {
+	if pw.projectedSchema != nil {
+		recordsWritten, err := pw.writeProjectedRecords(w, parquetResults)
 		if err != nil {
-			pw.logger.Error().Err(err).Str("file", filePath).Msg("Failed to close Parquet file")
+			return 0, errorwrapper.WrapError(err, "failed to write probe results to parquet file")
 		}
-	}()
+		return recordsWritten, nil
+	}
 
-	writer, err := pw.createParquetWriter(file)
+	writer, err := pw.createParquetWriter(w)
 	if err != nil {
 		return 0, err
 	}
-	defer func() {
-		err := writer.Close()
-		if err != nil {
-			pw.logger.Error().Err(err).Str("file", filePath).Msg("Failed to close Parquet writer")
-		}
-	}()
 
 	recordsWritten, err := pw.writeRecords(writer, parquetResults)
 	if err != nil {
+		_ = writer.Close()
 		return 0, errorwrapper.WrapError(err, "failed to write probe results to parquet file")
 	}
+	if err := writer.Close(); err != nil {
+		return 0, errorwrapper.WrapError(err, "failed to close parquet writer")
+	}
 
 	return recordsWritten, nil
 }
 
-// createParquetFile creates and opens a new Parquet file for writing
-func (pw *ParquetWriter) createParquetFile(filePath string) (*os.File, error) {
-	file, err := os.Create(filePath)
-	if err != nil {
-		return nil, errorwrapper.WrapError(err, "failed to create/truncate parquet file: "+filePath)
+// createParquetWriter creates a configured Parquet writer
+func (pw *ParquetWriter) createParquetWriter(w io.Writer) (*parquet.GenericWriter[ParquetProbeResult], error) {
+	options := append([]parquet.WriterOption{pw.getCompressionOption()}, pw.getLayoutOptions()...)
+	writer := parquet.NewGenericWriter[ParquetProbeResult](w, options...)
+	return writer, nil
+}
+
+// writeProjectedRecords writes records using the projected schema, converting them in batches
+func (pw *ParquetWriter) writeProjectedRecords(w io.Writer, parquetResults []ParquetProbeResult) (int, error) {
+	options := append([]parquet.WriterOption{pw.projectedSchema, pw.getCompressionOption()}, pw.getLayoutOptions()...)
+	writer := parquet.NewWriter(w, options...)
+
+	fullSchema := parquet.SchemaOf(ParquetProbeResult{})
+	batchSize := max(pw.writerConfig.BatchSize, 1)
+	rows := make([]parquet.Row, 0, min(batchSize, len(parquetResults)))
+
+	recordsWritten := 0
+	for start := 0; start < len(parquetResults); start += batchSize {
+		end := min(start+batchSize, len(parquetResults))
+
+		rows = rows[:0]
+		for i := start; i < end; i++ {
+			rows = append(rows, fullSchema.Deconstruct(nil, &parquetResults[i]))
+		}
+		if _, err := pw.projection.Convert(rows); err != nil {
+			_ = writer.Close()
+			return recordsWritten, err
+		}
+
+		n, err := writer.WriteRows(rows)
+		recordsWritten += n
+		if err != nil {
+			_ = writer.Close()
+			return recordsWritten, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return recordsWritten, err
 	}
-	return file, nil
+	return recordsWritten, nil
 }
 
-// createParquetWriter creates a configured Parquet writer
-func (pw *ParquetWriter) createParquetWriter(file *os.File) (*parquet.GenericWriter[ParquetProbeResult], error) {
-	compressionOption := pw.getCompressionOption()
-	writer := parquet.NewGenericWriter[ParquetProbeResult](file, compressionOption)
-	return writer, nil
+// getLayoutOptions returns row group and page sizing options; unset values keep the library defaults
+func (pw *ParquetWriter) getLayoutOptions() []parquet.WriterOption {
+	var options []parquet.WriterOption
+	if pw.config.RowGroupSize > 0 {
+		options = append(options, parquet.MaxRowsPerRowGroup(int64(pw.config.RowGroupSize)))
+	}
+	if pw.config.PageSize > 0 {
+		options = append(options, parquet.PageBufferSize(pw.config.PageSize))
+	}
+	return options
 }
 
 // getCompressionOption returns the compression option based on configuration
 func (pw *ParquetWriter) getCompressionOption() parquet.WriterOption {
+	level := pw.writerConfig.CompressionLevel
+
 	switch pw.writerConfig.CompressionType {
 	case "gzip":
+		if level > 0 {
+			return parquet.Compression(&gzip.Codec{Level: level})
+		}
 		return parquet.Compression(&parquet.Gzip)
 	case "snappy":
 		return parquet.Compression(&parquet.Snappy)
-	case "zstd":
+	case "none":
+		return parquet.Compression(&parquet.Uncompressed)
+	default: // zstd, also used for unknown codecs
+		if level > 0 {
+			return parquet.Compression(&zstd.Codec{Level: zstdEncoderLevel(level)})
+		}
 		return parquet.Compression(&parquet.Zstd)
+	}
+}
+
+// zstdEncoderLevel maps a standard zstd level (1-22) onto the encoder's speed presets,
+// the same way the reference zstd CLI levels are grouped
+func zstdEncoderLevel(level int) zstd.Level {
+	switch {
+	case level < 3:
+		return zstd.SpeedFastest
+	case level < 6:
+		return zstd.SpeedDefault
+	case level < 10:
+		return zstd.SpeedBetterCompression
 	default:
-		return parquet.Compression(&parquet.Zstd) // Default to Zstd
+		return zstd.SpeedBestCompression
 	}
 }
 
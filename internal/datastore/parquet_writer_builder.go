@@ -1,6 +1,8 @@
 package datastore
 
 import (
+	"strings"
+
 	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
 	"github.com/aleister1102/monsterinc/internal/common/filemanager"
 	"github.com/aleister1102/monsterinc/internal/config"
@@ -12,6 +14,7 @@ type ParquetWriterBuilder struct {
 	config       *config.StorageConfig
 	logger       zerolog.Logger
 	writerConfig ParquetWriterConfig
+	blob         Blob
 }
 
 // NewParquetWriterBuilder creates a new ParquetWriterBuilder
@@ -28,6 +31,20 @@ func (b *ParquetWriterBuilder) WithStorageConfig(cfg *config.StorageConfig) *Par
 	return b
 }
 
+// WithExtractionConfig omits the columns of every httpx extraction disabled in cfg from written files
+func (b *ParquetWriterBuilder) WithExtractionConfig(cfg *config.HttpxRunnerConfig) *ParquetWriterBuilder {
+	if cfg != nil {
+		b.writerConfig.OmitColumns = OmittedColumns(*cfg)
+	}
+	return b
+}
+
+// WithBlob sets the storage backend; by default it is created from the storage configuration
+func (b *ParquetWriterBuilder) WithBlob(blob Blob) *ParquetWriterBuilder {
+	b.blob = blob
+	return b
+}
+
 // WithWriterConfig sets the writer configuration
 func (b *ParquetWriterBuilder) WithWriterConfig(cfg ParquetWriterConfig) *ParquetWriterBuilder {
 	b.writerConfig = cfg
@@ -44,12 +61,47 @@ func (b *ParquetWriterBuilder) Build() (*ParquetWriter, error) {
 		b.logger.Warn().Msg("ParquetBasePath is empty in config")
 	}
 
+	if b.config.CompressionCodec != "" {
+		b.writerConfig.CompressionType = strings.ToLower(b.config.CompressionCodec)
+	}
+	if b.config.CompressionLevel != 0 {
+		b.writerConfig.CompressionLevel = b.config.CompressionLevel
+		if _, _, supported := b.config.CompressionLevelRange(); !supported {
+			b.logger.Warn().
+				Str("compression_codec", b.config.CompressionCodec).
+				Int("compression_level", b.config.CompressionLevel).
+				Msg("Compression level is not supported by this codec and will be ignored")
+		}
+	}
+
+	blob := b.blob
+	if blob == nil {
+		var err error
+		if blob, err = NewBlob(b.config, b.logger); err != nil {
+			return nil, err
+		}
+	}
+
 	fileManager := filemanager.NewFileManager(b.logger)
 
-	return &ParquetWriter{
+	writer := &ParquetWriter{
 		config:       b.config,
 		logger:       b.logger,
 		fileManager:  fileManager,
 		writerConfig: b.writerConfig,
-	}, nil
+		blob:         blob,
+	}
+
+	if len(b.writerConfig.OmitColumns) > 0 {
+		schema, conversion, err := probeResultSchemaWithout(b.writerConfig.OmitColumns)
+		if err != nil {
+			return nil, errorwrapper.WrapError(err, "failed to build Parquet schema without omitted columns")
+		}
+		writer.projectedSchema = schema
+		writer.projection = conversion
+
+		b.logger.Info().Strs("omitted_columns", b.writerConfig.OmitColumns).Msg("Columns of disabled extractions are omitted from Parquet files")
+	}
+
+	return writer, nil
 }
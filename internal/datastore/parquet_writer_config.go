@@ -3,7 +3,9 @@ package datastore
 // ParquetWriterConfig holds configuration for ParquetWriter
 type ParquetWriterConfig struct {
 	CompressionType  string
+	CompressionLevel int // 0 uses the codec default
 	BatchSize        int
+	OmitColumns      []string // Top-level columns left out of the written schema, see OmittedColumns
 	EnableValidation bool
 }
 
@@ -0,0 +1,140 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/gzip"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cutOffBlob fails every write, stopping the stream after limit bytes like a disk filling up or a dropped connection
+type cutOffBlob struct {
+	Blob
+	limit int64
+}
+
+var errCutOff = errors.New("write cut off")
+
+func (cb cutOffBlob) Write(ctx context.Context, key string, write func(io.Writer) error) (BlobInfo, error) {
+	return cb.Blob.Write(ctx, key, func(w io.Writer) error {
+		if err := write(&cutOffWriter{w: w, remaining: cb.limit}); err != nil {
+			return err
+		}
+		return errCutOff
+	})
+}
+
+type cutOffWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (cw *cutOffWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > cw.remaining {
+		n, _ := cw.w.Write(p[:cw.remaining])
+		cw.remaining = 0
+		return n, errCutOff
+	}
+	cw.remaining -= int64(len(p))
+	return cw.w.Write(p)
+}
+
+func TestParquetWriter_FailedWriteKeepsPreviousFile(t *testing.T) {
+	writer, reader, baseDir := newTestParquetStore(t)
+	writeProbeResults(t, writer, "example.com", "https://example.com/", "https://example.com/login")
+
+	livePath := filepath.Join(baseDir, scanBlobDir, "example.com.parquet")
+	before, err := os.ReadFile(livePath)
+	require.NoError(t, err)
+
+	storageConfig := config.NewDefaultStorageConfig()
+	storageConfig.ParquetBasePath = baseDir
+	failing, err := NewParquetWriterBuilder(zerolog.Nop()).
+		WithStorageConfig(&storageConfig).
+		WithBlob(cutOffBlob{Blob: NewLocalBlob(baseDir), limit: 64}).
+		Build()
+	require.NoError(t, err)
+
+	err = failing.Write(context.Background(), []httpxrunner.ProbeResult{{InputURL: "https://example.com/other", StatusCode: 500}}, "session-2", "example.com")
+	require.ErrorIs(t, err, errCutOff)
+
+	after, err := os.ReadFile(livePath)
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "the live file is untouched by the failed write")
+
+	entries, err := os.ReadDir(filepath.Join(baseDir, scanBlobDir))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the partial temporary file is removed")
+
+	results, _, err := reader.FindAllProbeResultsForTarget("example.com")
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+}
+
+func TestParquetWriter_CompressionCodec(t *testing.T) {
+	tests := []struct {
+		codec     string
+		level     int
+		wantCodec format.CompressionCodec
+		wantLevel int
+	}{
+		{codec: "", wantCodec: format.Zstd},
+		{codec: "zstd", level: 19, wantCodec: format.Zstd, wantLevel: int(zstd.SpeedBestCompression)},
+		{codec: "GZIP", level: 5, wantCodec: format.Gzip, wantLevel: 5},
+		{codec: "gzip", wantCodec: format.Gzip, wantLevel: gzip.DefaultCompression},
+		{codec: "Snappy", wantCodec: format.Snappy},
+		{codec: "none", wantCodec: format.Uncompressed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.codec+"/"+strconv.Itoa(tt.level), func(t *testing.T) {
+			baseDir := t.TempDir()
+			storageConfig := config.NewDefaultStorageConfig()
+			storageConfig.ParquetBasePath = baseDir
+			storageConfig.CompressionCodec = tt.codec
+			storageConfig.CompressionLevel = tt.level
+			writer, err := NewParquetWriterBuilder(zerolog.Nop()).WithStorageConfig(&storageConfig).Build()
+			require.NoError(t, err)
+			writeProbeResults(t, writer, "example.com", "https://example.com/")
+
+			file, err := os.Open(filepath.Join(baseDir, scanBlobDir, "example.com.parquet"))
+			require.NoError(t, err)
+			defer func() { _ = file.Close() }()
+			info, err := file.Stat()
+			require.NoError(t, err)
+			parquetFile, err := parquet.OpenFile(file, info.Size())
+			require.NoError(t, err)
+
+			rowGroups := parquetFile.Metadata().RowGroups
+			require.NotEmpty(t, rowGroups)
+			for _, column := range rowGroups[0].Columns {
+				assert.Equal(t, tt.wantCodec, column.MetaData.Codec, "column %v", column.MetaData.PathInSchema)
+			}
+
+			// The level is not recorded in the file, so check the codec the writer compressed with
+			writerConfig := parquet.DefaultWriterConfig()
+			writer.getCompressionOption().ConfigureWriter(writerConfig)
+			switch codec := writerConfig.Compression.(type) {
+			case *gzip.Codec:
+				assert.Equal(t, tt.wantLevel, codec.Level)
+			case *zstd.Codec:
+				if tt.wantLevel != 0 {
+					assert.Equal(t, zstd.Level(tt.wantLevel), codec.Level)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package datastore
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/parquet-go/parquet-go"
+)
+
+// StatusCodeRange is an inclusive range of HTTP status codes (e.g. 500-599)
+type StatusCodeRange struct {
+	Min int
+	Max int
+}
+
+// ProbeResultFilter selects stored probe results. Empty fields match everything;
+// all non-empty fields must match for a result to be returned.
+type ProbeResultFilter struct {
+	// Limit the query to one target's Parquet file; empty queries every stored target
+	RootTargetURL string
+	// Result status code must fall in at least one range
+	StatusCodeRanges []StatusCodeRange
+	// Hostname of the final (or input) URL must match at least one glob (e.g. "*.example.com")
+	HostGlobs []string
+	// Each entry must be a case-insensitive substring of at least one detected technology
+	TechnologiesContain []string
+	// Only results stored by this scan session
+	ScanSessionID string
+}
+
+// QueryProbeResults scans stored Parquet files and returns the probe results matching filter.
+// Row groups whose status code statistics cannot satisfy the filter are skipped without being read,
+// and rows are filtered while streaming so non-matching records are never accumulated.
+func (pr *ParquetReader) QueryProbeResults(filter ProbeResultFilter) ([]httpxrunner.ProbeResult, error) {
+	if err := pr.validateConfiguration(); err != nil {
+		return nil, err
+	}
+
+	keys, err := pr.queryKeys(filter.RootTargetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []httpxrunner.ProbeResult
+	for _, key := range keys {
+		matched, err := pr.queryProbeResultsFromFile(key, filter)
+		if err != nil {
+			pr.logger.Warn().Err(err).Str("file", pr.blob.Location(key)).Msg("Skipping unreadable Parquet file during query")
+			continue
+		}
+		results = append(results, matched...)
+	}
+
+	pr.logger.Debug().
+		Int("files_scanned", len(keys)).
+		Int("match_count", len(results)).
+		Msg("Probe result query completed")
+
+	return results, nil
+}
+
+// queryKeys returns the stored Parquet files covered by a query
+func (pr *ParquetReader) queryKeys(rootTargetURL string) ([]string, error) {
+	if rootTargetURL != "" {
+		key, err := pr.buildParquetKey(rootTargetURL)
+		if err != nil {
+			return nil, err
+		}
+		fileInfo, err := pr.statParquetFile(key)
+		if err != nil || fileInfo == nil {
+			return nil, err
+		}
+		return []string{key}, nil
+	}
+
+	return pr.listParquetKeys()
+}
+
+// queryProbeResultsFromFile streams the row groups of one file through the filter
+func (pr *ParquetReader) queryProbeResultsFromFile(key string, filter ProbeResultFilter) (results []httpxrunner.ProbeResult, err error) {
+	filePath := pr.blob.Location(key)
+	file, err := pr.openParquetFile(key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			pr.logger.Error().Err(closeErr).Str("file", filePath).Msg("Failed to close Parquet file")
+		}
+	}()
+
+	if err := pr.validateParquetFile(file, filePath); err != nil {
+		return nil, nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			results = nil
+			err = fmt.Errorf("panic reading parquet file: %v", r)
+		}
+	}()
+
+	parquetFile, err := parquet.OpenFile(file, file.Size())
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to open Parquet file: "+filePath)
+	}
+
+	statusColumn := -1
+	if leaf, ok := parquetFile.Schema().Lookup("status_code"); ok {
+		statusColumn = leaf.ColumnIndex
+	}
+
+	skipped := 0
+	for _, rowGroup := range parquetFile.RowGroups() {
+		if !filter.rowGroupMayMatch(rowGroup, statusColumn) {
+			skipped++
+			continue
+		}
+
+		matched, err := pr.readMatchingRows(rowGroup, filter)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, matched...)
+	}
+
+	pr.logger.Debug().
+		Str("file", filePath).
+		Int("row_groups", len(parquetFile.RowGroups())).
+		Int("row_groups_skipped", skipped).
+		Int("match_count", len(results)).
+		Msg("Queried Parquet file")
+
+	return results, nil
+}
+
+// readMatchingRows reads a row group in batches, keeping only rows that match the filter
+func (pr *ParquetReader) readMatchingRows(rowGroup parquet.RowGroup, filter ProbeResultFilter) ([]httpxrunner.ProbeResult, error) {
+	missingLists := missingListColumns(rowGroup.Schema())
+	reader := parquet.NewGenericRowGroupReader[ParquetProbeResult](rowGroup)
+	defer func() {
+		if err := reader.Close(); err != nil {
+			pr.logger.Error().Err(err).Msg("Failed to close Parquet row group reader")
+		}
+	}()
+
+	var results []httpxrunner.ProbeResult
+	const batchSize = 1000
+	rows := make([]ParquetProbeResult, batchSize)
+
+	for {
+		n, err := reader.Read(rows)
+		if err != nil && err != io.EOF {
+			return nil, errorwrapper.WrapError(err, "failed to read rows from Parquet row group")
+		}
+
+		for i := 0; i < n; i++ {
+			if filter.ScanSessionID != "" && StringFromPtr(rows[i].ScanSessionID) != filter.ScanSessionID {
+				continue
+			}
+			rows[i].clearListColumns(missingLists)
+			probeResult := rows[i].ToProbeResult(pr.logger)
+			if filter.Matches(probeResult) {
+				results = append(results, probeResult)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// rowGroupMayMatch uses the status code column statistics to rule out row groups before reading them
+func (f ProbeResultFilter) rowGroupMayMatch(rowGroup parquet.RowGroup, statusColumn int) bool {
+	if len(f.StatusCodeRanges) == 0 || statusColumn < 0 {
+		return true
+	}
+
+	chunks := rowGroup.ColumnChunks()
+	if statusColumn >= len(chunks) {
+		return true
+	}
+
+	chunk, ok := chunks[statusColumn].(*parquet.FileColumnChunk)
+	if !ok {
+		return true
+	}
+
+	minValue, maxValue, ok := chunk.Bounds()
+	if !ok {
+		// No statistics (e.g. all nulls): status 0 may still be selected by a range
+		return f.matchesStatusCode(0) || chunk.NullCount() < chunk.NumValues()
+	}
+
+	minStatus, maxStatus := int(minValue.Int32()), int(maxValue.Int32())
+	for _, r := range f.StatusCodeRanges {
+		if r.Min <= maxStatus && r.Max >= minStatus {
+			return true
+		}
+	}
+	// Null status codes read back as 0
+	return chunk.NullCount() > 0 && f.matchesStatusCode(0)
+}
+
+// Matches reports whether a probe result satisfies the filter (ScanSessionID is checked on the stored row)
+func (f ProbeResultFilter) Matches(result httpxrunner.ProbeResult) bool {
+	if len(f.StatusCodeRanges) > 0 && !f.matchesStatusCode(result.StatusCode) {
+		return false
+	}
+	if len(f.HostGlobs) > 0 && !f.matchesHost(result) {
+		return false
+	}
+	return f.matchesTechnologies(result.Technologies)
+}
+
+// matchesStatusCode checks the status code against the configured ranges
+func (f ProbeResultFilter) matchesStatusCode(statusCode int) bool {
+	for _, r := range f.StatusCodeRanges {
+		if statusCode >= r.Min && statusCode <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesHost checks the result hostname against the configured globs
+func (f ProbeResultFilter) matchesHost(result httpxrunner.ProbeResult) bool {
+	rawURL := result.FinalURL
+	if rawURL == "" {
+		rawURL = result.InputURL
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+	hostname := strings.ToLower(parsed.Hostname())
+
+	for _, glob := range f.HostGlobs {
+		if matched, err := path.Match(strings.ToLower(glob), hostname); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTechnologies requires every configured substring to appear in some technology name
+func (f ProbeResultFilter) matchesTechnologies(technologies []httpxrunner.Technology) bool {
+	for _, wanted := range f.TechnologiesContain {
+		wanted = strings.ToLower(wanted)
+		found := false
+		for _, tech := range technologies {
+			if strings.Contains(strings.ToLower(tech.Name), wanted) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package datastore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryRow builds a stored probe result; status 0 is stored as null
+func queryRow(targetURL string, status int32, session string, technologies ...string) ParquetProbeResult {
+	row := ParquetProbeResult{
+		OriginalURL:   targetURL,
+		ScanSessionID: &session,
+		Technologies:  technologies,
+	}
+	if status != 0 {
+		row.StatusCode = &status
+	}
+	return row
+}
+
+// writeQueryFile stores one target's Parquet file with one row group per entry of rowGroups
+func writeQueryFile(t *testing.T, baseDir, name string, rowGroups ...[]ParquetProbeResult) string {
+	t.Helper()
+	filePath := filepath.Join(baseDir, scanBlobDir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+	file, err := os.Create(filePath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	writer := parquet.NewGenericWriter[ParquetProbeResult](file)
+	for _, rows := range rowGroups {
+		_, err := writer.Write(rows)
+		require.NoError(t, err)
+		require.NoError(t, writer.Flush())
+	}
+	require.NoError(t, writer.Close())
+	return filePath
+}
+
+func newTestQueryReader(t *testing.T, baseDir string) *ParquetReader {
+	t.Helper()
+	storageConfig := config.NewDefaultStorageConfig()
+	storageConfig.ParquetBasePath = baseDir
+	reader, err := NewParquetReaderBuilder(zerolog.Nop()).WithStorageConfig(&storageConfig).Build()
+	require.NoError(t, err)
+	return reader
+}
+
+func queriedURLs(results []httpxrunner.ProbeResult) []string {
+	urls := make([]string, 0, len(results))
+	for _, result := range results {
+		urls = append(urls, result.InputURL)
+	}
+	return urls
+}
+
+func TestQueryProbeResults_Filters(t *testing.T) {
+	baseDir := t.TempDir()
+	writeQueryFile(t, baseDir, "example.com.parquet",
+		[]ParquetProbeResult{
+			queryRow("https://example.com/", 200, "s1", "Nginx", "PHP"),
+			queryRow("https://api.example.com/v1", 200, "s2", "nginx"),
+		},
+		[]ParquetProbeResult{
+			queryRow("https://example.com/admin", 500, "s2", "PHP"),
+			queryRow("https://example.com/down", 0, "s2"),
+		},
+	)
+	writeQueryFile(t, baseDir, "other.org.parquet", []ParquetProbeResult{
+		queryRow("https://other.org/", 503, "s2", "Apache"),
+	})
+	reader := newTestQueryReader(t, baseDir)
+
+	tests := []struct {
+		name   string
+		filter ProbeResultFilter
+		want   []string
+	}{
+		{
+			name:   "status range across targets",
+			filter: ProbeResultFilter{StatusCodeRanges: []StatusCodeRange{{Min: 500, Max: 599}}},
+			want:   []string{"https://example.com/admin", "https://other.org/"},
+		},
+		{
+			name:   "null status reads back as 0",
+			filter: ProbeResultFilter{StatusCodeRanges: []StatusCodeRange{{Min: 0, Max: 0}}},
+			want:   []string{"https://example.com/down"},
+		},
+		{
+			name:   "host glob",
+			filter: ProbeResultFilter{HostGlobs: []string{"*.EXAMPLE.com"}},
+			want:   []string{"https://api.example.com/v1"},
+		},
+		{
+			name:   "every technology substring must match",
+			filter: ProbeResultFilter{TechnologiesContain: []string{"nginx", "php"}},
+			want:   []string{"https://example.com/"},
+		},
+		{
+			name:   "session and root target",
+			filter: ProbeResultFilter{RootTargetURL: "example.com", ScanSessionID: "s2"},
+			want:   []string{"https://api.example.com/v1", "https://example.com/admin", "https://example.com/down"},
+		},
+		{
+			name:   "unknown root target",
+			filter: ProbeResultFilter{RootTargetURL: "missing.net"},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := reader.QueryProbeResults(tt.filter)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, queriedURLs(results))
+		})
+	}
+}
+
+func TestProbeResultFilter_RowGroupMayMatch(t *testing.T) {
+	baseDir := t.TempDir()
+	filePath := writeQueryFile(t, baseDir, "example.com.parquet",
+		[]ParquetProbeResult{queryRow("https://example.com/a", 200, "s1"), queryRow("https://example.com/b", 204, "s1")},
+		[]ParquetProbeResult{queryRow("https://example.com/c", 404, "s1"), queryRow("https://example.com/d", 0, "s1")},
+		[]ParquetProbeResult{queryRow("https://example.com/e", 502, "s1")},
+	)
+
+	file, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	require.NoError(t, err)
+	parquetFile, err := parquet.OpenFile(file, info.Size())
+	require.NoError(t, err)
+	require.Len(t, parquetFile.RowGroups(), 3)
+
+	leaf, ok := parquetFile.Schema().Lookup("status_code")
+	require.True(t, ok)
+
+	// mayMatch lists which row groups the filter reads
+	mayMatch := func(filter ProbeResultFilter) []bool {
+		var read []bool
+		for _, rowGroup := range parquetFile.RowGroups() {
+			read = append(read, filter.rowGroupMayMatch(rowGroup, leaf.ColumnIndex))
+		}
+		return read
+	}
+
+	assert.Equal(t, []bool{true, true, true}, mayMatch(ProbeResultFilter{}), "no status filter reads everything")
+	assert.Equal(t, []bool{false, false, true}, mayMatch(ProbeResultFilter{StatusCodeRanges: []StatusCodeRange{{Min: 500, Max: 599}}}))
+	assert.Equal(t, []bool{true, false, false}, mayMatch(ProbeResultFilter{StatusCodeRanges: []StatusCodeRange{{Min: 201, Max: 299}}}),
+		"a range inside the min/max bounds cannot be ruled out")
+	assert.Equal(t, []bool{false, true, false}, mayMatch(ProbeResultFilter{StatusCodeRanges: []StatusCodeRange{{Min: 0, Max: 0}}}),
+		"only the group with null status codes can hold status 0")
+
+	// Files without a status_code column cannot be pruned
+	assert.True(t, ProbeResultFilter{StatusCodeRanges: []StatusCodeRange{{Min: 500, Max: 599}}}.rowGroupMayMatch(parquetFile.RowGroups()[0], -1))
+}
@@ -4,13 +4,15 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 	"github.com/rs/zerolog"
 )
 
 // RecordTransformer handles transformation of records
 type RecordTransformer struct {
-	logger zerolog.Logger
+	logger     zerolog.Logger
+	bodyConfig config.ResponseBodyConfig
 }
 
 // NewRecordTransformer creates a new RecordTransformer
@@ -20,11 +22,19 @@ func NewRecordTransformer(logger zerolog.Logger) *RecordTransformer {
 	}
 }
 
+// WithResponseBodies sets whether and how response bodies are stored
+func (rt *RecordTransformer) WithResponseBodies(cfg config.ResponseBodyConfig) *RecordTransformer {
+	rt.bodyConfig = cfg
+	return rt
+}
+
 // TransformToParquetResult converts a models.ProbeResult to a models.ParquetProbeResult
 func (rt *RecordTransformer) TransformToParquetResult(pr httpxrunner.ProbeResult, scanTime time.Time, scanSessionID string) ParquetProbeResult {
 	headersJSON := rt.marshalHeaders(pr.Headers, pr.InputURL)
 	techNames := rt.extractTechnologyNames(pr.Technologies)
 	firstSeen := rt.determineFirstSeenTimestamp(pr.OldestScanTimestamp, scanTime)
+	lastSeen := rt.determineLastSeenTimestamp(pr, scanTime)
+	bodyGzip, bodyTruncated := rt.compressBody(pr.Body, pr.InputURL)
 
 	return ParquetProbeResult{
 		OriginalURL:   pr.InputURL,
@@ -45,8 +55,32 @@ func (rt *RecordTransformer) TransformToParquetResult(pr httpxrunner.ProbeResult
 		ScanSessionID:      StringPtrOrNil(scanSessionID),
 		ScanTimestamp:      scanTime.UnixMilli(),
 		FirstSeenTimestamp: TimePtrToUnixMilliOptional(firstSeen),
-		LastSeenTimestamp:  TimePtrToUnixMilliOptional(scanTime),
+		LastSeenTimestamp:  TimePtrToUnixMilliOptional(lastSeen),
+		MissedScans:        Int32PtrOrNilZero(int32(pr.MissedScans)),
+
+		TLSCertNotAfter: TimePtrToUnixMilliOptional(pr.TLSCertNotAfter),
+		TLSCertIssuer:   StringPtrOrNil(pr.TLSCertIssuer),
+		TLSCertSANs:     pr.TLSCertSANs,
+
+		Tags: pr.Tags,
+
+		ResponseBodyGzip:      bodyGzip,
+		ResponseBodyTruncated: bodyTruncated,
+	}
+}
+
+// compressBody returns the stored form of a response body, or nils when body storage is disabled
+func (rt *RecordTransformer) compressBody(body string, inputURL string) ([]byte, *bool) {
+	if !rt.bodyConfig.Enabled || body == "" {
+		return nil, nil
+	}
+
+	compressed, truncated, err := compressResponseBody(body, rt.bodyConfig.MaxSizeBytes())
+	if err != nil {
+		rt.logger.Error().Err(err).Str("url", inputURL).Msg("Failed to compress response body")
+		return nil, nil
 	}
+	return compressed, &truncated
 }
 
 // marshalHeaders converts headers map to JSON string pointer
@@ -81,3 +115,12 @@ func (rt *RecordTransformer) determineFirstSeenTimestamp(oldestScanTimestamp tim
 	}
 	return oldestScanTimestamp
 }
+
+// determineLastSeenTimestamp keeps the historical last-seen time for URLs carried
+// forward as "old"; everything else was seen in this scan
+func (rt *RecordTransformer) determineLastSeenTimestamp(pr httpxrunner.ProbeResult, scanTime time.Time) time.Time {
+	if pr.URLStatus == "old" && !pr.Timestamp.IsZero() {
+		return pr.Timestamp
+	}
+	return scanTime
+}
@@ -0,0 +1,48 @@
+package datastore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// compressResponseBody truncates body to maxSize bytes and gzip-compresses it.
+// It returns nil for an empty body.
+func compressResponseBody(body string, maxSize int) (compressed []byte, truncated bool, err error) {
+	if body == "" {
+		return nil, false, nil
+	}
+	if maxSize > 0 && len(body) > maxSize {
+		body = body[:maxSize]
+		truncated = true
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(body)); err != nil {
+		return nil, false, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), truncated, nil
+}
+
+// decompressResponseBody reverses compressResponseBody
+func decompressResponseBody(compressed []byte) (string, error) {
+	if len(compressed) == 0 {
+		return "", nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", err
+	}
+	defer gz.Close()
+
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
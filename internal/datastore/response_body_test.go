@@ -0,0 +1,129 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressResponseBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		maxSize       int
+		want          string
+		wantTruncated bool
+	}{
+		{name: "empty body", body: "", maxSize: 16, want: ""},
+		{name: "under the cap", body: "<html>ok</html>", maxSize: 16, want: "<html>ok</html>"},
+		{name: "exactly the cap", body: strings.Repeat("a", 16), maxSize: 16, want: strings.Repeat("a", 16)},
+		{name: "over the cap", body: strings.Repeat("a", 20), maxSize: 16, want: strings.Repeat("a", 16), wantTruncated: true},
+		{name: "no cap", body: strings.Repeat("a", 20), maxSize: 0, want: strings.Repeat("a", 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compressed, truncated, err := compressResponseBody(tt.body, tt.maxSize)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTruncated, truncated)
+			if tt.body == "" {
+				assert.Nil(t, compressed, "nothing is stored for an empty body")
+			}
+
+			body, err := decompressResponseBody(compressed)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, body)
+		})
+	}
+}
+
+func TestDecompressResponseBody_Corrupt(t *testing.T) {
+	_, err := decompressResponseBody([]byte("not gzip"))
+	assert.Error(t, err)
+
+	compressed, _, err := compressResponseBody(strings.Repeat("body ", 100), 0)
+	require.NoError(t, err)
+	_, err = decompressResponseBody(compressed[:len(compressed)/2])
+	assert.Error(t, err, "a cut-off stream is reported")
+}
+
+func TestToProbeResult_LogsCorruptBody(t *testing.T) {
+	var logs bytes.Buffer
+	row := ParquetProbeResult{OriginalURL: "https://example.com/page", ResponseBodyGzip: []byte("not gzip")}
+
+	result := row.ToProbeResult(zerolog.New(&logs))
+	assert.Equal(t, "https://example.com/page", result.InputURL, "the rest of the record is kept")
+	assert.Empty(t, result.Body)
+	assert.Contains(t, logs.String(), "https://example.com/page")
+}
+
+func TestParquetWriter_ResponseBodyRoundTrip(t *testing.T) {
+	longBody := strings.Repeat("x", 1500)
+	probes := []httpxrunner.ProbeResult{
+		{InputURL: "https://example.com/", StatusCode: 200, Body: "<html>home</html>", RootTargetURL: "example.com"},
+		{InputURL: "https://example.com/large", StatusCode: 200, Body: longBody, RootTargetURL: "example.com"},
+		{InputURL: "https://example.com/empty", StatusCode: 204, RootTargetURL: "example.com"},
+	}
+
+	store := func(t *testing.T, bodies config.ResponseBodyConfig) (map[string]string, []ParquetProbeResult) {
+		t.Helper()
+		baseDir := t.TempDir()
+		storageConfig := config.NewDefaultStorageConfig()
+		storageConfig.ParquetBasePath = baseDir
+		storageConfig.ResponseBodies = bodies
+		writer, err := NewParquetWriter(&storageConfig, zerolog.Nop())
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(context.Background(), probes, "session", "example.com"))
+
+		reader, err := NewParquetReaderBuilder(zerolog.Nop()).WithStorageConfig(&storageConfig).Build()
+		require.NoError(t, err)
+		results, _, err := reader.FindAllProbeResultsForTarget("example.com")
+		require.NoError(t, err)
+		bodiesByURL := make(map[string]string)
+		for _, result := range results {
+			bodiesByURL[result.InputURL] = result.Body
+		}
+
+		rows, err := parquet.ReadFile[ParquetProbeResult](filepath.Join(baseDir, scanBlobDir, "example.com.parquet"))
+		require.NoError(t, err)
+		return bodiesByURL, rows
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		bodies, rows := store(t, config.ResponseBodyConfig{Enabled: true, MaxSizeKB: 1})
+		assert.Equal(t, "<html>home</html>", bodies["https://example.com/"])
+		assert.Equal(t, longBody[:1024], bodies["https://example.com/large"], "bodies are cut at max_size_kb")
+		assert.Empty(t, bodies["https://example.com/empty"])
+
+		truncated := make(map[string]*bool)
+		for _, row := range rows {
+			truncated[row.OriginalURL] = row.ResponseBodyTruncated
+		}
+		require.NotNil(t, truncated["https://example.com/large"])
+		assert.True(t, *truncated["https://example.com/large"])
+		require.NotNil(t, truncated["https://example.com/"])
+		assert.False(t, *truncated["https://example.com/"])
+		assert.Nil(t, truncated["https://example.com/empty"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		bodies, rows := store(t, config.ResponseBodyConfig{Enabled: false, MaxSizeKB: 1})
+		for url, body := range bodies {
+			assert.Empty(t, body, "no body is read back for %s", url)
+		}
+		require.Len(t, rows, len(probes))
+		for _, row := range rows {
+			assert.Empty(t, row.ResponseBodyGzip, "no body is stored for %s", row.OriginalURL)
+			assert.Nil(t, row.ResponseBodyTruncated)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// retentionTempFileGrace keeps temporary files young enough to belong to a write still in progress
+const retentionTempFileGrace = time.Hour
+
+// RetentionResult summarises one retention cleanup
+type RetentionResult struct {
+	Targets      int   // Targets with files under the scan directory
+	DeletedFiles int   // Archived files deleted
+	FreedBytes   int64 // Size of the deleted files
+}
+
+// RetentionCleaner deletes archived files under the scan directory according to storage_config.retention.
+// Archived files are the .corrupt backups made by the reader and the temporary files of interrupted
+// writes. A target's live <target>.parquet holds the latest record of every URL and is never deleted.
+// When the content archive is enabled, its snapshots are cleaned up with the same limits, per host.
+type RetentionCleaner struct {
+	blob     Blob
+	archiver *ContentArchiver
+	config   config.RetentionConfig
+	logger   zerolog.Logger
+	now      func() time.Time
+}
+
+// NewRetentionCleaner creates a cleaner for the configured storage backend
+func NewRetentionCleaner(storageConfig *config.StorageConfig, logger zerolog.Logger) (*RetentionCleaner, error) {
+	blob, err := NewBlob(storageConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &RetentionCleaner{
+		blob:     blob,
+		archiver: NewContentArchiver(storageConfig.ResponseBodies.Archive, logger),
+		config:   storageConfig.Retention,
+		logger:   logger.With().Str("module", "RetentionCleaner").Logger(),
+		now:      time.Now,
+	}, nil
+}
+
+// storedTarget is the live file of one target and its archived files, oldest first
+type storedTarget struct {
+	live     *BlobInfo
+	archives []BlobInfo
+}
+
+// Cleanup applies the retention policy once. Temporary files older than an hour and archived files older
+// than max_age_days are deleted first; then, while a target's files exceed max_total_size_mb, its oldest
+// archives are deleted. A target whose live file alone exceeds the cap keeps it.
+func (rc *RetentionCleaner) Cleanup(ctx context.Context) (RetentionResult, error) {
+	infos, err := rc.blob.List(ctx, scanBlobDir)
+	if err != nil {
+		return RetentionResult{}, errorwrapper.WrapError(err, "failed to list stored scan files")
+	}
+
+	now := rc.now()
+	targets := groupStoredTargets(infos)
+	result := RetentionResult{Targets: len(targets)}
+	maxAge, maxSize := rc.config.MaxAge(), rc.config.MaxTotalSizeBytes()
+
+	var errs []error
+	for _, target := range targets {
+		var kept []BlobInfo
+		var keptSize int64
+		if target.live != nil {
+			keptSize = target.live.Size
+		}
+
+		for _, archive := range target.archives {
+			if isTempBlob(archive.Key) {
+				if now.Sub(archive.ModTime) < retentionTempFileGrace {
+					keptSize += archive.Size // Possibly a write in progress
+					continue
+				}
+				// Left by an interrupted write; never readable history
+				errs = append(errs, rc.delete(ctx, archive, "abandoned temporary file", &result))
+				continue
+			}
+			if maxAge > 0 && now.Sub(archive.ModTime) > maxAge {
+				errs = append(errs, rc.delete(ctx, archive, "older than max_age_days", &result))
+				continue
+			}
+			kept = append(kept, archive)
+			keptSize += archive.Size
+		}
+
+		for i := 0; maxSize > 0 && keptSize > maxSize && i < len(kept); i++ {
+			if err := rc.delete(ctx, kept[i], "over max_total_size_mb", &result); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			keptSize -= kept[i].Size
+		}
+	}
+
+	if rc.archiver != nil {
+		archiveResult, err := rc.archiver.Cleanup(maxAge, maxSize, now)
+		errs = append(errs, err)
+		result.DeletedFiles += archiveResult.DeletedFiles
+		result.FreedBytes += archiveResult.FreedBytes
+		rc.logger.Debug().
+			Int("hosts", archiveResult.Targets).
+			Int("deleted_files", archiveResult.DeletedFiles).
+			Msg("Content archive retention applied")
+	}
+
+	rc.logger.Info().
+		Int("targets", result.Targets).
+		Int("deleted_files", result.DeletedFiles).
+		Int64("freed_bytes", result.FreedBytes).
+		Msg("Storage retention cleanup completed")
+
+	return result, errors.Join(errs...)
+}
+
+// delete removes one archived file and records it in result
+func (rc *RetentionCleaner) delete(ctx context.Context, info BlobInfo, reason string, result *RetentionResult) error {
+	if err := rc.blob.Delete(ctx, info.Key); err != nil && !errors.Is(err, ErrBlobNotFound) {
+		return errorwrapper.WrapError(err, "failed to delete "+rc.blob.Location(info.Key))
+	}
+
+	result.DeletedFiles++
+	result.FreedBytes += info.Size
+	rc.logger.Debug().
+		Str("file", rc.blob.Location(info.Key)).
+		Int64("size", info.Size).
+		Time("modified", info.ModTime).
+		Str("reason", reason).
+		Msg("Deleted archived scan file")
+	return nil
+}
+
+// groupStoredTargets groups the scan directory listing by target: "<target>.parquet" is the live file,
+// and "<target>.parquet.<suffix>" files are its archives
+func groupStoredTargets(infos []BlobInfo) map[string]*storedTarget {
+	targets := make(map[string]*storedTarget)
+	for _, info := range infos {
+		name := blobBaseName(info.Key)
+		end := strings.Index(name, ".parquet")
+		if end <= 0 {
+			continue
+		}
+
+		target := targets[name[:end]]
+		if target == nil {
+			target = &storedTarget{}
+			targets[name[:end]] = target
+		}
+		if end+len(".parquet") == len(name) {
+			live := info
+			target.live = &live
+		} else {
+			target.archives = append(target.archives, info)
+		}
+	}
+
+	for _, target := range targets {
+		sort.SliceStable(target.archives, func(i, j int) bool {
+			return target.archives[i].ModTime.Before(target.archives[j].ModTime)
+		})
+	}
+	return targets
+}
+
+// isTempBlob reports whether key is a temporary file of LocalBlob.Write
+func isTempBlob(key string) bool {
+	return strings.Contains(blobBaseName(key), ".parquet.tmp-")
+}
@@ -0,0 +1,113 @@
+package datastore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const retentionTestMB = 1024 * 1024
+
+var retentionTestNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// writeScanFile creates a file of size bytes under the scan directory, last modified age before retentionTestNow
+func writeScanFile(t *testing.T, baseDir, name string, size int64, age time.Duration) {
+	t.Helper()
+	filePath := filepath.Join(baseDir, scanBlobDir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+	file, err := os.Create(filePath)
+	require.NoError(t, err)
+	require.NoError(t, file.Truncate(size))
+	require.NoError(t, file.Close())
+
+	modTime := retentionTestNow.Add(-age)
+	require.NoError(t, os.Chtimes(filePath, modTime, modTime))
+}
+
+func newTestRetentionCleaner(t *testing.T, baseDir string, retention config.RetentionConfig) *RetentionCleaner {
+	t.Helper()
+	storageConfig := config.NewDefaultStorageConfig()
+	storageConfig.ParquetBasePath = baseDir
+	storageConfig.Retention = retention
+
+	cleaner, err := NewRetentionCleaner(&storageConfig, zerolog.Nop())
+	require.NoError(t, err)
+	cleaner.now = func() time.Time { return retentionTestNow }
+	return cleaner
+}
+
+// remainingScanFiles lists the file names left in the scan directory
+func remainingScanFiles(t *testing.T, baseDir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(baseDir, scanBlobDir))
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestRetentionCleaner_MaxAge(t *testing.T) {
+	baseDir := t.TempDir()
+	day := 24 * time.Hour
+	writeScanFile(t, baseDir, "example.com.parquet", 100, 400*day)
+	writeScanFile(t, baseDir, "example.com.parquet.corrupt", 10, 40*day)
+	writeScanFile(t, baseDir, "example.com.parquet.1717243200.corrupt", 20, 5*day)
+	writeScanFile(t, baseDir, "example.com.parquet.tmp-123", 30, 2*time.Hour)
+	writeScanFile(t, baseDir, "example.com.parquet.tmp-456", 40, 10*time.Minute)
+
+	cleaner := newTestRetentionCleaner(t, baseDir, config.RetentionConfig{Enabled: true, MaxAgeDays: 30})
+	result, err := cleaner.Cleanup(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, RetentionResult{Targets: 1, DeletedFiles: 2, FreedBytes: 40}, result)
+	assert.ElementsMatch(t, []string{
+		"example.com.parquet",                    // live file, however old
+		"example.com.parquet.1717243200.corrupt", // younger than max_age_days
+		"example.com.parquet.tmp-456",            // may belong to a write in progress
+	}, remainingScanFiles(t, baseDir))
+}
+
+func TestRetentionCleaner_MaxTotalSize(t *testing.T) {
+	baseDir := t.TempDir()
+	writeScanFile(t, baseDir, "example.com.parquet", 2*retentionTestMB, time.Hour)
+	writeScanFile(t, baseDir, "example.com.parquet.a.corrupt", retentionTestMB, 3*time.Hour)
+	writeScanFile(t, baseDir, "example.com.parquet.b.corrupt", retentionTestMB, 2*time.Hour)
+	writeScanFile(t, baseDir, "example.com.parquet.c.corrupt", retentionTestMB, 90*time.Minute)
+	writeScanFile(t, baseDir, "other.com.parquet", retentionTestMB, time.Hour)
+	writeScanFile(t, baseDir, "other.com.parquet.corrupt", retentionTestMB, 2*time.Hour)
+
+	cleaner := newTestRetentionCleaner(t, baseDir, config.RetentionConfig{Enabled: true, MaxTotalSizeMB: 3})
+	result, err := cleaner.Cleanup(context.Background())
+	require.NoError(t, err)
+
+	// example.com holds 5 MB: its two oldest archives go; other.com is within the cap
+	assert.Equal(t, RetentionResult{Targets: 2, DeletedFiles: 2, FreedBytes: 2 * retentionTestMB}, result)
+	assert.ElementsMatch(t, []string{
+		"example.com.parquet",
+		"example.com.parquet.c.corrupt",
+		"other.com.parquet",
+		"other.com.parquet.corrupt",
+	}, remainingScanFiles(t, baseDir))
+}
+
+func TestRetentionCleaner_NeverDeletesLiveFile(t *testing.T) {
+	baseDir := t.TempDir()
+	writeScanFile(t, baseDir, "example.com.parquet", 2*retentionTestMB, 1000*24*time.Hour)
+	writeScanFile(t, baseDir, "example.com.parquet.corrupt", 10, time.Hour)
+
+	cleaner := newTestRetentionCleaner(t, baseDir, config.RetentionConfig{Enabled: true, MaxAgeDays: 1, MaxTotalSizeMB: 1})
+	result, err := cleaner.Cleanup(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.DeletedFiles, "the archive goes, the oversized and old live file stays")
+	assert.Equal(t, []string{"example.com.parquet"}, remainingScanFiles(t, baseDir))
+}
@@ -0,0 +1,68 @@
+package datastore
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+)
+
+// FindPossiblyDecommissionedURLs returns the stored URLs for a target that were not seen
+// in at least minMissedScans consecutive scans. Requires URL lifecycle tracking to be
+// enabled, otherwise unseen URLs are not retained in storage.
+func (pr *ParquetReader) FindPossiblyDecommissionedURLs(rootTargetURL string, minMissedScans int) ([]httpxrunner.ProbeResult, error) {
+	results, _, err := pr.FindAllProbeResultsForTarget(rootTargetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterByMissedScans(results, minMissedScans), nil
+}
+
+// FindAllPossiblyDecommissionedURLs runs FindPossiblyDecommissionedURLs across every stored
+// target. The returned map is keyed by the Parquet file name without extension.
+func (pr *ParquetReader) FindAllPossiblyDecommissionedURLs(minMissedScans int) (map[string][]httpxrunner.ProbeResult, error) {
+	if err := pr.validateConfiguration(); err != nil {
+		return nil, err
+	}
+
+	keys, err := pr.listParquetKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	decommissioned := make(map[string][]httpxrunner.ProbeResult)
+	for _, key := range keys {
+		target := strings.TrimSuffix(blobBaseName(key), ".parquet")
+		results, err := pr.readProbeResultsFromFile(key, "")
+		if err != nil {
+			pr.logger.Warn().Err(err).Str("file", blobBaseName(key)).Msg("Skipping unreadable Parquet file")
+			continue
+		}
+
+		if stale := filterByMissedScans(results, minMissedScans); len(stale) > 0 {
+			decommissioned[target] = stale
+		}
+	}
+
+	return decommissioned, nil
+}
+
+// filterByMissedScans keeps results missing from at least minMissedScans scans, most-missed first
+func filterByMissedScans(results []httpxrunner.ProbeResult, minMissedScans int) []httpxrunner.ProbeResult {
+	if minMissedScans < 1 {
+		minMissedScans = 1
+	}
+
+	var stale []httpxrunner.ProbeResult
+	for _, result := range results {
+		if result.MissedScans >= minMissedScans {
+			stale = append(stale, result)
+		}
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].MissedScans > stale[j].MissedScans
+	})
+	return stale
+}
@@ -324,3 +324,63 @@ func TestURLDiffer_Compare_ComplexProbeData(t *testing.T) {
 	assert.Equal(t, "nginx/1.20.0", result.ProbeResult.Headers["Server"])
 	assert.Equal(t, "true", result.ProbeResult.Headers["X-New"])
 }
+
+func TestURLStatusAnalyzer_TracksFirstSeenAndMissedScans(t *testing.T) {
+	analyzer := NewURLStatusAnalyzer(NewURLMapper(DefaultURLDifferConfig()))
+	firstSeen := time.Now().Add(-48 * time.Hour)
+	lastSeen := time.Now().Add(-24 * time.Hour)
+
+	historical := []httpxrunner.ProbeResult{
+		{InputURL: "http://example.com/kept", OldestScanTimestamp: firstSeen, Timestamp: lastSeen, MissedScans: 2},
+		{InputURL: "http://example.com/gone", OldestScanTimestamp: firstSeen, Timestamp: lastSeen, MissedScans: 1},
+	}
+	current := []*httpxrunner.ProbeResult{
+		{InputURL: "http://example.com/kept", Timestamp: time.Now()},
+	}
+	urlMaps := analyzer.urlMapper.CreateMaps(historical, current)
+
+	currentResults, counts := analyzer.AnalyzeCurrentURLs(current, urlMaps)
+	require.Len(t, currentResults, 1)
+	assert.Equal(t, 1, counts.Existing)
+	assert.True(t, currentResults[0].ProbeResult.OldestScanTimestamp.Equal(firstSeen))
+	assert.Equal(t, 0, currentResults[0].ProbeResult.MissedScans)
+
+	oldResults, oldCount := analyzer.AnalyzeOldURLs(urlMaps)
+	require.Equal(t, 1, oldCount)
+	assert.Equal(t, "http://example.com/gone", oldResults[0].ProbeResult.InputURL)
+	assert.Equal(t, 2, oldResults[0].ProbeResult.MissedScans)
+	assert.True(t, oldResults[0].ProbeResult.Timestamp.Equal(lastSeen))
+}
+
+func TestURLStatusAnalyzer_BodyChanged(t *testing.T) {
+	mapper := NewURLMapper(DefaultURLDifferConfig())
+	analyzer := NewURLStatusAnalyzer(mapper)
+
+	historical := []httpxrunner.ProbeResult{
+		{InputURL: "http://example.com/same", Body: "<html>v1</html>", ContentLength: 15},
+		{InputURL: "http://example.com/edited", Body: "<html>v1</html>", ContentLength: 15},
+		{InputURL: "http://example.com/truncated", Body: "<html>", ContentLength: 15},
+		{InputURL: "http://example.com/unstored"},
+	}
+	current := []*httpxrunner.ProbeResult{
+		{InputURL: "http://example.com/same", Body: "<html>v1</html>"},
+		{InputURL: "http://example.com/edited", Body: "<html>v2</html>"},
+		{InputURL: "http://example.com/truncated", Body: "<html>v1</html>"},
+		{InputURL: "http://example.com/unstored", Body: "<html>v1</html>"},
+		{InputURL: "http://example.com/new", Body: "<html>v1</html>"},
+	}
+
+	analyzer.AnalyzeCurrentURLs(current, mapper.CreateMaps(historical, current))
+
+	changed := make(map[string]bool)
+	for _, probe := range current {
+		changed[probe.InputURL] = probe.BodyChanged
+	}
+	assert.Equal(t, map[string]bool{
+		"http://example.com/same":      false,
+		"http://example.com/edited":    true,
+		"http://example.com/truncated": false, // Stored body was cut at the size cap and is a prefix
+		"http://example.com/unstored":  false, // Nothing stored to compare against
+		"http://example.com/new":       false,
+	}, changed)
+}
@@ -1,6 +1,10 @@
 package differ
 
-import "github.com/aleister1102/monsterinc/internal/httpxrunner"
+import (
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+)
 
 // URLStatusAnalyzer analyzes URL status changes
 type URLStatusAnalyzer struct {
@@ -21,11 +25,17 @@ func (usa *URLStatusAnalyzer) AnalyzeCurrentURLs(currentProbes []*httpxrunner.Pr
 
 	for _, currentProbe := range currentProbes {
 		key := usa.urlMapper.GetURLKey(currentProbe.GetEffectiveURL()) // Using consistent key generation
-		_, existsInHistory := urlMaps.HistoricalURLMap[key]
+		historicalProbe, existsInHistory := urlMaps.HistoricalURLMap[key]
 
 		if existsInHistory {
 			counts.Existing++
 			currentProbe.URLStatus = string(StatusExisting)
+			// Carry the first-seen time forward so it survives the storage rewrite
+			if !historicalProbe.OldestScanTimestamp.IsZero() {
+				currentProbe.OldestScanTimestamp = historicalProbe.OldestScanTimestamp
+			}
+			currentProbe.MissedScans = 0
+			currentProbe.BodyChanged = bodyChanged(historicalProbe, currentProbe.Body)
 		} else {
 			counts.New++
 			currentProbe.URLStatus = string(StatusNew)
@@ -47,9 +57,24 @@ func (usa *URLStatusAnalyzer) AnalyzeOldURLs(urlMaps URLMaps) ([]DiffedURL, int)
 		if !existsInCurrent {
 			oldCount++
 			historicalProbe.URLStatus = string(StatusOld)
+			historicalProbe.MissedScans++
 			oldResults = append(oldResults, DiffedURL{ProbeResult: historicalProbe})
 		}
 	}
 
 	return oldResults, oldCount
 }
+
+// bodyChanged reports whether the current body differs from the historical one. Only stored bodies
+// (storage_config.response_bodies) can be compared; a URL without one, or probed without a body now,
+// is not considered changed. Stored bodies are cut at max_size_kb, so one shorter than its recorded
+// content length is compared as a prefix.
+func bodyChanged(historical httpxrunner.ProbeResult, currentBody string) bool {
+	if historical.Body == "" || currentBody == "" {
+		return false
+	}
+	if int64(len(historical.Body)) < historical.ContentLength {
+		return !strings.HasPrefix(currentBody, historical.Body)
+	}
+	return currentBody != historical.Body
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+)
+
+// EventType identifies a scan lifecycle event
+type EventType string
+
+const (
+	EventScanStarted     EventType = "scan_started"
+	EventBatchCompleted  EventType = "batch_completed"
+	EventURLDiffDetected EventType = "url_diff_detected"
+	EventScanCompleted   EventType = "scan_completed"
+)
+
+// Event is a machine-readable scan lifecycle event delivered to an EventSink
+type Event struct {
+	Type          EventType                `json:"type"`
+	Timestamp     time.Time                `json:"timestamp"`
+	ScanSessionID string                   `json:"scan_session_id"`
+	Summary       *summary.ScanSummaryData `json:"summary,omitempty"`
+	Payload       map[string]interface{}   `json:"payload,omitempty"`
+}
+
+// NewEvent creates an event of the given type stamped with the current time
+func NewEvent(eventType EventType, scanSessionID string) Event {
+	return Event{
+		Type:          eventType,
+		Timestamp:     time.Now(),
+		ScanSessionID: scanSessionID,
+	}
+}
+
+// WithSummary attaches a copy of the scan summary to the event
+func (e Event) WithSummary(summaryData summary.ScanSummaryData) Event {
+	e.Summary = &summaryData
+	return e
+}
+
+// WithPayload attaches event-specific data to the event
+func (e Event) WithPayload(payload map[string]interface{}) Event {
+	e.Payload = payload
+	return e
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/rs/zerolog"
+)
+
+// FileSink appends events as JSON Lines to a local file
+type FileSink struct {
+	file   *os.File
+	mu     sync.Mutex
+	logger zerolog.Logger
+}
+
+// NewFileSink opens (or creates) the file at path for appending events
+func NewFileSink(path string, logger zerolog.Logger) (*FileSink, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, errorwrapper.WrapError(err, "failed to create event file directory")
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to open event file")
+	}
+
+	return &FileSink{
+		file:   file,
+		logger: logger.With().Str("component", "FileSink").Logger(),
+	}, nil
+}
+
+// Emit implements EventSink
+func (fs *FileSink) Emit(ctx context.Context, event Event) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		fs.logger.Error().Err(err).Str("event_type", string(event.Type)).Msg("Failed to encode event")
+		return
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return
+	}
+
+	if _, err := fs.file.Write(append(line, '\n')); err != nil {
+		fs.logger.Error().Err(err).Str("event_type", string(event.Type)).Msg("Failed to write event")
+	}
+}
+
+// Close implements EventSink
+func (fs *FileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return nil
+	}
+
+	err := fs.file.Close()
+	fs.file = nil
+	return err
+}
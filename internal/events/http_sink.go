@@ -0,0 +1,145 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// httpSinkDrainTimeout bounds how long Close waits for queued events to be delivered
+const httpSinkDrainTimeout = 5 * time.Second
+
+// SignatureHeader carries the HMAC-SHA256 of an event body when a signing secret is configured
+const SignatureHeader = "X-MonsterInc-Signature"
+
+// SignPayload returns the SignatureHeader value for body: "sha256=" followed by the hex HMAC-SHA256 of body
+// keyed with secret, as GitHub signs its webhooks. Receivers recompute it over the raw body and compare
+// with hmac.Equal.
+func SignPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// HTTPSink POSTs each event as JSON to a collector endpoint.
+// Events are queued and delivered by a background worker so Emit never waits on the network;
+// when the queue is full new events are dropped. With a signing secret every body is signed in SignatureHeader.
+type HTTPSink struct {
+	client        *httpclient.HTTPClient
+	url           string
+	headers       map[string]string
+	signingSecret string
+	queue         chan Event
+	done          chan struct{}
+	mu            sync.RWMutex // Guards sending on queue against Close closing it
+	closed        bool
+	logger        zerolog.Logger
+}
+
+// NewHTTPSink creates an HTTPSink and starts its delivery worker. An empty signingSecret sends unsigned events.
+func NewHTTPSink(client *httpclient.HTTPClient, url string, headers map[string]string, signingSecret string, bufferSize int, logger zerolog.Logger) *HTTPSink {
+	if bufferSize <= 0 {
+		bufferSize = config.DefaultEventSinkBufferSize
+	}
+
+	hs := &HTTPSink{
+		client:        client,
+		url:           url,
+		headers:       headers,
+		signingSecret: signingSecret,
+		queue:         make(chan Event, bufferSize),
+		done:          make(chan struct{}),
+		logger:        logger.With().Str("component", "HTTPSink").Logger(),
+	}
+
+	go hs.run()
+	return hs
+}
+
+// Emit implements EventSink
+func (hs *HTTPSink) Emit(ctx context.Context, event Event) {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	if hs.closed {
+		hs.logger.Warn().Str("event_type", string(event.Type)).Msg("Event emitted after sink was closed, dropping")
+		return
+	}
+
+	select {
+	case hs.queue <- event:
+	default:
+		hs.logger.Warn().Str("event_type", string(event.Type)).Msg("Event queue full, dropping event")
+	}
+}
+
+// Close implements EventSink. It stops accepting events and waits briefly for queued ones to be delivered.
+func (hs *HTTPSink) Close() error {
+	hs.mu.Lock()
+	if !hs.closed {
+		hs.closed = true
+		close(hs.queue)
+	}
+	hs.mu.Unlock()
+
+	select {
+	case <-hs.done:
+	case <-time.After(httpSinkDrainTimeout):
+		hs.logger.Warn().Int("pending", len(hs.queue)).Msg("Timed out delivering queued events")
+	}
+	return nil
+}
+
+// run delivers queued events until the queue is closed
+func (hs *HTTPSink) run() {
+	defer close(hs.done)
+
+	for event := range hs.queue {
+		hs.deliver(event)
+	}
+}
+
+// deliver sends a single event, logging any failure
+func (hs *HTTPSink) deliver(event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		hs.logger.Error().Err(err).Str("event_type", string(event.Type)).Msg("Failed to encode event")
+		return
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	for key, value := range hs.headers {
+		headers[key] = value
+	}
+	if hs.signingSecret != "" {
+		headers[SignatureHeader] = SignPayload(hs.signingSecret, body)
+	}
+
+	resp, err := hs.client.Do(&httpclient.HTTPRequest{
+		URL:     hs.url,
+		Method:  "POST",
+		Headers: headers,
+		Body:    bytes.NewReader(body),
+		Context: context.Background(),
+	})
+	if err != nil {
+		hs.logger.Warn().Err(err).Str("event_type", string(event.Type)).Msg("Failed to deliver event")
+		return
+	}
+
+	if resp.StatusCode >= 300 {
+		hs.logger.Warn().
+			Int("status_code", resp.StatusCode).
+			Str("event_type", string(event.Type)).
+			Msg("Event collector rejected event")
+	}
+}
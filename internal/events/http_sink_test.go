@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedEvent struct {
+	body      []byte
+	signature string
+}
+
+func newTestHTTPSink(t *testing.T, signingSecret string) (*HTTPSink, <-chan receivedEvent) {
+	received := make(chan receivedEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedEvent{body: body, signature: r.Header.Get(SignatureHeader)}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := httpclient.NewHTTPClientFactory(zerolog.Nop()).CreateBasicClient(5 * time.Second)
+	require.NoError(t, err)
+	return NewHTTPSink(client, server.URL, nil, signingSecret, 1, zerolog.Nop()), received
+}
+
+func TestHTTPSink_SignsBody(t *testing.T) {
+	sink, received := newTestHTTPSink(t, "s3cret")
+	sink.Emit(context.Background(), NewEvent(EventScanStarted, "session"))
+	require.NoError(t, sink.Close())
+
+	event := <-received
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, event.signature)
+	assert.True(t, hmac.Equal([]byte(SignPayload("s3cret", event.body)), []byte(event.signature)),
+		"the signature covers the exact body sent")
+	assert.NotEqual(t, SignPayload("other", event.body), event.signature)
+}
+
+func TestHTTPSink_UnsignedWithoutSecret(t *testing.T) {
+	sink, received := newTestHTTPSink(t, "")
+	sink.Emit(context.Background(), NewEvent(EventScanStarted, "session"))
+	require.NoError(t, sink.Close())
+
+	assert.Empty(t, (<-received).signature)
+}
+
+func TestSignPayload(t *testing.T) {
+	// Example from GitHub's webhook validation documentation
+	assert.Equal(t, "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17",
+		SignPayload("It's a Secret to Everybody", []byte("Hello, World!")))
+}
+
+func TestHTTPSink_EmitAfterCloseIsDropped(t *testing.T) {
+	sink, received := newTestHTTPSink(t, "")
+	require.NoError(t, sink.Close())
+	require.NoError(t, sink.Close(), "closing twice is harmless")
+
+	sink.Emit(context.Background(), NewEvent(EventScanStarted, "session"))
+	select {
+	case <-received:
+		t.Fatal("event emitted after Close was delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHTTPSink_EmitConcurrentWithClose(t *testing.T) {
+	sink, _ := newTestHTTPSink(t, "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sink.Emit(context.Background(), NewEvent(EventBatchCompleted, "session"))
+			}
+		}()
+	}
+	require.NoError(t, sink.Close())
+	wg.Wait()
+}
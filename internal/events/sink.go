@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// EventSink receives scan lifecycle events.
+// Emit must never block the scan for long and must never fail it; delivery errors are logged by the sink.
+type EventSink interface {
+	Emit(ctx context.Context, event Event)
+	Close() error
+}
+
+// NopSink discards all events
+type NopSink struct{}
+
+// Emit implements EventSink
+func (NopSink) Emit(ctx context.Context, event Event) {}
+
+// Close implements EventSink
+func (NopSink) Close() error { return nil }
+
+// MultiSink fans events out to several sinks
+type MultiSink struct {
+	sinks []EventSink
+}
+
+// NewMultiSink creates a sink that forwards every event to all given sinks
+func NewMultiSink(sinks ...EventSink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+// Emit implements EventSink
+func (ms *MultiSink) Emit(ctx context.Context, event Event) {
+	for _, sink := range ms.sinks {
+		sink.Emit(ctx, event)
+	}
+}
+
+// Close implements EventSink
+func (ms *MultiSink) Close() error {
+	var errs []error
+	for _, sink := range ms.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewEventSinkFromConfig builds the sink described by cfg. It returns a NopSink when events are
+// disabled or no destination is configured; destinations that fail to initialize are logged and skipped.
+// The HTTP destination is reached through proxyConfig.
+func NewEventSinkFromConfig(cfg config.EventSinkConfig, proxyConfig httpclient.ProxyConfig, logger zerolog.Logger) EventSink {
+	sinkLogger := logger.With().Str("module", "EventSink").Logger()
+
+	if !cfg.Enabled {
+		return NopSink{}
+	}
+
+	var sinks []EventSink
+
+	if cfg.FilePath != "" {
+		fileSink, err := NewFileSink(cfg.FilePath, sinkLogger)
+		if err != nil {
+			sinkLogger.Error().Err(err).Str("file_path", cfg.FilePath).Msg("Failed to initialize file event sink")
+		} else {
+			sinks = append(sinks, fileSink)
+		}
+	}
+
+	if cfg.HTTPURL != "" {
+		timeoutSecs := cfg.HTTPTimeoutSecs
+		if timeoutSecs <= 0 {
+			timeoutSecs = config.DefaultEventSinkHTTPTimeoutSecs
+		}
+
+		client, err := httpclient.NewHTTPClientFactory(sinkLogger).
+			WithProxyConfig(proxyConfig).
+			CreateBasicClient(time.Duration(timeoutSecs) * time.Second)
+		if err != nil {
+			sinkLogger.Error().Err(err).Msg("Failed to create HTTP client for event sink")
+		} else {
+			sinks = append(sinks, NewHTTPSink(client, cfg.HTTPURL, cfg.HTTPHeaders, cfg.HTTPSigningSecret, cfg.BufferSize, sinkLogger))
+		}
+	}
+
+	switch len(sinks) {
+	case 0:
+		sinkLogger.Warn().Msg("Event sink enabled but no destination configured, events will be discarded")
+		return NopSink{}
+	case 1:
+		return sinks[0]
+	default:
+		return NewMultiSink(sinks...)
+	}
+}
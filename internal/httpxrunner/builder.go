@@ -47,7 +47,7 @@ func (b *RunnerBuilder) Build() (*Runner, error) {
 
 	// Create components
 	configurator := NewHTTPXOptionsConfigurator(b.logger)
-	mapper := NewProbeResultMapper(b.logger)
+	mapper := NewProbeResultMapper(b.logger).WithConfig(b.config)
 	collector := NewResultCollector(b.logger)
 
 	// Configure httpx options
@@ -14,8 +14,10 @@ type Config struct {
 	ExtractServerHeader  bool
 	ExtractStatusCode    bool
 	ExtractTitle         bool
+	ExtractTLS           bool
 	FollowRedirects      bool
 	Method               string
+	Proxy                string // Single proxy URL (http|socks5); httpx cannot choose a proxy per request
 	RateLimit            int
 	RequestURIs          []string
 	Retries              int
@@ -41,6 +43,7 @@ func DefaultConfig() *Config {
 		ExtractServerHeader:  true,
 		ExtractStatusCode:    true,
 		ExtractTitle:         true,
+		ExtractTLS:           true,
 		FollowRedirects:      true,
 		Method:               "GET",
 		RateLimit:            0,
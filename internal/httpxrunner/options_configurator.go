@@ -26,6 +26,7 @@ func (hoc *HTTPXOptionsConfigurator) ConfigureOptions(config *Config) *runner.Op
 		hoc.applyBasicConfig(options, config)
 		hoc.applyTargetConfig(options, config)
 		hoc.applyPerformanceConfig(options, config)
+		hoc.applyProxyConfig(options, config)
 		hoc.applyCustomHeaders(options, config)
 		hoc.applyExtractionConfig(options, config)
 	}
@@ -105,6 +106,11 @@ func (hoc *HTTPXOptionsConfigurator) applyPerformanceConfig(options *runner.Opti
 	}
 }
 
+// applyProxyConfig routes httpx traffic through the configured proxy
+func (hoc *HTTPXOptionsConfigurator) applyProxyConfig(options *runner.Options, config *Config) {
+	options.Proxy = config.Proxy
+}
+
 // applyCustomHeaders applies custom headers configuration
 func (hoc *HTTPXOptionsConfigurator) applyCustomHeaders(options *runner.Options, config *Config) {
 	if len(config.CustomHeaders) == 0 {
@@ -115,8 +121,9 @@ func (hoc *HTTPXOptionsConfigurator) applyCustomHeaders(options *runner.Options,
 	for k, v := range config.CustomHeaders {
 		headerVal := k + ": " + v
 		if err := headers.Set(headerVal); err != nil {
+			// Log the name only: values may carry credentials
 			hoc.logger.Warn().
-				Str("header", headerVal).
+				Str("header", k).
 				Err(err).
 				Msg("Failed to set custom header")
 			continue
@@ -139,4 +146,5 @@ func (hoc *HTTPXOptionsConfigurator) applyExtractionConfig(options *runner.Optio
 	options.ResponseHeadersInStdout = config.ExtractHeaders
 	options.StatusCode = config.ExtractStatusCode
 	options.TechDetect = config.TechDetect
+	options.TLSGrab = config.ExtractTLS
 }
@@ -6,7 +6,9 @@ import "time"
 // Refactored ✅
 type ProbeResult struct {
 	Body                string            `json:"body,omitempty"`
+	BodyChanged         bool              `json:"body_changed,omitempty"` // Body differs from the one stored by the previous scan (set by the differ)
 	CNAMEs              []string          `json:"cnames,omitempty"`
+	DiscoveredFrom      string            `json:"discovered_from,omitempty"` // Page the crawler found this URL on; empty for seed URLs
 	ContentLength       int64             `json:"content_length,omitempty"`
 	ContentType         string            `json:"content_type,omitempty"`
 	Duration            float64           `json:"duration,omitempty"` // in seconds
@@ -16,6 +18,7 @@ type ProbeResult struct {
 	InputURL            string            `json:"input_url"`
 	IPs                 []string          `json:"ips,omitempty"`
 	Method              string            `json:"method"`
+	MissedScans         int               `json:"missed_scans,omitempty"`          // Consecutive scans this URL was not seen in (lifecycle tracking)
 	OldestScanTimestamp time.Time         `json:"oldest_scan_timestamp,omitempty"` // Timestamp of the very first scan, or historical record
 	RootTargetURL       string            `json:"root_target_url,omitempty"`
 	StatusCode          int               `json:"status_code,omitempty"`
@@ -26,6 +29,12 @@ type ProbeResult struct {
 	WebServer           string            `json:"webserver,omitempty"`
 	ASN                 int               `json:"asn,omitempty"`
 	ASNOrg              string            `json:"asn_org,omitempty"`
+	TLSVersion          string            `json:"tls_version,omitempty"`
+	TLSCipher           string            `json:"tls_cipher,omitempty"`
+	TLSCertIssuer       string            `json:"tls_cert_issuer,omitempty"`
+	TLSCertNotAfter     time.Time         `json:"tls_cert_not_after,omitempty"` // Expiry of the leaf certificate
+	TLSCertSANs         []string          `json:"tls_cert_sans,omitempty"`      // Subject alternative names of the leaf certificate
+	Tags                []string          `json:"tags,omitempty"`               // Tags of the target the URL belongs to
 }
 
 // GetEffectiveURL returns the effective URL based on priority:
@@ -45,6 +54,11 @@ func (pr *ProbeResult) GetEffectiveURL() string {
 	return pr.InputURL
 }
 
+// HasTLSCertificate returns true if the probe captured a leaf certificate
+func (pr *ProbeResult) HasTLSCertificate() bool {
+	return pr != nil && !pr.TLSCertNotAfter.IsZero()
+}
+
 // HasTechnologies returns true if any technologies were detected in the probe result.
 // No need to refactor ✅
 func (pr *ProbeResult) HasTechnologies() bool {
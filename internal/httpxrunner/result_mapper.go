@@ -12,6 +12,7 @@ import (
 // ProbeResultMapper handles mapping from httpx results to ProbeResult
 type ProbeResultMapper struct {
 	logger zerolog.Logger
+	config *Config
 }
 
 // NewProbeResultMapper creates a new probe result mapper
@@ -21,6 +22,12 @@ func NewProbeResultMapper(logger zerolog.Logger) *ProbeResultMapper {
 	}
 }
 
+// WithConfig sets the extraction toggles; fields whose extraction is disabled are dropped from mapped results
+func (prm *ProbeResultMapper) WithConfig(cfg *Config) *ProbeResultMapper {
+	prm.config = cfg
+	return prm
+}
+
 // MapResult converts an httpx runner.Result to a models.ProbeResult
 func (prm *ProbeResultMapper) MapResult(res runner.Result, rootURL string) *ProbeResult {
 	probeResult := prm.createBaseProbeResult(res, rootURL)
@@ -30,6 +37,8 @@ func (prm *ProbeResultMapper) MapResult(res runner.Result, rootURL string) *Prob
 	prm.mapTechnologies(probeResult, res)
 	prm.mapNetworkInfo(probeResult, res)
 	prm.mapASNInfo(probeResult, res)
+	prm.mapTLSInfo(probeResult, res)
+	prm.dropDisabledFields(probeResult)
 
 	return probeResult
 }
@@ -153,8 +162,70 @@ func (prm *ProbeResultMapper) mapASNInfo(probeResult *ProbeResult, res runner.Re
 	probeResult.ASNOrg = res.ASN.AsName
 }
 
+// mapTLSInfo maps the TLS handshake and leaf certificate details
+func (prm *ProbeResultMapper) mapTLSInfo(probeResult *ProbeResult, res runner.Result) {
+	if res.TLSData == nil {
+		return
+	}
+
+	probeResult.TLSVersion = res.TLSData.Version
+	probeResult.TLSCipher = res.TLSData.Cipher
+
+	cert := res.TLSData.CertificateResponse
+	if cert == nil {
+		return
+	}
+	probeResult.TLSCertNotAfter = cert.NotAfter
+	probeResult.TLSCertSANs = cert.SubjectAN
+	probeResult.TLSCertIssuer = cert.IssuerCN
+	if probeResult.TLSCertIssuer == "" && len(cert.IssuerOrg) > 0 {
+		probeResult.TLSCertIssuer = cert.IssuerOrg[0]
+	}
+}
+
 // parseASNNumber parses ASN number from string
 func (prm *ProbeResultMapper) parseASNNumber(asNumber string) (int, error) {
 	cleanNumber := strings.ReplaceAll(asNumber, "AS", "")
 	return strconv.Atoi(cleanNumber)
 }
+
+// dropDisabledFields clears data httpx still reports when its extraction is turned off
+// (A records are resolved regardless of OutputIP, for example), so it is never stored
+func (prm *ProbeResultMapper) dropDisabledFields(probeResult *ProbeResult) {
+	if prm.config == nil {
+		return
+	}
+
+	if !prm.config.ExtractASN {
+		probeResult.ASN = 0
+		probeResult.ASNOrg = ""
+	}
+	if !prm.config.ExtractContentLength {
+		probeResult.ContentLength = 0
+	}
+	if !prm.config.ExtractContentType {
+		probeResult.ContentType = ""
+	}
+	if !prm.config.ExtractHeaders {
+		probeResult.Headers = nil
+	}
+	if !prm.config.ExtractIPs {
+		probeResult.IPs = nil
+	}
+	if !prm.config.ExtractServerHeader {
+		probeResult.WebServer = ""
+	}
+	if !prm.config.ExtractTitle {
+		probeResult.Title = ""
+	}
+	if !prm.config.TechDetect {
+		probeResult.Technologies = nil
+	}
+	if !prm.config.ExtractTLS {
+		probeResult.TLSVersion = ""
+		probeResult.TLSCipher = ""
+		probeResult.TLSCertIssuer = ""
+		probeResult.TLSCertNotAfter = time.Time{}
+		probeResult.TLSCertSANs = nil
+	}
+}
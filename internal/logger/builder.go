@@ -49,8 +49,12 @@ func (lb *LoggerBuilder) Build() (*Logger, error) {
 		return nil, errorwrapper.NewError("no output writers configured")
 	}
 
-	multiWriter := zerolog.MultiLevelWriter(writers...)
-	zerologInstance := zerolog.New(multiWriter).
+	var output io.Writer = zerolog.MultiLevelWriter(writers...)
+	if lb.config.Format == FormatJSON {
+		// JSON is the machine-read format, so it carries the scan correlation fields
+		output = traceWriter{out: output}
+	}
+	zerologInstance := zerolog.New(output).
 		Level(lb.config.Level).
 		With().
 		Timestamp().
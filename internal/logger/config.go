@@ -11,6 +11,7 @@ type LoggerConfig struct {
 	FilePath      string
 	MaxSizeMB     int
 	MaxBackups    int
+	Compress      bool // Gzip rotated files
 	// New fields for organizing logs by scan/cycle
 	ScanID     string // Scan session ID for organizing scan logs
 	CycleID    string // Monitor cycle ID for organizing monitor logs
@@ -49,6 +50,7 @@ func DefaultLoggerConfig() LoggerConfig {
 		EnableFile:    false,
 		MaxSizeMB:     100,
 		MaxBackups:    3,
+		Compress:      true,
 		UseSubdirs:    true, // Enable subdirectories by default
 	}
 }
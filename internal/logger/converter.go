@@ -36,6 +36,7 @@ func (cc *ConfigConverter) ConvertConfig(cfg config.LogConfig) (LoggerConfig, er
 		FilePath:      cfg.LogFile,
 		MaxSizeMB:     cc.getMaxSizeMB(cfg.MaxLogSizeMB),
 		MaxBackups:    cc.getMaxBackups(cfg.MaxLogBackups),
+		Compress:      cfg.CompressLogBackups,
 		// New fields default to empty/false - will be set by builder methods
 		ScanID:     "",
 		CycleID:    "",
@@ -46,7 +47,7 @@ func (cc *ConfigConverter) ConvertConfig(cfg config.LogConfig) (LoggerConfig, er
 // getMaxSizeMB returns max size with default fallback
 func (cc *ConfigConverter) getMaxSizeMB(maxSize int) int {
 	if maxSize <= 0 {
-		return 100
+		return config.DefaultMaxLogSizeMB
 	}
 	return maxSize
 }
@@ -54,7 +55,7 @@ func (cc *ConfigConverter) getMaxSizeMB(maxSize int) int {
 // getMaxBackups returns max backups with default fallback
 func (cc *ConfigConverter) getMaxBackups(maxBackups int) int {
 	if maxBackups <= 0 {
-		return 3
+		return config.DefaultMaxLogBackups
 	}
 	return maxBackups
 }
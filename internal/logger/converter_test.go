@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigConverter_Rotation(t *testing.T) {
+	cfg := config.NewDefaultLogConfig()
+	cfg.LogFile = "logs/monsterinc.log"
+	cfg.MaxLogSizeMB = 20
+	cfg.MaxLogBackups = 7
+
+	converted, err := NewConfigConverter().ConvertConfig(cfg)
+	require.NoError(t, err)
+	assert.True(t, converted.EnableFile)
+	assert.Equal(t, 20, converted.MaxSizeMB)
+	assert.Equal(t, 7, converted.MaxBackups)
+	assert.True(t, converted.Compress, "rotated files are compressed by default")
+
+	cfg.MaxLogSizeMB = 0
+	cfg.MaxLogBackups = -1
+	cfg.CompressLogBackups = false
+	converted, err = NewConfigConverter().ConvertConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultMaxLogSizeMB, converted.MaxSizeMB)
+	assert.Equal(t, config.DefaultMaxLogBackups, converted.MaxBackups)
+	assert.False(t, converted.Compress)
+}
@@ -48,6 +48,7 @@ func (wf *WriterFactory) CreateFileWriter(config LoggerConfig) io.Writer {
 		MaxSize:    config.MaxSizeMB,
 		LocalTime:  true,
 		MaxBackups: config.MaxBackups,
+		Compress:   config.Compress,
 	}
 
 	strategy, exists := wf.strategies[config.Format]
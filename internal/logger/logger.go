@@ -26,7 +26,8 @@ func New(cfg config.LogConfig) (zerolog.Logger, error) {
 	return *logger.GetZerolog(), nil
 }
 
-// NewWithScanID creates a new logger instance with scan ID for organizing logs
+// NewWithScanID creates a new logger instance with scan ID for organizing logs.
+// Every line carries the scan's trace ID, so it correlates with other components' lines.
 func NewWithScanID(cfg config.LogConfig, scanID string) (zerolog.Logger, error) {
 	logger, err := NewLoggerBuilder().
 		WithConfig(cfg).
@@ -35,5 +36,8 @@ func NewWithScanID(cfg config.LogConfig, scanID string) (zerolog.Logger, error)
 	if err != nil {
 		return zerolog.Logger{}, err
 	}
-	return *logger.GetZerolog(), nil
+	if scanID == "" {
+		return *logger.GetZerolog(), nil
+	}
+	return logger.GetZerolog().With().Str(TraceIDField, TraceID(scanID)).Logger(), nil
 }
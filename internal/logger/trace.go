@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+)
+
+// Correlation fields added to JSON log lines while a scan is active
+const (
+	TraceIDField = "trace_id" // Same for every line of one scan session
+	SpanIDField  = "span_id"  // Same for every line about one URL within a scan
+)
+
+// scanTrace is the correlation context of the running scan
+type scanTrace struct {
+	traceID string
+}
+
+// activeTrace is process-wide: the CLI runs one scan at a time. Embedders running scans
+// concurrently get the trace of the scan started last.
+var activeTrace atomic.Pointer[scanTrace]
+
+// SetScanTrace makes every JSON log line carry the trace ID of scanSessionID until it is
+// cleared with an empty ID
+func SetScanTrace(scanSessionID string) {
+	if scanSessionID == "" {
+		activeTrace.Store(nil)
+		return
+	}
+	activeTrace.Store(&scanTrace{traceID: TraceID(scanSessionID)})
+}
+
+// ActiveTraceID returns the trace ID of the running scan, or an empty string
+func ActiveTraceID() string {
+	if trace := activeTrace.Load(); trace != nil {
+		return trace.traceID
+	}
+	return ""
+}
+
+// TraceID derives the 32 hex digit trace ID of a scan session. It is deterministic, so any
+// component (or a log query) knowing the session ID arrives at the same trace ID.
+func TraceID(scanSessionID string) string {
+	sum := sha256.Sum256([]byte(scanSessionID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// SpanID derives the 16 hex digit span ID of a URL within a trace, so the crawler, prober,
+// differ and reporter lines about one URL share it
+func SpanID(traceID, url string) string {
+	sum := sha256.Sum256([]byte(traceID + "\x00" + url))
+	return hex.EncodeToString(sum[:8])
+}
+
+// traceWriter adds the correlation fields to each JSON log line before passing it on
+type traceWriter struct {
+	out io.Writer
+}
+
+// Write implements io.Writer. Lines without a trace ID get the active scan's; lines about a URL
+// ("url" or "input_url" field) get the span ID of that URL. Other lines pass through unchanged.
+func (tw traceWriter) Write(p []byte) (int, error) {
+	end := bytes.LastIndexByte(p, '}')
+	if end <= 1 || p[0] != '{' {
+		return tw.write(p, p)
+	}
+	activeTraceID := ActiveTraceID()
+	if activeTraceID == "" && !bytes.Contains(p, []byte(`"`+TraceIDField+`":`)) {
+		return tw.write(p, p)
+	}
+
+	var fields struct {
+		URL      string `json:"url"`
+		InputURL string `json:"input_url"`
+		TraceID  string `json:"trace_id"`
+		SpanID   string `json:"span_id"`
+	}
+	_ = json.Unmarshal(p, &fields)
+
+	traceID := fields.TraceID
+	addTrace := traceID == ""
+	if addTrace {
+		traceID = activeTraceID
+	}
+	url := firstNonEmpty(fields.URL, fields.InputURL)
+	addSpan := url != "" && fields.SpanID == ""
+	if !addTrace && !addSpan {
+		return tw.write(p, p)
+	}
+
+	enriched := make([]byte, 0, len(p)+64)
+	enriched = append(enriched, p[:end]...)
+	if addTrace {
+		enriched = append(enriched, `,"`+TraceIDField+`":"`+traceID+`"`...)
+	}
+	if addSpan {
+		enriched = append(enriched, `,"`+SpanIDField+`":"`+SpanID(traceID, url)+`"`...)
+	}
+	enriched = append(enriched, p[end:]...)
+	return tw.write(p, enriched)
+}
+
+// write writes line and reports the length of the original input, as io.Writer requires
+func (tw traceWriter) write(original, line []byte) (int, error) {
+	if _, err := tw.out.Write(line); err != nil {
+		return 0, err
+	}
+	return len(original), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
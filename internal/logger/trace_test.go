@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLine(t *testing.T, line []byte) map[string]any {
+	t.Helper()
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(line, &fields))
+	return fields
+}
+
+func TestTraceWriter_AddsTraceAndSpanIDs(t *testing.T) {
+	SetScanTrace("20240101-120000")
+	defer SetScanTrace("")
+
+	var buf bytes.Buffer
+	log := zerolog.New(traceWriter{out: &buf})
+	traceID := TraceID("20240101-120000")
+
+	log.Info().Str("url", "https://example.com/a").Msg("crawled")
+	fields := decodeLine(t, buf.Bytes())
+	assert.Equal(t, traceID, fields[TraceIDField])
+	assert.Equal(t, SpanID(traceID, "https://example.com/a"), fields[SpanIDField])
+
+	buf.Reset()
+	log.Info().Str("input_url", "https://example.com/a").Msg("probed")
+	fields = decodeLine(t, buf.Bytes())
+	assert.Equal(t, SpanID(traceID, "https://example.com/a"), fields[SpanIDField], "same URL shares a span across components")
+
+	buf.Reset()
+	log.Info().Msg("scan started")
+	fields = decodeLine(t, buf.Bytes())
+	assert.Equal(t, traceID, fields[TraceIDField])
+	assert.NotContains(t, fields, SpanIDField)
+}
+
+func TestTraceWriter_KeepsExistingTraceID(t *testing.T) {
+	var buf bytes.Buffer
+	log := zerolog.New(traceWriter{out: &buf}).With().Str(TraceIDField, "existing").Logger()
+
+	log.Info().Str("url", "https://example.com/").Msg("done")
+	fields := decodeLine(t, buf.Bytes())
+	assert.Equal(t, "existing", fields[TraceIDField])
+	assert.Equal(t, SpanID("existing", "https://example.com/"), fields[SpanIDField])
+}
+
+func TestTraceWriter_PassesThroughWithoutScan(t *testing.T) {
+	SetScanTrace("")
+
+	var buf bytes.Buffer
+	tw := traceWriter{out: &buf}
+	line := []byte(`{"level":"info","url":"https://example.com/","message":"idle"}` + "\n")
+
+	n, err := tw.Write(line)
+	require.NoError(t, err)
+	assert.Equal(t, len(line), n)
+	assert.Equal(t, string(line), buf.String())
+}
+
+func TestTraceID_IsDeterministic(t *testing.T) {
+	assert.Equal(t, TraceID("session-1"), TraceID("session-1"))
+	assert.NotEqual(t, TraceID("session-1"), TraceID("session-2"))
+	assert.Len(t, TraceID("session-1"), 32)
+	assert.Len(t, SpanID(TraceID("session-1"), "https://example.com/"), 16)
+}
@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookRecorder is a Discord webhook endpoint remembering the Content-Type of every message it receives
+type webhookRecorder struct {
+	mu           sync.Mutex
+	contentTypes []string
+}
+
+func (wr *webhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.contentTypes = append(wr.contentTypes, r.Header.Get("Content-Type"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newWebhookHelper returns a helper sending every scan notification to a recording webhook
+func newWebhookHelper(t *testing.T) (*NotificationHelper, *webhookRecorder) {
+	t.Helper()
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+
+	cfg := config.NewDefaultNotificationConfig()
+	cfg.ScanServiceDiscordWebhookURLs = []string{server.URL}
+	cfg.NotifyOnSuccess = true
+	cfg.NotifyOnFailure = true
+	cfg.MaxMessagesPerMinute = 0
+
+	client, err := httpclient.NewHTTPClientFactory(zerolog.Nop()).
+		WithProxyConfig(httpclient.ProxyConfig{IgnoreEnvironment: true}).
+		CreateDiscordClient(5 * time.Second)
+	require.NoError(t, err)
+	discordNotifier, err := discord.NewDiscordNotifier(&cfg, zerolog.Nop(), client)
+	require.NoError(t, err)
+
+	nh := NewNotificationHelper(discordNotifier, cfg, zerolog.Nop()).WithKeptReportFiles(true)
+	return nh, recorder
+}
+
+func TestSendScanCompletionNotification_Baseline(t *testing.T) {
+	reportPath := filepath.Join(t.TempDir(), "report.html")
+	require.NoError(t, os.WriteFile(reportPath, []byte("<html></html>"), 0600))
+
+	baselineSummary := func(status summary.ScanStatus) summary.ScanSummaryData {
+		data := summary.GetDefaultScanSummaryData()
+		data.ScanSessionID = "20250101-120000"
+		data.Status = string(status)
+		data.Baseline = true
+		return data
+	}
+
+	t.Run("a successful baseline is not announced", func(t *testing.T) {
+		nh, recorder := newWebhookHelper(t)
+		nh.SendScanCompletionNotification(context.Background(), baselineSummary(summary.ScanStatusCompleted), []string{reportPath})
+		assert.Empty(t, recorder.contentTypes)
+	})
+
+	t.Run("a failed baseline is reported without reports", func(t *testing.T) {
+		nh, recorder := newWebhookHelper(t)
+		nh.SendScanCompletionNotification(context.Background(), baselineSummary(summary.ScanStatusFailed), []string{reportPath})
+		require.Len(t, recorder.contentTypes, 1)
+		assert.False(t, strings.HasPrefix(recorder.contentTypes[0], "multipart/"), "no report is attached")
+	})
+
+	t.Run("a normal scan still attaches its report", func(t *testing.T) {
+		nh, recorder := newWebhookHelper(t)
+		data := baselineSummary(summary.ScanStatusCompleted)
+		data.Baseline = false
+		nh.SendScanCompletionNotification(context.Background(), data, []string{reportPath})
+		require.NotEmpty(t, recorder.contentTypes)
+		assert.True(t, strings.HasPrefix(recorder.contentTypes[0], "multipart/"))
+	})
+}
@@ -21,3 +21,16 @@ const (
 	MaxSingleErrorLength       = 150 // Giới hạn cho mỗi error riêng lẻ
 	MaxErrorSampleCount        = 3   // Giảm từ 5 xuống 3
 )
+
+// New URL source constants
+const (
+	MaxNewURLSources      = 5 // Source pages listed in the completion message
+	MaxNewURLsPerSource   = 5 // New URLs listed under each source page
+	MaxNewURLFieldLength  = 1024
+	NewURLSourceFieldName = "🧭 New URLs by source page"
+)
+
+// Report attachment constants
+const (
+	CompressedReportNote = "Report is gzip-compressed (`.html.gz`); decompress before opening."
+)
@@ -1,6 +1,7 @@
 package discord
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -23,3 +24,29 @@ func TestDiscordEmbedBuilder_Build(t *testing.T) {
 		t.Error("expected timestamp to be set")
 	}
 }
+
+func TestDiscordEmbedBuilder_BuildKeepsFieldOverflow(t *testing.T) {
+	builder := NewDiscordEmbedBuilder().WithTitle("Overflow")
+	for i := 0; i < MaxEmbedFields+5; i++ {
+		builder.AddField(fmt.Sprintf("Field %d", i), "value", false)
+	}
+
+	embed := builder.Build()
+	if len(embed.Fields) != MaxEmbedFields+5 {
+		t.Fatalf("expected %d fields to be kept for overflow handling, got %d", MaxEmbedFields+5, len(embed.Fields))
+	}
+
+	trimmed, overflow := SplitOverflowFields(embed, MaxEmbedFields, NewDiscordEmbedField("Note", "more attached", false))
+	if len(trimmed.Fields) != MaxEmbedFields {
+		t.Errorf("expected %d fields after split, got %d", MaxEmbedFields, len(trimmed.Fields))
+	}
+	if trimmed.Fields[MaxEmbedFields-1].Name != "Note" {
+		t.Errorf("expected last field to be the overflow note, got '%s'", trimmed.Fields[MaxEmbedFields-1].Name)
+	}
+	if len(overflow) != 6 {
+		t.Errorf("expected 6 overflow fields, got %d", len(overflow))
+	}
+	if err := NewDiscordEmbedValidator().ValidateEmbed(trimmed); err != nil {
+		t.Errorf("expected trimmed embed to be valid, got %v", err)
+	}
+}
@@ -66,9 +66,10 @@ func (deb *DiscordEmbedBuilder) Validate() error {
 	return deb.validator.ValidateEmbed(deb.embed)
 }
 
-// Build builds the Discord embed with validation
+// Build builds the Discord embed with validation.
+// Embeds with too many fields are kept intact so the sender can spill the overflow (see SplitOverflowFields).
 func (deb *DiscordEmbedBuilder) Build() DiscordEmbed {
-	if err := deb.Validate(); err != nil {
+	if err := deb.validator.ValidateEmbedContent(deb.embed); err != nil {
 		return DiscordEmbed{}
 	}
 	return deb.embed
@@ -0,0 +1,28 @@
+package discord
+
+// SplitOverflowFields trims embed to at most maxFields fields. When fields overflow, the last kept
+// slot is replaced by noteField and all removed fields are returned in their original order.
+func SplitOverflowFields(embed DiscordEmbed, maxFields int, noteField DiscordEmbedField) (DiscordEmbed, []DiscordEmbedField) {
+	if maxFields <= 0 || maxFields > MaxEmbedFields {
+		maxFields = MaxEmbedFields
+	}
+
+	if NewDiscordEmbedValidator().FieldOverflow(embed, maxFields) == 0 {
+		return embed, nil
+	}
+
+	keep := maxFields - 1
+	if keep < 0 {
+		keep = 0
+	}
+
+	overflow := make([]DiscordEmbedField, len(embed.Fields)-keep)
+	copy(overflow, embed.Fields[keep:])
+
+	fields := make([]DiscordEmbedField, 0, keep+1)
+	fields = append(fields, embed.Fields[:keep]...)
+	fields = append(fields, noteField)
+	embed.Fields = fields
+
+	return embed, overflow
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
 )
 
+// MaxEmbedFields is the maximum number of fields Discord accepts in a single embed
+const MaxEmbedFields = 25
+
 // DiscordEmbedValidator validates Discord embed objects
 type DiscordEmbedValidator struct{}
 
@@ -16,6 +19,23 @@ func NewDiscordEmbedValidator() *DiscordEmbedValidator {
 
 // ValidateEmbed validates a Discord embed
 func (dev *DiscordEmbedValidator) ValidateEmbed(embed DiscordEmbed) error {
+	if dev.FieldOverflow(embed, MaxEmbedFields) > 0 {
+		return errorwrapper.NewValidationError("fields", embed.Fields, fmt.Sprintf("cannot have more than %d fields", MaxEmbedFields))
+	}
+
+	return dev.ValidateEmbedContent(embed)
+}
+
+// FieldOverflow returns how many fields exceed maxFields (0 when the embed fits)
+func (dev *DiscordEmbedValidator) FieldOverflow(embed DiscordEmbed, maxFields int) int {
+	if overflow := len(embed.Fields) - maxFields; overflow > 0 {
+		return overflow
+	}
+	return 0
+}
+
+// ValidateEmbedContent validates embed text lengths and fields, without enforcing the field count limit
+func (dev *DiscordEmbedValidator) ValidateEmbedContent(embed DiscordEmbed) error {
 	if len(embed.Title) > 256 {
 		return errorwrapper.NewValidationError("title", embed.Title, "title cannot exceed 256 characters")
 	}
@@ -24,10 +44,6 @@ func (dev *DiscordEmbedValidator) ValidateEmbed(embed DiscordEmbed) error {
 		return errorwrapper.NewValidationError("description", embed.Description, "description cannot exceed 4096 characters")
 	}
 
-	if len(embed.Fields) > 25 {
-		return errorwrapper.NewValidationError("fields", embed.Fields, "cannot have more than 25 fields")
-	}
-
 	// Validate fields
 	for i, field := range embed.Fields {
 		if len(field.Name) > 256 {
@@ -2,7 +2,10 @@ package discord
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
 	"github.com/aleister1102/monsterinc/internal/common/httpclient"
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/rs/zerolog"
@@ -12,7 +15,6 @@ import (
 type DiscordNotifier struct {
 	logger     zerolog.Logger
 	httpClient *httpclient.HTTPClient
-	webhookURL string
 }
 
 // NewDiscordNotifier creates a new DiscordNotifier instance
@@ -20,7 +22,6 @@ func NewDiscordNotifier(cfg *config.NotificationConfig, logger zerolog.Logger, h
 	return &DiscordNotifier{
 		logger:     logger.With().Str("module", "DiscordNotifier").Logger(),
 		httpClient: httpClient,
-		webhookURL: cfg.ScanServiceDiscordWebhookURL,
 	}, nil
 }
 
@@ -40,3 +41,41 @@ func (dn *DiscordNotifier) SendNotification(ctx context.Context, webhookURL stri
 	dn.logger.Info().Str("webhook_url", webhookURL).Msg("Discord notification sent successfully")
 	return nil
 }
+
+// incomingWebhookType is the Discord webhook type that accepts messages posted to its URL
+const incomingWebhookType = 1
+
+// WebhookInfo is the metadata Discord returns for a webhook URL
+type WebhookInfo struct {
+	ID        string `json:"id"`
+	Type      int    `json:"type"`
+	Name      string `json:"name"`
+	ChannelID string `json:"channel_id"`
+	GuildID   string `json:"guild_id"`
+}
+
+// CheckWebhook fetches the webhook's metadata with a GET, which posts nothing to the channel. It fails when
+// the URL does not answer as an incoming Discord webhook, e.g. because of a typo or a revoked token.
+func (dn *DiscordNotifier) CheckWebhook(ctx context.Context, webhookURL string) (WebhookInfo, error) {
+	resp, err := dn.httpClient.Do(&httpclient.HTTPRequest{
+		URL:     webhookURL,
+		Method:  "GET",
+		Headers: map[string]string{"Accept": "application/json"},
+		Context: ctx,
+	})
+	if err != nil {
+		return WebhookInfo{}, errorwrapper.WrapError(err, "failed to look up Discord webhook")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return WebhookInfo{}, &errorwrapper.HTTPError{StatusCode: resp.StatusCode, Message: "Discord webhook lookup failed: " + string(resp.Body)}
+	}
+
+	var info WebhookInfo
+	if err := json.Unmarshal(resp.Body, &info); err != nil || info.ID == "" {
+		return WebhookInfo{}, errorwrapper.NewError("URL did not return Discord webhook metadata")
+	}
+	if info.Type != incomingWebhookType {
+		return info, fmt.Errorf("webhook %s is not an incoming webhook (type %d)", info.ID, info.Type)
+	}
+	return info, nil
+}
@@ -0,0 +1,54 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+func TestDiscordNotifier_CheckWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/api/webhooks/1/valid":
+			_, _ = w.Write([]byte(`{"type": 1, "id": "1", "name": "scans", "channel_id": "2", "guild_id": "3"}`))
+		case "/api/webhooks/1/revoked":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "Invalid Webhook Token", "code": 50027}`))
+		default:
+			_, _ = w.Write([]byte("<html>not a webhook</html>"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := httpclient.NewHTTPClientFactory(zerolog.Nop()).CreateDiscordClient(5 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dn, _ := NewDiscordNotifier(&config.NotificationConfig{}, zerolog.Nop(), client)
+
+	info, err := dn.CheckWebhook(context.Background(), server.URL+"/api/webhooks/1/valid")
+	if err != nil || info.Name != "scans" {
+		t.Errorf("expected valid webhook 'scans', got %+v, %v", info, err)
+	}
+
+	_, err = dn.CheckWebhook(context.Background(), server.URL+"/api/webhooks/1/revoked")
+	var httpErr *errorwrapper.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected HTTP 401 error for revoked webhook, got %v", err)
+	}
+
+	if _, err := dn.CheckWebhook(context.Background(), server.URL+"/typo"); err == nil {
+		t.Error("expected an error for a URL that is not a Discord webhook")
+	}
+}
@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+)
+
+// spillOverflowFields trims embeds that exceed the configured field limit and returns the
+// removed fields rendered as plain text. The returned text is empty when nothing overflowed.
+func (nh *NotificationHelper) spillOverflowFields(payload discord.DiscordMessagePayload) (discord.DiscordMessagePayload, string) {
+	var overflowText strings.Builder
+
+	embeds := make([]discord.DiscordEmbed, len(payload.Embeds))
+	for i, embed := range payload.Embeds {
+		trimmed, overflow := discord.SplitOverflowFields(embed, nh.cfg.MaxEmbedFields, discord.NewDiscordEmbedField(
+			"📎 More Details",
+			fmt.Sprintf("%d more field(s) did not fit in this message and are attached as a text file.", countOverflow(embed, nh.cfg.MaxEmbedFields)),
+			false,
+		))
+		embeds[i] = trimmed
+
+		if len(overflow) == 0 {
+			continue
+		}
+
+		if embed.Title != "" {
+			overflowText.WriteString(fmt.Sprintf("=== %s ===\n\n", embed.Title))
+		}
+		for _, field := range overflow {
+			overflowText.WriteString(fmt.Sprintf("%s\n%s\n\n", field.Name, field.Value))
+		}
+	}
+
+	payload.Embeds = embeds
+	return payload, overflowText.String()
+}
+
+// countOverflow returns how many fields will be moved out of the embed, including the slot used by the note
+func countOverflow(embed discord.DiscordEmbed, maxFields int) int {
+	if maxFields <= 0 || maxFields > discord.MaxEmbedFields {
+		maxFields = discord.MaxEmbedFields
+	}
+	if len(embed.Fields) <= maxFields {
+		return 0
+	}
+	return len(embed.Fields) - (maxFields - 1)
+}
+
+// sendPayload sends a payload, spilling any embed field overflow into a follow-up text attachment
+func (nh *NotificationHelper) sendPayload(ctx context.Context, webhookURL string, payload discord.DiscordMessagePayload, attachmentPath string) error {
+	return nh.sendPayloadWithPriority(ctx, webhookURL, payload, attachmentPath, priorityNormal)
+}
+
+// sendPayloadWithPriority sends a payload once the webhook throttle allows it
+func (nh *NotificationHelper) sendPayloadWithPriority(ctx context.Context, webhookURL string, payload discord.DiscordMessagePayload, attachmentPath string, priority notificationPriority) error {
+	payload, overflowText := nh.spillOverflowFields(payload)
+
+	if err := nh.throttles.wait(ctx, webhookURL, priority); err != nil {
+		return err
+	}
+	if err := nh.discordNotifier.SendNotification(ctx, webhookURL, payload, attachmentPath); err != nil {
+		return err
+	}
+
+	if overflowText != "" {
+		nh.sendOverflowDetails(ctx, webhookURL, overflowText, priority)
+	}
+	return nil
+}
+
+// sendOverflowDetails writes overflow text to a temporary file and sends it as a follow-up attachment
+func (nh *NotificationHelper) sendOverflowDetails(ctx context.Context, webhookURL string, overflowText string, priority notificationPriority) {
+	file, err := os.CreateTemp("", "monsterinc-notification-details-*.txt")
+	if err != nil {
+		nh.logger.Error().Err(err).Msg("Failed to create overflow details file")
+		return
+	}
+	filePath := file.Name()
+	defer func() {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			nh.logger.Warn().Err(err).Str("file_path", filePath).Msg("Failed to remove overflow details file")
+		}
+	}()
+
+	_, writeErr := file.WriteString(overflowText)
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil {
+		nh.logger.Error().Err(writeErr).AnErr("close_error", closeErr).Msg("Failed to write overflow details file")
+		return
+	}
+
+	embed := discord.NewDiscordEmbedBuilder().
+		WithTitle("📎 Notification Details").
+		WithDescription("Fields that exceeded the embed limit are attached below.").
+		WithColor(DefaultEmbedColor).
+		WithTimestamp(time.Now()).
+		Build()
+
+	payload := discord.NewDiscordMessagePayloadBuilder().
+		WithUsername(DiscordUsername).
+		WithAvatarURL(DiscordAvatarURL).
+		AddEmbed(embed).
+		Build()
+
+	if err := nh.throttles.wait(ctx, webhookURL, priority); err != nil {
+		nh.logger.Error().Err(err).Msg("Gave up waiting to send overflow details")
+		return
+	}
+	if err := nh.discordNotifier.SendNotification(ctx, webhookURL, payload, filePath); err != nil {
+		nh.logger.Error().Err(err).Msg("Failed to send overflow details")
+	}
+}
@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -15,9 +16,18 @@ import (
 
 // NotificationHelper provides a high-level interface for sending various scan-related notifications.
 type NotificationHelper struct {
-	discordNotifier *discord.DiscordNotifier
-	cfg             config.NotificationConfig
-	logger          zerolog.Logger
+	discordNotifier  *discord.DiscordNotifier
+	cfg              config.NotificationConfig
+	logger           zerolog.Logger
+	reportCompressor *ReportCompressor
+	throttles        *webhookThrottles
+	quietHours       *quietHoursBuffer
+	messageTemplate  *MessageTemplate
+	routes           []webhookRoute
+	// Probe results per HTML report part, used to tell how many results were not attached
+	resultsPerReportPart int
+	// Leave report files on disk after they are sent, for the report browser
+	keepReportFiles bool
 }
 
 // NewNotificationHelper creates a new NotificationHelper.
@@ -26,29 +36,74 @@ func NewNotificationHelper(dn *discord.DiscordNotifier, cfg config.NotificationC
 		discordNotifier: dn,
 		cfg:             cfg,
 		logger:          logger.With().Str("module", "NotificationHelper").Logger(),
+		throttles:       newWebhookThrottles(cfg.MaxMessagesPerMinute),
 	}
+	nh.routes = newWebhookRoutes(cfg, nh.logger)
+	nh.reportCompressor = NewReportCompressor(cfg.ReportCompressionThresholdMB, nh.logger)
+	nh.quietHours = newQuietHoursBuffer(cfg.QuietHours, nh.sendQuietHoursDigest, nh.logger)
 	return nh
 }
 
-// getWebhookURL selects the appropriate webhook URL based on the service type.
-func (nh *NotificationHelper) getWebhookURL() string {
-	return nh.cfg.ScanServiceDiscordWebhookURL
+// WithMessageTemplate renders scan notifications with a custom template (see LoadMessageTemplate); nil keeps the built-in messages
+func (nh *NotificationHelper) WithMessageTemplate(mt *MessageTemplate) *NotificationHelper {
+	nh.messageTemplate = mt
+	return nh
+}
+
+// WithReportPartSize sets the reporter's max_probe_results_per_report_file, so notifications can tell how many
+// results the report parts beyond max_report_parts hold; 0 leaves the count out
+func (nh *NotificationHelper) WithReportPartSize(resultsPerPart int) *NotificationHelper {
+	nh.resultsPerReportPart = resultsPerPart
+	return nh
+}
+
+// WithKeptReportFiles leaves report files on disk once they are sent instead of removing them
+func (nh *NotificationHelper) WithKeptReportFiles(keep bool) *NotificationHelper {
+	nh.keepReportFiles = keep
+	return nh
+}
+
+// WithOnetimeMode tells quiet hours that the process exits after one scan; notifications are then only
+// deferred when quiet_hours.state_file can carry them to the next run
+func (nh *NotificationHelper) WithOnetimeMode(onetime bool) *NotificationHelper {
+	if nh.quietHours != nil {
+		nh.quietHours.exitsAfterScan = onetime
+	}
+	return nh
+}
+
+// sendToAllWebhooks sends payload to every scan webhook accepting severity, each with its own upload of
+// attachmentPath. A failing webhook does not stop the others; their errors are joined.
+func (nh *NotificationHelper) sendToAllWebhooks(ctx context.Context, payload discord.DiscordMessagePayload, attachmentPath string, priority notificationPriority, severity summary.Severity) error {
+	var errs []error
+	for i, route := range nh.routesFor(severity) {
+		if err := nh.sendPayloadWithPriority(ctx, route.url, route.withMentions(payload), attachmentPath, priority); err != nil {
+			// Webhook URLs embed their token, so errors name the webhook by position
+			errs = append(errs, fmt.Errorf("webhook %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // SendScanStartNotification sends a notification when a scan starts.
-func (nh *NotificationHelper) SendScanStartNotification(ctx context.Context, summary summary.ScanSummaryData) {
-	if !nh.cfg.NotifyOnScanStart || nh.discordNotifier == nil || nh.cfg.ScanServiceDiscordWebhookURL == "" {
+func (nh *NotificationHelper) SendScanStartNotification(ctx context.Context, summaryData summary.ScanSummaryData) {
+	if !nh.cfg.NotifyOnScanStart || nh.discordNotifier == nil || !nh.hasWebhooks() {
 		return
 	}
 
-	nh.logger.Info().Str("scan_session_id", summary.ScanSessionID).Str("target_source", summary.TargetSource).Int("total_targets", summary.TotalTargets).Msg("Preparing to send scan start notification.")
+	if nh.quietHours.deferIfQuiet(deferredNotification{Kind: deferredScanStart, Summary: summaryData}) {
+		return
+	}
+
+	nh.logger.Info().Str("scan_session_id", summaryData.ScanSessionID).Str("target_source", summaryData.TargetSource).Int("total_targets", summaryData.TotalTargets).Msg("Preparing to send scan start notification.")
 
-	payload := FormatScanStartMessage(summary, nh.cfg)
-	err := nh.discordNotifier.SendNotification(ctx, nh.cfg.ScanServiceDiscordWebhookURL, payload, "")
+	payload := FormatScanStartMessage(summaryData, nh.cfg)
+	nh.applyMessageTemplate(TemplateScanStart, summaryData, payload)
+	err := nh.sendToAllWebhooks(ctx, payload, "", priorityNormal, summary.SeverityInfo)
 	if err != nil {
 		nh.logger.Error().Err(err).Msg("Failed to send scan start notification")
 	} else {
-		nh.logger.Info().Str("scan_session_id", summary.ScanSessionID).Msg("Scan start notification sent successfully.")
+		nh.logger.Info().Str("scan_session_id", summaryData.ScanSessionID).Msg("Scan start notification sent successfully.")
 	}
 }
 
@@ -58,14 +113,36 @@ func (nh *NotificationHelper) SendScanCompletionNotification(ctx context.Context
 		return
 	}
 
-	webhookURL := nh.getWebhookURL()
-	if webhookURL == "" {
+	// A baseline scan exists to stop the first-run flood of "new" URLs: a successful one is not announced,
+	// and a failed one is reported without the diff reports
+	if summaryData.Baseline {
+		if summaryData.Status == string(summary.ScanStatusCompleted) {
+			nh.logger.Info().Str("scan_session_id", summaryData.ScanSessionID).Msg("Baseline scan recorded, skipping completion notification.")
+			return
+		}
+		reportFilePaths = nil
+	}
+
+	if !nh.hasWebhooks() {
 		nh.logger.Warn().Msg("Webhook URL is not configured for this service type. Skipping scan completion notification.")
 		return
 	}
 
+	// Only successful completions wait for quiet hours to end; failures go out immediately
+	if summaryData.Status == string(summary.ScanStatusCompleted) &&
+		nh.quietHours.deferIfQuiet(deferredNotification{Kind: deferredScanCompletion, Summary: summaryData, ReportPaths: reportFilePaths}) {
+		return
+	}
+
+	severity := summary.ScanSeverity(summaryData)
+	routes := nh.routesFor(severity)
+	if len(routes) == 0 {
+		nh.logger.Info().Str("scan_session_id", summaryData.ScanSessionID).Stringer("severity", severity).Msg("Scan completion is below the min_severity of every webhook, skipping notification.")
+		return
+	}
+
 	// Always send only summary notification (no individual report parts)
-	nh.sendSummaryOnlyReport(ctx, summaryData, webhookURL, reportFilePaths)
+	nh.sendSummaryOnlyReport(ctx, summaryData, routes, reportFilePaths)
 }
 
 // shouldSendScanCompletionNotification checks if notification should be sent based on config and scan status
@@ -81,20 +158,53 @@ func (nh *NotificationHelper) shouldSendScanCompletionNotification(summaryData s
 	return true
 }
 
-// sendSummaryOnlyReport sends a single notification with all report files attached
-func (nh *NotificationHelper) sendSummaryOnlyReport(ctx context.Context, summary summary.ScanSummaryData, webhookURL string, reportFilePaths []string) {
-	if len(reportFilePaths) > 0 {
-		// Send single notification with all reports
-		nh.sendSingleNotificationWithAllReports(ctx, summary, webhookURL, reportFilePaths)
-	} else {
+// sendSummaryOnlyReport sends a single notification with all report files attached to each route's webhook
+func (nh *NotificationHelper) sendSummaryOnlyReport(ctx context.Context, summary summary.ScanSummaryData, routes []webhookRoute, reportFilePaths []string) {
+	if len(reportFilePaths) == 0 {
 		// No reports to attach
-		nh.sendSingleReport(ctx, summary, webhookURL)
+		for _, route := range routes {
+			nh.sendSingleReport(ctx, summary, route)
+		}
+		return
+	}
+
+	parts := nh.limitReportParts(reportFilePaths)
+
+	// Uploads cannot be shared between webhooks, so every webhook gets its own copy of each report
+	deliveries := make(map[string]int, len(parts.attach))
+	for _, route := range routes {
+		for _, sentPath := range nh.sendSingleNotificationWithAllReports(ctx, summary, route, parts) {
+			deliveries[sentPath]++
+		}
+	}
+
+	if parts.archive != "" {
+		// The archive stands for every report file: they go once it reached every webhook
+		if deliveries[parts.archive] == len(routes) {
+			nh.cleanupReportFiles(reportFilePaths)
+		}
+		nh.removeFiles([]string{parts.archive})
+		return
+	}
+
+	// A report file is removed only once every webhook has received it; omitted files stay on disk
+	var sentReportFiles []string
+	for _, reportPath := range parts.attach {
+		if deliveries[reportPath] == len(routes) {
+			sentReportFiles = append(sentReportFiles, reportPath)
+		}
 	}
+	nh.cleanupReportFiles(sentReportFiles)
 }
 
-// sendSingleNotificationWithAllReports sends one notification with all report files attached
-func (nh *NotificationHelper) sendSingleNotificationWithAllReports(ctx context.Context, summary summary.ScanSummaryData, webhookURL string, reportFilePaths []string) {
+// sendSingleNotificationWithAllReports sends one notification with the attached report files of parts to
+// the route's webhook and returns the files it delivered
+func (nh *NotificationHelper) sendSingleNotificationWithAllReports(ctx context.Context, summary summary.ScanSummaryData, route webhookRoute, parts reportParts) []string {
+	reportFilePaths := parts.attach
 	payload := FormatScanCompleteMessageWithReports(summary, nh.cfg, true)
+	nh.applyMessageTemplate(TemplateScanComplete, summary, payload)
+	nh.addReportPartsNoteField(payload, parts)
+	payload = route.withMentions(payload)
 
 	// Update payload to indicate multiple reports in single notification
 	if len(reportFilePaths) > 1 {
@@ -108,10 +218,16 @@ func (nh *NotificationHelper) sendSingleNotificationWithAllReports(ctx context.C
 		Msg("Attempting to send scan completion notification with all reports.")
 
 	// Send notification with first report attached, then send additional reports separately
-	err := nh.discordNotifier.SendNotification(ctx, webhookURL, payload, reportFilePaths[0])
+	attachment := nh.reportCompressor.Prepare(reportFilePaths[0])
+	if attachment.Compressed {
+		nh.addCompressionNoteField(payload)
+	}
+
+	err := nh.sendPayloadWithPriority(ctx, route.url, payload, attachment.UploadPath, priorityCritical)
+	attachment.Cleanup(nh.logger)
 	if err != nil {
 		nh.logger.Error().Err(err).Msg("Failed to send scan completion notification")
-		return
+		return nil
 	}
 
 	nh.logger.Info().Msg("Scan completion notification sent successfully.")
@@ -122,7 +238,7 @@ func (nh *NotificationHelper) sendSingleNotificationWithAllReports(ctx context.C
 
 	// Send additional reports as follow-up messages if there are more than 1
 	for i := 1; i < len(reportFilePaths); i++ {
-		err := nh.sendAdditionalReport(ctx, summary, webhookURL, reportFilePaths[i], i+1, len(reportFilePaths))
+		err := nh.sendAdditionalReport(ctx, summary, route.url, reportFilePaths[i], i+1, len(reportFilePaths))
 		if err == nil {
 			// Only add to cleanup list if sent successfully
 			sentReportFiles = append(sentReportFiles, reportFilePaths[i])
@@ -134,21 +250,24 @@ func (nh *NotificationHelper) sendSingleNotificationWithAllReports(ctx context.C
 		}
 	}
 
-	// Cleanup all sent report files after successful notification
-	nh.cleanupReportFiles(sentReportFiles)
+	return sentReportFiles
 }
 
 // sendAdditionalReport sends additional report files as simple attachments
 func (nh *NotificationHelper) sendAdditionalReport(ctx context.Context, summary summary.ScanSummaryData, webhookURL string, reportPath string, partNum, totalParts int) error {
-	payload := nh.buildSimpleReportPayload(summary.ScanSessionID, partNum, totalParts)
+	attachment := nh.reportCompressor.Prepare(reportPath)
+	defer attachment.Cleanup(nh.logger)
+
+	payload := nh.buildSimpleReportPayload(summary.ScanSessionID, partNum, totalParts, attachment.Compressed)
 
 	nh.logger.Info().
 		Str("session_id", summary.ScanSessionID).
 		Int("part", partNum).
 		Int("total_parts", totalParts).
+		Bool("compressed", attachment.Compressed).
 		Msg("Sending additional report file.")
 
-	err := nh.discordNotifier.SendNotification(ctx, webhookURL, payload, reportPath)
+	err := nh.sendPayload(ctx, webhookURL, payload, attachment.UploadPath)
 	if err != nil {
 		nh.logger.Error().Err(err).Int("part", partNum).Msg("Failed to send additional report")
 		return err
@@ -156,9 +275,17 @@ func (nh *NotificationHelper) sendAdditionalReport(ctx context.Context, summary
 	return nil
 }
 
-// cleanupReportFiles removes report files after successful notification
+// cleanupReportFiles removes report files after successful notification, unless they are kept
 func (nh *NotificationHelper) cleanupReportFiles(reportFilePaths []string) {
-	for _, filePath := range reportFilePaths {
+	if nh.keepReportFiles {
+		return
+	}
+	nh.removeFiles(reportFilePaths)
+}
+
+// removeFiles deletes files, logging each result
+func (nh *NotificationHelper) removeFiles(filePaths []string) {
+	for _, filePath := range filePaths {
 		if filePath == "" {
 			continue
 		}
@@ -173,10 +300,15 @@ func (nh *NotificationHelper) cleanupReportFiles(reportFilePaths []string) {
 }
 
 // buildSimpleReportPayload creates a minimal payload for additional reports
-func (nh *NotificationHelper) buildSimpleReportPayload(sessionID string, partNum, totalParts int) discord.DiscordMessagePayload {
+func (nh *NotificationHelper) buildSimpleReportPayload(sessionID string, partNum, totalParts int, compressed bool) discord.DiscordMessagePayload {
+	description := fmt.Sprintf("**Session:** `%s`", sessionID)
+	if compressed {
+		description += "\n" + CompressedReportNote
+	}
+
 	embed := discord.NewDiscordEmbedBuilder().
 		WithTitle(fmt.Sprintf("📎 Report %d/%d", partNum, totalParts)).
-		WithDescription(fmt.Sprintf("**Session:** `%s`", sessionID)).
+		WithDescription(description).
 		WithColor(DefaultEmbedColor).
 		WithTimestamp(time.Now()).
 		Build()
@@ -188,6 +320,19 @@ func (nh *NotificationHelper) buildSimpleReportPayload(sessionID string, partNum
 		Build()
 }
 
+// addCompressionNoteField notes in the main embed that the attached report is gzip-compressed
+func (nh *NotificationHelper) addCompressionNoteField(payload discord.DiscordMessagePayload) {
+	if len(payload.Embeds) == 0 {
+		return
+	}
+
+	payload.Embeds[0].Fields = append(payload.Embeds[0].Fields, discord.DiscordEmbedField{
+		Name:   "🗜️ Compression",
+		Value:  CompressedReportNote,
+		Inline: false,
+	})
+}
+
 // adjustPayloadForMultipleReports modifies payload to indicate multiple reports are being sent
 func (nh *NotificationHelper) adjustPayloadForMultipleReports(payload discord.DiscordMessagePayload, reportCount int) {
 	for embedIdx := range payload.Embeds {
@@ -210,14 +355,16 @@ func (nh *NotificationHelper) adjustPayloadForMultipleReports(payload discord.Di
 	}
 }
 
-// sendSingleReport sends a single report without attachments
-func (nh *NotificationHelper) sendSingleReport(ctx context.Context, summary summary.ScanSummaryData, webhookURL string) {
+// sendSingleReport sends a single report without attachments to the route's webhook
+func (nh *NotificationHelper) sendSingleReport(ctx context.Context, summary summary.ScanSummaryData, route webhookRoute) {
 	payload := FormatScanCompleteMessageWithReports(summary, nh.cfg, false)
+	nh.applyMessageTemplate(TemplateScanComplete, summary, payload)
 	nh.adjustPayloadForNoAttachments(payload, summary)
+	payload = route.withMentions(payload)
 
 	nh.logger.Info().Str("status", summary.Status).Str("session_id", summary.ScanSessionID).Msg("Attempting to send scan completion notification (no report attachments).")
 
-	err := nh.discordNotifier.SendNotification(ctx, webhookURL, payload, "")
+	err := nh.sendPayloadWithPriority(ctx, route.url, payload, "", priorityCritical)
 	if err != nil {
 		nh.logger.Error().Err(err).Msg("Failed to send scan completion notification")
 	}
@@ -238,25 +385,78 @@ func (nh *NotificationHelper) adjustPayloadForNoAttachments(payload discord.Disc
 }
 
 // SendScanInterruptNotification sends a notification when a scan is interrupted.
-func (nh *NotificationHelper) SendScanInterruptNotification(ctx context.Context, summary summary.ScanSummaryData) {
+func (nh *NotificationHelper) SendScanInterruptNotification(ctx context.Context, summaryData summary.ScanSummaryData) {
 	if !nh.canSendScanFailureNotification() {
 		return
 	}
 
-	nh.logger.Info().Str("session_id", summary.ScanSessionID).Str("component", summary.Component).Msg("Preparing to send scan interrupt notification.")
+	nh.logger.Info().Str("session_id", summaryData.ScanSessionID).Str("component", summaryData.Component).Msg("Preparing to send scan interrupt notification.")
+
+	payload := FormatInterruptNotificationMessage(summaryData, nh.cfg)
+	nh.applyMessageTemplate(TemplateScanInterrupt, summaryData, payload)
+	nh.sendSimpleScanNotification(ctx, payload, "scan interrupt", priorityCritical, summary.SeverityHigh)
+}
+
+// SendCertificateExpiryNotification warns about hosts whose TLS certificate expires within warningDays
+func (nh *NotificationHelper) SendCertificateExpiryNotification(ctx context.Context, scanSessionID string, expiring []summary.HostStats, warningDays int) {
+	if len(expiring) == 0 || nh.discordNotifier == nil || !nh.hasWebhooks() {
+		return
+	}
+
+	nh.logger.Info().Str("session_id", scanSessionID).Int("hosts", len(expiring)).Msg("Sending TLS certificate expiry notification.")
+
+	now := time.Now()
+	// Expiring certificates need attention soon; expired ones already break clients
+	severity := summary.SeverityMedium
+	for _, host := range expiring {
+		if !host.CertNotAfter.After(now) {
+			severity = summary.SeverityHigh
+			break
+		}
+	}
+
+	payload := FormatCertificateExpiryMessage(scanSessionID, expiring, warningDays, now, nh.cfg)
+	nh.sendSimpleScanNotification(ctx, payload, "certificate expiry", priorityNormal, severity)
+}
+
+// SendNewHostsNotification announces the hosts a scan probed for the first time
+func (nh *NotificationHelper) SendNewHostsNotification(ctx context.Context, summaryData summary.ScanSummaryData) {
+	if len(summaryData.NewHosts) == 0 || nh.discordNotifier == nil || !nh.hasWebhooks() {
+		return
+	}
+
+	nh.logger.Info().Str("session_id", summaryData.ScanSessionID).Int("hosts", len(summaryData.NewHosts)).Msg("Sending new hosts notification.")
+
+	payload := FormatNewHostsMessage(summaryData, nh.cfg)
+	nh.sendSimpleScanNotification(ctx, payload, "new hosts", priorityNormal, summary.SeverityHigh)
+}
 
-	payload := FormatInterruptNotificationMessage(summary, nh.cfg)
-	nh.sendSimpleScanNotification(ctx, payload, "scan interrupt")
+// SendChangeDigestNotification sends the periodic change digest. It is sent even when nothing changed,
+// so a quiet week is distinguishable from a stopped scheduler.
+func (nh *NotificationHelper) SendChangeDigestNotification(ctx context.Context, digest summary.ChangeDigest) {
+	if nh.discordNotifier == nil || !nh.hasWebhooks() {
+		return
+	}
+
+	nh.logger.Info().
+		Time("window_start", digest.WindowStart).
+		Int("scans", digest.Scans).
+		Int("new_urls", digest.NewURLs).
+		Int("old_urls", digest.OldURLs).
+		Msg("Sending change digest notification.")
+
+	payload := FormatChangeDigestMessage(digest, nh.cfg)
+	nh.sendSimpleScanNotification(ctx, payload, "change digest", priorityNormal, summary.SeverityInfo)
 }
 
 // canSendScanFailureNotification checks if scan failure notifications can be sent
 func (nh *NotificationHelper) canSendScanFailureNotification() bool {
-	return nh.cfg.NotifyOnFailure && nh.discordNotifier != nil && nh.cfg.ScanServiceDiscordWebhookURL != ""
+	return nh.cfg.NotifyOnFailure && nh.discordNotifier != nil && nh.hasWebhooks()
 }
 
-// sendSimpleScanNotification sends a scan notification without file attachment
-func (nh *NotificationHelper) sendSimpleScanNotification(ctx context.Context, payload discord.DiscordMessagePayload, notificationType string) {
-	err := nh.discordNotifier.SendNotification(ctx, nh.cfg.ScanServiceDiscordWebhookURL, payload, "")
+// sendSimpleScanNotification sends a scan notification without file attachment to the webhooks accepting severity
+func (nh *NotificationHelper) sendSimpleScanNotification(ctx context.Context, payload discord.DiscordMessagePayload, notificationType string, priority notificationPriority, severity summary.Severity) {
+	err := nh.sendToAllWebhooks(ctx, payload, "", priority, severity)
 	if err != nil {
 		nh.logger.Error().Err(err).Msgf("Failed to send %s notification", notificationType)
 	}
@@ -0,0 +1,146 @@
+package notifier
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+)
+
+// Names of the templates looked up in NotificationConfig.TemplatePath; a message whose
+// template is not defined keeps the built-in formatting
+const (
+	TemplateScanStart     = "scan_start"
+	TemplateScanComplete  = "scan_complete"
+	TemplateScanInterrupt = "scan_interrupt"
+)
+
+var messageTemplateNames = []string{TemplateScanStart, TemplateScanComplete, TemplateScanInterrupt}
+
+// MessageTemplate renders the embed description and fields of scan notifications from a Go template file.
+// Each template receives the summary.ScanSummaryData of the message; its output becomes the embed
+// description, and fields added with {{field "Name" "value"}} or {{inlineField ...}} replace the built-in fields.
+type MessageTemplate struct {
+	path string
+	tmpl *template.Template
+}
+
+// LoadMessageTemplate parses the template file at path and renders each defined message once with sample
+// data, so a broken template fails at startup instead of when a notification is due. An empty path returns nil.
+func LoadMessageTemplate(path string) (*MessageTemplate, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to read notification template: "+path)
+	}
+
+	tmpl, err := template.New("notification").Funcs(messageTemplateFuncs(nil)).Parse(string(content))
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to parse notification template: "+path)
+	}
+
+	mt := &MessageTemplate{path: path, tmpl: tmpl}
+	defined := 0
+	for _, name := range messageTemplateNames {
+		_, _, ok, err := mt.render(name, sampleTemplateSummary())
+		if err != nil {
+			return nil, errorwrapper.WrapError(err, "notification template '"+name+"' failed to render")
+		}
+		if ok {
+			defined++
+		}
+	}
+
+	if defined == 0 {
+		return nil, errorwrapper.NewValidationError("template_path", path,
+			"template defines none of "+strings.Join(messageTemplateNames, ", "))
+	}
+	return mt, nil
+}
+
+// render executes the named template. ok is false when the file does not define it.
+func (mt *MessageTemplate) render(name string, data summary.ScanSummaryData) (string, []discord.DiscordEmbedField, bool, error) {
+	if mt == nil || mt.tmpl.Lookup(name) == nil {
+		return "", nil, false, nil
+	}
+
+	// Clone so concurrent renders collect fields independently
+	tmpl, err := mt.tmpl.Clone()
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	var fields []discord.DiscordEmbedField
+	var out bytes.Buffer
+	if err := tmpl.Funcs(messageTemplateFuncs(&fields)).ExecuteTemplate(&out, name, data); err != nil {
+		return "", nil, false, err
+	}
+	return strings.TrimSpace(out.String()), fields, true, nil
+}
+
+// messageTemplateFuncs returns the functions available to templates; field calls append to fields
+func messageTemplateFuncs(fields *[]discord.DiscordEmbedField) template.FuncMap {
+	addField := func(inline bool) func(name, value string) string {
+		return func(name, value string) string {
+			if fields != nil {
+				*fields = append(*fields, discord.DiscordEmbedField{Name: name, Value: value, Inline: inline})
+			}
+			return ""
+		}
+	}
+
+	return template.FuncMap{
+		"field":       addField(false),
+		"inlineField": addField(true),
+		"duration":    formatDuration,
+		"upper":       strings.ToUpper,
+		"lower":       strings.ToLower,
+		"join":        strings.Join,
+		"hosts":       summary.SortedHostStats,
+	}
+}
+
+// sampleTemplateSummary returns summary data with every collection populated, so validation renders loop bodies too
+func sampleTemplateSummary() summary.ScanSummaryData {
+	data := summary.GetDefaultScanSummaryData()
+	data.ScanSessionID = "20240101-120000"
+	data.TargetSource = "targets.txt"
+	data.ScanMode = "onetime"
+	data.Targets = []string{"https://example.com"}
+	data.TotalTargets = 1
+	data.Status = string(summary.ScanStatusCompleted)
+	data.ErrorMessages = []string{"sample error"}
+	data.HostStats = map[string]summary.HostStats{
+		"example.com": {Host: "example.com", TotalProbed: 1, New: 1, StatusCodes: map[int]int{200: 1}},
+	}
+	return data
+}
+
+// applyMessageTemplate replaces the description of the payload's first embed with the rendered template,
+// and its fields when the template adds any. Render errors are logged and the built-in message is kept.
+func (nh *NotificationHelper) applyMessageTemplate(name string, data summary.ScanSummaryData, payload discord.DiscordMessagePayload) {
+	if nh.messageTemplate == nil || len(payload.Embeds) == 0 {
+		return
+	}
+
+	description, fields, ok, err := nh.messageTemplate.render(name, data)
+	if err != nil {
+		nh.logger.Error().Err(err).Str("template", name).Str("template_path", nh.messageTemplate.path).Msg("Failed to render notification template, using built-in message")
+		return
+	}
+	if !ok {
+		return
+	}
+
+	payload.Embeds[0].Description = description
+	if len(fields) > 0 {
+		payload.Embeds[0].Fields = fields
+	}
+}
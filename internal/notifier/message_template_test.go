@@ -0,0 +1,116 @@
+package notifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMessageTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notification.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadMessageTemplate(t *testing.T) {
+	mt, err := LoadMessageTemplate("")
+	require.NoError(t, err)
+	assert.Nil(t, mt, "no template path keeps the built-in messages")
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "parse error", content: `{{define "scan_start"}}{{.ScanSessionID}{{end}}`, wantErr: "failed to parse"},
+		{name: "no known template", content: `{{define "scan_finished"}}done{{end}}`, wantErr: "defines none of"},
+		{name: "render error on sample data", content: `{{define "scan_complete"}}{{.NoSuchField}}{{end}}`, wantErr: "'scan_complete' failed to render"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMessageTemplate(writeMessageTemplate(t, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err = LoadMessageTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	assert.ErrorContains(t, err, "failed to read")
+}
+
+func TestMessageTemplate_Render(t *testing.T) {
+	mt, err := LoadMessageTemplate(writeMessageTemplate(t, `
+{{define "scan_complete"}}
+**{{upper .Status}}** session {{.ScanSessionID}}
+{{- inlineField "Targets" (printf "%d" .TotalTargets)}}
+{{- range hosts .HostStats}}{{field .Host (printf "%d new" .New)}}{{end}}
+{{end}}`))
+	require.NoError(t, err)
+
+	data := sampleTemplateSummary()
+	description, fields, ok, err := mt.render(TemplateScanComplete, data)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "**COMPLETED** session 20240101-120000", description)
+	assert.Equal(t, []discord.DiscordEmbedField{
+		{Name: "Targets", Value: "1", Inline: true},
+		{Name: "example.com", Value: "1 new"},
+	}, fields)
+
+	_, _, ok, err = mt.render(TemplateScanStart, data)
+	require.NoError(t, err)
+	assert.False(t, ok, "undefined templates are reported, not rendered empty")
+}
+
+func TestNotificationHelper_ApplyMessageTemplate(t *testing.T) {
+	mt, err := LoadMessageTemplate(writeMessageTemplate(t, `
+{{define "scan_start"}}Starting {{.ScanSessionID}}{{end}}
+{{define "scan_complete"}}Done{{inlineField "Status" .Status}}{{end}}
+{{define "scan_interrupt"}}{{if eq .ScanSessionID "broken"}}{{index .Targets 5}}{{end}}Interrupted{{end}}`))
+	require.NoError(t, err)
+	nh := NewNotificationHelper(nil, config.NewDefaultNotificationConfig(), zerolog.Nop()).WithMessageTemplate(mt)
+
+	builtIn := func() discord.DiscordMessagePayload {
+		return discord.DiscordMessagePayload{Embeds: []discord.DiscordEmbed{{
+			Title:       "Scan",
+			Description: "built-in",
+			Fields:      []discord.DiscordEmbedField{{Name: "Built-in", Value: "field"}},
+		}}}
+	}
+	data := summary.ScanSummaryData{ScanSessionID: "s1", Status: "COMPLETED"}
+
+	t.Run("description only keeps the built-in fields", func(t *testing.T) {
+		payload := builtIn()
+		nh.applyMessageTemplate(TemplateScanStart, data, payload)
+		assert.Equal(t, "Starting s1", payload.Embeds[0].Description)
+		assert.Equal(t, "Built-in", payload.Embeds[0].Fields[0].Name)
+		assert.Equal(t, "Scan", payload.Embeds[0].Title, "the title is never templated")
+	})
+
+	t.Run("template fields replace the built-in fields", func(t *testing.T) {
+		payload := builtIn()
+		nh.applyMessageTemplate(TemplateScanComplete, data, payload)
+		assert.Equal(t, "Done", payload.Embeds[0].Description)
+		assert.Equal(t, []discord.DiscordEmbedField{{Name: "Status", Value: "COMPLETED", Inline: true}}, payload.Embeds[0].Fields)
+	})
+
+	t.Run("a render error at send time keeps the built-in message", func(t *testing.T) {
+		payload := builtIn()
+		nh.applyMessageTemplate(TemplateScanInterrupt, summary.ScanSummaryData{ScanSessionID: "broken"}, payload)
+		assert.Equal(t, builtIn(), payload)
+	})
+
+	t.Run("no template keeps the built-in message", func(t *testing.T) {
+		payload := builtIn()
+		nh.WithMessageTemplate(nil).applyMessageTemplate(TemplateScanStart, data, payload)
+		assert.Equal(t, builtIn(), payload)
+	})
+}
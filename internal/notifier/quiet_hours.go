@@ -0,0 +1,352 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/rs/zerolog"
+)
+
+// Kinds of notifications that may be held back during quiet hours
+const (
+	deferredScanStart      = "scan_start"
+	deferredScanCompletion = "scan_completion"
+)
+
+var quietHoursWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// quietHoursWindow is the parsed form of config.QuietHoursConfig
+type quietHoursWindow struct {
+	startHour, startMinute int
+	endHour, endMinute     int
+	location               *time.Location
+	days                   map[time.Weekday]bool // empty means every day
+}
+
+// newQuietHoursWindow parses the configured start/end times, timezone and days
+func newQuietHoursWindow(cfg config.QuietHoursConfig) (*quietHoursWindow, error) {
+	start, err := time.Parse("15:04", cfg.Start)
+	if err != nil {
+		return nil, errorwrapper.NewValidationError("quiet_hours.start", cfg.Start, "expected HH:MM")
+	}
+	end, err := time.Parse("15:04", cfg.End)
+	if err != nil {
+		return nil, errorwrapper.NewValidationError("quiet_hours.end", cfg.End, "expected HH:MM")
+	}
+
+	location := time.Local
+	if cfg.Timezone != "" {
+		if location, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, errorwrapper.WrapError(err, "invalid quiet hours timezone")
+		}
+	}
+
+	days := make(map[time.Weekday]bool, len(cfg.Days))
+	for _, day := range cfg.Days {
+		weekday, ok := quietHoursWeekdays[strings.ToLower(day)]
+		if !ok {
+			return nil, errorwrapper.NewValidationError("quiet_hours.days", day, "expected mon, tue, wed, thu, fri, sat or sun")
+		}
+		days[weekday] = true
+	}
+
+	return &quietHoursWindow{
+		startHour:   start.Hour(),
+		startMinute: start.Minute(),
+		endHour:     end.Hour(),
+		endMinute:   end.Minute(),
+		location:    location,
+		days:        days,
+	}, nil
+}
+
+// activeUntil reports whether now falls inside a quiet window and, if so, when that window ends.
+// A window belongs to the day it starts on, so an overnight window started on Friday ends on Saturday.
+func (w *quietHoursWindow) activeUntil(now time.Time) (time.Time, bool) {
+	local := now.In(w.location)
+	overnight := w.endHour*60+w.endMinute <= w.startHour*60+w.startMinute
+
+	// Only a window that started today or yesterday can still be open
+	for offset := 0; offset >= -1; offset-- {
+		day := local.AddDate(0, 0, offset)
+		if len(w.days) > 0 && !w.days[day.Weekday()] {
+			continue
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day(), w.startHour, w.startMinute, 0, 0, w.location)
+		end := time.Date(day.Year(), day.Month(), day.Day(), w.endHour, w.endMinute, 0, 0, w.location)
+		if overnight {
+			end = end.AddDate(0, 0, 1)
+		}
+
+		if !local.Before(start) && local.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// deferredNotification is a notification held back during quiet hours
+type deferredNotification struct {
+	Kind        string                  `json:"kind"`
+	QueuedAt    time.Time               `json:"queued_at"`
+	Summary     summary.ScanSummaryData `json:"summary"`
+	ReportPaths []string                `json:"report_paths,omitempty"`
+}
+
+// quietHoursBuffer collects non-critical notifications during quiet hours and hands them to
+// flushFn as one batch when the window ends. A nil buffer never defers anything.
+type quietHoursBuffer struct {
+	mu        sync.Mutex
+	window    *quietHoursWindow
+	stateFile string
+	entries   []deferredNotification
+	timer     *time.Timer
+	flushFn   func([]deferredNotification)
+	logger    zerolog.Logger
+
+	// exitsAfterScan is set for onetime runs, where the process ends before the window does
+	exitsAfterScan bool
+}
+
+// newQuietHoursBuffer returns nil when quiet hours are disabled or misconfigured. Notifications
+// left in the state file by a previous run are restored and delivered at the next window end.
+func newQuietHoursBuffer(cfg config.QuietHoursConfig, flushFn func([]deferredNotification), logger zerolog.Logger) *quietHoursBuffer {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	bufferLogger := logger.With().Str("component", "QuietHours").Logger()
+	window, err := newQuietHoursWindow(cfg)
+	if err != nil {
+		bufferLogger.Error().Err(err).Msg("Invalid quiet hours configuration, notifications will not be deferred")
+		return nil
+	}
+
+	qb := &quietHoursBuffer{
+		window:    window,
+		stateFile: cfg.StateFile,
+		flushFn:   flushFn,
+		logger:    bufferLogger,
+	}
+	qb.restore()
+	return qb
+}
+
+// deferIfQuiet queues the notification if quiet hours are active and reports whether it was queued
+func (qb *quietHoursBuffer) deferIfQuiet(entry deferredNotification) bool {
+	if qb == nil {
+		return false
+	}
+
+	now := time.Now()
+	windowEnd, quiet := qb.window.activeUntil(now)
+	if !quiet {
+		return false
+	}
+
+	qb.mu.Lock()
+	defer qb.mu.Unlock()
+
+	// Without a state file the queue dies with a onetime process, so the notification goes out now
+	if qb.exitsAfterScan && qb.stateFile == "" {
+		qb.logger.Info().
+			Str("kind", entry.Kind).
+			Str("scan_session_id", entry.Summary.ScanSessionID).
+			Msg("Quiet hours active but no state_file is set for this onetime run, sending notification now")
+		return false
+	}
+
+	entry.QueuedAt = now
+	qb.entries = append(qb.entries, entry)
+	qb.persistLocked()
+	qb.scheduleLocked(windowEnd)
+
+	qb.logger.Info().
+		Str("kind", entry.Kind).
+		Str("scan_session_id", entry.Summary.ScanSessionID).
+		Int("queued", len(qb.entries)).
+		Time("window_end", windowEnd).
+		Msg("Quiet hours active, notification deferred to digest")
+	return true
+}
+
+// scheduleLocked arms the flush timer unless one is already pending. Caller must hold qb.mu.
+func (qb *quietHoursBuffer) scheduleLocked(at time.Time) {
+	if qb.timer != nil {
+		return
+	}
+	qb.timer = time.AfterFunc(time.Until(at), qb.flush)
+}
+
+// flush hands all queued notifications to flushFn, re-arming the timer if a new window has already begun
+func (qb *quietHoursBuffer) flush() {
+	qb.mu.Lock()
+	qb.timer = nil
+	if windowEnd, quiet := qb.window.activeUntil(time.Now()); quiet {
+		// Back-to-back windows: keep collecting until the combined quiet period ends
+		qb.scheduleLocked(windowEnd)
+		qb.mu.Unlock()
+		return
+	}
+
+	entries := qb.entries
+	qb.entries = nil
+	qb.persistLocked()
+	qb.mu.Unlock()
+
+	if len(entries) > 0 {
+		qb.logger.Info().Int("count", len(entries)).Msg("Quiet hours ended, sending notification digest")
+		qb.flushFn(entries)
+	}
+}
+
+// restore loads notifications saved by a previous process and schedules their delivery
+func (qb *quietHoursBuffer) restore() {
+	if qb.stateFile == "" {
+		return
+	}
+
+	data, err := os.ReadFile(qb.stateFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			qb.logger.Warn().Err(err).Str("state_file", qb.stateFile).Msg("Failed to read quiet hours state file")
+		}
+		return
+	}
+
+	var entries []deferredNotification
+	if err := json.Unmarshal(data, &entries); err != nil {
+		qb.logger.Warn().Err(err).Str("state_file", qb.stateFile).Msg("Ignoring unreadable quiet hours state file")
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	qb.mu.Lock()
+	defer qb.mu.Unlock()
+
+	qb.entries = entries
+	windowEnd, quiet := qb.window.activeUntil(time.Now())
+	if !quiet {
+		windowEnd = time.Now()
+	}
+	qb.scheduleLocked(windowEnd)
+	qb.logger.Info().Int("count", len(entries)).Time("deliver_at", windowEnd).Msg("Restored deferred notifications from previous run")
+}
+
+// persistLocked mirrors the queue to the state file, removing it when the queue is empty. Caller must hold qb.mu.
+func (qb *quietHoursBuffer) persistLocked() {
+	if qb.stateFile == "" {
+		return
+	}
+
+	if len(qb.entries) == 0 {
+		if err := os.Remove(qb.stateFile); err != nil && !os.IsNotExist(err) {
+			qb.logger.Warn().Err(err).Str("state_file", qb.stateFile).Msg("Failed to remove quiet hours state file")
+		}
+		return
+	}
+
+	data, err := json.Marshal(qb.entries)
+	if err != nil {
+		qb.logger.Warn().Err(err).Msg("Failed to encode deferred notifications")
+		return
+	}
+	if dir := filepath.Dir(qb.stateFile); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			qb.logger.Warn().Err(err).Str("dir", dir).Msg("Failed to create quiet hours state directory")
+			return
+		}
+	}
+
+	tmpPath := qb.stateFile + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		qb.logger.Warn().Err(err).Str("state_file", tmpPath).Msg("Failed to write quiet hours state file")
+		return
+	}
+	if err := os.Rename(tmpPath, qb.stateFile); err != nil {
+		qb.logger.Warn().Err(err).Str("state_file", qb.stateFile).Msg("Failed to replace quiet hours state file")
+	}
+}
+
+// sendQuietHoursDigest delivers notifications deferred during quiet hours as one message
+func (nh *NotificationHelper) sendQuietHoursDigest(entries []deferredNotification) {
+	if nh.discordNotifier == nil || !nh.hasWebhooks() {
+		return
+	}
+
+	// The digest reaches every webhook that would have received one of its notifications
+	severity := summary.SeverityInfo
+	for _, entry := range entries {
+		if entry.Kind == deferredScanCompletion {
+			severity = max(severity, summary.ScanSeverity(entry.Summary))
+		}
+	}
+
+	payload := formatQuietHoursDigest(entries, nh.cfg)
+	if err := nh.sendToAllWebhooks(context.Background(), payload, "", priorityNormal, severity); err != nil {
+		nh.logger.Error().Err(err).Int("count", len(entries)).Msg("Failed to send quiet hours digest")
+		return
+	}
+	nh.logger.Info().Int("count", len(entries)).Msg("Quiet hours digest sent successfully.")
+}
+
+// formatQuietHoursDigest builds a single message summarising every deferred notification
+func formatQuietHoursDigest(entries []deferredNotification, cfg config.NotificationConfig) discord.DiscordMessagePayload {
+	description := fmt.Sprintf("**%d** notification(s) were held during quiet hours, first queued at %s.",
+		len(entries), entries[0].QueuedAt.Format("2006-01-02 15:04 MST"))
+
+	embedBuilder := discord.NewDiscordEmbedBuilder().
+		WithTitle("🌙 Quiet Hours Digest").
+		WithDescription(description).
+		WithColor(InfoEmbedColor).
+		WithTimestamp(time.Now()).
+		WithFooter("MonsterInc Scanner", "")
+
+	for _, entry := range entries {
+		name, value := formatDeferredEntry(entry)
+		embedBuilder.AddField(name, value, false)
+	}
+
+	return discord.NewDiscordMessagePayloadBuilder().
+		WithUsername(DiscordUsername).
+		WithAvatarURL(DiscordAvatarURL).
+		WithContent(buildMentions(cfg.MentionRoleIDs)).
+		AddEmbed(embedBuilder.Build()).
+		Build()
+}
+
+// formatDeferredEntry renders one deferred notification as an embed field
+func formatDeferredEntry(entry deferredNotification) (string, string) {
+	s := entry.Summary
+	queued := entry.QueuedAt.Format("15:04 MST")
+
+	if entry.Kind == deferredScanStart {
+		return fmt.Sprintf("🚀 Scan started · %s", s.ScanSessionID),
+			fmt.Sprintf("**Targets:** %d (%s)\n**At:** %s", s.TotalTargets, s.TargetSource, queued)
+	}
+
+	value := fmt.Sprintf("**Targets:** %d · **Duration:** %s · **At:** %s\n**Probed:** %d (%d ok, %d failed)\n**Diff:** %d new, %d existing, %d old",
+		s.TotalTargets, formatDuration(s.ScanDuration), queued,
+		s.ProbeStats.TotalProbed, s.ProbeStats.SuccessfulProbes, s.ProbeStats.FailedProbes,
+		s.DiffStats.New, s.DiffStats.Existing, s.DiffStats.Old)
+	if len(entry.ReportPaths) > 0 {
+		value += fmt.Sprintf("\n**Reports:** %d kept on disk (`%s`)", len(entry.ReportPaths), filepath.Dir(entry.ReportPaths[0]))
+	}
+	return fmt.Sprintf("✅ Scan completed · %s", s.ScanSessionID), value
+}
@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// alwaysQuiet is a window that covers every minute of every day
+func alwaysQuiet(stateFile string) config.QuietHoursConfig {
+	return config.QuietHoursConfig{Enabled: true, Start: "00:00", End: "00:00", Timezone: "UTC", StateFile: stateFile}
+}
+
+func completedEntry(sessionID string) deferredNotification {
+	return deferredNotification{
+		Kind:    deferredScanCompletion,
+		Summary: summary.ScanSummaryData{ScanSessionID: sessionID, Status: string(summary.ScanStatusCompleted)},
+	}
+}
+
+func TestQuietHoursWindow_ActiveUntil(t *testing.T) {
+	window, err := newQuietHoursWindow(config.QuietHoursConfig{Start: "22:00", End: "08:00", Timezone: "UTC", Days: []string{"fri"}})
+	require.NoError(t, err)
+
+	// 2025-01-03 is a Friday
+	friday := func(hour int) time.Time { return time.Date(2025, 1, 3, hour, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		quiet  bool
+		endsAt time.Time
+	}{
+		{name: "before the window", now: friday(21)},
+		{name: "friday evening", now: friday(23), quiet: true, endsAt: time.Date(2025, 1, 4, 8, 0, 0, 0, time.UTC)},
+		{name: "saturday morning of a friday window", now: friday(24 + 7), quiet: true, endsAt: time.Date(2025, 1, 4, 8, 0, 0, 0, time.UTC)},
+		{name: "after the window", now: friday(24 + 8)},
+		{name: "saturday evening is not a window day", now: friday(24 + 23)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endsAt, quiet := window.activeUntil(tt.now)
+			assert.Equal(t, tt.quiet, quiet)
+			if tt.quiet {
+				assert.True(t, tt.endsAt.Equal(endsAt), "window ends at %s, got %s", tt.endsAt, endsAt)
+			}
+		})
+	}
+}
+
+func TestQuietHoursBuffer_DefersDuringWindow(t *testing.T) {
+	qb := newQuietHoursBuffer(alwaysQuiet(""), func([]deferredNotification) {}, zerolog.Nop())
+	require.NotNil(t, qb)
+
+	assert.True(t, qb.deferIfQuiet(completedEntry("automated")), "a long-running process holds the notification in memory")
+	assert.Len(t, qb.entries, 1)
+}
+
+func TestQuietHoursBuffer_OnetimeWithoutStateFileSendsNow(t *testing.T) {
+	nh := NewNotificationHelper(nil, config.NotificationConfig{QuietHours: alwaysQuiet("")}, zerolog.Nop()).
+		WithOnetimeMode(true)
+	require.NotNil(t, nh.quietHours)
+
+	assert.False(t, nh.quietHours.deferIfQuiet(completedEntry("onetime")), "nothing would deliver the digest after the process exits")
+	assert.Empty(t, nh.quietHours.entries)
+}
+
+func TestQuietHoursBuffer_OnetimeWithStateFileDefersAndRestores(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state", "quiet_hours.json")
+	nh := NewNotificationHelper(nil, config.NotificationConfig{QuietHours: alwaysQuiet(stateFile)}, zerolog.Nop()).
+		WithOnetimeMode(true)
+
+	require.True(t, nh.quietHours.deferIfQuiet(completedEntry("first run")))
+	_, err := os.Stat(stateFile)
+	require.NoError(t, err, "the deferred notification is persisted for the next run")
+
+	// The next run picks the notification up from the state file and sends it once the window ends
+	restored := newQuietHoursBuffer(alwaysQuiet(stateFile), func([]deferredNotification) {}, zerolog.Nop())
+	require.Len(t, restored.entries, 1)
+	assert.Equal(t, "first run", restored.entries[0].Summary.ScanSessionID)
+	assert.NotNil(t, restored.timer)
+	restored.timer.Stop()
+}
+
+func TestQuietHoursBuffer_FlushSendsDigestAndClearsState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "quiet_hours.json")
+	qb := newQuietHoursBuffer(alwaysQuiet(stateFile), nil, zerolog.Nop())
+
+	var flushed []deferredNotification
+	qb.flushFn = func(entries []deferredNotification) { flushed = entries }
+	require.True(t, qb.deferIfQuiet(completedEntry("queued")))
+	qb.timer.Stop()
+
+	// Once no window is open, the flush hands everything over and removes the state file
+	qb.window.days = map[time.Weekday]bool{(time.Now().UTC().Weekday() + 3) % 7: true}
+	qb.flush()
+
+	require.Len(t, flushed, 1)
+	assert.Equal(t, "queued", flushed[0].Summary.ScanSessionID)
+	_, err := os.Stat(stateFile)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,112 @@
+package notifier
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/rs/zerolog"
+)
+
+// ReportAttachment describes a report file prepared for Discord upload
+type ReportAttachment struct {
+	OriginalPath string
+	UploadPath   string
+	Compressed   bool
+}
+
+// Cleanup removes the temporary compressed file, if one was created
+func (ra ReportAttachment) Cleanup(logger zerolog.Logger) {
+	if !ra.Compressed || ra.UploadPath == "" {
+		return
+	}
+
+	if err := os.Remove(ra.UploadPath); err != nil && !os.IsNotExist(err) {
+		logger.Warn().Err(err).Str("file_path", ra.UploadPath).Msg("Failed to remove temporary compressed report")
+	}
+}
+
+// ReportCompressor gzips report files that exceed a size threshold before they are attached
+type ReportCompressor struct {
+	thresholdBytes int64
+	logger         zerolog.Logger
+}
+
+// NewReportCompressor creates a new ReportCompressor. A threshold of 0 disables compression.
+func NewReportCompressor(thresholdMB int, logger zerolog.Logger) *ReportCompressor {
+	return &ReportCompressor{
+		thresholdBytes: int64(thresholdMB) * 1024 * 1024,
+		logger:         logger.With().Str("component", "ReportCompressor").Logger(),
+	}
+}
+
+// Prepare returns the attachment to upload for reportPath, compressing it when it exceeds the threshold.
+// On compression failure the original file is used so the report is still delivered.
+func (rc *ReportCompressor) Prepare(reportPath string) ReportAttachment {
+	attachment := ReportAttachment{OriginalPath: reportPath, UploadPath: reportPath}
+
+	// Zip archives of report parts are compressed already
+	if rc.thresholdBytes <= 0 || reportPath == "" || strings.HasSuffix(reportPath, ".zip") {
+		return attachment
+	}
+
+	info, err := os.Stat(reportPath)
+	if err != nil {
+		rc.logger.Warn().Err(err).Str("file_path", reportPath).Msg("Failed to stat report file, sending uncompressed")
+		return attachment
+	}
+
+	if info.Size() <= rc.thresholdBytes {
+		return attachment
+	}
+
+	gzPath, err := rc.compressFile(reportPath)
+	if err != nil {
+		rc.logger.Warn().Err(err).Str("file_path", reportPath).Msg("Failed to compress report file, sending uncompressed")
+		return attachment
+	}
+
+	rc.logger.Info().
+		Str("file_path", reportPath).
+		Str("compressed_path", gzPath).
+		Int64("original_size", info.Size()).
+		Msg("Report file compressed for attachment")
+
+	attachment.UploadPath = gzPath
+	attachment.Compressed = true
+	return attachment
+}
+
+// compressFile writes a gzip copy of srcPath next to it and returns the new path
+func (rc *ReportCompressor) compressFile(srcPath string) (string, error) {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", errorwrapper.WrapError(err, "failed to open report file")
+	}
+	defer func() { _ = src.Close() }()
+
+	gzPath := srcPath + ".gz"
+	dst, err := os.Create(gzPath)
+	if err != nil {
+		return "", errorwrapper.WrapError(err, "failed to create compressed report file")
+	}
+
+	gzWriter := gzip.NewWriter(dst)
+	gzWriter.Name = filepath.Base(srcPath)
+
+	_, copyErr := io.Copy(gzWriter, src)
+	closeErr := gzWriter.Close()
+	fileErr := dst.Close()
+
+	for _, err := range []error{copyErr, closeErr, fileErr} {
+		if err != nil {
+			_ = os.Remove(gzPath)
+			return "", errorwrapper.WrapError(err, "failed to write compressed report file")
+		}
+	}
+
+	return gzPath, nil
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeReport writes an HTML report of exactly size bytes
+func writeReport(t *testing.T, name string, size int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	content := "<html>" + strings.Repeat("a", size-len("<html></html>")) + "</html>"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestReportCompressor_Prepare(t *testing.T) {
+	const oneMB = 1024 * 1024
+	compressor := NewReportCompressor(1, zerolog.Nop())
+
+	t.Run("at the threshold is sent as is", func(t *testing.T) {
+		path := writeReport(t, "report.html", oneMB)
+		attachment := compressor.Prepare(path)
+		assert.Equal(t, ReportAttachment{OriginalPath: path, UploadPath: path}, attachment)
+		assert.NoFileExists(t, path+".gz")
+	})
+
+	t.Run("a threshold of 0 disables compression", func(t *testing.T) {
+		path := writeReport(t, "report.html", oneMB+1)
+		attachment := NewReportCompressor(0, zerolog.Nop()).Prepare(path)
+		assert.False(t, attachment.Compressed)
+		assert.Equal(t, path, attachment.UploadPath)
+	})
+
+	t.Run("zip archives are passed through", func(t *testing.T) {
+		path := writeReport(t, "reports.zip", oneMB+1)
+		attachment := compressor.Prepare(path)
+		assert.False(t, attachment.Compressed)
+		assert.Equal(t, path, attachment.UploadPath)
+	})
+
+	t.Run("over the threshold is gzipped", func(t *testing.T) {
+		path := writeReport(t, "report.html", oneMB+1)
+		original, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		attachment := compressor.Prepare(path)
+		require.True(t, attachment.Compressed)
+		assert.Equal(t, path, attachment.OriginalPath)
+		assert.Equal(t, path+".gz", attachment.UploadPath)
+
+		file, err := os.Open(attachment.UploadPath)
+		require.NoError(t, err)
+		defer func() { _ = file.Close() }()
+		gz, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		assert.Equal(t, "report.html", gz.Name)
+		decompressed, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, original, decompressed)
+
+		attachment.Cleanup(zerolog.Nop())
+		assert.NoFileExists(t, attachment.UploadPath, "the compressed copy is removed")
+		assert.FileExists(t, path, "the report itself is kept")
+	})
+
+	t.Run("cleanup of an uncompressed attachment keeps the report", func(t *testing.T) {
+		path := writeReport(t, "report.html", 64)
+		compressor.Prepare(path).Cleanup(zerolog.Nop())
+		assert.FileExists(t, path)
+	})
+}
@@ -0,0 +1,134 @@
+package notifier
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+)
+
+// reportParts is the set of report files a scan completion notification attaches once max_report_parts applies
+type reportParts struct {
+	attach  []string // Files uploaded with the notification
+	omitted []string // Report files left on disk without being attached
+	archive string   // Zip of every report file uploaded instead of them, removed after sending
+	total   int      // Report files the scan produced
+}
+
+// limitReportParts applies max_report_parts to the report files of a scan. With the zip overflow every file goes
+// into one archive; when the archive cannot be written the omit overflow is used instead.
+func (nh *NotificationHelper) limitReportParts(reportFilePaths []string) reportParts {
+	parts := reportParts{attach: reportFilePaths, total: len(reportFilePaths)}
+	limit := nh.cfg.MaxReportParts
+	if limit <= 0 || len(reportFilePaths) <= limit {
+		return parts
+	}
+
+	if nh.cfg.ReportPartsOverflow == config.ReportPartsOverflowZip {
+		archive, err := zipReportFiles(reportFilePaths)
+		if err == nil {
+			nh.logger.Info().Int("report_files", len(reportFilePaths)).Str("archive", archive).Msg("Report files exceed max_report_parts, attaching them as one zip archive.")
+			parts.attach = []string{archive}
+			parts.archive = archive
+			return parts
+		}
+		nh.logger.Warn().Err(err).Msg("Failed to zip report files, attaching the first max_report_parts files instead")
+	}
+
+	nh.logger.Info().Int("report_files", len(reportFilePaths)).Int("max_report_parts", limit).Msg("Report files exceed max_report_parts, omitting the rest.")
+	parts.attach = reportFilePaths[:limit]
+	parts.omitted = reportFilePaths[limit:]
+	return parts
+}
+
+// omittedResults returns how many probe results the omitted HTML report parts hold at most, or 0 when unknown
+func (rp reportParts) omittedResults(resultsPerPart int) int {
+	if resultsPerPart <= 0 {
+		return 0
+	}
+	results := 0
+	for _, path := range rp.omitted {
+		if strings.HasSuffix(path, ".html") {
+			results += resultsPerPart
+		}
+	}
+	return results
+}
+
+// addReportPartsNoteField notes in the main embed that report files were zipped or left out. The note goes
+// right after the report field (or first when there is none) rather than at the end, so it stays in the
+// message when max_embed_fields spills the trailing fields into the overflow attachment.
+func (nh *NotificationHelper) addReportPartsNoteField(payload discord.DiscordMessagePayload, parts reportParts) {
+	if len(payload.Embeds) == 0 || (parts.archive == "" && len(parts.omitted) == 0) {
+		return
+	}
+
+	var note string
+	if parts.archive != "" {
+		note = fmt.Sprintf("All %d report files are attached as one zip archive.", parts.total)
+	} else {
+		note = fmt.Sprintf("Attached %d of %d report files; %d more remain in `%s`.",
+			len(parts.attach), parts.total, len(parts.omitted), filepath.Dir(parts.omitted[0]))
+		if results := parts.omittedResults(nh.resultsPerReportPart); results > 0 {
+			note += fmt.Sprintf(" Up to %d results are not attached.", results)
+		}
+	}
+
+	fields := payload.Embeds[0].Fields
+	position := slices.IndexFunc(fields, func(field discord.DiscordEmbedField) bool { return field.Name == "📄 Report" }) + 1
+	payload.Embeds[0].Fields = slices.Insert(slices.Clip(fields), position, discord.DiscordEmbedField{
+		Name:   "📦 Report Parts",
+		Value:  note,
+		Inline: false,
+	})
+}
+
+// zipReportFiles writes a zip archive of the report files next to the first one and returns its path
+func zipReportFiles(reportFilePaths []string) (string, error) {
+	archivePath := strings.TrimSuffix(reportFilePaths[0], filepath.Ext(reportFilePaths[0])) + "-reports.zip"
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		return "", errorwrapper.WrapError(err, "failed to create report archive")
+	}
+
+	zipWriter := zip.NewWriter(archiveFile)
+	var writeErr error
+	for _, reportPath := range reportFilePaths {
+		if writeErr = addFileToZip(zipWriter, reportPath); writeErr != nil {
+			break
+		}
+	}
+	closeErr := zipWriter.Close()
+	fileErr := archiveFile.Close()
+
+	for _, err := range []error{writeErr, closeErr, fileErr} {
+		if err != nil {
+			_ = os.Remove(archivePath)
+			return "", errorwrapper.WrapError(err, "failed to write report archive")
+		}
+	}
+	return archivePath, nil
+}
+
+// addFileToZip stores a file at the root of the archive under its base name
+func addFileToZip(zipWriter *zip.Writer, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := zipWriter.Create(filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeReportParts(t *testing.T, count int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, count)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("scan-part%d.html", i+1))
+		require.NoError(t, os.WriteFile(paths[i], []byte(fmt.Sprintf("<html>part %d</html>", i+1)), 0600))
+	}
+	return paths
+}
+
+func newReportPartsHelper(maxParts int, overflow string) *NotificationHelper {
+	cfg := config.NewDefaultNotificationConfig()
+	cfg.MaxReportParts = maxParts
+	cfg.ReportPartsOverflow = overflow
+	return NewNotificationHelper(nil, cfg, zerolog.Nop()).WithReportPartSize(1000)
+}
+
+func TestLimitReportParts_Omit(t *testing.T) {
+	paths := writeReportParts(t, 5)
+	nh := newReportPartsHelper(2, config.ReportPartsOverflowOmit)
+
+	parts := nh.limitReportParts(paths)
+	assert.Equal(t, paths[:2], parts.attach)
+	assert.Equal(t, paths[2:], parts.omitted)
+	assert.Empty(t, parts.archive)
+
+	payload := discord.DiscordMessagePayload{Embeds: []discord.DiscordEmbed{{Title: "Scan complete"}}}
+	nh.addReportPartsNoteField(payload, parts)
+	require.Len(t, payload.Embeds[0].Fields, 1)
+	assert.Contains(t, payload.Embeds[0].Fields[0].Value, "Attached 2 of 5 report files; 3 more remain")
+	assert.Contains(t, payload.Embeds[0].Fields[0].Value, "Up to 3000 results are not attached.")
+}
+
+func TestLimitReportParts_Zip(t *testing.T) {
+	paths := writeReportParts(t, 3)
+	nh := newReportPartsHelper(2, config.ReportPartsOverflowZip)
+
+	parts := nh.limitReportParts(paths)
+	require.NotEmpty(t, parts.archive)
+	assert.Equal(t, []string{parts.archive}, parts.attach)
+	assert.Empty(t, parts.omitted)
+
+	archive, err := zip.OpenReader(parts.archive)
+	require.NoError(t, err)
+	defer func() { _ = archive.Close() }()
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"scan-part1.html", "scan-part2.html", "scan-part3.html"}, names)
+}
+
+func TestAddReportPartsNoteField_SurvivesFieldLimit(t *testing.T) {
+	paths := writeReportParts(t, 3)
+	nh := newReportPartsHelper(1, config.ReportPartsOverflowOmit)
+	nh.cfg.MaxEmbedFields = 5
+
+	fields := []discord.DiscordEmbedField{{Name: "🔍 Probe Statistics"}, {Name: "📄 Report"}}
+	for i := 0; i < 10; i++ {
+		fields = append(fields, discord.DiscordEmbedField{Name: fmt.Sprintf("🌐 host%d.example.com", i), Inline: true})
+	}
+	payload := discord.DiscordMessagePayload{Embeds: []discord.DiscordEmbed{{Title: "Scan complete", Fields: fields}}}
+	nh.addReportPartsNoteField(payload, nh.limitReportParts(paths))
+	assert.Equal(t, "📦 Report Parts", payload.Embeds[0].Fields[2].Name, "the note follows the report field")
+
+	sent, overflowText := nh.spillOverflowFields(payload)
+	require.Len(t, sent.Embeds[0].Fields, 5)
+	assert.Equal(t, "📦 Report Parts", sent.Embeds[0].Fields[2].Name, "a full embed spills host fields, not the note")
+	assert.NotContains(t, overflowText, "Report Parts")
+
+	// A templated embed without a report field gets the note first
+	payload = discord.DiscordMessagePayload{Embeds: []discord.DiscordEmbed{{Title: "Scan complete", Fields: fields[2:]}}}
+	nh.addReportPartsNoteField(payload, nh.limitReportParts(paths))
+	assert.Equal(t, "📦 Report Parts", payload.Embeds[0].Fields[0].Name)
+}
+
+func TestLimitReportParts_WithinLimit(t *testing.T) {
+	paths := writeReportParts(t, 2)
+
+	for _, nh := range []*NotificationHelper{newReportPartsHelper(2, config.ReportPartsOverflowZip), newReportPartsHelper(0, config.ReportPartsOverflowOmit)} {
+		parts := nh.limitReportParts(paths)
+		assert.Equal(t, paths, parts.attach)
+		assert.Empty(t, parts.omitted)
+		assert.Empty(t, parts.archive)
+	}
+}
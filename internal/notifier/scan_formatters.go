@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -43,12 +44,18 @@ func buildScanStartDescription(summary summary.ScanSummaryData) string {
 		summary.TotalTargets,
 	)
 
-	// Add cycle interval for automated mode
-	if summary.CycleMinutes > 0 && summary.ScanMode == "automated" {
+	// Add cycle interval or cron schedule for automated mode
+	if summary.CronExpression != "" && summary.ScanMode == "automated" {
+		description += fmt.Sprintf("\n**Scan Schedule:** `%s`", summary.CronExpression)
+	} else if summary.CycleMinutes > 0 && summary.ScanMode == "automated" {
 		cycleDuration := time.Duration(summary.CycleMinutes) * time.Minute
 		description += fmt.Sprintf("\n**Scan Cycle:** Every %s", formatDuration(cycleDuration))
 	}
 
+	if summary.Baseline {
+		description += "\n**Baseline:** results are recorded without change notifications"
+	}
+
 	return addTargetURLsToDescription(description, summary.Targets)
 }
 
@@ -138,6 +145,10 @@ func determineScanCompleteMessageStyle(scanStatus summary.ScanStatus, cfg config
 		if len(cfg.MentionRoleIDs) > 0 {
 			content = buildMentions(cfg.MentionRoleIDs) + "\n"
 		}
+	} else if scanStatus == summary.ScanStatusPartialComplete {
+		embedColor = WarningEmbedColor
+		statusEmoji = "⏱️"
+		titleText = "Scan Partially Completed"
 	} else {
 		embedColor = WarningEmbedColor
 		statusEmoji = "⚠️"
@@ -162,12 +173,19 @@ func buildScanCompleteDescription(summary summary.ScanSummaryData, statusEmoji s
 		formatDuration(summary.ScanDuration),
 	)
 
-	// Add next scan time for automated mode (calculated from current time + cycle minutes)
-	if summary.CycleMinutes > 0 && summary.ScanMode == "automated" {
-		nextScanTime := time.Now().Add(time.Duration(summary.CycleMinutes) * time.Minute)
+	// Add the wall-clock cap when one was applied (onetime mode)
+	if summary.MaxDuration > 0 {
+		baseDescription += fmt.Sprintf("\n**Max Duration:** %s", formatDuration(summary.MaxDuration))
+	}
+
+	if summary.Baseline {
+		baseDescription += "\n**Baseline:** yes"
+	}
+
+	// Add next scan time for automated mode (from the cron schedule, or current time + cycle minutes)
+	if nextScanTime, ok := nextScheduledScanTime(summary); ok {
 		nextScanFormatted := nextScanTime.Format("2006-01-02 15:04:05 MST")
-		cycleDuration := time.Duration(summary.CycleMinutes) * time.Minute
-		baseDescription += fmt.Sprintf("\n**Next Scan:** %s (in %s)", nextScanFormatted, formatDuration(cycleDuration))
+		baseDescription += fmt.Sprintf("\n**Next Scan:** %s (in %s)", nextScanFormatted, formatDuration(time.Until(nextScanTime).Round(time.Second)))
 	}
 
 	// Add batch processing info if this is a multi-part report
@@ -182,6 +200,26 @@ func buildScanCompleteDescription(summary summary.ScanSummaryData, statusEmoji s
 	return baseDescription
 }
 
+// nextScheduledScanTime returns when an automated scan will run next, if it is scheduled
+func nextScheduledScanTime(summary summary.ScanSummaryData) (time.Time, bool) {
+	if summary.ScanMode != "automated" {
+		return time.Time{}, false
+	}
+
+	if summary.CronExpression != "" {
+		next, err := config.NextCronTime(summary.CronExpression, time.Now())
+		if err != nil {
+			return time.Time{}, false
+		}
+		return next, true
+	}
+
+	if summary.CycleMinutes > 0 {
+		return time.Now().Add(time.Duration(summary.CycleMinutes) * time.Minute), true
+	}
+	return time.Time{}, false
+}
+
 // extractReportPartInfo extracts part information from report path
 func extractReportPartInfo(reportPath string) string {
 	// Look for pattern like "part_1_of_3" in file name
@@ -211,6 +249,8 @@ func buildScanCompleteEmbed(description, titleText string, embedColor int, summa
 	addBatchProcessingField(embedBuilder, summary)
 	addReportField(embedBuilder, summary.ReportPath)
 	addErrorsField(embedBuilder, summary.ErrorMessages)
+	addNewURLsBySourceField(embedBuilder, summary.NewURLsBySource)
+	addHostBreakdownFields(embedBuilder, summary.HostStats)
 
 	return embedBuilder.Build()
 }
@@ -234,19 +274,23 @@ func buildScanCompleteEmbedWithReports(description, titleText string, embedColor
 	}
 
 	addErrorsField(embedBuilder, summary.ErrorMessages)
+	addNewURLsBySourceField(embedBuilder, summary.NewURLsBySource)
+	addHostBreakdownFields(embedBuilder, summary.HostStats)
 
 	return embedBuilder.Build()
 }
 
 // addProbeStatsField adds probe statistics field to embed
 func addProbeStatsField(embedBuilder *discord.DiscordEmbedBuilder, stats summary.ProbeStats) {
-	embedBuilder.AddField("🔍 Probe Statistics",
-		fmt.Sprintf("**Total Probed:** %d\n**Successful:** %d\n**Failed:** %d\n**Discoverable Items:** %d",
-			stats.TotalProbed,
-			stats.SuccessfulProbes,
-			stats.FailedProbes,
-			stats.DiscoverableItems),
-		true)
+	value := fmt.Sprintf("**Total Probed:** %d\n**Successful:** %d\n**Failed:** %d\n**Discoverable Items:** %d",
+		stats.TotalProbed,
+		stats.SuccessfulProbes,
+		stats.FailedProbes,
+		stats.DiscoverableItems)
+	if stats.DuplicatesSkipped > 0 {
+		value += fmt.Sprintf("\n**Duplicates Skipped:** %d", stats.DuplicatesSkipped)
+	}
+	embedBuilder.AddField("🔍 Probe Statistics", value, true)
 }
 
 // addDiffStatsField adds diff statistics field to embed
@@ -300,6 +344,55 @@ func addReportField(embedBuilder *discord.DiscordEmbedBuilder, reportPath string
 	}
 }
 
+// addNewURLsBySourceField lists the pages that linked to the most new URLs, with a few of those URLs each
+func addNewURLsBySourceField(embedBuilder *discord.DiscordEmbedBuilder, groups []summary.SourceNewURLs) {
+	if len(groups) == 0 {
+		return
+	}
+
+	var lines []string
+	for _, group := range groups[:min(len(groups), MaxNewURLSources)] {
+		lines = append(lines, fmt.Sprintf("**%s** (%d)", group.Source, len(group.URLs)))
+		for _, u := range group.URLs[:min(len(group.URLs), MaxNewURLsPerSource)] {
+			lines = append(lines, "• `"+u+"`")
+		}
+		if hidden := len(group.URLs) - MaxNewURLsPerSource; hidden > 0 {
+			lines = append(lines, fmt.Sprintf("• … and %d more", hidden))
+		}
+	}
+	if hidden := len(groups) - MaxNewURLSources; hidden > 0 {
+		lines = append(lines, fmt.Sprintf("… and %d more source page(s)", hidden))
+	}
+
+	value := truncateString(strings.Join(lines, "\n"), MaxNewURLFieldLength)
+	embedBuilder.AddField(NewURLSourceFieldName, value, false)
+}
+
+// addHostBreakdownFields adds one inline field per host with new/removed URLs or 4xx/5xx responses.
+// Fields are added last so that, on large scans, they are the ones spilled into the overflow attachment.
+func addHostBreakdownFields(embedBuilder *discord.DiscordEmbedBuilder, hostStats map[string]summary.HostStats) {
+	if len(hostStats) < 2 {
+		return
+	}
+
+	for _, hs := range summary.SortedHostStats(hostStats) {
+		notableCodes := hs.NotableStatusCodes()
+		if hs.New == 0 && hs.Old == 0 && len(notableCodes) == 0 {
+			continue
+		}
+
+		value := fmt.Sprintf("**New:** %d · **Gone:** %d\n**Probed:** %d (%d failed)", hs.New, hs.Old, hs.TotalProbed, hs.Failed)
+		if len(notableCodes) > 0 {
+			codes := make([]string, 0, len(notableCodes))
+			for _, code := range notableCodes {
+				codes = append(codes, fmt.Sprintf("%d×%d", code, hs.StatusCodes[code]))
+			}
+			value += "\n**Notable:** " + strings.Join(codes, ", ")
+		}
+		embedBuilder.AddField("🌐 "+hs.Host, value, true)
+	}
+}
+
 // addErrorsField adds errors field to embed if errors exist
 func addErrorsField(embedBuilder *discord.DiscordEmbedBuilder, errorMessages []string) {
 	if len(errorMessages) > 0 {
@@ -367,6 +460,189 @@ func addPartialResultsField(embedBuilder *discord.DiscordEmbedBuilder, stats sum
 	}
 }
 
+// FormatCertificateExpiryMessage lists hosts whose TLS certificate expires within warningDays, soonest first
+func FormatCertificateExpiryMessage(scanSessionID string, expiring []summary.HostStats, warningDays int, now time.Time, cfg config.NotificationConfig) discord.DiscordMessagePayload {
+	content := buildMentions(cfg.MentionRoleIDs)
+	if content != "" {
+		content += "\n"
+	}
+
+	color := WarningEmbedColor
+	for _, hs := range expiring {
+		if !hs.CertNotAfter.After(now) {
+			color = ErrorEmbedColor
+			break
+		}
+	}
+
+	description := fmt.Sprintf(
+		"**Session:** `%s`\n%d host(s) serve a certificate expiring within %d days.",
+		scanSessionID, len(expiring), warningDays,
+	)
+	embedBuilder := discord.NewDiscordEmbedBuilder().
+		WithTitle("🔒 TLS certificates expiring soon").
+		WithDescription(description).
+		WithColor(color).
+		WithTimestamp(now).
+		WithFooter("MonsterInc Scanner", "")
+
+	for _, hs := range expiring {
+		embedBuilder.AddField("🔒 "+hs.Host, formatCertificateExpiry(hs, now), true)
+	}
+
+	return discord.NewDiscordMessagePayloadBuilder().
+		WithUsername(DiscordUsername).
+		WithAvatarURL(DiscordAvatarURL).
+		WithContent(content).
+		AddEmbed(embedBuilder.Build()).
+		Build()
+}
+
+// formatCertificateExpiry describes when a host's certificate expires (or expired) and who issued it
+func formatCertificateExpiry(hs summary.HostStats, now time.Time) string {
+	date := hs.CertNotAfter.UTC().Format("2006-01-02")
+	var value string
+	if remaining := hs.CertNotAfter.Sub(now); remaining > 0 {
+		value = fmt.Sprintf("**Expires:** %s (in %s)", date, formatDays(remaining))
+	} else {
+		value = fmt.Sprintf("**Expired:** %s (%s ago)", date, formatDays(-remaining))
+	}
+	if hs.CertIssuer != "" {
+		value += "\n**Issuer:** " + hs.CertIssuer
+	}
+	return value
+}
+
+// formatDays renders a duration in whole days, falling back to hours below one day
+func formatDays(d time.Duration) string {
+	if days := int(d.Hours() / 24); days >= 1 {
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	if hours := int(d.Hours()); hours >= 1 {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return "under an hour"
+}
+
+// Limits on the lines of the change digest fields, keeping them under Discord's field size
+const (
+	maxDigestContentTypes = 15
+	maxDigestReportPaths  = 10
+)
+
+// FormatChangeDigestMessage formats the periodic digest of the changes found by scheduled scans.
+// Host fields come last so they are the ones spilled into an attachment on busy weeks.
+func FormatChangeDigestMessage(digest summary.ChangeDigest, cfg config.NotificationConfig) discord.DiscordMessagePayload {
+	content := buildMentions(cfg.MentionRoleIDs)
+	if content != "" {
+		content += "\n"
+	}
+
+	description := fmt.Sprintf(
+		"**Window:** %s → %s\n**Scans:** %d completed, %d failed\n**URLs:** %d new, %d gone across %d host(s)",
+		digest.WindowStart.UTC().Format("2006-01-02 15:04"),
+		digest.WindowEnd.UTC().Format("2006-01-02 15:04 MST"),
+		digest.Scans, digest.FailedScans,
+		digest.NewURLs, digest.OldURLs, len(digest.Hosts),
+	)
+	if !digest.HasChanges() {
+		description += "\n\nNo URL changes were found in this window."
+	}
+
+	embedBuilder := discord.NewDiscordEmbedBuilder().
+		WithTitle("📬 Change digest").
+		WithDescription(description).
+		WithColor(InfoEmbedColor).
+		WithTimestamp(digest.WindowEnd).
+		WithFooter("MonsterInc Scanner", "")
+
+	if contentTypes := digest.SortedContentTypes(); len(contentTypes) > 0 {
+		lines := make([]string, 0, maxDigestContentTypes+1)
+		for i, ct := range contentTypes {
+			if i == maxDigestContentTypes {
+				lines = append(lines, fmt.Sprintf("…and %d more", len(contentTypes)-i))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("`%s`: %d", ct.ContentType, ct.Count))
+		}
+		embedBuilder.AddField("🗂️ New URLs by content type", strings.Join(lines, "\n"), false)
+	}
+
+	if len(digest.ReportPaths) > 0 {
+		paths := digest.ReportPaths
+		if len(paths) > maxDigestReportPaths {
+			paths = paths[:maxDigestReportPaths]
+		}
+		lines := make([]string, 0, len(paths)+1)
+		for _, path := range paths {
+			lines = append(lines, "`"+filepath.Base(path)+"`")
+		}
+		if remaining := len(digest.ReportPaths) - len(paths); remaining > 0 {
+			lines = append(lines, fmt.Sprintf("…and %d more", remaining))
+		}
+		embedBuilder.AddField("📄 Reports", strings.Join(lines, "\n"), false)
+	}
+
+	for _, hs := range digest.Hosts {
+		embedBuilder.AddField("🌐 "+hs.Host, fmt.Sprintf("**New:** %d · **Gone:** %d", hs.New, hs.Old), true)
+	}
+
+	return discord.NewDiscordMessagePayloadBuilder().
+		WithUsername(DiscordUsername).
+		WithAvatarURL(DiscordAvatarURL).
+		WithContent(content).
+		AddEmbed(embedBuilder.Build()).
+		Build()
+}
+
+// FormatNewHostsMessage lists the hosts a scan probed for the first time, with what each answered.
+// Fields are spilled into an attachment when many hosts appear at once.
+func FormatNewHostsMessage(summaryData summary.ScanSummaryData, cfg config.NotificationConfig) discord.DiscordMessagePayload {
+	content := buildMentions(cfg.MentionRoleIDs)
+	if content != "" {
+		content += "\n"
+	}
+
+	description := fmt.Sprintf(
+		"**Session:** `%s`\n%d host(s) were probed for the first time.",
+		summaryData.ScanSessionID, len(summaryData.NewHosts),
+	)
+	embedBuilder := discord.NewDiscordEmbedBuilder().
+		WithTitle("🛰️ New hosts discovered").
+		WithDescription(description).
+		WithColor(WarningEmbedColor).
+		WithTimestamp(time.Now()).
+		WithFooter("MonsterInc Scanner", "")
+
+	for _, host := range summaryData.NewHosts {
+		hs := summaryData.HostStats[host]
+		value := fmt.Sprintf("**URLs:** %d (%d failed)", hs.TotalProbed, hs.Failed)
+		if len(hs.StatusCodes) > 0 {
+			statusCodes := make([]int, 0, len(hs.StatusCodes))
+			for code := range hs.StatusCodes {
+				statusCodes = append(statusCodes, code)
+			}
+			sort.Ints(statusCodes)
+			codes := make([]string, 0, len(statusCodes))
+			for _, code := range statusCodes {
+				codes = append(codes, fmt.Sprintf("%d×%d", code, hs.StatusCodes[code]))
+			}
+			value += "\n**Status:** " + strings.Join(codes, ", ")
+		}
+		embedBuilder.AddField("🆕 "+host, value, true)
+	}
+
+	return discord.NewDiscordMessagePayloadBuilder().
+		WithUsername(DiscordUsername).
+		WithAvatarURL(DiscordAvatarURL).
+		WithContent(content).
+		AddEmbed(embedBuilder.Build()).
+		Build()
+}
+
 // FormatCriticalErrorMessage formats the message for critical errors
 func FormatCriticalErrorMessage(summary summary.ScanSummaryData, cfg config.NotificationConfig) discord.DiscordMessagePayload {
 	content := buildMentions(cfg.MentionRoleIDs)
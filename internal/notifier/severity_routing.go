@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/rs/zerolog"
+)
+
+// webhookRoute is a webhook scan notifications are sent to, and the least severity it receives
+type webhookRoute struct {
+	url            string
+	minSeverity    summary.Severity
+	mentionRoleIDs []string // Mentioned only in messages sent to this webhook
+}
+
+// newWebhookRoutes builds the routes of scan_service_discord_webhook_url and severity_webhooks.
+// A severity the validator would reject falls back to info, so the webhook still receives everything.
+func newWebhookRoutes(cfg config.NotificationConfig, logger zerolog.Logger) []webhookRoute {
+	parse := func(name string) summary.Severity {
+		severity, err := summary.ParseSeverity(name)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Invalid notification severity, using info")
+		}
+		return severity
+	}
+
+	minSeverity := parse(cfg.MinSeverity)
+	var routes []webhookRoute
+	for _, webhookURL := range cfg.ScanServiceDiscordWebhookURLs {
+		routes = append(routes, webhookRoute{url: webhookURL, minSeverity: minSeverity})
+	}
+	for _, webhook := range cfg.SeverityWebhooks {
+		if strings.TrimSpace(webhook.URL) == "" {
+			continue
+		}
+		routes = append(routes, webhookRoute{url: webhook.URL, minSeverity: parse(webhook.MinSeverity), mentionRoleIDs: webhook.MentionRoleIDs})
+	}
+	return routes
+}
+
+// routesFor returns the routes accepting a notification of the given severity
+func (nh *NotificationHelper) routesFor(severity summary.Severity) []webhookRoute {
+	var routes []webhookRoute
+	for _, route := range nh.routes {
+		if severity >= route.minSeverity {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// hasWebhooks reports whether any webhook is configured for scan notifications
+func (nh *NotificationHelper) hasWebhooks() bool {
+	return len(nh.routes) > 0
+}
+
+// withMentions prepends the route's role mentions to the payload content
+func (r webhookRoute) withMentions(payload discord.DiscordMessagePayload) discord.DiscordMessagePayload {
+	if len(r.mentionRoleIDs) == 0 {
+		return payload
+	}
+	mentions := buildMentions(r.mentionRoleIDs)
+	if payload.Content == "" {
+		payload.Content = mentions
+	} else {
+		payload.Content = mentions + " " + payload.Content
+	}
+	return payload
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func routedURLs(routes []webhookRoute) []string {
+	urls := []string{}
+	for _, route := range routes {
+		urls = append(urls, route.url)
+	}
+	return urls
+}
+
+func TestNewWebhookRoutes(t *testing.T) {
+	cfg := config.NewDefaultNotificationConfig()
+	cfg.ScanServiceDiscordWebhookURLs = []string{"https://discord.test/main"}
+	cfg.MinSeverity = "low"
+	cfg.SeverityWebhooks = config.SeverityWebhooks{
+		{URL: "https://discord.test/oncall", MinSeverity: "high", MentionRoleIDs: []string{"42"}},
+		{URL: " ", MinSeverity: "critical"},
+		{URL: "https://discord.test/typo", MinSeverity: "urgent"},
+	}
+
+	routes := newWebhookRoutes(cfg, zerolog.Nop())
+	assert.Equal(t, []webhookRoute{
+		{url: "https://discord.test/main", minSeverity: summary.SeverityLow},
+		{url: "https://discord.test/oncall", minSeverity: summary.SeverityHigh, mentionRoleIDs: []string{"42"}},
+		{url: "https://discord.test/typo", minSeverity: summary.SeverityInfo},
+	}, routes, "blank URLs are skipped and unknown severities fall back to info")
+}
+
+func TestNotificationHelper_RoutesFor(t *testing.T) {
+	cfg := config.NewDefaultNotificationConfig()
+	cfg.ScanServiceDiscordWebhookURLs = []string{"https://discord.test/main"}
+	cfg.MinSeverity = "medium"
+	cfg.SeverityWebhooks = config.SeverityWebhooks{
+		{URL: "https://discord.test/everything", MinSeverity: "info"},
+		{URL: "https://discord.test/oncall", MinSeverity: "high"},
+	}
+	nh := NewNotificationHelper(nil, cfg, zerolog.Nop())
+
+	tests := []struct {
+		severity summary.Severity
+		want     []string
+	}{
+		{summary.SeverityInfo, []string{"https://discord.test/everything"}},
+		{summary.SeverityLow, []string{"https://discord.test/everything"}},
+		{summary.SeverityMedium, []string{"https://discord.test/main", "https://discord.test/everything"}},
+		{summary.SeverityHigh, []string{"https://discord.test/main", "https://discord.test/everything", "https://discord.test/oncall"}},
+		{summary.SeverityCritical, []string{"https://discord.test/main", "https://discord.test/everything", "https://discord.test/oncall"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, routedURLs(nh.routesFor(tt.severity)))
+		})
+	}
+}
+
+func TestWebhookRoute_WithMentions(t *testing.T) {
+	payload := discord.DiscordMessagePayload{Content: "Scan completed"}
+
+	plain := webhookRoute{url: "https://discord.test/main"}
+	assert.Equal(t, "Scan completed", plain.withMentions(payload).Content)
+
+	oncall := webhookRoute{url: "https://discord.test/oncall", mentionRoleIDs: []string{"42", "7"}}
+	assert.Equal(t, "<@&42> <@&7> Scan completed", oncall.withMentions(payload).Content)
+	assert.Equal(t, "Scan completed", payload.Content, "the shared payload is not changed for other routes")
+
+	assert.Equal(t, "<@&42> <@&7>", oncall.withMentions(discord.DiscordMessagePayload{}).Content)
+}
@@ -0,0 +1,129 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// notificationPriority decides the order in which queued notifications drain
+type notificationPriority int
+
+const (
+	priorityNormal notificationPriority = iota
+	// priorityCritical notifications take the next free token ahead of any queued normal ones
+	priorityCritical
+)
+
+// webhookThrottleBurst is how many messages a webhook may send back-to-back before throttling kicks in
+const webhookThrottleBurst = 5
+
+// webhookThrottle is a token bucket limiting how fast messages are sent to one webhook
+type webhookThrottle struct {
+	mu              sync.Mutex
+	tokens          float64
+	capacity        float64
+	refillInterval  time.Duration // time to earn one token
+	lastRefill      time.Time
+	criticalWaiting int
+}
+
+// newWebhookThrottle creates a full bucket allowing messagesPerMinute sustained sends
+func newWebhookThrottle(messagesPerMinute int) *webhookThrottle {
+	capacity := float64(webhookThrottleBurst)
+	if float64(messagesPerMinute) < capacity {
+		capacity = float64(messagesPerMinute)
+	}
+	return &webhookThrottle{
+		tokens:         capacity,
+		capacity:       capacity,
+		refillInterval: time.Minute / time.Duration(messagesPerMinute),
+		lastRefill:     time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done. While a critical
+// notification is waiting, normal notifications are held back.
+func (wt *webhookThrottle) wait(ctx context.Context, priority notificationPriority) error {
+	wt.mu.Lock()
+	if priority == priorityCritical {
+		wt.criticalWaiting++
+		defer func() {
+			wt.mu.Lock()
+			wt.criticalWaiting--
+			wt.mu.Unlock()
+		}()
+	}
+
+	for {
+		wt.refill(time.Now())
+		if wt.tokens >= 1 && (priority == priorityCritical || wt.criticalWaiting == 0) {
+			wt.tokens--
+			wt.mu.Unlock()
+			return nil
+		}
+
+		delay := wt.refillInterval
+		if wt.tokens < 1 {
+			delay = time.Duration((1 - wt.tokens) * float64(wt.refillInterval))
+		}
+		wt.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		wt.mu.Lock()
+	}
+}
+
+// refill adds the tokens earned since the last refill; callers must hold mu
+func (wt *webhookThrottle) refill(now time.Time) {
+	elapsed := now.Sub(wt.lastRefill)
+	if elapsed <= 0 {
+		return
+	}
+	wt.tokens += float64(elapsed) / float64(wt.refillInterval)
+	if wt.tokens > wt.capacity {
+		wt.tokens = wt.capacity
+	}
+	wt.lastRefill = now
+}
+
+// webhookThrottles keeps one throttle per webhook URL
+type webhookThrottles struct {
+	mu                sync.Mutex
+	messagesPerMinute int
+	throttles         map[string]*webhookThrottle
+}
+
+// newWebhookThrottles returns nil when throttling is disabled (messagesPerMinute <= 0)
+func newWebhookThrottles(messagesPerMinute int) *webhookThrottles {
+	if messagesPerMinute <= 0 {
+		return nil
+	}
+	return &webhookThrottles{
+		messagesPerMinute: messagesPerMinute,
+		throttles:         make(map[string]*webhookThrottle),
+	}
+}
+
+// wait blocks until webhookURL may receive another message
+func (wts *webhookThrottles) wait(ctx context.Context, webhookURL string, priority notificationPriority) error {
+	if wts == nil {
+		return nil
+	}
+
+	wts.mu.Lock()
+	throttle, ok := wts.throttles[webhookURL]
+	if !ok {
+		throttle = newWebhookThrottle(wts.messagesPerMinute)
+		wts.throttles[webhookURL] = throttle
+	}
+	wts.mu.Unlock()
+
+	return throttle.wait(ctx, priority)
+}
@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drainedThrottle returns a throttle earning a token every 100ms with its bucket empty
+func drainedThrottle() *webhookThrottle {
+	throttle := newWebhookThrottle(600)
+	throttle.tokens = 0
+	throttle.lastRefill = time.Now()
+	return throttle
+}
+
+func TestWebhookThrottle_CriticalFirst(t *testing.T) {
+	throttle := drainedThrottle()
+	order := make(chan notificationPriority, 2)
+	send := func(priority notificationPriority) {
+		if err := throttle.wait(context.Background(), priority); err == nil {
+			order <- priority
+		}
+	}
+
+	go send(priorityNormal)
+	time.Sleep(20 * time.Millisecond) // The normal notification is queued first
+	go send(priorityCritical)
+
+	for _, want := range []notificationPriority{priorityCritical, priorityNormal} {
+		select {
+		case got := <-order:
+			assert.Equal(t, want, got)
+		case <-time.After(2 * time.Second):
+			t.Fatal("throttle never released a waiting notification")
+		}
+	}
+}
+
+func TestWebhookThrottle_Burst(t *testing.T) {
+	throttle := newWebhookThrottle(60)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < webhookThrottleBurst; i++ {
+		require.NoError(t, throttle.wait(ctx, priorityNormal), "message %d fits in the burst", i+1)
+	}
+	assert.ErrorIs(t, throttle.wait(ctx, priorityNormal), context.DeadlineExceeded, "the next message waits a second for a token")
+}
+
+func TestWebhookThrottles_PerWebhook(t *testing.T) {
+	assert.Nil(t, newWebhookThrottles(0), "throttling disabled")
+	assert.NoError(t, (*webhookThrottles)(nil).wait(context.Background(), "https://discord.example/a", priorityNormal))
+
+	throttles := newWebhookThrottles(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, throttles.wait(ctx, "https://discord.example/a", priorityNormal))
+	require.NoError(t, throttles.wait(ctx, "https://discord.example/b", priorityNormal), "each webhook has its own bucket")
+	assert.ErrorIs(t, throttles.wait(ctx, "https://discord.example/a", priorityCritical), context.DeadlineExceeded)
+}
@@ -0,0 +1,62 @@
+package notifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/notifier/discord"
+)
+
+// FormatWebhookTestMessage creates a harmless message used to verify that a webhook is reachable.
+// It mentions no roles so deploy-time checks do not page anyone.
+func FormatWebhookTestMessage(channel string) discord.DiscordMessagePayload {
+	embed := discord.NewDiscordEmbedBuilder().
+		WithTitle("🧪 Webhook Test").
+		WithDescription(fmt.Sprintf("This is a test message for the **%s** webhook. If you can read it, notifications are configured correctly.", channel)).
+		WithColor(InfoEmbedColor).
+		WithFooter("MonsterInc Scanner", "").
+		WithTimestamp(time.Now()).
+		Build()
+
+	return discord.NewDiscordMessagePayloadBuilder().
+		WithUsername(DiscordUsername).
+		WithAvatarURL(DiscordAvatarURL).
+		AddEmbed(embed).
+		Build()
+}
+
+// ConfiguredWebhook is a webhook from notification_config, labelled for reports
+type ConfiguredWebhook struct {
+	Channel string // e.g. "scan", "scan #2" or "severity #1 (high and above)"
+	URL     string // Empty when the channel has no webhook configured
+}
+
+// ConfiguredWebhooks lists every webhook in cfg. The scan and monitor channels are always listed, once with
+// an empty URL when they have no webhook, so reports can say they were skipped.
+func ConfiguredWebhooks(cfg config.NotificationConfig) []ConfiguredWebhook {
+	var webhooks []ConfiguredWebhook
+	for _, service := range []struct {
+		channel string
+		urls    config.WebhookURLs
+	}{
+		{"scan", cfg.ScanServiceDiscordWebhookURLs},
+		{"monitor", cfg.MonitorServiceDiscordWebhookURLs},
+	} {
+		if len(service.urls) == 0 {
+			webhooks = append(webhooks, ConfiguredWebhook{Channel: service.channel})
+		}
+		for i, url := range service.urls {
+			channel := service.channel
+			if len(service.urls) > 1 {
+				channel = fmt.Sprintf("%s #%d", service.channel, i+1)
+			}
+			webhooks = append(webhooks, ConfiguredWebhook{Channel: channel, URL: url})
+		}
+	}
+	for i, webhook := range cfg.SeverityWebhooks {
+		channel := fmt.Sprintf("severity #%d (%s and above)", i+1, webhook.MinSeverity)
+		webhooks = append(webhooks, ConfiguredWebhook{Channel: channel, URL: webhook.URL})
+	}
+	return webhooks
+}
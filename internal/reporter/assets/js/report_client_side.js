@@ -55,6 +55,125 @@ function reportApp() {
             if (!grid || !window.agGrid) return;
 
             const self = this;
+
+            // Selectable columns, keyed by their reporter_config.columns name. URL and Details are always shown.
+            const columns = {
+                diff: {
+                    headerName: 'Status',
+                    field: 'diff_status',
+                    flex: 1,
+                    minWidth: 120,
+                    cellRenderer: p => {
+                        const colors = { new: 'bg-green-100 text-green-800', existing: 'bg-gray-100 text-gray-800', old: 'bg-red-100 text-red-800' };
+                        return `<span class="px-3 py-2 rounded-full text-xs font-medium ${colors[p.value?.toLowerCase()] || colors.existing}">${p.value}</span>`;
+                    },
+                    filter: 'agSetColumnFilter'
+                },
+                status: {
+                    headerName: 'Code',
+                    field: 'StatusCode',
+                    flex: 1,
+                    minWidth: 120,
+                    cellRenderer: p => {
+                        const c = parseInt(p.value);
+                        let color = 'bg-gray-100 text-gray-800';
+                        if (c >= 200 && c < 300) color = 'bg-green-100 text-green-800';
+                        else if (c >= 300 && c < 400) color = 'bg-yellow-100 text-yellow-800';
+                        else if (c >= 400 && c < 500) color = 'bg-red-100 text-red-800';
+                        else if (c >= 500) color = 'bg-purple-100 text-purple-800';
+                        return `<span class="px-3 py-2 rounded-full text-xs font-medium ${color}">${p.value}</span>`;
+                    },
+                    filter: 'agSetColumnFilter'
+                },
+                content_type: {
+                    headerName: 'Content-Type',
+                    field: 'ContentType',
+                    flex: 1,
+                    minWidth: 120,
+                    cellRenderer: p => {
+                        if (!p.value) return '<span class="text-gray-400 text-xs">N/A</span>';
+                        const shortType = p.value.split(';')[0].split('/')[1] || p.value;
+                        return `<span class="px-3 py-2 bg-blue-100 text-blue-800 rounded-full text-xs" title="${p.value}">${shortType}</span>`;
+                    },
+                    filter: 'agSetColumnFilter',
+                    hide: window.innerWidth < 1024
+                },
+                type: {
+                    headerName: 'Type',
+                    field: 'Category',
+                    flex: 1,
+                    minWidth: 100,
+                    cellRenderer: p => {
+                        const colors = { api: 'bg-orange-100 text-orange-800', static: 'bg-gray-100 text-gray-600', page: 'bg-sky-100 text-sky-800' };
+                        return p.value ? `<span class="px-3 py-2 rounded-full text-xs font-medium ${colors[p.value] || colors.page}" title="${(p.data.APISignals || []).join(', ')}">${p.value}</span>` : '';
+                    },
+                    filter: 'agSetColumnFilter',
+                    hide: window.innerWidth < 1024
+                },
+                title: {
+                    headerName: 'Title',
+                    field: 'Title',
+                    cellRenderer: p => p.value ? `<span class="break-words">${p.value}</span>` : '<span class="text-gray-400">No title</span>',
+                    filter: 'agTextColumnFilter',
+                    hide: window.innerWidth < 768,
+                    flex: 1,
+                    minWidth: 120
+                },
+                tech: {
+                    headerName: 'Technologies',
+                    field: 'Technologies',
+                    cellRenderer: p => p.value?.length ? `<div class="flex flex-wrap gap-1 justify-center">${p.value.slice(0, 3).map(t => `<span class="px-2 py-1 bg-indigo-100 text-indigo-800 rounded-full text-xs whitespace-nowrap">${t}</span>`).join('')}${p.value.length > 3 ? `<span class="text-xs text-gray-500">+${p.value.length - 3}</span>` : ''}</div>` : '<span class="text-gray-400 text-xs">None</span>',
+                    filter: 'agSetColumnFilter',
+                    hide: window.innerWidth < 1024,
+                    flex: 1,
+                    minWidth: 120
+                },
+                tags: {
+                    headerName: 'Tags',
+                    field: 'Tags',
+                    cellRenderer: p => p.value?.length ? `<div class="flex flex-wrap gap-1 justify-center">${p.value.map(t => `<span class="px-2 py-1 bg-amber-100 text-amber-800 rounded-full text-xs whitespace-nowrap">${t}</span>`).join('')}</div>` : '<span class="text-gray-400 text-xs">None</span>',
+                    filter: 'agSetColumnFilter',
+                    hide: !(window.reportData || []).some(item => item.Tags?.length),
+                    flex: 1,
+                    minWidth: 120
+                },
+                length: {
+                    headerName: 'Length',
+                    field: 'ContentLength',
+                    flex: 1,
+                    minWidth: 100,
+                    cellRenderer: p => p.value ? `<span title="${p.value} bytes">${self.formatBytes(p.value)}</span>` : '<span class="text-gray-400 text-xs">N/A</span>',
+                    filter: 'agNumberColumnFilter'
+                },
+                ip: {
+                    headerName: 'IPs',
+                    field: 'IPs',
+                    flex: 1,
+                    minWidth: 120,
+                    cellRenderer: p => p.value?.length ? `<div class="flex flex-col gap-1">${p.value.map(ip => `<span class="font-mono text-xs">${ip}</span>`).join('')}</div>` : '<span class="text-gray-400 text-xs">None</span>',
+                    filter: 'agTextColumnFilter',
+                    filterValueGetter: p => (p.data.IPs || []).join(' ')
+                },
+                asn: {
+                    headerName: 'ASN',
+                    field: 'ASN',
+                    flex: 1,
+                    minWidth: 120,
+                    cellRenderer: p => p.value ? `<span title="${p.data.ASNOrg || ''}">AS${p.value}${p.data.ASNOrg ? ` <span class="text-gray-500 text-xs">${p.data.ASNOrg}</span>` : ''}</span>` : '<span class="text-gray-400 text-xs">N/A</span>',
+                    filter: 'agSetColumnFilter'
+                },
+                server: {
+                    headerName: 'Server',
+                    field: 'WebServer',
+                    flex: 1,
+                    minWidth: 100,
+                    cellRenderer: p => p.value ? `<span class="break-words">${p.value}</span>` : '<span class="text-gray-400 text-xs">N/A</span>',
+                    filter: 'agSetColumnFilter'
+                }
+            };
+            const defaultColumns = ['diff', 'status', 'content_type', 'type', 'title', 'tech', 'tags'];
+            const selected = (window.reportColumns?.length ? window.reportColumns : defaultColumns).filter(name => columns[name]);
+
             this.gridApi = agGrid.createGrid(grid, {
                 columnDefs: [
                     {
@@ -70,64 +189,7 @@ function reportApp() {
                         minWidth: 120,
                         cellStyle: { textAlign: 'left' }
                     },
-                    {
-                        headerName: 'Status',
-                        field: 'diff_status',
-                        flex: 1,
-                        minWidth: 120,
-                        cellRenderer: p => {
-                            const colors = { new: 'bg-green-100 text-green-800', existing: 'bg-gray-100 text-gray-800', old: 'bg-red-100 text-red-800' };
-                            return `<span class="px-3 py-2 rounded-full text-xs font-medium ${colors[p.value?.toLowerCase()] || colors.existing}">${p.value}</span>`;
-                        },
-                        filter: 'agSetColumnFilter'
-                    },
-                    {
-                        headerName: 'Code',
-                        field: 'StatusCode',
-                        flex: 1,
-                        minWidth: 120,
-                        cellRenderer: p => {
-                            const c = parseInt(p.value);
-                            let color = 'bg-gray-100 text-gray-800';
-                            if (c >= 200 && c < 300) color = 'bg-green-100 text-green-800';
-                            else if (c >= 300 && c < 400) color = 'bg-yellow-100 text-yellow-800';
-                            else if (c >= 400 && c < 500) color = 'bg-red-100 text-red-800';
-                            else if (c >= 500) color = 'bg-purple-100 text-purple-800';
-                            return `<span class="px-3 py-2 rounded-full text-xs font-medium ${color}">${p.value}</span>`;
-                        },
-                        filter: 'agSetColumnFilter'
-                    },
-                    {
-                        headerName: 'Content-Type',
-                        field: 'ContentType',
-                        flex: 1,
-                        minWidth: 120,
-                        cellRenderer: p => {
-                            if (!p.value) return '<span class="text-gray-400 text-xs">N/A</span>';
-                            const shortType = p.value.split(';')[0].split('/')[1] || p.value;
-                            return `<span class="px-3 py-2 bg-blue-100 text-blue-800 rounded-full text-xs" title="${p.value}">${shortType}</span>`;
-                        },
-                        filter: 'agSetColumnFilter',
-                        hide: window.innerWidth < 1024
-                    },
-                    {
-                        headerName: 'Title',
-                        field: 'Title',
-                        cellRenderer: p => p.value ? `<span class="break-words">${p.value}</span>` : '<span class="text-gray-400">No title</span>',
-                        filter: 'agTextColumnFilter',
-                        hide: window.innerWidth < 768,
-                        flex: 1,
-                        minWidth: 120
-                    },
-                    {
-                        headerName: 'Technologies',
-                        field: 'Technologies',
-                        cellRenderer: p => p.value?.length ? `<div class="flex flex-wrap gap-1 justify-center">${p.value.slice(0, 3).map(t => `<span class="px-2 py-1 bg-indigo-100 text-indigo-800 rounded-full text-xs whitespace-nowrap">${t}</span>`).join('')}${p.value.length > 3 ? `<span class="text-xs text-gray-500">+${p.value.length - 3}</span>` : ''}</div>` : '<span class="text-gray-400 text-xs">None</span>',
-                        filter: 'agSetColumnFilter',
-                        hide: window.innerWidth < 1024,
-                        flex: 1,
-                        minWidth: 120
-                    },
+                    ...selected.map(name => columns[name]),
                     {
                         headerName: 'Details',
                         field: 'details',
@@ -28,4 +28,5 @@ const (
 
 	// Report generation limits
 	DefaultMaxResultsPerFile = 1000
+	MaxDefaultReportWorkers  = 4
 )
@@ -0,0 +1,95 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+)
+
+// csvReportHeader names the columns of the CSV export, one row per probe result
+var csvReportHeader = []string{
+	"url", "final_url", "status_code", "url_status", "title", "content_length",
+	"content_type", "technologies", "tags", "first_seen", "last_seen", "error", "category",
+}
+
+// GenerateCSVReport writes probe results as a single flat CSV next to the HTML report for
+// baseOutputPath (report.html -> report.csv) and returns its path. encoding/csv quotes values
+// containing commas, quotes or newlines; text cells a spreadsheet would evaluate as a formula
+// are prefixed with a single quote.
+func (r *HtmlReporter) GenerateCSVReport(probeResults []*httpxrunner.ProbeResult, baseOutputPath string) (string, error) {
+	if baseOutputPath == "" {
+		baseOutputPath = "report"
+	}
+	outputPath := strings.TrimSuffix(baseOutputPath, ".html") + ".csv"
+
+	if err := writeCSVReport(probeResults, outputPath); err != nil {
+		r.logger.Error().Err(err).Str("output", outputPath).Msg("Failed to write CSV report")
+		return "", err
+	}
+	return outputPath, nil
+}
+
+// writeCSVReport writes the header and one row per probe result to outputPath
+func writeCSVReport(probeResults []*httpxrunner.ProbeResult, outputPath string) error {
+	file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, FilePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV report %s: %w", outputPath, err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvReportHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, pr := range probeResults {
+		if pr == nil {
+			continue
+		}
+		if err := writer.Write(csvReportRow(ToProbeResultDisplay(*pr))); err != nil {
+			return fmt.Errorf("failed to write CSV row for %s: %w", pr.InputURL, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV report %s: %w", outputPath, err)
+	}
+	return file.Close()
+}
+
+// csvReportRow converts a display result into cells matching csvReportHeader
+func csvReportRow(pr ProbeResultDisplay) []string {
+	statusCode := ""
+	if pr.StatusCode > 0 {
+		statusCode = strconv.Itoa(pr.StatusCode)
+	}
+
+	return []string{
+		pr.InputURL,
+		pr.FinalURL,
+		statusCode,
+		pr.URLStatus,
+		csvSafeText(pr.Title),
+		strconv.FormatInt(pr.ContentLength, 10),
+		csvSafeText(pr.ContentType),
+		csvSafeText(strings.Join(pr.Technologies, "; ")),
+		csvSafeText(strings.Join(pr.Tags, "; ")),
+		pr.FirstSeen,
+		pr.LastSeen,
+		csvSafeText(pr.Error),
+		pr.Category,
+	}
+}
+
+// csvSafeText neutralizes values a spreadsheet would run as a formula (=, +, -, @, tab, carriage return)
+func csvSafeText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
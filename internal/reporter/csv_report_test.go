@@ -0,0 +1,61 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVSafeText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"Welcome", "Welcome"},
+		{"a=b", "a=b"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, csvSafeText(tt.value), "value %q", tt.value)
+	}
+}
+
+func TestWriteCSVReport_Quoting(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.csv")
+	probes := []*httpxrunner.ProbeResult{
+		{
+			InputURL:     "https://example.com/",
+			StatusCode:   200,
+			Title:        "Shop, \"Best\" prices\nnow",
+			Technologies: []httpxrunner.Technology{{Name: "=cmd|' /C calc'!A0"}, {Name: "Nginx"}},
+		},
+		nil,
+	}
+	require.NoError(t, writeCSVReport(probes, outputPath))
+
+	file, err := os.Open(outputPath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, records, 2, "header and one row; nil results are skipped")
+	assert.Equal(t, csvReportHeader, records[0])
+	row := records[1]
+	assert.Equal(t, "https://example.com/", row[0])
+	assert.Equal(t, "200", row[2])
+	assert.Equal(t, "Shop, \"Best\" prices\nnow", row[4], "commas, quotes and newlines survive a round trip")
+	assert.Equal(t, "'=cmd|' /C calc'!A0; Nginx", row[7])
+}
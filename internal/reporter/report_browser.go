@@ -0,0 +1,205 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/rs/zerolog"
+)
+
+// reportAssetsDir is the directory next to the reports holding their CSS and JS when assets are not embedded
+const reportAssetsDir = "assets"
+
+// reportBrowserShutdownTimeout bounds how long Serve waits for open requests once its context is done
+const reportBrowserShutdownTimeout = 5 * time.Second
+
+// reportBrowserPage lists the indexed scans, newest first, with links to the report files still on disk
+var reportBrowserPage = template.Must(template.New("report_browser").Funcs(template.FuncMap{
+	"base": filepath.Base,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>MonsterInc Reports</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: .4rem .6rem; text-align: left; font-size: .9rem; }
+th { background: #f4f4f4; }
+td.num { text-align: right; }
+.missing { color: #999; text-decoration: line-through; }
+form { margin-bottom: 1rem; }
+</style>
+</head>
+<body>
+<h1>MonsterInc Reports</h1>
+<form method="get"><input type="search" name="q" value="{{.Query}}" placeholder="Session, target source, mode or status"> <button type="submit">Filter</button></form>
+<p>{{len .Entries}} scan(s){{if .Query}} matching "{{.Query}}"{{end}}</p>
+<table>
+<tr><th>Generated</th><th>Session</th><th>Mode</th><th>Target source</th><th>Status</th><th>Targets</th><th>Probed</th><th>New</th><th>Old</th><th>Existing</th><th>Reports</th></tr>
+{{range .Entries}}<tr>
+<td>{{.Entry.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</td>
+<td>{{.Entry.ScanSessionID}}</td>
+<td>{{.Entry.ScanMode}}</td>
+<td>{{.Entry.TargetSource}}</td>
+<td>{{.Entry.Status}}</td>
+<td class="num">{{.Entry.TotalTargets}}</td>
+<td class="num">{{.Entry.TotalProbed}}</td>
+<td class="num">{{.Entry.NewURLs}}</td>
+<td class="num">{{.Entry.OldURLs}}</td>
+<td class="num">{{.Entry.ExistingURLs}}</td>
+<td>{{$session := .Entry.ScanSessionID}}{{range .Files}}{{if .Exists}}<a href="/reports/{{$session}}/{{base .Path}}">{{base .Path}}</a>{{else}}<span class="missing" title="Removed from disk">{{base .Path}}</span>{{end}} {{end}}</td>
+</tr>{{end}}
+</table>
+</body>
+</html>
+`))
+
+// reportBrowserRow is an index entry together with which of its files still exist
+type reportBrowserRow struct {
+	Entry ReportIndexEntry
+	Files []reportBrowserFile
+}
+
+// reportBrowserFile is one report file of an index entry
+type reportBrowserFile struct {
+	Path   string
+	Exists bool
+}
+
+// ReportBrowser serves a page listing the scans of a report index and the report files it names.
+// Only the files an index entry names and the report assets next to them are served.
+type ReportBrowser struct {
+	indexPath string
+	logger    zerolog.Logger
+}
+
+// NewReportBrowser creates a ReportBrowser for the index at indexPath
+func NewReportBrowser(indexPath string, logger zerolog.Logger) *ReportBrowser {
+	return &ReportBrowser{
+		indexPath: indexPath,
+		logger:    logger.With().Str("module", "ReportBrowser").Logger(),
+	}
+}
+
+// Handler returns the browser's routes: the scan list at / and report files at /reports/<session>/<file>
+func (rb *ReportBrowser) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", rb.serveIndex)
+	mux.HandleFunc("GET /reports/{session}/{file...}", rb.serveReportFile)
+	return mux
+}
+
+// Serve listens on addr until ctx is done, then shuts the server down
+func (rb *ReportBrowser) Serve(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           rb.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), reportBrowserShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	rb.logger.Info().Str("addr", addr).Str("index_file", rb.indexPath).Msg("Report browser listening")
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errorwrapper.WrapError(err, "report browser stopped")
+	}
+	return nil
+}
+
+// serveIndex renders the list of indexed scans, filtered by the q query parameter
+func (rb *ReportBrowser) serveIndex(w http.ResponseWriter, r *http.Request) {
+	entries, err := LoadReportIndex(rb.indexPath)
+	if err != nil {
+		rb.logger.Error().Err(err).Msg("Failed to load report index")
+		http.Error(w, "report index could not be read", http.StatusInternalServerError)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	rows := make([]reportBrowserRow, 0, len(entries))
+	for _, entry := range entries {
+		if query != "" && !reportIndexEntryMatches(entry, query) {
+			continue
+		}
+		row := reportBrowserRow{Entry: entry}
+		for _, path := range entry.ReportPaths {
+			_, statErr := os.Stat(path)
+			row.Files = append(row.Files, reportBrowserFile{Path: path, Exists: statErr == nil})
+		}
+		rows = append(rows, row)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := reportBrowserPage.Execute(w, map[string]any{"Entries": rows, "Query": query}); err != nil {
+		rb.logger.Warn().Err(err).Msg("Failed to render report browser page")
+	}
+}
+
+// serveReportFile serves a report file named by a session's index entry, or a file under the assets directory
+// next to it that reports without embedded assets link to. Nothing else in the report directory is served.
+func (rb *ReportBrowser) serveReportFile(w http.ResponseWriter, r *http.Request) {
+	entries, err := LoadReportIndex(rb.indexPath)
+	if err != nil {
+		http.Error(w, "report index could not be read", http.StatusInternalServerError)
+		return
+	}
+
+	session, file := r.PathValue("session"), r.PathValue("file")
+	for _, entry := range entries {
+		if entry.ScanSessionID != session || len(entry.ReportPaths) == 0 {
+			continue
+		}
+		for _, path := range entry.ReportPaths {
+			if filepath.Base(path) == file {
+				http.ServeFile(w, r, path)
+				return
+			}
+		}
+		if asset, ok := strings.CutPrefix(file, reportAssetsDir+"/"); ok {
+			rb.serveAsset(w, r, filepath.Join(filepath.Dir(entry.ReportPaths[0]), reportAssetsDir), asset)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// serveAsset serves one regular file below assetsDir; http.Dir rejects names escaping it
+func (rb *ReportBrowser) serveAsset(w http.ResponseWriter, r *http.Request, assetsDir, name string) {
+	file, err := http.Dir(assetsDir).Open("/" + name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r) // No directory listings
+		return
+	}
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+// reportIndexEntryMatches reports whether the session, target source, mode or status of entry contains query
+func reportIndexEntryMatches(entry ReportIndexEntry, query string) bool {
+	query = strings.ToLower(query)
+	for _, field := range []string{entry.ScanSessionID, entry.TargetSource, entry.ScanMode, entry.Status} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
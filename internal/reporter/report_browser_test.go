@@ -0,0 +1,149 @@
+package reporter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportIndexRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := filepath.Join(dir, "index", "report_index.jsonl")
+
+	entries, err := LoadReportIndex(indexPath)
+	require.NoError(t, err, "a missing index has no entries")
+	assert.Empty(t, entries)
+
+	older := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, AppendReportIndex(indexPath, NewReportIndexEntry(summary.ScanSummaryData{ScanSessionID: "first"}, nil, older)))
+	require.NoError(t, AppendReportIndex(indexPath, NewReportIndexEntry(summary.ScanSummaryData{ScanSessionID: "second"}, []string{"report.html"}, older.Add(time.Hour))))
+
+	file, err := os.OpenFile(indexPath, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString("{\"scan_session_id\": \"cut short\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	entries, err = LoadReportIndex(indexPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "an undecodable line is skipped")
+	assert.Equal(t, "second", entries[0].ScanSessionID, "newest scan first")
+	assert.True(t, filepath.IsAbs(entries[0].ReportPaths[0]))
+}
+
+func TestReportBrowserHandler(t *testing.T) {
+	dir := t.TempDir()
+	reportDir := filepath.Join(dir, "reports")
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	reportPath := filepath.Join(reportDir, "scan-part1.html")
+	require.NoError(t, os.WriteFile(reportPath, []byte("<p>report</p>"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "other-scan.html"), []byte("not indexed"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(reportDir, "assets", "css"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "assets", "css", "report.css"), []byte("body{}"), 0644))
+
+	indexPath := filepath.Join(dir, "report_index.jsonl")
+	entry := NewReportIndexEntry(summary.ScanSummaryData{ScanSessionID: "20250101-120000", TargetSource: "targets.txt"},
+		[]string{reportPath, filepath.Join(reportDir, "removed.csv")}, time.Now())
+	require.NoError(t, AppendReportIndex(indexPath, entry))
+
+	server := httptest.NewServer(NewReportBrowser(indexPath, zerolog.Nop()).Handler())
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `href="/reports/20250101-120000/scan-part1.html"`)
+	assert.Contains(t, body, `class="missing"`, "files no longer on disk are not linked")
+
+	_, body = get("/?q=nomatch")
+	assert.Contains(t, body, "0 scan(s)")
+
+	status, body = get("/reports/20250101-120000/scan-part1.html")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "<p>report</p>", body)
+
+	status, _ = get("/reports/unknown/scan-part1.html")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = get("/reports/20250101-120000/assets/css/report.css")
+	assert.Equal(t, http.StatusOK, status, "assets of reports without embedded assets")
+	assert.Equal(t, "body{}", body)
+
+	status, body = get("/reports/20250101-120000/other-scan.html")
+	assert.Equal(t, http.StatusNotFound, status, "a file in the report directory that the entry does not name")
+	assert.NotContains(t, body, "not indexed")
+
+	for _, path := range []string{
+		"/reports/20250101-120000/..%2fsecret.txt",
+		"/reports/20250101-120000/assets/..%2f..%2fsecret.txt",
+		"/reports/20250101-120000/assets/..%2fother-scan.html",
+		"/reports/20250101-120000/%2e%2e/secret.txt",
+	} {
+		status, body = get(path)
+		assert.NotEqual(t, http.StatusOK, status, path)
+		assert.NotContains(t, body, "secret", path)
+		assert.NotContains(t, body, "not indexed", path)
+	}
+
+	status, _ = get("/reports/20250101-120000/")
+	assert.Equal(t, http.StatusNotFound, status, "no directory listings")
+	status, _ = get("/reports/20250101-120000/assets/css")
+	assert.Equal(t, http.StatusNotFound, status, "no directory listings")
+}
+
+func TestPruneReportIndex(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := filepath.Join(dir, "report_index.jsonl")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	writeReport := func(name string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+		return path
+	}
+	expired := writeReport("expired.html")
+	recent := writeReport("recent.html")
+	require.NoError(t, AppendReportIndex(indexPath, NewReportIndexEntry(summary.ScanSummaryData{ScanSessionID: "expired"},
+		[]string{expired, filepath.Join(dir, "already-gone.csv")}, now.AddDate(0, 0, -40))))
+	require.NoError(t, AppendReportIndex(indexPath, NewReportIndexEntry(summary.ScanSummaryData{ScanSessionID: "recent"},
+		[]string{recent}, now.AddDate(0, 0, -2))))
+
+	result, err := PruneReportIndex(indexPath, 0, now)
+	require.NoError(t, err)
+	assert.Equal(t, ReportIndexPruneResult{}, result, "a zero max age keeps everything")
+
+	result, err = PruneReportIndex(indexPath, 30*24*time.Hour, now)
+	require.NoError(t, err)
+	assert.Equal(t, ReportIndexPruneResult{RemovedEntries: 1, DeletedFiles: 1}, result)
+	assert.NoFileExists(t, expired)
+	assert.FileExists(t, recent)
+
+	entries, err := LoadReportIndex(indexPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "recent", entries[0].ScanSessionID)
+
+	// Appending still works on the rewritten index
+	require.NoError(t, AppendReportIndex(indexPath, NewReportIndexEntry(summary.ScanSummaryData{ScanSessionID: "next"}, nil, now)))
+	entries, err = LoadReportIndex(indexPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
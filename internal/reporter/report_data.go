@@ -20,6 +20,9 @@ type ProbeResultDisplay struct {
 	Title           string
 	WebServer       string
 	Technologies    []string // Kept as a slice for easier template handling, join in template if needed
+	Tags            []string // Tags of the target the URL belongs to
+	Category        string   // api, static or page (see ClassifyURL)
+	APISignals      []string // Heuristics that classified the URL as an API endpoint
 	IPs             []string
 	CNAMEs          []string
 	ASN             int
@@ -33,6 +36,9 @@ type ProbeResultDisplay struct {
 	Body            string // Or a snippet, or path to stored body
 	Error           string
 	Timestamp       string // Formatted string for display
+	FirstSeen       string // Formatted first-seen time across scans
+	LastSeen        string // Formatted last-seen time across scans
+	MissedScans     int    // Consecutive scans the URL was not seen in
 	IsSuccess       bool   // Helper for template logic
 	HasTechnologies bool   // Helper
 	HasTLS          bool   // Helper
@@ -59,6 +65,8 @@ type ReportPageData struct {
 	UniqueRootTargets  []string                        // Deprecated: keeping for backward compatibility
 	UniqueHostnames    []string                        // New: for hostname-based grouping
 	UniqueURLStatuses  []string                        // For diff status filtering
+	APISurface         []APIEndpoint                   // URLs classified as API endpoints, sorted by URL
+	ResponseClasses    []ResponseClass                 // Responses shared by several hosts, most hosts first
 	CustomCSS          template.CSS                    // For embedded styles.css
 	ReportJs           template.JS                     // Embedded custom report.js
 	URLDiffs           map[string]differ.URLDiffResult `json:"url_diffs,omitempty"` // Added to hold raw diff results
@@ -77,6 +85,12 @@ type ReportPageData struct {
 
 	// Report Part Information (for multi-part reports)
 	ReportPartInfo string `json:"report_part_info,omitempty"`
+
+	// ChangedOnly is set when URLs unchanged since the previous scan were left out of the report
+	ChangedOnly bool `json:"changed_only,omitempty"`
+
+	// Columns selects and orders the probe result columns of the results grid; empty keeps the default layout
+	Columns []string `json:"columns,omitempty"`
 }
 
 // ReporterConfigForTemplate is a subset of reporter configurations relevant for the template.
@@ -95,6 +109,7 @@ func ToProbeResultDisplay(pr httpxrunner.ProbeResult) ProbeResultDisplay {
 	for _, t := range pr.Technologies {
 		technologies = append(technologies, t.Name)
 	}
+	classification := ClassifyURL(pr.InputURL, pr.ContentType)
 
 	return ProbeResultDisplay{
 		InputURL:        pr.InputURL,
@@ -106,17 +121,28 @@ func ToProbeResultDisplay(pr httpxrunner.ProbeResult) ProbeResultDisplay {
 		Title:           pr.Title,
 		WebServer:       pr.WebServer,
 		Technologies:    technologies,
+		Tags:            pr.Tags,
+		Category:        classification.Category,
+		APISignals:      classification.Signals,
 		IPs:             pr.IPs,
 		CNAMEs:          pr.CNAMEs,
 		ASN:             pr.ASN,
 		ASNOrg:          pr.ASNOrg,
+		TLSVersion:      pr.TLSVersion,
+		TLSCipher:       pr.TLSCipher,
+		TLSCertIssuer:   pr.TLSCertIssuer,
+		TLSCertExpiry:   formatOptionalTime(pr.TLSCertNotAfter),
 		Duration:        pr.Duration,
 		Headers:         pr.Headers,
 		Body:            pr.Body, // Consider snippet or link
 		Error:           pr.Error,
 		Timestamp:       pr.Timestamp.Format("2006-01-02 15:04:05 MST"),
+		FirstSeen:       formatOptionalTime(firstSeenTime(pr)),
+		LastSeen:        formatOptionalTime(pr.Timestamp),
+		MissedScans:     pr.MissedScans,
 		IsSuccess:       isSuccess,
 		HasTechnologies: len(technologies) > 0,
+		HasTLS:          pr.TLSVersion != "" || pr.HasTLSCertificate(),
 		HasASN:          pr.ASN != 0,
 		HasCNAMEs:       len(pr.CNAMEs) > 0,
 		HasIPs:          len(pr.IPs) > 0,
@@ -125,6 +151,22 @@ func ToProbeResultDisplay(pr httpxrunner.ProbeResult) ProbeResultDisplay {
 	}
 }
 
+// firstSeenTime falls back to the probe time for URLs seen for the first time in this scan
+func firstSeenTime(pr httpxrunner.ProbeResult) time.Time {
+	if pr.OldestScanTimestamp.IsZero() {
+		return pr.Timestamp
+	}
+	return pr.OldestScanTimestamp
+}
+
+// formatOptionalTime formats t for display, returning an empty string for the zero time
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05 MST")
+}
+
 // Add more helper functions or structs as needed for the report.
 // For example, a struct to hold filter options populated from the data.
 
@@ -147,6 +189,16 @@ func GetDefaultReportPageData() ReportPageData {
 	}
 }
 
+// APIEndpoint is a row of the "Discovered API Surface" report section
+type APIEndpoint struct {
+	URL         string
+	Method      string
+	StatusCode  int
+	ContentType string
+	URLStatus   string
+	Signals     []string
+}
+
 // DiffSummaryEntry holds counts for a specific root target's diff results
 type DiffSummaryEntry struct {
 	NewCount      int `json:"new_count"`
@@ -0,0 +1,193 @@
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+)
+
+// reportIndexMutex serializes appends to report index files within the process
+var reportIndexMutex sync.Mutex
+
+// ReportIndexEntry records the reports of one scan and the figures the report browser lists them by
+type ReportIndexEntry struct {
+	ScanSessionID string    `json:"scan_session_id"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	TargetSource  string    `json:"target_source"`
+	ScanMode      string    `json:"scan_mode"`
+	Status        string    `json:"status"`
+	TotalTargets  int       `json:"total_targets"`
+	TotalProbed   int       `json:"total_probed"`
+	NewURLs       int       `json:"new_urls"`
+	OldURLs       int       `json:"old_urls"`
+	ExistingURLs  int       `json:"existing_urls"`
+	ReportPaths   []string  `json:"report_paths"` // Absolute paths of the HTML parts and CSV export
+}
+
+// NewReportIndexEntry describes the reports of a finished scan
+func NewReportIndexEntry(summaryData summary.ScanSummaryData, reportPaths []string, generatedAt time.Time) ReportIndexEntry {
+	absolutePaths := make([]string, 0, len(reportPaths))
+	for _, path := range reportPaths {
+		if absolute, err := filepath.Abs(path); err == nil {
+			path = absolute
+		}
+		absolutePaths = append(absolutePaths, path)
+	}
+
+	return ReportIndexEntry{
+		ScanSessionID: summaryData.ScanSessionID,
+		GeneratedAt:   generatedAt,
+		TargetSource:  summaryData.TargetSource,
+		ScanMode:      summaryData.ScanMode,
+		Status:        summaryData.Status,
+		TotalTargets:  summaryData.TotalTargets,
+		TotalProbed:   summaryData.ProbeStats.TotalProbed,
+		NewURLs:       summaryData.DiffStats.New,
+		OldURLs:       summaryData.DiffStats.Old,
+		ExistingURLs:  summaryData.DiffStats.Existing,
+		ReportPaths:   absolutePaths,
+	}
+}
+
+// AppendReportIndex adds an entry to the JSON Lines index at indexPath, creating the file and its directory
+func AppendReportIndex(indexPath string, entry ReportIndexEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return errorwrapper.WrapError(err, "failed to encode report index entry")
+	}
+
+	reportIndexMutex.Lock()
+	defer reportIndexMutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return errorwrapper.WrapError(err, "failed to create report index directory")
+	}
+	file, err := os.OpenFile(indexPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errorwrapper.WrapError(err, "failed to open report index")
+	}
+
+	_, writeErr := file.Write(append(line, '\n'))
+	closeErr := file.Close()
+	if writeErr != nil {
+		return errorwrapper.WrapError(writeErr, "failed to write report index")
+	}
+	if closeErr != nil {
+		return errorwrapper.WrapError(closeErr, "failed to write report index")
+	}
+	return nil
+}
+
+// LoadReportIndex reads the index at indexPath, newest scan first. A missing index has no entries; lines that
+// cannot be decoded, such as one cut short by a crash, are skipped.
+func LoadReportIndex(indexPath string) ([]ReportIndexEntry, error) {
+	file, err := os.Open(indexPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to open report index")
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []ReportIndexEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry ReportIndexEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.ScanSessionID == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errorwrapper.WrapError(err, "failed to read report index")
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].GeneratedAt.After(entries[j].GeneratedAt)
+	})
+	return entries, nil
+}
+
+// ReportIndexPruneResult summarises one PruneReportIndex run
+type ReportIndexPruneResult struct {
+	RemovedEntries int // Index entries dropped
+	DeletedFiles   int // Report files deleted with them
+}
+
+// PruneReportIndex deletes the report files of entries generated more than maxAge before now and drops those
+// entries from the index. An entry whose files cannot all be deleted stays, so the next run retries it. The
+// index is replaced through a temporary file; lines that cannot be decoded are not carried over.
+func PruneReportIndex(indexPath string, maxAge time.Duration, now time.Time) (ReportIndexPruneResult, error) {
+	var result ReportIndexPruneResult
+	if maxAge <= 0 {
+		return result, nil
+	}
+
+	reportIndexMutex.Lock()
+	defer reportIndexMutex.Unlock()
+
+	entries, err := LoadReportIndex(indexPath)
+	if err != nil {
+		return result, err
+	}
+
+	var kept []ReportIndexEntry
+	var errs []error
+	for _, entry := range entries {
+		if now.Sub(entry.GeneratedAt) <= maxAge {
+			kept = append(kept, entry)
+			continue
+		}
+
+		removed := true
+		for _, path := range entry.ReportPaths {
+			if err := os.Remove(path); err != nil {
+				if !os.IsNotExist(err) {
+					errs = append(errs, errorwrapper.WrapError(err, "failed to delete expired report "+path))
+					removed = false
+				}
+				continue
+			}
+			result.DeletedFiles++
+		}
+		if removed {
+			result.RemovedEntries++
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	if result.RemovedEntries == 0 {
+		return result, errors.Join(errs...)
+	}
+
+	// Entries were loaded newest first; the index keeps appending order
+	var buf bytes.Buffer
+	for i := len(kept) - 1; i >= 0; i-- {
+		line, err := json.Marshal(kept[i])
+		if err != nil {
+			return result, errorwrapper.WrapError(err, "failed to encode report index entry")
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	tmpPath := indexPath + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return result, errorwrapper.WrapError(err, "failed to write report index")
+	}
+	if err := os.Rename(tmpPath, indexPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return result, errorwrapper.WrapError(err, "failed to replace report index")
+	}
+	return result, errors.Join(errs...)
+}
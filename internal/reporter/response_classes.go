@@ -0,0 +1,92 @@
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+)
+
+// MinResponseClassHosts is how many hosts must return the same response for it to be listed as a class
+const MinResponseClassHosts = 2
+
+// ResponseClass is a row of the "Identical Responses" report section: one response returned by several hosts,
+// e.g. a parked-domain page or a CDN error page
+type ResponseClass struct {
+	Hash          string // First HashLength hex characters of the grouping hash
+	ByFingerprint bool   // Bodies were not captured; grouped by status, content type, length and title instead
+	StatusCode    int
+	ContentType   string
+	ContentLength int64
+	Title         string
+	Hosts         []string // Sorted
+	URLs          []string // Sorted
+}
+
+// collectResponseClasses groups successful probes by a hash of their response body and returns the groups
+// served by at least MinResponseClassHosts hosts, most hosts first. Probes without a captured body
+// (httpx_runner_config.extract_body off) are grouped by a fingerprint of status, content type, length and title.
+func collectResponseClasses(probeResults []*httpxrunner.ProbeResult) []ResponseClass {
+	classes := make(map[string]*ResponseClass)
+	hostSets := make(map[string]map[string]bool)
+	for _, pr := range probeResults {
+		if pr == nil || pr.Error != "" || pr.StatusCode == 0 {
+			continue
+		}
+		host, err := urlhandler.ExtractHostname(pr.InputURL)
+		if err != nil || host == "" {
+			continue
+		}
+
+		key, byFingerprint := responseClassKey(pr)
+		class, ok := classes[key]
+		if !ok {
+			class = &ResponseClass{
+				Hash:          key[:HashLength],
+				ByFingerprint: byFingerprint,
+				StatusCode:    pr.StatusCode,
+				ContentType:   pr.ContentType,
+				ContentLength: pr.ContentLength,
+				Title:         pr.Title,
+			}
+			classes[key] = class
+			hostSets[key] = make(map[string]bool)
+		}
+		if !hostSets[key][host] {
+			hostSets[key][host] = true
+			class.Hosts = append(class.Hosts, host)
+		}
+		class.URLs = append(class.URLs, pr.InputURL)
+	}
+
+	var result []ResponseClass
+	for _, class := range classes {
+		if len(class.Hosts) < MinResponseClassHosts {
+			continue
+		}
+		sort.Strings(class.Hosts)
+		sort.Strings(class.URLs)
+		result = append(result, *class)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Hosts) != len(result[j].Hosts) {
+			return len(result[i].Hosts) > len(result[j].Hosts)
+		}
+		return result[i].Hash < result[j].Hash
+	})
+	return result
+}
+
+// responseClassKey hashes the probe's body, or its fingerprint when no body was captured
+func responseClassKey(pr *httpxrunner.ProbeResult) (string, bool) {
+	input := "body\x00" + pr.Body
+	byFingerprint := pr.Body == ""
+	if byFingerprint {
+		input = fmt.Sprintf("fingerprint\x00%d\x00%s\x00%d\x00%s", pr.StatusCode, pr.ContentType, pr.ContentLength, pr.Title)
+	}
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:]), byFingerprint
+}
@@ -0,0 +1,110 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// classProbe builds a successful probe of targetURL returning body
+func classProbe(targetURL, body string) *httpxrunner.ProbeResult {
+	return &httpxrunner.ProbeResult{InputURL: targetURL, StatusCode: 200, ContentType: "text/html", ContentLength: int64(len(body)), Body: body}
+}
+
+func TestCollectResponseClasses(t *testing.T) {
+	parked := "<html>This domain is for sale</html>"
+	cdnError := "<html>Origin unreachable</html>"
+
+	tests := []struct {
+		name   string
+		probes []*httpxrunner.ProbeResult
+		want   [][]string // Hosts of each class, in report order
+	}{
+		{
+			name: "identical bodies group across hosts",
+			probes: []*httpxrunner.ProbeResult{
+				classProbe("https://a.com/", parked),
+				classProbe("https://b.com/", parked),
+				classProbe("https://c.com/", "<html>c</html>"),
+			},
+			want: [][]string{{"a.com", "b.com"}},
+		},
+		{
+			name: "one host serving the same body on several URLs is below the threshold",
+			probes: []*httpxrunner.ProbeResult{
+				classProbe("https://a.com/", parked),
+				classProbe("https://a.com/other", parked),
+			},
+			want: nil,
+		},
+		{
+			name: "errors and unanswered probes are skipped",
+			probes: []*httpxrunner.ProbeResult{
+				classProbe("https://a.com/", parked),
+				{InputURL: "https://b.com/", StatusCode: 200, Body: parked, Error: "timeout"},
+				{InputURL: "https://c.com/", Body: parked},
+				nil,
+			},
+			want: nil,
+		},
+		{
+			name: "most hosts first",
+			probes: []*httpxrunner.ProbeResult{
+				classProbe("https://a.com/", cdnError),
+				classProbe("https://b.com/", cdnError),
+				classProbe("https://c.com/", parked),
+				classProbe("https://d.com/", parked),
+				classProbe("https://e.com/", parked),
+			},
+			want: [][]string{{"c.com", "d.com", "e.com"}, {"a.com", "b.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hosts [][]string
+			for _, class := range collectResponseClasses(tt.probes) {
+				hosts = append(hosts, class.Hosts)
+			}
+			assert.Equal(t, tt.want, hosts)
+		})
+	}
+}
+
+func TestCollectResponseClasses_Details(t *testing.T) {
+	parked := "<html>This domain is for sale</html>"
+	classes := collectResponseClasses([]*httpxrunner.ProbeResult{
+		classProbe("https://b.com/", parked),
+		classProbe("https://a.com/x", parked),
+		classProbe("https://a.com/", parked),
+	})
+
+	require.Len(t, classes, 1)
+	class := classes[0]
+	assert.Len(t, class.Hash, HashLength)
+	assert.False(t, class.ByFingerprint)
+	assert.Equal(t, 200, class.StatusCode)
+	assert.Equal(t, []string{"a.com", "b.com"}, class.Hosts, "each host listed once")
+	assert.Equal(t, []string{"https://a.com/", "https://a.com/x", "https://b.com/"}, class.URLs, "every URL listed")
+}
+
+func TestCollectResponseClasses_FingerprintFallback(t *testing.T) {
+	withoutBody := func(targetURL string, contentLength int64, title string) *httpxrunner.ProbeResult {
+		return &httpxrunner.ProbeResult{InputURL: targetURL, StatusCode: 403, ContentType: "text/html", ContentLength: contentLength, Title: title}
+	}
+	classes := collectResponseClasses([]*httpxrunner.ProbeResult{
+		withoutBody("https://a.com/", 512, "Forbidden"),
+		withoutBody("https://b.com/", 512, "Forbidden"),
+		withoutBody("https://c.com/", 513, "Forbidden"),
+		withoutBody("https://d.com/", 512, "Access Denied"),
+		// A captured body is never grouped with a fingerprint, even with the same status, type, length and title
+		{InputURL: "https://e.com/", StatusCode: 403, ContentType: "text/html", ContentLength: 512, Title: "Forbidden", Body: "x"},
+	})
+
+	require.Len(t, classes, 1)
+	assert.True(t, classes[0].ByFingerprint)
+	assert.Equal(t, "Forbidden", classes[0].Title)
+	assert.Equal(t, []string{"a.com", "b.com"}, classes[0].Hosts)
+}
@@ -0,0 +1,154 @@
+package reporter
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+)
+
+// URL categories shown in the report
+const (
+	URLCategoryAPI    = "api"
+	URLCategoryStatic = "static"
+	URLCategoryPage   = "page"
+)
+
+// apiPathSegments mark a path as an API route wherever they appear
+var apiPathSegments = map[string]bool{
+	"api": true, "apis": true, "_api": true, "rest": true, "graphql": true, "gql": true,
+	"rpc": true, "jsonrpc": true, "xmlrpc": true, "odata": true, "webhook": true, "webhooks": true,
+}
+
+// apiDocumentNames are API description documents, listed with the endpoints they describe
+var apiDocumentNames = map[string]bool{
+	"swagger.json": true, "swagger.yaml": true, "openapi.json": true, "openapi.yaml": true,
+	"api-docs": true, "graphiql": true,
+}
+
+// staticExtensions are file types served as assets rather than pages or API responses
+var staticExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".map": true, ".png": true, ".jpg": true, ".jpeg": true,
+	".gif": true, ".svg": true, ".ico": true, ".webp": true, ".avif": true, ".bmp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true, ".mp4": true,
+	".webm": true, ".mp3": true, ".wav": true, ".pdf": true, ".zip": true, ".gz": true, ".wasm": true,
+}
+
+// extensionContentTypes maps path extensions to the media type a URL without a Content-Type is treated as.
+// The table is fixed rather than taken from mime.TypeByExtension, whose answers depend on the host's mime files.
+var extensionContentTypes = map[string]string{
+	".html": "text/html", ".htm": "text/html", ".xhtml": "application/xhtml+xml", ".php": "text/html",
+	".asp": "text/html", ".aspx": "text/html", ".jsp": "text/html", ".txt": "text/plain", ".xml": "text/xml",
+	".json": "application/json", ".map": "application/json", ".jsonld": "application/ld+json",
+	".ndjson": "application/x-ndjson", ".yaml": "application/yaml", ".yml": "application/yaml",
+	".js": "text/javascript", ".mjs": "text/javascript", ".css": "text/css", ".wasm": "application/wasm",
+	".png": "image/png", ".jpg": "image/jpeg", ".jpeg": "image/jpeg", ".gif": "image/gif", ".svg": "image/svg+xml",
+	".ico": "image/x-icon", ".webp": "image/webp", ".avif": "image/avif", ".bmp": "image/bmp",
+	".woff": "font/woff", ".woff2": "font/woff2", ".ttf": "font/ttf", ".otf": "font/otf",
+	".mp4": "video/mp4", ".webm": "video/webm", ".mp3": "audio/mpeg", ".wav": "audio/wav",
+	".pdf": "application/pdf", ".zip": "application/zip",
+}
+
+var (
+	versionSegmentRegex    = regexp.MustCompile(`^v[0-9]+(\.[0-9]+)?$`)
+	resourceIDSegmentRegex = regexp.MustCompile(`^([0-9]+|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+)
+
+// URLClassification is the category of a URL and the heuristics that put an API endpoint there
+type URLClassification struct {
+	Category string
+	Signals  []string
+}
+
+// ClassifyURL sorts a URL into an API endpoint, a static asset or a page. contentType is the response
+// Content-Type; when empty (failed probes, URLs carried over from earlier scans) it is inferred from the
+// URL's extension. Asset extensions win over API signals so "/api/v1/app.js" stays a static asset.
+func ClassifyURL(rawURL, contentType string) URLClassification {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return URLClassification{Category: URLCategoryPage}
+	}
+
+	mediaType := summary.MediaType(contentType)
+	if contentType == "" {
+		mediaType = summary.MediaType(inferContentTypeFromURL(rawURL))
+	}
+	if staticExtensions[strings.ToLower(path.Ext(u.Path))] || isStaticMediaType(mediaType) {
+		return URLClassification{Category: URLCategoryStatic}
+	}
+
+	// Numeric path segments are common in HTML routes too ("/blog/2024"), so they only count off HTML
+	signals := apiPathSignals(u, mediaType != "text/html")
+	if isAPIMediaType(mediaType) {
+		signals = append(signals, mediaType+" response")
+	}
+	if len(signals) > 0 {
+		return URLClassification{Category: URLCategoryAPI, Signals: signals}
+	}
+	return URLClassification{Category: URLCategoryPage}
+}
+
+// inferContentTypeFromURL guesses the media type of a URL from its path extension using extensionContentTypes,
+// returning an empty string when the URL has no known extension
+func inferContentTypeFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return extensionContentTypes[strings.ToLower(path.Ext(u.Path))]
+}
+
+// apiPathSignals returns the API heuristics matched by the URL's path and query
+func apiPathSignals(u *url.URL, withResourceIDs bool) []string {
+	var signals []string
+	segments := strings.Split(strings.Trim(strings.ToLower(u.Path), "/"), "/")
+
+	for _, segment := range segments {
+		switch {
+		case apiPathSegments[segment]:
+			signals = append(signals, "/"+segment+"/ path")
+		case versionSegmentRegex.MatchString(segment):
+			signals = append(signals, "versioned path")
+		case apiDocumentNames[segment]:
+			signals = append(signals, "API description")
+		}
+	}
+
+	// A trailing numeric or UUID segment after a resource name ("/users/42") is REST-style
+	if n := len(segments); withResourceIDs && n >= 2 && resourceIDSegmentRegex.MatchString(segments[n-1]) && path.Ext(segments[n-2]) == "" && !resourceIDSegmentRegex.MatchString(segments[n-2]) {
+		signals = append(signals, "REST-style resource ID")
+	}
+
+	query := u.Query()
+	if _, ok := query["wsdl"]; ok || strings.HasSuffix(u.Path, ".wsdl") {
+		signals = append(signals, "SOAP WSDL")
+	}
+	return signals
+}
+
+// isAPIMediaType reports whether a media type is a machine-readable API payload
+func isAPIMediaType(mediaType string) bool {
+	switch mediaType {
+	case "application/json", "application/soap+xml", "application/grpc", "application/grpc-web",
+		"application/x-protobuf", "application/protobuf", "application/x-ndjson", "application/graphql":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json")
+}
+
+// isStaticMediaType reports whether a media type is an asset (images, fonts, media, styles, scripts)
+func isStaticMediaType(mediaType string) bool {
+	for _, prefix := range []string{"image/", "font/", "audio/", "video/"} {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	switch mediaType {
+	case "text/css", "text/javascript", "application/javascript", "application/x-javascript",
+		"application/wasm", "application/pdf", "application/zip", "application/font-woff":
+		return true
+	}
+	return false
+}
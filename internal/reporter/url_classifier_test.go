@@ -0,0 +1,107 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		want        URLClassification
+	}{
+		{
+			name: "asset extension wins over API path",
+			url:  "https://example.com/api/v1/app.js",
+			want: URLClassification{Category: URLCategoryStatic},
+		},
+		{
+			name:        "static media type without extension",
+			url:         "https://example.com/logo",
+			contentType: "image/png",
+			want:        URLClassification{Category: URLCategoryStatic},
+		},
+		{
+			name:        "API path segment and version",
+			url:         "https://example.com/api/v2/users",
+			contentType: "text/html",
+			want:        URLClassification{Category: URLCategoryAPI, Signals: []string{"/api/ path", "versioned path"}},
+		},
+		{
+			name:        "JSON response",
+			url:         "https://example.com/status",
+			contentType: "application/json; charset=utf-8",
+			want:        URLClassification{Category: URLCategoryAPI, Signals: []string{"application/json response"}},
+		},
+		{
+			name:        "vendor JSON media type",
+			url:         "https://example.com/status",
+			contentType: "application/problem+json",
+			want:        URLClassification{Category: URLCategoryAPI, Signals: []string{"application/problem+json response"}},
+		},
+		{
+			name:        "REST-style resource ID off HTML",
+			url:         "https://example.com/users/42",
+			contentType: "text/plain",
+			want:        URLClassification{Category: URLCategoryAPI, Signals: []string{"REST-style resource ID"}},
+		},
+		{
+			name:        "numeric segment on an HTML page",
+			url:         "https://example.com/blog/2024",
+			contentType: "text/html",
+			want:        URLClassification{Category: URLCategoryPage},
+		},
+		{
+			name: "UUID resource ID",
+			url:  "https://example.com/orders/123e4567-e89b-12d3-a456-426614174000",
+			want: URLClassification{Category: URLCategoryAPI, Signals: []string{"REST-style resource ID"}},
+		},
+		{
+			name: "API description document",
+			url:  "https://example.com/docs/swagger.json",
+			want: URLClassification{Category: URLCategoryAPI, Signals: []string{"API description", "application/json response"}},
+		},
+		{
+			name: "SOAP WSDL query",
+			url:  "https://example.com/Service.asmx?wsdl",
+			want: URLClassification{Category: URLCategoryAPI, Signals: []string{"SOAP WSDL"}},
+		},
+		{
+			name: "content type inferred from extension",
+			url:  "https://example.com/data/export.JSON",
+			want: URLClassification{Category: URLCategoryAPI, Signals: []string{"application/json response"}},
+		},
+		{
+			name: "HTML extension keeps numeric segments a page",
+			url:  "https://example.com/archive/2024/index.html",
+			want: URLClassification{Category: URLCategoryPage},
+		},
+		{
+			name: "plain page",
+			url:  "https://example.com/about",
+			want: URLClassification{Category: URLCategoryPage},
+		},
+		{
+			name: "unparseable URL",
+			url:  "://bad",
+			want: URLClassification{Category: URLCategoryPage},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyURL(tt.url, tt.contentType))
+		})
+	}
+}
+
+func TestInferContentTypeFromURL(t *testing.T) {
+	assert.Equal(t, "application/json", inferContentTypeFromURL("https://example.com/app.js.map"))
+	assert.Equal(t, "application/yaml", inferContentTypeFromURL("https://example.com/openapi.YML"))
+	assert.Equal(t, "text/html", inferContentTypeFromURL("https://example.com/index.php?id=1"))
+	assert.Empty(t, inferContentTypeFromURL("https://example.com/users"))
+	assert.Empty(t, inferContentTypeFromURL("https://example.com/file.unknownext"))
+}
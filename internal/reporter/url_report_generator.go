@@ -2,6 +2,9 @@ package reporter
 
 import (
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 )
@@ -57,33 +60,90 @@ func (r *HtmlReporter) generateSingleReport(probeResults []*httpxrunner.ProbeRes
 	return []string{outputPath}, nil
 }
 
-// generateChunkedReports creates multiple HTML report files for large result sets
+// generateChunkedReports creates multiple HTML report files for large result sets.
+// Parts are rendered by a bounded worker pool so at most reportWorkers rendered parts are held in memory at once;
+// part numbering and file names stay deterministic regardless of completion order.
 func (r *HtmlReporter) generateChunkedReports(probeResults []*httpxrunner.ProbeResult, baseOutputPath string, maxResults int) ([]string, error) {
 	totalChunks := (len(probeResults) + maxResults - 1) / maxResults
-	outputPaths := make([]string, 0, totalChunks)
+	workers := min(r.reportWorkers(), totalChunks)
+
+	partPaths := make([]string, totalChunks)
+	partErrs := make([]error, totalChunks)
+
+	r.logger.Debug().Int("parts", totalChunks).Int("workers", workers).Msg("Generating multi-part report")
+
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Bool
+	)
+	jobs := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// Skip remaining parts once one has failed, the caller gets an error either way
+				if failed.Load() {
+					continue
+				}
+				if err := r.generateReportPart(probeResults, baseOutputPath, maxResults, i, totalChunks); err != nil {
+					partErrs[i] = err
+					failed.Store(true)
+					continue
+				}
+				partPaths[i] = r.buildOutputPath(baseOutputPath, i+1, totalChunks)
+			}
+		}()
+	}
 
 	for i := 0; i < totalChunks; i++ {
-		start := i * maxResults
-		end := start + maxResults
-		if end > len(probeResults) {
-			end = len(probeResults)
-		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-		chunk := probeResults[start:end]
-		partInfo := fmt.Sprintf("Part %d of %d", i+1, totalChunks)
+	outputPaths := make([]string, 0, totalChunks)
+	for _, path := range partPaths {
+		if path != "" {
+			outputPaths = append(outputPaths, path)
+		}
+	}
 
-		pageData, err := r.prepareReportData(chunk, partInfo)
+	for _, err := range partErrs {
 		if err != nil {
-			return outputPaths, fmt.Errorf("failed to prepare data for chunk %d: %w", i+1, err)
+			return outputPaths, err
 		}
+	}
 
-		outputPath := r.buildOutputPath(baseOutputPath, i+1, totalChunks)
-		if err := r.executeAndWriteReport(*pageData, outputPath); err != nil {
-			return outputPaths, fmt.Errorf("failed to write chunk %d: %w", i+1, err)
-		}
+	return outputPaths, nil
+}
 
-		outputPaths = append(outputPaths, outputPath)
+// generateReportPart renders and writes part i (zero-based) of a multi-part report
+func (r *HtmlReporter) generateReportPart(probeResults []*httpxrunner.ProbeResult, baseOutputPath string, maxResults, i, totalChunks int) error {
+	start := i * maxResults
+	end := min(start+maxResults, len(probeResults))
+
+	chunk := probeResults[start:end]
+	partInfo := fmt.Sprintf("Part %d of %d", i+1, totalChunks)
+
+	pageData, err := r.prepareReportData(chunk, partInfo)
+	if err != nil {
+		return fmt.Errorf("failed to prepare data for chunk %d: %w", i+1, err)
 	}
 
-	return outputPaths, nil
+	outputPath := r.buildOutputPath(baseOutputPath, i+1, totalChunks)
+	if err := r.executeAndWriteReport(*pageData, outputPath); err != nil {
+		return fmt.Errorf("failed to write chunk %d: %w", i+1, err)
+	}
+
+	return nil
+}
+
+// reportWorkers returns the number of report parts rendered concurrently
+func (r *HtmlReporter) reportWorkers() int {
+	if r.cfg.ReportWorkers > 0 {
+		return r.cfg.ReportWorkers
+	}
+	return max(1, min(runtime.NumCPU(), MaxDefaultReportWorkers))
 }
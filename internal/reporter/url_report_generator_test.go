@@ -0,0 +1,91 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newChunkingReporter returns a reporter splitting reports into parts of two results each
+func newChunkingReporter(t *testing.T, workers int) *HtmlReporter {
+	t.Helper()
+	cfg := config.NewDefaultReporterConfig()
+	cfg.OutputDir = t.TempDir()
+	cfg.EmbedAssets = true
+	cfg.MaxProbeResultsPerReportFile = 2
+	cfg.ReportWorkers = workers
+	reporter, err := NewHtmlReporter(&cfg, zerolog.Nop())
+	require.NoError(t, err)
+	return reporter
+}
+
+func reportProbeResults(count int) []*httpxrunner.ProbeResult {
+	results := make([]*httpxrunner.ProbeResult, count)
+	for i := range results {
+		results[i] = &httpxrunner.ProbeResult{
+			InputURL:      fmt.Sprintf("https://example.com/page%d", i+1),
+			StatusCode:    200,
+			RootTargetURL: "https://example.com",
+		}
+	}
+	return results
+}
+
+func TestGenerateReport_ChunkedPartsInOrder(t *testing.T) {
+	reporter := newChunkingReporter(t, 3)
+	base := filepath.Join(t.TempDir(), "scan")
+
+	paths, err := reporter.GenerateReport(reportProbeResults(13), base)
+	require.NoError(t, err)
+
+	want := make([]string, 7)
+	for i := range want {
+		want[i] = fmt.Sprintf("%s-part%d.html", base, i+1)
+	}
+	assert.Equal(t, want, paths, "parts are numbered and listed in order whatever order the workers finish in")
+	for i, path := range paths {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), fmt.Sprintf("Part %d of 7", i+1))
+	}
+}
+
+func TestGenerateReport_ChunkedPartFailure(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			reporter := newChunkingReporter(t, workers)
+			base := filepath.Join(t.TempDir(), "scan")
+			// A directory in the way of part 3 makes writing it fail
+			require.NoError(t, os.Mkdir(base+"-part3.html", 0755))
+
+			paths, err := reporter.GenerateReport(reportProbeResults(13), base)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "chunk 3")
+
+			assert.NotContains(t, paths, base+"-part3.html")
+			for _, path := range paths {
+				assert.FileExists(t, path, "only written parts are listed")
+			}
+			if workers == 1 {
+				assert.Equal(t, []string{base + "-part1.html", base + "-part2.html"}, paths, "parts after the failure are skipped")
+			}
+		})
+	}
+}
+
+func TestHtmlReporter_ReportWorkers(t *testing.T) {
+	reporter := &HtmlReporter{cfg: &config.ReporterConfig{ReportWorkers: 3}}
+	assert.Equal(t, 3, reporter.reportWorkers())
+
+	reporter.cfg.ReportWorkers = 0
+	workers := reporter.reportWorkers()
+	assert.GreaterOrEqual(t, workers, 1)
+	assert.LessOrEqual(t, workers, MaxDefaultReportWorkers, "the default is capped")
+}
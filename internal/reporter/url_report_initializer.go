@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"html/template"
+	"path/filepath"
 
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/rs/zerolog"
@@ -91,7 +92,7 @@ func (r *HtmlReporter) getItemsPerPage() int {
 
 // copyAssets copies embedded assets to output directory
 func (r *HtmlReporter) copyAssets() error {
-	assetsDir := r.cfg.OutputDir + "/assets"
+	assetsDir := filepath.Join(r.cfg.OutputDir, reportAssetsDir)
 	return r.assetManager.CopyEmbedDir(assetsFS, "assets", assetsDir)
 }
 
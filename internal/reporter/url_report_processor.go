@@ -65,6 +65,8 @@ func (r *HtmlReporter) setBasicReportInfo(pageData *ReportPageData, partInfo str
 	pageData.ItemsPerPage = r.getItemsPerPage()
 	pageData.EnableDataTables = r.cfg.EnableDataTables
 	pageData.ReportPartInfo = partInfo
+	pageData.ChangedOnly = r.cfg.ChangedOnly
+	pageData.Columns = r.cfg.Columns
 	pageData.FaviconBase64 = r.favicon
 }
 
@@ -88,6 +90,8 @@ func (r *HtmlReporter) processProbeResults(probeResults []*httpxrunner.ProbeResu
 	}
 
 	pageData.ProbeResults = displayResults
+	pageData.APISurface = collectAPISurface(displayResults)
+	pageData.ResponseClasses = collectResponseClasses(probeResults)
 	r.sortAndAssignFilterData(pageData, hostnames, statusCodes, contentTypes, technologies, urlStatuses)
 }
 
@@ -97,6 +101,29 @@ func (r *HtmlReporter) TestProcessProbeResults(probeResults []*httpxrunner.Probe
 	r.processProbeResults(probeResults, pageData)
 }
 
+// collectAPISurface lists the results classified as API endpoints, one row per URL
+func collectAPISurface(displayResults []ProbeResultDisplay) []APIEndpoint {
+	seen := make(map[string]bool)
+	var endpoints []APIEndpoint
+	for _, dr := range displayResults {
+		if dr.Category != URLCategoryAPI || seen[dr.InputURL] {
+			continue
+		}
+		seen[dr.InputURL] = true
+		endpoints = append(endpoints, APIEndpoint{
+			URL:         dr.InputURL,
+			Method:      dr.Method,
+			StatusCode:  dr.StatusCode,
+			ContentType: dr.ContentType,
+			URLStatus:   dr.URLStatus,
+			Signals:     dr.APISignals,
+		})
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].URL < endpoints[j].URL })
+	return endpoints
+}
+
 func (r *HtmlReporter) shouldIncludeHostnameInFilter(pr *httpxrunner.ProbeResult) bool {
 	if pr.Error != "" && pr.StatusCode == 0 {
 		return false
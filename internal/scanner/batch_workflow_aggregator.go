@@ -16,6 +16,7 @@ func (bwo *BatchWorkflowOrchestrator) aggregateBatchResults(
 	aggregated.ProbeStats.SuccessfulProbes += batchSummary.ProbeStats.SuccessfulProbes
 	aggregated.ProbeStats.FailedProbes += batchSummary.ProbeStats.FailedProbes
 	aggregated.ProbeStats.DiscoverableItems += batchSummary.ProbeStats.DiscoverableItems
+	aggregated.ProbeStats.DuplicatesSkipped += batchSummary.ProbeStats.DuplicatesSkipped
 
 	// Aggregate diff stats
 	aggregated.DiffStats.New += batchSummary.DiffStats.New
@@ -23,6 +24,10 @@ func (bwo *BatchWorkflowOrchestrator) aggregateBatchResults(
 	aggregated.DiffStats.Existing += batchSummary.DiffStats.Existing
 	aggregated.DiffStats.Changed += batchSummary.DiffStats.Changed
 
+	// Hosts can span batches, so merge rather than overwrite
+	aggregated.HostStats = summary.MergeHostStats(aggregated.HostStats, batchSummary.HostStats)
+	aggregated.NewURLsBySource = summary.MergeNewURLsBySource(aggregated.NewURLsBySource, batchSummary.NewURLsBySource)
+
 	// Aggregate scan duration
 	aggregated.ScanDuration += batchSummary.ScanDuration
 
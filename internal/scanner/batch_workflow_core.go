@@ -9,6 +9,7 @@ import (
 	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/differ"
+	"github.com/aleister1102/monsterinc/internal/events"
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 	"github.com/rs/zerolog"
 )
@@ -47,7 +48,7 @@ func NewBatchWorkflowOrchestrator(
 		logger:         orchestratorLogger,
 		batchProcessor: batchprocessor.NewBatchProcessor(bpConfig, logger),
 		scanner:        scanner,
-		targetManager:  urlhandler.NewTargetManager(logger),
+		targetManager:  urlhandler.NewTargetManager(logger).WithExpansionConfig(gCfg.TargetExpansion.ToTargetExpansionConfig()).WithTagFilter(gCfg.OnlyTags).WithSampling(gCfg.TargetSampling.ToTargetSamplingConfig()),
 	}
 }
 
@@ -107,6 +108,9 @@ func (bwo *BatchWorkflowOrchestrator) ExecuteBatchScan(
 	}
 
 	targetURLs := bwo.targetManager.GetTargetStrings(targets)
+	bwo.scanner.SetTargetCredentials(urlhandler.NewTargetCredentials(targets))
+	bwo.scanner.SetTargetTags(urlhandler.NewTargetTags(targets))
+	bwo.scanner.SetTargetTimeouts(urlhandler.NewTargetTimeouts(targets))
 
 	// Log target loading info
 	bwo.logger.Info().
@@ -114,9 +118,69 @@ func (bwo *BatchWorkflowOrchestrator) ExecuteBatchScan(
 		Str("source", determinedSource).
 		Msg("Successfully loaded targets from file")
 
+	return bwo.ExecuteLoadedTargets(ctx, gCfg, targetURLs, scanSessionID, targetSource, scanMode)
+}
+
+// ExecuteLoadedTargets executes the scan workflow for targets that are already loaded and normalized
+func (bwo *BatchWorkflowOrchestrator) ExecuteLoadedTargets(
+	ctx context.Context,
+	gCfg *config.GlobalConfig,
+	targetURLs []string,
+	scanSessionID string,
+	targetSource string,
+	scanMode string,
+) (*BatchScanResult, error) {
+	bwo.scanner.emitEvent(ctx, events.NewEvent(events.EventScanStarted, scanSessionID).WithPayload(map[string]interface{}{
+		"target_source": targetSource,
+		"scan_mode":     scanMode,
+		"total_targets": len(targetURLs),
+		"baseline":      bwo.scanner.baseline,
+	}))
+
+	result, err := bwo.executeLoadedTargets(ctx, gCfg, targetURLs, scanSessionID, targetSource, scanMode)
+	if result != nil {
+		result.SummaryData.Baseline = bwo.scanner.baseline
+		bwo.recordReportIndex(gCfg, result)
+	}
+
+	completedEvent := events.NewEvent(events.EventScanCompleted, scanSessionID)
+	if result != nil {
+		completedEvent = completedEvent.WithSummary(result.SummaryData).WithPayload(map[string]interface{}{
+			"report_file_paths": result.ReportFilePaths,
+			"used_batching":     result.UsedBatching,
+			"total_batches":     result.TotalBatches,
+			"processed_batches": result.ProcessedBatches,
+		})
+	}
+	if err != nil {
+		if completedEvent.Payload == nil {
+			completedEvent.Payload = map[string]interface{}{}
+		}
+		completedEvent.Payload["error"] = err.Error()
+	}
+	bwo.scanner.emitEvent(ctx, completedEvent)
+
+	return result, err
+}
+
+// executeLoadedTargets runs the scan for already-loaded targets, batching when above the threshold
+func (bwo *BatchWorkflowOrchestrator) executeLoadedTargets(
+	ctx context.Context,
+	gCfg *config.GlobalConfig,
+	targetURLs []string,
+	scanSessionID string,
+	targetSource string,
+	scanMode string,
+) (*BatchScanResult, error) {
 	// Check if batching is needed
 	useBatching := bwo.batchProcessor.ShouldUseBatching(len(targetURLs))
 
+	batchCount, _ := bwo.batchProcessor.GetBatchingStats(len(targetURLs))
+	progress := NewScanProgress(gCfg.Progress, len(targetURLs), batchCount, bwo.logger)
+	ctx = withScanProgress(ctx, progress)
+	stopProgress := progress.Start(ctx)
+	defer stopProgress()
+
 	if !useBatching {
 		bwo.logger.Info().
 			Int("target_count", len(targetURLs)).
@@ -124,6 +188,7 @@ func (bwo *BatchWorkflowOrchestrator) ExecuteBatchScan(
 			Msg("Target count below batching threshold, processing all at once")
 
 		// Execute single scan workflow
+		progress.BeginBatch()
 		summaryData, _, reportPaths, err := bwo.scanner.ExecuteSingleScanWorkflowWithReporting(
 			ctx,
 			gCfg,
@@ -133,6 +198,9 @@ func (bwo *BatchWorkflowOrchestrator) ExecuteBatchScan(
 			targetSource,
 			scanMode,
 		)
+		if err == nil {
+			progress.CompleteBatch(len(targetURLs))
+		}
 
 		return &BatchScanResult{
 			SummaryData:      summaryData,
@@ -2,12 +2,14 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 
 	"github.com/aleister1102/monsterinc/internal/common/summary"
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/differ"
+	"github.com/aleister1102/monsterinc/internal/events"
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 )
 
@@ -50,6 +52,9 @@ func (bwo *BatchWorkflowOrchestrator) executeBatchedScan(
 	allURLDiffResults := make(map[string]differ.URLDiffResult)
 	bwo.logger.Info().Msg("Aggregating all batch results into merged reports (respecting Discord file size limits)")
 
+	// Shared across batches so each URL is probed at most once per scan
+	deduplicator := NewCrossBatchDeduplicator()
+
 	// Initialize summary data
 	aggregatedSummary = summary.GetDefaultScanSummaryData()
 	aggregatedSummary.ScanSessionID = scanSessionID
@@ -59,8 +64,10 @@ func (bwo *BatchWorkflowOrchestrator) executeBatchedScan(
 	aggregatedSummary.TotalTargets = len(targetURLs)
 
 	// Process function for each batch
+	progress := scanProgressFromContext(ctx)
 	processFunc := func(ctx context.Context, batch []string, batchIndex int) error {
 		batchNumber := batchIndex + 1 // Make it 1-based for display
+		progress.BeginBatch()
 
 		bwo.logger.Info().
 			Int("batch_index", batchIndex).
@@ -78,6 +85,7 @@ func (bwo *BatchWorkflowOrchestrator) executeBatchedScan(
 
 		// Always execute core workflow without generating reports per batch
 		ctx = context.WithValue(ctx, disableNotificationsKey, true)
+		ctx, dedupScope := withBatchDedupScope(ctx, deduplicator)
 		batchProbeResults, batchURLDiffResults, err := bwo.scanner.ExecuteScanWorkflow(
 			ctx,
 			batch,
@@ -113,16 +121,29 @@ func (bwo *BatchWorkflowOrchestrator) executeBatchedScan(
 			URLDiffResults: batchURLDiffResults,
 		}
 		batchSummary = summaryBuilder.BuildSummary(summaryInput)
+		batchSummary.ProbeStats.DuplicatesSkipped = dedupScope.skipped
 
 		bwo.logger.Info().
 			Int("batch_index", batchIndex).
 			Int("batch_probe_results", len(batchProbeResults)).
+			Int("batch_duplicates_skipped", batchSummary.ProbeStats.DuplicatesSkipped).
 			Int("total_accumulated_results", len(allProbeResults)).
 			Msg("Batch results accumulated for merged report")
 
 		// Aggregate results
 		bwo.aggregateBatchResults(&aggregatedSummary, batchSummary)
 		processedBatches++
+		progress.CompleteBatch(len(batch))
+
+		bwo.scanner.emitEvent(ctx, events.NewEvent(events.EventBatchCompleted, scanSessionID).
+			WithSummary(batchSummary).
+			WithPayload(map[string]interface{}{
+				"batch_session_id":   batchSessionID,
+				"batch_number":       batchNumber,
+				"total_batches":      batchCount,
+				"duplicates_skipped": batchSummary.ProbeStats.DuplicatesSkipped,
+			}))
+
 		// Force garbage collection after each batch to free memory
 		runtime.GC()
 
@@ -152,16 +173,24 @@ func (bwo *BatchWorkflowOrchestrator) executeBatchedScan(
 	// Ensure crawler is fully shutdown before generating reports to prevent ongoing requests
 	bwo.ensureCrawlerShutdown()
 
+	// A scan that hit its deadline still reports the batches it finished
+	timedOut := errors.Is(err, context.DeadlineExceeded)
+	reportCtx := ctx
+	if timedOut {
+		reportCtx = context.WithoutCancel(ctx)
+	}
+
 	// Generate merged report from all batch results if we have any
-	if len(allProbeResults) > 0 && (err == nil && processedBatches > 0) {
+	if len(allProbeResults) > 0 && ((err == nil || timedOut) && processedBatches > 0) {
 		bwo.logger.Info().
 			Int("total_probe_results", len(allProbeResults)).
 			Int("total_url_diffs", len(allURLDiffResults)).
 			Msg("Generating merged report from all batch results")
+		progress.SetStage(ProgressStageReporting)
 
 		reportGenerator := NewReportGenerator(&gCfg.ReporterConfig, bwo.logger)
 		reportInput := NewReportGenerationInputWithDiff(allProbeResults, allURLDiffResults, scanSessionID)
-		mergedReportPaths, reportErr := reportGenerator.GenerateReports(ctx, reportInput)
+		mergedReportPaths, reportErr := reportGenerator.GenerateReports(reportCtx, reportInput)
 
 		if reportErr != nil {
 			bwo.logger.Warn().Err(reportErr).Msg("Failed to generate merged report")
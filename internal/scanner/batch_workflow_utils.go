@@ -47,6 +47,7 @@ func (bwo *BatchWorkflowOrchestrator) logBatchProcessingSummary(result *BatchSca
 		Int("total_targets", result.SummaryData.TotalTargets).
 		Int("successful_probes", result.SummaryData.ProbeStats.SuccessfulProbes).
 		Int("failed_probes", result.SummaryData.ProbeStats.FailedProbes).
+		Int("duplicates_skipped", result.SummaryData.ProbeStats.DuplicatesSkipped).
 		Int("new_urls", result.SummaryData.DiffStats.New).
 		Int("existing_urls", result.SummaryData.DiffStats.Existing).
 		Int("old_urls", result.SummaryData.DiffStats.Old).
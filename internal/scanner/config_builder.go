@@ -1,6 +1,9 @@
 package scanner
 
 import (
+	"net/http"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 	"github.com/rs/zerolog"
@@ -11,14 +14,53 @@ import (
 type ConfigBuilder struct {
 	globalConfig *config.GlobalConfig
 	logger       zerolog.Logger
+	// Rotates httpx User-Agents across runs; httpx sends a single UA per run
+	httpxUserAgents *httpclient.UserAgentRotator
+	// Proxy pool of scan traffic; nil when proxy_config.pool has no URLs
+	proxyPool *httpclient.ProxyPool
+	// Limit on scan requests in flight across crawler and httpx; nil when max_in_flight_requests is 0
+	inFlightLimiter *httpclient.InFlightLimiter
 }
 
 // NewConfigBuilder creates a new configuration builder
 func NewConfigBuilder(globalConfig *config.GlobalConfig, logger zerolog.Logger) *ConfigBuilder {
-	return &ConfigBuilder{
+	cb := &ConfigBuilder{
 		globalConfig: globalConfig,
 		logger:       logger.With().Str("module", "ConfigBuilder").Logger(),
+		httpxUserAgents: httpclient.NewUserAgentRotator(
+			"",
+			globalConfig.HttpxRunnerConfig.UserAgents,
+			globalConfig.HttpxRunnerConfig.UserAgentRotation,
+		),
+	}
+
+	if len(globalConfig.ProxyConfig.NoProxy) > 0 {
+		cb.logger.Warn().
+			Strs("no_proxy", globalConfig.ProxyConfig.NoProxy).
+			Msg("httpx uses a single proxy for all targets; no_proxy only applies to the crawler and notification clients")
+	}
+
+	proxyPool, err := httpclient.NewProxyPool(globalConfig.ProxyConfig, logger)
+	if err != nil {
+		cb.logger.Warn().Err(err).Msg("Invalid proxy pool configuration, scan traffic uses the single proxy settings")
+	} else if proxyPool != nil {
+		cb.proxyPool = proxyPool
+		cb.logger.Info().
+			Int("proxies", proxyPool.Size()).
+			Str("rotation", globalConfig.ProxyConfig.Pool.Rotation).
+			Bool("fallback_direct", globalConfig.ProxyConfig.Pool.FallbackDirect).
+			Msg("Scan traffic rotates over the proxy pool")
+	}
+
+	if cb.inFlightLimiter = httpclient.NewInFlightLimiter(globalConfig.MaxInFlight); cb.inFlightLimiter != nil {
+		cb.logger.Info().
+			Int("max_in_flight_requests", cb.inFlightLimiter.Max()).
+			Int("crawler_max_concurrent_requests", globalConfig.CrawlerConfig.MaxConcurrentRequests).
+			Int("httpx_threads", globalConfig.HttpxRunnerConfig.Threads).
+			Msg("Scan requests in flight are capped across crawler and httpx")
 	}
+
+	return cb
 }
 
 // BuildCrawlerConfig creates crawler configuration with seed URLs
@@ -27,24 +69,79 @@ func (cb *ConfigBuilder) BuildCrawlerConfig(seedURLs []string, scanSessionID str
 	crawlerConfig := cb.globalConfig.CrawlerConfig
 	crawlerConfig.SeedURLs = make([]string, len(seedURLs))
 	copy(crawlerConfig.SeedURLs, seedURLs)
+	crawlerConfig.RequestHeaders = cb.globalConfig.RequestHeaders
+	crawlerConfig.Proxy = cb.globalConfig.ProxyConfig
+	crawlerConfig.ProxyPool = cb.proxyPool
+	crawlerConfig.InFlightLimiter = cb.inFlightLimiter
 
 	primaryRootTargetURL := cb.determinePrimaryRootTarget(seedURLs, scanSessionID)
 	return &crawlerConfig, primaryRootTargetURL, nil
 }
 
-// BuildHTTPXConfig creates HTTPX runner configuration from global config
-func (cb *ConfigBuilder) BuildHTTPXConfig(targets []string) *httpxrunner.Config {
+// InFlightLimiter returns the limit on scan requests in flight, or nil when max_in_flight_requests is 0
+func (cb *ConfigBuilder) InFlightLimiter() *httpclient.InFlightLimiter {
+	return cb.inFlightLimiter
+}
+
+// buildHTTPXHeaders merges the global default request headers with httpx-specific custom headers.
+// httpx custom headers take precedence, and a configured User-Agent pool overrides any static User-Agent.
+func (cb *ConfigBuilder) buildHTTPXHeaders(customHeaders map[string]string) map[string]string {
+	headers := cb.globalConfig.RequestHeaders.HeadersForHost("")
+	for key, value := range customHeaders {
+		headers[key] = value
+	}
+
+	if userAgent := cb.httpxUserAgents.Next(); userAgent != "" {
+		for key := range headers {
+			if http.CanonicalHeaderKey(key) == "User-Agent" {
+				delete(headers, key)
+			}
+		}
+		headers["User-Agent"] = userAgent
+	}
+
+	return headers
+}
+
+// buildHTTPXProxy resolves the single proxy URL handed to httpx. With a proxy pool, each httpx run takes
+// the next healthy proxy, so consecutive targets leave through different proxies.
+func (cb *ConfigBuilder) buildHTTPXProxy() (string, error) {
+	if cb.proxyPool != nil {
+		proxyURL, err := cb.proxyPool.Next()
+		if err != nil || proxyURL == nil {
+			return "", err
+		}
+		return proxyURL.String(), nil
+	}
+
+	proxyURL, err := cb.globalConfig.ProxyConfig.SingleURL()
+	if err != nil {
+		cb.logger.Warn().Err(err).Msg("Invalid proxy configuration, httpx will connect directly")
+		return "", nil
+	}
+	return proxyURL, nil
+}
+
+// BuildHTTPXConfig creates HTTPX runner configuration from global config. It fails only when the proxy
+// pool has no healthy proxy left and direct fallback is disabled.
+func (cb *ConfigBuilder) BuildHTTPXConfig(targets []string) (*httpxrunner.Config, error) {
 	httpxCfg := &cb.globalConfig.HttpxRunnerConfig
 
+	proxyURL, err := cb.buildHTTPXProxy()
+	if err != nil {
+		return nil, err
+	}
+
 	return &httpxrunner.Config{
 		Targets:              targets,
 		Method:               httpxCfg.Method,
+		Proxy:                proxyURL,
 		RequestURIs:          httpxCfg.RequestURIs,
 		FollowRedirects:      httpxCfg.FollowRedirects,
 		Timeout:              httpxCfg.TimeoutSecs,
 		Retries:              httpxCfg.Retries,
 		Threads:              httpxCfg.Threads,
-		CustomHeaders:        httpxCfg.CustomHeaders,
+		CustomHeaders:        cb.buildHTTPXHeaders(httpxCfg.CustomHeaders),
 		Verbose:              httpxCfg.Verbose,
 		TechDetect:           httpxCfg.TechDetect,
 		ExtractASN:           httpxCfg.ExtractASN,
@@ -57,7 +154,8 @@ func (cb *ConfigBuilder) BuildHTTPXConfig(targets []string) *httpxrunner.Config
 		ExtractIPs:           httpxCfg.ExtractIPs,
 		ExtractBody:          httpxCfg.ExtractBody,
 		ExtractHeaders:       httpxCfg.ExtractHeaders,
-	}
+		ExtractTLS:           httpxCfg.ExtractTLS,
+	}, nil
 }
 
 // determinePrimaryRootTarget determines the primary root target URL
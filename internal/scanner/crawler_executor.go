@@ -57,6 +57,10 @@ func (ce *CrawlerExecutor) Execute(input CrawlerExecutionInput) *CrawlerExecutio
 		return result
 	}
 
+	if input.CrawlerConfig.RootProbeOnly {
+		return ce.executeRootProbeOnly(input)
+	}
+
 	ce.logger.Info().
 		Int("seed_count", len(input.CrawlerConfig.SeedURLs)).
 		Str("session_id", input.ScanSessionID).
@@ -98,6 +102,24 @@ func (ce *CrawlerExecutor) Execute(input CrawlerExecutionInput) *CrawlerExecutio
 	return result
 }
 
+// executeRootProbeOnly skips crawling and hands each seed host's root URL and common paths to the prober
+func (ce *CrawlerExecutor) executeRootProbeOnly(input CrawlerExecutionInput) *CrawlerExecutionResult {
+	probeURLs := buildRootProbeURLs(input.CrawlerConfig.SeedURLs, input.CrawlerConfig.RootProbePaths)
+
+	for _, probeURL := range probeURLs {
+		ce.logger.Debug().Str("url", probeURL).Msg("Root probe URL queued")
+	}
+
+	ce.logger.Info().
+		Int("seed_count", len(input.CrawlerConfig.SeedURLs)).
+		Int("common_paths", len(input.CrawlerConfig.RootProbePaths)).
+		Int("probe_count", len(probeURLs)).
+		Str("session_id", input.ScanSessionID).
+		Msg("Root probe only mode enabled, skipping crawler")
+
+	return &CrawlerExecutionResult{DiscoveredURLs: probeURLs}
+}
+
 // Shutdown gracefully shuts down the crawler executor and its managed crawler
 func (ce *CrawlerExecutor) Shutdown() {
 	ce.logger.Info().Msg("Shutting down crawler executor")
@@ -157,6 +157,7 @@ func (cm *CrawlerManager) monitorCrawlerProgress(ctx context.Context, crawlerIns
 			// Get current stats from crawler
 			discoveredCount := len(crawlerInstance.GetDiscoveredURLs())
 			cm.logger.Info().Int("discovered_count", discoveredCount).Msg("Crawler progress")
+			scanProgressFromContext(ctx).SetCrawling(discoveredCount)
 		}
 	}
 }
@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+)
+
+const (
+	crossBatchDeduplicatorKey contextKey = "cross_batch_deduplicator"
+)
+
+// CrossBatchDeduplicator tracks URLs probed by earlier batches of the same scan
+// so a URL discovered from several batches is only probed once. The probe results are
+// kept so later batches can still store those URLs with their host's other results.
+type CrossBatchDeduplicator struct {
+	seenURLs map[string]bool
+	probed   map[string]httpxrunner.ProbeResult
+	skipped  int
+	mutex    sync.Mutex
+}
+
+// NewCrossBatchDeduplicator creates an empty cross-batch URL tracker
+func NewCrossBatchDeduplicator() *CrossBatchDeduplicator {
+	return &CrossBatchDeduplicator{
+		seenURLs: make(map[string]bool),
+		probed:   make(map[string]httpxrunner.ProbeResult),
+	}
+}
+
+// FilterUnseen claims the URLs no earlier batch has probed and returns them in order, along with the
+// results earlier batches recorded for the rest and how many URLs were skipped. Repeats within urls are
+// collapsed without counting as skipped. A URL claimed by a batch still probing has no result yet; that
+// batch stores it itself.
+func (d *CrossBatchDeduplicator) FilterUnseen(urls []string) ([]string, []httpxrunner.ProbeResult, int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	unseen := make([]string, 0, len(urls))
+	var earlier []httpxrunner.ProbeResult
+	skipped := 0
+	inCall := make(map[string]bool, len(urls))
+	for _, rawURL := range urls {
+		key := dedupKey(rawURL)
+		if inCall[key] {
+			continue
+		}
+		inCall[key] = true
+
+		if d.seenURLs[key] {
+			skipped++
+			if result, ok := d.probed[key]; ok {
+				earlier = append(earlier, result)
+			}
+			continue
+		}
+		d.seenURLs[key] = true
+		unseen = append(unseen, rawURL)
+	}
+
+	d.skipped += skipped
+	return unseen, earlier, skipped
+}
+
+// RecordProbed keeps the results of a batch for the batches that skip the same URLs
+func (d *CrossBatchDeduplicator) RecordProbed(results []httpxrunner.ProbeResult) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, result := range results {
+		d.probed[dedupKey(result.InputURL)] = result
+	}
+}
+
+// SkippedCount returns the total number of duplicate URLs skipped so far
+func (d *CrossBatchDeduplicator) SkippedCount() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.skipped
+}
+
+// dedupKey normalizes a URL for comparison, falling back to the raw value when it cannot be parsed
+func dedupKey(rawURL string) string {
+	normalized, err := urlhandler.NormalizeURL(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return normalized
+}
+
+// batchDedupScope binds the scan-wide deduplicator to one batch and records what that batch skipped
+type batchDedupScope struct {
+	deduplicator *CrossBatchDeduplicator
+	skipped      int
+	// Results of skipped URLs probed by earlier batches, stored again with this batch's host files
+	earlierResults []httpxrunner.ProbeResult
+}
+
+// withBatchDedupScope attaches a per-batch view of the deduplicator to the batch context
+func withBatchDedupScope(ctx context.Context, deduplicator *CrossBatchDeduplicator) (context.Context, *batchDedupScope) {
+	scope := &batchDedupScope{deduplicator: deduplicator}
+	return context.WithValue(ctx, crossBatchDeduplicatorKey, scope), scope
+}
+
+// batchDedupScopeFromContext returns the batch's dedup scope, or nil outside batch mode
+func batchDedupScopeFromContext(ctx context.Context) *batchDedupScope {
+	scope, _ := ctx.Value(crossBatchDeduplicatorKey).(*batchDedupScope)
+	return scope
+}
@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/datastore"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossBatchDeduplicator_FilterUnseen(t *testing.T) {
+	dedup := NewCrossBatchDeduplicator()
+
+	first, earlier, skipped := dedup.FilterUnseen([]string{"https://Example.com/a", "https://example.com/b", "https://example.com/b"})
+	assert.Equal(t, []string{"https://Example.com/a", "https://example.com/b"}, first)
+	assert.Empty(t, earlier)
+	assert.Equal(t, 0, skipped, "repeats within one batch are not cross-batch skips")
+
+	dedup.RecordProbed([]httpxrunner.ProbeResult{{InputURL: "https://Example.com/a", StatusCode: 200}})
+
+	second, earlier, skipped := dedup.FilterUnseen([]string{"https://example.com/a", "https://example.com/b", "https://example.com/c", "https://example.com/c"})
+	assert.Equal(t, []string{"https://example.com/c"}, second)
+	assert.Equal(t, 2, skipped)
+	assert.Equal(t, 2, dedup.SkippedCount())
+	require.Len(t, earlier, 1, "only URLs whose probe finished have a result to store again")
+	assert.Equal(t, "https://Example.com/a", earlier[0].InputURL)
+}
+
+func TestBatchDedupScope_FromContext(t *testing.T) {
+	assert.Nil(t, batchDedupScopeFromContext(context.Background()))
+
+	dedup := NewCrossBatchDeduplicator()
+	ctx, scope := withBatchDedupScope(context.Background(), dedup)
+	assert.Same(t, scope, batchDedupScopeFromContext(ctx))
+	assert.Same(t, dedup, scope.deduplicator)
+}
+
+func TestBatchedScan_StoresURLsSkippedByLaterBatches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		// Both seeds link to /shared; only the second links to /unique, so its batch rewrites the host file
+		links := `<a href="/shared">shared</a>`
+		if r.URL.Path == "/other" {
+			links += `<a href="/unique">unique</a>`
+		}
+		_, _ = fmt.Fprintf(w, `<html><body>%s</body></html>`, links)
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultGlobalConfig()
+	cfg.StorageConfig.ParquetBasePath = t.TempDir()
+	cfg.ReporterConfig.OutputDir = t.TempDir()
+	cfg.NotificationConfig = config.NotificationConfig{}
+	cfg.ScanBatchConfig.BatchSize = 1
+	cfg.ScanBatchConfig.MaxConcurrentBatch = 1
+	cfg.ScanBatchConfig.ThresholdSize = 1
+
+	result, err := RunOnetime(context.Background(), cfg, []string{server.URL + "/", server.URL + "/other"})
+	require.NoError(t, err)
+	require.True(t, result.UsedBatching)
+	assert.Positive(t, result.SummaryData.ProbeStats.DuplicatesSkipped, "the second batch skips probing /shared")
+
+	host, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	stored, _, err := datastore.NewParquetReader(&cfg.StorageConfig, zerolog.Nop()).FindAllProbeResultsForTarget(host.Hostname())
+	require.NoError(t, err)
+	var storedURLs []string
+	for _, probe := range stored {
+		storedURLs = append(storedURLs, probe.InputURL)
+	}
+	assert.Contains(t, storedURLs, server.URL+"/unique")
+	assert.Contains(t, storedURLs, server.URL+"/shared", "the second batch's rewrite of the host file keeps the URL it skipped")
+}
@@ -2,9 +2,12 @@ package scanner
 
 import (
 	"context"
+	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/contextutils"
 	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/datastore"
 	"github.com/aleister1102/monsterinc/internal/differ"
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 	"github.com/rs/zerolog"
@@ -13,9 +16,11 @@ import (
 // DiffStorageProcessor handles diffing and storage operations
 // Separates diff and storage logic from the main scanner
 type DiffStorageProcessor struct {
-	logger        zerolog.Logger
-	parquetWriter ParquetWriter
-	urlDiffer     *differ.UrlDiffer
+	logger          zerolog.Logger
+	parquetWriter   ParquetWriter
+	urlDiffer       *differ.UrlDiffer
+	urlLifecycle    config.URLLifecycleConfig
+	contentArchiver *datastore.ContentArchiver
 }
 
 // ParquetWriter interface for dependency injection and better testing
@@ -32,6 +37,18 @@ func NewDiffStorageProcessor(logger zerolog.Logger, parquetWriter ParquetWriter,
 	}
 }
 
+// WithURLLifecycle enables retaining unseen URLs in storage for first-seen/last-seen tracking
+func (dsp *DiffStorageProcessor) WithURLLifecycle(cfg config.URLLifecycleConfig) *DiffStorageProcessor {
+	dsp.urlLifecycle = cfg
+	return dsp
+}
+
+// WithContentArchiver archives the bodies of URLs whose content changed after each host is stored; nil disables it
+func (dsp *DiffStorageProcessor) WithContentArchiver(archiver *datastore.ContentArchiver) *DiffStorageProcessor {
+	dsp.contentArchiver = archiver
+	return dsp
+}
+
 // DiffTargetInput contains parameters for processing a single target
 type DiffTargetInput struct {
 	RootTarget            string
@@ -266,7 +283,8 @@ func (dsp *DiffStorageProcessor) processHostnameGroup(
 	dsp.updateProcessedProbeResults(processedProbeResults, updatedProbesForHostnameStorage, originalIndicesForHostname)
 
 	// Store results in Parquet - use hostname instead of root target
-	if err := dsp.writeProbeResultsToParquet(ctx, updatedProbesForHostnameStorage, scanSessionID, hostname); err != nil {
+	probesToWrite := dsp.appendRetainedOldURLs(updatedProbesForHostnameStorage, diffResult.URLDiffResult)
+	if err := dsp.writeProbeResultsToParquet(ctx, probesToWrite, scanSessionID, hostname); err != nil {
 		// Check if error is due to context cancellation
 		if ctx.Err() != nil {
 			dsp.logger.Info().Str("hostname", hostname).Msg("Parquet storage cancelled")
@@ -276,6 +294,8 @@ func (dsp *DiffStorageProcessor) processHostnameGroup(
 		dsp.logger.Warn().Err(err).Str("hostname", hostname).Msg("Failed to write to Parquet, continuing")
 	}
 
+	dsp.archiveChangedContent(ctx, hostname, updatedProbesForHostnameStorage)
+
 	// Add to all probes for potential reporting
 	output.AllProbesToStore = append(output.AllProbesToStore, updatedProbesForHostnameStorage...)
 
@@ -288,6 +308,53 @@ func (dsp *DiffStorageProcessor) processHostnameGroup(
 	return nil
 }
 
+// appendRetainedOldURLs adds URLs missing from this scan to the storage set when lifecycle
+// tracking is enabled, so their first-seen/last-seen history is not lost on rewrite
+func (dsp *DiffStorageProcessor) appendRetainedOldURLs(currentProbes []httpxrunner.ProbeResult, diffResult *differ.URLDiffResult) []httpxrunner.ProbeResult {
+	if !dsp.urlLifecycle.Enabled || diffResult == nil {
+		return currentProbes
+	}
+
+	probesToWrite := currentProbes
+	retained, dropped := 0, 0
+	for _, diffURL := range diffResult.Results {
+		if diffURL.ProbeResult.URLStatus != string(differ.StatusOld) {
+			continue
+		}
+		if !dsp.urlLifecycle.ShouldRetain(diffURL.ProbeResult.MissedScans) {
+			dropped++
+			continue
+		}
+		if retained == 0 {
+			// Copy before appending so the caller's slice is left untouched
+			probesToWrite = append(make([]httpxrunner.ProbeResult, 0, len(currentProbes)+len(diffResult.Results)), currentProbes...)
+		}
+		probesToWrite = append(probesToWrite, diffURL.ProbeResult)
+		retained++
+	}
+
+	if retained > 0 || dropped > 0 {
+		dsp.logger.Debug().
+			Str("root_target", diffResult.RootTargetURL).
+			Int("retained_unseen_urls", retained).
+			Int("dropped_unseen_urls", dropped).
+			Msg("Applied URL lifecycle retention")
+	}
+
+	return probesToWrite
+}
+
+// archiveChangedContent writes snapshots of changed bodies; failures are logged and never fail the scan
+func (dsp *DiffStorageProcessor) archiveChangedContent(ctx context.Context, hostname string, probes []httpxrunner.ProbeResult) {
+	if dsp.contentArchiver == nil {
+		return
+	}
+
+	if _, err := dsp.contentArchiver.Archive(ctx, hostname, probes, time.Now()); err != nil {
+		dsp.logger.Warn().Err(err).Str("hostname", hostname).Msg("Failed to archive changed content, continuing")
+	}
+}
+
 // writeProbeResultsToParquet handles the persistence of probe results to Parquet
 func (dsp *DiffStorageProcessor) writeProbeResultsToParquet(ctx context.Context, probesToStore []httpxrunner.ProbeResult, scanSessionID, hostname string) error {
 	if dsp.parquetWriter == nil {
@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+)
+
+// HARExporter writes the crawler and prober traffic of each scan workflow to HAR files for debugging.
+// The crawler records its requests through the shared recorder; probe results are added once httpx finishes.
+// A nil *HARExporter is valid and records nothing.
+type HARExporter struct {
+	config   config.HARExportConfig
+	recorder *httpclient.HARRecorder
+	logger   zerolog.Logger
+}
+
+// NewHARExporter creates a HARExporter, or returns nil when the export is disabled
+func NewHARExporter(cfg config.HARExportConfig, logger zerolog.Logger) *HARExporter {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &HARExporter{
+		config:   cfg,
+		recorder: httpclient.NewHARRecorder(),
+		logger:   logger.With().Str("module", "HARExporter").Logger(),
+	}
+}
+
+// Recorder returns the recorder the crawler transport writes to
+func (he *HARExporter) Recorder() *httpclient.HARRecorder {
+	if he == nil {
+		return nil
+	}
+	return he.recorder
+}
+
+// Begin drops the entries of the previous workflow
+func (he *HARExporter) Begin() {
+	if he == nil {
+		return
+	}
+	he.recorder.Reset()
+}
+
+// RecordProbeResults adds one entry per probe result. httpx does not expose the request it sent,
+// so prober entries carry the response side, the total duration and any probe error.
+func (he *HARExporter) RecordProbeResults(probeResults []httpxrunner.ProbeResult) {
+	if he == nil {
+		return
+	}
+	for i := range probeResults {
+		he.recorder.Add(probeResultHAREntry(&probeResults[i]))
+	}
+}
+
+// Write flushes the recorded entries of scanSessionID to disk and returns the files written
+func (he *HARExporter) Write(scanSessionID string) []string {
+	if he == nil || he.recorder.Len() == 0 {
+		return nil
+	}
+
+	comment := "MonsterInc scan session " + scanSessionID
+	outputDir := config.ExpandPathTemplate(he.config.OutputDir, scanSessionID, time.Now())
+	var paths []string
+	var err error
+	if he.config.PerHost {
+		paths, err = he.recorder.WriteFilesPerHost(filepath.Join(outputDir, scanSessionID), comment)
+	} else {
+		path := filepath.Join(outputDir, scanSessionID+".har")
+		if err = he.recorder.WriteFile(path, comment); err == nil {
+			paths = []string{path}
+		}
+	}
+	if err != nil {
+		he.logger.Warn().Err(err).Str("session_id", scanSessionID).Msg("Failed to write HAR export")
+		return paths
+	}
+
+	he.logger.Info().
+		Str("session_id", scanSessionID).
+		Int("entries", he.recorder.Len()).
+		Strs("files", paths).
+		Msg("HAR export written")
+	return paths
+}
+
+// probeResultHAREntry converts a probe result to a HAR entry
+func probeResultHAREntry(pr *httpxrunner.ProbeResult) httpclient.HAREntry {
+	method := pr.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	duration := pr.Duration * float64(time.Second/time.Millisecond)
+
+	header := make(http.Header, len(pr.Headers))
+	for name, value := range pr.Headers {
+		header.Set(name, value)
+	}
+
+	entry := httpclient.HAREntry{
+		StartedDateTime: pr.Timestamp,
+		Time:            duration,
+		Request: httpclient.HARRequest{
+			Method:      method,
+			URL:         pr.InputURL,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []httpclient.HARNameValue{},
+			Headers:     []httpclient.HARNameValue{},
+			QueryString: []httpclient.HARNameValue{},
+			HeadersSize: -1,
+			BodySize:    0,
+		},
+		Response: httpclient.HARResponse{
+			Status:      pr.StatusCode,
+			StatusText:  http.StatusText(pr.StatusCode),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []httpclient.HARNameValue{},
+			Headers:     httpclient.HARHeaders(header),
+			Content:     httpclient.HARContent{Size: max(pr.ContentLength, 0), MimeType: pr.ContentType},
+			HeadersSize: -1,
+			BodySize:    pr.ContentLength,
+		},
+		Timings: httpclient.HARTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Send: 0, Wait: duration, Receive: 0},
+		Source:  httpclient.HARSourceProber,
+		Error:   pr.Error,
+	}
+	if u, err := url.Parse(pr.InputURL); err == nil {
+		entry.Request.QueryString = httpclient.HARQueryString(u.RawQuery)
+	}
+	if pr.FinalURL != "" && pr.FinalURL != pr.InputURL {
+		entry.Response.RedirectURL = pr.FinalURL
+	}
+	if len(pr.IPs) > 0 {
+		entry.ServerIPAddress = pr.IPs[0]
+	}
+	return entry
+}
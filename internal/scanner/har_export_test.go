@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHARExporter_DisabledIsNil(t *testing.T) {
+	exporter := NewHARExporter(config.NewDefaultHARExportConfig(), zerolog.Nop())
+	assert.Nil(t, exporter)
+	assert.Nil(t, exporter.Recorder())
+
+	// Every method is safe on the disabled exporter
+	exporter.Begin()
+	exporter.RecordProbeResults([]httpxrunner.ProbeResult{{InputURL: "https://example.com"}})
+	assert.Nil(t, exporter.Write("session"))
+}
+
+func TestHARExporter_WritesProbeResults(t *testing.T) {
+	cfg := config.HARExportConfig{Enabled: true, OutputDir: t.TempDir()}
+	exporter := NewHARExporter(cfg, zerolog.Nop())
+	require.NotNil(t, exporter)
+
+	exporter.Begin()
+	exporter.RecordProbeResults([]httpxrunner.ProbeResult{{
+		InputURL:      "https://example.com/login?next=/home",
+		FinalURL:      "https://example.com/home",
+		StatusCode:    302,
+		ContentType:   "text/html",
+		ContentLength: 42,
+		Duration:      0.25,
+		Headers:       map[string]string{"location": "/home", "set-cookie": "sid=1"},
+		IPs:           []string{"93.184.216.34"},
+		Timestamp:     time.Unix(1700000000, 0).UTC(),
+	}})
+
+	paths := exporter.Write("20240101-000000")
+	require.Equal(t, []string{filepath.Join(cfg.OutputDir, "20240101-000000.har")}, paths)
+
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	var har httpclient.HAR
+	require.NoError(t, json.Unmarshal(data, &har))
+	require.Len(t, har.Log.Entries, 1)
+
+	entry := har.Log.Entries[0]
+	assert.Equal(t, httpclient.HARSourceProber, entry.Source)
+	assert.Equal(t, "GET", entry.Request.Method)
+	assert.Equal(t, []httpclient.HARNameValue{{Name: "next", Value: "/home"}}, entry.Request.QueryString)
+	assert.Equal(t, 302, entry.Response.Status)
+	assert.Equal(t, "https://example.com/home", entry.Response.RedirectURL)
+	assert.Equal(t, 250.0, entry.Time)
+	assert.Equal(t, "93.184.216.34", entry.ServerIPAddress)
+	assert.Contains(t, entry.Response.Headers, httpclient.HARNameValue{Name: "Set-Cookie", Value: "[REDACTED]"})
+
+	// A new workflow starts from an empty recorder
+	exporter.Begin()
+	assert.Equal(t, 0, exporter.Recorder().Len())
+	assert.Nil(t, exporter.Write("next"))
+}
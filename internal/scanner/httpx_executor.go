@@ -2,10 +2,13 @@ package scanner
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/contextutils"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
 	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/crawler"
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 	"github.com/rs/zerolog"
@@ -17,6 +20,7 @@ type HTTPXExecutor struct {
 	logger          zerolog.Logger
 	crawlerInstance *crawler.Crawler
 	httpxManager    *HTTPXManager // Added httpx manager for singleton instance
+	inFlightLimiter *httpclient.InFlightLimiter
 }
 
 // NewHTTPXExecutor creates a new HTTPX executor
@@ -32,6 +36,11 @@ func (he *HTTPXExecutor) SetCrawlerInstance(crawlerInstance *crawler.Crawler) {
 	he.crawlerInstance = crawlerInstance
 }
 
+// SetInFlightLimiter caps each httpx run's threads by the free slots of the limit shared with the crawler
+func (he *HTTPXExecutor) SetInFlightLimiter(limiter *httpclient.InFlightLimiter) {
+	he.inFlightLimiter = limiter
+}
+
 // Shutdown gracefully shuts down the httpx executor and its managed components
 func (he *HTTPXExecutor) Shutdown() {
 	he.logger.Info().Msg("Shutting down HTTPX executor")
@@ -52,6 +61,9 @@ type HTTPXExecutionInput struct {
 	PrimaryRootTargetURL string
 	ScanSessionID        string
 	HttpxRunnerConfig    *httpxrunner.Config
+	RequestHeaders       config.RequestHeadersConfig
+	TargetCredentials    urlhandler.TargetCredentials
+	TargetTimeouts       urlhandler.TargetTimeouts
 }
 
 // HTTPXExecutionResult contains the results from HTTPX execution
@@ -79,7 +91,7 @@ func (he *HTTPXExecutor) Execute(input HTTPXExecutionInput) *HTTPXExecutionResul
 
 	he.logger.Info().Int("url_count", len(input.DiscoveredURLs)).Str("session_id", input.ScanSessionID).Msg("Starting HTTPX probing")
 
-	runnerResults, err := he.runHTTPXRunner(input.Context, input.HttpxRunnerConfig, input.PrimaryRootTargetURL, input.ScanSessionID)
+	runnerResults, err := he.runHTTPXRunnerWithHostHeaders(input)
 
 	// Handle context cancellation during execution - immediate response
 	if err != nil && (input.Context.Err() == context.Canceled || input.Context.Err() == context.DeadlineExceeded) {
@@ -109,11 +121,137 @@ func (he *HTTPXExecutor) Execute(input HTTPXExecutionInput) *HTTPXExecutionResul
 }
 
 // runHTTPXRunner uses the managed httpx runner instead of creating new instances
+// With a shared in-flight limit, the run holds one slot per httpx thread and runs with as many threads as it got
 func (he *HTTPXExecutor) runHTTPXRunner(ctx context.Context, runnerConfig *httpxrunner.Config, primaryRootTargetURL, scanSessionID string) ([]httpxrunner.ProbeResult, error) {
+	if he.inFlightLimiter == nil {
+		return he.httpxManager.ExecuteRunnerBatch(ctx, runnerConfig, primaryRootTargetURL, scanSessionID)
+	}
+
+	threads := runnerConfig.Threads
+	if threads <= 0 {
+		threads = config.DefaultHTTPXThreads
+	}
+	slots, err := he.inFlightLimiter.AcquireUpTo(ctx, threads)
+	if err != nil {
+		return nil, err
+	}
+	defer he.inFlightLimiter.Release(slots)
+
+	if slots < threads {
+		he.logger.Debug().
+			Int("threads", threads).
+			Int("slots", slots).
+			Int("max_in_flight_requests", he.inFlightLimiter.Max()).
+			Msg("Reducing httpx threads to the free in-flight request slots")
+		limitedConfig := *runnerConfig
+		limitedConfig.Threads = slots
+		runnerConfig = &limitedConfig
+	}
 	return he.httpxManager.ExecuteRunnerBatch(ctx, runnerConfig, primaryRootTargetURL, scanSessionID)
 }
 
-// processHTTPXResults maps the raw httpx results to httpxrunner.ProbeResult and assigns RootTargetURL
+// probeGroup identifies URLs probed together: same hostname, target credentials and target timeout
+type probeGroup struct {
+	hostname string
+	auth     urlhandler.TargetAuth
+	timeout  time.Duration
+}
+
+// httpxBatch is one httpx run: the shared batch has a zero group, every other batch is one probeGroup
+type httpxBatch struct {
+	group  probeGroup
+	config httpxrunner.Config
+}
+
+// runHTTPXRunnerWithHostHeaders runs httpx once for URLs using the shared headers and once per host
+// that has its own header overrides, target credentials or target timeout, so each host is probed with
+// its pinned headers and timeout
+func (he *HTTPXExecutor) runHTTPXRunnerWithHostHeaders(input HTTPXExecutionInput) ([]httpxrunner.ProbeResult, error) {
+	if !input.RequestHeaders.HasPerHostOverrides() && input.TargetCredentials.Len() == 0 && input.TargetTimeouts.Len() == 0 {
+		return he.runHTTPXRunner(input.Context, input.HttpxRunnerConfig, input.PrimaryRootTargetURL, input.ScanSessionID)
+	}
+
+	var allResults []httpxrunner.ProbeResult
+	for _, batch := range splitHTTPXBatches(input) {
+		if batch.group.hostname != "" {
+			he.logger.Debug().
+				Str("hostname", batch.group.hostname).
+				Int("url_count", len(batch.config.Targets)).
+				Bool("target_credentials", batch.group.auth.Scheme != "").
+				Int("timeout_secs", batch.config.Timeout).
+				Msg("Probing host with per-host request headers")
+		}
+
+		results, err := he.runHTTPXRunner(input.Context, &batch.config, input.PrimaryRootTargetURL, input.ScanSessionID)
+		allResults = append(allResults, results...)
+		if err != nil {
+			return allResults, err
+		}
+	}
+
+	return allResults, nil
+}
+
+// splitHTTPXBatches groups the httpx targets into a shared batch, first, followed by one batch per probeGroup
+// in order of first appearance. Per-host batches get their own copy of CustomHeaders with the host's overrides
+// and credentials merged over the httpx custom headers; the input config is never modified.
+func splitHTTPXBatches(input HTTPXExecutionInput) []httpxBatch {
+	var sharedTargets []string
+	groupTargets := make(map[probeGroup][]string)
+	var groupOrder []probeGroup
+
+	for _, target := range input.HttpxRunnerConfig.Targets {
+		hostname, err := urlhandler.ExtractHostname(target)
+		if err != nil {
+			sharedTargets = append(sharedTargets, target)
+			continue
+		}
+		auth, hasAuth := input.TargetCredentials.ForURLString(target)
+		timeout, hasTimeout := input.TargetTimeouts.ForURLString(target)
+		if input.RequestHeaders.OverridesForHost(hostname) == nil && !hasAuth && !hasTimeout {
+			sharedTargets = append(sharedTargets, target)
+			continue
+		}
+
+		// Hostnames are case-insensitive, so one host is probed in one batch however its URLs spell it
+		group := probeGroup{hostname: strings.ToLower(hostname), auth: auth, timeout: timeout}
+		if _, seen := groupTargets[group]; !seen {
+			groupOrder = append(groupOrder, group)
+		}
+		groupTargets[group] = append(groupTargets[group], target)
+	}
+
+	batches := make([]httpxBatch, 0, len(groupOrder)+1)
+
+	if len(sharedTargets) > 0 {
+		groupConfig := *input.HttpxRunnerConfig
+		groupConfig.Targets = sharedTargets
+		batches = append(batches, httpxBatch{config: groupConfig})
+	}
+
+	for _, group := range groupOrder {
+		groupConfig := *input.HttpxRunnerConfig
+		groupConfig.Targets = groupTargets[group]
+		groupConfig.CustomHeaders = make(map[string]string, len(input.HttpxRunnerConfig.CustomHeaders))
+		for key, value := range input.HttpxRunnerConfig.CustomHeaders {
+			groupConfig.CustomHeaders[key] = value
+		}
+		for key, value := range input.RequestHeaders.OverridesForHost(group.hostname) {
+			groupConfig.CustomHeaders[key] = value
+		}
+		if group.auth.Scheme != "" {
+			groupConfig.CustomHeaders["Authorization"] = group.auth.HeaderValue()
+		}
+		if group.timeout > 0 {
+			groupConfig.Timeout = int(group.timeout.Round(time.Second) / time.Second)
+		}
+		batches = append(batches, httpxBatch{group: group, config: groupConfig})
+	}
+
+	return batches
+}
+
+// processHTTPXResults maps the raw httpx results to httpxrunner.ProbeResult and assigns RootTargetURL and DiscoveredFrom
 // Handles cases where no probe result is found for a discovered URL
 func (he *HTTPXExecutor) processHTTPXResults(
 	runnerResults []httpxrunner.ProbeResult,
@@ -130,11 +268,12 @@ func (he *HTTPXExecutor) processHTTPXResults(
 	}
 
 	for _, urlString := range discoveredURLs {
-		var rootTargetForThisURL string
+		var rootTargetForThisURL, discoveredFrom string
 
 		// Use crawler instance to get root target if available
 		if he.crawlerInstance != nil {
 			rootTargetForThisURL = he.crawlerInstance.GetRootTargetForDiscoveredURL(urlString)
+			discoveredFrom = he.crawlerInstance.GetURLParent(urlString)
 		} else {
 			// Fallback to urlhandler logic
 			rootTargetForThisURL = urlhandler.GetRootTargetForURL(urlString, seedURLs)
@@ -142,14 +281,16 @@ func (he *HTTPXExecutor) processHTTPXResults(
 
 		if r, exists := probeResultMap[urlString]; exists {
 			r.RootTargetURL = rootTargetForThisURL
+			r.DiscoveredFrom = discoveredFrom
 			processedResults = append(processedResults, r)
 		} else {
 			// Create error entry for missing probe result
 			processedResults = append(processedResults, httpxrunner.ProbeResult{
-				InputURL:      urlString,
-				Error:         "No response from httpx probe",
-				Timestamp:     time.Now(),
-				RootTargetURL: rootTargetForThisURL,
+				InputURL:       urlString,
+				Error:          "No response from httpx probe",
+				Timestamp:      time.Now(),
+				RootTargetURL:  rootTargetForThisURL,
+				DiscoveredFrom: discoveredFrom,
 			})
 		}
 	}
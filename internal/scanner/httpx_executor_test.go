@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitHTTPXBatches(t *testing.T) {
+	auth, err := urlhandler.ParseTargetAuth("bearer:secret")
+	require.NoError(t, err)
+
+	runnerConfig := &httpxrunner.Config{
+		Targets: []string{
+			"https://www.example.com/",
+			"https://shop.example.com/cart",
+			"https://api.example.com/v1",
+			"https://www.example.com/about",
+			"https://SHOP.example.com/checkout",
+			"https://slow.example.com/",
+		},
+		CustomHeaders: map[string]string{"Accept-Language": "en-US", "X-Scanner": "monsterinc"},
+		Timeout:       10,
+	}
+	input := HTTPXExecutionInput{
+		HttpxRunnerConfig: runnerConfig,
+		RequestHeaders: config.RequestHeadersConfig{
+			PerHost: map[string]map[string]string{"Shop.Example.com": {"Accept-Language": "de-DE"}},
+		},
+		TargetCredentials: urlhandler.NewTargetCredentials([]urlhandler.Target{{URL: "https://api.example.com", Auth: &auth}}),
+		TargetTimeouts:    urlhandler.NewTargetTimeouts([]urlhandler.Target{{URL: "https://slow.example.com", Timeout: 30 * time.Second}}),
+	}
+
+	batches := splitHTTPXBatches(input)
+	require.Len(t, batches, 4)
+
+	shared := batches[0]
+	assert.Empty(t, shared.group.hostname)
+	assert.Equal(t, []string{"https://www.example.com/", "https://www.example.com/about"}, shared.config.Targets)
+	assert.Equal(t, runnerConfig.CustomHeaders, shared.config.CustomHeaders)
+
+	shop := batches[1]
+	assert.Equal(t, []string{"https://shop.example.com/cart", "https://SHOP.example.com/checkout"}, shop.config.Targets,
+		"hosts are grouped case-insensitively, keeping target order")
+	assert.Equal(t, map[string]string{"Accept-Language": "de-DE", "X-Scanner": "monsterinc"}, shop.config.CustomHeaders,
+		"per-host headers win over the httpx custom headers")
+
+	api := batches[2]
+	assert.Equal(t, []string{"https://api.example.com/v1"}, api.config.Targets)
+	assert.Equal(t, "Bearer secret", api.config.CustomHeaders["Authorization"])
+	assert.Equal(t, 10, api.config.Timeout)
+
+	slow := batches[3]
+	assert.Equal(t, []string{"https://slow.example.com/"}, slow.config.Targets)
+	assert.Equal(t, 30, slow.config.Timeout)
+	assert.Equal(t, runnerConfig.CustomHeaders, slow.config.CustomHeaders)
+
+	assert.Equal(t, map[string]string{"Accept-Language": "en-US", "X-Scanner": "monsterinc"}, runnerConfig.CustomHeaders,
+		"the source config's headers are not modified")
+	assert.Len(t, runnerConfig.Targets, 6)
+	assert.Equal(t, 10, runnerConfig.Timeout)
+}
+
+func TestConfigBuilder_BuildHTTPXHeaders(t *testing.T) {
+	globalConfig := config.NewDefaultGlobalConfig()
+	globalConfig.RequestHeaders = config.RequestHeadersConfig{
+		Default: map[string]string{"Accept-Language": "en-US", "X-Scanner": "monsterinc"},
+		PerHost: map[string]map[string]string{"shop.example.com": {"Accept-Language": "de-DE"}},
+	}
+	builder := NewConfigBuilder(globalConfig, zerolog.Nop())
+
+	headers := builder.buildHTTPXHeaders(map[string]string{"X-Scanner": "httpx"})
+	assert.Equal(t, map[string]string{"Accept-Language": "en-US", "X-Scanner": "httpx"}, headers,
+		"httpx custom headers win over the defaults, and per-host overrides are left to the per-host batches")
+	assert.Equal(t, "monsterinc", globalConfig.RequestHeaders.Default["X-Scanner"])
+}
@@ -3,6 +3,8 @@ package scanner
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
@@ -54,6 +56,8 @@ func (hm *HTTPXManager) needsRecreation(config *httpxrunner.Config, rootTargetUR
 		hm.lastConfig.RateLimit != config.RateLimit ||
 		hm.lastConfig.Retries != config.Retries ||
 		hm.lastConfig.FollowRedirects != config.FollowRedirects ||
+		!maps.Equal(hm.lastConfig.CustomHeaders, config.CustomHeaders) ||
+		!slices.Equal(hm.lastConfig.Targets, config.Targets) ||
 		hm.lastRootTarget != rootTargetURL {
 		return true
 	}
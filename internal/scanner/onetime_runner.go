@@ -0,0 +1,200 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/datastore"
+	"github.com/aleister1102/monsterinc/internal/events"
+	"github.com/aleister1102/monsterinc/internal/logger"
+	"github.com/rs/zerolog"
+)
+
+// OnetimeRunner runs a single scan in-process and returns its result. It never
+// installs signal handlers or exits the process, so it is safe to embed.
+type OnetimeRunner struct {
+	config        *config.GlobalConfig
+	logger        zerolog.Logger
+	scanner       *Scanner
+	eventSink     events.EventSink
+	scanSessionID string
+	targetSource  string
+	baseline      bool
+	credentials   urlhandler.TargetCredentials
+	tags          urlhandler.TargetTags
+	timeouts      urlhandler.TargetTimeouts
+}
+
+// NewOnetimeRunner creates a runner for the given configuration
+func NewOnetimeRunner(cfg *config.GlobalConfig, logger zerolog.Logger) *OnetimeRunner {
+	return &OnetimeRunner{
+		config:       cfg,
+		logger:       logger,
+		targetSource: "api",
+	}
+}
+
+// WithScanner reuses an existing scanner instead of creating one per run.
+// The caller then owns the scanner's lifecycle (Shutdown, event sink).
+func (r *OnetimeRunner) WithScanner(scanner *Scanner) *OnetimeRunner {
+	r.scanner = scanner
+	return r
+}
+
+// WithEventSink sets the sink for scan lifecycle events of a runner-owned scanner
+func (r *OnetimeRunner) WithEventSink(sink events.EventSink) *OnetimeRunner {
+	r.eventSink = sink
+	return r
+}
+
+// WithScanSessionID overrides the generated scan session ID
+func (r *OnetimeRunner) WithScanSessionID(scanSessionID string) *OnetimeRunner {
+	r.scanSessionID = scanSessionID
+	return r
+}
+
+// WithTargetSource sets the target source shown in summaries and reports
+func (r *OnetimeRunner) WithTargetSource(targetSource string) *OnetimeRunner {
+	r.targetSource = targetSource
+	return r
+}
+
+// WithBaseline records the scan as a baseline: results are stored for later diffs but no
+// change notifications or URL diff events are produced
+func (r *OnetimeRunner) WithBaseline(baseline bool) *OnetimeRunner {
+	r.baseline = baseline
+	return r
+}
+
+// WithTargetCredentials sets credentials for targets loaded elsewhere, e.g. from a target
+// file's companion credentials file. Inline "|auth=" annotations in Run's targets are added on top.
+func (r *OnetimeRunner) WithTargetCredentials(credentials urlhandler.TargetCredentials) *OnetimeRunner {
+	r.credentials = credentials
+	return r
+}
+
+// WithTargetTags sets tags for targets loaded elsewhere. Tags from inline "|tags=" annotations in Run's
+// targets take precedence.
+func (r *OnetimeRunner) WithTargetTags(tags urlhandler.TargetTags) *OnetimeRunner {
+	r.tags = tags
+	return r
+}
+
+// WithTargetTimeouts sets request timeouts for targets loaded elsewhere. Timeouts from inline "|timeout="
+// annotations in Run's targets take precedence.
+func (r *OnetimeRunner) WithTargetTimeouts(timeouts urlhandler.TargetTimeouts) *OnetimeRunner {
+	r.timeouts = timeouts
+	return r
+}
+
+// RunOnetime scans targets once with cfg and returns the result. Targets are
+// normalized, deduplicated and CIDR-expanded like a target file.
+func RunOnetime(ctx context.Context, cfg *config.GlobalConfig, targets []string) (*BatchScanResult, error) {
+	return NewOnetimeRunner(cfg, zerolog.Nop()).Run(ctx, targets)
+}
+
+// Run scans targets once. The returned result is non-nil whenever targets were
+// valid, so callers get a summary (status, errors, report paths) even on failure.
+func (r *OnetimeRunner) Run(ctx context.Context, targets []string) (*BatchScanResult, error) {
+	if r.config == nil {
+		return nil, errorwrapper.NewError("global config cannot be nil")
+	}
+
+	targetURLs, loaded, err := r.prepareTargets(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	scanSessionID := r.scanSessionID
+	if scanSessionID == "" {
+		scanSessionID = time.Now().Format("20060102-150405")
+	}
+	logger.SetScanTrace(scanSessionID)
+	defer logger.SetScanTrace("")
+
+	scannerInstance := r.scanner
+	if scannerInstance == nil {
+		scannerInstance, err = r.newScanner()
+		if err != nil {
+			return nil, err
+		}
+		defer scannerInstance.Shutdown()
+	}
+	scannerInstance.SetBaselineMode(r.baseline)
+	credentials := urlhandler.NewTargetCredentials(loaded)
+	credentials.Merge(r.credentials)
+	scannerInstance.SetTargetCredentials(credentials)
+	tags := urlhandler.NewTargetTags(loaded)
+	if tags.Len() == 0 {
+		tags = r.tags
+	}
+	scannerInstance.SetTargetTags(tags)
+	timeouts := urlhandler.NewTargetTimeouts(loaded)
+	if timeouts.Len() == 0 {
+		timeouts = r.timeouts
+	}
+	scannerInstance.SetTargetTimeouts(timeouts)
+
+	orchestrator := NewBatchWorkflowOrchestrator(r.config, scannerInstance, r.logger)
+	result, err := orchestrator.ExecuteLoadedTargets(ctx, r.config, targetURLs, scanSessionID, r.targetSource, "onetime")
+	if result == nil {
+		result = &BatchScanResult{SummaryData: r.failedSummary(scanSessionID, targetURLs, err)}
+	}
+	return result, err
+}
+
+// prepareTargets normalizes, deduplicates and expands the given targets, returning their URLs and the
+// loaded targets with their inline annotations
+func (r *OnetimeRunner) prepareTargets(targets []string) ([]string, []urlhandler.Target, error) {
+	targetManager := urlhandler.NewTargetManager(r.logger).WithExpansionConfig(r.config.TargetExpansion.ToTargetExpansionConfig())
+	loaded, err := targetManager.LoadTargetsFromReader(strings.NewReader(strings.Join(targets, "\n")))
+	if err != nil {
+		return nil, nil, errorwrapper.WrapError(err, "failed to prepare scan targets")
+	}
+	if len(loaded) == 0 {
+		return nil, nil, errorwrapper.NewError("no valid targets to scan")
+	}
+	return targetManager.GetTargetStrings(loaded), loaded, nil
+}
+
+// newScanner builds a scanner with its own Parquet reader and writer. The storage path is expanded
+// once for both, so a scan running past midnight reads and writes the same {date} directory.
+func (r *OnetimeRunner) newScanner() (*Scanner, error) {
+	storageConfig := r.config.StorageConfig
+	storageConfig.ParquetBasePath = config.ExpandPathTemplate(storageConfig.ParquetBasePath, "", time.Now())
+
+	pReader := datastore.NewParquetReader(&storageConfig, r.logger)
+	pWriter, err := datastore.NewParquetWriterBuilder(r.logger).
+		WithStorageConfig(&storageConfig).
+		WithExtractionConfig(&r.config.HttpxRunnerConfig).
+		Build()
+	if err != nil {
+		return nil, errorwrapper.WrapError(err, "could not initialize ParquetWriter")
+	}
+
+	scannerInstance := NewScanner(r.config, r.logger, pReader, pWriter)
+	if r.eventSink != nil {
+		scannerInstance.SetEventSink(r.eventSink)
+	}
+	return scannerInstance, nil
+}
+
+// failedSummary describes a run that produced no workflow result
+func (r *OnetimeRunner) failedSummary(scanSessionID string, targetURLs []string, err error) summary.ScanSummaryData {
+	summaryData := summary.GetDefaultScanSummaryData()
+	summaryData.ScanSessionID = scanSessionID
+	summaryData.TargetSource = r.targetSource
+	summaryData.ScanMode = "onetime"
+	summaryData.Targets = targetURLs
+	summaryData.TotalTargets = len(targetURLs)
+	summaryData.Status = string(summary.ScanStatusFailed)
+	if err != nil {
+		summaryData.ErrorMessages = []string{err.Error()}
+	}
+	return summaryData
+}
@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/datastore"
+	"github.com/aleister1102/monsterinc/internal/events"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnetime_RejectsInvalidInput(t *testing.T) {
+	_, err := RunOnetime(context.Background(), nil, []string{"https://example.com"})
+	assert.Error(t, err)
+
+	cfg := config.NewDefaultGlobalConfig()
+	cfg.StorageConfig.ParquetBasePath = t.TempDir()
+
+	result, err := RunOnetime(context.Background(), cfg, []string{"", "not a url ::"})
+	require.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestOnetimeRunner_PrepareTargets(t *testing.T) {
+	cfg := config.NewDefaultGlobalConfig()
+	runner := NewOnetimeRunner(cfg, zerolog.Nop())
+
+	targets, loaded, err := runner.prepareTargets([]string{"https://example.com", "https://example.com", "https://example.com/a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com", "https://example.com/a"}, targets)
+	assert.Zero(t, urlhandler.NewTargetCredentials(loaded).Len())
+	assert.Zero(t, urlhandler.NewTargetTags(loaded).Len())
+	assert.Zero(t, urlhandler.NewTargetTimeouts(loaded).Len())
+
+	targets, loaded, err = runner.prepareTargets([]string{"https://api.example.com/v1|auth=bearer:secret|tags=prod,API|timeout=60"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://api.example.com/v1"}, targets)
+	auth, ok := urlhandler.NewTargetCredentials(loaded).ForURLString("https://api.example.com/v1/users")
+	require.True(t, ok)
+	assert.Equal(t, "Bearer secret", auth.HeaderValue())
+	assert.Equal(t, []string{"prod", "api"}, urlhandler.NewTargetTags(loaded).ForURLString("https://api.example.com/v1/users"))
+	timeout, ok := urlhandler.NewTargetTimeouts(loaded).ForURLString("https://api.example.com/v1/users")
+	require.True(t, ok)
+	assert.Equal(t, time.Minute, timeout)
+}
+
+func TestRunOnetime_DeadlineStillProbesAndReports(t *testing.T) {
+	tests := []struct {
+		name     string
+		slowPath string // The first request for this path outlives the deadline
+	}{
+		{name: "deadline during crawl", slowPath: "/"},
+		{name: "deadline during probing", slowPath: "/slow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var slowServed atomic.Bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == tt.slowPath && slowServed.CompareAndSwap(false, true) {
+					time.Sleep(time.Second)
+				}
+				w.Header().Set("Content-Type", "text/html")
+				_, _ = fmt.Fprint(w, `<html><body><a href="/slow">slow</a><a href="/fast">fast</a></body></html>`)
+			}))
+			defer server.Close()
+
+			cfg := config.NewDefaultGlobalConfig()
+			cfg.StorageConfig.ParquetBasePath = t.TempDir()
+			cfg.ReporterConfig.OutputDir = t.TempDir()
+			cfg.NotificationConfig = config.NotificationConfig{}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+
+			result, err := RunOnetime(ctx, cfg, []string{server.URL})
+			require.NoError(t, err)
+			require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded, "the deadline fired during the scan")
+			assert.Positive(t, result.SummaryData.ProbeStats.TotalProbed, "URLs found before the deadline are probed")
+			require.NotEmpty(t, result.ReportFilePaths, "and reported")
+			for _, path := range result.ReportFilePaths {
+				assert.FileExists(t, path)
+			}
+		})
+	}
+}
+
+// recordingSink keeps the events it receives
+type recordingSink struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (rs *recordingSink) Emit(ctx context.Context, event events.Event) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.events = append(rs.events, event)
+}
+
+func (rs *recordingSink) Close() error { return nil }
+
+// ofType returns the received events of eventType
+func (rs *recordingSink) ofType(eventType events.EventType) []events.Event {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	var matching []events.Event
+	for _, event := range rs.events {
+		if event.Type == eventType {
+			matching = append(matching, event)
+		}
+	}
+	return matching
+}
+
+func TestOnetimeRunner_BaselineStoresResultsWithoutDiffEvents(t *testing.T) {
+	var linkNewPage atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		links := `<a href="/known">known</a>`
+		if linkNewPage.Load() {
+			links += `<a href="/new">new</a>`
+		}
+		_, _ = fmt.Fprintf(w, `<html><body>%s</body></html>`, links)
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultGlobalConfig()
+	cfg.StorageConfig.ParquetBasePath = t.TempDir()
+	cfg.ReporterConfig.OutputDir = t.TempDir()
+	cfg.NotificationConfig = config.NotificationConfig{}
+
+	baselineSink := &recordingSink{}
+	result, err := NewOnetimeRunner(cfg, zerolog.Nop()).WithEventSink(baselineSink).WithBaseline(true).
+		Run(context.Background(), []string{server.URL})
+	require.NoError(t, err)
+	assert.True(t, result.SummaryData.Baseline, "the summary tells the notifier to skip the completion message")
+	assert.Empty(t, baselineSink.ofType(events.EventURLDiffDetected), "every URL is new, but a baseline reports no changes")
+
+	host, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	stored, _, err := datastore.NewParquetReader(&cfg.StorageConfig, zerolog.Nop()).FindAllProbeResultsForTarget(host.Hostname())
+	require.NoError(t, err)
+	var storedURLs []string
+	for _, probe := range stored {
+		storedURLs = append(storedURLs, probe.InputURL)
+	}
+	assert.Contains(t, storedURLs, server.URL+"/known", "baseline results are stored")
+
+	// The next normal scan diffs against the baseline and only reports what changed since
+	linkNewPage.Store(true)
+	sink := &recordingSink{}
+	result, err = NewOnetimeRunner(cfg, zerolog.Nop()).WithEventSink(sink).Run(context.Background(), []string{server.URL})
+	require.NoError(t, err)
+	assert.False(t, result.SummaryData.Baseline)
+	diffEvents := sink.ofType(events.EventURLDiffDetected)
+	require.Len(t, diffEvents, 1)
+	assert.Equal(t, 1, diffEvents[0].Payload["new"])
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/differ"
@@ -64,15 +65,24 @@ func (rg *ReportGenerator) GenerateReports(ctx context.Context, input *ReportGen
 		return nil, nil
 	}
 
-	reporter, err := rg.createHTMLReporter()
+	outputDir := rg.resolveOutputDir(input.ScanSessionID, time.Now())
+
+	reporter, err := rg.createHTMLReporter(outputDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize HTML reporter: %w", err)
 	}
 
-	baseReportPath := rg.buildBaseReportPath(input.ScanSessionID)
+	baseReportPath := rg.buildBaseReportPath(outputDir, input.ScanSessionID)
 
 	// Combine current scan results with old URLs from diff results
 	allProbeResults := rg.combineProbeResultsWithOldURLs(input.ProbeResults, input.URLDiffResults)
+	if rg.config.ChangedOnly {
+		allProbeResults = rg.dropUnchangedResults(allProbeResults)
+		if len(allProbeResults) == 0 {
+			rg.logger.Info().Str("session_id", input.ScanSessionID).Msg("No URL changed since the previous scan, skipping changed-only report.")
+			return nil, nil
+		}
+	}
 
 	// OPTIMIZATION: Direct pointer conversion to avoid intermediate allocation
 	probeResultsPtr := rg.convertToPointersOptimized(allProbeResults)
@@ -82,19 +92,38 @@ func (rg *ReportGenerator) GenerateReports(ctx context.Context, input *ReportGen
 		return nil, fmt.Errorf("failed to generate HTML report(s): %w", err)
 	}
 
+	if rg.config.CSVOutput && len(reportPaths) > 0 {
+		// The HTML report is still usable, so a failed export does not fail the scan
+		if csvPath, err := reporter.GenerateCSVReport(probeResultsPtr, baseReportPath); err == nil {
+			reportPaths = append(reportPaths, csvPath)
+		}
+	}
+
 	rg.logReportGeneration(input.ScanSessionID, reportPaths)
 	return reportPaths, nil
 }
 
-// createHTMLReporter creates and initializes a new HTML reporter
-func (rg *ReportGenerator) createHTMLReporter() (*reporter.HtmlReporter, error) {
-	return reporter.NewHtmlReporter(rg.config, rg.logger)
+// resolveOutputDir expands the {date}, {time} and {session} tokens of the configured output directory
+func (rg *ReportGenerator) resolveOutputDir(scanSessionID string, now time.Time) string {
+	outputDir := rg.config.OutputDir
+	if outputDir == "" {
+		outputDir = config.DefaultReporterOutputDir
+	}
+	return config.ExpandPathTemplate(outputDir, scanSessionID, now)
+}
+
+// createHTMLReporter creates and initializes a new HTML reporter writing to outputDir,
+// which the reporter creates along with its assets directory
+func (rg *ReportGenerator) createHTMLReporter(outputDir string) (*reporter.HtmlReporter, error) {
+	reporterConfig := *rg.config
+	reporterConfig.OutputDir = outputDir
+	return reporter.NewHtmlReporter(&reporterConfig, rg.logger)
 }
 
 // buildBaseReportPath creates base path for report file
-func (rg *ReportGenerator) buildBaseReportPath(scanSessionID string) string {
+func (rg *ReportGenerator) buildBaseReportPath(outputDir, scanSessionID string) string {
 	baseReportFilename := fmt.Sprintf("%s_scan_report.html", scanSessionID)
-	return filepath.Join(rg.config.OutputDir, baseReportFilename)
+	return filepath.Join(outputDir, baseReportFilename)
 }
 
 // convertToPointersOptimized converts ProbeResult slice to pointer slice efficiently
@@ -126,6 +155,21 @@ func (rg *ReportGenerator) logReportGeneration(scanSessionID string, reportPaths
 		Msg("HTML report(s) generated successfully")
 }
 
+// dropUnchangedResults removes the URLs found unchanged by the diff against the previous scan.
+// Without a previous scan no URL is "existing", so every result is kept.
+func (rg *ReportGenerator) dropUnchangedResults(probeResults []httpxrunner.ProbeResult) []httpxrunner.ProbeResult {
+	changed := make([]httpxrunner.ProbeResult, 0, len(probeResults))
+	for _, pr := range probeResults {
+		if pr.URLStatus != string(differ.StatusExisting) {
+			changed = append(changed, pr)
+		}
+	}
+	if omitted := len(probeResults) - len(changed); omitted > 0 {
+		rg.logger.Info().Int("omitted", omitted).Int("changed", len(changed)).Msg("Changed-only report: omitting unchanged URLs")
+	}
+	return changed
+}
+
 // combineProbeResultsWithOldURLs combines current scan results with old URLs from diff results
 func (rg *ReportGenerator) combineProbeResultsWithOldURLs(probeResults []httpxrunner.ProbeResult, urlDiffResults map[string]differ.URLDiffResult) []httpxrunner.ProbeResult {
 	if len(urlDiffResults) == 0 {
@@ -155,3 +199,29 @@ func (rg *ReportGenerator) combineProbeResultsWithOldURLs(probeResults []httpxru
 
 	return allProbeResults
 }
+
+// recordReportIndex adds the reports of a finished scan to the report browser's index
+func (bwo *BatchWorkflowOrchestrator) recordReportIndex(gCfg *config.GlobalConfig, result *BatchScanResult) {
+	if !gCfg.ReportBrowser.Enabled || len(result.ReportFilePaths) == 0 {
+		return
+	}
+
+	entry := reporter.NewReportIndexEntry(result.SummaryData, result.ReportFilePaths, time.Now())
+	if err := reporter.AppendReportIndex(gCfg.ReportBrowser.IndexFile, entry); err != nil {
+		// The reports themselves are unaffected, only the browser will not list them
+		bwo.logger.Warn().Err(err).Str("index_file", gCfg.ReportBrowser.IndexFile).Msg("Failed to add reports to the report browser index")
+	}
+
+	// Kept reports would otherwise pile up forever
+	pruned, err := reporter.PruneReportIndex(gCfg.ReportBrowser.IndexFile, gCfg.ReportBrowser.MaxAge(), time.Now())
+	if err != nil {
+		bwo.logger.Warn().Err(err).Str("index_file", gCfg.ReportBrowser.IndexFile).Msg("Failed to remove expired reports from the report browser index")
+	}
+	if pruned.RemovedEntries > 0 {
+		bwo.logger.Info().
+			Int("entries", pruned.RemovedEntries).
+			Int("files", pruned.DeletedFiles).
+			Int("max_age_days", gCfg.ReportBrowser.MaxAgeDays).
+			Msg("Removed expired reports from the report browser index")
+	}
+}
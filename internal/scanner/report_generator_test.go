@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/differ"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func changedOnlyReporterConfig(t *testing.T) *config.ReporterConfig {
+	cfg := config.NewDefaultReporterConfig()
+	cfg.OutputDir = t.TempDir()
+	cfg.ChangedOnly = true
+	return &cfg
+}
+
+func TestReportGenerator_ChangedOnlyOmitsExistingURLs(t *testing.T) {
+	cfg := changedOnlyReporterConfig(t)
+	cfg.CSVOutput = true
+	generator := NewReportGenerator(cfg, zerolog.Nop())
+
+	probeResults := []httpxrunner.ProbeResult{
+		{InputURL: "https://example.com/new", StatusCode: 200, URLStatus: string(differ.StatusNew)},
+		{InputURL: "https://example.com/same", StatusCode: 200, URLStatus: string(differ.StatusExisting)},
+	}
+	diffResults := map[string]differ.URLDiffResult{
+		"https://example.com": {Results: []differ.DiffedURL{
+			{ProbeResult: httpxrunner.ProbeResult{InputURL: "https://example.com/gone", URLStatus: string(differ.StatusOld)}},
+		}},
+	}
+
+	paths, err := generator.GenerateReports(context.Background(), NewReportGenerationInputWithDiff(probeResults, diffResults, "session"))
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+
+	csv, err := os.ReadFile(filepath.Join(cfg.OutputDir, "session_scan_report.csv"))
+	require.NoError(t, err)
+	assert.Contains(t, string(csv), "https://example.com/new")
+	assert.Contains(t, string(csv), "https://example.com/gone")
+	assert.NotContains(t, string(csv), "https://example.com/same")
+}
+
+func TestReportGenerator_ChangedOnlySkipsReportWithoutChanges(t *testing.T) {
+	generator := NewReportGenerator(changedOnlyReporterConfig(t), zerolog.Nop())
+
+	probeResults := []httpxrunner.ProbeResult{
+		{InputURL: "https://example.com/same", StatusCode: 200, URLStatus: string(differ.StatusExisting)},
+	}
+
+	paths, err := generator.GenerateReports(context.Background(), NewReportGenerationInput(probeResults, "session"))
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
@@ -0,0 +1,44 @@
+package scanner
+
+import (
+	"net/url"
+	"strings"
+)
+
+// buildRootProbeURLs returns the root URL of every distinct seed host followed by the
+// configured common paths on that host, preserving seed order
+func buildRootProbeURLs(seedURLs []string, commonPaths []string) []string {
+	seen := make(map[string]struct{})
+	var probeURLs []string
+
+	add := func(rawURL string) {
+		if _, exists := seen[rawURL]; exists {
+			return
+		}
+		seen[rawURL] = struct{}{}
+		probeURLs = append(probeURLs, rawURL)
+	}
+
+	for _, seed := range seedURLs {
+		parsed, err := url.Parse(strings.TrimSpace(seed))
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			continue
+		}
+
+		root := parsed.Scheme + "://" + parsed.Host
+		add(root + "/")
+
+		for _, path := range commonPaths {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+			add(root + path)
+		}
+	}
+
+	return probeURLs
+}
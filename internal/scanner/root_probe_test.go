@@ -0,0 +1,23 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildRootProbeURLs(t *testing.T) {
+	urls := buildRootProbeURLs(
+		[]string{"https://example.com/deep/page?q=1", "https://example.com/other", "http://api.example.com:8080", "not a url"},
+		[]string{"/robots.txt", "login", " "},
+	)
+
+	assert.Equal(t, []string{
+		"https://example.com/",
+		"https://example.com/robots.txt",
+		"https://example.com/login",
+		"http://api.example.com:8080/",
+		"http://api.example.com:8080/robots.txt",
+		"http://api.example.com:8080/login",
+	}, urls)
+}
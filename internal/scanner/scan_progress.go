@@ -0,0 +1,308 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+)
+
+const (
+	scanProgressKey contextKey = "scan_progress"
+
+	// progressBarWidth is the number of cells of the terminal progress bar
+	progressBarWidth = 30
+	// progressBarRefresh is how often the terminal progress bar is redrawn
+	progressBarRefresh = time.Second
+)
+
+// Scan stages reported by ScanProgress
+const (
+	ProgressStageCrawling  = "crawling"
+	ProgressStageProbing   = "probing"
+	ProgressStageDiffing   = "diffing"
+	ProgressStageReporting = "reporting"
+)
+
+// ScanProgress tracks how far a scan has got: batches and targets completed, URLs crawled and probed.
+// It logs a progress line every progress.interval_secs and each time another progress.percent_step of the
+// targets is done, with an ETA from the throughput so far, and can redraw a bar on a terminal.
+// Progress advances when a batch completes; crawl counts of concurrent batches are approximate.
+// Its methods are safe for concurrent use and do nothing on a nil receiver.
+type ScanProgress struct {
+	cfg    config.ProgressConfig
+	logger zerolog.Logger
+	bar    io.Writer // Terminal the bar is drawn on; nil when disabled
+	now    func() time.Time
+
+	mu             sync.Mutex
+	startedAt      time.Time
+	totalTargets   int
+	totalBatches   int
+	batchesStarted int
+	batchesDone    int
+	targetsDone    int
+	stage          string
+	crawled        int // URLs discovered by finished crawls
+	crawling       int // URLs discovered so far by the running crawl
+	probed         int
+	loggedStep     int // Highest percent step already logged
+}
+
+// ProgressSnapshot is the state of a scan at one point in time
+type ProgressSnapshot struct {
+	Stage          string
+	TotalTargets   int
+	TargetsDone    int
+	TotalBatches   int
+	BatchesStarted int
+	BatchesDone    int
+	Crawled        int
+	Probed         int
+	Percent        float64
+	Elapsed        time.Duration
+	ETA            time.Duration // Zero until the first batch completes
+}
+
+// NewScanProgress creates a tracker for a scan of totalTargets targets split into totalBatches batches
+func NewScanProgress(cfg config.ProgressConfig, totalTargets, totalBatches int, logger zerolog.Logger) *ScanProgress {
+	p := &ScanProgress{
+		cfg:          cfg,
+		logger:       logger.With().Str("module", "ScanProgress").Logger(),
+		now:          time.Now,
+		totalTargets: totalTargets,
+		totalBatches: max(totalBatches, 1),
+	}
+	if cfg.Bar {
+		p.bar = terminalWriter(os.Stderr)
+	}
+	p.startedAt = p.now()
+	return p
+}
+
+// Start reports progress in the background until the returned function is called, which logs a final line
+func (p *ScanProgress) Start(ctx context.Context) (stop func()) {
+	if p == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.run(ctx, done)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+			p.drawBar()
+			if p.bar != nil {
+				_, _ = fmt.Fprintln(p.bar)
+			}
+			p.logSnapshot("Scan progress: finished")
+		})
+	}
+}
+
+// run logs on the interval and redraws the bar until done is closed or ctx is cancelled
+func (p *ScanProgress) run(ctx context.Context, done <-chan struct{}) {
+	var logTick, barTick <-chan time.Time
+	if p.cfg.IntervalSecs > 0 {
+		ticker := time.NewTicker(time.Duration(p.cfg.IntervalSecs) * time.Second)
+		defer ticker.Stop()
+		logTick = ticker.C
+	}
+	if p.bar != nil {
+		ticker := time.NewTicker(progressBarRefresh)
+		defer ticker.Stop()
+		barTick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-logTick:
+			p.logSnapshot("Scan progress")
+		case <-barTick:
+			p.drawBar()
+		}
+	}
+}
+
+// BeginBatch records that a batch has started
+func (p *ScanProgress) BeginBatch() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.batchesStarted++
+}
+
+// SetStage records the step the scan is in
+func (p *ScanProgress) SetStage(stage string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stage = stage
+}
+
+// SetCrawling records how many URLs the running crawl has discovered so far
+func (p *ScanProgress) SetCrawling(discovered int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.crawling = discovered
+}
+
+// CrawlDone adds the URLs discovered by a finished crawl
+func (p *ScanProgress) CrawlDone(discovered int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.crawled += discovered
+	p.crawling = 0
+}
+
+// AddProbed adds probe results
+func (p *ScanProgress) AddProbed(count int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probed += count
+}
+
+// CompleteBatch records a finished batch of targets, and logs a line when another percent step is crossed
+func (p *ScanProgress) CompleteBatch(targets int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.batchesDone++
+	p.targetsDone = min(p.targetsDone+targets, p.totalTargets)
+	crossed := false
+	if p.cfg.PercentStep > 0 && p.totalTargets > 0 {
+		step := p.targetsDone * 100 / p.totalTargets / p.cfg.PercentStep
+		crossed = step > p.loggedStep
+		p.loggedStep = max(p.loggedStep, step)
+	}
+	p.mu.Unlock()
+
+	if crossed {
+		p.logSnapshot("Scan progress")
+	}
+}
+
+// Snapshot returns the current state of the scan
+func (p *ScanProgress) Snapshot() ProgressSnapshot {
+	if p == nil {
+		return ProgressSnapshot{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := ProgressSnapshot{
+		Stage:          p.stage,
+		TotalTargets:   p.totalTargets,
+		TargetsDone:    p.targetsDone,
+		TotalBatches:   p.totalBatches,
+		BatchesStarted: p.batchesStarted,
+		BatchesDone:    p.batchesDone,
+		Crawled:        p.crawled + p.crawling,
+		Probed:         p.probed,
+		Elapsed:        p.now().Sub(p.startedAt),
+	}
+	if p.totalTargets > 0 {
+		snapshot.Percent = float64(p.targetsDone) * 100 / float64(p.totalTargets)
+	}
+	if p.targetsDone > 0 && p.targetsDone < p.totalTargets {
+		remaining := p.totalTargets - p.targetsDone
+		snapshot.ETA = time.Duration(float64(snapshot.Elapsed) * float64(remaining) / float64(p.targetsDone))
+	}
+	return snapshot
+}
+
+// logSnapshot logs the current state of the scan
+func (p *ScanProgress) logSnapshot(msg string) {
+	snapshot := p.Snapshot()
+	event := p.logger.Info().
+		Str("stage", snapshot.Stage).
+		Str("percent", fmt.Sprintf("%.1f", snapshot.Percent)).
+		Int("targets_done", snapshot.TargetsDone).
+		Int("total_targets", snapshot.TotalTargets).
+		Int("batch", min(snapshot.BatchesStarted, snapshot.TotalBatches)).
+		Int("total_batches", snapshot.TotalBatches).
+		Int("crawled_urls", snapshot.Crawled).
+		Int("probed_urls", snapshot.Probed).
+		Dur("elapsed", snapshot.Elapsed.Round(time.Second))
+	if snapshot.ETA > 0 {
+		event = event.Dur("eta", snapshot.ETA.Round(time.Second))
+	}
+	event.Msg(msg)
+}
+
+// drawBar redraws the progress bar in place on the terminal
+func (p *ScanProgress) drawBar() {
+	if p.bar == nil {
+		return
+	}
+	_, _ = fmt.Fprint(p.bar, "\r\033[K"+formatProgressBar(p.Snapshot(), progressBarWidth))
+}
+
+// formatProgressBar renders a snapshot as one terminal line, e.g.
+// "[#########.....................]  30.0% batch 3/10 crawling | crawled 1200 probed 950 | ETA 14m0s"
+func formatProgressBar(snapshot ProgressSnapshot, width int) string {
+	filled := min(int(snapshot.Percent*float64(width)/100), width)
+	line := fmt.Sprintf("[%s%s] %5.1f%% batch %d/%d",
+		strings.Repeat("#", filled), strings.Repeat(".", width-filled),
+		snapshot.Percent, min(snapshot.BatchesStarted, snapshot.TotalBatches), snapshot.TotalBatches)
+	if snapshot.Stage != "" {
+		line += " " + snapshot.Stage
+	}
+	line += fmt.Sprintf(" | crawled %d probed %d", snapshot.Crawled, snapshot.Probed)
+	if snapshot.ETA > 0 {
+		line += " | ETA " + snapshot.ETA.Round(time.Second).String()
+	}
+	return line
+}
+
+// terminalWriter returns f when it is a terminal, or nil so no bar is drawn into files or pipes
+func terminalWriter(f *os.File) io.Writer {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return f
+}
+
+// withScanProgress attaches the scan's progress tracker to the scan context
+func withScanProgress(ctx context.Context, progress *ScanProgress) context.Context {
+	return context.WithValue(ctx, scanProgressKey, progress)
+}
+
+// scanProgressFromContext returns the scan's progress tracker, or nil when none is attached
+func scanProgressFromContext(ctx context.Context) *ScanProgress {
+	progress, _ := ctx.Value(scanProgressKey).(*ScanProgress)
+	return progress
+}
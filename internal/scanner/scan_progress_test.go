@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanProgress_SnapshotAndETA(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	progress := NewScanProgress(config.ProgressConfig{}, 100, 4, zerolog.Nop())
+	progress.now = func() time.Time { return now }
+	progress.startedAt = start
+
+	progress.BeginBatch()
+	progress.SetStage(ProgressStageCrawling)
+	progress.SetCrawling(40)
+	assert.Equal(t, 40, progress.Snapshot().Crawled, "the running crawl counts")
+	assert.Zero(t, progress.Snapshot().ETA, "no ETA before a batch completes")
+
+	progress.CrawlDone(50)
+	progress.AddProbed(45)
+	progress.CompleteBatch(25)
+	now = start.Add(10 * time.Minute)
+
+	snapshot := progress.Snapshot()
+	assert.Equal(t, 25.0, snapshot.Percent)
+	assert.Equal(t, 50, snapshot.Crawled)
+	assert.Equal(t, 45, snapshot.Probed)
+	assert.Equal(t, 30*time.Minute, snapshot.ETA, "75 targets left at 25 per 10 minutes")
+}
+
+func TestScanProgress_LogsPercentSteps(t *testing.T) {
+	var logs bytes.Buffer
+	progress := NewScanProgress(config.ProgressConfig{PercentStep: 25}, 100, 10, zerolog.New(&logs))
+
+	progress.CompleteBatch(10)
+	assert.Empty(t, logs.String(), "10% does not reach the first step")
+	progress.CompleteBatch(20)
+	assert.Equal(t, 1, strings.Count(logs.String(), "Scan progress"))
+	progress.CompleteBatch(10)
+	assert.Equal(t, 1, strings.Count(logs.String(), "Scan progress"), "40% is still in the same step")
+	progress.CompleteBatch(60)
+	assert.Equal(t, 2, strings.Count(logs.String(), "Scan progress"))
+
+	stop := progress.Start(context.Background())
+	stop()
+	assert.Contains(t, logs.String(), "Scan progress: finished")
+}
+
+func TestScanProgress_NilIsNoop(t *testing.T) {
+	var progress *ScanProgress
+	progress.BeginBatch()
+	progress.CompleteBatch(5)
+	progress.Start(context.Background())()
+	assert.Nil(t, scanProgressFromContext(context.Background()))
+}
+
+func TestFormatProgressBar(t *testing.T) {
+	line := formatProgressBar(ProgressSnapshot{
+		Stage:          ProgressStageProbing,
+		TotalBatches:   10,
+		BatchesStarted: 4,
+		Percent:        30,
+		Crawled:        1200,
+		Probed:         950,
+		ETA:            14 * time.Minute,
+	}, 10)
+	assert.Equal(t, "[###.......]  30.0% batch 4/10 probing | crawled 1200 probed 950 | ETA 14m0s", line)
+}
@@ -2,13 +2,17 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
 	"github.com/aleister1102/monsterinc/internal/config"
 	"github.com/aleister1102/monsterinc/internal/datastore"
 	"github.com/aleister1102/monsterinc/internal/differ"
+	"github.com/aleister1102/monsterinc/internal/events"
 	"github.com/aleister1102/monsterinc/internal/httpxrunner"
 
 	"github.com/rs/zerolog"
@@ -17,15 +21,23 @@ import (
 // Scanner handles the core logic of scanning operations
 // Focuses on coordinating crawler, httpx probing, and diff/storage operations
 type Scanner struct {
-	config          *config.GlobalConfig
-	logger          zerolog.Logger
-	parquetReader   *datastore.ParquetReader
-	parquetWriter   *datastore.ParquetWriter
-	configBuilder   *ConfigBuilder
-	crawlerExecutor *CrawlerExecutor
-	httpxExecutor   *HTTPXExecutor
-	diffProcessor   *DiffStorageProcessor
-	urlPreprocessor *URLPreprocessor
+	config            *config.GlobalConfig
+	logger            zerolog.Logger
+	parquetReader     *datastore.ParquetReader
+	parquetWriter     *datastore.ParquetWriter
+	configBuilder     *ConfigBuilder
+	crawlerExecutor   *CrawlerExecutor
+	httpxExecutor     *HTTPXExecutor
+	diffProcessor     *DiffStorageProcessor
+	urlPreprocessor   *URLPreprocessor
+	eventSink         events.EventSink
+	eventSinkMu       sync.RWMutex // Guards eventSink, which CloseEventSink swaps while workers may emit
+	baseline          bool
+	targetCredentials urlhandler.TargetCredentials
+	targetTags        urlhandler.TargetTags
+	targetTimeouts    urlhandler.TargetTimeouts
+	harExporter       *HARExporter
+	searchExporter    *SearchExporter
 
 	notificationHelper interface {
 		SendScanStartNotification(ctx context.Context, summary summary.ScanSummaryData)
@@ -42,22 +54,28 @@ func NewScanner(
 	pWriter *datastore.ParquetWriter,
 ) *Scanner {
 	scanner := &Scanner{
-		config:        globalConfig,
-		logger:        logger.With().Str("module", "Scanner").Logger(),
-		parquetReader: pReader,
-		parquetWriter: pWriter,
-		configBuilder: NewConfigBuilder(globalConfig, logger),
+		config:         globalConfig,
+		logger:         logger.With().Str("module", "Scanner").Logger(),
+		parquetReader:  pReader,
+		parquetWriter:  pWriter,
+		configBuilder:  NewConfigBuilder(globalConfig, logger),
+		eventSink:      events.NopSink{},
+		harExporter:    NewHARExporter(globalConfig.HARExport, logger),
+		searchExporter: NewSearchExporter(globalConfig.SearchExport, globalConfig.ProxyConfig, logger),
 	}
 
 	// Initialize executors
 	scanner.crawlerExecutor = NewCrawlerExecutor(logger)
 	scanner.httpxExecutor = NewHTTPXExecutor(logger)
+	scanner.httpxExecutor.SetInFlightLimiter(scanner.configBuilder.InFlightLimiter())
 
 	// Initialize diff processor with URL differ
 	if urlDiffer, err := differ.NewUrlDiffer(pReader, logger); err != nil {
 		logger.Warn().Err(err).Msg("Failed to initialize URL differ")
 	} else {
-		scanner.diffProcessor = NewDiffStorageProcessor(logger, pWriter, urlDiffer)
+		scanner.diffProcessor = NewDiffStorageProcessor(logger, pWriter, urlDiffer).
+			WithURLLifecycle(globalConfig.StorageConfig.URLLifecycle).
+			WithContentArchiver(datastore.NewContentArchiver(globalConfig.StorageConfig.ResponseBodies.Archive, logger))
 	}
 
 	// Initialize URL preprocessor
@@ -89,6 +107,99 @@ func (s *Scanner) SetNotificationHelper(notificationHelper interface {
 	s.notificationHelper = notificationHelper
 }
 
+// SetEventSink sets the sink that receives structured scan lifecycle events
+func (s *Scanner) SetEventSink(sink events.EventSink) {
+	if sink == nil {
+		sink = events.NopSink{}
+	}
+	s.eventSinkMu.Lock()
+	s.eventSink = sink
+	s.eventSinkMu.Unlock()
+}
+
+// SetBaselineMode makes scans record results without reporting changes: URL diff events are not emitted
+// and summaries are marked as baseline so the completion notification is suppressed
+func (s *Scanner) SetBaselineMode(enabled bool) {
+	s.baseline = enabled
+}
+
+// SetTargetCredentials sets the per-target credentials used by the crawler and httpx for the next scans
+func (s *Scanner) SetTargetCredentials(credentials urlhandler.TargetCredentials) {
+	s.targetCredentials = credentials
+}
+
+// SetTargetTags sets the per-target tags copied onto the probe results of the next scans
+func (s *Scanner) SetTargetTags(tags urlhandler.TargetTags) {
+	s.targetTags = tags
+}
+
+// SetTargetTimeouts sets the per-target request timeouts used by the crawler and httpx for the next scans
+func (s *Scanner) SetTargetTimeouts(timeouts urlhandler.TargetTimeouts) {
+	s.targetTimeouts = timeouts
+}
+
+// tagProbeResults copies the tags of the target each probed URL belongs to onto its result,
+// falling back to the root target for URLs discovered on other hosts
+func (s *Scanner) tagProbeResults(probeResults []httpxrunner.ProbeResult) {
+	if s.targetTags.Len() == 0 {
+		return
+	}
+	for i := range probeResults {
+		tags := s.targetTags.ForURLString(probeResults[i].InputURL)
+		if tags == nil && probeResults[i].RootTargetURL != "" {
+			tags = s.targetTags.ForURLString(probeResults[i].RootTargetURL)
+		}
+		probeResults[i].Tags = tags
+	}
+}
+
+// CloseEventSink flushes and closes the event sink
+func (s *Scanner) CloseEventSink() {
+	s.eventSinkMu.Lock()
+	sink := s.eventSink
+	s.eventSink = events.NopSink{}
+	s.eventSinkMu.Unlock()
+
+	if sink == nil {
+		return
+	}
+	if err := sink.Close(); err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to close event sink")
+	}
+}
+
+// emitEvent forwards an event to the configured sink; delivery never affects the scan
+func (s *Scanner) emitEvent(ctx context.Context, event events.Event) {
+	s.eventSinkMu.RLock()
+	sink := s.eventSink
+	s.eventSinkMu.RUnlock()
+
+	if sink == nil {
+		return
+	}
+	sink.Emit(ctx, event)
+}
+
+// emitURLDiffEvents emits a url_diff_detected event for each root target with new or old URLs
+func (s *Scanner) emitURLDiffEvents(ctx context.Context, scanSessionID string, urlDiffResults map[string]differ.URLDiffResult) {
+	if s.baseline {
+		return
+	}
+
+	for rootTarget, diffResult := range urlDiffResults {
+		if diffResult.New == 0 && diffResult.Old == 0 {
+			continue
+		}
+
+		s.emitEvent(ctx, events.NewEvent(events.EventURLDiffDetected, scanSessionID).WithPayload(map[string]interface{}{
+			"root_target_url": rootTarget,
+			"new":             diffResult.New,
+			"old":             diffResult.Old,
+			"existing":        diffResult.Existing,
+		}))
+	}
+}
+
 // ResetCrawler shuts down the crawler executor to clean up its state.
 // This is intended to be called between independent scan cycles.
 func (s *Scanner) ResetCrawler() {
@@ -111,6 +222,23 @@ func (s *Scanner) UpdateLogger(newLogger zerolog.Logger) {
 	// They use the logger passed to them during execution
 }
 
+// shouldSendInterruptNotification reports whether the workflow itself should announce a cancelled context.
+// Batched runs and scans stopped by a deadline are finalized and notified by their caller instead.
+func (s *Scanner) shouldSendInterruptNotification(ctx context.Context) bool {
+	return s.notificationHelper != nil &&
+		ctx.Value(disableNotificationsKey) == nil &&
+		!errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// continuePastDeadline returns a context that ignores a reached scan deadline, so the URLs crawled before it
+// are still probed, diffed and reported. A scan cancelled by its caller keeps its cancelled context.
+func continuePastDeadline(ctx context.Context) context.Context {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return context.WithoutCancel(ctx)
+	}
+	return ctx
+}
+
 // ExecuteSingleScanWorkflowWithReporting performs complete scan workflow with reporting
 func (s *Scanner) ExecuteSingleScanWorkflowWithReporting(
 	ctx context.Context,
@@ -133,9 +261,10 @@ func (s *Scanner) ExecuteSingleScanWorkflowWithReporting(
 	// Generate HTML reports if we have results
 	var reportFilePaths []string
 	if len(probeResults) > 0 {
+		scanProgressFromContext(ctx).SetStage(ProgressStageReporting)
 		reportGenerator := NewReportGenerator(&gCfg.ReporterConfig, s.logger)
 		reportInput := NewReportGenerationInputWithDiff(probeResults, urlDiffResults, scanSessionID)
-		reportPaths, reportErr := reportGenerator.GenerateReports(ctx, reportInput)
+		reportPaths, reportErr := reportGenerator.GenerateReports(continuePastDeadline(ctx), reportInput)
 		if reportErr != nil {
 			s.logger.Warn().Err(reportErr).Msg("Failed to generate reports")
 		} else {
@@ -196,6 +325,8 @@ func (s *Scanner) ExecuteScanWorkflow(
 	scanSessionID string,
 ) ([]httpxrunner.ProbeResult, map[string]differ.URLDiffResult, error) {
 	startTime := time.Now()
+	s.harExporter.Begin()
+	defer s.harExporter.Write(scanSessionID)
 	// Note: Scan start notification is sent from the main entry point (main.go or scheduler)
 	// to avoid duplicate notifications when this workflow is called from different contexts
 
@@ -251,6 +382,9 @@ func (s *Scanner) ExecuteScanWorkflow(
 
 		return nil, nil, fmt.Errorf("failed to build crawler config: %w", err)
 	}
+	crawlerConfig.TargetCredentials = s.targetCredentials
+	crawlerConfig.TargetTimeouts = s.targetTimeouts
+	crawlerConfig.HARRecorder = s.harExporter.Recorder()
 
 	crawlerInput := CrawlerExecutionInput{
 		Context:              ctx,
@@ -261,7 +395,7 @@ func (s *Scanner) ExecuteScanWorkflow(
 
 	// Check for context cancellation before crawler execution
 	if ctx.Err() != nil {
-		if s.notificationHelper != nil && ctx.Value(disableNotificationsKey) == nil {
+		if s.shouldSendInterruptNotification(ctx) {
 			interruptSummary := summary.ScanSummaryData{
 				ScanSessionID: scanSessionID,
 				ScanMode:      "scan",
@@ -277,7 +411,16 @@ func (s *Scanner) ExecuteScanWorkflow(
 		return nil, nil, ctx.Err()
 	}
 
+	progress := scanProgressFromContext(ctx)
+	progress.SetStage(ProgressStageCrawling)
 	crawlerResult := s.crawlerExecutor.Execute(crawlerInput)
+	progress.CrawlDone(len(crawlerResult.DiscoveredURLs))
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && crawlerResult.Error == nil {
+		s.logger.Warn().
+			Int("discovered_urls", len(crawlerResult.DiscoveredURLs)).
+			Msg("Scan deadline reached during crawl, probing the URLs crawled so far")
+		ctx = continuePastDeadline(ctx)
+	}
 	if crawlerResult.Error != nil {
 		// Only send error notification if not in batch mode
 		if s.notificationHelper != nil && ctx.Value(disableNotificationsKey) == nil {
@@ -300,8 +443,27 @@ func (s *Scanner) ExecuteScanWorkflow(
 	// Set crawler instance for HTTPX executor to use for root target tracking
 	s.httpxExecutor.SetCrawlerInstance(crawlerResult.CrawlerInstance)
 
+	// Skip probing URLs already probed by an earlier batch of the same scan
+	dedupScope := batchDedupScopeFromContext(ctx)
+	if dedupScope != nil {
+		unseenURLs, earlierResults, skipped := dedupScope.deduplicator.FilterUnseen(crawlerResult.DiscoveredURLs)
+		dedupScope.skipped = skipped
+		dedupScope.earlierResults = earlierResults
+		if skipped > 0 {
+			s.logger.Info().
+				Int("discovered_urls", len(crawlerResult.DiscoveredURLs)).
+				Int("duplicates_skipped", skipped).
+				Int("remaining_urls", len(unseenURLs)).
+				Msg("Skipped URLs already probed in earlier batches")
+		}
+		crawlerResult.DiscoveredURLs = unseenURLs
+	}
+
 	// Step 2: Execute HTTPX probing
-	httpxConfig := s.configBuilder.BuildHTTPXConfig(crawlerResult.DiscoveredURLs)
+	httpxConfig, err := s.configBuilder.BuildHTTPXConfig(crawlerResult.DiscoveredURLs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build httpx config: %w", err)
+	}
 	httpxInput := HTTPXExecutionInput{
 		Context:              ctx,
 		DiscoveredURLs:       crawlerResult.DiscoveredURLs,
@@ -309,11 +471,14 @@ func (s *Scanner) ExecuteScanWorkflow(
 		PrimaryRootTargetURL: primaryRootTargetURL,
 		ScanSessionID:        scanSessionID,
 		HttpxRunnerConfig:    httpxConfig,
+		RequestHeaders:       s.config.RequestHeaders,
+		TargetCredentials:    s.targetCredentials,
+		TargetTimeouts:       s.targetTimeouts,
 	}
 
 	// Check for context cancellation before HTTPX execution
 	if ctx.Err() != nil {
-		if s.notificationHelper != nil && ctx.Value(disableNotificationsKey) == nil {
+		if s.shouldSendInterruptNotification(ctx) {
 			interruptSummary := summary.ScanSummaryData{
 				ScanSessionID: scanSessionID,
 				ScanMode:      "scan",
@@ -329,7 +494,16 @@ func (s *Scanner) ExecuteScanWorkflow(
 		return nil, nil, ctx.Err()
 	}
 
+	progress.SetStage(ProgressStageProbing)
 	httpxResult := s.httpxExecutor.Execute(httpxInput)
+	progress.AddProbed(len(httpxResult.ProbeResults))
+	if errors.Is(httpxResult.Error, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		s.logger.Warn().
+			Int("probe_results", len(httpxResult.ProbeResults)).
+			Msg("Scan deadline reached during probing, keeping the results probed so far")
+		httpxResult.Error = nil
+		ctx = continuePastDeadline(ctx)
+	}
 	if httpxResult.Error != nil {
 		// Only send error notification if not in batch mode
 		if s.notificationHelper != nil && ctx.Value(disableNotificationsKey) == nil {
@@ -349,12 +523,26 @@ func (s *Scanner) ExecuteScanWorkflow(
 		return nil, nil, fmt.Errorf("HTTPX execution failed: %w", httpxResult.Error)
 	}
 
+	s.harExporter.RecordProbeResults(httpxResult.ProbeResults)
+	s.tagProbeResults(httpxResult.ProbeResults)
+
 	// Step 3: Process diffing and storage
 	var urlDiffResults map[string]differ.URLDiffResult
 	if s.diffProcessor != nil {
+		progress.SetStage(ProgressStageDiffing)
+		// Each host file is rewritten from this batch's results, so URLs an earlier batch probed are
+		// stored again; they are dropped from the batch results afterwards to be reported only once
+		probedCount := len(httpxResult.ProbeResults)
+		storeResults := httpxResult.ProbeResults
+		if dedupScope != nil {
+			dedupScope.deduplicator.RecordProbed(httpxResult.ProbeResults)
+			if len(dedupScope.earlierResults) > 0 {
+				storeResults = append(append(make([]httpxrunner.ProbeResult, 0, probedCount+len(dedupScope.earlierResults)), httpxResult.ProbeResults...), dedupScope.earlierResults...)
+			}
+		}
 		diffInput := ProcessDiffingAndStorageInput{
 			Ctx:                     ctx,
-			CurrentScanProbeResults: httpxResult.ProbeResults,
+			CurrentScanProbeResults: storeResults,
 			SeedURLs:                processedSeedURLs,
 			PrimaryRootTargetURL:    primaryRootTargetURL,
 			ScanSessionID:           scanSessionID,
@@ -365,11 +553,15 @@ func (s *Scanner) ExecuteScanWorkflow(
 			s.logger.Warn().Err(err).Msg("Diffing and storage failed, continuing with results")
 		} else {
 			urlDiffResults = diffOutput.URLDiffResults
-			httpxResult.ProbeResults = diffOutput.UpdatedScanProbeResults
+			httpxResult.ProbeResults = diffOutput.UpdatedScanProbeResults[:probedCount]
+			s.emitURLDiffEvents(ctx, scanSessionID, urlDiffResults)
 		}
 	}
 
-	// Step 4: Workflow completed
+	// Step 4: Index the results, with their diff status, for teams querying scans from a search cluster
+	s.searchExporter.Export(ctx, scanSessionID, httpxResult.ProbeResults)
+
+	// Step 5: Workflow completed
 	// NOTE: Notification is sent from the caller level (main.go or scheduler)
 	// to avoid duplicate notifications and to include report file paths
 
@@ -390,5 +582,7 @@ func (s *Scanner) Shutdown() {
 		s.httpxExecutor.Shutdown()
 	}
 
+	s.CloseEventSink()
+
 	s.logger.Info().Msg("Scanner shutdown complete")
 }
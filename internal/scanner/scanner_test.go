@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aleister1102/monsterinc/internal/events"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingSink counts the events it receives
+type countingSink struct {
+	closed  atomic.Bool
+	emitted atomic.Int64
+}
+
+func (cs *countingSink) Emit(ctx context.Context, event events.Event) {
+	cs.emitted.Add(1)
+}
+
+func (cs *countingSink) Close() error {
+	cs.closed.Store(true)
+	return nil
+}
+
+func TestScanner_CloseEventSinkWhileEmitting(t *testing.T) {
+	s := &Scanner{logger: zerolog.Nop()}
+	sink := &countingSink{}
+	s.SetEventSink(sink)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				s.emitEvent(context.Background(), events.NewEvent(events.EventBatchCompleted, "session"))
+			}
+		}()
+	}
+	s.CloseEventSink()
+	wg.Wait()
+
+	assert.True(t, sink.closed.Load())
+	emitted := sink.emitted.Load()
+	s.emitEvent(context.Background(), events.NewEvent(events.EventScanCompleted, "session"))
+	assert.Equal(t, emitted, sink.emitted.Load(), "events after CloseEventSink go to the no-op sink")
+}
@@ -0,0 +1,298 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+)
+
+// searchExportBackoff is the wait before the first retry of documents the cluster pushed back on; it doubles per retry
+const searchExportBackoff = time.Second
+
+// searchExportMaxWait caps the wait before one retry, whether from the backoff or the cluster's Retry-After,
+// so a misbehaving cluster cannot hold the scan workflow for longer
+const searchExportMaxWait = time.Minute
+
+// SearchExporter bulk-indexes the probe results of each scan workflow into Elasticsearch or OpenSearch.
+// A nil *SearchExporter is valid and exports nothing.
+type SearchExporter struct {
+	config  config.SearchExportConfig
+	client  *httpclient.HTTPClient
+	backoff time.Duration
+	maxWait time.Duration
+	logger  zerolog.Logger
+}
+
+// NewSearchExporter creates a SearchExporter reaching the cluster through proxyConfig, or returns nil
+// when the export is disabled or its HTTP client cannot be created
+func NewSearchExporter(cfg config.SearchExportConfig, proxyConfig httpclient.ProxyConfig, logger zerolog.Logger) *SearchExporter {
+	if !cfg.Enabled || cfg.URL == "" {
+		return nil
+	}
+	exportLogger := logger.With().Str("module", "SearchExporter").Logger()
+
+	if cfg.Index == "" {
+		cfg.Index = config.DefaultSearchExportIndex
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = config.DefaultSearchExportBatchSize
+	}
+	if cfg.TimeoutSecs <= 0 {
+		cfg.TimeoutSecs = config.DefaultSearchExportTimeoutSecs
+	}
+	cfg.MaxRetries = max(cfg.MaxRetries, 0)
+
+	client, err := httpclient.NewHTTPClientFactory(exportLogger).
+		WithProxyConfig(proxyConfig).
+		CreateBasicClient(time.Duration(cfg.TimeoutSecs) * time.Second)
+	if err != nil {
+		exportLogger.Error().Err(err).Msg("Failed to create HTTP client for search export, export disabled")
+		return nil
+	}
+
+	return &SearchExporter{
+		config:  cfg,
+		client:  client,
+		backoff: searchExportBackoff,
+		maxWait: searchExportMaxWait,
+		logger:  exportLogger,
+	}
+}
+
+// searchDocument is one probe result ready for the bulk API
+type searchDocument struct {
+	id     string
+	source []byte
+}
+
+// bulkOutcome is the cluster's answer to one bulk request
+type bulkOutcome struct {
+	indexed      int
+	retry        []searchDocument // Documents to send again: rejected with 429, or the whole request when it was pushed back
+	retryAfter   time.Duration    // Wait requested by the cluster's Retry-After header
+	failed       int              // Documents rejected for good, e.g. mapping errors
+	firstFailure string
+}
+
+// Export indexes probeResults in batches and returns how many documents were indexed. Failures are
+// logged, not returned: the scan does not depend on the export. A batch the cluster keeps rejecting
+// stops the export, since the remaining batches would only add load.
+func (se *SearchExporter) Export(ctx context.Context, scanSessionID string, probeResults []httpxrunner.ProbeResult) int {
+	if se == nil || len(probeResults) == 0 {
+		return 0
+	}
+
+	now := time.Now()
+	index := strings.ToLower(config.ExpandPathTemplate(se.config.Index, scanSessionID, now))
+
+	indexed := 0
+	for start := 0; start < len(probeResults); start += se.config.BatchSize {
+		end := min(start+se.config.BatchSize, len(probeResults))
+		docs := make([]searchDocument, 0, end-start)
+		for _, pr := range probeResults[start:end] {
+			doc, err := newSearchDocument(scanSessionID, now, pr)
+			if err != nil {
+				se.logger.Warn().Err(err).Str("url", pr.InputURL).Msg("Failed to encode probe result for search export")
+				continue
+			}
+			docs = append(docs, doc)
+		}
+
+		batchIndexed, err := se.indexBatch(ctx, index, docs)
+		indexed += batchIndexed
+		if err != nil {
+			se.logger.Error().Err(err).
+				Str("index", index).
+				Int("indexed", indexed).
+				Int("skipped", len(probeResults)-end).
+				Msg("Search export stopped")
+			return indexed
+		}
+	}
+
+	se.logger.Info().
+		Str("session_id", scanSessionID).
+		Str("index", index).
+		Int("indexed", indexed).
+		Int("probe_results", len(probeResults)).
+		Msg("Probe results indexed")
+	return indexed
+}
+
+// newSearchDocument encodes a probe result with its scan session. The document ID depends only on the
+// session and URL, so a retried or re-run export overwrites documents instead of duplicating them.
+func newSearchDocument(scanSessionID string, indexedAt time.Time, pr httpxrunner.ProbeResult) (searchDocument, error) {
+	// Response bodies stay in the local store; indexing them would bloat the cluster
+	pr.Body = ""
+
+	source, err := json.Marshal(struct {
+		httpxrunner.ProbeResult
+		ScanSessionID string    `json:"scan_session_id"`
+		IndexedAt     time.Time `json:"@timestamp"`
+	}{pr, scanSessionID, indexedAt})
+	if err != nil {
+		return searchDocument{}, err
+	}
+
+	sum := sha256.Sum256([]byte(scanSessionID + "\x00" + pr.Method + " " + pr.InputURL))
+	return searchDocument{id: hex.EncodeToString(sum[:16]), source: source}, nil
+}
+
+// indexBatch sends docs, retrying the documents the cluster pushes back on with exponential backoff.
+// Each wait is capped at maxWait and ends early when ctx is done.
+func (se *SearchExporter) indexBatch(ctx context.Context, index string, docs []searchDocument) (int, error) {
+	indexed := 0
+	pending := docs
+	for attempt := 0; len(pending) > 0; attempt++ {
+		outcome, err := se.sendBulk(ctx, index, pending)
+		if err != nil {
+			return indexed, err
+		}
+
+		indexed += outcome.indexed
+		if outcome.failed > 0 {
+			se.logger.Warn().
+				Str("index", index).
+				Int("failed", outcome.failed).
+				Str("first_error", outcome.firstFailure).
+				Msg("Cluster rejected documents")
+		}
+
+		pending = outcome.retry
+		if len(pending) == 0 {
+			break
+		}
+		if attempt >= se.config.MaxRetries {
+			return indexed, errorwrapper.NewError("cluster still pushing back on %d documents after %d retries", len(pending), attempt)
+		}
+
+		wait := outcome.retryAfter
+		if wait <= 0 {
+			wait = se.backoff << attempt
+		}
+		wait = min(wait, se.maxWait)
+		se.logger.Debug().Int("documents", len(pending)).Dur("wait", wait).Msg("Cluster is pushing back, retrying")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return indexed, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return indexed, nil
+}
+
+// sendBulk sends one _bulk request. Backpressure (429, 503) is reported through the outcome's retry list;
+// other failures are returned as errors.
+func (se *SearchExporter) sendBulk(ctx context.Context, index string, docs []searchDocument) (bulkOutcome, error) {
+	var body bytes.Buffer
+	for _, doc := range docs {
+		action, err := json.Marshal(map[string]map[string]string{"index": {"_index": index, "_id": doc.id}})
+		if err != nil {
+			return bulkOutcome{}, errorwrapper.WrapError(err, "failed to encode bulk action")
+		}
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(doc.source)
+		body.WriteByte('\n')
+	}
+
+	resp, err := se.client.Do(&httpclient.HTTPRequest{
+		URL:     strings.TrimSuffix(se.config.URL, "/") + "/_bulk",
+		Method:  http.MethodPost,
+		Headers: se.requestHeaders(),
+		Body:    &body,
+		Context: ctx,
+	})
+	if err != nil {
+		return bulkOutcome{}, errorwrapper.WrapError(err, "bulk request failed")
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		return bulkOutcome{retry: docs, retryAfter: parseRetryAfter(resp.Headers["Retry-After"], se.maxWait)}, nil
+	case resp.StatusCode >= 300:
+		return bulkOutcome{}, errorwrapper.NewError("bulk request returned status %d: %s", resp.StatusCode, truncateString(string(resp.Body), 200))
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return bulkOutcome{}, errorwrapper.WrapError(err, "failed to decode bulk response")
+	}
+	if !result.Errors {
+		return bulkOutcome{indexed: len(docs)}, nil
+	}
+
+	// Items are returned in request order, one per document
+	var outcome bulkOutcome
+	for i, item := range result.Items {
+		for _, itemResult := range item {
+			switch {
+			case itemResult.Status < 300:
+				outcome.indexed++
+			case itemResult.Status == http.StatusTooManyRequests && i < len(docs):
+				outcome.retry = append(outcome.retry, docs[i])
+			default:
+				outcome.failed++
+				if outcome.firstFailure == "" {
+					outcome.firstFailure = truncateString(string(itemResult.Error), 200)
+				}
+			}
+		}
+	}
+	return outcome, nil
+}
+
+// requestHeaders returns the bulk request headers, including authentication when configured
+func (se *SearchExporter) requestHeaders() map[string]string {
+	headers := map[string]string{"Content-Type": "application/x-ndjson"}
+	switch {
+	case se.config.APIKey != "":
+		headers["Authorization"] = "ApiKey " + se.config.APIKey
+	case se.config.Username != "":
+		credentials := base64.StdEncoding.EncodeToString([]byte(se.config.Username + ":" + se.config.Password))
+		headers["Authorization"] = "Basic " + credentials
+	}
+	return headers
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds, at most limit, returning 0 when absent or malformed
+func parseRetryAfter(value string, limit time.Duration) time.Duration {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	if seconds > int64(limit/time.Second) {
+		return limit
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// truncateString shortens s to at most maxLen bytes for logging
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/httpclient"
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/httpxrunner"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSearchCluster is a _bulk endpoint whose answers are scripted per request
+type fakeSearchCluster struct {
+	mu       sync.Mutex
+	requests [][]map[string]any // Decoded NDJSON lines of each request
+	headers  []http.Header
+	respond  func(request int, docs int) (status int, body string)
+}
+
+func (fc *fakeSearchCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var lines []map[string]any
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err == nil {
+			lines = append(lines, line)
+		}
+	}
+
+	fc.mu.Lock()
+	fc.requests = append(fc.requests, lines)
+	fc.headers = append(fc.headers, r.Header.Clone())
+	request := len(fc.requests)
+	fc.mu.Unlock()
+
+	status, body := fc.respond(request, len(lines)/2)
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
+
+func bulkOK(docs int) string {
+	return `{"errors":false,"items":[` + strings.TrimSuffix(strings.Repeat(`{"index":{"status":201}},`, docs), ",") + `]}`
+}
+
+func newTestSearchExporter(t *testing.T, cluster *fakeSearchCluster, cfg config.SearchExportConfig) *SearchExporter {
+	server := httptest.NewServer(cluster)
+	t.Cleanup(server.Close)
+
+	cfg.Enabled = true
+	cfg.URL = server.URL
+	exporter := NewSearchExporter(cfg, httpclient.ProxyConfig{}, zerolog.Nop())
+	require.NotNil(t, exporter)
+	exporter.backoff = time.Millisecond
+	return exporter
+}
+
+func testProbeResults(n int) []httpxrunner.ProbeResult {
+	results := make([]httpxrunner.ProbeResult, n)
+	for i := range results {
+		results[i] = httpxrunner.ProbeResult{
+			InputURL:   "https://example.com/" + string(rune('a'+i)),
+			Method:     http.MethodGet,
+			StatusCode: 200,
+			Body:       "<html>large body</html>",
+			URLStatus:  "new",
+		}
+	}
+	return results
+}
+
+func TestSearchExporter_DisabledIsNil(t *testing.T) {
+	exporter := NewSearchExporter(config.NewDefaultSearchExportConfig(), httpclient.ProxyConfig{}, zerolog.Nop())
+	assert.Nil(t, exporter)
+	assert.Zero(t, exporter.Export(context.Background(), "session", testProbeResults(1)))
+}
+
+func TestSearchExporter_IndexesInBatches(t *testing.T) {
+	cluster := &fakeSearchCluster{respond: func(_ int, docs int) (int, string) { return http.StatusOK, bulkOK(docs) }}
+	exporter := newTestSearchExporter(t, cluster, config.SearchExportConfig{Index: "MonsterInc-{session}", BatchSize: 2, APIKey: "secret"})
+
+	indexed := exporter.Export(context.Background(), "20240309-140507", testProbeResults(5))
+	assert.Equal(t, 5, indexed)
+	require.Len(t, cluster.requests, 3)
+	assert.Len(t, cluster.requests[2], 2, "last batch holds the remaining document")
+
+	action := cluster.requests[0][0]["index"].(map[string]any)
+	assert.Equal(t, "monsterinc-20240309-140507", action["_index"], "index names are expanded and lower-cased")
+	assert.NotEmpty(t, action["_id"])
+
+	doc := cluster.requests[0][1]
+	assert.Equal(t, "https://example.com/a", doc["input_url"])
+	assert.Equal(t, "20240309-140507", doc["scan_session_id"])
+	assert.Equal(t, "new", doc["url_status"])
+	assert.Contains(t, doc, "@timestamp")
+	assert.NotContains(t, doc, "body")
+
+	assert.Equal(t, "ApiKey secret", cluster.headers[0].Get("Authorization"))
+	assert.Equal(t, "application/x-ndjson", cluster.headers[0].Get("Content-Type"))
+}
+
+func TestSearchExporter_RetriesBackpressure(t *testing.T) {
+	cluster := &fakeSearchCluster{respond: func(request int, docs int) (int, string) {
+		switch request {
+		case 1:
+			return http.StatusTooManyRequests, `{"error":"es_rejected_execution_exception"}`
+		case 2:
+			// Second document rejected by a busy shard, third rejected for good
+			return http.StatusOK, `{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}},{"index":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`
+		default:
+			return http.StatusOK, bulkOK(docs)
+		}
+	}}
+	exporter := newTestSearchExporter(t, cluster, config.SearchExportConfig{MaxRetries: 3, Username: "elastic", Password: "changeme"})
+
+	indexed := exporter.Export(context.Background(), "session", testProbeResults(3))
+	assert.Equal(t, 2, indexed)
+	require.Len(t, cluster.requests, 3)
+	assert.Len(t, cluster.requests[1], 6, "a pushed-back request is resent whole")
+	require.Len(t, cluster.requests[2], 2, "only the rejected document is resent")
+	assert.Equal(t, "https://example.com/b", cluster.requests[2][1]["input_url"])
+	assert.True(t, strings.HasPrefix(cluster.headers[0].Get("Authorization"), "Basic "))
+}
+
+func TestSearchExporter_StopsWhenClusterKeepsPushingBack(t *testing.T) {
+	cluster := &fakeSearchCluster{respond: func(int, int) (int, string) { return http.StatusServiceUnavailable, "" }}
+	exporter := newTestSearchExporter(t, cluster, config.SearchExportConfig{BatchSize: 1, MaxRetries: 2})
+
+	indexed := exporter.Export(context.Background(), "session", testProbeResults(3))
+	assert.Zero(t, indexed)
+	assert.Len(t, cluster.requests, 3, "first batch tried once plus two retries, later batches skipped")
+}
+
+func TestSearchExporter_CapsRetryAfter(t *testing.T) {
+	cluster := &fakeSearchCluster{respond: func(request int, docs int) (int, string) {
+		if request == 1 {
+			return http.StatusTooManyRequests, ""
+		}
+		return http.StatusOK, bulkOK(docs)
+	}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "86400")
+		cluster.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	exporter := NewSearchExporter(config.SearchExportConfig{Enabled: true, URL: server.URL, MaxRetries: 1}, httpclient.ProxyConfig{}, zerolog.Nop())
+	require.NotNil(t, exporter)
+	exporter.maxWait = 10 * time.Millisecond
+
+	started := time.Now()
+	assert.Equal(t, 2, exporter.Export(context.Background(), "session", testProbeResults(2)))
+	assert.Less(t, time.Since(started), 5*time.Second, "a day-long Retry-After is cut to maxWait")
+
+	// Without the cap, the wait still ends with the scan
+	exporter.maxWait = time.Hour
+	cluster.requests = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started = time.Now()
+	assert.Zero(t, exporter.Export(ctx, "session", testProbeResults(2)))
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter(" 3 ", time.Minute))
+	assert.Equal(t, time.Minute, parseRetryAfter("86400", time.Minute))
+	assert.Equal(t, time.Minute, parseRetryAfter("99999999999999999", time.Minute), "no overflow")
+	assert.Zero(t, parseRetryAfter("Wed, 21 Oct 2015 07:28:00 GMT", time.Minute))
+	assert.Zero(t, parseRetryAfter("-5", time.Minute))
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+)
+
+// checkCertificateExpiry notifies about scanned hosts whose TLS certificate expires within
+// SchedulerConfig.TLSExpiryWarningDays. Each certificate is announced once, not on every cycle.
+func (s *Scheduler) checkCertificateExpiry(ctx context.Context, summaryData summary.ScanSummaryData) {
+	expiring := s.certificatesToWarn(summaryData.HostStats, time.Now())
+	if len(expiring) == 0 {
+		return
+	}
+
+	s.logger.Warn().
+		Str("scan_session_id", summaryData.ScanSessionID).
+		Int("hosts", len(expiring)).
+		Int("warning_days", s.globalConfig.SchedulerConfig.TLSExpiryWarningDays).
+		Time("soonest_expiry", expiring[0].CertNotAfter).
+		Msg("TLS certificates expiring soon")
+
+	if s.notificationHelper != nil {
+		s.notificationHelper.SendCertificateExpiryNotification(ctx, summaryData.ScanSessionID, expiring, s.globalConfig.SchedulerConfig.TLSExpiryWarningDays)
+	}
+}
+
+// certificatesToWarn returns the expiring certificates not yet announced and marks them as announced.
+// A host is announced again once it serves a different certificate.
+func (s *Scheduler) certificatesToWarn(hostStats map[string]summary.HostStats, now time.Time) []summary.HostStats {
+	window := s.globalConfig.SchedulerConfig.TLSExpiryWindow()
+	if window == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.certExpiryWarned == nil {
+		s.certExpiryWarned = make(map[string]time.Time)
+	}
+
+	var pending []summary.HostStats
+	for _, hs := range summary.ExpiringCertificates(hostStats, window, now) {
+		if warned, ok := s.certExpiryWarned[hs.Host]; ok && warned.Equal(hs.CertNotAfter) {
+			continue
+		}
+		s.certExpiryWarned[hs.Host] = hs.CertNotAfter
+		pending = append(pending, hs)
+	}
+	return pending
+}
@@ -2,19 +2,41 @@ package scheduler
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/aleister1102/monsterinc/internal/common/summary"
 	"github.com/rs/zerolog"
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// sqliteBusyTimeout is how long SQLite itself waits on a locked database before returning SQLITE_BUSY
+	sqliteBusyTimeout = 5 * time.Second
+	// dbLockRetryAttempts and dbLockRetryBaseDelay bound the retries on top of the busy timeout;
+	// the delay doubles after every attempt
+	dbLockRetryAttempts  = 3
+	dbLockRetryBaseDelay = 500 * time.Millisecond
 )
 
 // DB wraps the SQL database connection and provides methods for interacting with scan history.
+// Writes that hit a locked database are retried; when they still fail the scheduler keeps running
+// on the in-memory last scan time instead of losing track of its cycles.
 type DB struct {
 	db     *sql.DB
 	logger zerolog.Logger
+
+	mu           sync.Mutex
+	lastScanTime time.Time // Start of the last completed scan, kept in memory in case history writes fail
+	degraded     atomic.Bool
+	retryDelay   time.Duration
 }
 
 // ScanHistoryEntry represents a record in the scan_history table.
@@ -41,7 +63,41 @@ CREATE TABLE IF NOT EXISTS scan_history (
 	log_summary TEXT,
 	new_urls INTEGER DEFAULT 0,
 	old_urls INTEGER DEFAULT 0,
-	existing_urls INTEGER DEFAULT 0
+	existing_urls INTEGER DEFAULT 0,
+	scan_start_unix INTEGER
+);`
+
+// scan_start_time is stored as text SQLite cannot compare across zone offsets, so scan_start_unix repeats it
+// in Unix seconds for windowed queries. Databases created before the column existed get it added and filled in.
+const createScanStartIndexQuery = `CREATE INDEX IF NOT EXISTS idx_scan_history_scan_start_unix ON scan_history (scan_start_unix);`
+
+// Per-host and per-content-type changes of each completed scan, read back by the change digest.
+// recorded_at holds Unix seconds so windows can be compared in SQL.
+const createChangeTablesQuery = `
+CREATE TABLE IF NOT EXISTS scan_host_changes (
+	scan_session_id TEXT NOT NULL,
+	recorded_at INTEGER NOT NULL,
+	host TEXT NOT NULL,
+	new_urls INTEGER DEFAULT 0,
+	old_urls INTEGER DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_scan_host_changes_recorded_at ON scan_host_changes (recorded_at);
+CREATE TABLE IF NOT EXISTS scan_content_type_changes (
+	scan_session_id TEXT NOT NULL,
+	recorded_at INTEGER NOT NULL,
+	content_type TEXT NOT NULL,
+	new_urls INTEGER DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_scan_content_type_changes_recorded_at ON scan_content_type_changes (recorded_at);`
+
+// Every host probed by a completed scan, used to spot hosts that appear in scope for the first time.
+// first_seen_at and last_seen_at hold Unix seconds.
+const createHostsTableQuery = `
+CREATE TABLE IF NOT EXISTS scan_hosts (
+	host TEXT PRIMARY KEY,
+	first_seen_session TEXT NOT NULL,
+	first_seen_at INTEGER NOT NULL,
+	last_seen_at INTEGER NOT NULL
 );`
 
 // NewDB initializes a new DB connection and ensures the schema is set up.
@@ -56,8 +112,9 @@ func NewDB(dataSourceName string, logger zerolog.Logger) (*DB, error) {
 	}
 
 	db := &DB{
-		db:     dbInstance,
-		logger: logger,
+		db:         dbInstance,
+		logger:     logger,
+		retryDelay: dbLockRetryBaseDelay,
 	}
 
 	if err := db.InitSchema(); err != nil {
@@ -79,13 +136,84 @@ func ensureDBDirectory(dataSourceName string) error {
 }
 
 func openDatabase(dataSourceName string) (*sql.DB, error) {
-	dbInstance, err := sql.Open("sqlite", dataSourceName)
+	dbInstance, err := sql.Open("sqlite", withBusyTimeout(dataSourceName))
 	if err != nil {
 		return nil, fmt.Errorf("sql.Open failed for %s: %w", dataSourceName, err)
 	}
 	return dbInstance, nil
 }
 
+// withBusyTimeout adds the busy_timeout pragma to the data source, so every pooled connection
+// waits for a lock held by another process instead of failing at once
+func withBusyTimeout(dataSourceName string) string {
+	separator := "?"
+	if strings.Contains(dataSourceName, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=busy_timeout(%d)", dataSourceName, separator, sqliteBusyTimeout.Milliseconds())
+}
+
+// isLockError reports whether err means the database or a table was locked by another connection
+func isLockError(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff // Strip the extended result code
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	}
+	return err != nil && strings.Contains(err.Error(), "database is locked")
+}
+
+// withLockRetry runs op, retrying while the database is locked. The first failure after retries
+// marks the database as degraded and the next success clears it, each logged once.
+func (d *DB) withLockRetry(operation string, op func() error) error {
+	delay := d.retryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = op()
+		if err == nil || !isLockError(err) || attempt > dbLockRetryAttempts {
+			break
+		}
+		d.logger.Warn().
+			Err(err).
+			Str("operation", operation).
+			Int("attempt", attempt).
+			Dur("retry_in", delay).
+			Msg("Scheduler database is locked, retrying")
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	if err != nil {
+		if isLockError(err) && d.degraded.CompareAndSwap(false, true) {
+			d.logger.Error().
+				Err(err).
+				Str("operation", operation).
+				Msg("Scheduler database stays locked; scan history is degraded and scheduling falls back to in-memory state")
+		}
+		return err
+	}
+	if d.degraded.CompareAndSwap(true, false) {
+		d.logger.Info().Str("operation", operation).Msg("Scheduler database is writable again; scan history recording resumed")
+	}
+	return nil
+}
+
+// RememberScanTime keeps the start of a completed scan in memory, whether or not it reached the database
+func (d *DB) RememberScanTime(startTime time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if startTime.After(d.lastScanTime) {
+		d.lastScanTime = startTime
+	}
+}
+
+// rememberedScanTime returns the in-memory last scan time, or the zero time
+func (d *DB) rememberedScanTime() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastScanTime
+}
+
 // Close closes the database connection.
 func (d *DB) Close() error {
 	if d.db != nil {
@@ -94,21 +222,94 @@ func (d *DB) Close() error {
 	return nil
 }
 
-// InitSchema creates the scan_history table if it doesn't already exist.
+// InitSchema creates the scan_history, change and host tables if they don't already exist.
 func (d *DB) InitSchema() error {
-	_, err := d.db.Exec(createTableQuery)
-	if err != nil {
+	if _, err := d.db.Exec(createTableQuery); err != nil {
+		return err
+	}
+	if err := d.addScanStartUnixColumn(); err != nil {
+		return err
+	}
+	if _, err := d.db.Exec(createScanStartIndexQuery); err != nil {
+		return err
+	}
+	if _, err := d.db.Exec(createChangeTablesQuery); err != nil {
+		return err
+	}
+	if _, err := d.db.Exec(createHostsTableQuery); err != nil {
 		return err
 	}
 	return nil
 }
 
+// addScanStartUnixColumn adds scan_start_unix to a scan_history table created without it and fills it in
+// from scan_start_time
+func (d *DB) addScanStartUnixColumn() error {
+	var exists int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('scan_history') WHERE name = 'scan_start_unix'`).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to inspect scan_history: %w", err)
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	if _, err := d.db.Exec(`ALTER TABLE scan_history ADD COLUMN scan_start_unix INTEGER`); err != nil {
+		return fmt.Errorf("failed to add scan_start_unix: %w", err)
+	}
+
+	rows, err := d.db.Query(`SELECT id, scan_start_time FROM scan_history`)
+	if err != nil {
+		return fmt.Errorf("failed to read scan start times: %w", err)
+	}
+	startTimes := make(map[int64]time.Time)
+	unreadable := 0
+	for rows.Next() {
+		var id int64
+		var startTime any
+		if err := rows.Scan(&id, &startTime); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan scan start time: %w", err)
+		}
+		// The driver returns the raw text when it cannot parse a time; such scans stay out of windowed queries
+		if parsed, ok := startTime.(time.Time); ok {
+			startTimes[id] = parsed
+		} else {
+			unreadable++
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read scan start times: %w", err)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin scan_start_unix backfill: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for id, startTime := range startTimes {
+		if _, err := tx.Exec(`UPDATE scan_history SET scan_start_unix = ? WHERE id = ?`, startTime.Unix(), id); err != nil {
+			return fmt.Errorf("failed to fill in scan_start_unix: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit scan_start_unix backfill: %w", err)
+	}
+
+	d.logger.Info().Int("scans", len(startTimes)).Int("unreadable_start_times", unreadable).Msg("Added scan_start_unix to scan history")
+	return nil
+}
+
 // RecordScanStart inserts a new record into scan_history with status "STARTED"
 // and returns the ID of the newly inserted row.
 func (d *DB) RecordScanStart(scanSessionID string, targetSource string, numTargets int, startTime time.Time) (int64, error) {
-	query := `INSERT INTO scan_history (scan_session_id, target_source, num_targets, scan_start_time, status) VALUES (?, ?, ?, ?, ?)`
+	query := `INSERT INTO scan_history (scan_session_id, target_source, num_targets, scan_start_time, scan_start_unix, status) VALUES (?, ?, ?, ?, ?, ?)`
 
-	result, err := d.db.Exec(query, scanSessionID, targetSource, numTargets, startTime, ScanStatusStarted)
+	var result sql.Result
+	err := d.withLockRetry("record_scan_start", func() (err error) {
+		result, err = d.db.Exec(query, scanSessionID, targetSource, numTargets, startTime, startTime.Unix(), ScanStatusStarted)
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert scan start record: %w", err)
 	}
@@ -125,17 +326,20 @@ func (d *DB) RecordScanStart(scanSessionID string, targetSource string, numTarge
 func (d *DB) UpdateScanCompletion(dbScanID int64, endTime time.Time, status string, logSummary string, newURLs int, oldURLs int, existingURLs int, reportPath string) error {
 	query := `UPDATE scan_history SET scan_end_time = ?, status = ?, log_summary = ?, new_urls = ?, old_urls = ?, existing_urls = ?, report_file_path = ? WHERE id = ?`
 
-	_, err := d.db.Exec(
-		query,
-		endTime,
-		status,
-		createNullString(logSummary),
-		newURLs,
-		oldURLs,
-		existingURLs,
-		createNullString(reportPath),
-		dbScanID,
-	)
+	err := d.withLockRetry("update_scan_completion", func() error {
+		_, err := d.db.Exec(
+			query,
+			endTime,
+			status,
+			createNullString(logSummary),
+			newURLs,
+			oldURLs,
+			existingURLs,
+			createNullString(reportPath),
+			dbScanID,
+		)
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to update scan completion for ID %d: %w", dbScanID, err)
@@ -151,19 +355,219 @@ func createNullString(value string) sql.NullString {
 	}
 }
 
-// GetLastScanTime retrieves the scan_start_time of the most recent scan attempt
+// GetLastScanTime retrieves the scan_start_time of the most recent completed scan.
+// When the query fails, or the history lags behind because writes were lost to a locked database,
+// the time remembered in memory (see RememberScanTime) is returned instead.
 func (d *DB) GetLastScanTime() (*time.Time, error) {
 	query := `SELECT scan_start_time FROM scan_history WHERE status = ? ORDER BY scan_start_time DESC LIMIT 1`
 
 	var scanStartTime time.Time
-	err := d.db.QueryRow(query, ScanStatusCompleted).Scan(&scanStartTime)
+	err := d.withLockRetry("get_last_scan_time", func() error {
+		return d.db.QueryRow(query, ScanStatusCompleted).Scan(&scanStartTime)
+	})
 
+	remembered := d.rememberedScanTime()
 	if err != nil {
+		if !remembered.IsZero() {
+			if err != sql.ErrNoRows {
+				d.logger.Warn().Err(err).Time("last_scan_time", remembered).Msg("Failed to query last scan time, using the in-memory value")
+			}
+			return &remembered, nil
+		}
 		if err == sql.ErrNoRows {
 			return nil, err
 		}
 		return nil, fmt.Errorf("failed to query last scan start time: %w", err)
 	}
 
+	if remembered.After(scanStartTime) {
+		return &remembered, nil
+	}
 	return &scanStartTime, nil
 }
+
+// RecordScanChanges stores the hosts and content types with new or removed URLs of a completed scan
+func (d *DB) RecordScanChanges(scanSessionID string, recordedAt time.Time, hostStats map[string]summary.HostStats) error {
+	return d.withLockRetry("record_scan_changes", func() error {
+		return d.recordScanChanges(scanSessionID, recordedAt, hostStats)
+	})
+}
+
+// recordScanChanges writes the changes of one scan in a single transaction
+func (d *DB) recordScanChanges(scanSessionID string, recordedAt time.Time, hostStats map[string]summary.HostStats) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin scan changes transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	recordedAtUnix := recordedAt.Unix()
+	for host, hs := range hostStats {
+		if hs.New == 0 && hs.Old == 0 {
+			continue
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO scan_host_changes (scan_session_id, recorded_at, host, new_urls, old_urls) VALUES (?, ?, ?, ?, ?)`,
+			scanSessionID, recordedAtUnix, host, hs.New, hs.Old,
+		); err != nil {
+			return fmt.Errorf("failed to insert host changes for %s: %w", host, err)
+		}
+		for contentType, count := range hs.NewByContentType {
+			if _, err := tx.Exec(
+				`INSERT INTO scan_content_type_changes (scan_session_id, recorded_at, content_type, new_urls) VALUES (?, ?, ?, ?)`,
+				scanSessionID, recordedAtUnix, contentType, count,
+			); err != nil {
+				return fmt.Errorf("failed to insert content type changes for %s: %w", host, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit scan changes: %w", err)
+	}
+	return nil
+}
+
+// RecordScanHosts stores the hosts probed by a completed scan and returns those no earlier scan probed.
+// The first scan recorded only builds the baseline, so it returns no new hosts.
+func (d *DB) RecordScanHosts(scanSessionID string, recordedAt time.Time, hosts []string) ([]string, error) {
+	var newHosts []string
+	err := d.withLockRetry("record_scan_hosts", func() (err error) {
+		newHosts, err = d.recordScanHosts(scanSessionID, recordedAt, hosts)
+		return err
+	})
+	return newHosts, err
+}
+
+// recordScanHosts writes the hosts of one scan in a single transaction
+func (d *DB) recordScanHosts(scanSessionID string, recordedAt time.Time, hosts []string) ([]string, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin scan hosts transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var knownHosts int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM scan_hosts`).Scan(&knownHosts); err != nil {
+		return nil, fmt.Errorf("failed to count known hosts: %w", err)
+	}
+
+	recordedAtUnix := recordedAt.Unix()
+	var newHosts []string
+	for _, host := range hosts {
+		result, err := tx.Exec(
+			`INSERT OR IGNORE INTO scan_hosts (host, first_seen_session, first_seen_at, last_seen_at) VALUES (?, ?, ?, ?)`,
+			host, scanSessionID, recordedAtUnix, recordedAtUnix,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert host %s: %w", host, err)
+		}
+		if inserted, _ := result.RowsAffected(); inserted > 0 {
+			newHosts = append(newHosts, host)
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE scan_hosts SET last_seen_at = ? WHERE host = ?`, recordedAtUnix, host); err != nil {
+			return nil, fmt.Errorf("failed to update host %s: %w", host, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit scan hosts: %w", err)
+	}
+	if knownHosts == 0 {
+		return nil, nil
+	}
+	return newHosts, nil
+}
+
+// GetChangeDigest aggregates the scans that started in [since, until) and the changes recorded in that window
+func (d *DB) GetChangeDigest(since, until time.Time) (summary.ChangeDigest, error) {
+	digest := summary.ChangeDigest{
+		WindowStart:  since,
+		WindowEnd:    until,
+		ContentTypes: make(map[string]int),
+	}
+
+	if err := d.addScanHistoryToDigest(&digest); err != nil {
+		return digest, err
+	}
+
+	hostRows, err := d.db.Query(
+		`SELECT host, SUM(new_urls), SUM(old_urls) FROM scan_host_changes WHERE recorded_at >= ? AND recorded_at < ? GROUP BY host`,
+		since.Unix(), until.Unix(),
+	)
+	if err != nil {
+		return digest, fmt.Errorf("failed to query host changes: %w", err)
+	}
+	hosts := make(map[string]summary.HostStats)
+	for hostRows.Next() {
+		var hs summary.HostStats
+		if err := hostRows.Scan(&hs.Host, &hs.New, &hs.Old); err != nil {
+			_ = hostRows.Close()
+			return digest, fmt.Errorf("failed to scan host changes: %w", err)
+		}
+		hosts[hs.Host] = hs
+	}
+	_ = hostRows.Close()
+	if err := hostRows.Err(); err != nil {
+		return digest, fmt.Errorf("failed to read host changes: %w", err)
+	}
+	digest.Hosts = summary.SortedHostStats(hosts)
+
+	typeRows, err := d.db.Query(
+		`SELECT content_type, SUM(new_urls) FROM scan_content_type_changes WHERE recorded_at >= ? AND recorded_at < ? GROUP BY content_type`,
+		since.Unix(), until.Unix(),
+	)
+	if err != nil {
+		return digest, fmt.Errorf("failed to query content type changes: %w", err)
+	}
+	defer func() { _ = typeRows.Close() }()
+	for typeRows.Next() {
+		var contentType string
+		var count int
+		if err := typeRows.Scan(&contentType, &count); err != nil {
+			return digest, fmt.Errorf("failed to scan content type changes: %w", err)
+		}
+		digest.ContentTypes[contentType] = count
+	}
+	if err := typeRows.Err(); err != nil {
+		return digest, fmt.Errorf("failed to read content type changes: %w", err)
+	}
+
+	return digest, nil
+}
+
+// addScanHistoryToDigest counts the scans of the digest window and collects the reports of those with changes,
+// newest first
+func (d *DB) addScanHistoryToDigest(digest *summary.ChangeDigest) error {
+	rows, err := d.db.Query(
+		`SELECT status, new_urls, old_urls, report_file_path FROM scan_history
+		WHERE status != ? AND scan_start_unix >= ? AND scan_start_unix < ? ORDER BY id DESC`,
+		ScanStatusStarted, digest.WindowStart.Unix(), digest.WindowEnd.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query scan history: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var status string
+		var newURLs, oldURLs int
+		var reportPath sql.NullString
+		if err := rows.Scan(&status, &newURLs, &oldURLs, &reportPath); err != nil {
+			return fmt.Errorf("failed to scan scan history: %w", err)
+		}
+
+		if status == ScanStatusFailed {
+			digest.FailedScans++
+			continue
+		}
+		digest.Scans++
+		digest.NewURLs += newURLs
+		digest.OldURLs += oldURLs
+		if (newURLs > 0 || oldURLs > 0) && reportPath.Valid {
+			digest.ReportPaths = append(digest.ReportPaths, reportPath.String)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+)
+
+// tryStartDigestService starts the change digest loop when SchedulerConfig.Digest is enabled
+func (s *Scheduler) tryStartDigestService(ctx context.Context) {
+	if !s.globalConfig.SchedulerConfig.Digest.Enabled {
+		return
+	}
+
+	s.wg.Add(1)
+	go s.runDigest(ctx)
+}
+
+// runDigest sends a change digest every time the digest cron expression fires
+func (s *Scheduler) runDigest(ctx context.Context) {
+	defer s.wg.Done()
+
+	digestCfg := s.globalConfig.SchedulerConfig.Digest
+	for {
+		nextDigestTime, err := config.NextCronTime(digestCfg.CronExpression, time.Now())
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to calculate next change digest time, digest disabled")
+			return
+		}
+
+		s.logger.Info().Time("next_digest", nextDigestTime).Msg("Waiting for next change digest")
+
+		select {
+		case <-time.After(time.Until(nextDigestTime)):
+			s.sendChangeDigest(ctx, time.Now())
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// sendChangeDigest notifies about the changes recorded during the digest window ending at now
+func (s *Scheduler) sendChangeDigest(ctx context.Context, now time.Time) {
+	window := s.globalConfig.SchedulerConfig.Digest.Window()
+	digest, err := s.db.GetChangeDigest(now.Add(-window), now)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to build change digest")
+		return
+	}
+
+	s.logger.Info().
+		Int("scans", digest.Scans).
+		Int("new_urls", digest.NewURLs).
+		Int("old_urls", digest.OldURLs).
+		Int("hosts", len(digest.Hosts)).
+		Msg("Change digest built")
+
+	if s.notificationHelper != nil {
+		s.notificationHelper.SendChangeDigestNotification(ctx, digest)
+	}
+}
@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/common/summary"
+)
+
+// recordScanHosts stores the hosts probed by a completed scan and returns those no earlier scan probed.
+// Failures are logged and yield no new hosts, so a history problem never fails the scan.
+func (s *Scheduler) recordScanHosts(summaryData summary.ScanSummaryData) []string {
+	newHosts, err := s.db.RecordScanHosts(summaryData.ScanSessionID, time.Now(), summary.HostNames(summaryData.HostStats))
+	if err != nil {
+		s.logger.Error().Err(err).Str("scan_session_id", summaryData.ScanSessionID).Msg("Scheduler: Failed to record scan hosts in database")
+		return nil
+	}
+	return newHosts
+}
+
+// notifyNewHosts notifies about hosts that appeared in scope for the first time with this scan
+func (s *Scheduler) notifyNewHosts(ctx context.Context, summaryData summary.ScanSummaryData) {
+	if len(summaryData.NewHosts) == 0 {
+		return
+	}
+
+	s.logger.Info().
+		Str("scan_session_id", summaryData.ScanSessionID).
+		Int("new_hosts", len(summaryData.NewHosts)).
+		Strs("hosts", summaryData.NewHosts).
+		Msg("New hosts discovered")
+
+	if s.notificationHelper != nil && s.globalConfig.SchedulerConfig.NotifyNewHosts {
+		s.notificationHelper.SendNewHostsNotification(ctx, summaryData)
+	}
+}
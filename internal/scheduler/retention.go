@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleister1102/monsterinc/internal/datastore"
+)
+
+// tryStartRetentionService starts the periodic storage cleanup when StorageConfig.Retention is enabled.
+// The cleanup at startup is run by the caller for both modes.
+func (s *Scheduler) tryStartRetentionService(ctx context.Context) {
+	if !s.globalConfig.StorageConfig.Retention.Enabled {
+		return
+	}
+
+	cleaner, err := datastore.NewRetentionCleaner(&s.globalConfig.StorageConfig, s.logger)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to create storage retention cleaner, periodic cleanup disabled")
+		return
+	}
+
+	s.wg.Add(1)
+	go s.runRetention(ctx, cleaner)
+}
+
+// runRetention applies the retention policy every cleanup interval
+func (s *Scheduler) runRetention(ctx context.Context, cleaner *datastore.RetentionCleaner) {
+	defer s.wg.Done()
+
+	interval := s.globalConfig.StorageConfig.Retention.CleanupInterval()
+	for {
+		s.logger.Debug().Time("next_cleanup", time.Now().Add(interval)).Msg("Waiting for next storage retention cleanup")
+
+		select {
+		case <-time.After(interval):
+			if _, err := cleaner.Cleanup(ctx); err != nil {
+				s.logger.Error().Err(err).Msg("Storage retention cleanup failed")
+			}
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/aleister1102/monsterinc/internal/common/contextutils"
 	"github.com/aleister1102/monsterinc/internal/common/errorwrapper"
 	"github.com/aleister1102/monsterinc/internal/common/summary"
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
 	"github.com/aleister1102/monsterinc/internal/logger"
 	"github.com/aleister1102/monsterinc/internal/scanner"
 	"github.com/rs/zerolog"
@@ -101,6 +102,7 @@ func (s *Scheduler) attemptScanCycle(ctx context.Context, config scanAttemptConf
 
 	// Add cycle minutes for completion notification
 	updatedSummary.CycleMinutes = s.globalConfig.SchedulerConfig.CycleMinutes
+	updatedSummary.CronExpression = s.globalConfig.SchedulerConfig.CronExpression
 
 	if err == nil {
 		s.logger.Info().Str("scan_session_id", config.scanSessionID).Msg("Scheduler: Cycle completed successfully.")
@@ -109,6 +111,8 @@ func (s *Scheduler) attemptScanCycle(ctx context.Context, config scanAttemptConf
 			updatedSummary,
 			reportFilePaths,
 		)
+		s.checkCertificateExpiry(context.Background(), updatedSummary)
+		s.notifyNewHosts(context.Background(), updatedSummary)
 		return true
 	}
 
@@ -132,6 +136,8 @@ func (s *Scheduler) executeScanCycle(
 	SetActiveScanSessionID(scanSessionID)
 	defer SetActiveScanSessionID("") // Clear when done
 	defer s.scanner.ResetCrawler()   // Reset crawler state after cycle
+	logger.SetScanTrace(scanSessionID)
+	defer logger.SetScanTrace("")
 
 	// Create scan logger
 	scanLogger, err := logger.NewWithScanID(s.globalConfig.LogConfig, scanSessionID)
@@ -161,6 +167,7 @@ func (s *Scheduler) executeScanCycle(
 	startSummary.TotalTargets = len(htmlURLs)
 	startSummary.Status = string(summary.ScanStatusStarted)
 	startSummary.CycleMinutes = s.globalConfig.SchedulerConfig.CycleMinutes
+	startSummary.CronExpression = s.globalConfig.SchedulerConfig.CronExpression
 
 	s.logger.Info().
 		Str("scan_session_id", scanSessionID).
@@ -231,6 +238,10 @@ func (s *Scheduler) executeSchedulerBatchScan(
 	batchResult.SummaryData.ScanDuration = actualScanDuration
 
 	// Update database with success
+	if batchResult.SummaryData.Status == string(summary.ScanStatusCompleted) {
+		s.db.RememberScanTime(startTime)
+		batchResult.SummaryData.NewHosts = s.recordScanHosts(batchResult.SummaryData)
+	}
 	s.updateDBOnSuccess(dbScanID, batchResult.SummaryData)
 
 	scanLogger.Info().
@@ -255,6 +266,8 @@ func (s *Scheduler) updateSummaryWithAttemptResult(summaryData summary.ScanSumma
 		WithTotalTargets(summaryData.TotalTargets).
 		WithProbeStats(summaryData.ProbeStats).
 		WithDiffStats(summaryData.DiffStats).
+		WithHostStats(summaryData.HostStats).
+		WithNewHosts(summaryData.NewHosts).
 		WithScanDuration(summaryData.ScanDuration)
 
 	if err == nil {
@@ -292,6 +305,7 @@ func (s *Scheduler) buildInterruptSummary(summaryData summary.ScanSummaryData) s
 		WithTotalTargets(summaryData.TotalTargets).
 		WithProbeStats(summaryData.ProbeStats).
 		WithDiffStats(summaryData.DiffStats).
+		WithHostStats(summaryData.HostStats).
 		WithScanDuration(summaryData.ScanDuration).
 		WithReportPath(summaryData.ReportPath).
 		WithStatus(summary.ScanStatusInterrupted).
@@ -306,6 +320,7 @@ func (s *Scheduler) handleFinalFailure(config scanAttemptConfig) {
 
 	// Add cycle minutes for failure notification
 	summary.CycleMinutes = s.globalConfig.SchedulerConfig.CycleMinutes
+	summary.CronExpression = s.globalConfig.SchedulerConfig.CronExpression
 
 	s.logger.Error().Str("scan_session_id", config.scanSessionID).Msg("Scheduler: All retry attempts exhausted.")
 	s.notificationHelper.SendScanCompletionNotification(
@@ -351,6 +366,11 @@ func (s *Scheduler) buildErrorSummary(baseSummary summary.ScanSummaryData, err e
 
 // Database operations
 func (s *Scheduler) updateDBOnFailure(dbScanID int64, err error) {
+	if dbScanID == 0 {
+		s.logger.Warn().Err(err).Msg("Scheduler: Scan has no history record, failure not recorded in database")
+		return
+	}
+
 	err = s.db.UpdateScanCompletion(
 		dbScanID,
 		time.Now(),
@@ -370,7 +390,9 @@ func (s *Scheduler) updateDBOnSuccess(dbScanID int64, result summary.ScanSummary
 		reportPath = result.ReportPath
 	}
 
-	err := s.db.UpdateScanCompletion(
+	if dbScanID == 0 {
+		s.logger.Warn().Str("scan_session_id", result.ScanSessionID).Msg("Scheduler: Scan has no history record, completion not recorded in database")
+	} else if err := s.db.UpdateScanCompletion(
 		dbScanID,
 		time.Now(),
 		result.Status,
@@ -379,10 +401,13 @@ func (s *Scheduler) updateDBOnSuccess(dbScanID int64, result summary.ScanSummary
 		result.DiffStats.Old,
 		result.DiffStats.Existing,
 		reportPath,
-	)
-	if err != nil {
+	); err != nil {
 		s.logger.Error().Err(err).Msg("Scheduler: Failed to update scan completion in database")
 	}
+
+	if err := s.db.RecordScanChanges(result.ScanSessionID, time.Now(), result.HostStats); err != nil {
+		s.logger.Error().Err(err).Msg("Scheduler: Failed to record scan changes in database")
+	}
 }
 
 func (s *Scheduler) buildLogSummary(result summary.ScanSummaryData) string {
@@ -408,6 +433,8 @@ func (s *Scheduler) updateSummaryWithScanResult(baseSummary summary.ScanSummaryD
 		WithTotalTargets(scanResult.TotalTargets).
 		WithProbeStats(scanResult.ProbeStats).
 		WithDiffStats(scanResult.DiffStats).
+		WithHostStats(scanResult.HostStats).
+		WithNewHosts(scanResult.NewHosts).
 		WithScanDuration(scanResult.ScanDuration).
 		WithReportPath(scanResult.ReportPath).
 		WithStatus(summary.ScanStatus(scanResult.Status)).
@@ -435,11 +462,15 @@ func (s *Scheduler) loadAndPrepareScanTargets(initialTargetSource string) (htmlU
 		return nil, determinedSource, errorwrapper.NewError("no targets to process from source: %s", determinedSource)
 	}
 
-	// Convert targets to string slice
+	// Convert targets to string slice; credentials stay with the scanner, out of URLs and logs
 	allTargetURLs := make([]string, len(targets))
 	for i, target := range targets {
 		allTargetURLs[i] = target.URL
 	}
+	s.scanner.SetTargetCredentials(urlhandler.NewTargetCredentials(targets))
+	s.scanner.SetTargetTags(urlhandler.NewTargetTags(targets))
+	s.scanner.SetTargetTimeouts(urlhandler.NewTargetTimeouts(targets))
+	s.reconcileTargets(allTargetURLs)
 
 	// All loaded URLs are used for scanning
 	htmlURLs = make([]string, len(allTargetURLs))
@@ -461,6 +492,14 @@ func (s *Scheduler) recordScanStartToDB(
 ) (int64, error) {
 	dbScanID, err := s.db.RecordScanStart(scanSessionID, targetSource, len(htmlURLs), startTime)
 	if err != nil {
+		if isLockError(err) {
+			// A locked history database must not cost the cycle; the scan runs without a history row
+			s.logger.Warn().
+				Err(err).
+				Str("scan_session_id", scanSessionID).
+				Msg("Scheduler: Scan history database is locked, running this scan without recording it")
+			return 0, nil
+		}
 		return 0, errorwrapper.WrapError(err, fmt.Sprintf("scheduler failed to record scan start in DB for session %s", scanSessionID))
 	}
 
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
 	"github.com/aleister1102/monsterinc/internal/config"
@@ -29,6 +31,9 @@ type Scheduler struct {
 	isStopped          bool
 	mu                 sync.Mutex
 	stopOnce           sync.Once
+	certExpiryWarned   map[string]time.Time // Host -> expiry of the certificate already announced
+	cycleTargets       map[string]struct{}  // Target URLs of the latest cycle; see reconcileTargets
+	paused             atomic.Bool          // Scan cycles are skipped while set; see Pause
 }
 
 // NewScheduler creates a new Scheduler instance
@@ -52,7 +57,7 @@ func NewScheduler(
 		logger:             schedulerLogger,
 		scanTargetsFile:    scanTargetsFile,
 		notificationHelper: notificationHelper,
-		targetManager:      urlhandler.NewTargetManager(schedulerLogger),
+		targetManager:      urlhandler.NewTargetManager(schedulerLogger).WithExpansionConfig(cfg.TargetExpansion.ToTargetExpansionConfig()).WithTagFilter(cfg.OnlyTags).WithSampling(cfg.TargetSampling.ToTargetSamplingConfig()),
 		scanner:            scanner,
 		stopChan:           make(chan struct{}),
 	}, nil
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/aleister1102/monsterinc/internal/config"
 )
 
 // Start begins the scheduler's main loop
@@ -29,6 +31,8 @@ func (s *Scheduler) startConfiguredServices(ctx context.Context) error {
 	if !s.tryStartScanService(ctx) {
 		return fmt.Errorf("scan service not configured to run")
 	}
+	s.tryStartDigestService(ctx)
+	s.tryStartRetentionService(ctx)
 
 	return nil
 }
@@ -58,7 +62,7 @@ func (s *Scheduler) runScanner(ctx context.Context) {
 
 	// Execute first scan immediately on startup
 	s.logger.Info().Msg("Executing initial scan immediately on startup")
-	s.executeScanCycleWithRetries(ctx)
+	s.executeScanCycleUnlessPaused(ctx)
 
 	// Continue with regular scheduled cycles
 	for {
@@ -77,7 +81,7 @@ func (s *Scheduler) runScanner(ctx context.Context) {
 			return
 		}
 
-		s.executeScanCycleWithRetries(ctx)
+		s.executeScanCycleUnlessPaused(ctx)
 	}
 }
 
@@ -124,8 +128,13 @@ func (s *Scheduler) waitForNextScan(ctx context.Context) (interrupted bool, err
 	}
 }
 
-// calculateNextScanTime calculates when the next scan should occur
+// calculateNextScanTime calculates when the next scan should occur.
+// A configured cron expression takes precedence over the fixed cycle interval.
 func (s *Scheduler) calculateNextScanTime() (time.Time, error) {
+	if s.globalConfig.SchedulerConfig.UsesCron() {
+		return config.NextCronTime(s.globalConfig.SchedulerConfig.CronExpression, time.Now())
+	}
+
 	cycleMinutes := s.globalConfig.SchedulerConfig.CycleMinutes
 	if cycleMinutes <= 0 {
 		return time.Time{}, fmt.Errorf("invalid cycle minutes: %d", cycleMinutes)
@@ -1,10 +1,17 @@
 package scheduler
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aleister1102/monsterinc/internal/common/summary"
 	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/rs/zerolog"
 )
 
 func TestScheduler_CalculateNextScanTime(t *testing.T) {
@@ -23,3 +30,326 @@ func TestScheduler_CalculateNextScanTime(t *testing.T) {
 		t.Errorf("expected next scan time in the future, got %v", next)
 	}
 }
+
+func TestScheduler_CalculateNextScanTime_Cron(t *testing.T) {
+	s := &Scheduler{
+		globalConfig: &config.GlobalConfig{
+			SchedulerConfig: config.SchedulerConfig{CycleMinutes: 10, CronExpression: "0 2 * * *"},
+		},
+	}
+
+	next, err := s.calculateNextScanTime()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if next.Hour() != 2 || next.Minute() != 0 {
+		t.Errorf("expected next scan at 02:00, got %v", next)
+	}
+	if !next.After(time.Now()) {
+		t.Errorf("expected next scan time in the future, got %v", next)
+	}
+}
+
+func TestScheduler_CertificatesToWarn(t *testing.T) {
+	now := time.Now()
+	s := &Scheduler{
+		globalConfig: &config.GlobalConfig{
+			SchedulerConfig: config.SchedulerConfig{TLSExpiryWarningDays: 14},
+		},
+	}
+	stats := map[string]summary.HostStats{
+		"soon.example.com":  {Host: "soon.example.com", CertNotAfter: now.Add(3 * 24 * time.Hour)},
+		"later.example.com": {Host: "later.example.com", CertNotAfter: now.Add(90 * 24 * time.Hour)},
+		"plain.example.com": {Host: "plain.example.com"},
+	}
+
+	if warned := s.certificatesToWarn(stats, now); len(warned) != 1 || warned[0].Host != "soon.example.com" {
+		t.Fatalf("expected only soon.example.com, got %v", warned)
+	}
+	if warned := s.certificatesToWarn(stats, now); len(warned) != 0 {
+		t.Errorf("expected an announced certificate not to be repeated, got %v", warned)
+	}
+
+	renewed := stats["soon.example.com"]
+	renewed.CertNotAfter = now.Add(10 * 24 * time.Hour)
+	stats["soon.example.com"] = renewed
+	if warned := s.certificatesToWarn(stats, now); len(warned) != 1 {
+		t.Errorf("expected a different certificate to be announced again, got %v", warned)
+	}
+
+	s.globalConfig.SchedulerConfig.TLSExpiryWarningDays = 0
+	s.certExpiryWarned = nil
+	if warned := s.certificatesToWarn(stats, now); len(warned) != 0 {
+		t.Errorf("expected no warnings when disabled, got %v", warned)
+	}
+}
+
+func TestDB_GetChangeDigest(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "scheduler.db"), zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	now := time.Now()
+	recordScan := func(sessionID string, start time.Time, status string, newURLs, oldURLs int, reportPath string, hostStats map[string]summary.HostStats) {
+		t.Helper()
+		id, err := db.RecordScanStart(sessionID, "targets.txt", 1, start)
+		if err != nil {
+			t.Fatalf("RecordScanStart: %v", err)
+		}
+		if err := db.UpdateScanCompletion(id, start.Add(time.Minute), status, "", newURLs, oldURLs, 0, reportPath); err != nil {
+			t.Fatalf("UpdateScanCompletion: %v", err)
+		}
+		if err := db.RecordScanChanges(sessionID, start.Add(time.Minute), hostStats); err != nil {
+			t.Fatalf("RecordScanChanges: %v", err)
+		}
+	}
+
+	recordScan("old", now.Add(-10*24*time.Hour), ScanStatusCompleted, 7, 0, "reports/old.html", map[string]summary.HostStats{
+		"a.example.com": {New: 7, NewByContentType: map[string]int{"text/html": 7}},
+	})
+	recordScan("first", now.Add(-3*24*time.Hour), ScanStatusCompleted, 3, 1, "reports/first.html", map[string]summary.HostStats{
+		"a.example.com": {New: 2, Old: 1, NewByContentType: map[string]int{"text/html": 1, "application/json": 1}},
+		"b.example.com": {New: 1, NewByContentType: map[string]int{"text/html": 1}},
+		"c.example.com": {Existing: 4},
+	})
+	recordScan("quiet", now.Add(-2*24*time.Hour), ScanStatusCompleted, 0, 0, "reports/quiet.html", nil)
+	recordScan("failed", now.Add(-24*time.Hour), ScanStatusFailed, 0, 0, "", nil)
+	recordScan("second", now.Add(-time.Hour), ScanStatusCompleted, 2, 0, "reports/second.html", map[string]summary.HostStats{
+		"b.example.com": {New: 2, NewByContentType: map[string]int{"application/json": 2}},
+	})
+
+	digest, err := db.GetChangeDigest(now.Add(-7*24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("GetChangeDigest: %v", err)
+	}
+
+	if digest.Scans != 3 || digest.FailedScans != 1 {
+		t.Errorf("expected 3 completed and 1 failed scans, got %d and %d", digest.Scans, digest.FailedScans)
+	}
+	if digest.NewURLs != 5 || digest.OldURLs != 1 {
+		t.Errorf("expected 5 new and 1 old URLs, got %d and %d", digest.NewURLs, digest.OldURLs)
+	}
+	if len(digest.ReportPaths) != 2 || digest.ReportPaths[0] != "reports/second.html" || digest.ReportPaths[1] != "reports/first.html" {
+		t.Errorf("expected reports with changes newest first, got %v", digest.ReportPaths)
+	}
+	if len(digest.Hosts) != 2 || digest.Hosts[0].Host != "b.example.com" || digest.Hosts[0].New != 3 || digest.Hosts[1].Old != 1 {
+		t.Errorf("unexpected hosts %+v", digest.Hosts)
+	}
+	if digest.ContentTypes["text/html"] != 2 || digest.ContentTypes["application/json"] != 3 || len(digest.ContentTypes) != 2 {
+		t.Errorf("unexpected content types %v", digest.ContentTypes)
+	}
+}
+
+func TestDB_AddsScanStartUnixToExistingHistory(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "scheduler.db")
+	legacy, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	legacySchema := strings.Replace(createTableQuery, ",\n\tscan_start_unix INTEGER", "", 1)
+	if _, err := legacy.Exec(legacySchema); err != nil {
+		t.Fatalf("failed to create legacy scan_history: %v", err)
+	}
+	now := time.Now()
+	start := now.Add(-time.Hour).In(time.FixedZone("+07", 7*3600))
+	insert := `INSERT INTO scan_history (scan_session_id, target_source, scan_start_time, status, new_urls, report_file_path) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := legacy.Exec(insert, "legacy", "targets.txt", start, ScanStatusCompleted, 4, "reports/legacy.html"); err != nil {
+		t.Fatalf("failed to insert legacy scan: %v", err)
+	}
+	if _, err := legacy.Exec(insert, "unreadable", "targets.txt", "yesterday", ScanStatusCompleted, 9, "reports/unreadable.html"); err != nil {
+		t.Fatalf("failed to insert unreadable scan: %v", err)
+	}
+	_ = legacy.Close()
+
+	db, err := NewDB(dbPath, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to open legacy database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	digest, err := db.GetChangeDigest(now.Add(-2*time.Hour), now)
+	if err != nil {
+		t.Fatalf("GetChangeDigest: %v", err)
+	}
+	if digest.Scans != 1 || digest.NewURLs != 4 || len(digest.ReportPaths) != 1 {
+		t.Errorf("expected the legacy scan in the digest, got %+v", digest)
+	}
+
+	digest, err = db.GetChangeDigest(now.Add(-30*time.Minute), now)
+	if err != nil {
+		t.Fatalf("GetChangeDigest: %v", err)
+	}
+	if digest.Scans != 0 {
+		t.Errorf("expected no scans in a window after the legacy scan, got %d", digest.Scans)
+	}
+}
+
+func TestDB_RecordScanStartWaitsForLock(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "scheduler.db")
+	db, err := NewDB(dbPath, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// Another process holds an exclusive lock for a moment
+	other, err := openDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open second connection: %v", err)
+	}
+	defer func() { _ = other.Close() }()
+	conn, err := other.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), "BEGIN EXCLUSIVE"); err != nil {
+		t.Fatalf("failed to lock database: %v", err)
+	}
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = conn.ExecContext(context.Background(), "COMMIT")
+		_ = conn.Close()
+	}()
+
+	if _, err := db.RecordScanStart("locked", "targets.txt", 1, time.Now()); err != nil {
+		t.Fatalf("expected the write to wait for the lock, got %v", err)
+	}
+}
+
+func TestDB_WithLockRetry(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "scheduler.db"), zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	db.retryDelay = time.Millisecond
+
+	lockErr := errors.New("database is locked (5) (SQLITE_BUSY)")
+
+	calls := 0
+	err = db.withLockRetry("test", func() error {
+		calls++
+		if calls < 3 {
+			return lockErr
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected success on the third attempt, got err=%v after %d calls", err, calls)
+	}
+
+	calls = 0
+	if err := db.withLockRetry("test", func() error { calls++; return lockErr }); err == nil {
+		t.Error("expected the lock error once retries are exhausted")
+	}
+	if calls != dbLockRetryAttempts+1 {
+		t.Errorf("expected %d attempts, got %d", dbLockRetryAttempts+1, calls)
+	}
+	if !db.degraded.Load() {
+		t.Error("expected the database to be marked degraded")
+	}
+
+	calls = 0
+	if err := db.withLockRetry("test", func() error { calls++; return errors.New("syntax error") }); err == nil || calls != 1 {
+		t.Errorf("expected other errors to fail without retries, got err=%v after %d calls", err, calls)
+	}
+
+	if err := db.withLockRetry("test", func() error { return nil }); err != nil || db.degraded.Load() {
+		t.Errorf("expected a success to clear the degraded state, got err=%v", err)
+	}
+}
+
+func TestDB_GetLastScanTimeFallsBackToMemory(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "scheduler.db"), zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	stored := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	id, err := db.RecordScanStart("stored", "targets.txt", 1, stored)
+	if err != nil {
+		t.Fatalf("RecordScanStart: %v", err)
+	}
+	if err := db.UpdateScanCompletion(id, stored.Add(time.Minute), ScanStatusCompleted, "", 0, 0, 0, ""); err != nil {
+		t.Fatalf("UpdateScanCompletion: %v", err)
+	}
+
+	last, err := db.GetLastScanTime()
+	if err != nil || !last.Equal(stored) {
+		t.Fatalf("expected the stored scan time %v, got %v (err %v)", stored, last, err)
+	}
+
+	// A newer scan whose history write was lost wins over the stored one
+	remembered := time.Now().Add(-time.Hour)
+	db.RememberScanTime(remembered)
+	if last, err := db.GetLastScanTime(); err != nil || !last.Equal(remembered) {
+		t.Errorf("expected the remembered scan time %v, got %v (err %v)", remembered, last, err)
+	}
+
+	// The remembered time is still served once the database is unusable
+	_ = db.Close()
+	if last, err := db.GetLastScanTime(); err != nil || !last.Equal(remembered) {
+		t.Errorf("expected the remembered scan time after close, got %v (err %v)", last, err)
+	}
+}
+
+func TestDB_RecordScanHosts(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "scheduler.db"), zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	now := time.Now()
+	newHosts, err := db.RecordScanHosts("first", now, []string{"a.example.com", "b.example.com"})
+	if err != nil {
+		t.Fatalf("RecordScanHosts: %v", err)
+	}
+	if len(newHosts) != 0 {
+		t.Errorf("expected the first scan to only record a baseline, got %v", newHosts)
+	}
+
+	newHosts, err = db.RecordScanHosts("second", now.Add(time.Hour), []string{"a.example.com", "new.example.com"})
+	if err != nil {
+		t.Fatalf("RecordScanHosts: %v", err)
+	}
+	if len(newHosts) != 1 || newHosts[0] != "new.example.com" {
+		t.Errorf("expected only new.example.com to be new, got %v", newHosts)
+	}
+
+	// A host missing from one scan is not new when it returns
+	newHosts, err = db.RecordScanHosts("third", now.Add(2*time.Hour), []string{"b.example.com", "new.example.com"})
+	if err != nil {
+		t.Fatalf("RecordScanHosts: %v", err)
+	}
+	if len(newHosts) != 0 {
+		t.Errorf("expected no new hosts, got %v", newHosts)
+	}
+}
+
+func TestScheduler_PauseResume(t *testing.T) {
+	s := &Scheduler{logger: zerolog.Nop()}
+
+	if s.IsPaused() {
+		t.Fatal("expected a new scheduler not to be paused")
+	}
+	if !s.Pause() || !s.IsPaused() {
+		t.Fatal("expected Pause to pause the scheduler")
+	}
+	if s.Pause() {
+		t.Error("expected a second Pause to report no change")
+	}
+
+	// A paused scheduler skips the cycle without touching the scanner or the database
+	s.executeScanCycleUnlessPaused(context.Background())
+
+	if !s.Resume() || s.IsPaused() {
+		t.Fatal("expected Resume to resume the scheduler")
+	}
+	if s.Resume() {
+		t.Error("expected a second Resume to report no change")
+	}
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/aleister1102/monsterinc/internal/common/urlhandler"
+)
+
+// reconcileTargets compares the targets loaded for this cycle with those of the previous cycle. URLs added
+// to or removed from the targets file are logged, and TLS expiry warnings of hosts that no target covers
+// any more are forgotten, so a host put back in scope is announced again. The first cycle only records the set.
+func (s *Scheduler) reconcileTargets(targetURLs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.cycleTargets
+	s.cycleTargets = make(map[string]struct{}, len(targetURLs))
+	for _, targetURL := range targetURLs {
+		s.cycleTargets[targetURL] = struct{}{}
+	}
+	if previous == nil {
+		return
+	}
+
+	added, removed := diffTargetURLs(previous, s.cycleTargets)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	s.logger.Info().
+		Int("added", len(added)).
+		Int("removed", len(removed)).
+		Int("total_targets", len(targetURLs)).
+		Msg("Scheduler: Targets file changed since the previous cycle")
+	s.logger.Debug().Strs("added", added).Strs("removed", removed).Msg("Scheduler: Reloaded targets")
+
+	remainingHosts := make(map[string]bool, len(s.cycleTargets))
+	for targetURL := range s.cycleTargets {
+		if host, err := urlhandler.ExtractHostname(targetURL); err == nil {
+			remainingHosts[host] = true
+		}
+	}
+	for _, targetURL := range removed {
+		host, err := urlhandler.ExtractHostname(targetURL)
+		if err != nil || remainingHosts[host] {
+			continue
+		}
+		delete(s.certExpiryWarned, host)
+	}
+}
+
+// diffTargetURLs returns the URLs only in current and those only in previous, each sorted
+func diffTargetURLs(previous, current map[string]struct{}) (added, removed []string) {
+	for targetURL := range current {
+		if _, ok := previous[targetURL]; !ok {
+			added = append(added, targetURL)
+		}
+	}
+	for targetURL := range previous {
+		if _, ok := current[targetURL]; !ok {
+			removed = append(removed, targetURL)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
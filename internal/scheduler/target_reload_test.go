@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestScheduler_ReconcileTargets(t *testing.T) {
+	s := &Scheduler{logger: zerolog.Nop()}
+	expiry := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.reconcileTargets([]string{"https://a.example.com", "https://b.example.com/login", "https://b.example.com"})
+	s.certExpiryWarned = map[string]time.Time{"a.example.com": expiry, "b.example.com": expiry}
+
+	// b.example.com stays in scope through its other URL; a.example.com leaves it
+	s.reconcileTargets([]string{"https://b.example.com", "https://c.example.com"})
+
+	if _, ok := s.certExpiryWarned["a.example.com"]; ok {
+		t.Error("expected the warning of a host no longer targeted to be forgotten")
+	}
+	if _, ok := s.certExpiryWarned["b.example.com"]; !ok {
+		t.Error("expected the warning of a host still targeted to be kept")
+	}
+	if len(s.cycleTargets) != 2 {
+		t.Errorf("expected the latest cycle's 2 targets, got %v", s.cycleTargets)
+	}
+}
+
+func TestDiffTargetURLs(t *testing.T) {
+	previous := map[string]struct{}{"https://a.example.com": {}, "https://b.example.com": {}}
+	current := map[string]struct{}{"https://b.example.com": {}, "https://d.example.com": {}, "https://c.example.com": {}}
+
+	added, removed := diffTargetURLs(previous, current)
+	if want := []string{"https://c.example.com", "https://d.example.com"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+	if want := []string{"https://a.example.com"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+}
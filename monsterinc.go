@@ -0,0 +1,49 @@
+// Package monsterinc exposes MonsterInc as a Go library so a scan can run
+// in-process instead of shelling out to the binary.
+//
+//	cfg := monsterinc.DefaultConfig()
+//	cfg.StorageConfig.ParquetBasePath = "/var/lib/monsterinc"
+//	result, err := monsterinc.RunOnetime(ctx, cfg, []string{"https://example.com"})
+package monsterinc
+
+import (
+	"context"
+
+	"github.com/aleister1102/monsterinc/internal/config"
+	"github.com/aleister1102/monsterinc/internal/scanner"
+	"github.com/rs/zerolog"
+)
+
+// Config is the full MonsterInc configuration (the same structure as config.yaml)
+type Config = config.GlobalConfig
+
+// ScanResult holds the summary, report paths and batch statistics of a scan
+type ScanResult = scanner.BatchScanResult
+
+// DefaultConfig returns a configuration with all defaults applied
+func DefaultConfig() *Config {
+	return config.NewDefaultGlobalConfig()
+}
+
+// LoadConfig loads and validates a YAML/JSON configuration file; an empty path searches the default locations
+func LoadConfig(path string) (*Config, error) {
+	cfg, err := config.LoadGlobalConfig(path, zerolog.Nop())
+	if err != nil {
+		return nil, err
+	}
+	if err := config.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// RunOnetime scans targets once and returns the result. Cancelling ctx stops the
+// scan; results gathered so far are still reported in the returned summary.
+func RunOnetime(ctx context.Context, cfg *Config, targets []string) (*ScanResult, error) {
+	return scanner.RunOnetime(ctx, cfg, targets)
+}
+
+// RunOnetimeWithLogger is RunOnetime with scan logs written to logger
+func RunOnetimeWithLogger(ctx context.Context, cfg *Config, targets []string, logger zerolog.Logger) (*ScanResult, error) {
+	return scanner.NewOnetimeRunner(cfg, logger).Run(ctx, targets)
+}